@@ -0,0 +1,60 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/apparmor"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
+)
+
+func installCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install [flags]",
+		Short: fmt.Sprintf("Install the default AppArmor profile %q, and the profile of RootlessKit when needed, into %s", defaults.AppArmorProfileName, apparmorutil.ProfilesDir),
+		Long: fmt.Sprintf(`Install the default AppArmor profile %q into %s, and load it.
+When AppArmor restricts unprivileged user namespaces (Ubuntu 24.04 and later), the profile of RootlessKit is installed too.
+
+Loading AppArmor profiles requires root. Use '--sudo-script' to print a shell script for the steps that need root,
+e.g., 'nerdctl apparmor install --sudo-script | sudo sh'.
+
+The builtin seccomp profile is written to seccomp/default.json next to nerdctl.toml, which does not need root.`,
+			defaults.AppArmorProfileName, apparmorutil.ProfilesDir),
+		Args:          cobra.NoArgs,
+		RunE:          installAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("sudo-script", false, "Print a shell script for the steps that need root, instead of executing them")
+	return cmd
+}
+
+func installAction(cmd *cobra.Command, _ []string) error {
+	sudoScript, err := cmd.Flags().GetBool("sudo-script")
+	if err != nil {
+		return err
+	}
+	return apparmor.Install(types.ApparmorInstallOptions{
+		Stdout:     cmd.OutOrStdout(),
+		SudoScript: sudoScript,
+	})
+}
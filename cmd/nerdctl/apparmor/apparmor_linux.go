@@ -35,6 +35,7 @@ func Command() *cobra.Command {
 		listCommand(),
 		inspectCommand(),
 		loadCommand(),
+		installCommand(),
 		unloadCommand(),
 	)
 	return cmd
@@ -21,22 +21,32 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/apparmor"
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
 )
 
 func loadCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:           "load",
-		Short:         fmt.Sprintf("Load the default AppArmor profile %q. Requires root.", defaults.AppArmorProfileName),
-		Args:          cobra.NoArgs,
+		Use:           "load [FILE...]",
+		Short:         fmt.Sprintf("Load AppArmor profiles from files, or the default AppArmor profile %q. Requires root.", defaults.AppArmorProfileName),
+		Args:          cobra.ArbitraryArgs,
 		RunE:          loadAction,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
+	cmd.Flags().Bool("persist", false, fmt.Sprintf("Also install the profiles into %s, so that they are loaded on boot", apparmorutil.ProfilesDir))
 	return cmd
 }
 
 func loadAction(cmd *cobra.Command, args []string) error {
-	return apparmor.Load()
+	persist, err := cmd.Flags().GetBool("persist")
+	if err != nil {
+		return err
+	}
+	return apparmor.Load(types.ApparmorLoadOptions{
+		Files:   args,
+		Persist: persist,
+	})
 }
@@ -18,9 +18,12 @@ package builder
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/docker/go-units"
@@ -28,6 +31,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
 )
 
@@ -42,7 +46,13 @@ func Command() *cobra.Command {
 	}
 	cmd.AddCommand(
 		BuildCommand(),
+		BakeCommand(),
 		pruneCommand(),
+		duCommand(),
+		createCommand(),
+		listCommand(),
+		useCommand(),
+		removeCommand(),
 		debugCommand(),
 	)
 	return cmd
@@ -60,8 +70,14 @@ func pruneCommand() *cobra.Command {
 	}
 
 	cmd.Flags().String("buildkit-host", "", "BuildKit address")
+	cmd.Flags().String("builder", "", "Builder to use")
+	cmd.RegisterFlagCompletionFunc("builder", builderShellComplete)
 	cmd.Flags().BoolP("all", "a", false, "Remove all unused build cache, not just dangling ones")
 	cmd.Flags().BoolP("force", "f", false, "Do not prompt for confirmation")
+	cmd.Flags().StringArray("filter", nil, "Provide filter values (e.g. 'until=24h', 'type=regular')")
+	cmd.Flags().String("keep-storage", "", "Amount of disk space to keep for cache (e.g. 10GB)")
+	cmd.Flags().String("max-used-space", "", "Maximum amount of disk space allowed to keep for cache (e.g. 10GB)")
+	cmd.Flags().String("min-free-space", "", "Target amount of free disk space after pruning (e.g. 10GB)")
 	return cmd
 }
 
@@ -76,6 +92,8 @@ func pruneAction(cmd *cobra.Command, _ []string) error {
 
 		if options.All {
 			msg = "This will remove all build cache."
+		} else if len(options.Filters) > 0 {
+			msg = "This will remove the build cache matching the filters."
 		} else {
 			msg = "This will remove any dangling build cache."
 		}
@@ -107,7 +125,7 @@ func pruneOptions(cmd *cobra.Command) (types.BuilderPruneOptions, error) {
 		return types.BuilderPruneOptions{}, err
 	}
 
-	buildkitHost, err := GetBuildkitHost(cmd, globalOptions.Namespace)
+	buildkitHost, err := GetBuildkitHost(cmd, globalOptions)
 	if err != nil {
 		return types.BuilderPruneOptions{}, err
 	}
@@ -122,15 +140,146 @@ func pruneOptions(cmd *cobra.Command) (types.BuilderPruneOptions, error) {
 		return types.BuilderPruneOptions{}, err
 	}
 
+	filters, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return types.BuilderPruneOptions{}, err
+	}
+
+	var sizes [3]int64
+	for i, name := range []string{"keep-storage", "max-used-space", "min-free-space"} {
+		v, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return types.BuilderPruneOptions{}, err
+		}
+		if v == "" {
+			continue
+		}
+		if sizes[i], err = units.FromHumanSize(v); err != nil {
+			return types.BuilderPruneOptions{}, fmt.Errorf("invalid value for --%s: %w", name, err)
+		}
+	}
+
 	return types.BuilderPruneOptions{
 		Stderr:       cmd.OutOrStderr(),
 		GOptions:     globalOptions,
 		BuildKitHost: buildkitHost,
 		All:          all,
 		Force:        force,
+		Filters:      filters,
+		KeepStorage:  sizes[0],
+		MaxUsedSpace: sizes[1],
+		MinFreeSpace: sizes[2],
 	}, nil
 }
 
+func duCommand() *cobra.Command {
+	shortHelp := `Show BuildKit build cache disk usage`
+	var cmd = &cobra.Command{
+		Use:           "du",
+		Args:          cobra.NoArgs,
+		Short:         shortHelp,
+		RunE:          duAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().String("buildkit-host", "", "BuildKit address")
+	cmd.Flags().String("builder", "", "Builder to use")
+	cmd.RegisterFlagCompletionFunc("builder", builderShellComplete)
+	cmd.Flags().StringArray("filter", nil, "Provide filter values (e.g. 'type=regular')")
+	cmd.Flags().BoolP("verbose", "v", false, "Show every cache record instead of the summary by record type")
+	return cmd
+}
+
+func duAction(cmd *cobra.Command, _ []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	buildkitHost, err := GetBuildkitHost(cmd, globalOptions)
+	if err != nil {
+		return err
+	}
+	filters, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return err
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+	options := types.BuilderDiskUsageOptions{
+		Stdout:       cmd.OutOrStdout(),
+		Stderr:       cmd.OutOrStderr(),
+		GOptions:     globalOptions,
+		BuildKitHost: buildkitHost,
+		Filters:      filters,
+		Verbose:      verbose,
+	}
+
+	records, err := builder.DiskUsage(cmd.Context(), options)
+	if err != nil {
+		return err
+	}
+	return printDiskUsage(options, records)
+}
+
+func printDiskUsage(options types.BuilderDiskUsageOptions, records []buildkitutil.UsageInfo) error {
+	w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	var total, reclaimable, shared int64
+	if options.Verbose {
+		fmt.Fprintln(w, "ID\tTYPE\tRECLAIMABLE\tSHARED\tSIZE\tLAST ACCESSED\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(w, "TYPE\tRECORDS\tSIZE\tRECLAIMABLE")
+	}
+	type typeUsage struct {
+		records     int
+		size        int64
+		reclaimable int64
+	}
+	byType := make(map[buildkitutil.UsageRecordType]*typeUsage)
+	for _, r := range records {
+		total += r.Size
+		if !r.InUse {
+			reclaimable += r.Size
+		}
+		if r.Shared {
+			shared += r.Size
+		}
+		if options.Verbose {
+			lastAccessed := ""
+			if r.LastUsedAt != nil {
+				lastAccessed = units.HumanDuration(time.Since(*r.LastUsedAt)) + " ago"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\t%s\t%s\n", r.ID, r.RecordType, !r.InUse, r.Shared,
+				units.HumanSize(float64(r.Size)), lastAccessed, r.Description)
+			continue
+		}
+		u, ok := byType[r.RecordType]
+		if !ok {
+			u = &typeUsage{}
+			byType[r.RecordType] = u
+		}
+		u.records++
+		u.size += r.Size
+		if !r.InUse {
+			u.reclaimable += r.Size
+		}
+	}
+	for _, t := range slices.Sorted(maps.Keys(byType)) {
+		u := byType[t]
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", t, u.records, units.HumanSize(float64(u.size)), units.HumanSize(float64(u.reclaimable)))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(options.Stdout, "Shared:\t\t%s\n", units.HumanSize(float64(shared)))
+	fmt.Fprintf(options.Stdout, "Private:\t%s\n", units.HumanSize(float64(total-shared)))
+	fmt.Fprintf(options.Stdout, "Reclaimable:\t%s\n", units.HumanSize(float64(reclaimable)))
+	fmt.Fprintf(options.Stdout, "Total:\t\t%s\n", units.HumanSize(float64(total)))
+	return nil
+}
+
 func debugCommand() *cobra.Command {
 	shortHelp := `Debug Dockerfile`
 	var cmd = &cobra.Command{
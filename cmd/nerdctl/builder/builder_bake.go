@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+)
+
+func BakeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "bake [flags] [TARGET...]",
+		Short: "Build the targets of docker-bake.hcl or docker-bake.json files",
+		Long: `Build the targets of docker-bake.hcl or docker-bake.json files. Needs buildkitd to be running.
+TARGET is a target or a group of targets. The "default" group is built when no target is specified.
+The targets are built one after another with "nerdctl build".`,
+		RunE:          bakeAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringArrayP("file", "f", nil, "Bake file (default: docker-bake.json, docker-bake.hcl, docker-bake.override.json, docker-bake.override.hcl)")
+	cmd.Flags().StringArray("set", nil, "Override target value (e.g., \"targetpattern.key=value\")")
+	cmd.Flags().Bool("print", false, "Print the options without building")
+	cmd.Flags().Bool("no-cache", false, "Do not use cache when building the images")
+	cmd.Flags().Bool("pull", false, "Always attempt to pull all referenced images")
+	cmd.Flags().String("progress", "", "Set type of progress output (auto, plain, tty, rawjson, json, quiet). Defaults to the global --progress")
+	cmd.Flags().String("builder", "", "Builder to use")
+	cmd.RegisterFlagCompletionFunc("builder", builderShellComplete)
+	return cmd
+}
+
+func bakeAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	files, err := cmd.Flags().GetStringArray("file")
+	if err != nil {
+		return err
+	}
+	set, err := cmd.Flags().GetStringArray("set")
+	if err != nil {
+		return err
+	}
+	printOnly, err := cmd.Flags().GetBool("print")
+	if err != nil {
+		return err
+	}
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		return err
+	}
+	pull, err := cmd.Flags().GetBool("pull")
+	if err != nil {
+		return err
+	}
+	progress, err := cmd.Flags().GetString("progress")
+	if err != nil {
+		return err
+	}
+	builderName, err := cmd.Flags().GetString("builder")
+	if err != nil {
+		return err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return builder.Bake(cmd.Context(), types.BuilderBakeOptions{
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+		Files:       files,
+		Targets:     args,
+		Set:         set,
+		Print:       printOnly,
+		NoCache:     noCache,
+		Pull:        pull,
+		Progress:    progress,
+		Builder:     builderName,
+	})
+}
@@ -0,0 +1,140 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestBake(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: require.Not(nerdtest.Docker),
+		SubTests: []*test.Case{
+			{
+				Description: "build a group with inheritance and an inline Dockerfile",
+				Require:     nerdtest.Build,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					dockerfile := fmt.Sprintf(`FROM %s
+ARG MESSAGE
+RUN echo "$MESSAGE" > /message
+CMD ["cat", "/message"]`, testutil.CommonImage)
+					data.Temp().Save(dockerfile, "Dockerfile")
+					bakefile := fmt.Sprintf(`
+variable "MESSAGE" {
+  default = "nerdctl-bake-test"
+}
+group "default" {
+  targets = ["app", "inline"]
+}
+target "base" {
+  context = %q
+  args = {
+    MESSAGE = "${MESSAGE}"
+  }
+}
+target "app" {
+  inherits = ["base"]
+  tags = ["%s-app"]
+}
+target "inline" {
+  inherits = ["base"]
+  tags = ["%s-inline"]
+  dockerfile-inline = <<EOT
+FROM %s
+CMD ["echo", "nerdctl-bake-inline"]
+EOT
+}
+`, data.Temp().Path(), data.Identifier(), data.Identifier(), testutil.CommonImage)
+					data.Temp().Save(bakefile, "docker-bake.hcl")
+					helpers.Ensure("bake", "-f", data.Temp().Path("docker-bake.hcl"), "--set", "app.args.MESSAGE=nerdctl-bake-override")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier()+"-app", data.Identifier()+"-inline")
+				},
+				SubTests: []*test.Case{
+					{
+						Description: "app",
+						Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+							return helpers.Command("run", "--rm", data.Identifier()+"-app")
+						},
+						Expected: test.Expects(0, nil, expect.Equals("nerdctl-bake-override\n")),
+					},
+					{
+						Description: "inline",
+						Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+							return helpers.Command("run", "--rm", data.Identifier()+"-inline")
+						},
+						Expected: test.Expects(0, nil, expect.Equals("nerdctl-bake-inline\n")),
+					},
+				},
+			},
+			{
+				Description: "print a matrix target",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					data.Temp().Save(`{
+  "target": {
+    "app": {
+      "name": "app-${variant}",
+      "matrix": {"variant": ["a", "b"]},
+      "tags": ["app:${variant}"]
+    }
+  }
+}`, "docker-bake.json")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("bake", "-f", data.Temp().Path("docker-bake.json"), "--print", "app")
+				},
+				Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+					var def struct {
+						Group  map[string]struct{ Targets []string }
+						Target map[string]struct{ Tags []string }
+					}
+					assert.NilError(t, json.Unmarshal([]byte(stdout), &def), stdout)
+					assert.DeepEqual(t, def.Group["default"].Targets, []string{"app-a", "app-b"})
+					assert.DeepEqual(t, def.Target["app-b"].Tags, []string{"app:b"})
+				}),
+			},
+			{
+				Description: "unknown target",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					data.Temp().Save(`target "app" {}`, "docker-bake.hcl")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("bake", "-f", data.Temp().Path("docker-bake.hcl"), "--print", "nope")
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New(`failed to find target "nope"`)}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -19,8 +19,8 @@ package builder
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -32,6 +32,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -40,23 +41,32 @@ func BuildCommand() *cobra.Command {
 		Use:   "build [flags] PATH",
 		Short: "Build an image from a Dockerfile. Needs buildkitd to be running.",
 		Long: `Build an image from a Dockerfile. Needs buildkitd to be running.
-If Dockerfile is not present and -f is not specified, it will look for Containerfile and build with it. `,
+If Dockerfile is not present and -f is not specified, it will look for Containerfile and build with it.
+Specify --builder=containerd to use a minimal built-in builder that does not need buildkitd. `,
 		RunE:          buildAction,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 	cmd.Flags().String("buildkit-host", "", "BuildKit address")
-	cmd.Flags().StringArray("add-host", nil, "Add a custom host-to-IP mapping (format: \"host:ip\")")
+	cmd.Flags().String("builder", "", "Builder to use (\"containerd\" for the built-in builder that does not need buildkitd)")
+	cmd.RegisterFlagCompletionFunc("builder", builderShellComplete)
+	cmd.Flags().Bool("provision-buildkit", false, "Start a rootless buildkitd for the namespace when none is running")
+	cmd.Flags().StringArray("add-host", nil, "Add a custom host-to-IP mapping (format: \"host:ip\", ip can be \"host-gateway\")")
 	cmd.Flags().StringArrayP("tag", "t", nil, "Name and optionally a tag in the 'name:tag' format")
 	cmd.Flags().StringP("file", "f", "", "Name of the Dockerfile")
 	cmd.Flags().String("target", "", "Set the target build stage to build")
 	cmd.Flags().StringArray("build-arg", nil, "Set build-time variables")
 	cmd.Flags().Bool("no-cache", false, "Do not use cache when building the image")
 	cmd.Flags().StringP("output", "o", "", "Output destination (format: type=local,dest=path)")
-	cmd.Flags().String("progress", "auto", "Set type of progress output (auto, plain, tty). Use plain to show container output")
+	cmd.Flags().Bool("push", false, "Push the image to the registry (shorthand for \"--output=type=image,push=true\"). Not loaded into the image store unless --load is specified")
+	cmd.Flags().Bool("load", false, "Load the image into the image store (default unless --output or --push is specified)")
+	cmd.Flags().String("progress", "auto", "Set type of progress output (auto, plain, tty, rawjson, json, quiet). Use plain to show container output, rawjson (or json) to stream the build status as JSON lines. Defaults to the global --progress")
+	cmd.RegisterFlagCompletionFunc("progress", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"auto", "plain", "tty", builder.ProgressRawJSON, jobs.ProgressJSON, jobs.ProgressQuiet}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().String("provenance", "", "Shorthand for \"--attest=type=provenance\"")
 	cmd.Flags().Bool("pull", false, "On true, always attempt to pull latest image version from remote. Default uses buildkit's default.")
-	cmd.Flags().StringArray("secret", nil, "Secret file to expose to the build: id=mysecret,src=/local/secret")
+	cmd.Flags().StringArray("secret", nil, "Secret to expose to the build: id=mysecret,src=/local/secret or id=mytoken,env=MY_TOKEN")
 	cmd.Flags().StringArray("allow", nil, "Allow extra privileged entitlement, e.g. network.host, security.insecure")
 	cmd.RegisterFlagCompletionFunc("allow", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"network.host", "security.insecure"}, cobra.ShellCompDirectiveNoFileComp
@@ -68,9 +78,20 @@ If Dockerfile is not present and -f is not specified, it will look for Container
 	cmd.Flags().StringArray("cache-from", nil, "External cache sources (eg. user/app:cache, type=local,src=path/to/dir)")
 	cmd.Flags().StringArray("cache-to", nil, "Cache export destinations (eg. user/app:cache, type=local,dest=path/to/dir)")
 	cmd.Flags().Bool("rm", true, "Remove intermediate containers after a successful build")
-	cmd.Flags().String("network", "default", "Set type of network for build (format:network=default|none|host)")
+	cmd.Flags().Bool("force-rm", false, "Always remove intermediate containers")
+	cmd.Flags().String("invoke", "", "Run an interactive shell in the container of a failed RUN instruction (on-error). Only supported with --builder=containerd")
+	cmd.RegisterFlagCompletionFunc("invoke", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{builder.InvokeOnError}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().String("call", builder.CallBuild, "Set method for evaluating the build (build, check, outline, targets)")
+	cmd.RegisterFlagCompletionFunc("call", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return builder.CallTypes, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Bool("check", false, "Shorthand for \"--call=check\": run the lint checks of the Dockerfile without building it")
+	cmd.Flags().String("network", "default", "Set type of network for build (format:network=default|none|host, or a nerdctl network with --builder=containerd)")
 	cmd.RegisterFlagCompletionFunc("network", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"default", "host", "none"}, cobra.ShellCompDirectiveNoFileComp
+		candidates, directive := completion.NetworkNames(cmd, nil)
+		return append([]string{"default"}, candidates...), directive
 	})
 	// #region platform flags
 	// platform is defined as StringSlice, not StringArray, to allow specifying "--platform=amd64,arm64"
@@ -85,15 +106,61 @@ If Dockerfile is not present and -f is not specified, it will look for Container
 	return cmd
 }
 
+// provisionBuildkitHost returns the buildkitd detected for the namespace, and starts one in rootless mode when none is running.
+func provisionBuildkitHost(globalOptions types.GlobalCommandOptions) (string, error) {
+	if buildkitHost, err := buildkitutil.DetectBuildkitHost(globalOptions.Namespace); err == nil {
+		return buildkitHost, nil
+	}
+	log.L.Infof("No buildkitd is running for namespace %q, starting one", globalOptions.Namespace)
+	return buildkitutil.ProvisionRootless(globalOptions.Namespace, globalOptions.Address, globalOptions.Snapshotter)
+}
+
 func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBuildOptions, error) {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
 	}
-	buildKitHost, err := GetBuildkitHost(cmd, globalOptions.Namespace)
+	builderName, err := cmd.Flags().GetString("builder")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
+	if !cmd.Flags().Changed("buildkit-host") && (builderName != "" || os.Getenv("BUILDKIT_HOST") == "") {
+		b, err := builder.Resolve(globalOptions.DataRoot, builderName)
+		if err != nil {
+			return types.BuilderBuildOptions{}, err
+		}
+		builderName = b.Name
+	}
+	provision, err := cmd.Flags().GetBool("provision-buildkit")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
+	callType, err := cmd.Flags().GetString("call")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
+	check, err := cmd.Flags().GetBool("check")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
 	}
+	if check {
+		if cmd.Flags().Changed("call") && callType != builder.CallCheck {
+			return types.BuilderBuildOptions{}, fmt.Errorf("--check can't be combined with --call=%s", callType)
+		}
+		callType = builder.CallCheck
+	}
+	var buildKitHost string
+	if builderName != builder.BuilderContainerd {
+		// the built-in builder does not need buildkitd
+		if provision && builderName == builder.BuilderDefault && !cmd.Flags().Changed("buildkit-host") && os.Getenv("BUILDKIT_HOST") == "" {
+			buildKitHost, err = provisionBuildkitHost(globalOptions)
+		} else {
+			buildKitHost, err = GetBuildkitHost(cmd, globalOptions)
+		}
+		if err != nil {
+			return types.BuilderBuildOptions{}, fmt.Errorf("%w (Hint: specify --builder=%s for building without buildkitd)", err, builder.BuilderContainerd)
+		}
+	}
 	extraHosts, err := cmd.Flags().GetStringArray("add-host")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
@@ -107,13 +174,21 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 		return types.BuilderBuildOptions{}, errors.New("context needs to be specified")
 	}
 	buildContext := args[0]
-	if buildContext == "-" || strings.Contains(buildContext, "://") {
+	if buildContext == "-" {
 		return types.BuilderBuildOptions{}, fmt.Errorf("unsupported build context: %q", buildContext)
 	}
 	output, err := cmd.Flags().GetString("output")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
 	}
+	push, err := cmd.Flags().GetBool("push")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
+	load, err := cmd.Flags().GetBool("load")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
 	tagValue, err := cmd.Flags().GetStringArray("tag")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
@@ -174,6 +249,14 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
 	}
+	forceRm, err := cmd.Flags().GetBool("force-rm")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
+	invoke, err := cmd.Flags().GetString("invoke")
+	if err != nil {
+		return types.BuilderBuildOptions{}, err
+	}
 	iidfile, err := cmd.Flags().GetString("iidfile")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
@@ -182,6 +265,17 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
 	}
+	// the local --progress shadows the global one, which still applies when the local one is not specified
+	if !cmd.Flags().Changed("progress") && globalOptions.Progress != "" {
+		progress = globalOptions.Progress
+	}
+	switch progress {
+	case jobs.ProgressJSON:
+		progress = builder.ProgressRawJSON
+	case jobs.ProgressQuiet:
+		progress = "auto"
+		quiet = true
+	}
 	network, err := cmd.Flags().GetString("network")
 	if err != nil {
 		return types.BuilderBuildOptions{}, err
@@ -217,11 +311,17 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 		log.L.Warn("userns remap is not supported with nerdctl build. dropping the config.")
 	}
 
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
 	return types.BuilderBuildOptions{
 		GOptions:             globalOptions,
 		BuildKitHost:         buildKitHost,
+		Builder:              builderName,
+		NerdctlCmd:           nerdctlCmd,
+		NerdctlArgs:          nerdctlArgs,
 		BuildContext:         buildContext,
 		Output:               output,
+		Push:                 push,
+		Load:                 load,
 		Tag:                  tagValue,
 		Progress:             progress,
 		File:                 filename,
@@ -237,6 +337,9 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 		CacheFrom:            cacheFrom,
 		CacheTo:              cacheTo,
 		Rm:                   rm,
+		ForceRm:              forceRm,
+		Invoke:               invoke,
+		Call:                 callType,
 		IidFile:              iidfile,
 		Quiet:                quiet,
 		Platform:             platform,
@@ -249,22 +352,6 @@ func processBuildCommandFlag(cmd *cobra.Command, args []string) (types.BuilderBu
 	}, nil
 }
 
-func GetBuildkitHost(cmd *cobra.Command, namespace string) (string, error) {
-	if cmd.Flags().Changed("buildkit-host") {
-		// If address is explicitly specified, use it.
-		buildkitHost, err := cmd.Flags().GetString("buildkit-host")
-		if err != nil {
-			return "", err
-		}
-		if err := buildkitutil.PingBKDaemon(buildkitHost); err != nil {
-			return "", err
-		}
-		return buildkitHost, nil
-	}
-
-	return buildkitutil.GetBuildkitHost(namespace)
-}
-
 func buildAction(cmd *cobra.Command, args []string) error {
 	options, err := processBuildCommandFlag(cmd, args)
 	if err != nil {
@@ -37,6 +37,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest/registry"
 )
 
 func TestBuildBasics(t *testing.T) {
@@ -845,6 +846,40 @@ RUN curl -I http://google.com
 				},
 				Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
 			},
+			{
+				Description: "user-defined network is not supported by BuildKit",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("network", "create", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("network", "rm", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", data.Labels().Get("buildCtx"), "--network", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is not supported by BuildKit")}, nil),
+			},
+			{
+				Description: "user-defined network with the containerd builder",
+				Require:     nerdtest.Rootful,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("network", "create", data.Identifier())
+					helpers.Ensure("run", "-d", "--network", data.Identifier(), "--name", data.Identifier("server"),
+						testutil.NginxAlpineImage)
+					data.Temp().Save(fmt.Sprintf(`FROM %s
+RUN wget -q -O /index.html http://%s/
+CMD ["cat", "/index.html"]`, testutil.CommonImage, data.Identifier("server")), "network", "Dockerfile")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+					helpers.Anyhow("rm", "-f", data.Identifier("server"))
+					helpers.Anyhow("network", "rm", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--builder=containerd", "--network", data.Identifier(), "-t", data.Identifier(), data.Temp().Path("network"))
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
 		},
 	}
 
@@ -943,6 +978,17 @@ func TestBuildAttestation(t *testing.T) {
 					}
 				},
 			},
+			{
+				Description: "Attestations are stored in the image index",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					helpers.Ensure("build", "--sbom=true", "--provenance=mode=min", "-t", data.Identifier("image"), data.Labels().Get("buildCtx"))
+					return helpers.Command("image", "inspect", "--mode=native", data.Identifier("image"))
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("image"))
+				},
+				Expected: test.Expects(0, nil, expect.Contains("attestation-manifest")),
+			},
 			{
 				Description: "Attestation",
 				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
@@ -1025,6 +1071,30 @@ RUN ping -c 5 beta
 	testCase.Run(t)
 }
 
+func TestBuildAddHostGateway(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: require.All(
+			nerdtest.Build,
+			require.Not(nerdtest.Docker),
+		),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`FROM %s
+RUN grep -E "^192.0.2.1[[:space:]]+gateway" /etc/hosts
+`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+		},
+		Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			return helpers.Command("--host-gateway-ip=192.0.2.1", "build", "--no-cache",
+				"--add-host", "gateway=host-gateway", data.Temp().Path())
+		},
+		Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+	}
+
+	testCase.Run(t)
+}
+
 func TestBuildWithBuildkitConfig(t *testing.T) {
 	nerdtest.Setup()
 
@@ -1108,3 +1178,380 @@ CMD ["echo", "nerdctl-build-test-string"]`, testutil.CommonImage)
 	}
 	testCase.Run(t)
 }
+
+func TestBuildWithContainerdBuilder(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		// the containerd builder does not need buildkitd
+		Require: require.All(
+			require.Linux,
+			require.Not(nerdtest.Docker),
+		),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`ARG BASE=%s
+FROM ${BASE}
+ARG GREETING=hello
+ENV APP_DIR=/opt/app
+WORKDIR ${APP_DIR}
+COPY greeting.txt ./
+COPY conf/ conf/
+RUN echo "${GREETING} from RUN" > run.txt
+LABEL org.example.stage="final"
+CMD ["sh", "-c", "cat greeting.txt conf/app.conf run.txt"]`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Temp().Save("greeting\n", "greeting.txt")
+			data.Temp().Save("conf\n", "conf", "app.conf")
+			data.Labels().Set("buildCtx", data.Temp().Path())
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "build and run",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("build", "--builder=containerd", "--build-arg", "GREETING=hi", "-t", data.Identifier(), data.Labels().Get("buildCtx"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Expected: test.Expects(0, nil, expect.Equals("greeting\nconf\nhi from RUN\n")),
+			},
+			{
+				Description: "image config",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("build", "--builder=containerd", "-t", data.Identifier(), data.Labels().Get("buildCtx"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("image", "inspect", "--format", "{{.Config.WorkingDir}} {{index .Config.Labels \"org.example.stage\"}}", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Expected: test.Expects(0, nil, expect.Equals("/opt/app final\n")),
+			},
+			{
+				Description: "intermediate images are removed",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("build", "--builder=containerd", "-t", data.Identifier(), data.Labels().Get("buildCtx"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("images")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Expected: test.Expects(0, nil, expect.DoesNotContain("nerdctl-build-")),
+			},
+			{
+				Description: "options that need buildkit are rejected",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--builder=containerd", "--output=type=local,dest="+data.Temp().Path("out"), data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("--output is not supported by the containerd builder")}, nil),
+			},
+			{
+				Description: "invoke a shell on error",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					dockerfile := fmt.Sprintf(`FROM %s
+RUN echo partial > /partial && false`, testutil.CommonImage)
+					data.Temp().Save(dockerfile, "invoke", "Dockerfile")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					cmd := helpers.Command("build", "--builder=containerd", "--invoke=on-error", "-t", data.Identifier(), data.Temp().Path("invoke"))
+					cmd.Feed(strings.NewReader("cat /partial\n"))
+					return cmd
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, expect.Contains("partial\n")),
+			},
+			{
+				Description: "invoke is rejected with buildkit",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--invoke=on-error", data.Labels().Get("buildCtx"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("--invoke is only supported with --builder=containerd")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildCacheImportExport(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: nerdtest.Build,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`FROM %s
+RUN echo nerdctl-build-cache > /cached
+CMD ["cat", "/cached"]`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "export and import a local cache",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("build", "-t", data.Identifier("export"),
+						"--cache-to", "type=local,dest="+data.Temp().Path("cache")+",mode=max", data.Temp().Path())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("export"))
+					helpers.Anyhow("rmi", "-f", data.Identifier("import"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-t", data.Identifier("import"),
+						"--cache-from", "type=local,src="+data.Temp().Path("cache"), data.Temp().Path())
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "mode is only valid for export",
+				Require:     require.Not(nerdtest.Docker),
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--cache-from", "type=registry,ref=example.com/app:cache,mode=max", data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("mode is only supported by --cache-to")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildPushLoad(t *testing.T) {
+	nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase := &test.Case{
+		Require: require.All(
+			nerdtest.Build,
+			require.Not(nerdtest.Docker),
+		),
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-build-push"]`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "push without loading",
+				Require:     nerdtest.Registry,
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+					reg.Setup(data, helpers)
+					ref := fmt.Sprintf("%s:%d/%s:v1", reg.IP.String(), reg.Port, data.Identifier())
+					data.Labels().Set("ref", ref)
+					helpers.Ensure("--insecure-registry", "build", "--push", "-t", ref, data.Temp().Path())
+					helpers.Fail("image", "inspect", ref)
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Labels().Get("ref"))
+					if reg != nil {
+						reg.Cleanup(data, helpers)
+					}
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--insecure-registry", "pull", data.Labels().Get("ref"))
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "push needs a tag",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--push", data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("--push needs an image name")}, nil),
+			},
+			{
+				Description: "load can't be combined with output",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--load", "--output=type=local,dest="+data.Temp().Path("out"), data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("can't be combined with --output")}, nil),
+			},
+			{
+				Description: "multi-platform image can't be exported in the docker format",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--platform=amd64,arm64", "--output=type=docker,dest="+data.Temp().Path("out.tar"), data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("the docker format does not support manifest lists")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildSecret(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: nerdtest.Build,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`FROM %s
+RUN --mount=type=secret,id=mytoken test "$(cat /run/secrets/mytoken)" = "nerdctl-build-secret"`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Temp().Save("nerdctl-build-secret", "secret")
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "secret from a file",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--no-cache", "--secret", "id=mytoken,src="+data.Temp().Path("secret"), data.Temp().Path())
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "secret from an environment variable",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					cmd := helpers.Command("build", "--no-cache", "--secret", "id=mytoken,env=NERDCTL_TEST_TOKEN", data.Temp().Path())
+					cmd.Setenv("NERDCTL_TEST_TOKEN", "nerdctl-build-secret")
+					return cmd
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "secret from an unset environment variable",
+				Require:     require.Not(nerdtest.Docker),
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--secret", "id=mytoken,env=NERDCTL_TEST_UNSET_TOKEN", data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New(`environment variable "NERDCTL_TEST_UNSET_TOKEN" is not set`)}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildRemoteContext(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: nerdtest.Build,
+		SubTests: []*test.Case{
+			{
+				Description: "git context with a ref and a subdirectory",
+				Require:     require.Arch("amd64"),
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("build", "-t", data.Identifier(), "https://github.com/docker-library/hello-world.git#master:amd64/hello-world")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", data.Identifier())
+				},
+				Expected: test.Expects(0, nil, expect.Contains("Hello from Docker!")),
+			},
+			{
+				Description: "git named context",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					dockerfile := `FROM scratch
+COPY --from=hello-world hello /hello`
+					data.Temp().Save(dockerfile, "Dockerfile")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "-t", data.Identifier(),
+						"--build-context", "hello-world=https://github.com/docker-library/hello-world.git#master:amd64/hello-world",
+						data.Temp().Path())
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "Dockerfile from stdin is not supported with a remote context",
+				Require:     require.Not(nerdtest.Docker),
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					cmd := helpers.Command("build", "-f", "-", "https://github.com/docker-library/hello-world.git")
+					cmd.Feed(strings.NewReader("FROM scratch\n"))
+					return cmd
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("not supported with a remote build context")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildProgressRawJSON(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		Require: nerdtest.Build,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			dockerfile := fmt.Sprintf(`FROM %s
+RUN echo nerdctl-build-progress`, testutil.CommonImage)
+			data.Temp().Save(dockerfile, "Dockerfile")
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "status is streamed as JSON lines",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--no-cache", "--progress=rawjson", data.Temp().Path())
+				},
+				Expected: test.Expects(0, []error{errors.New(`"vertexes"`), errors.New("nerdctl-build-progress")}, nil),
+			},
+			{
+				Description: "unknown progress type",
+				Require:     require.Not(nerdtest.Docker),
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--progress=json", data.Temp().Path())
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New(`unsupported --progress type "json"`)}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestBuildCheck(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		// the checks are run by the Dockerfile frontend of BuildKit
+		Require: nerdtest.Build,
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(fmt.Sprintf("FROM %s AS build\nMAINTAINER someone\nFROM build\nCMD [\"true\"]\n", testutil.CommonImage), "warnings", "Dockerfile")
+			data.Temp().Save(fmt.Sprintf("FROM %s\nRUN <<EOT\ntrue\nEOT\nCMD [\"true\"]\n", testutil.CommonImage), "clean", "Dockerfile")
+		},
+		SubTests: []*test.Case{
+			{
+				Description: "warnings",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--check", data.Temp().Path("warnings"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("1 warning has been found")},
+					expect.Contains("WARNING: MaintainerDeprecated", "Dockerfile:2")),
+			},
+			{
+				Description: "no warnings",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--check", data.Temp().Path("clean"))
+				},
+				Expected: test.Expects(0, nil, expect.Contains("no warnings found")),
+			},
+			{
+				Description: "targets",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--call=targets", data.Temp().Path("warnings"))
+				},
+				Expected: test.Expects(0, nil, expect.Contains("build", "(default)")),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -69,6 +69,46 @@ CMD ["echo", "nerdctl-test-builder-prune"]`, testutil.CommonImage)
 				Command:  test.Command("builder", "prune", "--force", "--all"),
 				Expected: test.Expects(0, nil, nil),
 			},
+			{
+				Description: "PruneFilterAndStorage",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-test-builder-prune"]`, testutil.CommonImage)
+					data.Temp().Save(dockerfile, "Dockerfile")
+					helpers.Ensure("build", data.Temp().Path())
+				},
+				Command: test.Command("builder", "prune", "--force", "--all",
+					"--filter", "until=24h", "--filter", "type=regular", "--keep-storage", "10GB"),
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "PruneInvalidFilter",
+				NoParallel:  true,
+				Require:     require.Not(nerdtest.Docker),
+				Command:     test.Command("builder", "prune", "--force", "--filter", "until=yesterday"),
+				Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid value for filter")}, nil),
+			},
+			{
+				Description: "DiskUsage",
+				NoParallel:  true,
+				Require:     require.Not(nerdtest.Docker),
+				Setup: func(data test.Data, helpers test.Helpers) {
+					dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-test-builder-du"]`, testutil.CommonImage)
+					data.Temp().Save(dockerfile, "Dockerfile")
+					helpers.Ensure("build", data.Temp().Path())
+				},
+				Command:  test.Command("builder", "du"),
+				Expected: test.Expects(0, nil, expect.Contains("TYPE", "RECLAIMABLE", "Total:")),
+			},
+			{
+				Description: "DiskUsageVerbose",
+				NoParallel:  true,
+				Require:     require.Not(nerdtest.Docker),
+				Command:     test.Command("builder", "du", "--verbose", "--filter", "type=regular"),
+				Expected:    test.Expects(0, nil, expect.Contains("LAST ACCESSED", "Total:")),
+			},
 			{
 				Description: "builder with buildkit-host",
 				NoParallel:  true,
@@ -0,0 +1,226 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/builderstore"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+)
+
+func createCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "create --name NAME [flags] [ENDPOINT]",
+		Short: "Create a named builder for a buildkitd instance",
+		Long: `Create a named builder for a buildkitd instance, to be selected with "--builder" or "nerdctl builder use".
+ENDPOINT is the address of buildkitd (e.g. unix:///run/user/1000/buildkit/buildkitd.sock).
+When ENDPOINT is omitted, the buildkitd detected for the current namespace is used.
+Creating a builder does not start buildkitd.`,
+		Args:          cobra.MaximumNArgs(1),
+		RunE:          createAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("name", "", "Builder name")
+	cmd.Flags().StringSlice("platform", nil, "Platforms the builder builds for (e.g. linux/amd64,linux/arm64)")
+	cmd.Flags().Bool("use", false, "Set the builder as the current builder")
+	return cmd
+}
+
+func createAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("--name must be specified")
+	}
+	platform, err := cmd.Flags().GetStringSlice("platform")
+	if err != nil {
+		return err
+	}
+	use, err := cmd.Flags().GetBool("use")
+	if err != nil {
+		return err
+	}
+	var buildkitHost string
+	if len(args) > 0 {
+		buildkitHost = args[0]
+	}
+	return builder.Create(types.BuilderCreateOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		Name:         name,
+		BuildKitHost: buildkitHost,
+		Platforms:    platform,
+		Use:          use,
+	})
+}
+
+func listCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "ls",
+		Aliases:       []string{"list"},
+		Short:         "List builders",
+		Args:          cobra.NoArgs,
+		RunE:          listAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func listAction(cmd *cobra.Command, _ []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	return builder.List(types.BuilderListOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+	})
+}
+
+func useCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Set the current builder",
+		Args:              cobra.ExactArgs(1),
+		RunE:              useAction,
+		ValidArgsFunction: builderShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func useAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	return builder.Use(types.BuilderUseOptions{
+		GOptions: globalOptions,
+		Name:     args[0],
+	})
+}
+
+func removeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "rm NAME [NAME...]",
+		Aliases:           []string{"remove"},
+		Short:             "Remove one or more builders",
+		Long:              "Remove one or more builders. The buildkitd instances themselves are not stopped.",
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              removeAction,
+		ValidArgsFunction: builderShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func removeAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	return builder.Remove(args, types.BuilderRemoveOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+	})
+}
+
+func builderShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	candidates := []string{builder.BuilderDefault, builder.BuilderContainerd}
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+	st, err := builderstore.New(globalOptions.DataRoot)
+	if err != nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+	builders, err := st.List()
+	if err != nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, b := range builders {
+		candidates = append(candidates, b.Name)
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// GetBuildkitHost returns the buildkitd address to use.
+// An explicit --buildkit-host takes precedence, then --builder (when the command has it),
+// then $BUILDKIT_HOST, then the builder selected with `nerdctl builder use`.
+// The default builder auto-detects buildkitd.
+func GetBuildkitHost(cmd *cobra.Command, globalOptions types.GlobalCommandOptions) (string, error) {
+	if cmd.Flags().Changed("buildkit-host") {
+		// If address is explicitly specified, use it.
+		buildkitHost, err := cmd.Flags().GetString("buildkit-host")
+		if err != nil {
+			return "", err
+		}
+		if err := buildkitutil.PingBKDaemon(buildkitHost); err != nil {
+			return "", err
+		}
+		return buildkitHost, nil
+	}
+
+	var builderName string
+	if f := cmd.Flags().Lookup("builder"); f != nil {
+		builderName = f.Value.String()
+	}
+	if builderName == "" && os.Getenv("BUILDKIT_HOST") != "" {
+		return buildkitutil.GetBuildkitHost(globalOptions.Namespace)
+	}
+	b, err := builder.Resolve(globalOptions.DataRoot, builderName)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case b.Name == builder.BuilderContainerd:
+		return "", fmt.Errorf("builder %q does not use BuildKit", b.Name)
+	case b.BuildKitHost == "":
+		return buildkitutil.GetBuildkitHost(globalOptions.Namespace)
+	}
+	if err := buildkitutil.PingBKDaemon(b.BuildKitHost); err != nil {
+		return "", fmt.Errorf("builder %q is not available: %w", b.Name, err)
+	}
+	return b.BuildKitHost, nil
+}
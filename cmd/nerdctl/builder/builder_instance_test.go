@@ -0,0 +1,135 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestBuilderInstances(t *testing.T) {
+	nerdtest.Setup()
+
+	testCase := &test.Case{
+		// The current builder is global state
+		NoParallel: true,
+		Require:    require.Not(nerdtest.Docker),
+		SubTests: []*test.Case{
+			{
+				Description: "create, ls, use and rm",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("builder", "create", "--name", data.Identifier(), "--platform", "linux/amd64,linux/arm64", "unix:///nonexistent/buildkitd.sock")
+					helpers.Ensure("builder", "use", data.Identifier())
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("builder", "use", "default")
+					helpers.Anyhow("builder", "rm", data.Identifier())
+				},
+				Command: test.Command("builder", "ls"),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: expect.All(
+							expect.Contains("default", "containerd"),
+							func(stdout string, t tig.T) {
+								var found bool
+								for _, line := range strings.Split(stdout, "\n") {
+									if strings.HasPrefix(line, data.Identifier()+" *") {
+										found = true
+										assert.Assert(t, strings.Contains(line, "unix:///nonexistent/buildkitd.sock"), line)
+										assert.Assert(t, strings.Contains(line, "inactive"), line)
+										assert.Assert(t, strings.Contains(line, "linux/amd64,linux/arm64"), line)
+									}
+								}
+								assert.Assert(t, found, stdout)
+							},
+						),
+					}
+				},
+			},
+			{
+				Description: "build with an unavailable builder fails",
+				NoParallel:  true,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("builder", "create", "--name", data.Identifier(), "unix:///nonexistent/buildkitd.sock")
+					data.Temp().Save(fmt.Sprintf("FROM %s\n", testutil.CommonImage), "Dockerfile")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("builder", "rm", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--builder", data.Identifier(), data.Temp().Path())
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						ExitCode: expect.ExitCodeGenericFail,
+						Errors:   []error{fmt.Errorf("builder %q is not available", data.Identifier())},
+					}
+				},
+			},
+			{
+				Description: "build with a named builder",
+				NoParallel:  true,
+				Require:     nerdtest.Build,
+				Setup: func(data test.Data, helpers test.Helpers) {
+					buildkitHost, err := buildkitutil.GetBuildkitHost(testutil.Namespace)
+					assert.NilError(helpers.T(), err)
+					helpers.Ensure("builder", "create", "--name", data.Identifier(), buildkitHost)
+					data.Temp().Save(fmt.Sprintf("FROM %s\nCMD [\"echo\", \"nerdctl-builder-instance\"]\n", testutil.CommonImage), "Dockerfile")
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier())
+					helpers.Anyhow("builder", "rm", data.Identifier())
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("build", "--builder", data.Identifier(), "-t", data.Identifier(), data.Temp().Path())
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "unknown builder",
+				Command:     test.Command("build", "--builder", "nonexistent-builder", "."),
+				Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("builder \"nonexistent-builder\" not found")}, nil),
+			},
+			{
+				Description: "reserved names",
+				Command:     test.Command("builder", "create", "--name", "containerd", "unix:///nonexistent/buildkitd.sock"),
+				Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is reserved")}, nil),
+			},
+			{
+				Description: "built-in builders cannot be removed",
+				Command:     test.Command("builder", "rm", "default"),
+				Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cannot be removed")}, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
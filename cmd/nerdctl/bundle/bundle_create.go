@@ -0,0 +1,139 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/bundle"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+)
+
+func createCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "create [flags] OUTPUT [SERVICE...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Package a compose project, its images and its network configurations into an archive",
+		Long: `Package a compose project, its images and its network configurations into an archive.
+
+Missing images are pulled (or built) before packaging.
+Load the archive on the destination host with 'nerdctl bundle load'.`,
+		Example:       "  nerdctl bundle create -f compose.yaml bundle.tar",
+		RunE:          createAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringArrayP("file", "f", nil, "Specify an alternate compose file")
+	cmd.Flags().String("project-directory", "", "Specify an alternate working directory")
+	cmd.Flags().StringP("project-name", "p", "", "Specify an alternate project name")
+	cmd.Flags().String("env-file", "", "Specify an alternate environment file")
+	cmd.Flags().StringArray("profile", []string{}, "Specify a profile to enable")
+
+	// #region platform flags
+	// platform is defined as StringSlice, not StringArray, to allow specifying "--platform=amd64,arm64"
+	cmd.Flags().StringSlice("platform", []string{}, "Bundle content for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	cmd.Flags().Bool("all-platforms", false, "Bundle content for all platforms")
+	// #endregion
+	return cmd
+}
+
+func composeOptions(cmd *cobra.Command, globalOptions types.GlobalCommandOptions) (composer.Options, error) {
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	files, err := cmd.Flags().GetStringArray("file")
+	if err != nil {
+		return composer.Options{}, err
+	}
+	projectDirectory, err := cmd.Flags().GetString("project-directory")
+	if err != nil {
+		return composer.Options{}, err
+	}
+	projectName, err := cmd.Flags().GetString("project-name")
+	if err != nil {
+		return composer.Options{}, err
+	}
+	envFile, err := cmd.Flags().GetString("env-file")
+	if err != nil {
+		return composer.Options{}, err
+	}
+	profiles, err := cmd.Flags().GetStringArray("profile")
+	if err != nil {
+		return composer.Options{}, err
+	}
+	return composer.Options{
+		Project:          projectName,
+		ProjectDirectory: projectDirectory,
+		ConfigPaths:      files,
+		Profiles:         profiles,
+		EnvFile:          envFile,
+		NerdctlCmd:       nerdctlCmd,
+		NerdctlArgs:      nerdctlArgs,
+		DebugPrintFull:   globalOptions.DebugFull,
+		Experimental:     globalOptions.Experimental,
+	}, nil
+}
+
+func createOptions(cmd *cobra.Command, args []string) (types.BundleCreateOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.BundleCreateOptions{}, err
+	}
+	platform, err := cmd.Flags().GetStringSlice("platform")
+	if err != nil {
+		return types.BundleCreateOptions{}, err
+	}
+	allPlatforms, err := cmd.Flags().GetBool("all-platforms")
+	if err != nil {
+		return types.BundleCreateOptions{}, err
+	}
+	return types.BundleCreateOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		Output:       args[0],
+		Services:     args[1:],
+		Platform:     platform,
+		AllPlatforms: allPlatforms,
+	}, nil
+}
+
+func createAction(cmd *cobra.Command, args []string) error {
+	options, err := createOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+	copts, err := composeOptions(cmd, options.GOptions)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	c, err := compose.New(client, options.GOptions, copts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	return bundle.Create(ctx, client, c, options)
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestBundleCreateLoad(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		// Removes images and networks that other tests may be using
+		nerdtest.Private,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("network", "create", data.Identifier("net"))
+		data.Temp().Save(fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: sleep infinity
+    networks:
+      - net0
+networks:
+  net0:
+    name: %s
+    external: true
+`, testutil.CommonImage, data.Identifier("net")), "compose.yaml")
+		data.Temp().Dir("out")
+
+		helpers.Ensure("bundle", "create", "-f", data.Temp().Path("compose.yaml"), data.Temp().Path("bundle.tar"))
+		helpers.Ensure("rmi", "-f", testutil.CommonImage)
+		helpers.Ensure("network", "rm", data.Identifier("net"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("network", "rm", data.Identifier("net"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("bundle", "load", "-o", data.Temp().Path("out"), data.Temp().Path("bundle.tar"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.All(
+				expect.Contains("Loaded network: "+data.Identifier("net")),
+				expect.Contains(filepath.Join(data.Temp().Path("out"), "compose.yaml")),
+				func(stdout string, t tig.T) {
+					helpers.Ensure("image", "inspect", testutil.CommonImage)
+					helpers.Ensure("network", "inspect", data.Identifier("net"))
+					helpers.Ensure("compose", "-f", filepath.Join(data.Temp().Path("out"), "compose.yaml"), "config")
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/bundle"
+)
+
+func loadCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "load [flags] BUNDLE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Load the images and network configurations of a bundle, and extract its compose file",
+		Long: `Load the images and network configurations of a bundle created by 'nerdctl bundle create',
+and extract its compose file. Run 'nerdctl compose up' in the output directory to start the project.`,
+		Example:       "  nerdctl bundle load -o /srv/app bundle.tar",
+		RunE:          loadAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringP("output", "o", ".", "Directory to extract the compose file to")
+	cmd.Flags().BoolP("quiet", "q", false, "Suppress the image load output")
+	return cmd
+}
+
+func loadOptions(cmd *cobra.Command, args []string) (types.BundleLoadOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.BundleLoadOptions{}, err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return types.BundleLoadOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.BundleLoadOptions{}, err
+	}
+	return types.BundleLoadOptions{
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		Input:     args[0],
+		Directory: output,
+		Quiet:     quiet,
+	}, nil
+}
+
+func loadAction(cmd *cobra.Command, args []string) error {
+	options, err := loadOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return bundle.Load(ctx, client, options)
+}
@@ -18,6 +18,7 @@ package completion
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -157,6 +158,21 @@ func VolumeNames(cmd *cobra.Command) ([]string, cobra.ShellCompDirective) {
 	return candidates, cobra.ShellCompDirectiveNoFileComp
 }
 
+// VolumeSpecs completes the volume names for `-v NAME:CONTAINER_PATH`, and falls back to the file completion for host paths.
+func VolumeSpecs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.Contains(toComplete, ":") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if strings.HasPrefix(toComplete, "/") || strings.HasPrefix(toComplete, ".") || strings.HasPrefix(toComplete, "~") {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	names, directive := VolumeNames(cmd)
+	for i := range names {
+		names[i] += ":"
+	}
+	return names, directive | cobra.ShellCompDirectiveNoSpace
+}
+
 func Platforms(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	candidates := []string{
 		"amd64",
@@ -201,6 +201,48 @@ func TestCompletion(t *testing.T) {
 				Command:     test.Command("__complete", "run", "-it", "--rm", ""),
 				Expected:    test.Expects(0, nil, expect.Contains(testutil.CommonImage)),
 			},
+			{
+				Description: "run -v",
+				Command:     test.Command("__complete", "run", "-v", ""),
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: expect.Contains(data.Labels().Get("identifier") + ":\n"),
+					}
+				},
+			},
+			{
+				Description: "compose logs",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					data.Temp().Save(`
+services:
+  svc0:
+    image: `+testutil.CommonImage+`
+  svc1:
+    image: `+testutil.CommonImage+`
+`, "compose.yaml")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("__complete", "compose", "--file", data.Temp().Path("compose.yaml"), "logs", "svc0", "")
+				},
+				Expected: test.Expects(0, nil, expect.All(
+					expect.Contains("svc1\n"),
+					expect.DoesNotContain("svc0\n"),
+				)),
+			},
+			{
+				Description: "compose exec",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					data.Temp().Save(`
+services:
+  svc0:
+    image: `+testutil.CommonImage+`
+`, "compose.yaml")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("__complete", "compose", "--file", data.Temp().Path("compose.yaml"), "exec", "")
+				},
+				Expected: test.Expects(0, nil, expect.Contains("svc0\n")),
+			},
 			{
 				Description: "namespace run -i",
 				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
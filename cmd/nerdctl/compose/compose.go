@@ -17,6 +17,8 @@
 package compose
 
 import (
+	"slices"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
@@ -64,6 +66,8 @@ func Command() *cobra.Command {
 		unpauseCommand(),
 		topCommand(),
 		createCommand(),
+		watchCommand(),
+		eventsCommand(),
 	)
 
 	return cmd
@@ -109,3 +113,31 @@ func getComposeOptions(cmd *cobra.Command, debugFull, experimental bool) (compos
 		IPFSAddress:      ipfsAddressStr,
 	}, nil
 }
+
+// serviceNamesComplete completes the service names of the compose project, excluding the ones already specified.
+func serviceNamesComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	options, err := getComposeOptions(cmd, false, false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names, err := composer.ProjectServiceNames(options)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var candidates []string
+	for _, name := range names {
+		if !slices.Contains(args, name) {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// serviceNameComplete completes the service name of the compose project, for the commands that take a single SERVICE
+// followed by other arguments.
+func serviceNameComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return serviceNamesComplete(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
@@ -27,15 +27,16 @@ import (
 
 func buildCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "build [flags] [SERVICE...]",
-		Short:         "Build or rebuild services",
-		RunE:          buildAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "build [flags] [SERVICE...]",
+		Short:             "Build or rebuild services",
+		RunE:              buildAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().StringArray("build-arg", nil, "Set build-time variables for services.")
 	cmd.Flags().Bool("no-cache", false, "Do not use cache when building the image.")
-	cmd.Flags().String("progress", "", "Set type of progress output (auto, plain, tty). Use plain to show container output")
+	cmd.Flags().String("progress", "", "Set type of progress output (auto, plain, tty, json, quiet). Use plain to show container output. Defaults to the global --progress")
 
 	return cmd
 }
@@ -38,7 +38,14 @@ func configCommand() *cobra.Command {
 	cmd.Flags().BoolP("quiet", "q", false, "Only validate the configuration, don't print anything.")
 	cmd.Flags().Bool("services", false, "Print the service names, one per line.")
 	cmd.Flags().Bool("volumes", false, "Print the volume names, one per line.")
+	cmd.Flags().Bool("profiles", false, "Print the profile names, one per line.")
 	cmd.Flags().String("hash", "", "Print the service config hash, one per line.")
+	cmd.Flags().String("format", "yaml", "Format the output. Values: [yaml | json]")
+	cmd.Flags().Bool("no-interpolate", false, "Don't interpolate environment variables.")
+	cmd.Flags().Bool("resolve-image-digests", false, "Pin image tags to digests.")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.RegisterFlagCompletionFunc("hash", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"\"*\""}, cobra.ShellCompDirectiveNoFileComp
 	})
@@ -66,10 +73,26 @@ func configAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	profiles, err := cmd.Flags().GetBool("profiles")
+	if err != nil {
+		return err
+	}
 	hash, err := cmd.Flags().GetString("hash")
 	if err != nil {
 		return err
 	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	noInterpolate, err := cmd.Flags().GetBool("no-interpolate")
+	if err != nil {
+		return err
+	}
+	resolveImageDigests, err := cmd.Flags().GetBool("resolve-image-digests")
+	if err != nil {
+		return err
+	}
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -80,6 +103,7 @@ func configAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	options.NoInterpolate = noInterpolate
 	c, err := compose.New(client, globalOptions, options, cmd.OutOrStdout(), cmd.ErrOrStderr())
 	if err != nil {
 		return err
@@ -91,7 +115,11 @@ func configAction(cmd *cobra.Command, args []string) error {
 	co := composer.ConfigOptions{
 		Services: services,
 		Volumes:  volumes,
+		Profiles: profiles,
 		Hash:     hash,
+
+		Format:              format,
+		ResolveImageDigests: resolveImageDigests,
 	}
 	return c.Config(ctx, cmd.OutOrStdout(), co)
 }
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -77,6 +78,185 @@ services:
 	testCase.Run(t)
 }
 
+func TestComposeConfigFormat(t *testing.T) {
+	dockerComposeYAML := fmt.Sprintf(`
+services:
+  hello:
+    image: %s
+    environment:
+      FOO: ${FOO}
+`, testutil.CommonImage)
+
+	type composeJSON struct {
+		Services map[string]struct {
+			Image       string            `json:"image"`
+			Environment map[string]string `json:"environment"`
+		} `json:"services"`
+	}
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Labels().Set("composeYaml", data.Temp().Path("compose.yaml"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "config --format json",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--format", "json")
+				cmd.Setenv("FOO", "bar")
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.JSON(
+				composeJSON{},
+				func(project composeJSON, t tig.T) {
+					assert.Equal(t, project.Services["hello"].Image, testutil.CommonImage)
+					assert.Equal(t, project.Services["hello"].Environment["FOO"], "bar")
+				},
+			)),
+		},
+		{
+			Description: "config --no-interpolate",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--no-interpolate")
+				cmd.Setenv("FOO", "bar")
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+				expect.Contains("FOO: ${FOO}"),
+				expect.DoesNotContain("FOO: bar"),
+			)),
+		},
+		{
+			Description: "config --resolve-image-digests",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--resolve-image-digests")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("@sha256:")),
+		},
+		{
+			Description: "config --format with an unsupported value",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--format", "toml")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeConfigInclude(t *testing.T) {
+	dockerComposeYAML := fmt.Sprintf(`
+include:
+  - path: sub/compose.yaml
+    env_file: sub/sub.env
+services:
+  hello:
+    image: %s
+    depends_on:
+      - included
+`, testutil.CommonImage)
+
+	const includedComposeYAML = `
+services:
+  included:
+    image: ${INCLUDED_IMAGE}
+    volumes:
+      - ./data:/data
+`
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Temp().Save(includedComposeYAML, "sub", "compose.yaml")
+		data.Temp().Save("INCLUDED_IMAGE="+testutil.CommonImage, "sub", "sub.env")
+		data.Labels().Set("composeYaml", data.Temp().Path("compose.yaml"))
+		data.Labels().Set("dataDir", data.Temp().Path("sub", "data"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "included services are listed",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--services")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("hello\n", "included\n")),
+		},
+		{
+			Description: "included services use the env_file and the directory of the included file",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains("image: "+testutil.CommonImage, "source: "+data.Labels().Get("dataDir")),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeConfigProfiles(t *testing.T) {
+	dockerComposeYAML := fmt.Sprintf(`
+services:
+  hello:
+    image: %[1]s
+  debug:
+    image: %[1]s
+    profiles:
+      - debug
+      - test
+  tools:
+    image: %[1]s
+    profiles:
+      - tools
+`, testutil.CommonImage)
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Labels().Set("composeYaml", data.Temp().Path("compose.yaml"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "config --profiles lists all declared profiles",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "config", "--profiles")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("debug\ntest\ntools\n")),
+		},
+		{
+			Description: "config --services only lists services of active profiles",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command(
+					"compose",
+					"-f",
+					data.Labels().Get("composeYaml"),
+					"--profile",
+					"tools",
+					"config",
+					"--services",
+				)
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+				assert.Assert(t, strings.Contains(stdout, "hello\n"))
+				assert.Assert(t, strings.Contains(stdout, "tools\n"))
+				assert.Assert(t, !strings.Contains(stdout, "debug"))
+			}),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestComposeConfigWithPrintServiceHash(t *testing.T) {
 	const dockerComposeYAML = `
 services:
@@ -89,3 +89,63 @@ services:
 
 	testCase.Run(t)
 }
+
+func TestComposeCopyReplicas(t *testing.T) {
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    deploy:
+      replicas: 2
+`, testutil.CommonImage)
+
+	const testFileContent = "test-file-content"
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		compYamlPath := data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		helpers.Ensure("compose", "-f", compYamlPath, "up", "-d")
+
+		srcFilePath := data.Temp().Save(testFileContent, "test-file")
+
+		data.Labels().Set("composeYaml", compYamlPath)
+		data.Labels().Set("srcFile", srcFilePath)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "test copy to the second replica only",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("compose", "-f", data.Labels().Get("composeYaml"),
+					"cp", "--index", "2", data.Labels().Get("srcFile"), "svc0:/index-2")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"),
+					"exec", "-i=false", "--no-TTY", "--index", "1", "svc0", "cat", "/index-2")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "test copy to all the replicas",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("compose", "-f", data.Labels().Get("composeYaml"),
+					"cp", data.Labels().Get("srcFile"), "svc0:/all")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("composeYaml"),
+					"exec", "-i=false", "--no-TTY", "--index", "2", "svc0", "cat", "/all")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals(testFileContent)),
+		},
+	}
+
+	testCase.Run(t)
+}
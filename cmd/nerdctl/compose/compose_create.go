@@ -29,11 +29,12 @@ import (
 
 func createCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "create [flags] [SERVICE...]",
-		Short:         "Creates containers for one or more services",
-		RunE:          createAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "create [flags] [SERVICE...]",
+		Short:             "Creates containers for one or more services",
+		RunE:              createAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().Bool("build", false, "Build images before starting containers.")
 	cmd.Flags().Bool("no-build", false, "Don't build an image even if it's missing, conflict with --build.")
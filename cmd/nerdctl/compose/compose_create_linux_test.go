@@ -224,5 +224,5 @@ services:
 
 	container := serviceparser.DefaultContainerName(projectName, "svc0", "1")
 	base.Cmd("inspect", "--format", "{{json .Config.Labels}}", container).
-		AssertOutContains("com.docker.compose.config-hash")
+		AssertOutContainsAll("com.docker.compose.config-hash", "com.docker.compose.image")
 }
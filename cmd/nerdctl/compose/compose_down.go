@@ -17,6 +17,8 @@
 package compose
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
@@ -36,6 +38,10 @@ func downCommand() *cobra.Command {
 	}
 	cmd.Flags().BoolP("volumes", "v", false, "Remove named volumes declared in the `volumes` section of the Compose file and anonymous volumes attached to containers.")
 	cmd.Flags().Bool("remove-orphans", false, "Remove containers for services not defined in the Compose file.")
+	cmd.Flags().String("rmi", "", "Remove images used by services. \"local\" remove only images that don't have a custom tag (\"local\"|\"all\")")
+	cmd.RegisterFlagCompletionFunc("rmi", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{composer.RemoveImagesLocal, composer.RemoveImagesAll}, cobra.ShellCompDirectiveNoFileComp
+	})
 	return cmd
 }
 
@@ -57,6 +63,13 @@ func downAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	rmi, err := cmd.Flags().GetString("rmi")
+	if err != nil {
+		return err
+	}
+	if rmi != "" && rmi != composer.RemoveImagesLocal && rmi != composer.RemoveImagesAll {
+		return fmt.Errorf("invalid value for --rmi: %q (must be %q or %q)", rmi, composer.RemoveImagesLocal, composer.RemoveImagesAll)
+	}
 	defer cancel()
 	options, err := getComposeOptions(cmd, globalOptions.DebugFull, globalOptions.Experimental)
 	if err != nil {
@@ -70,6 +83,7 @@ func downAction(cmd *cobra.Command, args []string) error {
 	downOpts := composer.DownOptions{
 		RemoveVolumes: volumes,
 		RemoveOrphans: removeOrphans,
+		RemoveImages:  rmi,
 	}
 	return c.Down(ctx, downOpts)
 }
@@ -94,3 +94,79 @@ services:
 	base.ComposeCmd("-p", projectName, "-f", compOrphan.YAMLFullPath(), "down", "--remove-orphans").AssertOK()
 	base.ComposeCmd("-p", projectName, "-f", compFull.YAMLFullPath(), "ps", "-a").AssertOutNotContains(orphanContainer)
 }
+
+func TestComposeDownProfile(t *testing.T) {
+	base := testutil.NewBase(t)
+	serviceRegular := testutil.Identifier(t) + "-regular"
+	serviceProfiled := testutil.Identifier(t) + "-profiled"
+
+	dockerComposeYAML := fmt.Sprintf(`
+services:
+  %s:
+    image: %[3]s
+    command: "sleep infinity"
+
+  %[2]s:
+    image: %[3]s
+    command: "sleep infinity"
+    profiles:
+      - test-profile
+`, serviceRegular, serviceProfiled, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "--profile", "test-profile", "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "--profile", "test-profile", "down", "-v").Run()
+
+	// services of inactive profiles are not listed
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps").AssertOutNotContains(serviceProfiled)
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "--profile", "test-profile", "ps").AssertOutContains(serviceProfiled)
+
+	// services of inactive profiles are neither removed nor considered as orphans
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "--remove-orphans").AssertOK()
+	psCmd := base.Cmd("ps", "-a", "--format={{.Names}}")
+	psCmd.AssertOutNotContains(serviceRegular)
+	psCmd.AssertOutContains(serviceProfiled)
+}
+
+func TestComposeDownRmi(t *testing.T) {
+	const imageTagged = "composedownrmi_tagged"
+
+	dockerComposeYAML := fmt.Sprintf(`
+services:
+  local:
+    build: .
+    command: "sleep infinity"
+  tagged:
+    build: .
+    image: %s
+    command: "sleep infinity"
+`, imageTagged)
+
+	testutil.RequiresBuild(t)
+	testutil.RegisterBuildCacheCleanup(t)
+	base := testutil.NewBase(t)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	comp.WriteFile("Dockerfile", fmt.Sprintf(`FROM %s`, testutil.CommonImage))
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	imageLocal := serviceparser.DefaultImageName(projectName, "local")
+	defer base.Cmd("rmi", "-f", imageLocal, imageTagged).Run()
+
+	// --rmi=local only removes the images without a custom tag
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--build").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "--rmi=local").AssertOK()
+	imagesCmd := base.Cmd("images", "--format={{.Repository}}")
+	imagesCmd.AssertOutNotContains(imageLocal)
+	imagesCmd.AssertOutContains(imageTagged)
+
+	// --rmi=all removes all the images of the services
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--build").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "--rmi=all").AssertOK()
+	base.Cmd("images", "--format={{.Repository}}").AssertOutNotContains(imageTagged)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "--rmi=none").AssertFail()
+}
@@ -31,12 +31,13 @@ import (
 
 func execCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "exec [flags] SERVICE COMMAND [ARGS...]",
-		Short:         "Execute a command in a running container of the service",
-		Args:          cobra.MinimumNArgs(2),
-		RunE:          execAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "exec [flags] SERVICE COMMAND [ARGS...]",
+		Short:             "Execute a command in a running container of the service",
+		Args:              cobra.MinimumNArgs(2),
+		RunE:              execAction,
+		ValidArgsFunction: serviceNameComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().SetInterspersed(false)
 
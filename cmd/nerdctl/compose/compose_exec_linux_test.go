@@ -358,5 +358,19 @@ services:
 		})
 	}
 
+	testCase.SubTests = append(testCase.SubTests, &test.Case{
+		Description: "index out of range",
+		Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			return helpers.Command("compose", "-f", data.Labels().Get("YAMLPath"), "exec", "-i=false", "--no-TTY", "--index", "4", "svc0", "true")
+		},
+		Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+	}, &test.Case{
+		Description: "logs of a single instance",
+		Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			return helpers.Command("compose", "-f", data.Labels().Get("YAMLPath"), "logs", "--index", "2", "svc0")
+		},
+		Expected: test.Expects(expect.ExitCodeSuccess, nil, nil),
+	})
+
 	testCase.Run(t)
 }
@@ -40,11 +40,12 @@ import (
 
 func imagesCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "images [flags] [SERVICE...]",
-		Short:         "List images used by created containers in services",
-		RunE:          imagesAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "images [flags] [SERVICE...]",
+		Short:             "List images used by created containers in services",
+		RunE:              imagesAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().String("format", "", "Format the output. Supported values: [json]")
 	cmd.Flags().BoolP("quiet", "q", false, "Only show numeric image IDs")
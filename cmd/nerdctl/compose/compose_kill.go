@@ -27,11 +27,12 @@ import (
 
 func killCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "kill [flags] [SERVICE...]",
-		Short:         "Force stop service containers",
-		RunE:          killAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "kill [flags] [SERVICE...]",
+		Short:             "Force stop service containers",
+		RunE:              killAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().StringP("signal", "s", "SIGKILL", "SIGNAL to send to the container.")
 	return cmd
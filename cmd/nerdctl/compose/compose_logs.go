@@ -17,6 +17,8 @@
 package compose
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
@@ -27,17 +29,21 @@ import (
 
 func logsCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "logs [flags] [SERVICE...]",
-		Short:         "Show logs of running containers",
-		RunE:          logsAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "logs [flags] [SERVICE...]",
+		Short:             "Show logs of running containers",
+		RunE:              logsAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().BoolP("follow", "f", false, "Follow log output.")
 	cmd.Flags().BoolP("timestamps", "t", false, "Show timestamps")
-	cmd.Flags().String("tail", "all", "Number of lines to show from the end of the logs")
+	cmd.Flags().StringP("tail", "n", "all", "Number of lines to show from the end of the logs")
+	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
+	cmd.Flags().String("until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	cmd.Flags().Bool("no-color", false, "Produce monochrome output")
 	cmd.Flags().Bool("no-log-prefix", false, "Don't print prefix in logs")
+	cmd.Flags().Int("index", 0, "index of the container if the service has multiple instances.")
 	return cmd
 }
 
@@ -58,6 +64,14 @@ func logsAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return err
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return err
+	}
 	noColor, err := cmd.Flags().GetBool("no-color")
 	if err != nil {
 		return err
@@ -66,6 +80,13 @@ func logsAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	index, err := cmd.Flags().GetInt("index")
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("index") && index < 1 {
+		return fmt.Errorf("index starts from 1 and should be equal or greater than 1, given index: %d", index)
+	}
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -85,8 +106,11 @@ func logsAction(cmd *cobra.Command, args []string) error {
 		Follow:      follow,
 		Timestamps:  timestamps,
 		Tail:        tail,
+		Since:       since,
+		Until:       until,
 		NoColor:     noColor,
 		NoLogPrefix: noLogPrefix,
+		Index:       index,
 	}
 	return c.Logs(ctx, lo, args)
 }
@@ -29,6 +29,7 @@ func pauseCommand() *cobra.Command {
 		Use:                   "pause [SERVICE...]",
 		Short:                 "Pause all processes within containers of service(s). They can be unpaused with nerdctl compose unpause",
 		RunE:                  pauseAction,
+		ValidArgsFunction:     serviceNamesComplete,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -64,6 +65,7 @@ func unpauseCommand() *cobra.Command {
 		Use:                   "unpause [SERVICE...]",
 		Short:                 "Unpause all processes within containers of service(s).",
 		RunE:                  unpauseAction,
+		ValidArgsFunction:     serviceNamesComplete,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -19,6 +19,7 @@ package compose
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -30,12 +31,13 @@ import (
 
 func portCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "port [flags] SERVICE PRIVATE_PORT",
-		Short:         "Print the public port for a port binding",
-		Args:          cobra.ExactArgs(2),
-		RunE:          portAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "port [flags] SERVICE PRIVATE_PORT[/PROTOCOL]",
+		Short:             "Print the public port for a port binding",
+		Args:              cobra.ExactArgs(2),
+		RunE:              portAction,
+		ValidArgsFunction: serviceNameComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().Int("index", 1, "index of the container if the service has multiple instances.")
 	cmd.Flags().String("protocol", "tcp", "protocol of the port (tcp|udp)")
@@ -60,13 +62,20 @@ func portAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	portStr, portProtocol, hasProtocol := strings.Cut(args[1], "/")
+	if hasProtocol {
+		if cmd.Flags().Changed("protocol") && portProtocol != protocol {
+			return fmt.Errorf("conflicting protocols: %q and --protocol=%q", args[1], protocol)
+		}
+		protocol = portProtocol
+	}
 	switch protocol {
 	case "tcp", "udp":
 	default:
 		return fmt.Errorf("unsupported protocol: %s (only tcp and udp are supported)", protocol)
 	}
 
-	port, err := strconv.Atoi(args[1])
+	port, err := strconv.Atoi(portStr)
 	if err != nil {
 		return err
 	}
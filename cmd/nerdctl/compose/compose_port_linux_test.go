@@ -18,8 +18,11 @@ package compose
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 
@@ -51,6 +54,30 @@ services:
 	// `port` should work for given port and protocol
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "svc0", "10000").AssertOutExactly("0.0.0.0:12345\n")
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "--protocol", "udp", "svc0", "10001").AssertOutExactly("0.0.0.0:12346\n")
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "svc0", "10001/udp").AssertOutExactly("0.0.0.0:12346\n")
+}
+
+func TestComposePortDynamic(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    ports:
+    - "10000"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").AssertOK()
+
+	// `port` should print the host port allocated for the private port
+	out := base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "svc0", "10000").Out()
+	assert.Assert(t, regexp.MustCompile(`^0\.0\.0\.0:[0-9]+\n$`).MatchString(out), out)
 }
 
 func TestComposePortFailure(t *testing.T) {
@@ -78,6 +105,7 @@ services:
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "svc0", "9999").AssertFail()
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "--protocol", "udp", "svc0", "10000").AssertFail()
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "--protocol", "tcp", "svc0", "10001").AssertFail()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "port", "--protocol", "tcp", "svc0", "10001/udp").AssertFail()
 }
 
 // TestComposeMultiplePorts tests whether it is possible to allocate a large
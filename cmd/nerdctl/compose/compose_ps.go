@@ -34,6 +34,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
@@ -42,15 +43,16 @@ import (
 
 func psCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "ps [flags] [SERVICE...]",
-		Short:         "List containers of services",
-		RunE:          psAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "ps [flags] [SERVICE...]",
+		Short:             "List containers of services",
+		RunE:              psAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().String("format", "table", "Format the output. Supported values: [table|json]")
-	cmd.Flags().String("filter", "", "Filter matches containers based on given conditions")
-	cmd.Flags().StringArray("status", []string{}, "Filter services by status. Values: [paused | restarting | removing | running | dead | created | exited]")
+	cmd.Flags().StringArray("filter", []string{}, "Filter matches containers based on given conditions")
+	cmd.Flags().StringArray("status", []string{}, "Filter services by status. Values: [paused | restarting | removing | running | dead | created | exited]. Implies --all")
 	cmd.Flags().BoolP("quiet", "q", false, "Only display container IDs")
 	cmd.Flags().Bool("services", false, "Display services")
 	cmd.Flags().BoolP("all", "a", false, "Show all containers (default shows just running)")
@@ -65,7 +67,7 @@ type composeContainerPrintable struct {
 	Project  string
 	Service  string
 	State    string
-	Health   string // placeholder, lack containerd support.
+	Health   string // empty if the container has no healthcheck
 	ExitCode uint32
 	// `Publishers` stores docker-compatible ports and used for json output.
 	// `Ports` stores formatted ports and only used for console output.
@@ -97,11 +99,11 @@ func psAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	filter, err := cmd.Flags().GetString("filter")
+	filters, err := cmd.Flags().GetStringArray("filter")
 	if err != nil {
 		return err
 	}
-	if filter != "" {
+	for _, filter := range filters {
 		splited := strings.SplitN(filter, "=", 2)
 		if len(splited) != 2 {
 			return fmt.Errorf("invalid argument \"%s\" for \"-f, --filter\": bad format of filter (expected name=value)", filter)
@@ -117,6 +119,8 @@ func psAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	// filtering by status implies showing all the containers, as in `docker compose ps`
+	all = all || len(status) != 0
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -200,7 +204,13 @@ func psAction(cmd *cobra.Command, args []string) error {
 	}
 
 	if displayServices {
+		// the replicas of a service are listed once
+		seen := make(map[string]struct{})
 		for _, p := range containersPrintable {
+			if _, ok := seen[p.Service]; ok {
+				continue
+			}
+			seen[p.Service] = struct{}{}
 			fmt.Fprintln(cmd.OutOrStdout(), p.Service)
 		}
 		return nil
@@ -246,6 +256,13 @@ func composeContainerPrintableTab(ctx context.Context, container containerd.Cont
 	status := formatter.ContainerStatus(ctx, container)
 	if status == "Up" {
 		status = "running" // corresponds to Docker Compose v2.0.1
+		health, err := composer.ContainerHealth(ctx, container)
+		if err != nil {
+			return composeContainerPrintable{}, err
+		}
+		if health != nil {
+			status = fmt.Sprintf("%s (%s)", status, health.Status)
+		}
 	}
 	image, err := container.Image(ctx)
 	if err != nil {
@@ -302,6 +319,16 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 	} else {
 		state = string(containerd.Unknown)
 	}
+	var healthStatus string
+	if state == string(containerd.Running) {
+		health, err := composer.ContainerHealth(ctx, container)
+		if err != nil {
+			return composeContainerPrintable{}, err
+		}
+		if health != nil {
+			healthStatus = health.Status
+		}
+	}
 	image, err := container.Image(ctx)
 	if err != nil {
 		return composeContainerPrintable{}, err
@@ -327,7 +354,7 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 		Project:    info.Labels[labels.ComposeProject],
 		Service:    info.Labels[labels.ComposeService],
 		State:      state,
-		Health:     "",
+		Health:     healthStatus,
 		ExitCode:   exitCode,
 		Publishers: formatPublishers(portMappings),
 	}, nil
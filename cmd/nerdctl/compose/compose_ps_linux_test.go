@@ -100,6 +100,32 @@ volumes:
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "alpine", "-a").AssertOutWithFunc(assertHandler("alpine_container", testutil.CommonImage))
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "-a", "--filter", "status=exited").AssertOutWithFunc(assertHandler("alpine_container", testutil.CommonImage))
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--services", "-a").AssertOutContainsAll("wordpress\n", "db\n", "alpine\n")
+	// filtering by status implies --all
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--status", "exited").AssertOutWithFunc(assertHandler("alpine_container", testutil.CommonImage))
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--services", "--filter", "status=running", "--filter", "status=exited").
+		AssertOutContainsAll("wordpress\n", "db\n", "alpine\n")
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--filter", "name=alpine").AssertFail()
+}
+
+func TestComposePsServicesReplicas(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    scale: 2
+`, testutil.CommonImage)
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	// the replicas of a service are listed once
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--services").AssertOutExactly("svc0\n")
 }
 
 func TestComposePsJSON(t *testing.T) {
@@ -182,3 +208,46 @@ volumes:
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--format", "json", "wordpress").
 		AssertOutWithFunc(assertHandler("wordpress", 0))
 }
+
+func TestComposePsHealth(t *testing.T) {
+	// `nerdctl container healthcheck` is used to run the healthchecks without waiting for the interval
+	testutil.DockerIncompatible(t)
+
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %[1]s
+    command: "sleep infinity"
+    healthcheck:
+      test: ["CMD", "true"]
+      interval: 1h
+  svc1:
+    image: %[1]s
+    command: "sleep infinity"
+    healthcheck:
+      disable: true
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").AssertOK()
+
+	base.Cmd("container", "healthcheck", fmt.Sprintf("%s-svc0-1", projectName)).AssertOK()
+
+	assertHealth := func(service, expected string) {
+		var printables []composeContainerPrintable
+		out := base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "--format", "json", service).Out()
+		assert.NilError(t, json.Unmarshal([]byte(out), &printables), out)
+		assert.Equal(t, len(printables), 1, out)
+		assert.Equal(t, printables[0].Health, expected, out)
+	}
+	assertHealth("svc0", "healthy")
+	assertHealth("svc1", "")
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "svc0").AssertOutContains("running (healthy)")
+}
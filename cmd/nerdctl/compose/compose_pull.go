@@ -27,13 +27,16 @@ import (
 
 func pullCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "pull [flags] [SERVICE...]",
-		Short:         "Pull service images",
-		RunE:          pullAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "pull [flags] [SERVICE...]",
+		Short:             "Pull service images",
+		RunE:              pullAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().BoolP("quiet", "q", false, "Pull without printing progress information")
+	cmd.Flags().Bool("include-deps", false, "Also pull services declared as dependencies")
+	cmd.Flags().Bool("ignore-buildable", false, "Ignore images that can be built")
 	return cmd
 }
 
@@ -61,8 +64,18 @@ func pullAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	includeDeps, err := cmd.Flags().GetBool("include-deps")
+	if err != nil {
+		return err
+	}
+	ignoreBuildable, err := cmd.Flags().GetBool("ignore-buildable")
+	if err != nil {
+		return err
+	}
 	po := composer.PullOptions{
-		Quiet: quiet,
+		Quiet:           quiet,
+		IncludeDeps:     includeDeps,
+		IgnoreBuildable: ignoreBuildable,
 	}
 	return c.Pull(ctx, po, args)
 }
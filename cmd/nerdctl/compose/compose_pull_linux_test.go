@@ -18,8 +18,11 @@ package compose
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 )
 
@@ -60,3 +63,45 @@ volumes:
 
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "pull", "db").AssertOutNotContains("wordpress")
 }
+
+func TestComposePullIncludeDeps(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  app:
+    image: %s
+    depends_on:
+      - db
+  db:
+    image: %s
+`, testutil.CommonImage, testutil.NginxAlpineImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	out := base.ComposeCmd("-f", comp.YAMLFullPath(), "pull", "app").Run().Combined()
+	assert.Assert(t, !strings.Contains(out, testutil.NginxAlpineImage), out)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "pull", "--include-deps", "app").AssertCombinedOutContains("Pulled image " + testutil.NginxAlpineImage + " for service db")
+}
+
+func TestComposePullIgnoreBuildable(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  app:
+    image: %s
+  built:
+    image: nerdctl-compose-test-pull-not-pushed
+    build: .
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	comp.WriteFile("Dockerfile", "FROM "+testutil.CommonImage)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "pull").AssertFail()
+	out := base.ComposeCmd("-f", comp.YAMLFullPath(), "pull", "--ignore-buildable").Run()
+	assert.Equal(t, out.ExitCode, 0, out.Combined())
+	assert.Assert(t, !strings.Contains(out.Combined(), "nerdctl-compose-test-pull-not-pushed"), out.Combined())
+}
@@ -27,11 +27,12 @@ import (
 
 func pushCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "push [flags] [SERVICE...]",
-		Short:         "Push service images",
-		RunE:          pushAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "push [flags] [SERVICE...]",
+		Short:             "Push service images",
+		RunE:              pushAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	return cmd
 }
@@ -27,13 +27,15 @@ import (
 
 func restartCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "restart [flags] [SERVICE...]",
-		Short:         "Restart containers of given (or all) services",
-		RunE:          restartAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "restart [flags] [SERVICE...]",
+		Short:             "Restart containers of given (or all) services",
+		RunE:              restartAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().UintP("timeout", "t", 10, "Seconds to wait before restarting them")
+	cmd.Flags().Bool("no-deps", false, "Don't restart the dependencies of the specified services")
 	return cmd
 }
 
@@ -51,6 +53,11 @@ func restartAction(cmd *cobra.Command, args []string) error {
 		}
 		opt.Timeout = &timeValue
 	}
+	noDeps, err := cmd.Flags().GetBool("no-deps")
+	if err != nil {
+		return err
+	}
+	opt.NoDeps = noDeps
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -30,11 +30,12 @@ import (
 
 func removeCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "rm [flags] [SERVICE...]",
-		Short:         "Remove stopped service containers",
-		RunE:          removeAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "rm [flags] [SERVICE...]",
+		Short:             "Remove stopped service containers",
+		RunE:              removeAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().BoolP("force", "f", false, "Do not prompt for confirmation")
 	cmd.Flags().BoolP("stop", "s", false, "Stop containers before removing")
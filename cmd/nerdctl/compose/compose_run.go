@@ -34,6 +34,7 @@ func runCommand() *cobra.Command {
 		Short:                 "Run a one-off command on a service",
 		Args:                  cobra.MinimumNArgs(1),
 		RunE:                  runAction,
+		ValidArgsFunction:     serviceNameComplete,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -64,7 +65,7 @@ func runCommand() *cobra.Command {
 	//        For docker compatibility, it should be fixed.
 	cmd.Flags().StringSlice("publish", nil, "Publish a container's port(s) to the host")
 	cmd.Flags().Bool("service-ports", false, "Run command with the service's ports enabled and mapped to the host")
-	// TODO: use-aliases
+	cmd.Flags().Bool("use-aliases", false, "Use the service's network aliases in the network(s) the container connects to")
 
 	return cmd
 }
@@ -157,6 +158,10 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	useAliases, err := cmd.Flags().GetBool("use-aliases")
+	if err != nil {
+		return err
+	}
 
 	if servicePorts && publish != nil && len(publish) > 0 {
 		return fmt.Errorf("--service-ports and --publish(-p) cannot exist simultaneously")
@@ -209,6 +214,7 @@ func runAction(cmd *cobra.Command, args []string) error {
 		Label:        label,
 		WorkDir:      workdir,
 		ServicePorts: servicePorts,
+		UseAliases:   useAliases,
 		Publish:      publish,
 	}
 
@@ -41,6 +41,7 @@ func startCommand() *cobra.Command {
 		Use:                   "start [SERVICE...]",
 		Short:                 "Start existing containers for service(s)",
 		RunE:                  startAction,
+		ValidArgsFunction:     serviceNamesComplete,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
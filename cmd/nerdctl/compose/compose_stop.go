@@ -27,13 +27,15 @@ import (
 
 func stopCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "stop [flags] [SERVICE...]",
-		Short:         "Stop running containers without removing them.",
-		RunE:          stopAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "stop [flags] [SERVICE...]",
+		Short:             "Stop running containers without removing them.",
+		RunE:              stopAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().UintP("timeout", "t", 10, "Seconds to wait for stop before killing them")
+	cmd.Flags().Bool("no-deps", false, "Don't stop the dependencies of the specified services")
 	return cmd
 }
 
@@ -51,6 +53,11 @@ func stopAction(cmd *cobra.Command, args []string) error {
 		}
 		opt.Timeout = &timeValue
 	}
+	noDeps, err := cmd.Flags().GetBool("no-deps")
+	if err != nil {
+		return err
+	}
+	opt.NoDeps = noDeps
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
 	if err != nil {
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
 
@@ -37,6 +38,7 @@ func topCommand() *cobra.Command {
 		Use:                   "top [SERVICE...]",
 		Short:                 "Display the running processes of service containers",
 		RunE:                  topAction,
+		ValidArgsFunction:     serviceNamesComplete,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -67,34 +69,45 @@ func topAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	containers, err := c.Containers(ctx, serviceNames...)
-	if err != nil {
-		return err
-	}
+	// group the containers by service, in a stable order
+	sort.Strings(serviceNames)
 	stdout := cmd.OutOrStdout()
-	for _, c := range containers {
-		cStatus, err := containerutil.ContainerStatus(ctx, c)
+	for _, svc := range serviceNames {
+		containers, err := c.Containers(ctx, svc)
 		if err != nil {
 			return err
 		}
-		if cStatus.Status != containerd.Running {
-			continue
-		}
-
-		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
-		if err != nil {
-			return err
+		names := make(map[string]string, len(containers))
+		for _, c := range containers {
+			info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+			if err != nil {
+				return err
+			}
+			names[c.ID()] = info.Labels[labels.Name]
 		}
-		fmt.Fprintln(stdout, info.Labels[labels.Name])
-		// `compose ps` uses empty ps args
-		err = container.Top(ctx, client, []string{c.ID()}, types.ContainerTopOptions{
-			Stdout:   cmd.OutOrStdout(),
-			GOptions: globalOptions,
+		sort.Slice(containers, func(i, j int) bool {
+			return names[containers[i].ID()] < names[containers[j].ID()]
 		})
-		if err != nil {
-			return err
+		for _, c := range containers {
+			cStatus, err := containerutil.ContainerStatus(ctx, c)
+			if err != nil {
+				return err
+			}
+			if cStatus.Status != containerd.Running {
+				continue
+			}
+
+			fmt.Fprintln(stdout, names[c.ID()])
+			// `compose ps` uses empty ps args
+			err = container.Top(ctx, client, []string{c.ID()}, types.ContainerTopOptions{
+				Stdout:   cmd.OutOrStdout(),
+				GOptions: globalOptions,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(stdout)
 		}
-		fmt.Fprintln(stdout)
 	}
 
 	return nil
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -67,6 +68,13 @@ services:
 			},
 			Expected: test.Expects(0, nil, expect.Contains("nginx")),
 		},
+		{
+			Description: "all the services are listed, grouped by service",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Labels().Get("yamlPath"), "top")
+			},
+			Expected: test.Expects(0, nil, expect.Match(regexp.MustCompile(`(?s)svc0-1\n.*sleep infinity.*svc1-1\n.*nginx`))),
+		},
 	}
 
 	testCase.Run(t)
@@ -32,11 +32,12 @@ import (
 
 func upCommand() *cobra.Command {
 	var cmd = &cobra.Command{
-		Use:           "up [flags] [SERVICE...]",
-		Short:         "Create and start containers",
-		RunE:          upAction,
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "up [flags] [SERVICE...]",
+		Short:             "Create and start containers",
+		RunE:              upAction,
+		ValidArgsFunction: serviceNamesComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 	}
 	cmd.Flags().Bool("abort-on-container-exit", false, "Stops all containers if any container was stopped. Incompatible with -d.")
 	cmd.Flags().BoolP("detach", "d", false, "Detached mode: Run containers in the background. Incompatible with --abort-on-container-exit.")
@@ -51,6 +52,11 @@ func upCommand() *cobra.Command {
 	cmd.Flags().Bool("no-recreate", false, "Don't recreate containers if they exist, conflict with --force-recreate.")
 	cmd.Flags().StringArray("scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
 	cmd.Flags().String("pull", "", "Pull image before running (\"always\"|\"missing\"|\"never\")")
+	cmd.Flags().Bool("wait", false, "Wait for services to be running|healthy. Implies detached mode.")
+	cmd.Flags().Duration("wait-timeout", 0, "Maximum duration to wait for the services to be running|healthy (0 for no timeout)")
+	cmd.Flags().Bool("no-deps", false, "Don't start linked services")
+	cmd.Flags().Bool("attach-dependencies", false, "Automatically attach to log output of dependent services")
+	cmd.Flags().Duration("dependency-timeout", 0, "Maximum duration to wait for dependencies with the service_healthy condition to become healthy (0 for no timeout)")
 	return cmd
 }
 
@@ -70,6 +76,19 @@ func upAction(cmd *cobra.Command, services []string) error {
 	if err != nil {
 		return err
 	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		return err
+	}
+	if wait && abortOnContainerExit {
+		return fmt.Errorf("--abort-on-container-exit flag is incompatible with flag --wait")
+	}
+	// --wait implies --detach
+	detach = detach || wait
+	waitTimeout, err := cmd.Flags().GetDuration("wait-timeout")
+	if err != nil {
+		return err
+	}
 	noBuild, err := cmd.Flags().GetBool("no-build")
 	if err != nil {
 		return err
@@ -120,6 +139,21 @@ func upAction(cmd *cobra.Command, services []string) error {
 	if forceRecreate && noRecreate {
 		return errors.New("flag --force-recreate and --no-recreate cannot be specified together")
 	}
+	dependencyTimeout, err := cmd.Flags().GetDuration("dependency-timeout")
+	if err != nil {
+		return err
+	}
+	noDeps, err := cmd.Flags().GetBool("no-deps")
+	if err != nil {
+		return err
+	}
+	attachDependencies, err := cmd.Flags().GetBool("attach-dependencies")
+	if err != nil {
+		return err
+	}
+	if attachDependencies && detach {
+		return errors.New("flag --attach-dependencies and -d cannot be specified together")
+	}
 	scale := make(map[string]int)
 	for _, s := range scaleSlice {
 		parts := strings.Split(s, "=")
@@ -162,6 +196,11 @@ func upAction(cmd *cobra.Command, services []string) error {
 		Pull:                 pull,
 		ForceRecreate:        forceRecreate,
 		NoRecreate:           noRecreate,
+		DependencyTimeout:    dependencyTimeout,
+		Wait:                 wait,
+		WaitTimeout:          waitTimeout,
+		NoDeps:               noDeps,
+		AttachDependencies:   attachDependencies,
 	}
 	return c.Up(ctx, uo, services)
 }
@@ -17,9 +17,11 @@
 package compose
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -292,6 +294,48 @@ services:
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps").AssertOutContains(serviceparser.DefaultContainerName(projectName, "test", "2"))
 }
 
+func TestComposeUpScaleDown(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  test:
+    image: %s
+    command: "sleep infinity"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--scale", "test=3").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps").AssertOutContains(serviceparser.DefaultContainerName(projectName, "test", "3"))
+
+	// the replicas share the service name as hostname, so the service name resolves to all of them
+	hostsCmd := base.ComposeCmd("-f", comp.YAMLFullPath(), "exec", "test", "cat", "/etc/hosts")
+	hostsCmd.AssertOutWithFunc(func(stdout string) error {
+		n := 0
+		for _, line := range strings.Split(stdout, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) > 1 && slices.Contains(fields[1:], "test") {
+				n++
+			}
+		}
+		if n != 3 {
+			return fmt.Errorf("expected 3 addresses for the service name, got %d: %q", n, stdout)
+		}
+		return nil
+	})
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--scale", "test=1").AssertOK()
+	psCmd := base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "-a")
+	psCmd.AssertOutContains(serviceparser.DefaultContainerName(projectName, "test", "1"))
+	psCmd.AssertOutNotContains(serviceparser.DefaultContainerName(projectName, "test", "2"))
+	psCmd.AssertOutNotContains(serviceparser.DefaultContainerName(projectName, "test", "3"))
+}
+
 func TestComposeIPAMConfig(t *testing.T) {
 	base := testutil.NewBase(t)
 
@@ -417,6 +461,116 @@ services:
 	assert.Equal(base.T, out1, out2)
 }
 
+func TestComposeUpRecreateDiverged(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	const dockerComposeYAMLTemplate = `
+services:
+  foo:
+    image: %s
+    command: "sleep infinity"
+    environment:
+      FOO: %s
+`
+	comp := testutil.NewComposeDir(t, fmt.Sprintf(dockerComposeYAMLTemplate, testutil.CommonImage, "1"))
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	fooName := serviceparser.DefaultContainerName(projectName, "foo", "1")
+	containerID := func() string {
+		res := base.Cmd("inspect", fooName, "--format", "{{.Id}}").Run()
+		assert.Assert(t, res.ExitCode == 0, res.Stdout()+res.Stderr())
+		return strings.TrimSpace(res.Stdout())
+	}
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+	id1 := containerID()
+
+	// unchanged configuration: the container is kept
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	assert.Equal(t, id1, containerID())
+
+	// --force-recreate: the container is recreated even if the configuration is unchanged
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--force-recreate").AssertOK()
+	id2 := containerID()
+	assert.Assert(t, id1 != id2)
+
+	// changed configuration with --no-recreate: the container is kept
+	comp.WriteFile("docker-compose.yaml", fmt.Sprintf(dockerComposeYAMLTemplate, testutil.CommonImage, "2"))
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--no-recreate").AssertOK()
+	assert.Equal(t, id2, containerID())
+
+	// changed configuration: the container is recreated
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	assert.Assert(t, id2 != containerID())
+	base.Cmd("exec", fooName, "env").AssertOutContains("FOO=2")
+}
+
+func TestComposeUpNoDeps(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  foo:
+    image: %s
+    command: "sleep infinity"
+  bar:
+    image: %s
+    command: "sleep infinity"
+    depends_on:
+      - foo
+`, testutil.CommonImage, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--no-deps", "bar").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	psCmd := base.Cmd("ps", "-a", "--format={{.Names}}")
+	psCmd.AssertOutContains(serviceparser.DefaultContainerName(projectName, "bar", "1"))
+	psCmd.AssertOutNotContains(serviceparser.DefaultContainerName(projectName, "foo", "1"))
+}
+
+func TestComposeUpAttachDependencies(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  foo:
+    image: %s
+    command: "echo foo-output"
+  bar:
+    image: %s
+    command: "sh -c 'sleep 1; echo bar-output'"
+    depends_on:
+      - foo
+`, testutil.CommonImage, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	// the logs of the dependencies are not attached by default
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "bar").AssertOutWithFunc(func(stdout string) error {
+		if !strings.Contains(stdout, "bar-output") {
+			return fmt.Errorf("expected the logs of bar, got %q", stdout)
+		}
+		if strings.Contains(stdout, "foo-output") {
+			return fmt.Errorf("expected no logs of foo, got %q", stdout)
+		}
+		return nil
+	})
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--attach-dependencies", "bar").AssertOutContainsAll("foo-output", "bar-output")
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--attach-dependencies", "bar").AssertFail()
+}
+
 func TestComposeUpWithExternalNetwork(t *testing.T) {
 	testCase := nerdtest.Setup()
 
@@ -476,6 +630,189 @@ networks:
 	testCase.Run(t)
 }
 
+func TestComposeUpWithExternalNetworkName(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    networks:
+      - foo
+networks:
+  foo:
+    external: true
+    name: %s
+`, testutil.CommonImage, data.Identifier("network"))
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+		helpers.Anyhow("network", "rm", data.Identifier("network"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "up fails when the external network does not exist",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Temp().Path("compose.yaml"), "up", "-d")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("declared as external, but could not be found")}, nil),
+		},
+		{
+			Description: "up connects the service to the external network",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "create", data.Identifier("network"))
+				helpers.Ensure("compose", "-f", data.Temp().Path("compose.yaml"), "up", "-d")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("ps", "--filter", "network="+data.Identifier("network"), "--format", "{{.Names}}")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("svc0")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeUpNetworkDriverOpts(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    networks:
+      - foo
+networks:
+  foo:
+    driver_opts:
+      com.docker.network.driver.mtu: 1400
+`, testutil.CommonImage)
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		helpers.Ensure("compose", "-f", data.Temp().Path("compose.yaml"), "up", "-d")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("compose", "-f", data.Temp().Path("compose.yaml"), "exec", "-i=false", "--no-TTY", "svc0", "ifconfig", "eth0")
+	}
+
+	testCase.Expected = test.Expects(0, nil, expect.Contains("MTU:1400"))
+
+	testCase.Run(t)
+}
+
+func TestComposeUpWithExternalVolume(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    volumes:
+      - foo:/data
+volumes:
+  foo:
+    external: true
+    name: %s
+`, testutil.CommonImage, data.Identifier("volume"))
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("volume"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "up fails when the external volume does not exist",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "-f", data.Temp().Path("compose.yaml"), "up", "-d")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("declared as external, but could not be found")}, nil),
+		},
+		{
+			Description: "up mounts the external volume and down -v keeps it",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "create", data.Identifier("volume"))
+				helpers.Ensure("compose", "-f", data.Temp().Path("compose.yaml"), "up", "-d")
+				helpers.Ensure("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "ls", "--quiet")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(data.Identifier("volume")),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeUpVolumeDriverOpts(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    volumes:
+      - foo:/data
+volumes:
+  foo:
+    name: %s
+    driver: local
+    driver_opts:
+      type: nfs
+      o: addr=10.0.0.5,rw
+      device: ":/export/data"
+    labels:
+      com.example.description: "shared data"
+`, testutil.CommonImage, data.Identifier("volume"))
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		helpers.Ensure("compose", "-f", data.Temp().Path("compose.yaml"), "create")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("volume"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("volume", "inspect", "--format", "{{json .Options}} {{json .Labels}}", data.Identifier("volume"))
+	}
+
+	testCase.Expected = test.Expects(0, nil, expect.Contains(
+		`"device":":/export/data"`,
+		`"o":"addr=10.0.0.5,rw"`,
+		`"type":"nfs"`,
+		`"com.example.description":"shared data"`,
+	))
+
+	testCase.Run(t)
+}
+
 func TestComposeUpWithBypass4netns(t *testing.T) {
 	// docker does not support bypass4netns mode
 	testutil.DockerIncompatible(t)
@@ -741,3 +1078,52 @@ services:
 
 	testCase.Run(t)
 }
+
+func TestComposeUpDependsOnServiceHealthy(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  db:
+    image: %[1]s
+    command: "sleep infinity"
+  app:
+    image: %[1]s
+    command: "sleep infinity"
+    depends_on:
+      db:
+        condition: service_healthy
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	// db has no healthcheck, so app cannot be started
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--dependency-timeout=10s").AssertFail()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "app").AssertOutNotContains("app")
+}
+
+func TestComposeUpWait(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %[1]s
+    command: "sleep infinity"
+  svc1:
+    image: %[1]s
+    command: "false"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--wait", "--wait-timeout=30s", "svc0").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "svc0").AssertOutContainsAny("Up", "running")
+
+	// svc1 exits immediately, so it never converges
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--wait", "--wait-timeout=30s", "svc1").AssertFail()
+}
@@ -36,6 +36,7 @@ func Command() *cobra.Command {
 		RunCommand(),
 		UpdateCommand(),
 		ExecCommand(),
+		DebugCommand(),
 		listCommand(),
 		inspectCommand(),
 		LogsCommand(),
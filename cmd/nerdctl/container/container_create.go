@@ -18,7 +18,10 @@ package container
 
 import (
 	"fmt"
+	"maps"
 	"runtime"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	cdiparser "tags.cncf.io/container-device-interface/pkg/parser"
@@ -32,14 +35,14 @@ import (
 
 func CreateCommand() *cobra.Command {
 	shortHelp := "Create a new container. Optionally specify \"ipfs://\" or \"ipns://\" scheme to pull image from IPFS."
-	longHelp := shortHelp
+	longHelp := shortHelp + "\nNOTE: `nerdctl create` is not supported for containerd on an SSH host (`--address ssh://...`), use a context with `nerdctl context create --ssh` instead."
 	switch runtime.GOOS {
 	case "windows":
 		longHelp += "\n"
 		longHelp += "WARNING: `nerdctl create` is experimental on Windows and currently broken (https://github.com/containerd/nerdctl/issues/28)"
 	case "freebsd":
 		longHelp += "\n"
-		longHelp += "WARNING: `nerdctl create` is experimental on FreeBSD and currently requires `--net=none` (https://github.com/containerd/nerdctl/blob/main/docs/freebsd.md)"
+		longHelp += "WARNING: `nerdctl create` is experimental on FreeBSD (https://github.com/containerd/nerdctl/blob/main/docs/freebsd.md)"
 	}
 	var cmd = &cobra.Command{
 		Use:               "create [flags] IMAGE [COMMAND] [ARG...]",
@@ -367,6 +370,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.Secret, err = cmd.Flags().GetStringArray("secret")
+	if err != nil {
+		return opt, err
+	}
 	// #endregion
 
 	// #region for rootfs flags
@@ -374,6 +381,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.ReadOnlyTmpfs, err = cmd.Flags().GetBool("read-only-tmpfs")
+	if err != nil {
+		return opt, err
+	}
 	opt.Rootfs, err = cmd.Flags().GetBool("rootfs")
 	if err != nil {
 		return opt, err
@@ -417,6 +428,15 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.Bypass4netns, err = cmd.Flags().GetBool("bypass4netns")
+	if err != nil {
+		return opt, err
+	}
+	opt.Bypass4netnsChanged = cmd.Flags().Changed("bypass4netns")
+	opt.RootlessPortDriver, err = cmd.Flags().GetString("rootless-port-driver")
+	if err != nil {
+		return opt, err
+	}
 	opt.CidFile, err = cmd.Flags().GetString("cidfile")
 	if err != nil {
 		return opt, err
@@ -440,6 +460,21 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	// the [logging] defaults of nerdctl.toml, as the log-driver and log-opts of the Docker daemon
+	globalLogDriver := opt.GOptions.Logging.Driver
+	if globalLogDriver == "" {
+		globalLogDriver = "json-file"
+	} else if !cmd.Flags().Changed("log-driver") {
+		opt.LogDriver = globalLogDriver
+	}
+	if opt.LogDriver == globalLogDriver && len(opt.GOptions.Logging.Opts) > 0 {
+		// the default options are overridden by --log-opt
+		var logOpt []string
+		for _, k := range slices.Sorted(maps.Keys(opt.GOptions.Logging.Opts)) {
+			logOpt = append(logOpt, k+"="+opt.GOptions.Logging.Opts[k])
+		}
+		opt.LogOpt = append(logOpt, opt.LogOpt...)
+	}
 	// #endregion
 
 	// #region for shared memory flags
@@ -479,6 +514,13 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	// the [verify] defaults of nerdctl.toml, so that the images are verified even when they are present locally
+	if !cmd.Flags().Changed("verify") && opt.GOptions.Verify.Provider != "" {
+		imageVerifyOpt.Provider = opt.GOptions.Verify.Provider
+	}
+	if !cmd.Flags().Changed("cosign-key") && opt.GOptions.Verify.CosignKey != "" {
+		imageVerifyOpt.CosignKey = opt.GOptions.Verify.CosignKey
+	}
 	quiet, err := cmd.Flags().GetBool("quiet")
 	if err != nil {
 		return opt, err
@@ -506,6 +548,8 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 
 	if userns == "host" {
 		opt.UserNS = ""
+	} else if userns == "auto" || strings.HasPrefix(userns, "auto:") {
+		opt.UserNS = userns
 	} else if userns != "" {
 		return opt, fmt.Errorf("invalid user mode")
 	}
@@ -511,3 +511,60 @@ func delUser(username string, helpers test.Helpers) {
 func delGroup(groupname string, helpers test.Helpers) {
 	helpers.Custom("groupdel", groupname).Run(&test.Expected{ExitCode: expect.ExitCodeNoCheck})
 }
+
+func TestCreateWithGlobalLogConfig(t *testing.T) {
+	var configContent test.ConfigValue = `[logging]
+driver = "json-file"
+
+[logging.opts]
+max-size = "5m"
+max-file = "2"`
+
+	nerdtest.Setup()
+
+	inspectLogConfig := func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("container", "inspect", "--format", "{{.HostConfig.LogConfig.Driver}} {{json .HostConfig.LogConfig.Opts}}", data.Identifier())
+	}
+	cleanup := func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase := &test.Case{
+		Config: test.WithConfig(nerdtest.NerdctlToml, configContent),
+		// NERDCTL_TOML not supported in Docker
+		Require: require.Not(nerdtest.Docker),
+		SubTests: []*test.Case{
+			{
+				Description: "Global logging options are used when --log-opt is not provided",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("create", "--name", data.Identifier(), testutil.CommonImage)
+				},
+				Cleanup: cleanup,
+				Command: inspectLogConfig,
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.Contains(`json-file {"max-file":"2","max-size":"5m"}`)),
+			},
+			{
+				Description: "--log-opt overrides the global logging options per key",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("create", "--log-opt", "max-size=1m", "--name", data.Identifier(), testutil.CommonImage)
+				},
+				Cleanup: cleanup,
+				Command: inspectLogConfig,
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.Contains(`json-file {"max-file":"2","max-size":"1m"}`)),
+			},
+			{
+				Description: "Global logging options are not applied to another --log-driver",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("create", "--log-driver", "none", "--name", data.Identifier(), testutil.CommonImage)
+				},
+				Cleanup: cleanup,
+				Command: inspectLogConfig,
+				Expected: test.Expects(expect.ExitCodeSuccess, nil,
+					expect.DoesNotContain("max-size")),
+			},
+		},
+	}
+	testCase.Run(t)
+}
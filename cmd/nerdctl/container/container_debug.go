@@ -0,0 +1,114 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+)
+
+func DebugCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "debug [flags] CONTAINER [COMMAND] [ARG...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Run a temporary container with debugging tools in the namespaces of a running container",
+		Long: `Run a temporary container with debugging tools in the namespaces of a running container.
+
+The debug container joins the PID, network, and IPC namespaces of the target container, which is not modified.
+The root filesystem of the target container is visible at /proc/1/root in the debug container.
+This is useful for debugging the containers of the images that have no shell (e.g., distroless images).
+
+The default command is "sh".`,
+		Example: `  nerdctl debug -it mycontainer
+  nerdctl debug --image nicolaka/netshoot -it mycontainer
+  nerdctl debug mycontainer -- cat /proc/1/root/etc/os-release`,
+		RunE:              debugAction,
+		ValidArgsFunction: execShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().SetInterspersed(false)
+
+	cmd.Flags().String("image", container.DefaultDebugImage, "Image of the debug container, providing the debugging tools")
+	cmd.Flags().String("pull", "missing", `Pull the image before running ("always"|"missing"|"never")`)
+	cmd.RegisterFlagCompletionFunc("pull", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"always", "missing", "never"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolP("tty", "t", false, "Allocate a pseudo-TTY")
+	cmd.Flags().BoolP("interactive", "i", false, "Keep STDIN open even if not attached")
+	return cmd
+}
+
+func debugOptions(cmd *cobra.Command) (types.ContainerDebugOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ContainerDebugOptions{}, err
+	}
+	image, err := cmd.Flags().GetString("image")
+	if err != nil {
+		return types.ContainerDebugOptions{}, err
+	}
+	pull, err := cmd.Flags().GetString("pull")
+	if err != nil {
+		return types.ContainerDebugOptions{}, err
+	}
+	isTerminal, err := cmd.Flags().GetBool("tty")
+	if err != nil {
+		return types.ContainerDebugOptions{}, err
+	}
+	isInteractive, err := cmd.Flags().GetBool("interactive")
+	if err != nil {
+		return types.ContainerDebugOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.ContainerDebugOptions{
+		Stdin:       cmd.InOrStdin(),
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		Image:       image,
+		Pull:        pull,
+		Interactive: isInteractive,
+		TTY:         isTerminal,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}, nil
+}
+
+func debugAction(cmd *cobra.Command, args []string) error {
+	options, err := debugOptions(cmd)
+	if err != nil {
+		return err
+	}
+	// simulate the behavior of double dash
+	command := args[1:]
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return container.Debug(ctx, client, args[0], command, options)
+}
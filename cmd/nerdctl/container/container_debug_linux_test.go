@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestDebug(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// `docker debug` is only available in Docker Desktop
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-euc", "echo debug-marker > /marker; exec sleep "+nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		helpers.Ensure("create", "--name", data.Identifier("created"), testutil.CommonImage, "true")
+		data.Labels().Set("container_name", data.Identifier())
+		data.Labels().Set("created_name", data.Identifier("created"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier(), data.Identifier("created"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "sees the processes of the target",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("debug", "--image", testutil.CommonImage, data.Labels().Get("container_name"), "--", "ps")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("sleep")),
+		},
+		{
+			Description: "sees the root filesystem of the target",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("debug", "--image", testutil.CommonImage, data.Labels().Get("container_name"), "cat", "/proc/1/root/marker")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("debug-marker\n")),
+		},
+		{
+			Description: "does not modify the target",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("debug", "--image", testutil.CommonImage, data.Labels().Get("container_name"), "touch", "/debug-only")
+				return helpers.Command("exec", data.Labels().Get("container_name"), "ls", "/")
+			},
+			Expected: test.Expects(0, nil, expect.DoesNotContain("debug-only")),
+		},
+		{
+			Description: "propagates the exit code",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("debug", "--image", testutil.CommonImage, data.Labels().Get("container_name"), "sh", "-c", "exit 42")
+			},
+			Expected: test.Expects(42, nil, nil),
+		},
+		{
+			Description: "fails for a container that is not running",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("debug", "--image", testutil.CommonImage, data.Labels().Get("created_name"), "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("is not running")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -19,6 +19,9 @@ package container
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -26,6 +29,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -42,10 +46,47 @@ func HealthCheckCommand() *cobra.Command {
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 	}
+	healthCheckCommand.AddCommand(healthCheckServeCommand())
 
 	return healthCheckCommand
 }
 
+func healthCheckServeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "serve [flags]",
+		Short: "Run the health checks of the running containers at their intervals",
+		Long: `Run the health checks of the running containers at their intervals, until interrupted.
+
+The health states of the containers are updated, and "health_status" events are emitted when they change.
+The containers whose health checks are run by the transient systemd timers are skipped.
+This command can be run as a systemd service, e.g., in rootless mode, or when "disable_hc_systemd" is set.`,
+		Args:          cobra.NoArgs,
+		RunE:          healthCheckServeAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	return cmd
+}
+
+func healthCheckServeAction(cmd *cobra.Command, _ []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client, ctx, cancel, err := clientutil.NewClient(ctx, globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return container.HealthCheckServe(ctx, client, types.ContainerHealthCheckServeOptions{
+		GOptions: globalOptions,
+	})
+}
+
 func healthCheckAction(cmd *cobra.Command, args []string) error {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -1155,3 +1156,55 @@ func TestHealthCheck_SystemdIntegration_Advanced(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestHealthCheckServe(t *testing.T) {
+	testCase := nerdtest.Setup()
+	// The healthchecks are run by the transient systemd timers in rootful mode
+	testCase.Require = require.All(require.Not(nerdtest.Docker), nerdtest.Rootless)
+
+	var server test.TestableCommand
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(),
+			"--health-cmd", "echo healthy",
+			"--health-interval", "1s",
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		server = helpers.Command("container", "healthcheck", "serve")
+		server.WithTimeout(30 * time.Second)
+		server.Background()
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		if server != nil {
+			server.Signal(os.Kill)
+		}
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("container", "inspect", data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				var status string
+				for range 10 {
+					h := nerdtest.InspectContainer(helpers, data.Identifier()).State.Health
+					if h != nil && h.Status == healthcheck.Healthy {
+						assert.Assert(t, len(h.Log) > 0, "expected at least one health check log entry")
+						return
+					}
+					if h != nil {
+						status = h.Status
+					}
+					time.Sleep(time.Second)
+				}
+				assert.Assert(t, false, fmt.Sprintf("container did not become healthy, status: %q", status))
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -18,11 +18,16 @@ package container
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"syscall"
 	"text/tabwriter"
 	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -30,6 +35,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 )
 
@@ -55,6 +61,7 @@ func PsCommand() *cobra.Command {
 		return []string{"json", "table", "wide"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().StringSliceP("filter", "f", nil, "Filter matches containers based on given conditions. When specifying the condition 'status', it filters all containers")
+	cmd.Flags().Bool("watch", false, "Live-update the list as containers are created, started, stopped, and removed")
 	return cmd
 }
 
@@ -100,6 +107,11 @@ func processOptions(cmd *cobra.Command) (types.ContainerListOptions, FormattingA
 		return types.ContainerListOptions{}, FormattingAndPrintingOptions{}, err
 	}
 
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return types.ContainerListOptions{}, FormattingAndPrintingOptions{}, err
+	}
+
 	size := false
 	if !quiet {
 		size, err = cmd.Flags().GetBool("size")
@@ -120,6 +132,7 @@ func processOptions(cmd *cobra.Command) (types.ContainerListOptions, FormattingA
 			Quiet:  quiet,
 			Format: format,
 			Size:   size,
+			Watch:  watch,
 		}, nil
 }
 
@@ -129,12 +142,32 @@ func psAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), clOpts.GOptions.Namespace, clOpts.GOptions.Address)
+	ctx := cmd.Context()
+	if fpOpts.Watch {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+	client, ctx, cancel, err := clientutil.NewClient(ctx, clOpts.GOptions.Namespace, clOpts.GOptions.Address)
 	if err != nil {
 		return err
 	}
 	defer cancel()
 
+	if fpOpts.Watch {
+		stdout := fpOpts.Stdout
+		return container.Watch(ctx, client, clOpts, psWatchInterval, func(containers []container.ListItem) error {
+			// the whole frame is rendered first, so that the screen does not flicker
+			var frame bytes.Buffer
+			frameOpts := fpOpts
+			frameOpts.Stdout = &frame
+			if err := formatAndPrintContainerInfo(containers, frameOpts); err != nil {
+				return err
+			}
+			return consoleutil.Redraw(stdout, frame.Bytes(), false)
+		})
+	}
+
 	containers, err := container.List(ctx, client, clOpts)
 	if err != nil {
 		return err
@@ -152,6 +185,16 @@ type FormattingAndPrintingOptions struct {
 	Format string
 	// Display total file sizes.
 	Size bool
+	// Live-update the list until interrupted.
+	Watch bool
+}
+
+// psWatchInterval is the interval of refreshing the CREATED and STATUS columns of `nerdctl ps --watch`.
+const psWatchInterval = 2 * time.Second
+
+// psTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var psTableHeaders = map[string]string{
+	"ID": "CONTAINER ID",
 }
 
 func formatAndPrintContainerInfo(containers []container.ListItem, options FormattingAndPrintingOptions) error {
@@ -183,7 +226,7 @@ func formatAndPrintContainerInfo(containers []container.ListItem, options Format
 			return errors.New("format and quiet must not be specified together")
 		}
 		var err error
-		tmpl, err = formatter.ParseTemplate(options.Format)
+		w, tmpl, err = formatter.ParseListTemplate(w, options.Format, psTableHeaders, options.Quiet)
 		if err != nil {
 			return err
 		}
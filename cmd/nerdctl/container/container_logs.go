@@ -30,8 +30,11 @@ import (
 )
 
 func LogsCommand() *cobra.Command {
-	const shortUsage = "Fetch the logs of a container. Expected to be used with 'nerdctl run -d'."
-	const longUsage = `Fetch the logs of a container.
+	const shortUsage = "Fetch the logs of one or more containers. Expected to be used with 'nerdctl run -d'."
+	const longUsage = `Fetch the logs of one or more containers.
+
+When multiple containers are specified (or matched by '--filter'), their logs are interleaved,
+and each line is prefixed with the container name.
 
 The following containers are supported:
 - Containers created with 'nerdctl run -d'. The log is currently empty for containers created without '-d'.
@@ -39,8 +42,8 @@ The following containers are supported:
 - Containers created with Kubernetes (EXPERIMENTAL).
 `
 	var cmd = &cobra.Command{
-		Use:               "logs [flags] CONTAINER",
-		Args:              helpers.IsExactArgs(1),
+		Use:               "logs [flags] CONTAINER [CONTAINER...]",
+		Args:              logsArgs,
 		Short:             shortUsage,
 		Long:              longUsage,
 		RunE:              logsAction,
@@ -49,14 +52,29 @@ The following containers are supported:
 		SilenceErrors:     true,
 	}
 	cmd.Flags().BoolP("follow", "f", false, "Follow log output")
+	cmd.Flags().Int("follow-retries", -1, "Number of times to keep following the logs after the container or containerd restarts (-1 for unlimited)")
 	cmd.Flags().BoolP("timestamps", "t", false, "Show timestamps")
 	cmd.Flags().StringP("tail", "n", "all", "Number of lines to show from the end of the logs")
 	cmd.Flags().String("since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	cmd.Flags().String("until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37Z) or relative (e.g. 42m for 42 minutes)")
 	cmd.Flags().Bool("details", false, "Show extra details provided to logs")
+	cmd.Flags().StringSlice("filter", nil, "Show logs of the containers matching the filter (e.g. 'label=com.example.app=web')")
+	cmd.Flags().Bool("no-color", false, "Produce monochrome output of the log prefixes")
+	cmd.Flags().Bool("no-log-prefix", false, "Don't print the container name prefix when showing the logs of multiple containers")
 	return cmd
 }
 
+func logsArgs(cmd *cobra.Command, args []string) error {
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return err
+	}
+	if len(filters) > 0 {
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
 func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
@@ -66,6 +84,10 @@ func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	if err != nil {
 		return types.ContainerLogsOptions{}, err
 	}
+	followRetries, err := cmd.Flags().GetInt("follow-retries")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
 	tailArg, err := cmd.Flags().GetString("tail")
 	if err != nil {
 		return types.ContainerLogsOptions{}, err
@@ -93,16 +115,32 @@ func logsOptions(cmd *cobra.Command) (types.ContainerLogsOptions, error) {
 	if err != nil {
 		return types.ContainerLogsOptions{}, err
 	}
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	noColor, err := cmd.Flags().GetBool("no-color")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
+	noLogPrefix, err := cmd.Flags().GetBool("no-log-prefix")
+	if err != nil {
+		return types.ContainerLogsOptions{}, err
+	}
 	return types.ContainerLogsOptions{
-		Stdout:     cmd.OutOrStdout(),
-		Stderr:     cmd.OutOrStderr(),
-		GOptions:   globalOptions,
-		Follow:     follow,
-		Timestamps: timestamps,
-		Tail:       tail,
-		Since:      since,
-		Until:      until,
-		Details:    details,
+		Stdout:        cmd.OutOrStdout(),
+		Stderr:        cmd.OutOrStderr(),
+		GOptions:      globalOptions,
+		Follow:        follow,
+		FollowRetries: followRetries,
+		Timestamps:    timestamps,
+		Tail:          tail,
+		Since:         since,
+		Until:         until,
+		Details:       details,
+		Filters:       filters,
+		NoColor:       noColor,
+		NoLogPrefix:   noLogPrefix,
 	}, nil
 }
 
@@ -118,7 +156,7 @@ func logsAction(cmd *cobra.Command, args []string) error {
 	}
 	defer cancel()
 
-	return container.Logs(ctx, client, args[0], options)
+	return container.Logs(ctx, client, args, options)
 }
 
 func logsShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
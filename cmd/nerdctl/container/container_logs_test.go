@@ -224,9 +224,47 @@ bar
 			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
 				return helpers.Command("logs", "--since", "60s", data.Labels().Get("cID"))
 			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals(expected)),
+		},
+		{
+			Description: "logs --until 2000-01-01",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--until", "2000-01-01", data.Labels().Get("cID"))
+			},
 			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.DoesNotContain("foo", "bar")),
 		},
+		{
+			Description: "logs --tail 1",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "1", data.Labels().Get("cID"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("bar\n")),
+		},
+		{
+			Description: "logs --timestamps",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--timestamps", data.Labels().Get("cID"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Match(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\S+ foo\n`))),
+		},
+		{
+			Description: "logs with a custom tag",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--network", "none", "--log-driver", "journald", "--log-opt", "tag={{.FullID}}",
+					"--name", data.Identifier("tag"), testutil.CommonImage, "echo", "baz")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier("tag"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Identifier("tag"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("baz\n")),
+		},
 	}
+
+	testCase.Run(t)
 }
 
 func TestLogsWithFailingContainer(t *testing.T) {
@@ -471,6 +509,51 @@ func TestLogsTailFollowRotate(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestLogsRotatedFiles(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(require.Windows)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--log-driver", "json-file",
+			"--log-opt", "max-size=10k", "--log-opt", "max-file=100", "--log-opt", "compress=true",
+			"--name", data.Identifier(), testutil.CommonImage, "seq", "1", "1000")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "logs",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				assert.Equal(t, len(lines), 1000)
+				assert.Equal(t, lines[0], "1")
+				assert.Equal(t, lines[999], "1000")
+			}),
+		},
+		{
+			Description: "logs --tail 500",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "500", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				assert.Equal(t, len(lines), 500)
+				assert.Equal(t, lines[0], "501")
+				assert.Equal(t, lines[499], "1000")
+			}),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestLogsNoneLoggerHasNoLogURI(t *testing.T) {
 	testCase := nerdtest.Setup()
 
@@ -615,3 +698,118 @@ func TestLogsWithStartContainer(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestLogsMultipleContainers(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Not(require.Windows),
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--label", "test-logs="+data.Identifier(), "--name", data.Identifier("a"),
+			testutil.CommonImage, "echo", "foo-a")
+		helpers.Ensure("run", "--label", "test-logs="+data.Identifier(), "--name", data.Identifier("b"),
+			testutil.CommonImage, "echo", "foo-b")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("a"), data.Identifier("b"))
+	}
+
+	expectPrefixed := func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				for _, suffix := range []string{"a", "b"} {
+					re := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(data.Identifier(suffix)) + ` +\|foo-` + suffix + `$`)
+					assert.Assert(t, re.MatchString(stdout), "expected prefixed line for %q in %q", suffix, stdout)
+				}
+			},
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "by name",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-color", data.Identifier("a"), data.Identifier("b"))
+			},
+			Expected: expectPrefixed,
+		},
+		{
+			Description: "by label filter",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-color", "--filter", "label=test-logs="+data.Identifier())
+			},
+			Expected: expectPrefixed,
+		},
+		{
+			Description: "without prefix",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-log-prefix", data.Identifier("a"), data.Identifier("b"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Match(regexp.MustCompile(`(?m)^foo-a$`)),
+						expect.Match(regexp.MustCompile(`(?m)^foo-b$`)),
+					),
+				}
+			},
+		},
+		{
+			Description: "with timestamps",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--no-color", "-t", data.Identifier("a"), data.Identifier("b"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Match(regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(data.Identifier("a")) + ` +\|\d{4}-\d{2}-\d{2}T\S+ foo-a$`)),
+				}
+			},
+		},
+		{
+			Description: "no container matches the filter",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--filter", "label=test-logs="+data.Identifier("nonexistent"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestLogsFollowRestartedContainer(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		require.Not(require.Windows),
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--restart=always", "--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-c", "echo foo; sleep 2; exit 1")
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "follow across one restart",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("logs", "-f", "--follow-retries=1", data.Identifier())
+				cmd.WithTimeout(60 * time.Second)
+				return cmd
+			},
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				assert.Assert(t, strings.Count(stdout, "foo") >= 2, "expected the logs of the restarted container, got %q", stdout)
+			}),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -19,12 +19,16 @@ package container
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
 
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
@@ -121,3 +125,41 @@ func TestContainerRmIptables(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestContainerRmAnonymousVolumes(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "rm -v keeps the anonymous volumes used by other containers",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier("source"), "-v", "/data", testutil.CommonImage, "sleep", nerdtest.Infinity)
+				helpers.Ensure("run", "-d", "--name", data.Identifier("user"), "--volumes-from", data.Identifier("source"), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				vol := strings.TrimSpace(helpers.Capture("inspect", "--format", "{{range .Mounts}}{{.Name}}{{end}}", data.Identifier("source")))
+				data.Labels().Set("vol", vol)
+				helpers.Ensure("rm", "-f", "-v", data.Identifier("source"))
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier("source"))
+				helpers.Anyhow("rm", "-f", data.Identifier("user"))
+				if vol := data.Labels().Get("vol"); vol != "" {
+					helpers.Anyhow("volume", "rm", "-f", vol)
+				}
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "ls", "--quiet", "--filter", "dangling=false")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						assert.Assert(t, strings.Contains(stdout, data.Labels().Get("vol")))
+						helpers.Ensure("rm", "-f", "-v", data.Identifier("user"))
+						helpers.Fail("volume", "inspect", data.Labels().Get("vol"))
+					},
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -52,14 +52,14 @@ const (
 
 func RunCommand() *cobra.Command {
 	shortHelp := "Run a command in a new container. Optionally specify \"ipfs://\" or \"ipns://\" scheme to pull image from IPFS."
-	longHelp := shortHelp
+	longHelp := shortHelp + "\nNOTE: `nerdctl run` is not supported for containerd on an SSH host (`--address ssh://...`), use a context with `nerdctl context create --ssh` instead."
 	switch runtime.GOOS {
 	case "windows":
 		longHelp += "\n"
 		longHelp += "WARNING: `nerdctl run` is experimental on Windows and currently broken (https://github.com/containerd/nerdctl/issues/28)"
 	case "freebsd":
 		longHelp += "\n"
-		longHelp += "WARNING: `nerdctl run` is experimental on FreeBSD and currently requires `--net=none` (https://github.com/containerd/nerdctl/blob/main/docs/freebsd.md)"
+		longHelp += "WARNING: `nerdctl run` is experimental on FreeBSD (https://github.com/containerd/nerdctl/blob/main/docs/freebsd.md)"
 	}
 	var cmd = &cobra.Command{
 		Use:               "run [flags] IMAGE [COMMAND] [ARG...]",
@@ -131,10 +131,16 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("dns-option", nil, "Set DNS options")
 	// publish is defined as StringSlice, not StringArray, to allow specifying "--publish=80:80,443:443" (compatible with Podman)
 	cmd.Flags().StringSliceP("publish", "p", nil, "Publish a container's port(s) to the host")
+	cmd.Flags().Bool("bypass4netns", false, "Accelerate the networking with bypass4netns in rootless mode (starts bypass4netnsd if not running)")
+	cmd.Flags().String("rootless-port-driver", "", "Port driver of the published ports in rootless mode (rootlesskit|bypass4netns|builtin|slirp4netns|implicit|pasta)")
+	cmd.RegisterFlagCompletionFunc("rootless-port-driver", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"rootlesskit", "bypass4netns", "builtin", "slirp4netns", "implicit", "pasta"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().String("ip", "", "IPv4 address to assign to the container")
 	cmd.Flags().String("ip6", "", "IPv6 address to assign to the container")
 	cmd.Flags().StringP("hostname", "h", "", "Container host name")
 	cmd.Flags().String("domainname", "", "Container domain name")
+	cmd.Flags().StringSlice("network-alias", nil, "Add network-scoped alias for the container")
 	cmd.Flags().String("mac-address", "", "MAC address to assign to the container")
 	// #endregion
 
@@ -199,6 +205,7 @@ func setCreateFlags(cmd *cobra.Command) {
 			"apparmor=", "apparmor=" + defaults.AppArmorProfileName, "apparmor=unconfined",
 			"no-new-privileges",
 			"systempaths=unconfined",
+			"mask=", "unmask=", "unmask=ALL",
 			"privileged-without-host-devices"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	// cap-add and cap-drop are defined as StringSlice, not StringArray, to allow specifying "--cap-add=CAP_SYS_ADMIN,CAP_NET_ADMIN" (compatible with Podman)
@@ -224,16 +231,23 @@ func setCreateFlags(cmd *cobra.Command) {
 	// #region mount flags
 	// volume needs to be StringArray, not StringSlice, to prevent "/foo:/foo:ro,Z" from being split to {"/foo:/foo:ro", "Z"}
 	cmd.Flags().StringArrayP("volume", "v", nil, "Bind mount a volume")
+	cmd.RegisterFlagCompletionFunc("volume", completion.VolumeSpecs)
 	// tmpfs needs to be StringArray, not StringSlice, to prevent "/foo:size=64m,exec" from being split to {"/foo:size=64m", "exec"}
 	cmd.Flags().StringArray("tmpfs", nil, "Mount a tmpfs directory")
 	cmd.Flags().StringArray("mount", nil, "Attach a filesystem mount to the container")
 	// volumes-from needs to be StringArray, not StringSlice, to prevent "id1,id2" from being split to {"id1", "id2"} (compatible with Docker)
 	cmd.Flags().StringArray("volumes-from", nil, "Mount volumes from the specified container(s)")
+	cmd.RegisterFlagCompletionFunc("volumes-from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completion.ContainerNames(cmd, nil)
+	})
+	// secret needs to be StringArray, not StringSlice, as the options are comma-separated
+	cmd.Flags().StringArray("secret", nil, "Mount a secret into the container, e.g. 'source=dbpass,file=./dbpass.txt,target=/run/secrets/dbpass,mode=0400'")
 	// #endregion
 
 	// rootfs flags
 	cmd.Flags().Bool("read-only", false, "Mount the container's root filesystem as read only")
 	// rootfs flags (from Podman)
+	cmd.Flags().Bool("read-only-tmpfs", true, "Mount tmpfs on /run, /tmp, and /var/tmp when --read-only is specified")
 	cmd.Flags().Bool("rootfs", false, "The first argument is not an image but the rootfs to the exploded container")
 
 	// Health check flags
@@ -286,9 +300,9 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().String("pidfile", "", "file path to write the task's pid")
 
 	// #region verify flags
-	cmd.Flags().String("verify", "none", "Verify the image (none|cosign|notation)")
+	cmd.Flags().String("verify", "none", "Verify the image (none|cosign|notation|scan)")
 	cmd.RegisterFlagCompletionFunc("verify", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"none", "cosign", "notation"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"none", "cosign", "notation", "scan"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().String("cosign-key", "", "Path to the public key file, KMS, URI or Kubernetes Secret for --verify=cosign")
 	cmd.Flags().String("cosign-certificate-identity", "", "The identity expected in a valid Fulcio certificate for --verify=cosign. Valid values include email address, DNS names, IP addresses, and URIs. Either --cosign-certificate-identity or --cosign-certificate-identity-regexp must be set for keyless flows")
@@ -305,7 +319,7 @@ func setCreateFlags(cmd *cobra.Command) {
 		}
 		return []string{"default"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	cmd.Flags().String("userns", "", "Specify host to disable userns-remap")
+	cmd.Flags().String("userns", "", "Specify host to disable userns-remap, or auto[:size=<SIZE>] to allocate a dedicated range of IDs from the subordinate IDs of the user \"containers\"")
 
 }
 
@@ -473,6 +473,15 @@ func TestRunWithFluentdLogDriver(t *testing.T) {
 	logData := string(data)
 	assert.Equal(t, true, strings.Contains(logData, "test"))
 	assert.Equal(t, true, strings.Contains(logData, inspectedContainer.ID))
+
+	// the logs are also kept in the local cache
+	base.Cmd("logs", testContainerName).AssertOutContains("test")
+
+	noCacheContainerName := containerName + "nocache"
+	base.Cmd("run", "-d", "--log-driver", "fluentd", "--log-opt", "cache-disabled=true", "--name", noCacheContainerName,
+		testutil.CommonImage, "sh", "-c", "echo test").AssertOK()
+	defer base.Cmd("rm", "-f", noCacheContainerName).AssertOK()
+	base.Cmd("logs", noCacheContainerName).AssertFail()
 }
 
 func TestRunWithFluentdLogDriverWithLogOpt(t *testing.T) {
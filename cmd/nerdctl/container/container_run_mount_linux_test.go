@@ -28,6 +28,7 @@ import (
 
 	"github.com/containerd/containerd/v2/core/mount"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
 
@@ -258,6 +259,110 @@ RUN echo -n "rev0" > /mnt/file
 	base.Cmd("exec", containerName, "cat", "/mnt/file").AssertOutExactly("rev1")
 }
 
+func TestRunVolumeNoCopy(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("copy"), data.Identifier("nocopy"), data.Identifier("v-nocopy"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the image contents are copied into an empty volume by default",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Identifier("copy")+",dst=/etc/apk,volume-nocopy=false",
+					testutil.CommonImage, "ls", "/etc/apk")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("repositories")),
+		},
+		{
+			Description: "volume-nocopy",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Identifier("nocopy")+",dst=/etc/apk,volume-nocopy",
+					testutil.CommonImage, "ls", "/etc/apk")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("")),
+		},
+		{
+			Description: "-v with nocopy",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Identifier("v-nocopy")+":/etc/apk:nocopy",
+					testutil.CommonImage, "ls", "/etc/apk")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunVolumeChown(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("v"), data.Identifier("mount"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "-v with U",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--user", "1000:1000", "-v", data.Identifier("v")+":/mnt:U",
+					testutil.CommonImage, "sh", "-euxc", "touch /mnt/file && stat -c %u:%g /mnt /mnt/file")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("1000:1000\n1000:1000\n")),
+		},
+		{
+			Description: "--mount with chown",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--user", "1000:1000",
+					"--mount", "type=volume,src="+data.Identifier("mount")+",dst=/etc/apk,chown",
+					testutil.CommonImage, "stat", "-c", "%u:%g", "/etc/apk/repositories")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("1000:1000\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunMountOverlay(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save("upper", "lower1", "file")
+		data.Temp().Save("lower", "lower2", "file")
+		data.Temp().Save("lower", "lower2", "other")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the first lowerdir is the top layer",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=overlay,lowerdir="+data.Temp().Path("lower1")+":"+data.Temp().Path("lower2")+",target=/mnt",
+					testutil.CommonImage, "cat", "/mnt/file", "/mnt/other")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("upperlower")),
+		},
+		{
+			Description: "the overlay is read-only",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=overlay,lowerdir="+data.Temp().Path("lower1")+":"+data.Temp().Path("lower2")+",target=/mnt",
+					testutil.CommonImage, "touch", "/mnt/new")
+			},
+			Expected: test.Expects(1, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunTmpfs(t *testing.T) {
 	t.Parallel()
 	base := testutil.NewBase(t)
@@ -284,6 +389,8 @@ func TestRunTmpfs(t *testing.T) {
 	base.Cmd("run", "--rm", "--tmpfs", "/tmp:size=64m,exec", testutil.AlpineImage, "grep", "/tmp", "/proc/mounts").AssertOutWithFunc(f([]string{"rw", "nosuid", "nodev", "size=65536k"}, []string{"noexec"}))
 	// for https://github.com/containerd/nerdctl/issues/594
 	base.Cmd("run", "--rm", "--tmpfs", "/dev/shm:rw,exec,size=1g", testutil.AlpineImage, "grep", "/dev/shm", "/proc/mounts").AssertOutWithFunc(f([]string{"rw", "nosuid", "nodev", "size=1048576k"}, []string{"noexec"}))
+	base.Cmd("run", "--rm", "--tmpfs", "/run:rw,noexec,nosuid,size=64m,mode=1770,uid=1000,gid=1000", testutil.AlpineImage, "stat", "-c", "%a %u %g", "/run").AssertOutExactly("1770 1000 1000\n")
+	base.Cmd("run", "--rm", "--tmpfs", "/tmp:mode=17777", testutil.AlpineImage, "true").AssertFail()
 }
 
 func TestRunBindMountTmpfs(t *testing.T) {
@@ -305,6 +412,8 @@ func TestRunBindMountTmpfs(t *testing.T) {
 	}
 	base.Cmd("run", "--rm", "--mount", "type=tmpfs,target=/tmp", testutil.AlpineImage, "grep", "/tmp", "/proc/mounts").AssertOutWithFunc(f([]string{"rw", "nosuid", "nodev", "noexec"}))
 	base.Cmd("run", "--rm", "--mount", "type=tmpfs,target=/tmp,tmpfs-size=64m", testutil.AlpineImage, "grep", "/tmp", "/proc/mounts").AssertOutWithFunc(f([]string{"rw", "nosuid", "nodev", "size=65536k"}))
+	base.Cmd("run", "--rm", "--mount", "type=tmpfs,target=/tmp,tmpfs-mode=700", testutil.AlpineImage, "stat", "-c", "%a", "/tmp").AssertOutExactly("700\n")
+	base.Cmd("run", "--rm", "--mount", "type=tmpfs,source=/foo,target=/tmp", testutil.AlpineImage, "true").AssertFail()
 }
 
 func mountExistsWithOpt(mountPoint, mountOpt string) test.Comparator {
@@ -408,6 +517,52 @@ func TestRunBindMountBind(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestRunMountVolumeSubpath(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("run", "--rm", "-v", data.Identifier()+":/mnt", testutil.CommonImage,
+			"sh", "-euxc", "mkdir -p /mnt/app1 /mnt/app2 && echo app1 > /mnt/app1/file && echo app2 > /mnt/app2/file && ln -s / /mnt/escape")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "mount a subdirectory of the volume",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Identifier()+",dst=/var/lib/app,volume-subpath=app1",
+					testutil.CommonImage, "cat", "/var/lib/app/file")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("app1\n")),
+		},
+		{
+			Description: "symlinks do not escape the volume",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Identifier()+",dst=/var/lib/app,volume-subpath=escape/app2",
+					testutil.CommonImage, "cat", "/var/lib/app/file")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("app2\n")),
+		},
+		{
+			Description: "non existent subpath should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm",
+					"--mount", "type=volume,src="+data.Identifier()+",dst=/var/lib/app,volume-subpath=nonexistent",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunMountBindMode(t *testing.T) {
 	if rootlessutil.IsRootless() {
 		t.Skip("must be superuser to use mount")
@@ -762,3 +917,82 @@ func TestBindMountWhenHostFolderDoesNotExist(t *testing.T) {
 	_, err = os.Stat(hp)
 	assert.ErrorIs(t, err, os.ErrNotExist)
 }
+
+func TestRunSecret(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save("s3cr3t", "dbpass")
+		helpers.Ensure("run", "-d", "--name", data.Identifier(),
+			"--secret", "source=dbpass,file="+data.Temp().Path("dbpass")+",mode=0400",
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "content",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "cat", "/run/secrets/dbpass")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("s3cr3t")),
+		},
+		{
+			Description: "mode",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "stat", "-c", "%a", "/run/secrets/dbpass")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("400\n")),
+		},
+		{
+			Description: "not in inspect",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "inspect", "--format", "{{json .Mounts}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.DoesNotContain("/run/secrets/dbpass")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunReadOnlyTmpfs(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker does not support --read-only-tmpfs
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "tmpfs are mounted by default",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--read-only", testutil.CommonImage,
+					"sh", "-euc", "touch /run/foo /tmp/foo /var/tmp/foo; ! touch /foo 2>/dev/null")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "tmpfs are not mounted with --read-only-tmpfs=false",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--read-only", "--read-only-tmpfs=false", testutil.CommonImage, "touch", "/var/tmp/foo")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "the user mounts take precedence",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--read-only", "--tmpfs", "/tmp:size=1m", testutil.CommonImage,
+					"sh", "-euc", "grep -E '^tmpfs /tmp ' /proc/mounts")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("size=1024k")),
+		},
+	}
+
+	testCase.Run(t)
+}
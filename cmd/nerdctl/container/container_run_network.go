@@ -54,6 +54,12 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 		networkSet = true
 	}
 
+	if !networkSet && globalOpts.DefaultNetwork != "" {
+		// default_network of nerdctl.toml, possibly overridden for the namespace
+		netSlice = append(netSlice, globalOpts.DefaultNetwork)
+		networkSet = true
+	}
+
 	if !networkSet {
 		network, err := cmd.Flags().GetStringSlice("network")
 		if err != nil {
@@ -103,6 +109,13 @@ func loadNetworkFlags(cmd *cobra.Command, globalOpts types.GlobalCommandOptions)
 	}
 	netOpts.Domainname = domainname
 
+	// --network-alias=<alias> ...
+	networkAliases, err := cmd.Flags().GetStringSlice("network-alias")
+	if err != nil {
+		return netOpts, err
+	}
+	netOpts.NetworkAliases = strutil.DedupeStrSlice(networkAliases)
+
 	// --dns=<DNS host> ...
 	// Use command flags if set, otherwise use global config is set
 	var dnsSlice []string
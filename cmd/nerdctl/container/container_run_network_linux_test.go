@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -1135,3 +1136,149 @@ func TestReservePorts(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestRunRootlessPortDriver(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker does not support --rootless-port-driver
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "rootful",
+			Require:     nerdtest.Rootful,
+			Command:     test.Command("run", "--rm", "--rootless-port-driver=rootlesskit", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("only supported in rootless mode")}, nil),
+		},
+		{
+			Description: "rootlesskit",
+			Require:     nerdtest.Rootless,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--rootless-port-driver=rootlesskit", "-p", "127.0.0.1:0:80",
+					testutil.CommonImage, "true")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "unknown",
+			Require:     nerdtest.Rootless,
+			Command:     test.Command("run", "--rm", "--rootless-port-driver=unknown", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unknown rootless port driver")}, nil),
+		},
+		{
+			Description: "pasta without --network=pasta",
+			Require:     nerdtest.Rootless,
+			Command:     test.Command("run", "--rm", "--rootless-port-driver=pasta", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("requires --network=pasta")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunBypass4netns(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker does not support --bypass4netns
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "rootful",
+			Require:     nerdtest.Rootful,
+			Command:     test.Command("run", "--rm", "--bypass4netns", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("only supported in rootless mode")}, nil),
+		},
+		{
+			Description: "rootful with false",
+			Require:     nerdtest.Rootful,
+			Command:     test.Command("run", "--rm", "--bypass4netns=false", testutil.CommonImage, "true"),
+			Expected:    test.Expects(0, nil, nil),
+		},
+		{
+			Description: "conflicts with the annotation",
+			Require:     nerdtest.Rootless,
+			Command: test.Command("run", "--rm", "--bypass4netns=false", "--annotation", "nerdctl/bypass4netns=true",
+				testutil.CommonImage, "true"),
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("conflicts with annotation")}, nil),
+		},
+		{
+			Description: "status",
+			Require: require.All(
+				nerdtest.Rootless,
+				require.Binary("bypass4netnsd"),
+			),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("id", strings.TrimSpace(helpers.Capture("run", "-d", "--bypass4netns", "-p", "127.0.0.1:0:80",
+					testutil.CommonImage, "sleep", nerdtest.Infinity)))
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				if id := data.Labels().Get("id"); id != "" {
+					helpers.Anyhow("rm", "-f", id)
+				}
+			},
+			Command: test.Command("system", "bypass4netns", "status", "--format", "{{.ID}}"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return test.Expects(0, nil, expect.Contains(data.Labels().Get("id")))(data, helpers)
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunNetworkPasta(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		// Docker does not support --network=pasta
+		require.Not(nerdtest.Docker),
+		require.Not(nerdtest.RootlessWithoutDetachNetNS),
+		require.Binary("pasta"),
+	)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "resolv.conf",
+			Command:     test.Command("run", "--rm", "--network=pasta", testutil.CommonImage, "cat", "/etc/resolv.conf"),
+			Expected:    test.Expects(0, nil, expect.Contains("nameserver 169.254.1.1")),
+		},
+		{
+			Description: "pasta options",
+			Command:     test.Command("run", "--rm", "--network=pasta:--ipv4-only,-a,10.0.2.100,-n,24", testutil.CommonImage, "ip", "addr"),
+			Expected:    test.Expects(0, nil, expect.Contains("inet 10.0.2.100/24")),
+		},
+		{
+			Description: "--ip is rejected",
+			Command:     test.Command("run", "--rm", "--network=pasta", "--ip", "10.0.2.100", testutil.CommonImage, "true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cannot be used with pasta networking")}, nil),
+		},
+		{
+			Description: "port forwarding",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), "--network=pasta", "-p", "127.0.0.1:60082:80", testutil.NginxAlpineImage)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						resp, err := nettestutil.HTTPGet("http://127.0.0.1:60082", 5, false)
+						assert.NilError(t, err)
+						respBody, err := io.ReadAll(resp.Body)
+						assert.NilError(t, err)
+						assert.Assert(t, strings.Contains(string(respBody), testutil.NginxAlpineIndexHTMLSnippet))
+					},
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
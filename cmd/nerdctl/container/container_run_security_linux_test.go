@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -26,9 +27,14 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
 	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
 
 func getCapEff(base *testutil.Base, args ...string) uint64 {
@@ -50,8 +56,10 @@ func getCapEff(base *testutil.Base, args ...string) uint64 {
 }
 
 const (
-	CapNetRaw  = 13
-	CapIPCLock = 14
+	CapKill           = 5
+	CapNetBindService = 10
+	CapNetRaw         = 13
+	CapIPCLock        = 14
 )
 
 func TestRunCap(t *testing.T) {
@@ -102,6 +110,14 @@ func TestRunCap(t *testing.T) {
 			args:   []string{"--cap-drop=all", "--cap-add=CAP_NET_RAW"},
 			capEff: 1 << CapNetRaw,
 		},
+		{
+			args:   []string{"--cap-drop=all", "--cap-add=net-basic"},
+			capEff: 1<<CapNetBindService | 1<<CapNetRaw,
+		},
+		{
+			args:   []string{"--cap-drop=all", "--cap-add=default"},
+			capEff: allCaps & defaultCaps,
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc // IMPORTANT
@@ -184,6 +200,7 @@ func TestRunApparmor(t *testing.T) {
 	base.Cmd("run", "--rm", "--security-opt", "apparmor="+defaultProfile, testutil.AlpineImage, "cat", attrCurrentPath).AssertOutExactly(attrCurrentEnforceExpected)
 	base.Cmd("run", "--rm", "--security-opt", "apparmor=unconfined", testutil.AlpineImage, "cat", attrCurrentPath).AssertOutContains("unconfined")
 	base.Cmd("run", "--rm", "--privileged", testutil.AlpineImage, "cat", attrCurrentPath).AssertOutContains("unconfined")
+	base.Cmd("run", "--rm", "--security-opt", "apparmor="+defaultProfile+"-nonexistent", testutil.AlpineImage, "true").AssertFail()
 }
 
 // TestRunSeccompCapSysPtrace tests https://github.com/containerd/nerdctl/issues/976
@@ -193,6 +210,98 @@ func TestRunSeccompCapSysPtrace(t *testing.T) {
 	// Docker/Moby 's seccomp profile allows ptrace(2) by default, but containerd does not (yet): https://github.com/containerd/containerd/issues/6802
 }
 
+func TestRunSeccompBuiltinVariant(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "allow-ptrace",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "seccomp=builtin:allow-ptrace",
+					testutil.AlpineImage, "grep", "-Eq", `^Seccomp:\s*2$`, "/proc/self/status")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "unknown variant",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "seccomp=builtin:nonexistent", testutil.AlpineImage, "true")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unknown builtin seccomp profile")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunDefaultCapabilitiesConfig(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.Config = test.WithConfig(nerdtest.NerdctlToml, `default_capabilities = ["net-basic", "CAP_KILL"]`)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "default",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", testutil.AlpineImage, "grep", "-w", "^CapEff:", "/proc/self/status")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(fmt.Sprintf("%016x", 1<<CapNetBindService|1<<CapNetRaw|1<<CapKill))),
+		},
+		{
+			Description: "cap-add",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--cap-add=ipc_lock", "--cap-drop=net_raw", testutil.AlpineImage, "grep", "-w", "^CapEff:", "/proc/self/status")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(fmt.Sprintf("%016x", 1<<CapNetBindService|1<<CapIPCLock|1<<CapKill))),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestRunNoNewPrivileges(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// the deprecated "<KEY>:<VALUE>" form, used by compose files
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), "--security-opt", "no-new-privileges:true",
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "run",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Identifier(), "grep", "-Eq", `^NoNewPrivs:\s*1$`, "/proc/1/status")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "exec",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", "--privileged", data.Identifier(), "grep", "-Eq", `^NoNewPrivs:\s*1$`, "/proc/self/status")
+			},
+			Expected: test.Expects(0, nil, nil),
+		},
+		{
+			Description: "inspect",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "inspect", "--format", "{{json .HostConfig.SecurityOpt}}", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Contains(`["no-new-privileges=true"]`)),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestRunSystemPathsUnconfined(t *testing.T) {
 	base := testutil.NewBase(t)
 
@@ -284,3 +393,30 @@ func TestRunPrivileged(t *testing.T) {
 	// something like `ls: /dev/dummy-zero: No such file or directory`
 	assert.Check(t, strings.Contains(res.Combined(), "No such file or directory"))
 }
+
+func TestRunSecurityOptMask(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "mask",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "mask=/etc/alpine-release",
+					testutil.CommonImage, "sh", "-c", "wc -c < /etc/alpine-release")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("0\n")),
+		},
+		{
+			Description: "unmask",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "--security-opt", "unmask=/proc/sys",
+					testutil.CommonImage, "sh", "-c", "grep -E '^proc /proc/sys ' /proc/mounts || echo writable")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("writable\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
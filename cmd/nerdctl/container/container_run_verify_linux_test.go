@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -83,3 +84,81 @@ CMD ["echo", "nerdctl-build-test-string"]
 
 	testCase.Run(t)
 }
+
+func TestRunVerifyCosignConfig(t *testing.T) {
+	dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-build-test-string"]
+	`, testutil.CommonImage)
+
+	testCase := nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase.Require = require.All(
+		require.Binary("cosign"),
+		require.Not(nerdtest.Docker),
+		nerdtest.Build,
+		nerdtest.Registry,
+	)
+
+	testCase.Env["COSIGN_PASSWORD"] = "1"
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		data.Temp().Save(dockerfile, "Dockerfile")
+		pri, pub := nerdtest.GenerateCosignKeyPair(data, helpers, "1")
+		reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+		reg.Setup(data, helpers)
+
+		testImageRef := fmt.Sprintf("127.0.0.1:%d/%s", reg.Port, data.Identifier("push-cosign-image"))
+		helpers.Ensure("build", "-t", testImageRef, data.Temp().Path())
+		helpers.Ensure("push", testImageRef, "--sign=cosign", "--cosign-key="+pri)
+		// tamper the local image after the signed one was pushed
+		helpers.Ensure("tag", testutil.CommonImage, testImageRef)
+
+		data.Temp().Save(fmt.Sprintf(`[verify]
+provider = "cosign"
+cosign_key = %q
+`, pub), "nerdctl.toml")
+		data.Labels().Set("nerdctl_toml", data.Temp().Path("nerdctl.toml"))
+		data.Labels().Set("image_ref", testImageRef)
+	}
+
+	// the [verify] table of nerdctl.toml, written in Setup as it contains the path of the public key
+	withVerifyConfig := func(data test.Data, cmd test.TestableCommand) test.TestableCommand {
+		cmd.Setenv("NERDCTL_TOML", data.Labels().Get("nerdctl_toml"))
+		return cmd
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rmi", "-f", data.Labels().Get("image_ref"))
+		if reg != nil {
+			reg.Cleanup(data, helpers)
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the tampered local image is rejected",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return withVerifyConfig(data, helpers.Command("run", "--rm", "--pull=never", data.Labels().Get("image_ref")))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("does not match the verified digest")}, nil),
+		},
+		{
+			Description: "the verified image is pulled",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return withVerifyConfig(data, helpers.Command("run", "--rm", data.Labels().Get("image_ref")))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("nerdctl-build-test-string")),
+		},
+		{
+			Description: "--verify=none overrides the config",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return withVerifyConfig(data, helpers.Command("run", "--rm", "--pull=never", "--verify=none", data.Labels().Get("image_ref"), "echo", "tampered"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains("tampered")),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -17,6 +17,9 @@
 package container
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	containerd "github.com/containerd/containerd/v2/client"
@@ -26,6 +29,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
 )
 
 func StatsCommand() *cobra.Command {
@@ -45,9 +49,16 @@ func StatsCommand() *cobra.Command {
 
 func addStatsFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("all", "a", false, "Show all containers (default shows just running)")
-	cmd.Flags().String("format", "", "Pretty-print images using a Go template, e.g, '{{json .}}'")
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().Bool("no-stream", false, "Disable streaming stats and only pull the first result")
 	cmd.Flags().Bool("no-trunc", false, "Do not truncate output")
+	cmd.Flags().String("sort", "", fmt.Sprintf("Sort the containers by the given key (%s). In the interactive mode, the key can be switched by pressing its initial", strings.Join(statsutil.SortKeys, "|")))
+	cmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return statsutil.SortKeys, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions, error) {
@@ -76,7 +87,13 @@ func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions,
 		return types.ContainerStatsOptions{}, err
 	}
 
+	sort, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		return types.ContainerStatsOptions{}, err
+	}
+
 	return types.ContainerStatsOptions{
+		Stdin:    cmd.InOrStdin(),
 		Stdout:   cmd.OutOrStdout(),
 		Stderr:   cmd.ErrOrStderr(),
 		GOptions: globalOptions,
@@ -84,6 +101,7 @@ func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions,
 		Format:   format,
 		NoStream: noStream,
 		NoTrunc:  noTrunc,
+		Sort:     sort,
 	}, nil
 }
 
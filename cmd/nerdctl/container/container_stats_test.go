@@ -17,6 +17,7 @@
 package container
 
 import (
+	"errors"
 	"runtime"
 	"testing"
 
@@ -54,6 +55,7 @@ func TestStats(t *testing.T) {
 		helpers.Ensure("run", "-d", "--name", data.Identifier("memlimited"), "--memory", "1g", testutil.CommonImage, "sleep", nerdtest.Infinity)
 		helpers.Ensure("run", "--name", data.Identifier("exited"), testutil.CommonImage, "echo", "'exited'")
 		data.Labels().Set("id", data.Identifier("container"))
+		data.Labels().Set("memlimited", data.Identifier("memlimited"))
 	}
 
 	testCase.SubTests = []*test.Case{
@@ -89,6 +91,23 @@ func TestStats(t *testing.T) {
 			},
 			Expected: test.Expects(0, nil, nil),
 		},
+		{
+			Description: "json format",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--no-trunc", "--format", "json", data.Labels().Get("id"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					// the PIDs are from a complete sample, not zeroed out
+					Output: expect.Contains(
+						`"Name":"`+data.Labels().Get("id")+`"`,
+						`"PIDs":"1"`,
+						`"BlockIO":`,
+						`"NetIO":`,
+					),
+				}
+			},
+		},
 		{
 			Description: "no mem limit set",
 			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
@@ -105,6 +124,27 @@ func TestStats(t *testing.T) {
 			},
 			Expected: test.Expects(0, nil, expect.Contains("1GiB")),
 		},
+		{
+			Description: "sort by name",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--sort", "name", "--format", "{{.Name}}",
+					data.Labels().Get("memlimited"), data.Labels().Get("id"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Labels().Get("id") + "\n" + data.Labels().Get("memlimited") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "unknown sort key",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--sort", "foo")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unknown sort key")}, nil),
+		},
 	}
 
 	testCase.Run(t)
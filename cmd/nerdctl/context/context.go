@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations:   map[string]string{helpers.Category: helpers.Management},
+		Use:           "context",
+		Short:         "Manage contexts",
+		Long:          "A context is a named set of the containerd address, namespace, snapshotter, and optionally a remote host to run nerdctl on over SSH",
+		RunE:          helpers.UnknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		createCommand(),
+		listCommand(),
+		useCommand(),
+		inspectCommand(),
+		removeCommand(),
+	)
+	return cmd
+}
+
+// storeDir returns the directory of the context store, next to nerdctl.toml.
+func storeDir() string {
+	return contextstore.Dir(helpers.NerdctlTOML())
+}
+
+// NameComplete completes the names of the contexts.
+func NameComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	candidates := []string{context.DefaultName}
+	st, err := contextstore.New(storeDir())
+	if err != nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+	contexts, err := st.List()
+	if err != nil {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, c := range contexts {
+		candidates = append(candidates, c.Name)
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
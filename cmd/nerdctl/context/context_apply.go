@@ -0,0 +1,138 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+)
+
+// contextFlags are the global flags set by the contexts, with their aliases and environment variables.
+var contextFlags = []struct {
+	names []string
+	env   string
+	value func(c *contextstore.Context) string
+}{
+	{[]string{"address", "a", "H", "host"}, "CONTAINERD_ADDRESS", func(c *contextstore.Context) string { return c.Address }},
+	{[]string{"namespace", "n"}, "CONTAINERD_NAMESPACE", func(c *contextstore.Context) string { return c.Namespace }},
+	{[]string{"snapshotter", "storage-driver"}, "CONTAINERD_SNAPSHOTTER", func(c *contextstore.Context) string { return c.Snapshotter }},
+}
+
+// Name returns the name of the context specified with --context or $NERDCTL_CONTEXT,
+// or selected with `nerdctl context use`. It returns "" for the default context.
+func Name(cmd *cobra.Command) (string, error) {
+	name, err := cmd.Flags().GetString("context")
+	if err != nil || name != "" {
+		return name, err
+	}
+	dir := storeDir()
+	// not to create the store just for looking up the current context
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	st, err := contextstore.New(dir)
+	if err != nil {
+		return "", err
+	}
+	return st.Current()
+}
+
+// Apply applies the context in effect to the global flags of cmd, except for the flags specified explicitly or
+// with their environment variables. It returns the context, or nil for the default context.
+// The `context` and `completion` commands ignore the contexts, so that a broken context can always be fixed.
+func Apply(cmd *cobra.Command) (*contextstore.Context, error) {
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Parent() == c.Root() && (c.Name() == "context" || c.Name() == "completion") {
+			return nil, nil
+		}
+	}
+	name, err := Name(cmd)
+	if err != nil || name == "" || name == context.DefaultName {
+		return nil, err
+	}
+	c, err := context.Resolve(storeDir(), name)
+	if err != nil {
+		return nil, err
+	}
+	if c.SSHHost != "" {
+		// the flags are passed to the remote nerdctl instead, see context.RunRemote
+		return c, nil
+	}
+	for _, f := range contextFlags {
+		v := f.value(c)
+		if v == "" || flagChanged(cmd, f.names) {
+			continue
+		}
+		if _, ok := os.LookupEnv(f.env); ok {
+			continue
+		}
+		if err := cmd.Flags().Set(f.names[0], v); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// RunRemote runs the command line of nerdctl on the SSH host of the context.
+// It always returns an error, to stop running the command locally; the error is an errutil.ExitCoder
+// carrying the exit status of the remote command, unless ssh itself failed.
+func RunRemote(cmd *cobra.Command, c *contextstore.Context) error {
+	args := os.Args[1:]
+	if cmd.Flags().Changed("context") {
+		args = removeContextFlag(args)
+	}
+	if err := context.RunRemote(cmd.Context(), c, args, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()); err != nil {
+		return err
+	}
+	return errutil.NewExitCoderErr(0)
+}
+
+func flagChanged(cmd *cobra.Command, names []string) bool {
+	for _, name := range names {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return true
+		}
+		if f := cmd.Root().Flags().Lookup(name); f != nil && f.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// removeContextFlag removes the first `--context NAME` or `--context=NAME` from args.
+// Only the first one is removed, as the later ones may be the arguments of the container, e.g., `nerdctl run IMAGE CMD --context`.
+func removeContextFlag(args []string) []string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return args
+		case arg == "--context":
+			end := min(i+2, len(args))
+			return append(args[:i:i], args[end:]...)
+		case strings.HasPrefix(arg, "--context="):
+			return append(args[:i:i], args[i+1:]...)
+		}
+	}
+	return args
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func createCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "create [flags] NAME",
+		Short: "Create a context",
+		Long: `Create a context, to be selected with "--context", $NERDCTL_CONTEXT, or "nerdctl context use".
+The settings left empty fall back to nerdctl.toml and the defaults.
+With --ssh, the commands are run by nerdctl on the remote host, so nerdctl has to be installed there.`,
+		Args:          cobra.ExactArgs(1),
+		RunE:          createAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("description", "", "Description of the context")
+	cmd.Flags().String("containerd-address", "", `containerd address, optionally with "unix://" prefix, or "ssh://[USER@]HOST[:PORT][/PATH/TO/containerd.sock]" for containerd on a remote host`)
+	cmd.Flags().String("containerd-namespace", "", "containerd namespace")
+	cmd.Flags().String("containerd-snapshotter", "", "containerd snapshotter")
+	cmd.Flags().String("ssh", "", "Remote host to run nerdctl on, in the form of [ssh://][USER@]HOST[:PORT]")
+	return cmd
+}
+
+func createOptions(cmd *cobra.Command, args []string) (types.ContextCreateOptions, error) {
+	description, err := cmd.Flags().GetString("description")
+	if err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	address, err := cmd.Flags().GetString("containerd-address")
+	if err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	namespace, err := cmd.Flags().GetString("containerd-namespace")
+	if err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	snapshotter, err := cmd.Flags().GetString("containerd-snapshotter")
+	if err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	sshHost, err := cmd.Flags().GetString("ssh")
+	if err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	return types.ContextCreateOptions{
+		Stdout:      cmd.OutOrStdout(),
+		Dir:         storeDir(),
+		Name:        args[0],
+		Description: description,
+		Address:     address,
+		Namespace:   namespace,
+		Snapshotter: snapshotter,
+		SSHHost:     sshHost,
+	}, nil
+}
+
+func createAction(cmd *cobra.Command, args []string) error {
+	options, err := createOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+	return context.Create(options)
+}
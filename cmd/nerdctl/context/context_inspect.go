@@ -0,0 +1,60 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func inspectCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "inspect [NAME...]",
+		Short:             "Display detailed information on one or more contexts",
+		Long:              "Display detailed information on one or more contexts. The current context is inspected when no name is given.",
+		RunE:              inspectAction,
+		ValidArgsFunction: NameComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func inspectAction(cmd *cobra.Command, args []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		current, err := Name(cmd)
+		if err != nil {
+			return err
+		}
+		args = []string{current}
+	}
+	return context.Inspect(args, types.ContextInspectOptions{
+		Stdout: cmd.OutOrStdout(),
+		Dir:    storeDir(),
+		Format: format,
+	})
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestMain(m *testing.M) {
+	testutil.M(m)
+}
+
+func TestContext(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The contexts are stored next to $NERDCTL_TOML, which is private to each test
+	testCase.Require = require.Not(nerdtest.Docker)
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "create, use and ls",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("context", "create", "--description", "remote host", "--ssh", "ssh://user@example.com:2222", "--containerd-namespace", "k8s.io", data.Identifier())
+				helpers.Ensure("context", "use", data.Identifier())
+			},
+			Command: test.Command("context", "ls"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						var found bool
+						for _, line := range strings.Split(stdout, "\n") {
+							if strings.HasPrefix(line, data.Identifier()+" *") {
+								found = true
+								assert.Assert(t, strings.Contains(line, "remote host"), line)
+								assert.Assert(t, strings.Contains(line, "k8s.io"), line)
+								assert.Assert(t, strings.Contains(line, "ssh://user@example.com:2222"), line)
+							}
+						}
+						assert.Assert(t, found, stdout)
+					},
+				}
+			},
+		},
+		{
+			Description: "inspect",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("context", "create", "--containerd-snapshotter", "stargz", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("context", "inspect", "--format", "{{.Name}} {{.Snapshotter}}", data.Identifier(), "default")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(data.Identifier()+" stargz\n", "default"),
+				}
+			},
+		},
+		{
+			Description: "unknown context",
+			Command:     test.Command("--context", "nonexistent-context", "ps"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("nonexistent-context"), errors.New("not found")}, nil),
+		},
+		{
+			Description: "invalid SSH host",
+			Command:     test.Command("context", "create", "--ssh", "tcp://example.com", "invalid-ssh"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unsupported scheme")}, nil),
+		},
+		{
+			Description: "the default context cannot be removed",
+			Command:     test.Command("context", "rm", "default"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cannot be removed")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
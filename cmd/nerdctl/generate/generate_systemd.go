@@ -0,0 +1,103 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/generate"
+)
+
+func systemdCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "systemd [flags] CONTAINER|PROJECT [CONTAINER|PROJECT...]",
+		Short: "Generate systemd service units for containers, or the containers of compose projects",
+		Long: `Generate systemd service units for containers, or the containers of compose projects.
+
+The units start the containers with 'nerdctl start --attach', so that systemd supervises them.
+The restart policies of the containers are translated into the Restart= of the units, and cleared from the containers.
+The units of a compose project are ordered after the units of the services they depend on.
+
+In rootless mode, the units are generated for the user scope ('systemctl --user').
+Run 'sudo loginctl enable-linger $(whoami)' to keep the containers running after logout.`,
+		Example:           "  nerdctl generate systemd --files nginx",
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              systemdAction,
+		ValidArgsFunction: systemdShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("scope", "", "Scope of the units (system|user), defaults to \"user\" in rootless mode")
+	cmd.RegisterFlagCompletionFunc("scope", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"system", "user"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Bool("files", false, "Write the units to files in the current directory, instead of stdout")
+	cmd.Flags().UintP("time", "t", 10, "Seconds to wait for stopping the containers before killing them")
+	return cmd
+}
+
+func systemdOptions(cmd *cobra.Command) (types.GenerateSystemdOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	scope, err := cmd.Flags().GetString("scope")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	files, err := cmd.Flags().GetBool("files")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	stopTimeout, err := cmd.Flags().GetUint("time")
+	if err != nil {
+		return types.GenerateSystemdOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.GenerateSystemdOptions{
+		Stdout:      cmd.OutOrStdout(),
+		GOptions:    globalOptions,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+		Scope:       scope,
+		Files:       files,
+		StopTimeout: stopTimeout,
+	}, nil
+}
+
+func systemdAction(cmd *cobra.Command, args []string) error {
+	options, err := systemdOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return generate.Systemd(ctx, client, args, options)
+}
+
+func systemdShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.ContainerNames(cmd, nil)
+}
@@ -18,11 +18,16 @@ package helpers
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	ncdefaults "github.com/containerd/nerdctl/v2/pkg/defaults"
 	"github.com/containerd/nerdctl/v2/pkg/fs"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 func VerifyOptions(cmd *cobra.Command) (opt types.ImageVerifyOptions, err error) {
@@ -121,6 +126,14 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
+	noTokenCache, err := cmd.Flags().GetBool("no-token-cache")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	snapshotterFallback, err := cmd.Flags().GetBool("snapshotter-fallback")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
 	experimental, err := cmd.Flags().GetBool("experimental")
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
@@ -141,6 +154,27 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
+	scanner, err := cmd.Flags().GetString("scanner")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	scanSeverity, err := cmd.Flags().GetString("scan-severity")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	seccompProfile, err := cmd.Flags().GetString("seccomp-profile")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	otelEndpoint, err := cmd.Flags().GetString("otel-endpoint")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	// build, bake, and compose build have their own local --progress flag that shadows the global one
+	progress, err := cmd.Root().PersistentFlags().GetString("progress")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
 	dns, err := cmd.Flags().GetStringSlice("global-dns")
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
@@ -153,6 +187,65 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
+	defaultCaps, err := cmd.Flags().GetStringSlice("global-default-capabilities")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	logDriver, err := cmd.Flags().GetString("global-log-driver")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	logOpts, err := cmd.Flags().GetStringArray("global-log-opts")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	verifyProvider, err := cmd.Flags().GetString("global-verify")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	verifyCosignKey, err := cmd.Flags().GetString("global-cosign-key")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	globalRuntimes, err := cmd.Flags().GetString("global-runtimes")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	var runtimes map[string]config.RuntimeConfig
+	if globalRuntimes != "" {
+		if err := toml.Unmarshal([]byte(globalRuntimes), &runtimes); err != nil {
+			return types.GlobalCommandOptions{}, fmt.Errorf("failed to parse the runtimes: %w", err)
+		}
+	}
+	globalQuotas, err := cmd.Flags().GetString("global-quotas")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	var quotas map[string]config.QuotaConfig
+	if globalQuotas != "" {
+		if err := toml.Unmarshal([]byte(globalQuotas), &quotas); err != nil {
+			return types.GlobalCommandOptions{}, fmt.Errorf("failed to parse the quotas: %w", err)
+		}
+	}
+	globalRegistries, err := cmd.Flags().GetString("global-registries")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	var registries map[string]config.RegistryConfig
+	if globalRegistries != "" {
+		if err := toml.Unmarshal([]byte(globalRegistries), &registries); err != nil {
+			return types.GlobalCommandOptions{}, fmt.Errorf("failed to parse the registries: %w", err)
+		}
+	}
+
+	defaultNetwork, err := cmd.Flags().GetString("global-default-network")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	namespaceConfigs, err := namespaceConfigs(cmd)
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
 
 	// Point to dataRoot for filesystem-helpers implementing rollback / backups.
 	err = fs.InitFS(dataRoot)
@@ -172,6 +265,7 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 		CgroupManager:    cgroupManager,
 		InsecureRegistry: insecureRegistry,
 		HostsDir:         hostsDir,
+		NoTokenCache:     noTokenCache,
 		Experimental:     experimental,
 		HostGatewayIP:    hostGatewayIP,
 		BridgeIP:         bridgeIP,
@@ -180,6 +274,26 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 		DNS:              dns,
 		DNSOpts:          dnsOpts,
 		DNSSearch:        dnsSearch,
+		Scanner:          scanner,
+		ScanSeverity:     scanSeverity,
+		SeccompProfile:   seccompProfile,
+		OTelEndpoint:     otelEndpoint,
+		Progress:         progress,
+		Logging: config.LoggingConfig{
+			Driver: logDriver,
+			Opts:   strutil.ConvertKVStringsToMap(logOpts),
+		},
+		SnapshotterFallback: snapshotterFallback,
+		DefaultCapabilities: defaultCaps,
+		Verify: config.VerifyConfig{
+			Provider:  verifyProvider,
+			CosignKey: verifyCosignKey,
+		},
+		Runtimes:       runtimes,
+		Quotas:         quotas,
+		Registries:     registries,
+		DefaultNetwork: defaultNetwork,
+		Namespaces:     namespaceConfigs,
 	}, nil
 }
 
@@ -195,3 +309,11 @@ func CheckExperimental(feature string) func(cmd *cobra.Command, args []string) e
 		return nil
 	}
 }
+
+// NerdctlTOML returns the path of nerdctl.toml, which can be overridden with $NERDCTL_TOML.
+func NerdctlTOML() string {
+	if v, ok := os.LookupEnv("NERDCTL_TOML"); ok {
+		return v
+	}
+	return ncdefaults.NerdctlTOML()
+}
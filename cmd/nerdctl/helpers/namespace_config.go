@@ -0,0 +1,126 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+// namespaceConfigs decodes the [namespaces] tables of nerdctl.toml, passed with the hidden --global-namespaces flag.
+func namespaceConfigs(cmd *cobra.Command) (map[string]config.NamespaceConfig, error) {
+	globalNamespaces, err := cmd.Flags().GetString("global-namespaces")
+	if err != nil {
+		return nil, err
+	}
+	var namespaces map[string]config.NamespaceConfig
+	if globalNamespaces != "" {
+		if err := toml.Unmarshal([]byte(globalNamespaces), &namespaces); err != nil {
+			return nil, fmt.Errorf("failed to parse the namespaces: %w", err)
+		}
+	}
+	return namespaces, nil
+}
+
+// ApplyNamespaceConfig applies the [namespaces."<NAMESPACE>"] table of the namespace in use to the global flags of cmd,
+// except for the flags specified explicitly or with their environment variables.
+// The flags are not marked as changed, as the values still come from nerdctl.toml.
+func ApplyNamespaceConfig(cmd *cobra.Command) error {
+	namespaces, err := namespaceConfigs(cmd)
+	if err != nil || len(namespaces) == 0 {
+		return err
+	}
+	namespace, err := cmd.Flags().GetString("namespace")
+	if err != nil {
+		return err
+	}
+	nc, ok := namespaces[namespace]
+	if !ok {
+		return nil
+	}
+	if nc.Snapshotter != "" && !flagChanged(cmd, "snapshotter", "storage-driver") {
+		if _, ok := os.LookupEnv("CONTAINERD_SNAPSHOTTER"); !ok {
+			if err := setFlagValue(cmd, "snapshotter", nc.Snapshotter); err != nil {
+				return err
+			}
+		}
+	}
+	if len(nc.HostsDir) > 0 && !flagChanged(cmd, "hosts-dir") {
+		if err := replaceFlagValues(cmd, "hosts-dir", nc.HostsDir); err != nil {
+			return err
+		}
+	}
+	if nc.DefaultNetwork != "" {
+		if err := setFlagValue(cmd, "global-default-network", nc.DefaultNetwork); err != nil {
+			return err
+		}
+	}
+	if nc.Logging.Driver != "" || len(nc.Logging.Opts) > 0 {
+		// the options of the top-level driver may not be valid for the driver of the namespace,
+		// so the [logging] table is replaced as a whole
+		if nc.Logging.Driver != "" {
+			if err := setFlagValue(cmd, "global-log-driver", nc.Logging.Driver); err != nil {
+				return err
+			}
+		}
+		var logOpts []string
+		for _, k := range slices.Sorted(maps.Keys(nc.Logging.Opts)) {
+			logOpts = append(logOpts, k+"="+nc.Logging.Opts[k])
+		}
+		if err := replaceFlagValues(cmd, "global-log-opts", logOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flagChanged(cmd *cobra.Command, names ...string) bool {
+	for _, name := range names {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+func setFlagValue(cmd *cobra.Command, name, value string) error {
+	f := cmd.Flags().Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q is not defined", name)
+	}
+	return f.Value.Set(value)
+}
+
+func replaceFlagValues(cmd *cobra.Command, name string, values []string) error {
+	f := cmd.Flags().Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q is not defined", name)
+	}
+	sv, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return fmt.Errorf("flag %q is not a slice", name)
+	}
+	return sv.Replace(values)
+}
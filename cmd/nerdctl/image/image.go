@@ -41,6 +41,14 @@ func Command() *cobra.Command {
 		PushCommand(),
 		LoadCommand(),
 		SaveCommand(),
+		exportFSCommand(),
+		prefetchCommand(),
+		squashCommand(),
+		duCommand(),
+		checkCommand(),
+		sbomCommand(),
+		scanCommand(),
+		copyCommand(),
 		ImportCommand(),
 		TagCommand(),
 		imageRemoveCommand(),
@@ -0,0 +1,115 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func checkCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "check [flags] [IMAGE...]",
+		Short: "Verify the integrity of the local content of images",
+		Long: `Verify the integrity of the local content of images.
+
+Every blob (index, manifest, config and layers) of the selected platforms must be present
+in the content store, and must match its digest and size. Missing and corrupted blobs are reported,
+as well as the missing snapshots of partially unpacked images.
+
+With --repair, the corrupted blobs are removed, and the missing blobs are fetched from the registry again
+(by digest), before unpacking the image again.`,
+		RunE:              checkAction,
+		ValidArgsFunction: checkShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().BoolP("all", "a", false, "Check all images")
+	cmd.Flags().Bool("repair", false, "Remove corrupted content, and fetch the missing content and snapshots again")
+
+	// #region platform flags
+	// platform is defined as StringSlice, not StringArray, to allow specifying "--platform=amd64,arm64"
+	cmd.Flags().StringSlice("platform", []string{}, "Check content for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	cmd.Flags().Bool("all-platforms", false, "Check content for all platforms")
+	// #endregion
+	return cmd
+}
+
+func checkOptions(cmd *cobra.Command) (types.ImageCheckOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageCheckOptions{}, err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return types.ImageCheckOptions{}, err
+	}
+	platform, err := cmd.Flags().GetStringSlice("platform")
+	if err != nil {
+		return types.ImageCheckOptions{}, err
+	}
+	allPlatforms, err := cmd.Flags().GetBool("all-platforms")
+	if err != nil {
+		return types.ImageCheckOptions{}, err
+	}
+	repair, err := cmd.Flags().GetBool("repair")
+	if err != nil {
+		return types.ImageCheckOptions{}, err
+	}
+	return types.ImageCheckOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		All:          all,
+		Platform:     platform,
+		AllPlatforms: allPlatforms,
+		Repair:       repair,
+	}, nil
+}
+
+func checkAction(cmd *cobra.Command, args []string) error {
+	options, err := checkOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 && !options.All {
+		return errors.New("requires at least 1 argument, or --all")
+	}
+	if len(args) > 0 && options.All {
+		return errors.New("--all cannot be specified together with image names")
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Check(ctx, client, args, options)
+}
+
+func checkShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show image names
+	return completion.ImageNames(cmd)
+}
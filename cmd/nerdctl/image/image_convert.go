@@ -18,6 +18,7 @@ package image
 
 import (
 	"compress/gzip"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -32,6 +33,9 @@ const imageConvertHelp = `Convert an image format.
 
 e.g., 'nerdctl image convert --estargz --oci example.com/foo:orig example.com/foo:esgz'
 
+Use '--format=oci' or '--format=docker' to rewrite manifest, config and layer media types
+to OCI or Docker media types, e.g., 'nerdctl image convert --format=docker example.com/foo:oci example.com/foo:docker'
+
 Use '--platform' to define the output platform.
 When '--all-platforms' is given all images in a manifest list must be available.
 
@@ -51,7 +55,7 @@ func convertCommand() *cobra.Command {
 		SilenceErrors:     true,
 	}
 
-	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, 'json'")
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, 'json'. 'oci' or 'docker' rewrites the media types of the converted image instead")
 
 	// #region estargz flags
 	cmd.Flags().Bool("estargz", false, "Convert legacy tar(.gz) layers to eStargz for lazy pulling. Should be used in conjunction with '--oci'")
@@ -90,6 +94,11 @@ func convertCommand() *cobra.Command {
 	cmd.Flags().String("overlaybd-dbstr", "", "Database config string for overlaybd")
 	// #endregion
 
+	// #region erofs flags
+	cmd.Flags().Bool("erofs", false, "Convert tar.gz layers to native EROFS layers, for the erofs snapshotter. Should be used in conjunction with '--oci'")
+	cmd.Flags().String("erofs-mkfs-options", "", "Extra options of mkfs.erofs, e.g., \"-zlz4hc\"")
+	// #endregion
+
 	// #region soci flags
 	cmd.Flags().Bool("soci", false, "Convert image to SOCI Index V2 format.")
 	cmd.Flags().Int64("soci-min-layer-size", -1, "The minimum size of layers that will be converted to SOCI Index V2 format")
@@ -99,6 +108,7 @@ func convertCommand() *cobra.Command {
 	// #region generic flags
 	cmd.Flags().Bool("uncompress", false, "Convert tar.gz layers to uncompressed tar layers")
 	cmd.Flags().Bool("oci", false, "Convert Docker media types to OCI media types")
+	cmd.Flags().Int("parallelism", 0, "Maximum number of layers converted in parallel (0 for the number of CPUs)")
 	// #endregion
 
 	// #region platform flags
@@ -224,6 +234,17 @@ func convertOptions(cmd *cobra.Command) (types.ImageConvertOptions, error) {
 	}
 	// #endregion
 
+	// #region erofs flags
+	erofs, err := cmd.Flags().GetBool("erofs")
+	if err != nil {
+		return types.ImageConvertOptions{}, err
+	}
+	erofsMkfsOptions, err := cmd.Flags().GetString("erofs-mkfs-options")
+	if err != nil {
+		return types.ImageConvertOptions{}, err
+	}
+	// #endregion
+
 	// #region soci flags
 	soci, err := cmd.Flags().GetBool("soci")
 	if err != nil {
@@ -248,6 +269,19 @@ func convertOptions(cmd *cobra.Command) (types.ImageConvertOptions, error) {
 	if err != nil {
 		return types.ImageConvertOptions{}, err
 	}
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	if err != nil {
+		return types.ImageConvertOptions{}, err
+	}
+	var docker bool
+	switch format {
+	case "oci":
+		oci = true
+		format = ""
+	case "docker":
+		docker = true
+		format = ""
+	}
 	// #endregion
 
 	// #region platform flags
@@ -264,8 +298,10 @@ func convertOptions(cmd *cobra.Command) (types.ImageConvertOptions, error) {
 		GOptions: globalOptions,
 		Format:   format,
 		// #region generic flags
-		Uncompress: uncompress,
-		Oci:        oci,
+		Uncompress:  uncompress,
+		Oci:         oci,
+		Docker:      docker,
+		Parallelism: parallelism,
 		// #endregion
 		// #region platform flags
 		Platforms:    platforms,
@@ -304,6 +340,10 @@ func convertOptions(cmd *cobra.Command) (types.ImageConvertOptions, error) {
 			OverlayFsType:  overlaybdFsType,
 			OverlaydbDBStr: overlaybdDbstr,
 		},
+		ErofsOptions: types.ErofsOptions{
+			Erofs:            erofs,
+			ErofsMkfsOptions: strings.Fields(erofsMkfsOptions),
+		},
 		SociConvertOptions: types.SociConvertOptions{
 			Soci: soci,
 			SociOptions: types.SociOptions{
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 
@@ -54,6 +55,17 @@ func TestImageConvert(t *testing.T) {
 				},
 				Expected: test.Expects(0, nil, nil),
 			},
+			{
+				Description: "esgz with parallelism",
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("converted-image"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("image", "convert", "--oci", "--estargz", "--parallelism=1",
+						testutil.CommonImage, data.Identifier("converted-image"))
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
 			{
 				Description: "nydus",
 				Require: require.All(
@@ -68,6 +80,20 @@ func TestImageConvert(t *testing.T) {
 				},
 				Expected: test.Expects(0, nil, nil),
 			},
+			{
+				Description: "erofs",
+				Require: require.All(
+					require.Binary("mkfs.erofs"),
+				),
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("converted-image"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("image", "convert", "--oci", "--erofs",
+						testutil.CommonImage, data.Identifier("converted-image"))
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
 			{
 				Description: "zstd",
 				Cleanup: func(data test.Data, helpers test.Helpers) {
@@ -90,6 +116,32 @@ func TestImageConvert(t *testing.T) {
 				},
 				Expected: test.Expects(0, nil, nil),
 			},
+			{
+				Description: "oci to docker media types",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("image", "convert", "--format=oci", testutil.CommonImage, data.Identifier("oci-image"))
+				},
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("oci-image"))
+					helpers.Anyhow("rmi", "-f", data.Identifier("converted-image"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					helpers.Ensure("image", "convert", "--format=docker", data.Identifier("oci-image"), data.Identifier("converted-image"))
+					return helpers.Command("image", "inspect", "--mode=native", "--format={{.Manifest.MediaType}}", data.Identifier("converted-image"))
+				},
+				Expected: test.Expects(0, nil, expect.Contains("application/vnd.docker.distribution.manifest.v2+json")),
+			},
+			{
+				Description: "docker media types conflict with estargz",
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", data.Identifier("converted-image"))
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("image", "convert", "--format=docker", "--estargz",
+						testutil.CommonImage, data.Identifier("converted-image"))
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+			},
 			{
 				Description: "soci",
 				Require: require.All(
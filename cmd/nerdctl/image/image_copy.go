@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func copyCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "copy [flags] SOURCE DESTINATION",
+		Args:  cobra.ExactArgs(2),
+		Short: "Copy an image from a registry to another",
+		Long: `Copy an image from a registry to another.
+
+The blobs are streamed between the registries, without storing the image in the local content store.
+The credentials of "nerdctl login" are used for both registries.`,
+		Example:       "  nerdctl image copy --all-platforms registry-a.example.com/app:1.0 registry-b.example.com/app:1.0",
+		RunE:          copyAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("platform", "", "Copy content for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	cmd.Flags().Bool("all-platforms", false, "Copy content for all platforms")
+	cmd.MarkFlagsMutuallyExclusive("platform", "all-platforms")
+	cmd.Flags().BoolP("quiet", "q", false, "Only display the digest of the copied image")
+	return cmd
+}
+
+func copyOptions(cmd *cobra.Command) (types.ImageCopyOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageCopyOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageCopyOptions{}, err
+	}
+	allPlatforms, err := cmd.Flags().GetBool("all-platforms")
+	if err != nil {
+		return types.ImageCopyOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ImageCopyOptions{}, err
+	}
+	return types.ImageCopyOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		Platform:     platform,
+		AllPlatforms: allPlatforms,
+		Quiet:        quiet,
+	}, nil
+}
+
+func copyAction(cmd *cobra.Command, args []string) error {
+	options, err := copyOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	return image.Copy(cmd.Context(), args[0], args[1], options)
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest/registry"
+)
+
+func TestImageCopy(t *testing.T) {
+	nerdtest.Setup()
+
+	var srcRegistry, dstRegistry *registry.Server
+
+	testCase := &test.Case{
+		Require: require.All(
+			require.Linux,
+			require.Not(nerdtest.Docker),
+			nerdtest.Registry,
+		),
+
+		Setup: func(data test.Data, helpers test.Helpers) {
+			srcRegistry = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+			srcRegistry.Setup(data, helpers)
+			dstRegistry = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+			dstRegistry.Setup(data, helpers)
+
+			srcRef := fmt.Sprintf("%s:%d/%s:1.0", srcRegistry.IP.String(), srcRegistry.Port, data.Identifier())
+			dstRef := fmt.Sprintf("%s:%d/%s:1.0", dstRegistry.IP.String(), dstRegistry.Port, data.Identifier())
+			data.Labels().Set("srcRef", srcRef)
+			data.Labels().Set("dstRef", dstRef)
+			helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+			helpers.Ensure("tag", testutil.CommonImage, srcRef)
+			helpers.Ensure("push", "--insecure-registry", srcRef)
+			helpers.Ensure("rmi", srcRef)
+		},
+
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			if data.Labels().Get("dstRef") != "" {
+				helpers.Anyhow("rmi", "-f", data.Labels().Get("dstRef"))
+			}
+			if srcRegistry != nil {
+				srcRegistry.Cleanup(data, helpers)
+			}
+			if dstRegistry != nil {
+				dstRegistry.Cleanup(data, helpers)
+			}
+		},
+
+		Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			return helpers.Command("--insecure-registry", "image", "copy", data.Labels().Get("srcRef"), data.Labels().Get("dstRef"))
+		},
+
+		Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+			return &test.Expected{
+				Output: expect.All(
+					expect.Contains("Copied"),
+					func(stdout string, t tig.T) {
+						// The image is not stored locally, but can be pulled from the destination
+						helpers.Fail("image", "inspect", data.Labels().Get("dstRef"))
+						helpers.Ensure("pull", "--quiet", "--insecure-registry", data.Labels().Get("dstRef"))
+					},
+				),
+			}
+		},
+	}
+
+	testCase.Run(t)
+}
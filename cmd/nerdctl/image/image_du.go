@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func duCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "du [flags] [IMAGE...]",
+		Short: "Show the disk usage of images and of their layers",
+		Long: `Show the disk usage of images and of their layers, based on the usage reported by the snapshotter.
+
+SHARED SIZE is the size of the layers that are also used by other images.
+UNIQUE SIZE is the size of the layers only used by the image, i.e., the space reclaimed by removing it.`,
+		RunE:              duAction,
+		ValidArgsFunction: duShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().BoolP("verbose", "v", false, "Show the disk usage of each layer")
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func duOptions(cmd *cobra.Command, args []string) (types.ImageDiskUsageOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageDiskUsageOptions{}, err
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return types.ImageDiskUsageOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageDiskUsageOptions{}, err
+	}
+	return types.ImageDiskUsageOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Images:   args,
+		Format:   format,
+		Verbose:  verbose,
+	}, nil
+}
+
+func duAction(cmd *cobra.Command, args []string) error {
+	options, err := duOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.DiskUsage(ctx, client, options)
+}
+
+func duShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show image names
+	return completion.ImageNames(cmd)
+}
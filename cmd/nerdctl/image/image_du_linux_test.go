@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestImageDiskUsage(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--name", data.Identifier("container"), testutil.CommonImage, "sh", "-c", "dd if=/dev/zero of=/du-test bs=1M count=1")
+		helpers.Ensure("commit", data.Identifier("container"), data.Identifier("committed"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("container"))
+		helpers.Anyhow("rmi", "-f", data.Identifier("committed"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "table",
+			Command:     test.Command("image", "du", "-v", testutil.CommonImage),
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Contains(
+				"REPOSITORY", "SHARED SIZE", "UNIQUE SIZE", "└─",
+			)),
+		},
+		{
+			Description: "shared base layers",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("image", "du", "--format={{json .}}", data.Identifier("committed"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, func(stdout string, t tig.T) {
+				var du struct {
+					Size       int64
+					SharedSize int64
+					UniqueSize int64
+				}
+				assert.NilError(t, json.Unmarshal([]byte(stdout), &du))
+				assert.Assert(t, du.SharedSize > 0, "the layers of the base image should be shared")
+				assert.Assert(t, du.UniqueSize >= 1024*1024, "the committed layer should be unique")
+				assert.Equal(t, du.Size, du.SharedSize+du.UniqueSize)
+			}),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func exportFSCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export-fs [flags] IMAGE OUTPUT",
+		Args:  cobra.ExactArgs(2),
+		Short: "Flatten an image rootfs into a squashfs or erofs filesystem image",
+		Long: `Flatten an image rootfs into a squashfs or erofs filesystem image.
+
+The resulting file can be embedded into VM images or appliance builds.
+Requires mksquashfs (for --format=squashfs) or mkfs.erofs (for --format=erofs) to be installed.`,
+		Example:           "  nerdctl image export-fs --format=squashfs alpine:latest alpine.sqfs",
+		RunE:              exportFSAction,
+		ValidArgsFunction: exportFSShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("format", "squashfs", "Filesystem format of the output image (squashfs|erofs)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"squashfs", "erofs"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().String("platform", "", "Export the rootfs for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	return cmd
+}
+
+func exportFSOptions(cmd *cobra.Command, args []string) (types.ImageExportFSOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageExportFSOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageExportFSOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageExportFSOptions{}, err
+	}
+	return types.ImageExportFSOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+		Platform: platform,
+		Output:   args[1],
+	}, nil
+}
+
+func exportFSAction(cmd *cobra.Command, args []string) error {
+	options, err := exportFSOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.ExportFS(ctx, client, args[0], options)
+}
+
+func exportFSShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		// show image names
+		return completion.ImageNames(cmd)
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
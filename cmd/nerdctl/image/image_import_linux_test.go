@@ -166,6 +166,48 @@ func TestImageImport(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "image import with change",
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rmi", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("import",
+					"--change", `CMD ["sh"]`,
+					"--change", `ENV FOO=bar GREETING="hello world"`,
+					"--change", `LABEL description="a b" version=1`,
+					"--change", "WORKDIR /work",
+					"--change", "EXPOSE 8080",
+					"-", data.Identifier())
+				cmd.Feed(bytes.NewReader(minimalRootfsTar(t).Bytes()))
+				return cmd
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				identifier := data.Identifier() + ":latest"
+				return &test.Expected{
+					Output: expect.All(
+						func(stdout string, t tig.T) {
+							img := nerdtest.InspectImage(helpers, identifier)
+							assert.DeepEqual(t, img.Config.Cmd, []string{"sh"})
+							assert.DeepEqual(t, img.Config.Env, []string{"FOO=bar", "GREETING=hello world"})
+							assert.DeepEqual(t, img.Config.Labels, map[string]string{"description": "a b", "version": "1"})
+							assert.Equal(t, img.Config.WorkingDir, "/work")
+							_, ok := img.Config.ExposedPorts["8080/tcp"]
+							assert.Assert(t, ok)
+						},
+					),
+				}
+			},
+		},
+		{
+			Description: "image import with unsupported change",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("import", "--change", "RUN true", "-", data.Identifier())
+				cmd.Feed(bytes.NewReader(minimalRootfsTar(t).Bytes()))
+				return cmd
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
 		{
 			Description: "image import from URL",
 			Cleanup: func(data test.Data, helpers test.Helpers) {
@@ -77,6 +77,7 @@ func processLoadCommandFlags(cmd *cobra.Command) (types.ImageLoadOptions, error)
 		Platform:     platform,
 		AllPlatforms: allPlatforms,
 		Stdout:       cmd.OutOrStdout(),
+		Stderr:       cmd.ErrOrStderr(),
 		Stdin:        cmd.InOrStdin(),
 		Quiet:        quiet,
 	}, nil
@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func prefetchCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "prefetch [flags] IMAGE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Fetch the contents of a lazily-pulled image before the containers access them",
+		Long: `Fetch the contents of a lazily-pulled image before the containers access them.
+
+The files of the image are read through a read-only view of its rootfs, so that the remote snapshotter
+(stargz, nydus, soci or overlaybd) fetches their chunks from the registry and caches them.
+Useful for warming the cache of latency-sensitive services before traffic arrives.
+The image has to be pulled with the same --snapshotter beforehand.`,
+		Example: `  nerdctl --snapshotter=stargz image prefetch ghcr.io/stargz-containers/python:3.7-esgz
+  nerdctl --snapshotter=stargz image prefetch --paths=/usr/local/lib/python3.7,/etc ghcr.io/stargz-containers/python:3.7-esgz`,
+		RunE:              prefetchAction,
+		ValidArgsFunction: prefetchShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringSlice("paths", nil, "Paths in the image to prefetch; directories are prefetched recursively (default: the whole rootfs)")
+	cmd.Flags().String("platform", "", "Prefetch the image for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	cmd.Flags().Int("parallelism", 0, "Maximum number of files read in parallel (0 for the number of CPUs)")
+	return cmd
+}
+
+func prefetchOptions(cmd *cobra.Command) (types.ImagePrefetchOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImagePrefetchOptions{}, err
+	}
+	paths, err := cmd.Flags().GetStringSlice("paths")
+	if err != nil {
+		return types.ImagePrefetchOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImagePrefetchOptions{}, err
+	}
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	if err != nil {
+		return types.ImagePrefetchOptions{}, err
+	}
+	return types.ImagePrefetchOptions{
+		Stdout:      cmd.OutOrStdout(),
+		GOptions:    globalOptions,
+		Paths:       paths,
+		Platform:    platform,
+		Parallelism: parallelism,
+	}, nil
+}
+
+func prefetchAction(cmd *cobra.Command, args []string) error {
+	options, err := prefetchOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Prefetch(ctx, client, args[0], options)
+}
+
+func prefetchShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		// show image names
+		return completion.ImageNames(cmd)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestImagePrefetch(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "non-remote snapshotter is rejected",
+			Command:     test.Command("--snapshotter=overlayfs", "image", "prefetch", testutil.CommonImage),
+			Expected:    test.Expects(1, []error{errors.New("does not support lazy pulling")}, nil),
+		},
+		{
+			Description: "stargz",
+			Require: require.All(
+				nerdtest.Stargz,
+				require.Amd64,
+			),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("--snapshotter=stargz", "pull", "--quiet", testutil.FedoraESGZImage)
+			},
+			Command:  test.Command("--snapshotter=stargz", "image", "prefetch", "--paths=/etc", testutil.FedoraESGZImage),
+			Expected: test.Expects(0, nil, expect.Contains("Prefetched ")),
+		},
+		{
+			Description: "missing path",
+			Require: require.All(
+				nerdtest.Stargz,
+				require.Amd64,
+			),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("--snapshotter=stargz", "pull", "--quiet", testutil.FedoraESGZImage)
+			},
+			Command:  test.Command("--snapshotter=stargz", "image", "prefetch", "--paths=/nonexistent", testutil.FedoraESGZImage),
+			Expected: test.Expects(1, []error{errors.New("does not exist in the image")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -17,6 +17,8 @@
 package image
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
@@ -50,9 +52,9 @@ func PullCommand() *cobra.Command {
 	// #endregion
 
 	// #region verify flags
-	cmd.Flags().String("verify", "none", "Verify the image (none|cosign|notation)")
+	cmd.Flags().String("verify", "none", "Verify the image (none|cosign|notation|scan)")
 	cmd.RegisterFlagCompletionFunc("verify", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"none", "cosign", "notation"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"none", "cosign", "notation", "scan"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().String("cosign-key", "", "Path to the public key file, KMS, URI or Kubernetes Secret for --verify=cosign")
 	cmd.Flags().String("cosign-certificate-identity", "", "The identity expected in a valid Fulcio certificate for --verify=cosign. Valid values include email address, DNS names, IP addresses, and URIs. Either --cosign-certificate-identity or --cosign-certificate-identity-regexp must be set for keyless flows")
@@ -67,6 +69,8 @@ func PullCommand() *cobra.Command {
 
 	cmd.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
 
+	cmd.Flags().Int("retry", 0, "Number of times to retry on transient errors. Interrupted layer downloads are resumed")
+
 	cmd.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
 
 	return cmd
@@ -114,6 +118,14 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 		return types.ImagePullOptions{}, err
 	}
 
+	retry, err := cmd.Flags().GetInt("retry")
+	if err != nil {
+		return types.ImagePullOptions{}, err
+	}
+	if retry < 0 {
+		return types.ImagePullOptions{}, errors.New("--retry cannot be negative")
+	}
+
 	verifyOptions, err := helpers.VerifyOptions(cmd)
 	if err != nil {
 		return types.ImagePullOptions{}, err
@@ -129,6 +141,7 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 		RFlags: types.RemoteSnapshotterFlags{
 			SociIndexDigest: sociIndexDigest,
 		},
+		Retry:                  retry,
 		Stdout:                 cmd.OutOrStdout(),
 		Stderr:                 cmd.OutOrStderr(),
 		ProgressOutputToStdout: true,
@@ -17,6 +17,7 @@
 package image
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -277,6 +278,53 @@ func TestImagePullProcessOutput(t *testing.T) {
 				},
 				Expected: test.Expects(0, nil, expect.DoesNotContain(testutil.BusyboxImage)),
 			},
+			{
+				Description: "Pull Image with --progress=json - records should be in stderr",
+				Require:     require.Not(nerdtest.Docker),
+				NoParallel:  true,
+				Cleanup: func(data test.Data, helpers test.Helpers) {
+					helpers.Anyhow("rmi", "-f", testutil.BusyboxImage)
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--progress=json", "pull", testutil.BusyboxImage)
+				},
+				Expected: test.Expects(0, nil, expect.DoesNotContain(`"operation":"pull"`)),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestImagePullSnapshotterPreflight(t *testing.T) {
+	nerdtest.Setup()
+
+	// "nydus-missing" is handled as a nydus-compatible snapshotter, which is not configured in containerd
+	const snapshotter = "nydus-missing"
+
+	testCase := &test.Case{
+		Require: require.Not(nerdtest.Docker),
+		SubTests: []*test.Case{
+			{
+				Description: "Missing snapshotter fails before pulling",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--snapshotter="+snapshotter, "pull", testutil.CommonImage)
+				},
+				Expected: test.Expects(1, []error{
+					errors.New(`snapshotter "nydus-missing" is not configured in containerd`),
+					errors.New("--snapshotter-fallback"),
+				}, nil),
+			},
+			{
+				Description: "Missing snapshotter falls back to the default snapshotter",
+				NoParallel:  true,
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--snapshotter="+snapshotter, "--snapshotter-fallback", "pull", testutil.CommonImage)
+				},
+				Expected: test.Expects(0, []error{
+					errors.New("falling back to the default snapshotter"),
+				}, nil),
+			},
 		},
 	}
 
@@ -50,6 +50,9 @@ func PushCommand() *cobra.Command {
 	cmd.Flags().Bool("estargz", false, "Convert the image into eStargz")
 	cmd.Flags().Bool("ipfs-ensure-image", true, "Ensure the entire contents of the image is locally available before push")
 	cmd.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
+	cmd.Flags().String("ipfs-pin-service", "", "Endpoint of a remote pinning service implementing the IPFS Pinning Service API (e.g. https://api.pinata.cloud/psa) to pin the pushed CID to")
+	helpers.AddStringFlag(cmd, "ipfs-pin-token", nil, "", "IPFS_PIN_TOKEN", "Bearer token for the remote pinning service")
+	cmd.Flags().String("ipfs-pin-name", "", "Name of the pin on the remote pinning service (default: the image name)")
 
 	// #region sign flags
 	cmd.Flags().String("sign", "none", "Sign the image (none|cosign|notation")
@@ -69,6 +72,11 @@ func PushCommand() *cobra.Command {
 
 	cmd.Flags().Bool(allowNonDistFlag, false, "Allow pushing images with non-distributable blobs")
 
+	cmd.Flags().String("sbom", "", "Generate an SBOM (spdx-json|cyclonedx) and attach it to the pushed image as a referrer artifact")
+	cmd.RegisterFlagCompletionFunc("sbom", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"spdx-json", "cyclonedx"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	return cmd
 }
 
@@ -97,6 +105,18 @@ func pushOptions(cmd *cobra.Command) (types.ImagePushOptions, error) {
 	if err != nil {
 		return types.ImagePushOptions{}, err
 	}
+	ipfsPinService, err := cmd.Flags().GetString("ipfs-pin-service")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
+	ipfsPinToken, err := cmd.Flags().GetString("ipfs-pin-token")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
+	ipfsPinName, err := cmd.Flags().GetString("ipfs-pin-name")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
 	quiet, err := cmd.Flags().GetBool("quiet")
 	if err != nil {
 		return types.ImagePushOptions{}, err
@@ -105,6 +125,10 @@ func pushOptions(cmd *cobra.Command) (types.ImagePushOptions, error) {
 	if err != nil {
 		return types.ImagePushOptions{}, err
 	}
+	sbomFormat, err := cmd.Flags().GetString("sbom")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
 	signOptions, err := signOptions(cmd)
 	if err != nil {
 		return types.ImagePushOptions{}, err
@@ -122,9 +146,14 @@ func pushOptions(cmd *cobra.Command) (types.ImagePushOptions, error) {
 		Estargz:                        estargz,
 		IpfsEnsureImage:                ipfsEnsureImage,
 		IpfsAddress:                    ipfsAddress,
+		IpfsPinService:                 ipfsPinService,
+		IpfsPinToken:                   ipfsPinToken,
+		IpfsPinName:                    ipfsPinName,
 		Quiet:                          quiet,
 		AllowNondistributableArtifacts: allowNonDist,
+		SBOM:                           sbomFormat,
 		Stdout:                         cmd.OutOrStdout(),
+		Stderr:                         cmd.ErrOrStderr(),
 	}, nil
 }
 
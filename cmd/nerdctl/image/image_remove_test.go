@@ -522,3 +522,42 @@ func TestRemoveKubeWithKubeHideDupe(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestRemoveUntag(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+		helpers.Ensure("tag", testutil.CommonImage, data.Identifier("tag1"))
+		helpers.Ensure("tag", testutil.CommonImage, data.Identifier("tag2"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rmi", "-f", data.Identifier("tag1"), data.Identifier("tag2"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "Removing one of several tags only untags",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("rmi", data.Identifier("tag1"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains("Untagged: "),
+						expect.DoesNotContain("Deleted: "),
+						func(stdout string, t tig.T) {
+							helpers.Command("images", data.Identifier("tag2")).Run(&test.Expected{
+								Output: expect.Contains(data.Identifier("tag2")),
+							})
+						},
+					),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
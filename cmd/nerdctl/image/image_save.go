@@ -96,6 +96,7 @@ func saveAction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cowardly refusing to save to a terminal. Use the -o flag or redirect")
 	}
 	options.Stdout = output
+	options.Stderr = cmd.ErrOrStderr()
 
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
 	if err != nil {
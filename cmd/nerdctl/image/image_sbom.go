@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func sbomCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sbom [flags] IMAGE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Generate a Software Bill of Materials (SBOM) of an image",
+		Long: `Generate a Software Bill of Materials (SBOM) of an image.
+
+The packages are detected from the package databases of the image rootfs (apk and dpkg).
+Use "nerdctl push --sbom=FORMAT" to attach the SBOM to the pushed image as an OCI referrer artifact.`,
+		Example:           "  nerdctl image sbom --format=cyclonedx alpine:latest",
+		RunE:              sbomAction,
+		ValidArgsFunction: sbomShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("format", "spdx-json", "Format of the SBOM document (spdx-json|cyclonedx)")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"spdx-json", "cyclonedx"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().String("platform", "", "Scan the image for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	cmd.Flags().StringP("output", "o", "", "Write the SBOM to a file, instead of STDOUT")
+	return cmd
+}
+
+func sbomOptions(cmd *cobra.Command) (types.ImageSBOMOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageSBOMOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageSBOMOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageSBOMOptions{}, err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return types.ImageSBOMOptions{}, err
+	}
+	return types.ImageSBOMOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+		Platform: platform,
+		Output:   output,
+	}, nil
+}
+
+func sbomAction(cmd *cobra.Command, args []string) error {
+	options, err := sbomOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.SBOM(ctx, client, args[0], options)
+}
+
+func sbomShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show image names
+	return completion.ImageNames(cmd)
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestImageSBOM(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "spdx-json",
+			Command:     test.Command("image", "sbom", testutil.CommonImage),
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				var doc map[string]interface{}
+				assert.NilError(t, json.Unmarshal([]byte(stdout), &doc))
+				assert.Equal(t, doc["spdxVersion"], "SPDX-2.3")
+				assert.Assert(t, len(doc["packages"].([]interface{})) > 1)
+			}),
+		},
+		{
+			Description: "cyclonedx to file",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("image", "sbom", "--format=cyclonedx", "-o", filepath.Join(data.Temp().Path(), "sbom.json"), testutil.CommonImage)
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						b, err := os.ReadFile(filepath.Join(data.Temp().Path(), "sbom.json"))
+						assert.NilError(t, err)
+						var doc map[string]interface{}
+						assert.NilError(t, json.Unmarshal(b, &doc))
+						assert.Equal(t, doc["bomFormat"], "CycloneDX")
+					},
+				}
+			},
+		},
+		{
+			Description: "unsupported format",
+			Command:     test.Command("image", "sbom", "--format=syft", testutil.CommonImage),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func scanCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "scan [flags] IMAGE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Scan a local image for vulnerabilities",
+		Long: `Scan a local image for vulnerabilities.
+
+The scanner is configured with the "scanner" property of nerdctl.toml, or with the --scanner global flag.
+Supported scanners: trivy, grype.`,
+		Example:           "  nerdctl --scanner=trivy image scan --severity=HIGH alpine:latest",
+		RunE:              scanAction,
+		ValidArgsFunction: scanShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("format", "", "Format the output using the given Go template (applied to each vulnerability), e.g, '{{json .}}'")
+	cmd.Flags().String("severity", "", "Fail when a vulnerability of this severity or higher is found (UNKNOWN|LOW|MEDIUM|HIGH|CRITICAL)")
+	cmd.RegisterFlagCompletionFunc("severity", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringSlice("platform", []string{}, "Scan the image for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	return cmd
+}
+
+func scanOptions(cmd *cobra.Command) (types.ImageScanOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageScanOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageScanOptions{}, err
+	}
+	severity, err := cmd.Flags().GetString("severity")
+	if err != nil {
+		return types.ImageScanOptions{}, err
+	}
+	platform, err := cmd.Flags().GetStringSlice("platform")
+	if err != nil {
+		return types.ImageScanOptions{}, err
+	}
+	return types.ImageScanOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+		Severity: severity,
+		Platform: platform,
+	}, nil
+}
+
+func scanAction(cmd *cobra.Command, args []string) error {
+	options, err := scanOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Scan(ctx, client, args[0], options)
+}
+
+func scanShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show image names
+	return completion.ImageNames(cmd)
+}
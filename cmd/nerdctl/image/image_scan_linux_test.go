@@ -0,0 +1,64 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestImageScan(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "no scanner configured",
+			Command:     test.Command("--scanner=", "image", "scan", testutil.CommonImage),
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{
+				errors.New("no scanner is configured"),
+			}, nil),
+		},
+		{
+			Description: "unknown severity",
+			Command:     test.Command("--scanner=trivy", "image", "scan", "--severity=SEVERE", testutil.CommonImage),
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{
+				errors.New("unknown severity"),
+			}, nil),
+		},
+		{
+			Description: "trivy",
+			Require:     require.Binary("trivy"),
+			Command:     test.Command("--scanner=trivy", "image", "scan", testutil.CommonImage),
+			Expected:    test.Expects(0, nil, expect.Contains("SEVERITY", "CRITICAL: ")),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func squashCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:               "squash [flags] SOURCE_IMAGE TARGET_IMAGE",
+		Args:              cobra.ExactArgs(2),
+		Short:             "Collapse all the layers of an image into a single layer",
+		Example:           "  nerdctl image squash example.com/foo:latest example.com/foo:squashed",
+		RunE:              squashAction,
+		ValidArgsFunction: squashShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringP("message", "m", "", "Set the history comment of the squashed layer")
+	cmd.Flags().String("platform", "", "Squash the image for a specific platform")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	return cmd
+}
+
+func squashOptions(cmd *cobra.Command, args []string) (types.ImageSquashOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageSquashOptions{}, err
+	}
+	message, err := cmd.Flags().GetString("message")
+	if err != nil {
+		return types.ImageSquashOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageSquashOptions{}, err
+	}
+	return types.ImageSquashOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Source:   args[0],
+		Target:   args[1],
+		Platform: platform,
+		Message:  message,
+	}, nil
+}
+
+func squashAction(cmd *cobra.Command, args []string) error {
+	options, err := squashOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Squash(ctx, client, options)
+}
+
+func squashShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show image names
+	return completion.ImageNames(cmd)
+}
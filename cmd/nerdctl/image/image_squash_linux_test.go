@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestImageSquash(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--name", data.Identifier("container"), testutil.CommonImage, "sh", "-c", "echo squashed > /squash-test")
+		helpers.Ensure("commit", data.Identifier("container"), data.Identifier("committed"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("container"))
+		helpers.Anyhow("rmi", "-f", data.Identifier("committed"))
+		helpers.Anyhow("rmi", "-f", data.Identifier("squashed"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("image", "squash", data.Identifier("committed"), data.Identifier("squashed"))
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: expect.All(
+				expect.Contains("sha256:"),
+				func(stdout string, t tig.T) {
+					var layers []string
+					out := helpers.Capture("image", "inspect", "--format={{json .RootFS.Layers}}", data.Identifier("squashed"))
+					assert.NilError(t, json.Unmarshal([]byte(out), &layers))
+					assert.Equal(t, len(layers), 1)
+					helpers.Command("run", "--rm", data.Identifier("squashed"), "cat", "/squash-test").
+						Run(&test.Expected{Output: expect.Equals("squashed\n")})
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
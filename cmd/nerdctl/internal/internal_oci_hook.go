@@ -57,7 +57,7 @@ func internalOCIHookAction(cmd *cobra.Command, args []string) error {
 	cniPath := globalOptions.CNIPath
 	cniNetconfpath := globalOptions.CNINetConfPath
 	bridgeIP := globalOptions.BridgeIP
-	return ocihook.Run(os.Stdin, os.Stderr, event,
+	return ocihook.Run(cmd.Context(), os.Stdin, os.Stderr, event,
 		dataStore,
 		cniPath,
 		cniNetconfpath,
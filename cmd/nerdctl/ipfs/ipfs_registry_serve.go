@@ -43,6 +43,8 @@ func newIPFSRegistryServeCommand() *cobra.Command {
 	helpers.AddStringFlag(cmd, "ipfs-address", nil, "", "IPFS_REGISTRY_SERVE_IPFS_ADDRESS", "multiaddr of IPFS API (default is pulled from $IPFS_PATH/api file. If $IPFS_PATH env var is not present, it defaults to ~/.ipfs)")
 	helpers.AddIntFlag(cmd, "read-retry-num", nil, defaultIPFSReadRetryNum, "IPFS_REGISTRY_SERVE_READ_RETRY_NUM", "times to retry query on IPFS. Zero or lower means no retry.")
 	helpers.AddDurationFlag(cmd, "read-timeout", nil, defaultIPFSReadTimeoutDuration, "IPFS_REGISTRY_SERVE_READ_TIMEOUT", "timeout duration of a read request to IPFS. Zero means no timeout.")
+	// gateway is defined as StringSlice, not StringArray, to allow specifying "--gateway=https://ipfs.io,https://dweb.link"
+	cmd.Flags().StringSlice("gateway", nil, "HTTP gateways (e.g. https://ipfs.io) to fetch contents from when the IPFS API cannot provide them. Tried in order.")
 
 	return cmd
 }
@@ -64,11 +66,16 @@ func processIPFSRegistryServeOptions(cmd *cobra.Command) (opts types.IPFSRegistr
 	if err != nil {
 		return types.IPFSRegistryServeOptions{}, err
 	}
+	gateways, err := cmd.Flags().GetStringSlice("gateway")
+	if err != nil {
+		return types.IPFSRegistryServeOptions{}, err
+	}
 	return types.IPFSRegistryServeOptions{
 		ListenRegistry: listenAddress,
 		IPFSAddress:    ipfsAddressStr,
 		ReadTimeout:    readTimeout,
 		ReadRetryNum:   readRetryNum,
+		Gateways:       gateways,
 	}, nil
 }
 
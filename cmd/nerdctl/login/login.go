@@ -42,6 +42,13 @@ func Command() *cobra.Command {
 	cmd.Flags().StringP("username", "u", "", "Username")
 	cmd.Flags().StringP("password", "p", "", "Password")
 	cmd.Flags().Bool("password-stdin", false, "Take the password from stdin")
+	cmd.Flags().String("identity-token", "", "Identity token (an OAuth2 refresh token) to log in with, instead of the username and the password")
+	cmd.Flags().Bool("identity-token-stdin", false, "Take the identity token from stdin")
+	cmd.Flags().Bool("device-flow", false, "Log in with the OAuth2 device flow of --oauth-issuer, printing a URL and a code to confirm in a browser")
+	cmd.Flags().String("oauth-issuer", "", "Issuer URL of the OAuth2 authorization server of the registry, for --device-flow")
+	cmd.Flags().String("oauth-client-id", "", "OAuth2 client ID, for --device-flow")
+	cmd.Flags().String("oauth-scope", login.DefaultOAuthScope, "OAuth2 scope, for --device-flow")
+	cmd.Flags().Bool("list", false, "List the registries with stored credentials, and where they are stored")
 	return cmd
 }
 
@@ -88,14 +95,80 @@ func loginOptions(cmd *cobra.Command) (types.LoginCommandOptions, error) {
 		password = strings.TrimSuffix(string(contents), "\n")
 		password = strings.TrimSuffix(password, "\r")
 	}
+	identityToken, err := cmd.Flags().GetString("identity-token")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+	identityTokenStdin, err := cmd.Flags().GetBool("identity-token-stdin")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+	deviceFlow, err := cmd.Flags().GetBool("device-flow")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+	oauthIssuer, err := cmd.Flags().GetString("oauth-issuer")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+	oauthClientID, err := cmd.Flags().GetString("oauth-client-id")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+	oauthScope, err := cmd.Flags().GetString("oauth-scope")
+	if err != nil {
+		return types.LoginCommandOptions{}, err
+	}
+
+	if identityToken != "" && identityTokenStdin {
+		return types.LoginCommandOptions{}, errors.New("--identity-token and --identity-token-stdin are mutually exclusive")
+	}
+	if identityTokenStdin {
+		if passwordStdin {
+			return types.LoginCommandOptions{}, errors.New("--password-stdin and --identity-token-stdin are mutually exclusive")
+		}
+		contents, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return types.LoginCommandOptions{}, err
+		}
+		identityToken = strings.TrimSpace(string(contents))
+	}
+	if identityToken != "" || deviceFlow {
+		if password != "" {
+			return types.LoginCommandOptions{}, errors.New("--password cannot be used with an identity token or --device-flow")
+		}
+		if identityToken != "" && deviceFlow {
+			return types.LoginCommandOptions{}, errors.New("--identity-token and --device-flow are mutually exclusive")
+		}
+	}
+	if deviceFlow && oauthIssuer == "" {
+		return types.LoginCommandOptions{}, errors.New("must provide --oauth-issuer with --device-flow")
+	}
+
 	return types.LoginCommandOptions{
-		GOptions: globalOptions,
-		Username: username,
-		Password: password,
+		GOptions:      globalOptions,
+		Username:      username,
+		Password:      password,
+		IdentityToken: identityToken,
+		DeviceFlow:    deviceFlow,
+		OAuthIssuer:   oauthIssuer,
+		OAuthClientID: oauthClientID,
+		OAuthScope:    oauthScope,
 	}, nil
 }
 
 func loginAction(cmd *cobra.Command, args []string) error {
+	list, err := cmd.Flags().GetBool("list")
+	if err != nil {
+		return err
+	}
+	if list {
+		if len(args) > 0 {
+			return errors.New("--list cannot be used with SERVER")
+		}
+		return login.List(cmd.OutOrStdout())
+	}
+
 	options, err := loginOptions(cmd)
 	if err != nil {
 		return err
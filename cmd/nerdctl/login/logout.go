@@ -17,6 +17,8 @@
 package login
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 
 	"github.com/containerd/log"
@@ -25,7 +27,7 @@ import (
 )
 
 func LogoutCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:               "logout [flags] [SERVER]",
 		Args:              cobra.MaximumNArgs(1),
 		Short:             "Log out from a container registry",
@@ -34,9 +36,26 @@ func LogoutCommand() *cobra.Command {
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 	}
+	cmd.Flags().Bool("all", false, "Log out from all the registries with stored credentials")
+	return cmd
 }
 
 func logoutAction(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	if all {
+		if len(args) > 0 {
+			return errors.New("--all cannot be used with SERVER")
+		}
+		errGroup, err := logout.LogoutAll(cmd.Context(), cmd.OutOrStdout())
+		for server, v := range errGroup {
+			log.L.WithError(v).Errorf("Failed to erase credentials for: %s", server)
+		}
+		return err
+	}
+
 	logoutServer := ""
 	if len(args) > 0 {
 		logoutServer = args[0]
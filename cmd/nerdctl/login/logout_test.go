@@ -0,0 +1,79 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestLoginListAndLogoutAll(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		// "dXNlcjpwYXNz" is "user:pass"
+		data.Temp().Save(`{"auths": {
+	"registry-a.example.com": {"auth": "dXNlcjpwYXNz"},
+	"registry-b.example.com": {"identitytoken": "token"}
+}}`, "docker", "config.json")
+		data.Labels().Set("dockerConfig", data.Temp().Path("docker"))
+	}
+
+	command := func(args ...string) func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return func(data test.Data, helpers test.Helpers) test.TestableCommand {
+			cmd := helpers.Command(args...)
+			cmd.Setenv("DOCKER_CONFIG", data.Labels().Get("dockerConfig"))
+			return cmd
+		}
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "login --list",
+			NoParallel:  true,
+			Command:     command("login", "--list"),
+			Expected: test.Expects(0, nil, expect.Contains(
+				"SERVER", "registry-a.example.com", "user", "password",
+				"registry-b.example.com", "identity token",
+			)),
+		},
+		{
+			Description: "logout --all",
+			NoParallel:  true,
+			Command:     command("logout", "--all"),
+			Expected: test.Expects(0, nil, expect.Contains(
+				"Removing login credentials for registry-a.example.com",
+				"Removing login credentials for registry-b.example.com",
+			)),
+		},
+		{
+			Description: "login --list after logout --all",
+			NoParallel:  true,
+			Command:     command("login", "--list"),
+			Expected:    test.Expects(0, nil, expect.DoesNotContain("registry-a.example.com", "registry-b.example.com")),
+		},
+	}
+
+	testCase.Run(t)
+}
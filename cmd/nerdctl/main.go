@@ -19,8 +19,10 @@ package main
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
@@ -31,10 +33,13 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/builder"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/bundle"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/checkpoint"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/compose"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/container"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/context"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/generate"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/image"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/inspect"
@@ -44,14 +49,18 @@ import (
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/manifest"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/namespace"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/network"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/plugin"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/registry"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/system"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/volume"
 	"github.com/containerd/nerdctl/v2/pkg/config"
-	ncdefaults "github.com/containerd/nerdctl/v2/pkg/defaults"
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 	"github.com/containerd/nerdctl/v2/pkg/version"
 )
 
@@ -144,7 +153,9 @@ func xmain() error {
 	if err != nil {
 		return err
 	}
-	return app.Execute()
+	err = app.Execute()
+	tracingutil.Shutdown(err)
+	return err
 }
 
 func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet, error) {
@@ -168,13 +179,14 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 	rootCmd.PersistentFlags().Bool("debug", cfg.Debug, "debug mode")
 	rootCmd.PersistentFlags().Bool("debug-full", cfg.DebugFull, "debug mode (with full output)")
 	// -a is aliases (conflicts with nerdctl images -a)
-	helpers.AddPersistentStringFlag(rootCmd, "address", []string{"a", "H"}, nil, []string{"host"}, aliasToBeInherited, cfg.Address, "CONTAINERD_ADDRESS", `containerd address, optionally with "unix://" prefix`)
+	helpers.AddPersistentStringFlag(rootCmd, "address", []string{"a", "H"}, nil, []string{"host"}, aliasToBeInherited, cfg.Address, "CONTAINERD_ADDRESS", `containerd address, optionally with "unix://" prefix, or "ssh://[USER@]HOST[:PORT][/PATH/TO/containerd.sock]" for containerd on a remote host`)
 	// -n is aliases (conflicts with nerdctl logs -n)
 	helpers.AddPersistentStringFlag(rootCmd, "namespace", []string{"n"}, nil, nil, aliasToBeInherited, cfg.Namespace, "CONTAINERD_NAMESPACE", `containerd namespace, such as "moby" for Docker, "k8s.io" for Kubernetes`)
 	rootCmd.RegisterFlagCompletionFunc("namespace", completion.NamespaceNames)
 	helpers.AddPersistentStringFlag(rootCmd, "snapshotter", nil, nil, []string{"storage-driver"}, aliasToBeInherited, cfg.Snapshotter, "CONTAINERD_SNAPSHOTTER", "containerd snapshotter")
 	rootCmd.RegisterFlagCompletionFunc("snapshotter", completion.SnapshotterNames)
 	rootCmd.RegisterFlagCompletionFunc("storage-driver", completion.SnapshotterNames)
+	rootCmd.PersistentFlags().Bool("snapshotter-fallback", cfg.SnapshotterFallback, "Fall back to the default snapshotter with a warning, when the remote snapshotter (stargz, nydus, soci, etc.) is not available")
 	helpers.AddPersistentStringFlag(rootCmd, "cni-path", nil, nil, nil, aliasToBeInherited, cfg.CNIPath, "CNI_PATH", "cni plugins binary directory")
 	helpers.AddPersistentStringFlag(rootCmd, "cni-netconfpath", nil, nil, nil, aliasToBeInherited, cfg.CNINetConfPath, "NETCONFPATH", "cni config directory")
 	rootCmd.PersistentFlags().String("data-root", cfg.DataRoot, "Root directory of persistent nerdctl state (managed by nerdctl, not by containerd)")
@@ -183,6 +195,7 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 	rootCmd.PersistentFlags().Bool("insecure-registry", cfg.InsecureRegistry, "skips verifying HTTPS certs, and allows falling back to plain HTTP")
 	// hosts-dir is defined as StringSlice, not StringArray, to allow specifying "--hosts-dir=/etc/containerd/certs.d,/etc/docker/certs.d"
 	rootCmd.PersistentFlags().StringSlice("hosts-dir", cfg.HostsDir, "A directory that contains <HOST:PORT>/hosts.toml (containerd style) or <HOST:PORT>/{ca.cert, cert.pem, key.pem} (docker style)")
+	rootCmd.PersistentFlags().Bool("no-token-cache", cfg.NoTokenCache, "Do not cache the bearer tokens of the registries across the commands")
 	// Experimental enable experimental feature, see in https://github.com/containerd/nerdctl/blob/main/docs/experimental.md
 	helpers.AddPersistentBoolFlag(rootCmd, "experimental", nil, nil, cfg.Experimental, "NERDCTL_EXPERIMENTAL", "Control experimental: https://github.com/containerd/nerdctl/blob/main/docs/experimental.md")
 	helpers.AddPersistentStringFlag(rootCmd, "host-gateway-ip", nil, nil, nil, aliasToBeInherited, cfg.HostGatewayIP, "NERDCTL_HOST_GATEWAY_IP", "IP address that the special 'host-gateway' string in --add-host resolves to. Defaults to the IP address of the host. It has no effect without setting --add-host")
@@ -190,17 +203,77 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 	rootCmd.PersistentFlags().Bool("kube-hide-dupe", cfg.KubeHideDupe, "Deduplicate images for Kubernetes with namespace k8s.io")
 	rootCmd.PersistentFlags().StringSlice("cdi-spec-dirs", cfg.CDISpecDirs, "The directories to search for CDI spec files. Defaults to /etc/cdi,/var/run/cdi")
 	rootCmd.PersistentFlags().String("userns-remap", cfg.UsernsRemap, "Support idmapping for creating and running containers. This options is only supported on linux. If `host` is passed, no idmapping is done. if a user name is passed, it does idmapping based on the uidmap and gidmap ranges specified in /etc/subuid and /etc/subgid respectively")
+	rootCmd.PersistentFlags().String("scanner", cfg.Scanner, "Vulnerability scanner used by `nerdctl image scan` and `--verify=scan` (trivy|grype)")
+	rootCmd.PersistentFlags().String("scan-severity", cfg.ScanSeverity, "Lowest vulnerability severity that fails `--verify=scan` (UNKNOWN|LOW|MEDIUM|HIGH|CRITICAL)")
+	rootCmd.PersistentFlags().String("seccomp-profile", cfg.SeccompProfile, "Default seccomp profile of the containers (a JSON file path, \"builtin\", or \"builtin:<VARIANT>\")")
+	helpers.AddPersistentStringFlag(rootCmd, "context", nil, nil, nil, aliasToBeInherited, "", "NERDCTL_CONTEXT", "Name of the context to use, overriding the one selected with \"nerdctl context use\"")
+	rootCmd.RegisterFlagCompletionFunc("context", context.NameComplete)
+	helpers.AddPersistentStringFlag(rootCmd, "otel-endpoint", nil, nil, nil, aliasToBeInherited, cfg.OTelEndpoint, "NERDCTL_OTEL_ENDPOINT", "OTLP/HTTP endpoint (e.g. \"http://localhost:4318\") to export the traces of the command to. The OTEL_EXPORTER_OTLP_ENDPOINT environment variable is honored too")
+	helpers.AddPersistentStringFlag(rootCmd, "progress", nil, nil, nil, aliasToBeInherited, cfg.Progress, "NERDCTL_PROGRESS", "Progress output of pull, push, build, save, load, and compose (auto|plain|json|quiet). \"json\" prints a JSON record per line to stderr")
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns", cfg.DNS, "Global DNS servers for containers")
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns-opts", cfg.DNSOpts, "Global DNS options for containers")
 	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-dns-search", cfg.DNSSearch, "Global DNS search domains for containers")
+	helpers.HiddenPersistentStringArrayFlag(rootCmd, "global-default-capabilities", cfg.DefaultCapabilities, "Default capabilities of the containers")
+	rootCmd.PersistentFlags().String("global-log-driver", cfg.Logging.Driver, "Default logging driver for containers")
+	rootCmd.PersistentFlags().MarkHidden("global-log-driver")
+	// global-log-opts needs to be StringArray, not StringSlice, to prevent "env=os,customer" from being split
+	var globalLogOpts []string
+	for _, k := range slices.Sorted(maps.Keys(cfg.Logging.Opts)) {
+		globalLogOpts = append(globalLogOpts, k+"="+cfg.Logging.Opts[k])
+	}
+	rootCmd.PersistentFlags().StringArray("global-log-opts", globalLogOpts, "Default logging driver options for containers")
+	rootCmd.PersistentFlags().MarkHidden("global-log-opts")
+	rootCmd.PersistentFlags().String("global-verify", cfg.Verify.Provider, "Default image verification of containers")
+	rootCmd.PersistentFlags().MarkHidden("global-verify")
+	rootCmd.PersistentFlags().String("global-cosign-key", cfg.Verify.CosignKey, "Default public key for verifying images of containers with cosign")
+	rootCmd.PersistentFlags().MarkHidden("global-cosign-key")
+	// global-runtimes is the [runtimes] tables of nerdctl.toml, re-encoded in TOML as they are nested
+	var globalRuntimes []byte
+	if len(cfg.Runtimes) > 0 {
+		var err error
+		if globalRuntimes, err = toml.Marshal(cfg.Runtimes); err != nil {
+			return nil, err
+		}
+	}
+	rootCmd.PersistentFlags().String("global-runtimes", string(globalRuntimes), "Named runtime configurations")
+	rootCmd.PersistentFlags().MarkHidden("global-runtimes")
+	// global-quotas is the [quotas] tables of nerdctl.toml, re-encoded in TOML as well
+	var globalQuotas []byte
+	if len(cfg.Quotas) > 0 {
+		var err error
+		if globalQuotas, err = toml.Marshal(cfg.Quotas); err != nil {
+			return nil, err
+		}
+	}
+	rootCmd.PersistentFlags().String("global-quotas", string(globalQuotas), "Disk quotas of the namespaces")
+	rootCmd.PersistentFlags().MarkHidden("global-quotas")
+	// global-registries is the [registries] tables of nerdctl.toml, re-encoded in TOML as well
+	var globalRegistries []byte
+	if len(cfg.Registries) > 0 {
+		var err error
+		if globalRegistries, err = toml.Marshal(cfg.Registries); err != nil {
+			return nil, err
+		}
+	}
+	rootCmd.PersistentFlags().String("global-registries", string(globalRegistries), "TLS client configurations of the registries")
+	rootCmd.PersistentFlags().MarkHidden("global-registries")
+	rootCmd.PersistentFlags().String("global-default-network", cfg.DefaultNetwork, "Default network of containers")
+	rootCmd.PersistentFlags().MarkHidden("global-default-network")
+	// global-namespaces is the [namespaces] tables of nerdctl.toml, re-encoded in TOML as well
+	var globalNamespaces []byte
+	if len(cfg.Namespaces) > 0 {
+		var err error
+		if globalNamespaces, err = toml.Marshal(cfg.Namespaces); err != nil {
+			return nil, err
+		}
+	}
+	rootCmd.PersistentFlags().String("global-namespaces", string(globalNamespaces), "Per-namespace configurations")
+	rootCmd.PersistentFlags().MarkHidden("global-namespaces")
 	return aliasToBeInherited, nil
 }
 
 func newApp() (*cobra.Command, error) {
-	tomlPath := ncdefaults.NerdctlTOML()
-	if v, ok := os.LookupEnv("NERDCTL_TOML"); ok {
-		tomlPath = v
-	}
+	tomlPath := helpers.NerdctlTOML()
 
 	short := "nerdctl is a command line interface for containerd"
 	long := fmt.Sprintf(`%s
@@ -228,6 +301,14 @@ Config file ($NERDCTL_TOML): %s
 	}
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		nerdctlContext, err := context.Apply(cmd)
+		if err != nil {
+			return err
+		}
+		// applied after the context, which may switch the namespace
+		if err := helpers.ApplyNamespaceConfig(cmd); err != nil {
+			return err
+		}
 		globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 		if err != nil {
 			return err
@@ -239,8 +320,17 @@ Config file ($NERDCTL_TOML): %s
 		if debug {
 			log.SetLevel(log.DebugLevel.String())
 		}
+		if nerdctlContext != nil && nerdctlContext.SSHHost != "" && !plugin.IsPluginCommand(cmd) {
+			// the whole command runs on the remote host instead.
+			// The plugins run locally, and their nerdctl commands run on the remote host with $NERDCTL_CONTEXT.
+			return context.RunRemote(cmd, nerdctlContext)
+		}
 		address := globalOptions.Address
-		if strings.Contains(address, "://") && !strings.HasPrefix(address, "unix://") {
+		if sshutil.IsAddress(address) {
+			if _, err := sshutil.ParseAddress(address); err != nil {
+				return err
+			}
+		} else if strings.Contains(address, "://") && !strings.HasPrefix(address, "unix://") {
 			return fmt.Errorf("invalid address %q", address)
 		}
 		cgroupManager := globalOptions.CgroupManager
@@ -252,6 +342,14 @@ Config file ($NERDCTL_TOML): %s
 			}
 		}
 
+		if err := jobs.ValidateProgressMode(globalOptions.Progress); err != nil {
+			return err
+		}
+		if globalOptions.Progress == jobs.ProgressJSON {
+			// keep stderr machine-readable
+			log.SetFormat(log.JSONFormat)
+		}
+
 		// Since we store containers' stateful information on the filesystem per namespace, we need namespaces to be
 		// valid, safe path segments.
 		// Note that the container runtime will further enforce additional restrictions on namespace names
@@ -261,10 +359,21 @@ Config file ($NERDCTL_TOML): %s
 		if err = store.IsFilesystemSafe(globalOptions.Namespace); err != nil {
 			return err
 		}
-		if appNeedsRootlessParentMain(cmd, args) {
+		// containerd on the SSH host does not need the local RootlessKit
+		if !sshutil.IsAddress(address) && appNeedsRootlessParentMain(cmd, args) {
+			if err := startBypass4netnsdIfNeeded(cmd); err != nil {
+				return err
+			}
 			// reexec /proc/self/exe with `nsenter` into RootlessKit namespaces
 			return rootlessutil.ParentMain(globalOptions.HostGatewayIP)
 		}
+		// The spans of the containerd client are recorded under the root span of the command too
+		ctx, err := tracingutil.Init(cmd.Context(), globalOptions.OTelEndpoint, cmd.CommandPath())
+		if err != nil {
+			log.L.WithError(err).Warn("failed to set up the traces")
+			return nil
+		}
+		cmd.SetContext(ctx)
 		return nil
 	}
 	rootCmd.RunE = helpers.UnknownSubcommandAction
@@ -274,6 +383,7 @@ Config file ($NERDCTL_TOML): %s
 		container.RunCommand(),
 		container.UpdateCommand(),
 		container.ExecCommand(),
+		container.DebugCommand(),
 		// #endregion
 
 		// #region Container management
@@ -298,6 +408,7 @@ Config file ($NERDCTL_TOML): %s
 
 		// Build
 		builder.BuildCommand(),
+		builder.BakeCommand(),
 
 		// #region Image management
 		image.ImagesCommand(),
@@ -309,6 +420,7 @@ Config file ($NERDCTL_TOML): %s
 		image.TagCommand(),
 		image.RmiCommand(),
 		image.HistoryCommand(),
+		registry.SearchCommand(),
 		// #endregion
 
 		// #region System
@@ -332,6 +444,9 @@ Config file ($NERDCTL_TOML): %s
 		system.Command(),
 		namespace.Command(),
 		builder.Command(),
+		registry.Command(),
+		context.Command(),
+		plugin.Command(),
 		// #endregion
 
 		// Internal
@@ -346,6 +461,12 @@ Config file ($NERDCTL_TOML): %s
 		// Compose
 		compose.Command(),
 
+		// Bundle
+		bundle.Command(),
+
+		// Generate
+		generate.Command(),
+
 		// IPFS
 		ipfs.NewIPFSCommand(),
 
@@ -357,6 +478,7 @@ Config file ($NERDCTL_TOML): %s
 	)
 	addApparmorCommand(rootCmd)
 	container.AddCpCommand(rootCmd)
+	plugin.AddRunCommand(rootCmd, os.Args[1:])
 
 	// add aliasToBeInherited to subCommand(s) InheritedFlags
 	for _, subCmd := range rootCmd.Commands() {
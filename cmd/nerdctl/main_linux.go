@@ -21,6 +21,8 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/apparmor"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/plugin"
+	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -38,11 +40,15 @@ func appNeedsRootlessParentMain(cmd *cobra.Command, args []string) bool {
 	if len(commands) < 2 {
 		return true
 	}
+	// CLI plugins: false, because the nerdctl commands run by the plugins enter the namespaces by themselves
+	if plugin.IsPluginCommand(cmd) {
+		return false
+	}
 	switch commands[1] {
-	// completion, login, logout, version: false, because it shouldn't require the daemon to be running
+	// completion, login, logout, version, plugin: false, because it shouldn't require the daemon to be running
 	// apparmor: false, because it requires the initial mount namespace to access /sys/kernel/security
 	// cp, compose cp: false, because it requires the initial mount namespace to inspect file owners
-	case "", "completion", "login", "logout", "apparmor", "cp", "version":
+	case "", "completion", "login", "logout", "apparmor", "cp", "version", "plugin":
 		return false
 	case "container":
 		if len(commands) < 3 {
@@ -60,10 +66,32 @@ func appNeedsRootlessParentMain(cmd *cobra.Command, args []string) bool {
 		case "cp":
 			return false
 		}
+	case "system":
+		if len(commands) < 3 {
+			return true
+		}
+		switch commands[2] {
+		// check-rootless: false, because it has to work even when rootless containerd is not running
+		case "check-rootless":
+			return false
+		}
 	}
 	return true
 }
 
+// startBypass4netnsdIfNeeded starts bypass4netnsd for `nerdctl run --bypass4netns` and `nerdctl create --bypass4netns`.
+// bypass4netnsd has to be started here, in the namespaces of the host, before reexecuting nerdctl in the RootlessKit namespaces.
+func startBypass4netnsdIfNeeded(cmd *cobra.Command) error {
+	if f := cmd.Flags().Lookup("bypass4netns"); f == nil || !f.Changed {
+		return nil
+	}
+	enabled, err := cmd.Flags().GetBool("bypass4netns")
+	if err != nil || !enabled {
+		return err
+	}
+	return bypass4netnsutil.StartBypass4netnsd(cmd.Context())
+}
+
 func addApparmorCommand(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(apparmor.Command())
 }
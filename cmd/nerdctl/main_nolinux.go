@@ -26,6 +26,10 @@ func appNeedsRootlessParentMain(cmd *cobra.Command, args []string) bool {
 	return false
 }
 
+func startBypass4netnsdIfNeeded(cmd *cobra.Command) error {
+	return nil
+}
+
 func addApparmorCommand(rootCmd *cobra.Command) {
 	// NOP
 }
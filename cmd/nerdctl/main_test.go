@@ -119,6 +119,31 @@ func TestNerdctlConfig(t *testing.T) {
 			Expected:    test.Expects(0, nil, expect.Equals("dummy-snapshotter-via-cli\n")),
 			Config:      test.WithConfig(nerdtest.NerdctlToml, `snapshotter = "dummy-snapshotter-via-toml"`),
 		},
+		{
+			Description: "Namespace TOML > TOML > Default",
+			Command:     test.Command("info", "-f", "{{.Driver}}", "--namespace=nerdctl-test-ns-config"),
+			Expected:    test.Expects(0, nil, expect.Equals("dummy-snapshotter-via-ns-toml\n")),
+			Config: test.WithConfig(nerdtest.NerdctlToml, `snapshotter = "dummy-snapshotter-via-toml"
+[namespaces."nerdctl-test-ns-config"]
+snapshotter = "dummy-snapshotter-via-ns-toml"`),
+		},
+		{
+			Description: "Namespace TOML of another namespace",
+			Command:     test.Command("info", "-f", "{{.Driver}}", "--namespace=nerdctl-test-ns-config-other"),
+			Expected:    test.Expects(0, nil, expect.Equals("dummy-snapshotter-via-toml\n")),
+			Config: test.WithConfig(nerdtest.NerdctlToml, `snapshotter = "dummy-snapshotter-via-toml"
+[namespaces."nerdctl-test-ns-config"]
+snapshotter = "dummy-snapshotter-via-ns-toml"`),
+		},
+		{
+			Description: "Env > Namespace TOML > TOML > Default",
+			Command:     test.Command("info", "-f", "{{.Driver}}", "--namespace=nerdctl-test-ns-config"),
+			Env:         map[string]string{"CONTAINERD_SNAPSHOTTER": "dummy-snapshotter-via-env"},
+			Expected:    test.Expects(0, nil, expect.Equals("dummy-snapshotter-via-env\n")),
+			Config: test.WithConfig(nerdtest.NerdctlToml, `snapshotter = "dummy-snapshotter-via-toml"
+[namespaces."nerdctl-test-ns-config"]
+snapshotter = "dummy-snapshotter-via-ns-toml"`),
+		},
 		{
 			Description: "Broken config",
 			Command:     test.Command("info"),
@@ -40,6 +40,7 @@ func inspectCommand() *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	cmd.Flags().Bool("usage", false, "Report the disk usage of the images, the snapshots, and the containers of the namespaces")
 	return cmd
 }
 
@@ -52,10 +53,15 @@ func inspectOptions(cmd *cobra.Command) (types.NamespaceInspectOptions, error) {
 	if err != nil {
 		return types.NamespaceInspectOptions{}, err
 	}
+	usage, err := cmd.Flags().GetBool("usage")
+	if err != nil {
+		return types.NamespaceInspectOptions{}, err
+	}
 	return types.NamespaceInspectOptions{
 		GOptions: globalOptions,
 		Format:   format,
 		Stdout:   cmd.OutOrStdout(),
+		Usage:    usage,
 	}, nil
 }
 
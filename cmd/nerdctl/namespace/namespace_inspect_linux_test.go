@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package namespace
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestNamespaceInspectUsage(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", testutil.CommonImage)
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("namespace", "inspect", "--usage", "--format", "{{json .Usage}}", testutil.Namespace)
+	}
+
+	testCase.Expected = test.Expects(expect.ExitCodeSuccess, nil, expect.Contains(`"Images":{"Count":`, `"Snapshots":{"Count":`, `"Containers":{"Count":`))
+
+	testCase.Run(t)
+}
+
+func TestNamespaceQuota(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Config = test.WithConfig(nerdtest.NerdctlToml, test.ConfigValue(fmt.Sprintf(`[quotas.%s]
+disk = "1KiB"
+enforce = true`, testutil.Namespace)))
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", testutil.CommonImage)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the quota is reported by inspect",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("namespace", "inspect", "--usage", "--format", "{{.Usage.Quota}}", testutil.Namespace)
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("1024\n")),
+		},
+		{
+			Description: "creating a container fails when the quota is enforced",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("create", "--name", data.Identifier(), testutil.CommonImage)
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("exceeding its disk quota")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
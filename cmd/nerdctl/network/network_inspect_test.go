@@ -236,6 +236,39 @@ func TestNetworkInspect(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "bridge interface",
+			Require: require.All(
+				require.Linux,
+				// Docker does not report the bridge interface
+				require.Not(nerdtest.Docker),
+			),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "create", "--subnet", "10.24.25.0/24", data.Identifier())
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), "--network", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+				nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+				helpers.Anyhow("network", "rm", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "inspect", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						var dc []dockercompat.Network
+						err := json.Unmarshal([]byte(stdout), &dc)
+						assert.NilError(t, err, "Unable to unmarshal output\n")
+						assert.Equal(t, 1, len(dc), "Unexpectedly got multiple results\n")
+						assert.Assert(t, dc[0].Interface != nil, "the bridge interface is not reported")
+						assert.Assert(t, strings.HasPrefix(dc[0].Interface.Name, "br-"), dc[0].Interface.Name)
+						assert.Assert(t, strings.Contains(strings.Join(dc[0].Interface.Addrs, " "), "10.24.25.1/24"), dc[0].Interface.Addrs)
+					},
+				}
+			},
+		},
 		{
 			Description: "with namespace",
 			Require:     require.Not(nerdtest.Docker),
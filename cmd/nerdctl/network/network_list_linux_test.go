@@ -112,6 +112,22 @@ func TestNetworkLsFilter(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "table format with label",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "ls", "--filter", "name="+data.Labels().Get("net1"), "--format", `table {{.Name}}\t{{.Label "mylabel"}}`)
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						var lines = strings.Split(strings.TrimSpace(stdout), "\n")
+						assert.Equal(t, len(lines), 2, stdout)
+						assert.DeepEqual(t, strings.Fields(lines[0]), []string{"NAME", "LABEL"})
+						assert.DeepEqual(t, strings.Fields(lines[1]), []string{data.Labels().Get("net1"), "label-1"})
+					},
+				}
+			},
+		},
 	}
 
 	testCase.Run(t)
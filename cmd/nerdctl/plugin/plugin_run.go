@@ -0,0 +1,130 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package plugin
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/context"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/plugin"
+)
+
+// annotation marks the commands that run CLI plugins.
+const annotation = "nerdctl.plugin"
+
+// reservedNames are the commands added by cobra itself on execution.
+var reservedNames = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// AddRunCommand adds the command that runs the CLI plugin, if args (os.Args[1:]) run a plugin rather than
+// a built-in command.
+// It has to be called after all the built-in commands are added to rootCmd.
+func AddRunCommand(rootCmd *cobra.Command, args []string) {
+	name := commandName(rootCmd, args)
+	if name == "" || slices.Contains(reservedNames, name) || slices.Contains(builtinNames(rootCmd), name) {
+		return
+	}
+	path, err := plugin.Lookup(name)
+	if err != nil {
+		return
+	}
+	rootCmd.AddCommand(&cobra.Command{
+		Annotations: map[string]string{annotation: path},
+		Use:         name,
+		Short:       "Run the CLI plugin " + path,
+		// the flags following the name are passed to the plugin
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAction(cmd, path, args)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	})
+}
+
+// IsPluginCommand returns whether cmd runs a CLI plugin.
+func IsPluginCommand(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[annotation]
+	return ok
+}
+
+func runAction(cmd *cobra.Command, path string, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	nerdctlContext, err := context.Name(cmd)
+	if err != nil {
+		return err
+	}
+	return plugin.Run(cmd.Context(), types.PluginRunOptions{
+		Stdin:       cmd.InOrStdin(),
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		Path:        path,
+		Args:        args,
+		Context:     nerdctlContext,
+		NerdctlTOML: helpers.NerdctlTOML(),
+	})
+}
+
+// builtinNames returns the names and the aliases of the built-in commands.
+func builtinNames(rootCmd *cobra.Command) []string {
+	var names []string
+	for _, c := range rootCmd.Commands() {
+		if IsPluginCommand(c) {
+			continue
+		}
+		names = append(names, c.Name())
+		names = append(names, c.Aliases...)
+	}
+	return names
+}
+
+// commandName returns the first argument that is neither a global flag nor its value, in the same way as
+// cobra.Command.Traverse.
+func commandName(rootCmd *cobra.Command, args []string) string {
+	// the aliases of the global flags are local to rootCmd, see helpers.AddPersistentStringFlag
+	flags := pflag.NewFlagSet(rootCmd.Name(), pflag.ContinueOnError)
+	flags.AddFlagSet(rootCmd.Flags())
+	flags.AddFlagSet(rootCmd.PersistentFlags())
+	inFlag := false
+	for _, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case strings.HasPrefix(arg, "--") && !strings.Contains(arg, "="):
+			f := flags.Lookup(arg[2:])
+			inFlag = f == nil || f.NoOptDefVal == ""
+		case strings.HasPrefix(arg, "-") && len(arg) == 2:
+			f := flags.ShorthandLookup(arg[1:])
+			inFlag = f == nil || f.NoOptDefVal == ""
+		case inFlag:
+			inFlag = false
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return arg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestMain(m *testing.M) {
+	testutil.M(m)
+}
+
+func TestPlugin(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		// the plugin is a shell script
+		require.Not(require.Windows),
+	)
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		script := data.Temp().Save("#!/bin/sh\necho \"$CONTAINERD_NAMESPACE $*\"\nexit 42\n", "bin", "nerdctl-"+data.Identifier())
+		assert.NilError(helpers.T(), os.Chmod(script, 0o755))
+		data.Labels().Set("name", data.Identifier())
+		data.Labels().Set("path", filepath.Dir(script)+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "run",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("--namespace", "plugin-test", data.Labels().Get("name"), "--namespace", "arg")
+				cmd.Setenv("PATH", data.Labels().Get("path"))
+				return cmd
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: 42,
+					Output:   expect.Equals("plugin-test --namespace arg\n"),
+				}
+			},
+		},
+		{
+			Description: "ls",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("plugin", "ls", "--quiet")
+				cmd.Setenv("PATH", data.Labels().Get("path"))
+				return cmd
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(data.Labels().Get("name") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "not found",
+			Command:     test.Command("nonexistent-plugin"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("unknown subcommand")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,108 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest/registry"
+)
+
+func TestRegistryReposAndTags(t *testing.T) {
+	nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase := &test.Case{
+		Require: require.All(
+			require.Linux,
+			require.Not(nerdtest.Docker),
+			nerdtest.Registry,
+		),
+
+		Setup: func(data test.Data, helpers test.Helpers) {
+			reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+			reg.Setup(data, helpers)
+
+			host := fmt.Sprintf("%s:%d", reg.IP.String(), reg.Port)
+			data.Labels().Set("host", host)
+			data.Labels().Set("repo", host+"/"+data.Identifier())
+			helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+			for _, tag := range []string{"1.0", "2.0"} {
+				helpers.Ensure("tag", testutil.CommonImage, data.Labels().Get("repo")+":"+tag)
+				helpers.Ensure("push", "--insecure-registry", data.Labels().Get("repo")+":"+tag)
+			}
+		},
+
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			if data.Labels().Get("repo") != "" {
+				helpers.Anyhow("rmi", "-f", data.Labels().Get("repo")+":1.0", data.Labels().Get("repo")+":2.0")
+			}
+			if reg != nil {
+				reg.Cleanup(data, helpers)
+			}
+		},
+
+		SubTests: []*test.Case{
+			{
+				Description: "repos",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--insecure-registry", "registry", "repos", data.Labels().Get("host"))
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: expect.Contains("REPOSITORY", data.Identifier()),
+					}
+				},
+			},
+			{
+				Description: "tags",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--insecure-registry", "registry", "tags", "--format", "{{.Tag}}", data.Labels().Get("repo"))
+				},
+				Expected: test.Expects(0, nil, expect.Equals("1.0\n2.0\n")),
+			},
+			{
+				Description: "tags with limit",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--insecure-registry", "registry", "tags", "--format", "{{.Tag}}", "--limit", "1", data.Labels().Get("repo"))
+				},
+				Expected: test.Expects(0, nil, expect.Equals("1.0\n")),
+			},
+			{
+				Description: "search the catalog",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--insecure-registry", "search", "--format", "{{.Name}}", data.Labels().Get("repo"))
+				},
+				Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+					return &test.Expected{
+						Output: expect.Equals(data.Labels().Get("repo") + "\n"),
+					}
+				},
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
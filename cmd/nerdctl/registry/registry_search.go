@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/registry"
+)
+
+func SearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "search [flags] TERM",
+		Short:         "Search Docker Hub or a registry for images",
+		Long:          "Search Docker Hub for images, or a registry when TERM is prefixed with its host (e.g., registry.example.com/foo)",
+		Args:          helpers.IsExactArgs(1),
+		RunE:          searchAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringSliceP("filter", "f", nil, "Filter output based on conditions provided (stars=<N>, is-official=<BOOL>, is-automated=<BOOL>)")
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Int("limit", registry.DefaultSearchLimit, "Max number of search results")
+	cmd.Flags().Bool("no-trunc", false, "Don't truncate output")
+	return cmd
+}
+
+func searchOptions(cmd *cobra.Command) (types.SearchOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SearchOptions{}, err
+	}
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.SearchOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.SearchOptions{}, err
+	}
+	limit, err := cmd.Flags().GetInt("limit")
+	if err != nil {
+		return types.SearchOptions{}, err
+	}
+	noTrunc, err := cmd.Flags().GetBool("no-trunc")
+	if err != nil {
+		return types.SearchOptions{}, err
+	}
+	return types.SearchOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Filters:  filters,
+		Format:   format,
+		Limit:    limit,
+		NoTrunc:  noTrunc,
+	}, nil
+}
+
+func searchAction(cmd *cobra.Command, args []string) error {
+	options, err := searchOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return registry.Search(cmd.Context(), args[0], options)
+}
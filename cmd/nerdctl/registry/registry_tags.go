@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/registry"
+)
+
+func tagsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "tags [flags] REPOSITORY",
+		Short:         "List the tags of a repository",
+		Args:          helpers.IsExactArgs(1),
+		RunE:          tagsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Int("limit", 0, "Maximum number of tags to list (0 for all of them)")
+	return cmd
+}
+
+func tagsOptions(cmd *cobra.Command) (types.RegistryTagsOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.RegistryTagsOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.RegistryTagsOptions{}, err
+	}
+	limit, err := cmd.Flags().GetInt("limit")
+	if err != nil {
+		return types.RegistryTagsOptions{}, err
+	}
+	return types.RegistryTagsOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+		Limit:    limit,
+	}, nil
+}
+
+func tagsAction(cmd *cobra.Command, args []string) error {
+	options, err := tagsOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return registry.Tags(cmd.Context(), args[0], options)
+}
@@ -35,7 +35,14 @@ func Command() *cobra.Command {
 	cmd.AddCommand(
 		EventsCommand(),
 		InfoCommand(),
+		dfCommand(),
 		pruneCommand(),
+		gcCommand(),
+		metricsCommand(),
+		checkRootlessCommand(),
+		bypass4netnsCommand(),
+		supervisorCommand(),
+		rateLimitCommand(),
 	)
 	return cmd
 }
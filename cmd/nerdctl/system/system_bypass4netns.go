@@ -0,0 +1,61 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func bypass4netnsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "bypass4netns",
+		Short:         "Manage bypass4netns, the accelerator for rootless networking",
+		RunE:          helpers.UnknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(bypass4netnsStatusCommand())
+	return cmd
+}
+
+func bypass4netnsStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "status [flags]",
+		Short:         "Show the containers and the connections accelerated by bypass4netns",
+		Args:          cobra.NoArgs,
+		RunE:          bypass4netnsStatusAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	return cmd
+}
+
+func bypass4netnsStatusAction(cmd *cobra.Command, _ []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	return system.Bypass4netnsStatus(cmd.Context(), types.SystemBypass4netnsStatusOptions{
+		Stdout: cmd.OutOrStdout(),
+		Format: format,
+	})
+}
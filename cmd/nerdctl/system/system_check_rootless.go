@@ -0,0 +1,60 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func checkRootlessCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check-rootless [flags]",
+		Short: "Check the requirements of rootless mode",
+		Long: `Check the requirements of rootless mode: cgroup v2 with the cgroup controllers delegated by systemd,
+newuidmap and newgidmap, and the subordinate IDs of the user in /etc/subuid and /etc/subgid.
+Whether ports below 1024 can be published is checked too.
+
+Use '--fix' with sudo to write the systemd drop-in for the cgroup delegation.
+Use '--fix --privileged-ports' with sudo to also grant CAP_NET_BIND_SERVICE to rootlesskit.`,
+		Args:          cobra.NoArgs,
+		RunE:          checkRootlessAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("fix", false, "Write the systemd drop-ins needed for the cgroup delegation (requires root)")
+	cmd.Flags().Bool("privileged-ports", false, "With --fix, grant CAP_NET_BIND_SERVICE to rootlesskit so that ports below 1024 can be published")
+	return cmd
+}
+
+func checkRootlessAction(cmd *cobra.Command, _ []string) error {
+	fix, err := cmd.Flags().GetBool("fix")
+	if err != nil {
+		return err
+	}
+	privilegedPorts, err := cmd.Flags().GetBool("privileged-ports")
+	if err != nil {
+		return err
+	}
+	return system.CheckRootless(types.SystemCheckRootlessOptions{
+		Stdout:          cmd.OutOrStdout(),
+		Fix:             fix,
+		PrivilegedPorts: privilegedPorts,
+	})
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestSystemCheckRootless(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.Rootless,
+	)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "check",
+			// The result depends on the host
+			Command: test.Command("system", "check-rootless"),
+			Expected: test.Expects(expect.ExitCodeNoCheck, nil, expect.All(
+				expect.Contains("cgroup v2"),
+				expect.Contains("newuidmap"),
+				expect.Contains("/etc/subuid"),
+				expect.Contains("ports below"),
+			)),
+		},
+		{
+			Description: "fix requires root",
+			Command:     test.Command("system", "check-rootless", "--fix"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "privileged-ports requires fix",
+			Command:     test.Command("system", "check-rootless", "--privileged-ports"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
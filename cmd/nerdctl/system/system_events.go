@@ -27,7 +27,14 @@ import (
 
 func EventsCommand() *cobra.Command {
 	shortHelp := `Get real time events from the server`
-	longHelp := shortHelp + "\nNOTE: The output format is not compatible with Docker."
+	longHelp := shortHelp + `
+
+The containerd events are translated into the Docker-compatible events of the types "container", "image", and "volume".
+The other containerd events (e.g., "/snapshot/prepare") are printed with the type and the action named after the topic,
+and the status "unknown".
+
+Supported filters: "type", "event" (or "status"), "container", "image", "volume", and "label".
+`
 	var cmd = &cobra.Command{
 		Use:           "events",
 		Args:          cobra.NoArgs,
@@ -41,7 +48,9 @@ func EventsCommand() *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	cmd.Flags().StringSliceP("filter", "f", []string{}, "Filter matches containers based on given conditions")
+	cmd.Flags().StringSliceP("filter", "f", []string{}, "Filter output based on conditions provided")
+	cmd.Flags().String("since", "", "Show all events created since timestamp")
+	cmd.Flags().String("until", "", "Stream events until this timestamp")
 	return cmd
 }
 
@@ -58,11 +67,21 @@ func eventsOptions(cmd *cobra.Command) (types.SystemEventsOptions, error) {
 	if err != nil {
 		return types.SystemEventsOptions{}, err
 	}
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
 	return types.SystemEventsOptions{
 		Stdout:   cmd.OutOrStdout(),
 		GOptions: globalOptions,
 		Format:   format,
 		Filters:  filters,
+		Since:    since,
+		Until:    until,
 	}, nil
 }
 
@@ -54,7 +54,7 @@ func TestEventFilters(t *testing.T) {
 			},
 			Data: test.WithLabels(map[string]string{
 				"filter": "event=START",
-				"output": "\"Status\":\"start\"",
+				"output": "\"status\":\"start\"",
 			}),
 		},
 		{
@@ -83,7 +83,21 @@ func TestEventFilters(t *testing.T) {
 			},
 			Data: test.WithLabels(map[string]string{
 				"filter": "event=unknown",
-				"output": "\"Status\":\"unknown\"",
+				"output": "\"status\":\"unknown\"",
+			}),
+		},
+		{
+			Description: "DieEventFilter",
+			Command:     testEventFilterExecutor,
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeTimeout,
+					Output:   expect.Contains(data.Labels().Get("output")),
+				}
+			},
+			Data: test.WithLabels(map[string]string{
+				"filter": "event=die",
+				"output": "\"Action\":\"die\"",
 			}),
 		},
 		{
@@ -112,10 +126,105 @@ func TestEventFilters(t *testing.T) {
 			},
 			Data: test.WithLabels(map[string]string{
 				"filter": "status=unknown",
-				"output": "\"Status\":\"unknown\"",
+				"output": "\"status\":\"unknown\"",
+			}),
+		},
+		{
+			Description: "TypeFilter",
+			Command:     testEventFilterExecutor,
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeTimeout,
+					Output:   expect.Contains(data.Labels().Get("output")),
+				}
+			},
+			Data: test.WithLabels(map[string]string{
+				"filter": "type=container",
+				"output": "\"Type\":\"container\"",
 			}),
 		},
 	}
 
 	testCase.Run(t)
 }
+
+func TestEventsContainerFilter(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("other"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		helpers.Ensure("pull", testutil.CommonImage)
+		cmd := helpers.Command("events", "--filter", "container="+data.Identifier(), "--filter", "event=start", "--format", "{{.Actor.Attributes.name}}")
+		cmd.WithTimeout(10 * time.Second)
+		cmd.Background()
+		// wait for the subscription
+		time.Sleep(time.Second)
+		helpers.Ensure("run", "--name", data.Identifier("other"), testutil.CommonImage, "true")
+		helpers.Ensure("run", "--rm", "--name", data.Identifier(), testutil.CommonImage, "true")
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: expect.All(
+				expect.Contains(data.Identifier()),
+				expect.DoesNotContain(data.Identifier("other")),
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestEventsUntil(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Command = test.Command("events", "--until", "1s")
+
+	testCase.Expected = test.Expects(0, nil, nil)
+
+	testCase.Run(t)
+}
+
+func TestVolumeEvents(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		helpers.Ensure("pull", testutil.CommonImage)
+		cmd := helpers.Command("events", "--format", "{{.Topic}} {{.ID}} {{.Event}}")
+		cmd.WithTimeout(10 * time.Second)
+		cmd.Background()
+		// wait for the subscription
+		time.Sleep(time.Second)
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("run", "--name", data.Identifier(), "-v", data.Identifier()+":/mnt", testutil.CommonImage, "true")
+		helpers.Ensure("rm", data.Identifier())
+		helpers.Ensure("volume", "rm", data.Identifier())
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: expect.Contains(
+				"/volumes/create",
+				"/volumes/mount",
+				"/volumes/unmount",
+				"/volumes/destroy",
+				`"name":"`+data.Identifier()+`","driver":"local"`,
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func gcCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc [flags]",
+		Short: "Trigger the garbage collection of containerd",
+		Long: `Trigger the garbage collection of containerd, and list the content and the snapshots that were freed.
+
+Use '--dry-run' to list the content and the snapshots that are not referenced by any image, container or lease,
+without triggering the garbage collection.`,
+		Args:          cobra.NoArgs,
+		RunE:          gcAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("dry-run", false, "Only list the content and the snapshots that would be freed")
+	cmd.Flags().String("threshold", "", "Only trigger the garbage collection when the reclaimable space exceeds the threshold (e.g. 1GB)")
+	return cmd
+}
+
+func gcOptions(cmd *cobra.Command) (types.SystemGCOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemGCOptions{}, err
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return types.SystemGCOptions{}, err
+	}
+	thresholdStr, err := cmd.Flags().GetString("threshold")
+	if err != nil {
+		return types.SystemGCOptions{}, err
+	}
+	var threshold int64
+	if thresholdStr != "" {
+		threshold, err = units.FromHumanSize(thresholdStr)
+		if err != nil {
+			return types.SystemGCOptions{}, err
+		}
+	}
+	return types.SystemGCOptions{
+		Stdout:    cmd.OutOrStdout(),
+		GOptions:  globalOptions,
+		DryRun:    dryRun,
+		Threshold: threshold,
+	}, nil
+}
+
+func gcAction(cmd *cobra.Command, _ []string) error {
+	options, err := gcOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return system.GC(ctx, client, options)
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestSystemGC(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		// Private because the garbage collection affects the whole namespace
+		nerdtest.Private,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "dry-run does not free referenced content",
+			NoParallel:  true,
+			Command:     test.Command("system", "gc", "--dry-run"),
+			Expected: test.Expects(0, nil, expect.All(
+				expect.Contains("Total reclaimable space"),
+				expect.DoesNotContain("Deleted"),
+			)),
+		},
+		{
+			Description: "threshold skips the garbage collection",
+			NoParallel:  true,
+			Command:     test.Command("system", "gc", "--threshold", "1PB"),
+			Expected:    test.Expects(0, nil, expect.Contains("below the threshold")),
+		},
+		{
+			Description: "gc keeps the image",
+			NoParallel:  true,
+			Command:     test.Command("system", "gc"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains("Total reclaimed space"),
+						func(stdout string, t tig.T) {
+							helpers.Ensure("image", "inspect", testutil.CommonImage)
+						},
+					),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"slices"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
@@ -62,6 +64,23 @@ func TestInfo(t *testing.T) {
 			Command:     test.Command("info", "--format", "json"),
 			Expected:    test.Expects(0, nil, testInfoComparator),
 		},
+		{
+			Description: "info counts containers and images",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: test.Command("info", "--format", "json"),
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				var dinf dockercompat.Info
+				assert.NilError(t, json.Unmarshal([]byte(stdout), &dinf), "failed to unmarshal stdout")
+				assert.Assert(t, dinf.ContainersRunning >= 1, fmt.Sprintf("expected a running container, got %d", dinf.ContainersRunning))
+				assert.Equal(t, dinf.Containers, dinf.ContainersRunning+dinf.ContainersPaused+dinf.ContainersStopped)
+				assert.Assert(t, dinf.Images >= 1, fmt.Sprintf("expected an image, got %d", dinf.Images))
+			}),
+		},
 		{
 			Description: "info with namespace",
 			Require:     require.Not(nerdtest.Docker),
@@ -81,6 +100,23 @@ func TestInfo(t *testing.T) {
 			},
 			Expected: test.Expects(0, nil, expect.Contains("Namespace:	test")),
 		},
+		{
+			Description: "info reports the network namespace of RootlessKit",
+			Require: require.All(
+				require.Not(nerdtest.Docker),
+				nerdtest.Rootless,
+			),
+			Command: test.Command("info", "--format", "{{json .RootlessNetwork}}"),
+			Expected: test.Expects(0, nil, func(stdout string, t tig.T) {
+				var n native.RootlessNetwork
+				assert.NilError(t, json.Unmarshal([]byte(stdout), &n), "failed to unmarshal stdout")
+				var names []string
+				for _, iface := range n.Interfaces {
+					names = append(names, iface.Name)
+				}
+				assert.Assert(t, slices.Contains(names, "lo"), fmt.Sprintf("expected the loopback interface, got %v", names))
+			}),
+		},
 	}
 
 	testCase.Run(t)
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func metricsCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "metrics [flags]",
+		Short: "Serve Prometheus metrics of the containers",
+		Long: `Serve Prometheus metrics of the containers, the images, and the volumes in the namespace.
+
+The metrics are collected from containerd on each scrape of "/metrics":
+  - per container: state, restart count, CPU, memory, network I/O, block I/O, and PIDs
+  - the number of containers by state, the number of images, and the number of volumes`,
+		Args:          cobra.NoArgs,
+		RunE:          metricsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:9323", "Address to serve the metrics on")
+	return cmd
+}
+
+func metricsOptions(cmd *cobra.Command) (types.SystemMetricsOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemMetricsOptions{}, err
+	}
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return types.SystemMetricsOptions{}, err
+	}
+	return types.SystemMetricsOptions{
+		GOptions: globalOptions,
+		Listen:   listen,
+	}, nil
+}
+
+func metricsAction(cmd *cobra.Command, _ []string) error {
+	options, err := metricsOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return system.Metrics(ctx, client, options)
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nettestutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/portlock"
+)
+
+func TestSystemMetrics(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		// the metrics are served in the network namespace of RootlessKit in rootless mode
+		nerdtest.Rootful,
+		nerdtest.CgroupsAccessible,
+	)
+
+	var server test.TestableCommand
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		port, err := portlock.Acquire(0)
+		assert.NilError(helpers.T(), err)
+		data.Labels().Set("addr", fmt.Sprintf("127.0.0.1:%d", port))
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+		server = helpers.Command("system", "metrics", "--listen", data.Labels().Get("addr"))
+		server.WithTimeout(30 * time.Second)
+		server.Background()
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		if server != nil {
+			server.Signal(os.Kill)
+		}
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		return helpers.Command("container", "inspect", data.Identifier())
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			Output: func(stdout string, t tig.T) {
+				resp, err := nettestutil.HTTPGet("http://"+data.Labels().Get("addr")+"/metrics", 10, false)
+				assert.NilError(t, err)
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				assert.NilError(t, err)
+				for _, s := range []string{
+					`nerdctl_container_running{id="`,
+					`name="` + data.Identifier() + `"} 1`,
+					"nerdctl_container_memory_usage_bytes{",
+					"nerdctl_container_pids{",
+					"nerdctl_containers{state=\"running\"}",
+					"nerdctl_images ",
+					"nerdctl_volumes ",
+				} {
+					assert.Assert(t, strings.Contains(string(body), s), "missing %q in %s", s, body)
+				}
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -44,6 +44,9 @@ func pruneCommand() *cobra.Command {
 	cmd.Flags().BoolP("all", "a", false, "Remove all unused images, not just dangling ones")
 	cmd.Flags().BoolP("force", "f", false, "Do not prompt for confirmation")
 	cmd.Flags().Bool("volumes", false, "Prune volumes")
+	cmd.Flags().Bool("build-cache", true, "Prune build cache")
+	cmd.Flags().StringArray("filter", nil, "Provide filter values (e.g. 'until=24h', 'label=<key>=<value>')")
+	cmd.Flags().Bool("dry-run", false, "Only show what would be removed")
 	return cmd
 }
 
@@ -63,10 +66,28 @@ func pruneOptions(cmd *cobra.Command) (types.SystemPruneOptions, error) {
 		return types.SystemPruneOptions{}, err
 	}
 
-	buildkitHost, err := builder.GetBuildkitHost(cmd, globalOptions.Namespace)
+	buildCache, err := cmd.Flags().GetBool("build-cache")
 	if err != nil {
-		log.L.WithError(err).Warn("BuildKit is not running. Build caches will not be pruned.")
-		buildkitHost = ""
+		return types.SystemPruneOptions{}, err
+	}
+
+	filters, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return types.SystemPruneOptions{}, err
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return types.SystemPruneOptions{}, err
+	}
+
+	var buildkitHost string
+	if buildCache {
+		buildkitHost, err = builder.GetBuildkitHost(cmd, globalOptions)
+		if err != nil {
+			log.L.WithError(err).Warn("BuildKit is not running. Build caches will not be pruned.")
+			buildkitHost = ""
+		}
 	}
 
 	return types.SystemPruneOptions{
@@ -75,6 +96,9 @@ func pruneOptions(cmd *cobra.Command) (types.SystemPruneOptions, error) {
 		GOptions:             globalOptions,
 		All:                  all,
 		Volumes:              vFlag,
+		BuildCache:           buildCache,
+		Filters:              filters,
+		DryRun:               dryRun,
 		BuildKitHost:         buildkitHost,
 		NetworkDriversToKeep: network.NetworkDriversToKeep,
 	}, nil
@@ -86,7 +110,7 @@ func grantSystemPrunePermission(cmd *cobra.Command, options types.SystemPruneOpt
 		return false, err
 	}
 
-	if !force {
+	if !force && !options.DryRun {
 		var confirm string
 		msg := `This will remove:
   - all stopped containers
@@ -97,12 +121,22 @@ func grantSystemPrunePermission(cmd *cobra.Command, options types.SystemPruneOpt
 		}
 		if options.All {
 			msg += `
-  - all images without at least one container associated to them
-  - all build cache`
+  - all images without at least one container associated to them`
 		} else {
 			msg += `
-  - all dangling images
+  - all dangling images`
+		}
+		if options.BuildCache {
+			if options.All {
+				msg += `
+  - all build cache`
+			} else {
+				msg += `
   - all dangling build cache`
+			}
+		}
+		if len(options.Filters) > 0 {
+			msg += "\n\nItems to be pruned will be filtered with:\n  - " + strings.Join(options.Filters, "\n  - ")
 		}
 
 		msg += "\nAre you sure you want to continue? [y/N] "
@@ -17,6 +17,7 @@
 package system
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -75,6 +76,78 @@ func TestSystemPrune(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "dry-run does not remove anything",
+			Require:     require.All(nerdtest.Private, require.Not(nerdtest.Docker)),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "create", data.Identifier())
+				helpers.Ensure("run", "--net", data.Identifier(), "--name", data.Identifier(), testutil.CommonImage)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+				helpers.Anyhow("network", "rm", data.Identifier())
+			},
+			Command: test.Command("system", "prune", "--dry-run", "--all"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains("Would Delete Containers:", "Would Delete Networks:", data.Identifier()),
+						expect.DoesNotContain("Are you sure"),
+						func(stdout string, t tig.T) {
+							containers := helpers.Capture("ps", "-a")
+							networks := helpers.Capture("network", "ls")
+							assert.Assert(t, strings.Contains(containers, data.Identifier()), containers)
+							assert.Assert(t, strings.Contains(networks, data.Identifier()), networks)
+						},
+					),
+				}
+			},
+		},
+		{
+			Description: "label filter",
+			Require:     nerdtest.Private,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--label", "prune=yes", "--name", data.Identifier("yes"), testutil.CommonImage)
+				helpers.Ensure("run", "--name", data.Identifier("no"), testutil.CommonImage)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier("yes"), data.Identifier("no"))
+			},
+			Command: test.Command("system", "prune", "-f", "--filter", "label=prune=yes"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						containers := helpers.Capture("ps", "-a")
+						assert.Assert(t, !strings.Contains(containers, data.Identifier("yes")), containers)
+						assert.Assert(t, strings.Contains(containers, data.Identifier("no")), containers)
+					},
+				}
+			},
+		},
+		{
+			Description: "until filter",
+			Require:     nerdtest.Private,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--name", data.Identifier(), testutil.CommonImage)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: test.Command("system", "prune", "-f", "--filter", "until=1h"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						containers := helpers.Capture("ps", "-a")
+						assert.Assert(t, strings.Contains(containers, data.Identifier()), containers)
+					},
+				}
+			},
+		},
+		{
+			Description: "invalid filter",
+			Command:     test.Command("system", "prune", "-f", "--filter", "dangling=true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errors.New("invalid filter")}, nil),
+		},
 		{
 			Description: "buildkit",
 			// FIXME: using a dedicated namespace does not work with rootful (because of buildkitd)
@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func rateLimitCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "ratelimit [flags]",
+		Short: "Show the pull rate limit of Docker Hub",
+		Long: `Show the pull rate limit of Docker Hub, for the credentials of 'nerdctl login docker.io', or for the IP address of the host.
+
+Querying the rate limit does not count as a pull.`,
+		Args:          cobra.NoArgs,
+		RunE:          rateLimitAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func rateLimitOptions(cmd *cobra.Command) (types.SystemRateLimitOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemRateLimitOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.SystemRateLimitOptions{}, err
+	}
+	return types.SystemRateLimitOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Format:   format,
+	}, nil
+}
+
+func rateLimitAction(cmd *cobra.Command, args []string) error {
+	options, err := rateLimitOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return system.RateLimit(cmd.Context(), options)
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func supervisorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supervisor [flags]",
+		Short: "Enforce the restart policies of the containers",
+		Long: `Enforce the restart policies of the containers, independently of the restart monitor plugin of containerd.
+
+The containers are restarted as soon as their tasks exit, according to their '--restart' policies.
+The supervisor runs until it is interrupted. Use '--systemd-unit' to print a systemd unit running it:
+
+  $ nerdctl system supervisor --systemd-unit | sudo tee /etc/systemd/system/nerdctl-supervisor.service
+  $ sudo systemctl enable --now nerdctl-supervisor`,
+		Args:          cobra.NoArgs,
+		RunE:          supervisorAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolP("all-namespaces", "A", false, "Supervise the containers of all the namespaces")
+	cmd.Flags().Duration("interval", system.DefaultSupervisorInterval, "Interval of reconciling all the containers, in addition to the task exit events")
+	cmd.Flags().Bool("systemd-unit", false, "Print a systemd unit running the supervisor, instead of running it")
+	return cmd
+}
+
+func supervisorOptions(cmd *cobra.Command) (types.SystemSupervisorOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemSupervisorOptions{}, err
+	}
+	allNamespaces, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		return types.SystemSupervisorOptions{}, err
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return types.SystemSupervisorOptions{}, err
+	}
+	systemdUnit, err := cmd.Flags().GetBool("systemd-unit")
+	if err != nil {
+		return types.SystemSupervisorOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return types.SystemSupervisorOptions{
+		Stdout:        cmd.OutOrStdout(),
+		GOptions:      globalOptions,
+		AllNamespaces: allNamespaces,
+		Interval:      interval,
+		SystemdUnit:   systemdUnit,
+		NerdctlCmd:    nerdctlCmd,
+		NerdctlArgs:   nerdctlArgs,
+	}, nil
+}
+
+func supervisorAction(cmd *cobra.Command, _ []string) error {
+	options, err := supervisorOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	client, ctx, cancel, err := clientutil.NewClient(ctx, options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return system.Supervisor(ctx, client, options)
+}
@@ -37,6 +37,9 @@ func Command() *cobra.Command {
 		createCommand(),
 		removeCommand(),
 		pruneCommand(),
+		exportCommand(),
+		importCommand(),
+		cloneCommand(),
 	)
 	return cmd
 }
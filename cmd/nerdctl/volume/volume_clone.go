@@ -0,0 +1,59 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+)
+
+func cloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "clone [flags] SOURCE DESTINATION",
+		Short:             "Create a volume with a copy of the contents of another volume",
+		Long:              "Create a volume with a copy of the contents of another volume.\nThe files are cloned instead of being copied on filesystems supporting reflinks.\nThe containers writing to the source volume should be stopped first.",
+		Args:              cobra.ExactArgs(2),
+		RunE:              cloneAction,
+		ValidArgsFunction: cloneShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func cloneAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	return volume.Clone(cmd.Context(), args[0], args[1], types.VolumeCloneOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+	})
+}
+
+func cloneShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// show volume names
+	return completion.VolumeNames(cmd)
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestVolumeClone(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--label", "foo=bar", data.Identifier("src"))
+		helpers.Ensure("run", "--rm", "-v", data.Identifier("src")+":/mnt", testutil.CommonImage,
+			"sh", "-euxc", "mkdir /mnt/dir && echo hello > /mnt/dir/file && chown -R 1000:1000 /mnt/dir && ln -s dir/file /mnt/link")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("src"), data.Identifier("dst"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "clone copies the contents and the labels",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "clone", data.Identifier("src"), data.Identifier("dst"))
+				helpers.Ensure("run", "--rm", "-v", data.Identifier("src")+":/mnt", testutil.CommonImage, "rm", "-rf", "/mnt/dir")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Identifier("dst")+":/mnt", testutil.CommonImage,
+					"sh", "-euxc", "cat /mnt/link && stat -c %u:%g /mnt/dir/file")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, t tig.T) {
+						assert.Equal(t, stdout, "hello\n1000:1000\n")
+						assert.Equal(t, helpers.Capture("volume", "inspect", "--format", "{{.Labels.foo}}", data.Identifier("dst")), "bar\n")
+					},
+				}
+			},
+		},
+		{
+			Description: "cloning to an existing volume should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "clone", data.Identifier("src"), data.Identifier("src"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrAlreadyExists}, nil),
+		},
+		{
+			Description: "cloning a volume that does not exist should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "clone", data.Identifier("missing"), data.Identifier("missing-dst"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrNotFound}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
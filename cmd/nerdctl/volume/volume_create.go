@@ -18,6 +18,7 @@ package volume
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -38,6 +39,8 @@ func createCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	cmd.Flags().StringArray("label", nil, "Set a label on the volume")
+	cmd.Flags().StringP("driver", "d", volume.LocalDriver, "Specify volume driver name (\"local\", or the name of a Docker volume plugin)")
+	cmd.Flags().StringArrayP("opt", "o", nil, "Set driver specific options")
 	return cmd
 }
 
@@ -56,9 +59,25 @@ func createOptions(cmd *cobra.Command) (types.VolumeCreateOptions, error) {
 		}
 	}
 
+	driver, err := cmd.Flags().GetString("driver")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	opts, err := cmd.Flags().GetStringArray("opt")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	for _, opt := range opts {
+		if k, _, _ := strings.Cut(opt, "="); k == "" {
+			return types.VolumeCreateOptions{}, fmt.Errorf("invalid option %q (%w)", opt, errdefs.ErrInvalidArgument)
+		}
+	}
+
 	return types.VolumeCreateOptions{
 		GOptions: globalOptions,
 		Labels:   labels,
+		Driver:   driver,
+		Options:  opts,
 		Stdout:   cmd.OutOrStdout(),
 	}, nil
 }
@@ -72,7 +91,7 @@ func createAction(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		volumeName = args[0]
 	}
-	_, err = volume.Create(volumeName, options)
+	_, err = volume.Create(cmd.Context(), volumeName, options)
 
 	return err
 }
@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+)
+
+func exportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "export [flags] VOLUME",
+		Short:             "Export the contents of a volume as a tar archive",
+		Args:              cobra.ExactArgs(1),
+		RunE:              exportAction,
+		ValidArgsFunction: exportShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringP("output", "o", "", "Write to a file, instead of STDOUT")
+	cmd.Flags().String("compression", "", "Compression of the archive (none|gzip|zstd), guessed from the extension of the output file by default")
+	cmd.RegisterFlagCompletionFunc("compression", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{volume.CompressionNone, volume.CompressionGzip, volume.CompressionZstd}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// compressionFromExtension guesses the compression of an archive from its file name.
+func compressionFromExtension(output string) string {
+	switch {
+	case strings.HasSuffix(output, ".gz"), strings.HasSuffix(output, ".tgz"):
+		return volume.CompressionGzip
+	case strings.HasSuffix(output, ".zst"), strings.HasSuffix(output, ".tzst"):
+		return volume.CompressionZstd
+	}
+	return volume.CompressionNone
+}
+
+func exportAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	compression, err := cmd.Flags().GetString("compression")
+	if err != nil {
+		return err
+	}
+	if compression == "" {
+		compression = compressionFromExtension(output)
+	}
+
+	writer := cmd.OutOrStdout()
+	if output != "" {
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		writer = f
+	} else if isatty.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("cowardly refusing to save to a terminal. Use the -o flag or redirect")
+	}
+
+	return volume.Export(cmd.Context(), args[0], types.VolumeExportOptions{
+		Stdout:      writer,
+		GOptions:    globalOptions,
+		Compression: compression,
+	})
+}
+
+func exportShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show volume names
+	return completion.VolumeNames(cmd)
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestVolumeExportImport(t *testing.T) {
+	testCase := nerdtest.Setup()
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier("src"))
+		helpers.Ensure("run", "--rm", "-v", data.Identifier("src")+":/mnt", testutil.CommonImage,
+			"sh", "-euxc", "mkdir /mnt/dir && echo hello > /mnt/dir/file && chown -R 1000:1000 /mnt/dir && ln -s dir/file /mnt/link")
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("src"), data.Identifier("gzip"), data.Identifier("zstd"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "gzip",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "export", "-o", data.Temp().Path("vol.tar.gz"), data.Identifier("src"))
+				helpers.Ensure("volume", "import", "-i", data.Temp().Path("vol.tar.gz"), data.Identifier("gzip"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Identifier("gzip")+":/mnt", testutil.CommonImage,
+					"sh", "-euxc", "cat /mnt/link && stat -c %u:%g /mnt/dir/file")
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.Equals("hello\n1000:1000\n")),
+		},
+		{
+			Description: "zstd through stdin",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "export", "--compression", "zstd", "-o", data.Temp().Path("vol.archive"), data.Identifier("src"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Command("volume", "import", data.Identifier("zstd"))
+				cmd.Feed(strings.NewReader(data.Temp().Load("vol.archive")))
+				return cmd
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Identifier("zstd") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "importing into a volume that is not empty should fail",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("volume", "export", "-o", data.Temp().Path("vol.tar"), data.Identifier("src"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "import", "-i", data.Temp().Path("vol.tar"), data.Identifier("src"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrFailedPrecondition}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
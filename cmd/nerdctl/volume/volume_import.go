@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+)
+
+func importCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "import [flags] VOLUME",
+		Short:             "Import the contents of a volume from a tar archive, optionally compressed with gzip or zstd",
+		Long:              "Import the contents of a volume from a tar archive, optionally compressed with gzip or zstd.\nThe volume is created if it does not exist, and must be empty otherwise.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              importAction,
+		ValidArgsFunction: importShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringP("input", "i", "", "Read from a file, instead of STDIN")
+	return cmd
+}
+
+func importAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	input, err := cmd.Flags().GetString("input")
+	if err != nil {
+		return err
+	}
+
+	reader := cmd.InOrStdin()
+	if input != "" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	return volume.Import(cmd.Context(), args[0], types.VolumeImportOptions{
+		Stdout:   cmd.OutOrStdout(),
+		Stdin:    reader,
+		GOptions: globalOptions,
+	})
+}
+
+func importShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// show volume names
+	return completion.VolumeNames(cmd)
+}
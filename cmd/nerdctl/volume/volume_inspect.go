@@ -17,11 +17,17 @@
 package volume
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 )
 
@@ -69,7 +75,20 @@ func inspectAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return volume.Inspect(cmd.Context(), args, options)
+	ctx := cmd.Context()
+	// the client is only needed to count the containers referencing the volumes
+	var client *containerd.Client
+	if options.Size {
+		var cancel context.CancelFunc
+		client, ctx, cancel, err = clientutil.NewClient(ctx, options.GOptions.Namespace, options.GOptions.Address)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to connect to containerd, the containers referencing the volumes are not counted")
+			client, ctx = nil, cmd.Context()
+		} else {
+			defer cancel()
+		}
+	}
+	return volume.Inspect(ctx, client, args, options)
 }
 
 func volumeInspectShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -139,6 +139,9 @@ func TestVolumeInspect(t *testing.T) {
 						expect.Contains(data.Labels().Get("vol1")),
 						expect.JSON([]native.Volume{}, func(dc []native.Volume, t tig.T) {
 							assert.Assert(t, dc[0].Size == size, fmt.Sprintf("expected size to be %d (was %d)", size, dc[0].Size))
+							assert.Assert(t, dc[0].UsageData != nil, "expected usage data")
+							assert.Assert(t, dc[0].UsageData.Size == size, fmt.Sprintf("expected usage size to be %d (was %d)", size, dc[0].UsageData.Size))
+							assert.Assert(t, dc[0].UsageData.RefCount == 0, fmt.Sprintf("expected ref count to be 0 (was %d)", dc[0].UsageData.RefCount))
 						}),
 					),
 				}
@@ -17,10 +17,15 @@
 package volume
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 
+	containerd "github.com/containerd/containerd/v2/client"
+
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 )
 
@@ -81,5 +86,16 @@ func listAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return volume.List(options)
+	ctx := cmd.Context()
+	// the client is only needed to find the volumes referenced by containers
+	var client *containerd.Client
+	if volume.HasDanglingFilter(options.Filters) {
+		var cancel context.CancelFunc
+		client, ctx, cancel, err = clientutil.NewClient(ctx, options.GOptions.Namespace, options.GOptions.Address)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+	}
+	return volume.List(ctx, client, options)
 }
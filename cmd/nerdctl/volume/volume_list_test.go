@@ -24,6 +24,7 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
@@ -324,6 +325,70 @@ func TestVolumeLsFilter(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "Retrieving dangling=true",
+			Command:     test.Command("volume", "ls", "--quiet", "--filter", "dangling=true"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(
+						data.Labels().Get("vol1"),
+						data.Labels().Get("vol2"),
+						data.Labels().Get("vol3"),
+						data.Labels().Get("vol4"),
+					),
+				}
+			},
+		},
+		{
+			Description: "Retrieving dangling=false",
+			Command:     test.Command("volume", "ls", "--quiet", "--filter", "dangling=false"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.DoesNotContain(
+						data.Labels().Get("vol1"),
+						data.Labels().Get("vol2"),
+						data.Labels().Get("vol3"),
+						data.Labels().Get("vol4"),
+					),
+				}
+			},
+		},
+		{
+			Description: "Retrieving driver=local",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "ls", "--quiet", "--filter", "driver=local", "--filter", "name="+data.Labels().Get("vol1"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Labels().Get("vol1") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "Retrieving driver=foo",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "ls", "--quiet", "--filter", "driver=foo", "--filter", "name="+data.Labels().Get("vol1"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("")),
+		},
+		{
+			Description: "Format with template fields",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "ls", "--filter", "name="+data.Labels().Get("vol1"),
+					"--format", `{{.Name}} {{.Driver}} {{.Scope}} {{.Label "mylabel"}}`)
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Labels().Get("vol1") + " local local label-1\n"),
+				}
+			},
+		},
+		{
+			Description: "Invalid filter",
+			Require:     require.Not(nerdtest.Docker),
+			Command:     test.Command("volume", "ls", "--filter", "foo=bar"),
+			Expected:    test.Expects(1, nil, nil),
+		},
 		{
 			Description: "Retrieving size=1024000",
 			Require:     require.Not(nerdtest.Docker),
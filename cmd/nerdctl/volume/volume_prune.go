@@ -38,6 +38,7 @@ func pruneCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	cmd.Flags().BoolP("all", "a", false, "Remove all unused volumes, not just anonymous ones")
+	cmd.Flags().StringSlice("filter", []string{}, "Provide filter values (e.g. 'label=<label>')")
 	cmd.Flags().BoolP("force", "f", false, "Do not prompt for confirmation")
 	return cmd
 }
@@ -53,6 +54,11 @@ func pruneOptions(cmd *cobra.Command) (types.VolumePruneOptions, error) {
 		return types.VolumePruneOptions{}, err
 	}
 
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.VolumePruneOptions{}, err
+	}
+
 	force, err := cmd.Flags().GetBool("force")
 	if err != nil {
 		return types.VolumePruneOptions{}, err
@@ -61,6 +67,7 @@ func pruneOptions(cmd *cobra.Command) (types.VolumePruneOptions, error) {
 	options := types.VolumePruneOptions{
 		GOptions: globalOptions,
 		All:      all,
+		Filters:  filters,
 		Force:    force,
 		Stdout:   cmd.OutOrStdout(),
 	}
@@ -75,7 +82,10 @@ func pruneAction(cmd *cobra.Command, _ []string) error {
 
 	if !options.Force {
 		var confirm string
-		msg := "This will remove all local volumes not used by at least one container."
+		msg := "This will remove anonymous local volumes not used by at least one container."
+		if options.All {
+			msg = "This will remove all local volumes not used by at least one container."
+		}
 		msg += "\nAre you sure you want to continue? [y/N] "
 		fmt.Fprintf(options.Stdout, "WARNING! %s", msg)
 		fmt.Fscanf(cmd.InOrStdin(), "%s", &confirm)
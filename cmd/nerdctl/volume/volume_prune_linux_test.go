@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
@@ -111,3 +112,61 @@ func TestVolumePrune(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+func TestVolumePruneFilter(t *testing.T) {
+	var setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--label", "foo=bar", data.Identifier("bar"))
+		helpers.Ensure("volume", "create", "--label", "foo=baz", data.Identifier("baz"))
+		helpers.Ensure("volume", "create", data.Identifier("nolabel"))
+	}
+
+	var cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier("bar"), data.Identifier("baz"), data.Identifier("nolabel"))
+	}
+
+	testCase := nerdtest.Setup()
+	testCase.Require = nerdtest.Private
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "label filter",
+			NoParallel:  true,
+			Setup:       setup,
+			Cleanup:     cleanup,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "prune", "-f", "--all", "--filter", "label=foo=bar")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(data.Identifier("bar")),
+						expect.DoesNotContain(data.Identifier("baz"), data.Identifier("nolabel")),
+					),
+				}
+			},
+		},
+		{
+			Description: "negated label filter",
+			NoParallel:  true,
+			Setup:       setup,
+			Cleanup:     cleanup,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "prune", "-f", "--filter", "all=true", "--filter", "label!=foo")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(data.Identifier("nolabel")),
+						expect.DoesNotContain(data.Identifier("bar"), data.Identifier("baz")),
+					),
+				}
+			},
+		},
+		{
+			Description: "invalid filter",
+			Command:     test.Command("volume", "prune", "-f", "--filter", "dangling=true"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
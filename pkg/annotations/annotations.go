@@ -33,6 +33,10 @@ const (
 	// Bypass4netnsIgnoreBind disables acceleration for bind.
 	// Boolean value which can be parsed with strconv.ParseBool() is required.
 	Bypass4netnsIgnoreBind = Bypass4netns + "-ignore-bind"
+
+	// RootlessPortDriver is the port driver of the ports of the container in rootless mode,
+	// specified with `--rootless-port-driver`.
+	RootlessPortDriver = Prefix + "rootless-port-driver"
 )
 
 var ShellCompletions = []string{
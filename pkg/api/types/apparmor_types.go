@@ -31,3 +31,18 @@ type ApparmorListOptions struct {
 type ApparmorInspectOptions struct {
 	Stdout io.Writer
 }
+
+// ApparmorLoadOptions specifies options for `nerdctl apparmor load`.
+type ApparmorLoadOptions struct {
+	// Files of the profiles to load. The default profile is loaded if empty.
+	Files []string
+	// Persist also installs the profiles into /etc/apparmor.d, so that they are loaded on boot.
+	Persist bool
+}
+
+// ApparmorInstallOptions specifies options for `nerdctl apparmor install`.
+type ApparmorInstallOptions struct {
+	Stdout io.Writer
+	// SudoScript prints a shell script for the steps that need root, instead of executing them.
+	SudoScript bool
+}
@@ -27,6 +27,12 @@ type BuilderBuildOptions struct {
 	GOptions GlobalCommandOptions
 	// BuildKitHost is the buildkit host
 	BuildKitHost string
+	// Builder is the builder to use. "containerd" selects the built-in builder that does not need buildkitd.
+	Builder string
+	// NerdctlCmd is the command name of nerdctl, used by the containerd builder
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl, used by the containerd builder
+	NerdctlArgs []string
 	// Tag is the tag of the image
 	Tag []string
 	// File Name of the Dockerfile
@@ -39,7 +45,11 @@ type BuilderBuildOptions struct {
 	NoCache bool
 	// Output is the output destination
 	Output string
-	// Progress Set type of progress output (auto, plain, tty). Use plain to show container output
+	// Push pushes the image to the registry from BuildKit, without loading it unless Load is set
+	Push bool
+	// Load loads the image into the image store, which is the default unless Output or Push is set
+	Load bool
+	// Progress Set type of progress output (auto, plain, tty, rawjson). Use plain to show container output
 	Progress string
 	// Secret file to expose to the build: id=mysecret,src=/local/secret
 	Secret []string
@@ -57,6 +67,12 @@ type BuilderBuildOptions struct {
 	CacheTo []string
 	// Rm remove intermediate containers after a successful build
 	Rm bool
+	// ForceRm always removes intermediate containers, even after a failed build
+	ForceRm bool
+	// Invoke is "on-error" for running an interactive shell in the container of a failed RUN instruction
+	Invoke string
+	// Call is the request to evaluate instead of building the image: "build" (default), "check", "outline", or "targets"
+	Call string
 	// Platform set target platform for build (e.g., "amd64", "arm64")
 	Platform []string
 	// IidFile write the image ID to the file
@@ -75,6 +91,34 @@ type BuilderBuildOptions struct {
 	ExtraHosts []string
 }
 
+// BuilderBakeOptions specifies options for `nerdctl bake`.
+type BuilderBakeOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// NerdctlCmd is the command name of nerdctl, used to run the builds
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl, used to run the builds
+	NerdctlArgs []string
+	// Files are the bake files. The default files (docker-bake.hcl, etc.) are read when empty.
+	Files []string
+	// Targets are the targets or groups to build. The "default" group is built when empty.
+	Targets []string
+	// Set overrides target values (e.g. "app.tags=app:v1", "*.platform=linux/arm64")
+	Set []string
+	// Print prints the resolved definition as JSON instead of building
+	Print bool
+	// NoCache disables cache for all the targets
+	NoCache bool
+	// Pull always attempts to pull the base images for all the targets
+	Pull bool
+	// Progress Set type of progress output (auto, plain, tty, rawjson)
+	Progress string
+	// Builder is the builder to use
+	Builder string
+}
+
 // BuilderPruneOptions specifies options for `nerdctl builder prune`.
 type BuilderPruneOptions struct {
 	Stderr io.Writer
@@ -86,4 +130,67 @@ type BuilderPruneOptions struct {
 	All bool
 	// Force will not prompt for confirmation.
 	Force bool
+	// Filters are docker-style filters (e.g. "until=24h", "type=regular") for the cache records to remove
+	Filters []string
+	// KeepStorage is the amount of disk space (in bytes) to keep for cache
+	KeepStorage int64
+	// MaxUsedSpace is the maximum amount of disk space (in bytes) for cache
+	MaxUsedSpace int64
+	// MinFreeSpace is the target amount of free disk space (in bytes) to keep
+	MinFreeSpace int64
+	// DryRun only returns the cache records that would be removed, without honoring the storage limits
+	DryRun bool
+}
+
+// BuilderDiskUsageOptions specifies options for `nerdctl builder du`.
+type BuilderDiskUsageOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// BuildKitHost is the buildkit host
+	BuildKitHost string
+	// Filters are docker-style filters (e.g. "type=regular") for the cache records to show
+	Filters []string
+	// Verbose shows every cache record instead of the summary by record type
+	Verbose bool
+}
+
+// BuilderCreateOptions specifies options for `nerdctl builder create`.
+type BuilderCreateOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Name is the name of the builder
+	Name string
+	// BuildKitHost is the buildkitd address of the builder. Auto-detected when empty.
+	BuildKitHost string
+	// Platforms are the platforms the builder is declared to build for
+	Platforms []string
+	// Use selects the builder after creating it
+	Use bool
+}
+
+// BuilderListOptions specifies options for `nerdctl builder ls`.
+type BuilderListOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// BuilderUseOptions specifies options for `nerdctl builder use`.
+type BuilderUseOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Name is the name of the builder to select
+	Name string
+}
+
+// BuilderRemoveOptions specifies options for `nerdctl builder rm`.
+type BuilderRemoveOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
 }
@@ -0,0 +1,45 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// BundleCreateOptions specifies options for `nerdctl bundle create`.
+type BundleCreateOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Output is the path of the bundle archive to write
+	Output string
+	// Services to include in the bundle (all services if empty)
+	Services []string
+	// Platform bundle content for specific platforms
+	Platform []string
+	// AllPlatforms bundle content for all platforms
+	AllPlatforms bool
+}
+
+// BundleLoadOptions specifies options for `nerdctl bundle load`.
+type BundleLoadOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Input is the path of the bundle archive to load
+	Input string
+	// Directory to extract the compose file to
+	Directory string
+	// Quiet suppresses the image load output
+	Quiet bool
+}
@@ -34,6 +34,8 @@ type NetworkOptions struct {
 	Hostname string
 	// Domainname specifies the container's domain name
 	Domainname string
+	// NetworkAliases specifies additional names of the container on its networks
+	NetworkAliases []string
 	// DNSServers set custom DNS servers
 	DNSServers []string
 	// DNSResolvConfOptions set DNS options
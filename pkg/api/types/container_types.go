@@ -229,11 +229,15 @@ type ContainerCreateOptions struct {
 	Mount []string
 	// VolumesFrom specifies a list of specified containers to mount from
 	VolumesFrom []string
+	// Secret specifies a list of secrets to mount (`source=<NAME>,file=<PATH>|env=<VAR>|provider=<PROGRAM>[,target=<PATH>,uid=<UID>,gid=<GID>,mode=<MODE>]`)
+	Secret []string
 	// #endregion
 
 	// #region for rootfs flags
 	// ReadOnly mount the container's root filesystem as read only
 	ReadOnly bool
+	// ReadOnlyTmpfs mounts tmpfs on /run, /tmp, and /var/tmp when ReadOnly is set. Corresponds to Podman CLI.
+	ReadOnlyTmpfs bool
 	// Rootfs specifies the first argument is not an image but the rootfs to the exploded container. Corresponds to Podman CLI.
 	Rootfs bool
 	// #endregion
@@ -261,6 +265,12 @@ type ContainerCreateOptions struct {
 	LabelFile []string
 	// Annotations set meta data on a container (passed through to the OCI runtime)
 	Annotations []string
+	// Bypass4netns enables the acceleration with bypass4netns in rootless mode, as `--annotation nerdctl/bypass4netns=true`
+	Bypass4netns bool
+	// Bypass4netnsChanged specifies whether Bypass4netns has been specified
+	Bypass4netnsChanged bool
+	// RootlessPortDriver specifies the port driver of the ports of the container in rootless mode (rootlesskit|bypass4netns|builtin|slirp4netns|implicit|pasta)
+	RootlessPortDriver string
 	// CidFile write the container ID to the file
 	CidFile string
 	// PidFile specifies the file path to write the task's pid. The CLI syntax conforms to Podman convention.
@@ -351,6 +361,37 @@ type ContainerPruneOptions struct {
 	Stdout io.Writer
 	// GOptions is the global options
 	GOptions GlobalCommandOptions
+	// Filters are docker-style filters (e.g. "until=24h", "label=foo") for the containers to remove
+	Filters []string
+	// DryRun only prints the containers that would be removed
+	DryRun bool
+}
+
+// ContainerHealthCheckServeOptions specifies options for `nerdctl container healthcheck serve`.
+type ContainerHealthCheckServeOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+}
+
+// ContainerDebugOptions specifies options for `nerdctl debug`.
+type ContainerDebugOptions struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Image is the image of the debug container, providing the tools
+	Image string
+	// Pull is the pull policy of Image (always|missing|never)
+	Pull string
+	// Interactive keeps STDIN open
+	Interactive bool
+	// TTY allocates a pseudo-TTY
+	TTY bool
+	// NerdctlCmd is the command name of nerdctl
+	NerdctlCmd string
+	// NerdctlArgs is the arguments of nerdctl
+	NerdctlArgs []string
 }
 
 // ContainerUnpauseOptions specifies options for `nerdctl (container) unpause`.
@@ -458,6 +499,9 @@ type ContainerLogsOptions struct {
 	GOptions GlobalCommandOptions
 	// Follow specifies whether to stream the logs or just print the existing logs.
 	Follow bool
+	// FollowRetries specifies how many times to keep following the logs after the container is restarted,
+	// or containerd is restarted. Negative values mean unlimited.
+	FollowRetries int
 	// Timestamps specifies whether to show the timestamps of the logs.
 	Timestamps bool
 	// Tail specifies the number of lines to show from the end of the logs.
@@ -469,6 +513,12 @@ type ContainerLogsOptions struct {
 	Until string
 	// Details specifies whether to show extra details provided to logs
 	Details bool
+	// Filters selects the containers to show the logs of, in addition to the containers specified by name or ID.
+	Filters []string
+	// NoColor disables the colors of the container name prefixes.
+	NoColor bool
+	// NoLogPrefix disables the container name prefixes when showing the logs of multiple containers.
+	NoLogPrefix bool
 }
 
 // ContainerWaitOptions specifies options for `nerdctl (container) wait`.
@@ -545,6 +595,8 @@ type ContainerCpOptions struct {
 
 // ContainerStatsOptions specifies options for `nerdctl stats`.
 type ContainerStatsOptions struct {
+	// Stdin is read for the sorting keys, when it is a terminal
+	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
 	// GOptions is the global options.
@@ -557,4 +609,6 @@ type ContainerStatsOptions struct {
 	NoStream bool
 	// Do not truncate output.
 	NoTrunc bool
+	// Sort is the initial sorting key (cpu|mem|net|block|pids|name|id), empty for the order of creation.
+	Sort string
 }
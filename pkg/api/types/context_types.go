@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// ContextCreateOptions specifies options for `nerdctl context create`.
+type ContextCreateOptions struct {
+	Stdout io.Writer
+	// Dir is the directory of the context store
+	Dir string
+	// Name is the name of the context
+	Name string
+	// Description is the description of the context
+	Description string
+	// Address is the containerd address of the context
+	Address string
+	// Namespace is the containerd namespace of the context
+	Namespace string
+	// Snapshotter is the containerd snapshotter of the context
+	Snapshotter string
+	// SSHHost is the remote host of the context, in the form of "[ssh://][USER@]HOST[:PORT]"
+	SSHHost string
+}
+
+// ContextListOptions specifies options for `nerdctl context ls`.
+type ContextListOptions struct {
+	Stdout io.Writer
+	// Dir is the directory of the context store
+	Dir string
+	// Current is the name of the context in effect, marked with "*"
+	Current string
+	// Quiet only shows the names
+	Quiet bool
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// ContextUseOptions specifies options for `nerdctl context use`.
+type ContextUseOptions struct {
+	Stdout io.Writer
+	// Dir is the directory of the context store
+	Dir string
+	// Name is the name of the context to select
+	Name string
+}
+
+// ContextInspectOptions specifies options for `nerdctl context inspect`.
+type ContextInspectOptions struct {
+	Stdout io.Writer
+	// Dir is the directory of the context store
+	Dir string
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// ContextRemoveOptions specifies options for `nerdctl context rm`.
+type ContextRemoveOptions struct {
+	Stdout io.Writer
+	// Dir is the directory of the context store
+	Dir string
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// GenerateSystemdOptions specifies options for `nerdctl generate systemd`.
+type GenerateSystemdOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// NerdctlCmd is the command name of nerdctl, executed by the units
+	NerdctlCmd string
+	// NerdctlArgs is the global flags of nerdctl, passed to NerdctlCmd in the units
+	NerdctlArgs []string
+	// Scope is the scope of the units (system|user). Defaults to "user" in rootless mode, "system" otherwise.
+	Scope string
+	// Files writes the units to files in the current directory, instead of Stdout
+	Files bool
+	// StopTimeout is the timeout (in seconds) of stopping the containers
+	StopTimeout uint
+}
@@ -55,6 +55,10 @@ type ImageConvertOptions struct {
 	Uncompress bool
 	// Oci convert Docker media types to OCI media types
 	Oci bool
+	// Docker convert OCI media types to Docker media types
+	Docker bool
+	// Parallelism is the maximum number of layers converted in parallel (0 for the number of CPUs)
+	Parallelism int
 	// #endregion
 
 	// #region platform flags
@@ -73,6 +77,7 @@ type ImageConvertOptions struct {
 	ZstdChunkedOptions
 	NydusOptions
 	OverlaybdOptions
+	ErofsOptions
 	SociConvertOptions
 }
 
@@ -141,6 +146,14 @@ type OverlaybdOptions struct {
 	// #endregion
 }
 
+// ErofsOptions contains EROFS conversion options
+type ErofsOptions struct {
+	// Erofs convert tar(.gz) layers to native EROFS layers, for the erofs snapshotter
+	Erofs bool
+	// ErofsMkfsOptions are the extra options of mkfs.erofs, e.g., "-zlz4hc"
+	ErofsMkfsOptions []string
+}
+
 type SociConvertOptions struct {
 	// Soci convert image to SOCI format.
 	Soci bool
@@ -184,6 +197,7 @@ type ImageInspectOptions struct {
 // ImagePushOptions specifies options for `nerdctl (image) push`.
 type ImagePushOptions struct {
 	Stdout      io.Writer
+	Stderr      io.Writer
 	GOptions    GlobalCommandOptions
 	SignOptions ImageSignOptions
 	SociOptions SociOptions
@@ -198,16 +212,27 @@ type ImagePushOptions struct {
 	IpfsEnsureImage bool
 	// IpfsAddress multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)
 	IpfsAddress string
+	// IpfsPinService is the endpoint of a remote pinning service (IPFS Pinning Service API) to pin the pushed CID to
+	IpfsPinService string
+	// IpfsPinToken is the bearer token for the remote pinning service
+	IpfsPinToken string
+	// IpfsPinName is the name of the pin (defaults to the image name)
+	IpfsPinName string
 	// Suppress verbose output
 	Quiet bool
 	// AllowNondistributableArtifacts allow pushing non-distributable artifacts
 	AllowNondistributableArtifacts bool
+	// SBOM generates an SBOM of the given format (spdx-json|cyclonedx) and attaches it to the pushed image as a referrer
+	SBOM string
 }
 
 // RemoteSnapshotterFlags are used for pulling with remote snapshotters
 // e.g. SOCI, stargz, overlaybd
 type RemoteSnapshotterFlags struct {
 	SociIndexDigest string
+	// PullCredentials returns the registry credentials to be passed to the snapshotter via the snapshot labels (nydus only).
+	// Nil disables passing credentials.
+	PullCredentials func() (username, secret string, err error)
 }
 
 // ImagePullOptions specifies options for `nerdctl (image) pull`.
@@ -232,6 +257,8 @@ type ImagePullOptions struct {
 	IPFSAddress string
 	// Flags to pass into remote snapshotters
 	RFlags RemoteSnapshotterFlags
+	// Retry is the number of times to retry pulling on transient errors (interrupted downloads, 5xx responses)
+	Retry int
 }
 
 // ImageTagOptions specifies options for `nerdctl (image) tag`.
@@ -266,11 +293,14 @@ type ImagePruneOptions struct {
 	Filters []string
 	// Force will not prompt for confirmation.
 	Force bool
+	// DryRun only prints the images that would be removed.
+	DryRun bool
 }
 
 // ImageSaveOptions specifies options for `nerdctl (image) save`.
 type ImageSaveOptions struct {
 	Stdout   io.Writer
+	Stderr   io.Writer
 	GOptions GlobalCommandOptions
 	// Export content for all platforms
 	AllPlatforms bool
@@ -278,6 +308,106 @@ type ImageSaveOptions struct {
 	Platform []string
 }
 
+// ImageExportFSOptions specifies options for `nerdctl image export-fs`.
+type ImageExportFSOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format of the filesystem image (squashfs|erofs)
+	Format string
+	// Platform of the image to export
+	Platform string
+	// Output is the path of the filesystem image to write
+	Output string
+}
+
+// ImagePrefetchOptions specifies options for `nerdctl image prefetch`.
+type ImagePrefetchOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Paths to prefetch, relative to the image rootfs. Directories are prefetched recursively. Empty for the whole rootfs.
+	Paths []string
+	// Platform of the image to prefetch
+	Platform string
+	// Parallelism is the maximum number of files read in parallel (0 for the number of CPUs)
+	Parallelism int
+}
+
+// ImageSquashOptions specifies options for `nerdctl image squash`.
+type ImageSquashOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Source is the image to squash
+	Source string
+	// Target is the name of the squashed image
+	Target string
+	// Platform of the image to squash
+	Platform string
+	// Message is the history comment of the squashed layer
+	Message string
+}
+
+// ImageDiskUsageOptions specifies options for `nerdctl image du`.
+type ImageDiskUsageOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Images to report (all images if empty)
+	Images []string
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Verbose shows the usage of each layer
+	Verbose bool
+}
+
+// ImageCheckOptions specifies options for `nerdctl image check`.
+type ImageCheckOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// All checks all the images
+	All bool
+	// Platform checks content for specific platforms
+	Platform []string
+	// AllPlatforms checks content for all platforms
+	AllPlatforms bool
+	// Repair removes corrupted content, and fetches the missing content and snapshots again
+	Repair bool
+}
+
+// ImageSBOMOptions specifies options for `nerdctl image sbom`.
+type ImageSBOMOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format of the SBOM document (spdx-json|cyclonedx)
+	Format string
+	// Platform of the image to scan
+	Platform string
+	// Output is the path of the SBOM document (defaults to stdout)
+	Output string
+}
+
+// ImageScanOptions specifies options for `nerdctl image scan`.
+type ImageScanOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format the output using the given Go template (applied to each vulnerability)
+	Format string
+	// Severity fails the command when a vulnerability of this severity or higher is found
+	Severity string
+	// Platform of the image to scan
+	Platform []string
+}
+
+// ImageCopyOptions specifies options for `nerdctl image copy`.
+type ImageCopyOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Platform copies content for a specific platform
+	Platform string
+	// AllPlatforms copies content for all platforms
+	AllPlatforms bool
+	// Quiet suppresses the output
+	Quiet bool
+}
+
 // ImageSignOptions contains options for signing an image. It contains options from
 // all providers. The `provider` field determines which provider is used.
 type ImageSignOptions struct {
@@ -292,7 +422,7 @@ type ImageSignOptions struct {
 // ImageVerifyOptions contains options for verifying an image. It contains options from
 // all providers. The `provider` field determines which provider is used.
 type ImageVerifyOptions struct {
-	// Provider used to verify the image (none|cosign|notation)
+	// Provider used to verify the image (none|cosign|notation|scan)
 	Provider string
 	// CosignKey Path to the public key file, KMS URI or Kubernetes Secret for --verify=cosign
 	CosignKey string
@@ -28,4 +28,6 @@ type ImageImportOptions struct {
 	Reference string
 	Message   string
 	Platform  string
+	// Change applies Dockerfile instructions to the config of the imported image
+	Change []string
 }
@@ -30,4 +30,6 @@ type IPFSRegistryServeOptions struct {
 	ReadRetryNum int
 	// ReadTimeout timeout duration of a read request to IPFS. Zero means no timeout.
 	ReadTimeout time.Duration
+	// Gateways HTTP gateways used as fallback content providers when the IPFS API cannot provide the content
+	Gateways []string
 }
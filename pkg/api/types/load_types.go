@@ -21,6 +21,7 @@ import "io"
 // ImageLoadOptions specifies options for `nerdctl (image) load`.
 type ImageLoadOptions struct {
 	Stdout   io.Writer
+	Stderr   io.Writer
 	Stdin    io.Reader
 	GOptions GlobalCommandOptions
 	// Input read from tar archive file, instead of STDIN
@@ -30,4 +30,14 @@ type LoginCommandOptions struct {
 	//
 	// If it's empty, the user will be prompted to provide it.
 	Password string
+	// IdentityToken is the identity token (an OAuth2 refresh token) to log in with, instead of the username and the password.
+	IdentityToken string
+	// DeviceFlow logs in with the OAuth2 device authorization grant of OAuthIssuer.
+	DeviceFlow bool
+	// OAuthIssuer is the issuer URL of the authorization server, discovered with OpenID Connect Discovery.
+	OAuthIssuer string
+	// OAuthClientID is the client ID registered at the authorization server.
+	OAuthClientID string
+	// OAuthScope is the scope requested with the device flow.
+	OAuthScope string
 }
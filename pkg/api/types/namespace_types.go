@@ -42,6 +42,8 @@ type NamespaceInspectOptions struct {
 	GOptions GlobalCommandOptions
 	// Format the output using the given Go template, e.g, '{{json .}}'
 	Format string
+	// Usage reports the disk usage of the namespaces
+	Usage bool
 }
 
 // NamespaceListOptions specifies options for `nerdctl namespace ls`.
@@ -71,6 +71,10 @@ type NetworkPruneOptions struct {
 	GOptions GlobalCommandOptions
 	// Network drivers to keep while pruning
 	NetworkDriversToKeep []string
+	// Filters are docker-style filters (e.g. "until=24h", "label=foo") for the networks to remove
+	Filters []string
+	// DryRun only prints the networks that would be removed
+	DryRun bool
 }
 
 // NetworkRemoveOptions specifies options for `nerdctl network rm`.
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// PluginListOptions specifies options for `nerdctl plugin ls`.
+type PluginListOptions struct {
+	Stdout io.Writer
+	// Builtins are the names of the built-in commands, which take precedence over the plugins
+	Builtins []string
+	// Quiet only shows the plugin names
+	Quiet bool
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// PluginRunOptions specifies options for running a CLI plugin as `nerdctl <NAME>`.
+type PluginRunOptions struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions are the global options passed to the plugin via the environment
+	GOptions GlobalCommandOptions
+	// Path is the path of the plugin executable
+	Path string
+	// Args are the arguments following the plugin name
+	Args []string
+	// Context is the name of the context in effect, or "" for the default context
+	Context string
+	// NerdctlTOML is the path of nerdctl.toml
+	NerdctlTOML string
+}
@@ -0,0 +1,53 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// SearchOptions specifies options for `nerdctl search`.
+type SearchOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Filters are the filters of the results (stars=<N>, is-official=<BOOL>, is-automated=<BOOL>)
+	Filters []string
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Limit is the maximum number of results
+	Limit int
+	// NoTrunc does not truncate the descriptions
+	NoTrunc bool
+}
+
+// RegistryReposOptions specifies options for `nerdctl registry repos`.
+type RegistryReposOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Limit is the maximum number of repositories listed, or 0 for all of them
+	Limit int
+}
+
+// RegistryTagsOptions specifies options for `nerdctl registry tags`.
+type RegistryTagsOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Limit is the maximum number of tags listed, or 0 for all of them
+	Limit int
+}
@@ -16,7 +16,10 @@
 
 package types
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // SystemInfoOptions specifies options for `nerdctl (system) info`.
 type SystemInfoOptions struct {
@@ -39,6 +42,32 @@ type SystemEventsOptions struct {
 	Format string
 	// Filter events based on given conditions
 	Filters []string
+	// Since shows the events created since the timestamp
+	Since string
+	// Until streams the events until the timestamp
+	Until string
+}
+
+// SystemDiskUsageOptions specifies options for `nerdctl system df`.
+type SystemDiskUsageOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// BuildKitHost is the buildkit host, empty when BuildKit is not running
+	BuildKitHost string
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Verbose shows the usage of each image, container, volume, and build cache record
+	Verbose bool
+}
+
+// SystemMetricsOptions specifies options for `nerdctl system metrics`.
+type SystemMetricsOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Listen is the address to serve the metrics on, e.g., "127.0.0.1:9323"
+	Listen string
 }
 
 // SystemPruneOptions specifies options for `nerdctl system prune`.
@@ -51,8 +80,66 @@ type SystemPruneOptions struct {
 	All bool
 	// Volumes decide whether prune volumes or not
 	Volumes bool
+	// BuildCache decide whether prune the build cache or not
+	BuildCache bool
+	// Filters are docker-style filters ("until=<timestamp>", "label=<key>[=<value>]") for the objects to remove
+	Filters []string
+	// DryRun only prints the objects that would be removed
+	DryRun bool
 	// BuildKitHost the address of BuildKit host
 	BuildKitHost string
 	// NetworkDriversToKeep the network drivers which need to keep
 	NetworkDriversToKeep []string
 }
+
+// SystemGCOptions specifies options for `nerdctl system gc`.
+type SystemGCOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// DryRun only reports the content and the snapshots that would be freed, without triggering the garbage collection
+	DryRun bool
+	// Threshold is the minimum reclaimable space in bytes to trigger the garbage collection. Zero means no threshold.
+	Threshold int64
+}
+
+// SystemCheckRootlessOptions specifies options for `nerdctl system check-rootless`.
+type SystemCheckRootlessOptions struct {
+	Stdout io.Writer
+	// Fix writes the systemd drop-ins needed for the cgroup delegation (requires root)
+	Fix bool
+	// PrivilegedPorts grants CAP_NET_BIND_SERVICE to RootlessKit with Fix, so that ports below 1024 can be published
+	PrivilegedPorts bool
+}
+
+// SystemBypass4netnsStatusOptions specifies options for `nerdctl system bypass4netns status`.
+type SystemBypass4netnsStatusOptions struct {
+	Stdout io.Writer
+	// Format the output using the given Go template (e.g., '{{json .}}')
+	Format string
+}
+
+// SystemRateLimitOptions specifies options for `nerdctl system ratelimit`.
+type SystemRateLimitOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// SystemSupervisorOptions specifies options for `nerdctl system supervisor`.
+type SystemSupervisorOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// AllNamespaces supervises the containers of all the namespaces, not only the namespace of GOptions
+	AllNamespaces bool
+	// Interval is the interval of reconciling all the containers, in addition to the task exit events
+	Interval time.Duration
+	// SystemdUnit prints a systemd unit running the supervisor, instead of running it
+	SystemdUnit bool
+	// NerdctlCmd is the command name of nerdctl, written to the systemd unit
+	NerdctlCmd string
+	// NerdctlArgs are the global arguments of nerdctl, written to the systemd unit
+	NerdctlArgs []string
+}
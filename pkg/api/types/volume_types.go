@@ -24,6 +24,10 @@ type VolumeCreateOptions struct {
 	GOptions GlobalCommandOptions
 	// Labels are the volume labels
 	Labels []string
+	// Driver is the volume driver: "local", or the name of a Docker volume plugin
+	Driver string
+	// Options are the driver specific options
+	Options []string
 }
 
 // VolumeInspectOptions specifies options for `nerdctl volume inspect`.
@@ -56,8 +60,12 @@ type VolumePruneOptions struct {
 	GOptions GlobalCommandOptions
 	//Remove all unused volumes, not just anonymous ones
 	All bool
+	// Filters are docker-style filters (e.g. "label=foo", "label!=foo=bar") for the volumes to remove
+	Filters []string
 	// Do not prompt for confirmation
 	Force bool
+	// DryRun only prints the volumes that would be removed
+	DryRun bool
 }
 
 // VolumeRemoveOptions specifies options for `nerdctl volume rm`.
@@ -67,3 +75,24 @@ type VolumeRemoveOptions struct {
 	// Force the removal of one or more volumes
 	Force bool
 }
+
+// VolumeExportOptions specifies options for `nerdctl volume export`.
+type VolumeExportOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Compression is the compression of the tar archive: "none", "gzip", or "zstd"
+	Compression string
+}
+
+// VolumeImportOptions specifies options for `nerdctl volume import`.
+type VolumeImportOptions struct {
+	Stdout   io.Writer
+	Stdin    io.Reader
+	GOptions GlobalCommandOptions
+}
+
+// VolumeCloneOptions specifies options for `nerdctl volume clone`.
+type VolumeCloneOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+}
@@ -17,6 +17,7 @@
 package apparmorutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -154,6 +155,67 @@ func Profiles() ([]Profile, error) {
 	return res, nil
 }
 
+// ProfilesDir is the directory of the profiles loaded on boot by the apparmor service.
+const ProfilesDir = "/etc/apparmor.d"
+
+// InstallHint is the hint for installing the default profile when nerdctl cannot load it by itself,
+// e.g., in rootless mode.
+const InstallHint = "run `nerdctl apparmor install --sudo-script | sudo sh`"
+
+// IsLoaded returns whether the profile is loaded.
+//
+// IsLoaded has the same requirements as Profiles, so it cannot be called from rootless child.
+func IsLoaded(name string) (bool, error) {
+	profiles, err := Profiles()
+	if err != nil {
+		return false, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RestrictsUnprivilegedUserNS returns whether AppArmor restricts unprivileged user namespaces
+// (sysctl kernel.apparmor_restrict_unprivileged_userns=1, the default since Ubuntu 24.04).
+// When restricted, RootlessKit needs a profile that allows creating user namespaces.
+func RestrictsUnprivilegedUserNS() bool {
+	b, err := os.ReadFile("/proc/sys/kernel/apparmor_restrict_unprivileged_userns")
+	return err == nil && strings.TrimSpace(string(b)) == "1"
+}
+
+// RootlessKitProfile returns the profile that allows the RootlessKit binary to create user namespaces,
+// with the file name for ProfilesDir (e.g., "home.foo.bin.rootlesskit").
+// See https://rootlesscontaine.rs/getting-started/common/apparmor/
+func RootlessKitProfile(rootlessKitPath string) (fileName, content string) {
+	fileName = strings.ReplaceAll(strings.TrimPrefix(rootlessKitPath, "/"), "/", ".")
+	content = fmt.Sprintf(`# ref: https://ubuntu.com/blog/ubuntu-23-10-restricted-unprivileged-user-namespaces
+abi <abi/4.0>,
+include <tunables/global>
+
+"%s" flags=(unconfined) {
+  userns,
+
+  # Site-specific additions and overrides. See local/README for details.
+  include if exists <local/%s>
+}
+`, rootlessKitPath, fileName)
+	return fileName, content
+}
+
+// LoadFile loads the profiles defined in the file, replacing the loaded ones with the same name.
+// Needs root and apparmor_parser.
+func LoadFile(path string) error {
+	// -K: do not write the profile cache, to match containerd's LoadDefaultProfile
+	cmd := exec.Command("apparmor_parser", "-Kr", path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to load AppArmor profile %q: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // Unload unloads a profile. Needs access to /sys/kernel/security/apparmor/.remove .
 func Unload(target string) error {
 	remover, err := os.OpenFile("/sys/kernel/security/apparmor/.remove", os.O_RDWR|os.O_TRUNC, 0644)
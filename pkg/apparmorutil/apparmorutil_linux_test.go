@@ -0,0 +1,32 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmorutil
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRootlessKitProfile(t *testing.T) {
+	fileName, content := RootlessKitProfile("/home/foo/bin/rootlesskit")
+	assert.Equal(t, fileName, "home.foo.bin.rootlesskit")
+	assert.Assert(t, strings.HasPrefix(content, "# ref: "))
+	assert.Assert(t, strings.Contains(content, "\"/home/foo/bin/rootlesskit\" flags=(unconfined) {\n  userns,\n"), content)
+	assert.Assert(t, strings.Contains(content, "include if exists <local/home.foo.bin.rootlesskit>\n"), content)
+}
@@ -0,0 +1,788 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package bake implements the docker-bake.hcl and docker-bake.json file formats used by `nerdctl bake`.
+package bake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DefaultFiles are the files read when no file is specified. Later files override earlier ones.
+var DefaultFiles = []string{
+	"docker-bake.json",
+	"docker-bake.hcl",
+	"docker-bake.override.json",
+	"docker-bake.override.hcl",
+}
+
+// DefaultGroup is the group built when no target is specified.
+const DefaultGroup = "default"
+
+// File is a bake file.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// ReadFiles reads the bake files. When no file is specified, the existing DefaultFiles in dir are read.
+func ReadFiles(dir string, names []string) ([]File, error) {
+	if len(names) == 0 {
+		for _, name := range DefaultFiles {
+			if _, err := os.Stat(filepath.Join(dir, name)); errors.Is(err, os.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("couldn't find a bake definition file (tried %s)", strings.Join(DefaultFiles, ", "))
+		}
+	}
+	files := make([]File, 0, len(names))
+	for _, name := range names {
+		p := name
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: name, Data: data})
+	}
+	return files, nil
+}
+
+// Target is a resolved bake target. The JSON representation follows the one printed by `docker buildx bake --print`.
+type Target struct {
+	Name             string            `json:"-"`
+	Description      string            `json:"description,omitempty"`
+	Context          *string           `json:"context,omitempty"`
+	Contexts         map[string]string `json:"contexts,omitempty"`
+	Dockerfile       *string           `json:"dockerfile,omitempty"`
+	DockerfileInline *string           `json:"dockerfile-inline,omitempty"`
+	Args             map[string]string `json:"args,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	CacheFrom        []string          `json:"cache-from,omitempty"`
+	CacheTo          []string          `json:"cache-to,omitempty"`
+	Target           *string           `json:"target,omitempty"`
+	Platforms        []string          `json:"platforms,omitempty"`
+	Secrets          []string          `json:"secret,omitempty"`
+	SSH              []string          `json:"ssh,omitempty"`
+	Outputs          []string          `json:"output,omitempty"`
+	Attest           []string          `json:"attest,omitempty"`
+	Network          *string           `json:"network,omitempty"`
+	NoCache          *bool             `json:"no-cache,omitempty"`
+	Pull             *bool             `json:"pull,omitempty"`
+
+	inherits []string
+}
+
+// Group is a named list of targets.
+type Group struct {
+	Targets []string `json:"targets"`
+}
+
+// Definition is the resolved bake definition, as printed by `nerdctl bake --print`.
+type Definition struct {
+	Group  map[string]*Group  `json:"group,omitempty"`
+	Target map[string]*Target `json:"target"`
+}
+
+// Config is the merged content of the bake files.
+type Config struct {
+	rootAttrs    map[string]*attribute
+	variables    map[string]map[string]*attribute
+	groups       map[string]map[string]*attribute
+	targets      map[string]map[string]*attribute
+	targetsOrder []string
+}
+
+// Parse parses and merges the bake files. Blocks with the same name are merged, and the
+// attributes of later files override those of earlier files.
+func Parse(files []File) (*Config, error) {
+	c := &Config{
+		rootAttrs: map[string]*attribute{},
+		variables: map[string]map[string]*attribute{},
+		groups:    map[string]map[string]*attribute{},
+		targets:   map[string]map[string]*attribute{},
+	}
+	for _, f := range files {
+		b, err := parseFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range b.attrs {
+			c.rootAttrs[a.name] = a
+		}
+		for _, blk := range b.blocks {
+			var dst map[string]map[string]*attribute
+			switch blk.typ {
+			case "variable":
+				dst = c.variables
+			case "group":
+				dst = c.groups
+			case "target":
+				dst = c.targets
+			default:
+				return nil, fmt.Errorf("%s: unsupported block type %q", blk.pos, blk.typ)
+			}
+			name := blk.label
+			if _, ok := dst[name]; !ok {
+				dst[name] = map[string]*attribute{}
+				if blk.typ == "target" {
+					c.targetsOrder = append(c.targetsOrder, name)
+				}
+			}
+			for _, a := range blk.attrs {
+				dst[name][a.name] = a
+			}
+		}
+	}
+	return c, nil
+}
+
+var (
+	targetNameRegexp        = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	invalidTargetNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+type instance struct {
+	attrs  map[string]*attribute
+	lookup scope
+}
+
+type resolver struct {
+	cfg       *Config
+	values    map[string]cty.Value
+	resolving map[string]bool
+	instances map[string]*instance
+	// expansions maps the name of a matrix target block to the names of its instances
+	expansions map[string][]string
+	resolved   map[string]*Target
+}
+
+// Resolve returns the targets selected by names (targets or groups, DefaultGroup when empty),
+// with the inheritance and matrix expanded and the overrides (`--set` values) applied.
+func (c *Config) Resolve(names []string, overrides []string) ([]*Target, error) {
+	r := &resolver{
+		cfg:        c,
+		values:     map[string]cty.Value{},
+		resolving:  map[string]bool{},
+		instances:  map[string]*instance{},
+		expansions: map[string][]string{},
+		resolved:   map[string]*Target{},
+	}
+	if err := r.expandMatrix(); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = []string{DefaultGroup}
+	}
+	var selected []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		expanded, err := r.expandName(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range expanded {
+			if !seen[n] {
+				seen[n] = true
+				selected = append(selected, n)
+			}
+		}
+	}
+	targets := make([]*Target, 0, len(selected))
+	for _, name := range selected {
+		t, err := r.target(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		t = t.clone()
+		targets = append(targets, t)
+	}
+	if err := applyOverrides(targets, overrides); err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if t.Context == nil {
+			t.Context = ptr(".")
+		}
+		if t.Dockerfile == nil && t.DockerfileInline == nil {
+			t.Dockerfile = ptr("Dockerfile")
+		}
+	}
+	return targets, nil
+}
+
+// lookup resolves the variables, from the environment or their default value, and the root attributes.
+func (r *resolver) lookup(name string) (cty.Value, bool, error) {
+	if v, ok := r.values[name]; ok {
+		return v, true, nil
+	}
+	var (
+		e        hcl.Expression
+		isVar    bool
+		hasValue bool
+	)
+	if attrs, ok := r.cfg.variables[name]; ok {
+		isVar = true
+		if def, ok := attrs["default"]; ok {
+			e, hasValue = def.expr, true
+		}
+	} else if a, ok := r.cfg.rootAttrs[name]; ok {
+		e, hasValue = a.expr, true
+	} else {
+		return cty.NilVal, false, nil
+	}
+	if r.resolving[name] {
+		return cty.NilVal, false, fmt.Errorf("variable %q references itself", name)
+	}
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+	v := cty.NullVal(cty.DynamicPseudoType)
+	if hasValue {
+		var err error
+		if v, err = evaluateValue(e, r.lookup); err != nil {
+			return cty.NilVal, false, err
+		}
+	}
+	if isVar {
+		if env, ok := os.LookupEnv(name); ok {
+			var err error
+			if v, err = convertEnv(name, env, v); err != nil {
+				return cty.NilVal, false, err
+			}
+		}
+	}
+	r.values[name] = v
+	return v, true, nil
+}
+
+// convertEnv converts the value of an environment variable to the type of the default value.
+func convertEnv(name, env string, def cty.Value) (cty.Value, error) {
+	switch def.Type() {
+	case cty.Bool:
+		b, err := strconv.ParseBool(env)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("failed to parse the environment variable %s as a bool: %w", name, err)
+		}
+		return cty.BoolVal(b), nil
+	case cty.Number:
+		n, err := cty.ParseNumberVal(env)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("failed to parse the environment variable %s as a number: %w", name, err)
+		}
+		return n, nil
+	default:
+		return cty.StringVal(env), nil
+	}
+}
+
+// expandMatrix creates the instances of the target blocks, one per combination of the matrix values.
+func (r *resolver) expandMatrix() error {
+	for _, blockName := range r.cfg.targetsOrder {
+		attrs := r.cfg.targets[blockName]
+		m, ok := attrs["matrix"]
+		if !ok {
+			r.instances[blockName] = &instance{attrs: attrs, lookup: r.lookup}
+			continue
+		}
+		matrix, err := evaluateValue(m.expr, r.lookup)
+		if err != nil {
+			return err
+		}
+		if matrix.IsNull() || !matrix.IsWhollyKnown() || !(matrix.Type().IsObjectType() || matrix.Type().IsMapType()) {
+			return fmt.Errorf("%s: the matrix of target %q must be an object", m.pos, blockName)
+		}
+		matrixValues := matrix.AsValueMap()
+		keys := sortedKeys(matrixValues)
+		combinations := []map[string]cty.Value{{}}
+		for _, k := range keys {
+			ty := matrixValues[k].Type()
+			if matrixValues[k].IsNull() || !(ty.IsListType() || ty.IsTupleType() || ty.IsSetType()) {
+				return fmt.Errorf("%s: the matrix value %q of target %q must be a list", m.pos, k, blockName)
+			}
+			values := matrixValues[k].AsValueSlice()
+			var next []map[string]cty.Value
+			for _, comb := range combinations {
+				for _, value := range values {
+					c := make(map[string]cty.Value, len(comb)+1)
+					for ck, cv := range comb {
+						c[ck] = cv
+					}
+					c[k] = value
+					next = append(next, c)
+				}
+			}
+			combinations = next
+		}
+		for _, comb := range combinations {
+			lookup := func(name string) (cty.Value, bool, error) {
+				if v, ok := comb[name]; ok {
+					return v, true, nil
+				}
+				return r.lookup(name)
+			}
+			var name string
+			if a, ok := attrs["name"]; ok {
+				v, err := evaluate(a.expr, lookup)
+				if err != nil {
+					return err
+				}
+				if name, err = toString(v); err != nil {
+					return fmt.Errorf("%s: invalid name of target %q: %w", a.pos, blockName, err)
+				}
+			} else {
+				parts := []string{blockName}
+				for _, k := range keys {
+					v, err := fromCty(comb[k])
+					if err != nil {
+						return fmt.Errorf("%s: invalid matrix value %q of target %q: %w", m.pos, k, blockName, err)
+					}
+					s, err := toString(v)
+					if err != nil {
+						return fmt.Errorf("%s: invalid matrix value %q of target %q: %w", m.pos, k, blockName, err)
+					}
+					parts = append(parts, s)
+				}
+				name = invalidTargetNameRegexp.ReplaceAllString(strings.Join(parts, "-"), "_")
+			}
+			if !targetNameRegexp.MatchString(name) {
+				return fmt.Errorf("invalid name %q of target %q, it must match %s", name, blockName, targetNameRegexp)
+			}
+			if _, ok := r.instances[name]; ok {
+				return fmt.Errorf("duplicate target name %q (expanded from the matrix of target %q)", name, blockName)
+			}
+			r.instances[name] = &instance{attrs: attrs, lookup: lookup}
+			r.expansions[blockName] = append(r.expansions[blockName], name)
+		}
+	}
+	return nil
+}
+
+// expandName expands a group or a matrix target block into the names of the target instances.
+func (r *resolver) expandName(name string, stack []string) ([]string, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("group %q references itself", name)
+		}
+	}
+	if attrs, ok := r.cfg.groups[name]; ok {
+		var res []string
+		if a, ok := attrs["targets"]; ok {
+			v, err := evaluate(a.expr, r.lookup)
+			if err != nil {
+				return nil, err
+			}
+			targets, err := asStringList(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid targets of group %q: %w", a.pos, name, err)
+			}
+			for _, t := range targets {
+				expanded, err := r.expandName(t, append(stack, name))
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, expanded...)
+			}
+		}
+		return res, nil
+	}
+	if expanded, ok := r.expansions[name]; ok {
+		return expanded, nil
+	}
+	if _, ok := r.instances[name]; ok {
+		return []string{name}, nil
+	}
+	return nil, fmt.Errorf("failed to find target %q", name)
+}
+
+// target decodes a target instance, and merges the targets it inherits from.
+func (r *resolver) target(name string, stack []string) (*Target, error) {
+	if t, ok := r.resolved[name]; ok {
+		return t, nil
+	}
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("target %q inherits from itself", name)
+		}
+	}
+	inst, ok := r.instances[name]
+	if !ok {
+		return nil, fmt.Errorf("failed to find target %q", name)
+	}
+	own := &Target{}
+	for _, attrName := range sortedKeys(inst.attrs) {
+		if attrName == "matrix" || attrName == "name" {
+			continue
+		}
+		a := inst.attrs[attrName]
+		v, err := evaluate(a.expr, inst.lookup)
+		if err != nil {
+			return nil, err
+		}
+		if err := own.set(attrName, v); err != nil {
+			return nil, fmt.Errorf("%s: target %q: %w", a.pos, name, err)
+		}
+	}
+	res := &Target{}
+	for _, parent := range own.inherits {
+		p, err := r.target(parent, append(stack, name))
+		if err != nil {
+			return nil, err
+		}
+		res.merge(p)
+	}
+	res.merge(own)
+	res.Name = name
+	res.inherits = nil
+	r.resolved[name] = res
+	return res, nil
+}
+
+func (t *Target) set(name string, v any) error {
+	var err error
+	switch name {
+	case "description":
+		t.Description, err = asString(v)
+	case "context":
+		t.Context, err = asStringPtr(v)
+	case "contexts":
+		t.Contexts, err = asStringMap(v)
+	case "dockerfile":
+		t.Dockerfile, err = asStringPtr(v)
+	case "dockerfile-inline":
+		t.DockerfileInline, err = asStringPtr(v)
+	case "args":
+		t.Args, err = asStringMap(v)
+	case "labels":
+		t.Labels, err = asStringMap(v)
+	case "tags":
+		t.Tags, err = asStringList(v)
+	case "cache-from":
+		t.CacheFrom, err = asStringList(v)
+	case "cache-to":
+		t.CacheTo, err = asStringList(v)
+	case "target":
+		t.Target, err = asStringPtr(v)
+	case "platforms":
+		t.Platforms, err = asStringList(v)
+	case "secret":
+		t.Secrets, err = asStringList(v)
+	case "ssh":
+		t.SSH, err = asStringList(v)
+	case "output":
+		t.Outputs, err = asStringList(v)
+	case "attest":
+		t.Attest, err = asStringList(v)
+	case "network":
+		t.Network, err = asStringPtr(v)
+	case "no-cache":
+		t.NoCache, err = asBoolPtr(v)
+	case "pull":
+		t.Pull, err = asBoolPtr(v)
+	case "inherits":
+		t.inherits, err = asStringList(v)
+	default:
+		return fmt.Errorf("unsupported attribute %q", name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value of %q: %w", name, err)
+	}
+	return nil
+}
+
+// merge overrides the fields of t with the fields set in src. Maps are merged by key.
+func (t *Target) merge(src *Target) {
+	if src.Description != "" {
+		t.Description = src.Description
+	}
+	mergePtr(&t.Context, src.Context)
+	mergePtr(&t.Dockerfile, src.Dockerfile)
+	mergePtr(&t.DockerfileInline, src.DockerfileInline)
+	mergePtr(&t.Target, src.Target)
+	mergePtr(&t.Network, src.Network)
+	mergePtr(&t.NoCache, src.NoCache)
+	mergePtr(&t.Pull, src.Pull)
+	mergeMap(&t.Contexts, src.Contexts)
+	mergeMap(&t.Args, src.Args)
+	mergeMap(&t.Labels, src.Labels)
+	mergeList(&t.Tags, src.Tags)
+	mergeList(&t.CacheFrom, src.CacheFrom)
+	mergeList(&t.CacheTo, src.CacheTo)
+	mergeList(&t.Platforms, src.Platforms)
+	mergeList(&t.Secrets, src.Secrets)
+	mergeList(&t.SSH, src.SSH)
+	mergeList(&t.Outputs, src.Outputs)
+	mergeList(&t.Attest, src.Attest)
+	mergeList(&t.inherits, src.inherits)
+}
+
+func (t *Target) clone() *Target {
+	c := &Target{Name: t.Name}
+	c.merge(t)
+	return c
+}
+
+func mergePtr[T any](dst **T, src *T) {
+	if src != nil {
+		v := *src
+		*dst = &v
+	}
+}
+
+func mergeMap(dst *map[string]string, src map[string]string) {
+	if src == nil {
+		return
+	}
+	m := make(map[string]string, len(*dst)+len(src))
+	for k, v := range *dst {
+		m[k] = v
+	}
+	for k, v := range src {
+		m[k] = v
+	}
+	*dst = m
+}
+
+func mergeList(dst *[]string, src []string) {
+	if src != nil {
+		*dst = append([]string{}, src...)
+	}
+}
+
+// applyOverrides applies the `--set` values, in the form of "<target pattern>.<key>=<value>".
+// A list key set several times accumulates the values, which replace those of the file.
+func applyOverrides(targets []*Target, overrides []string) error {
+	seen := map[string]bool{}
+	for _, o := range overrides {
+		keyPath, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return fmt.Errorf("invalid override %q, expected <target>.<key>=<value>", o)
+		}
+		pattern, key, ok := strings.Cut(keyPath, ".")
+		if !ok || pattern == "" || key == "" {
+			return fmt.Errorf("invalid override %q, expected <target>.<key>=<value>", o)
+		}
+		matched := false
+		for _, t := range targets {
+			if ok, err := path.Match(pattern, t.Name); err != nil {
+				return fmt.Errorf("invalid target pattern %q: %w", pattern, err)
+			} else if !ok {
+				continue
+			}
+			matched = true
+			first := !seen[t.Name+"."+key]
+			seen[t.Name+"."+key] = true
+			if err := t.override(key, value, first); err != nil {
+				return fmt.Errorf("invalid override %q: %w", o, err)
+			}
+		}
+		if !matched {
+			return fmt.Errorf("invalid override %q: no target matches %q", o, pattern)
+		}
+	}
+	return nil
+}
+
+func (t *Target) override(key, value string, first bool) error {
+	appendList := func(dst *[]string, values ...string) {
+		if first {
+			*dst = nil
+		}
+		*dst = append(*dst, values...)
+	}
+	if mapName, k, ok := strings.Cut(key, "."); ok {
+		var dst *map[string]string
+		switch mapName {
+		case "args":
+			dst = &t.Args
+		case "labels":
+			dst = &t.Labels
+		case "contexts":
+			dst = &t.Contexts
+		default:
+			return fmt.Errorf("unsupported key %q", key)
+		}
+		mergeMap(dst, map[string]string{k: value})
+		return nil
+	}
+	switch key {
+	case "context":
+		t.Context = &value
+	case "dockerfile":
+		t.Dockerfile = &value
+	case "target":
+		t.Target = &value
+	case "network":
+		t.Network = &value
+	case "tags":
+		appendList(&t.Tags, value)
+	case "cache-from":
+		appendList(&t.CacheFrom, value)
+	case "cache-to":
+		appendList(&t.CacheTo, value)
+	case "platform", "platforms":
+		appendList(&t.Platforms, strings.Split(value, ",")...)
+	case "secrets", "secret":
+		appendList(&t.Secrets, value)
+	case "ssh":
+		appendList(&t.SSH, value)
+	case "output":
+		appendList(&t.Outputs, value)
+	case "attest":
+		appendList(&t.Attest, value)
+	case "no-cache", "pull", "push", "load":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of %q, expected a bool", value, key)
+		}
+		switch key {
+		case "no-cache":
+			t.NoCache = &b
+		case "pull":
+			t.Pull = &b
+		case "push":
+			if b {
+				appendList(&t.Outputs, "type=image,push=true")
+			}
+		case "load":
+			if b {
+				appendList(&t.Outputs, "type=docker")
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported key %q", key)
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func asString(v any) (string, error) {
+	if _, ok := v.(string); !ok {
+		switch v.(type) {
+		case float64, bool:
+		default:
+			return "", fmt.Errorf("expected a string, got %v", v)
+		}
+	}
+	return toString(v)
+}
+
+func asStringPtr(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	s, err := asString(v)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func asBoolPtr(v any) (*bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return &v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("expected a bool, got %q", v)
+		}
+		return &b, nil
+	default:
+		return nil, fmt.Errorf("expected a bool, got %v", v)
+	}
+}
+
+func asStringList(v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	l, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %v", v)
+	}
+	res := make([]string, 0, len(l))
+	for _, item := range l {
+		if item == nil {
+			continue
+		}
+		s, err := asString(item)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// asStringMap converts an object to a map of strings. Null values are ignored.
+func asStringMap(v any) (map[string]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %v", v)
+	}
+	res := make(map[string]string, len(m))
+	for k, item := range m {
+		if item == nil {
+			continue
+		}
+		s, err := asString(item)
+		if err != nil {
+			return nil, err
+		}
+		res[k] = s
+	}
+	return res, nil
+}
+
+// NewDefinition returns the definition of the resolved targets, as printed by `nerdctl bake --print`.
+// The selected targets are listed in the default group.
+func NewDefinition(targets []*Target) *Definition {
+	def := &Definition{
+		Group:  map[string]*Group{DefaultGroup: {Targets: []string{}}},
+		Target: map[string]*Target{},
+	}
+	for _, t := range targets {
+		def.Target[t.Name] = t
+		def.Group[DefaultGroup].Targets = append(def.Group[DefaultGroup].Targets, t.Name)
+	}
+	return def
+}
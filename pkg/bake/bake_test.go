@@ -0,0 +1,306 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bake
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func resolve(t *testing.T, files []File, names []string, overrides []string) ([]*Target, error) {
+	t.Helper()
+	cfg, err := Parse(files)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Resolve(names, overrides)
+}
+
+func TestResolveHCL(t *testing.T) {
+	t.Setenv("TAG", "v1")
+	const hcl = `
+# comment
+variable "TAG" {
+  default = "latest"
+}
+variable "REGISTRY" {
+  default = "example.com" // comment
+}
+variable "IMAGE" {
+  default = "${REGISTRY}/app"
+}
+
+group "default" {
+  targets = ["app", "docs"]
+}
+
+/* base
+   target */
+target "base" {
+  context = "."
+  args = {
+    GO_VERSION = "1.24"
+    DEBUG      = 0
+  }
+  platforms = ["linux/amd64"]
+}
+
+target "app" {
+  inherits = ["base"]
+  dockerfile = "Dockerfile.app"
+  args = {
+    DEBUG = 1
+  }
+  tags = ["${IMAGE}:${TAG}", "${IMAGE}:$${literal}"]
+  no-cache = true
+  dockerfile-inline = <<-EOT
+    FROM alpine
+    RUN echo "${upper(TAG)}"
+    EOT
+}
+
+target "docs" {
+  context = "docs"
+  target  = "html"
+}
+`
+	targets, err := resolve(t, []File{{Name: "docker-bake.hcl", Data: []byte(hcl)}}, nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(targets), 2)
+
+	app := targets[0]
+	assert.Equal(t, app.Name, "app")
+	assert.Equal(t, *app.Context, ".")
+	assert.Equal(t, *app.Dockerfile, "Dockerfile.app")
+	assert.DeepEqual(t, app.Args, map[string]string{"GO_VERSION": "1.24", "DEBUG": "1"})
+	assert.DeepEqual(t, app.Tags, []string{"example.com/app:v1", "example.com/app:${literal}"})
+	assert.DeepEqual(t, app.Platforms, []string{"linux/amd64"})
+	assert.Equal(t, *app.NoCache, true)
+	assert.Equal(t, *app.DockerfileInline, "FROM alpine\nRUN echo \"V1\"\n")
+
+	docs := targets[1]
+	assert.Equal(t, docs.Name, "docs")
+	assert.Equal(t, *docs.Context, "docs")
+	assert.Equal(t, *docs.Dockerfile, "Dockerfile")
+	assert.Equal(t, *docs.Target, "html")
+}
+
+func TestResolveJSON(t *testing.T) {
+	const json = `{
+  "variable": {"TAG": {"default": "latest"}},
+  "group": {"default": {"targets": ["app"]}},
+  "target": {
+    "app": {
+      "context": "app",
+      "tags": ["app:${TAG}"],
+      "labels": {"org.example.version": "${TAG}"},
+      "pull": true
+    }
+  }
+}`
+	const override = `
+target "app" {
+  tags = ["app:override"]
+}
+`
+	targets, err := resolve(t, []File{
+		{Name: "docker-bake.json", Data: []byte(json)},
+		{Name: "docker-bake.override.hcl", Data: []byte(override)},
+	}, nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(targets), 1)
+	assert.Equal(t, *targets[0].Context, "app")
+	assert.DeepEqual(t, targets[0].Tags, []string{"app:override"})
+	assert.DeepEqual(t, targets[0].Labels, map[string]string{"org.example.version": "latest"})
+	assert.Equal(t, *targets[0].Pull, true)
+}
+
+func TestResolveMatrix(t *testing.T) {
+	const hcl = `
+target "app" {
+  name = "app-${tgt}-${replace(ver, ".", "-")}"
+  matrix = {
+    tgt = ["release", "debug"]
+    ver = ["1.0", "2.0"]
+  }
+  target = tgt
+  args = {
+    VERSION = ver
+  }
+}
+target "other" {
+  matrix = {
+    v = ["a", "b"]
+  }
+  tags = ["other:${v}"]
+}
+`
+	files := []File{{Name: "docker-bake.hcl", Data: []byte(hcl)}}
+	targets, err := resolve(t, files, []string{"app"}, nil)
+	assert.NilError(t, err)
+	var names []string
+	for _, tgt := range targets {
+		names = append(names, tgt.Name)
+	}
+	assert.DeepEqual(t, names, []string{"app-release-1-0", "app-release-2-0", "app-debug-1-0", "app-debug-2-0"})
+	assert.Equal(t, *targets[1].Target, "release")
+	assert.DeepEqual(t, targets[1].Args, map[string]string{"VERSION": "2.0"})
+
+	targets, err = resolve(t, files, []string{"other-b"}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(targets), 1)
+	assert.DeepEqual(t, targets[0].Tags, []string{"other:b"})
+}
+
+func TestResolveExpressions(t *testing.T) {
+	t.Setenv("DEBUG", "true")
+	const hcl = `
+variable "DEBUG" {
+  default = false
+}
+variable "VERSIONS" {
+  default = ["1.0", "2.0"]
+}
+target "app" {
+  tags = [for v in VERSIONS : "app:${v}${DEBUG ? "-debug" : ""}"]
+  args = {
+    COUNT   = length(VERSIONS) * 2
+    LATEST  = element(VERSIONS, length(VERSIONS) - 1)
+    VERSION = try(NOPE, "none")
+  }
+  no-cache = !DEBUG
+}
+`
+	targets, err := resolve(t, []File{{Name: "docker-bake.hcl", Data: []byte(hcl)}}, []string{"app"}, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, targets[0].Tags, []string{"app:1.0-debug", "app:2.0-debug"})
+	assert.DeepEqual(t, targets[0].Args, map[string]string{"COUNT": "4", "LATEST": "2.0", "VERSION": "none"})
+	assert.Equal(t, *targets[0].NoCache, false)
+}
+
+func TestResolveOverrides(t *testing.T) {
+	const hcl = `
+target "app" {
+  tags = ["app:latest"]
+  args = {
+    A = "1"
+  }
+}
+target "db" {
+  platforms = ["linux/amd64"]
+}
+`
+	targets, err := resolve(t, []File{{Name: "docker-bake.hcl", Data: []byte(hcl)}}, []string{"app", "db"}, []string{
+		"app.tags=app:v1",
+		"app.tags=app:v2",
+		"app.args.B=2",
+		"*.platform=linux/arm64,linux/amd64",
+		"d*.no-cache=true",
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, targets[0].Tags, []string{"app:v1", "app:v2"})
+	assert.DeepEqual(t, targets[0].Args, map[string]string{"A": "1", "B": "2"})
+	assert.DeepEqual(t, targets[0].Platforms, []string{"linux/arm64", "linux/amd64"})
+	assert.Assert(t, targets[0].NoCache == nil)
+	assert.DeepEqual(t, targets[1].Platforms, []string{"linux/arm64", "linux/amd64"})
+	assert.Equal(t, *targets[1].NoCache, true)
+}
+
+func TestResolveErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		hcl       string
+		targets   []string
+		overrides []string
+		err       string
+	}{
+		{
+			name: "missing default group",
+			hcl:  `target "app" {}`,
+			err:  `failed to find target "default"`,
+		},
+		{
+			name:    "unknown variable",
+			hcl:     `target "app" { tags = ["${NOPE}"] }`,
+			targets: []string{"app"},
+			err:     `docker-bake.hcl:1,27-31: Unknown variable; There is no variable named "NOPE".`,
+		},
+		{
+			name:    "unsupported function",
+			hcl:     `target "app" { tags = [nope()] }`,
+			targets: []string{"app"},
+			err:     `There is no function named "nope"`,
+		},
+		{
+			name:    "unsupported attribute",
+			hcl:     `target "app" { foo = "bar" }`,
+			targets: []string{"app"},
+			err:     `unsupported attribute "foo"`,
+		},
+		{
+			name:    "inheritance cycle",
+			hcl:     "target \"a\" {\n inherits = [\"b\"]\n}\ntarget \"b\" {\n inherits = [\"a\"]\n}",
+			targets: []string{"a"},
+			err:     `target "a" inherits from itself`,
+		},
+		{
+			name:    "group cycle",
+			hcl:     `group "default" { targets = ["default"] }`,
+			targets: nil,
+			err:     `group "default" references itself`,
+		},
+		{
+			name:    "syntax error",
+			hcl:     "target \"app\" {\n  tags = [\"a\" \"b\"]\n}",
+			targets: []string{"app"},
+			err:     `docker-bake.hcl:2,15-16: Missing item separator`,
+		},
+		{
+			name:    "unsupported block type",
+			hcl:     `function "f" {}`,
+			targets: []string{"app"},
+			err:     `docker-bake.hcl:1: unsupported block type "function"`,
+		},
+		{
+			name:    "nested block",
+			hcl:     "target \"app\" {\n  foo {}\n}",
+			targets: []string{"app"},
+			err:     `Unexpected "foo" block`,
+		},
+		{
+			name:      "override without match",
+			hcl:       `target "app" {}`,
+			targets:   []string{"app"},
+			overrides: []string{"db.tags=db"},
+			err:       `no target matches "db"`,
+		},
+		{
+			name:      "override with unsupported key",
+			hcl:       `target "app" {}`,
+			targets:   []string{"app"},
+			overrides: []string{"app.foo=bar"},
+			err:       `unsupported key "foo"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := resolve(t, []File{{Name: "docker-bake.hcl", Data: []byte(tc.hcl)}}, tc.targets, tc.overrides)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
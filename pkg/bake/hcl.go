@@ -0,0 +1,139 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bake
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// The bake files are parsed with github.com/hashicorp/hcl/v2, in the native syntax or in the JSON syntax,
+// into the attributes of their top-level body and of their blocks. The expressions of the attributes are
+// only evaluated when the targets are resolved, as they may reference variables and matrix values.
+
+type attribute struct {
+	name string
+	expr hcl.Expression
+	pos  string
+}
+
+type block struct {
+	typ   string
+	label string
+	attrs []*attribute
+	pos   string
+}
+
+type body struct {
+	attrs  []*attribute
+	blocks []*block
+}
+
+// bodySchema is the schema of the top-level body of a bake file. The other top-level attributes are
+// variables too, and blocks of other types are rejected.
+var bodySchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "group", LabelNames: []string{"name"}},
+		{Type: "target", LabelNames: []string{"name"}},
+	},
+}
+
+// parseFile parses the bake file f, in the JSON syntax when its name ends with ".json" or its content is an object.
+func parseFile(f File) (*body, error) {
+	p := hclparse.NewParser()
+	var (
+		file  *hcl.File
+		diags hcl.Diagnostics
+	)
+	if strings.HasSuffix(f.Name, ".json") || bytes.HasPrefix(bytes.TrimSpace(f.Data), []byte("{")) {
+		file, diags = p.ParseJSON(f.Data, f.Name)
+	} else {
+		file, diags = p.ParseHCL(f.Data, f.Name)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	content, remain, diags := file.Body.PartialContent(bodySchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	b := &body{}
+	if sb, ok := file.Body.(*hclsyntax.Body); ok {
+		// JustAttributes of the native syntax rejects the blocks of the schema too
+		for _, a := range sb.Attributes {
+			b.attrs = append(b.attrs, &attribute{name: a.Name, expr: a.Expr, pos: pos(a.NameRange)})
+		}
+		sort.Slice(b.attrs, func(i, j int) bool { return b.attrs[i].name < b.attrs[j].name })
+		// the blocks of other types are ignored by PartialContent, and rejected by Parse
+		for _, blk := range sb.Blocks {
+			if !slices.ContainsFunc(bodySchema.Blocks, func(s hcl.BlockHeaderSchema) bool { return s.Type == blk.Type }) {
+				b.blocks = append(b.blocks, &block{typ: blk.Type, pos: pos(blk.TypeRange)})
+			}
+		}
+	} else {
+		rootAttrs, diags := remain.JustAttributes()
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		b.attrs = attributes(rootAttrs)
+	}
+	for _, blk := range content.Blocks {
+		// nested blocks are rejected by JustAttributes
+		attrs, diags := blk.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		b.blocks = append(b.blocks, &block{
+			typ:   blk.Type,
+			label: blk.Labels[0],
+			attrs: attributes(attrs),
+			pos:   pos(blk.DefRange),
+		})
+	}
+	return b, nil
+}
+
+// attributes returns the attributes sorted by name.
+func attributes(attrs hcl.Attributes) []*attribute {
+	res := make([]*attribute, 0, len(attrs))
+	for _, a := range attrs {
+		res = append(res, &attribute{name: a.Name, expr: a.Expr, pos: pos(a.NameRange)})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+func pos(r hcl.Range) string {
+	return fmt.Sprintf("%s:%d", r.Filename, r.Start.Line)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,197 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package builderstore persists the named builders created with `nerdctl builder create`,
+// and the builder selected with `nerdctl builder use`.
+// Builders are global to the data root (not namespaced), as a buildkitd may serve multiple namespaces.
+package builderstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+const (
+	instancesDir = "instances"
+	currentKey   = "current"
+)
+
+// Builder is a named BuildKit endpoint.
+type Builder struct {
+	Name string `json:"Name"`
+	// BuildKitHost is the address of buildkitd, e.g. "unix:///run/buildkit/buildkitd.sock"
+	BuildKitHost string `json:"BuildKitHost"`
+	// Platforms are the platforms the builder is declared to build for
+	Platforms []string `json:"Platforms,omitempty"`
+}
+
+// Store manages the named builders.
+type Store interface {
+	// Get returns the builder with the given name, or an error wrapping errdefs.ErrNotFound
+	Get(name string) (*Builder, error)
+	// List returns all the builders, sorted by name
+	List() ([]*Builder, error)
+	// Create saves a new builder. It errors with errdefs.ErrAlreadyExists if the name is taken.
+	Create(builder *Builder) error
+	// Remove removes the builder, and unselects it if it was the current one
+	Remove(name string) error
+	// Current returns the name of the builder selected with Use, or "" if none is selected
+	Current() (string, error)
+	// Use selects the builder with the given name. An empty name unselects the current builder.
+	// The name is not checked against the stored builders, so that built-in builders can be selected too.
+	Use(name string) error
+}
+
+// New returns the builder store under the data root.
+func New(dataRoot string) (Store, error) {
+	st, err := store.New(filepath.Join(dataRoot, "builders"), 0o755, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create builder store: %w", err)
+	}
+	return &builderStore{store: st}, nil
+}
+
+type builderStore struct {
+	store store.Store
+}
+
+func (s *builderStore) Get(name string) (*Builder, error) {
+	var builder *Builder
+	err := s.store.WithLock(func() error {
+		var err error
+		builder, err = s.get(name)
+		return err
+	})
+	return builder, err
+}
+
+func (s *builderStore) List() ([]*Builder, error) {
+	var builders []*Builder
+	err := s.store.WithLock(func() error {
+		names, err := s.store.List(instancesDir)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			builder, err := s.get(name)
+			if err != nil {
+				return err
+			}
+			builders = append(builders, builder)
+		}
+		return nil
+	})
+	return builders, err
+}
+
+func (s *builderStore) Create(builder *Builder) error {
+	if err := identifiers.ValidateDockerCompat(builder.Name); err != nil {
+		return fmt.Errorf("invalid builder name %q: %w", builder.Name, err)
+	}
+	data, err := json.Marshal(builder)
+	if err != nil {
+		return err
+	}
+	return s.store.WithLock(func() error {
+		exists, err := s.store.Exists(instancesDir, builder.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("builder %q already exists: %w", builder.Name, errdefs.ErrAlreadyExists)
+		}
+		return s.store.Set(data, instancesDir, builder.Name)
+	})
+}
+
+func (s *builderStore) Remove(name string) error {
+	return s.store.WithLock(func() error {
+		if err := s.store.Delete(instancesDir, name); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return fmt.Errorf("builder %q not found: %w", name, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		current, err := s.current()
+		if err != nil {
+			return err
+		}
+		if current == name {
+			return s.store.Delete(currentKey)
+		}
+		return nil
+	})
+}
+
+func (s *builderStore) Current() (string, error) {
+	var current string
+	err := s.store.WithLock(func() error {
+		var err error
+		current, err = s.current()
+		return err
+	})
+	return current, err
+}
+
+func (s *builderStore) Use(name string) error {
+	return s.store.WithLock(func() error {
+		if name == "" {
+			if err := s.store.Delete(currentKey); err != nil && !errors.Is(err, store.ErrNotFound) {
+				return err
+			}
+			return nil
+		}
+		return s.store.Set([]byte(name), currentKey)
+	})
+}
+
+func (s *builderStore) get(name string) (*Builder, error) {
+	data, err := s.store.Get(instancesDir, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("builder %q not found: %w", name, errdefs.ErrNotFound)
+		}
+		return nil, err
+	}
+	var builder Builder
+	if err := json.Unmarshal(data, &builder); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal builder %q: %w", name, err)
+	}
+	return &builder, nil
+}
+
+func (s *builderStore) current() (string, error) {
+	data, err := s.store.Get(currentKey)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builderstore
+
+import (
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuilderStore(t *testing.T) {
+	st, err := New(t.TempDir())
+	assert.NilError(t, err)
+
+	builders, err := st.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(builders), 0)
+
+	current, err := st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "")
+
+	rootful := &Builder{Name: "rootful", BuildKitHost: "unix:///run/buildkit/buildkitd.sock"}
+	rootless := &Builder{Name: "rootless", BuildKitHost: "unix:///run/user/1000/buildkit/buildkitd.sock", Platforms: []string{"linux/arm64"}}
+	assert.NilError(t, st.Create(rootless))
+	assert.NilError(t, st.Create(rootful))
+	assert.ErrorIs(t, st.Create(rootful), errdefs.ErrAlreadyExists)
+	assert.ErrorContains(t, st.Create(&Builder{Name: "in/valid"}), "invalid builder name")
+
+	builders, err = st.List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, builders, []*Builder{rootful, rootless})
+
+	got, err := st.Get("rootless")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, rootless)
+	_, err = st.Get("missing")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+
+	assert.NilError(t, st.Use("rootless"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "rootless")
+
+	// removing another builder keeps the selection
+	assert.NilError(t, st.Remove("rootful"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "rootless")
+
+	// removing the current builder unselects it
+	assert.NilError(t, st.Remove("rootless"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "")
+	assert.ErrorIs(t, st.Remove("rootless"), errdefs.ErrNotFound)
+
+	// unselecting without a selection is a no-op
+	assert.NilError(t, st.Use(""))
+}
@@ -60,6 +60,15 @@ func BuildctlBaseArgs(buildkitHost string) []string {
 }
 
 func GetBuildkitHost(namespace string) (string, error) {
+	buildkitHost, err := DetectBuildkitHost(namespace)
+	if err != nil && os.Getenv("BUILDKIT_HOST") == "" {
+		log.L.WithError(err).Error(getHint())
+	}
+	return buildkitHost, err
+}
+
+// DetectBuildkitHost is like GetBuildkitHost but does not log a hint on failure.
+func DetectBuildkitHost(namespace string) (string, error) {
 	if buildkitHost := os.Getenv("BUILDKIT_HOST"); buildkitHost != "" {
 		if _, err := pingBKDaemon(buildkitHost); err != nil {
 			return "", err
@@ -83,7 +92,6 @@ func GetBuildkitHost(namespace string) (string, error) {
 		errs = append(errs, fmt.Errorf("failed to ping to host %s: %w", buildkitHost, err))
 	}
 	allErr := errors.Join(errs...)
-	log.L.WithError(allErr).Error(getHint())
 	return "", fmt.Errorf("no buildkit host is available, tried %d candidates: %w", len(paths), allErr)
 }
 
@@ -125,7 +133,8 @@ func GetWorkerLabels(buildkitHost string) (labels map[string]string, _ error) {
 func getHint() string {
 	hint := "`buildctl` needs to be installed and `buildkitd` needs to be running, see https://github.com/moby/buildkit"
 	if rootlessutil.IsRootless() {
-		hint += " , and `containerd-rootless-setuptool.sh install-buildkit` for OCI worker or `containerd-rootless-setuptool.sh install-buildkit-containerd` for containerd worker"
+		hint += " , and `containerd-rootless-setuptool.sh install-buildkit` for OCI worker or `containerd-rootless-setuptool.sh install-buildkit-containerd` for containerd worker" +
+			" (or `nerdctl build --provision-buildkit` to start buildkitd on demand)"
 	}
 	return hint
 }
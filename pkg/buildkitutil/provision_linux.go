@@ -0,0 +1,117 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package buildkitutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// provisionTimeout is how long ProvisionRootless waits for the provisioned buildkitd to respond.
+const provisionTimeout = 30 * time.Second
+
+// ProvisionRootless starts a per-user buildkitd with the containerd worker for the namespace,
+// in the same way as `containerd-rootless-setuptool.sh install-buildkit-containerd` but as a
+// detached process instead of a systemd unit. The socket is one of the candidates of
+// GetBuildkitHost, so that the next builds find the provisioned buildkitd.
+func ProvisionRootless(namespace, containerdAddress, snapshotter string) (string, error) {
+	if !rootlessutil.IsRootless() {
+		return "", errors.New("provisioning buildkitd is only supported in rootless mode")
+	}
+	if namespace == "" {
+		return "", fmt.Errorf("namespace must be specified")
+	}
+	buildkitd, err := exec.LookPath("buildkitd")
+	if err != nil {
+		return "", fmt.Errorf("buildkitd (https://github.com/moby/buildkit) needs to be present under $PATH: %w", err)
+	}
+	run, err := getRuntimeVariableDataDir()
+	if err != nil {
+		return "", err
+	}
+	dataHome, err := rootlessutil.XDGDataHome()
+	if err != nil {
+		return "", err
+	}
+	sock := filepath.Join(run, "buildkit-"+namespace, "buildkitd.sock")
+	root := filepath.Join(dataHome, "buildkit-"+namespace)
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return "", err
+	}
+	logPath := filepath.Join(root, "buildkitd.log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(buildkitd, rootlessBuildkitdArgs(namespace, containerdAddress, snapshotter, sock, root)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// buildkitd keeps running after nerdctl exits
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start buildkitd: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	buildkitHost := "unix://" + sock
+	deadline := time.After(provisionTimeout)
+	for {
+		select {
+		case err := <-exited:
+			return "", fmt.Errorf("buildkitd exited (%v), see %s", err, logPath)
+		case <-deadline:
+			return "", fmt.Errorf("buildkitd (pid %d) did not respond on %s within %s, see %s", cmd.Process.Pid, buildkitHost, provisionTimeout, logPath)
+		case <-time.After(200 * time.Millisecond):
+		}
+		if _, err := pingBKDaemon(buildkitHost); err == nil {
+			log.L.Infof("Started buildkitd (pid %d) on %s, logs are written to %s", cmd.Process.Pid, buildkitHost, logPath)
+			return buildkitHost, nil
+		}
+	}
+}
+
+func rootlessBuildkitdArgs(namespace, containerdAddress, snapshotter, sock, root string) []string {
+	args := []string{
+		"--addr=unix://" + sock,
+		"--root=" + root,
+		"--oci-worker=false",
+		"--containerd-worker=true",
+		"--containerd-worker-rootless=true",
+		"--containerd-worker-namespace=" + namespace,
+	}
+	if containerdAddress != "" {
+		args = append(args, "--containerd-worker-addr="+containerdAddress)
+	}
+	if snapshotter != "" {
+		args = append(args, "--containerd-worker-snapshotter="+snapshotter)
+	}
+	return args
+}
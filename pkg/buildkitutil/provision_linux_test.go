@@ -0,0 +1,48 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package buildkitutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRootlessBuildkitdArgs(t *testing.T) {
+	args := rootlessBuildkitdArgs("default", "/run/containerd/containerd.sock", "overlayfs",
+		"/run/user/1000/buildkit-default/buildkitd.sock", "/home/user/.local/share/buildkit-default")
+	assert.DeepEqual(t, args, []string{
+		"--addr=unix:///run/user/1000/buildkit-default/buildkitd.sock",
+		"--root=/home/user/.local/share/buildkit-default",
+		"--oci-worker=false",
+		"--containerd-worker=true",
+		"--containerd-worker-rootless=true",
+		"--containerd-worker-namespace=default",
+		"--containerd-worker-addr=/run/containerd/containerd.sock",
+		"--containerd-worker-snapshotter=overlayfs",
+	})
+
+	args = rootlessBuildkitdArgs("ns", "", "", "/run/user/1000/buildkit-ns/buildkitd.sock", "/data/buildkit-ns")
+	assert.DeepEqual(t, args, []string{
+		"--addr=unix:///run/user/1000/buildkit-ns/buildkitd.sock",
+		"--root=/data/buildkit-ns",
+		"--oci-worker=false",
+		"--containerd-worker=true",
+		"--containerd-worker-rootless=true",
+		"--containerd-worker-namespace=ns",
+	})
+}
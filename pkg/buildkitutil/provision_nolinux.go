@@ -0,0 +1,26 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package buildkitutil
+
+import "errors"
+
+// ProvisionRootless is only supported on Linux.
+func ProvisionRootless(namespace, containerdAddress, snapshotter string) (string, error) {
+	return "", errors.New("provisioning buildkitd is only supported in rootless mode on Linux")
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bypass4netnsutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// IsBypass4netnsdRunning returns whether bypass4netnsd is listening on the default socket.
+func IsBypass4netnsdRunning() (bool, error) {
+	socketPath, err := GetBypass4NetnsdDefaultSocketPath()
+	if err != nil {
+		return false, err
+	}
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return false, nil
+	}
+	conn.Close()
+	return true, nil
+}
+
+// StartBypass4netnsd starts bypass4netnsd in the background, unless it is already running.
+// It has to be called in the namespaces of the host, not in the namespaces of RootlessKit.
+//
+// The daemon started by this function is not managed by systemd, so
+// `containerd-rootless-setuptool.sh install-bypass4netnsd` is still recommended.
+func StartBypass4netnsd(ctx context.Context) error {
+	running, err := IsBypass4netnsdRunning()
+	if err != nil || running {
+		return err
+	}
+	bin, err := exec.LookPath("bypass4netnsd")
+	if err != nil {
+		return fmt.Errorf("bypass4netnsd is not running and cannot be started (Hint: install bypass4netns, "+
+			"then run `containerd-rootless-setuptool.sh install-bypass4netnsd`): %w", err)
+	}
+	xdgRuntimeDir, err := rootlessutil.XDGRuntimeDir()
+	if err != nil {
+		return err
+	}
+	logFilePath := filepath.Join(xdgRuntimeDir, "bypass4netnsd.log")
+	cmd := exec.Command(bin,
+		"--pid-file="+filepath.Join(xdgRuntimeDir, "bypass4netnsd.pid"),
+		"--log-file="+logFilePath,
+	)
+	// detach from the session of nerdctl, so that the daemon survives nerdctl
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start bypass4netnsd: %w", err)
+	}
+	log.G(ctx).Infof("Started bypass4netnsd (pid=%d, log=%s)", cmd.Process.Pid, logFilePath)
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	for range 50 {
+		select {
+		case err := <-exited:
+			return fmt.Errorf("bypass4netnsd exited (see %s): %w", logFilePath, err)
+		case <-time.After(100 * time.Millisecond):
+		}
+		if running, _ := IsBypass4netnsdRunning(); running {
+			return cmd.Process.Release()
+		}
+	}
+	return fmt.Errorf("timed out waiting for bypass4netnsd to listen (see %s)", logFilePath)
+}
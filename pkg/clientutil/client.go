@@ -25,28 +25,35 @@ import (
 	"strings"
 
 	"github.com/opencontainers/go-digest"
+	"google.golang.org/grpc"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 	"github.com/containerd/nerdctl/v2/pkg/systemutil"
 )
 
 func NewClient(ctx context.Context, namespace, address string, opts ...containerd.Opt) (*containerd.Client, context.Context, context.CancelFunc, error) {
 	ctx = namespaces.WithNamespace(ctx, namespace)
 
-	address = strings.TrimPrefix(address, "unix://")
-	const dockerContainerdaddress = "/var/run/docker/containerd/containerd.sock"
-	if err := systemutil.IsSocketAccessible(address); err != nil {
-		if systemutil.IsSocketAccessible(dockerContainerdaddress) == nil {
-			err = fmt.Errorf("cannot access containerd socket %q (hint: try running with `--address %s` to connect to Docker-managed containerd): %w", address, dockerContainerdaddress, err)
-		} else {
-			err = fmt.Errorf("cannot access containerd socket %q: %w", address, err)
+	if sshutil.IsAddress(address) {
+		host, err := sshutil.ParseAddress(address)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// gRPC connects to the socket on the remote host over ssh, instead of the local socket
+		opts = append(opts, containerd.WithExtraDialOpts([]grpc.DialOption{grpc.WithContextDialer(host.Dial)}))
+		address = host.Socket
+	} else {
+		address = strings.TrimPrefix(address, "unix://")
+		if err := checkSocket(address); err != nil {
+			return nil, nil, nil, err
 		}
-		return nil, nil, nil, err
 	}
 	client, err := containerd.New(address, opts...)
 	if err != nil {
@@ -57,6 +64,20 @@ func NewClient(ctx context.Context, namespace, address string, opts ...container
 	return client, ctx, cancel, nil
 }
 
+// checkSocket returns an error with a hint when the local containerd socket is not accessible.
+func checkSocket(address string) error {
+	const dockerContainerdaddress = "/var/run/docker/containerd/containerd.sock"
+	if err := systemutil.IsSocketAccessible(address); err != nil {
+		if systemutil.IsSocketAccessible(dockerContainerdaddress) == nil {
+			err = fmt.Errorf("cannot access containerd socket %q (hint: try running with `--address %s` to connect to Docker-managed containerd): %w", address, dockerContainerdaddress, err)
+		} else {
+			err = fmt.Errorf("cannot access containerd socket %q: %w", address, err)
+		}
+		return err
+	}
+	return nil
+}
+
 func NewClientWithPlatform(ctx context.Context, namespace, address, platform string, clientOpts ...containerd.Opt) (*containerd.Client, context.Context, context.CancelFunc, error) {
 	if platform != "" {
 		if canExec, canExecErr := platformutil.CanExecProbably(platform); !canExec {
@@ -82,6 +103,9 @@ func NewClientWithPlatform(ctx context.Context, namespace, address, platform str
 // "1935db9" is from `$(echo -n "/run/containerd/containerd.sock" | sha256sum | cut -c1-8)`
 // on Windows it will return "%PROGRAMFILES%/nerdctl/1935db59"
 func DataStore(dataRoot, address string) (string, error) {
+	if err := CheckLocalState(address); err != nil {
+		return "", err
+	}
 	if err := os.MkdirAll(dataRoot, 0700); err != nil {
 		return "", err
 	}
@@ -96,10 +120,20 @@ func DataStore(dataRoot, address string) (string, error) {
 	return dataStore, nil
 }
 
+// CheckLocalState returns an error wrapping errdefs.ErrNotImplemented for an SSH address,
+// as the nerdctl state of the containers (the data store and the CNI configs) is on the remote host.
+func CheckLocalState(address string) error {
+	if sshutil.IsAddress(address) {
+		return fmt.Errorf("this command reads the nerdctl state on the host of containerd, which is not supported over SSH, use a context with `nerdctl context create --ssh` to run nerdctl on the remote host instead: %w", errdefs.ErrNotImplemented)
+	}
+	return nil
+}
+
 func getAddrHash(addr string) (string, error) {
 	const addrHashLen = 8
 
-	if runtime.GOOS != "windows" {
+	// the SSH addresses are hashed as they are, as the sockets are on the remote hosts
+	if runtime.GOOS != "windows" && !sshutil.IsAddress(addr) {
 		addr = strings.TrimPrefix(addr, "unix://")
 
 		var err error
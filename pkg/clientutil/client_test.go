@@ -0,0 +1,34 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package clientutil
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestDataStoreOverSSH(t *testing.T) {
+	dataRoot := t.TempDir()
+	_, err := DataStore(dataRoot, "ssh://user@host.example.com")
+	assert.Assert(t, errors.Is(err, errdefs.ErrNotImplemented), err)
+
+	assert.NilError(t, CheckLocalState("/run/containerd/containerd.sock"))
+}
@@ -0,0 +1,167 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/v2/contrib/apparmor"
+	"github.com/containerd/containerd/v2/contrib/seccomp"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+type profileFile struct {
+	// Name is the file name in apparmorutil.ProfilesDir
+	Name    string
+	Content string
+}
+
+// profileFiles returns the default profile, and the profile of RootlessKit if AppArmor restricts
+// unprivileged user namespaces.
+func profileFiles() ([]profileFile, error) {
+	b, err := apparmor.DumpDefaultProfile(defaults.AppArmorProfileName)
+	if err != nil {
+		return nil, err
+	}
+	files := []profileFile{{Name: defaults.AppArmorProfileName, Content: b}}
+	if !apparmorutil.RestrictsUnprivilegedUserNS() {
+		return files, nil
+	}
+	// When executed with sudo, RootlessKit is looked up in the home of the user who invoked sudo.
+	u, err := user.Current()
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && os.Geteuid() == 0 {
+		u, err = user.Lookup(sudoUser)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rlk, err := rootlessutil.LookRootlessKit(u.HomeDir)
+	if err != nil {
+		log.L.WithError(err).Warn("RootlessKit is not found, skipping its AppArmor profile")
+		return files, nil
+	}
+	name, content := apparmorutil.RootlessKitProfile(rlk)
+	return append(files, profileFile{Name: name, Content: content}), nil
+}
+
+// Install installs the default profile, and the profile of RootlessKit when needed, into /etc/apparmor.d, and loads them.
+// The default seccomp profile is installed into the directory of nerdctl.toml too, see installSeccompProfile.
+//
+// Loading AppArmor profiles always needs root, and the profiles can't be loaded from the home directory either.
+// Without root, an error with a hint is returned, so that rootless containers do not silently run unconfined.
+func Install(options types.ApparmorInstallOptions) error {
+	if err := installSeccompProfile(seccompProfilePath()); err != nil {
+		return err
+	}
+	files, err := profileFiles()
+	if err != nil {
+		return err
+	}
+	if options.SudoScript {
+		return writeSudoScript(options.Stdout, files)
+	}
+	if !apparmorutil.CanLoadNewProfile() {
+		if os.Geteuid() == 0 || !apparmorutil.CanApplyExistingProfile() {
+			return errors.New("the host does not support loading AppArmor profiles")
+		}
+		return fmt.Errorf("loading AppArmor profiles requires root (Hint: %s)", apparmorutil.InstallHint)
+	}
+	for _, f := range files {
+		path := filepath.Join(apparmorutil.ProfilesDir, f.Name)
+		log.L.Infof("Writing profile %q to %q", f.Name, path)
+		if err := os.WriteFile(path, []byte(f.Content), 0o644); err != nil {
+			return err
+		}
+		log.L.Infof("Loading profile file %q", path)
+		if err := apparmorutil.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSudoScript writes a shell script that installs and loads the profiles.
+func writeSudoScript(w io.Writer, files []profileFile) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Generated by `nerdctl apparmor install --sudo-script`. Execute with `sudo sh`.\nset -eu\n")
+	for _, f := range files {
+		path := filepath.Join(apparmorutil.ProfilesDir, f.Name)
+		const eof = "NERDCTL_APPARMOR_EOF"
+		if strings.Contains(f.Content, eof) {
+			return fmt.Errorf("profile %q must not contain %q", f.Name, eof)
+		}
+		fmt.Fprintf(&b, "\ncat <<'%s' >%s\n%s", eof, strutil.ShellQuote(path), f.Content)
+		if !strings.HasSuffix(f.Content, "\n") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\napparmor_parser -Kr %s\n", eof, strutil.ShellQuote(path))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// seccompProfilePath returns the path of the seccomp profile installed by installSeccompProfile,
+// next to nerdctl.toml (i.e., under XDG_CONFIG_HOME in rootless mode).
+func seccompProfilePath() string {
+	return filepath.Join(filepath.Dir(defaults.NerdctlTOML()), "seccomp", "default.json")
+}
+
+// installSeccompProfile writes the builtin seccomp profile for the default capabilities to path as a JSON file,
+// so that it can be customized and specified with `seccomp_profile` of nerdctl.toml.
+// Unlike AppArmor profiles, seccomp profiles are applied without root, so the profile is written to the
+// user-scope config directory in rootless mode. An existing file is not overwritten.
+func installSeccompProfile(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		log.L.Infof("Seccomp profile %q already exists, skipping", path)
+		return nil
+	}
+	ctx := namespaces.WithNamespace(context.Background(), namespaces.Default)
+	spec, err := oci.GenerateSpec(ctx, nil, &containers.Container{ID: "nerdctl-seccomp"})
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(seccomp.DefaultProfile(spec), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	log.L.Infof("Writing seccomp profile to %q", path)
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	log.L.Infof("Set `seccomp_profile = %q` in %q to use the profile by default", path, defaults.NerdctlTOML())
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apparmor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteSudoScript(t *testing.T) {
+	var b bytes.Buffer
+	err := writeSudoScript(&b, []profileFile{
+		{Name: "nerdctl-default", Content: "profile nerdctl-default {}\n"},
+		{Name: "home.it's me.bin.rootlesskit", Content: "profile rootlesskit {}"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, b.String(), `#!/bin/sh
+# Generated by `+"`nerdctl apparmor install --sudo-script`. Execute with `sudo sh`."+`
+set -eu
+
+cat <<'NERDCTL_APPARMOR_EOF' >/etc/apparmor.d/nerdctl-default
+profile nerdctl-default {}
+NERDCTL_APPARMOR_EOF
+apparmor_parser -Kr /etc/apparmor.d/nerdctl-default
+
+cat <<'NERDCTL_APPARMOR_EOF' >'/etc/apparmor.d/home.it'"'"'s me.bin.rootlesskit'
+profile rootlesskit {}
+NERDCTL_APPARMOR_EOF
+apparmor_parser -Kr '/etc/apparmor.d/home.it'"'"'s me.bin.rootlesskit'
+`)
+}
+
+func TestWriteSudoScriptRejectsDelimiter(t *testing.T) {
+	var b bytes.Buffer
+	err := writeSudoScript(&b, []profileFile{{Name: "foo", Content: "NERDCTL_APPARMOR_EOF\nrm -rf /\n"}})
+	assert.ErrorContains(t, err, "must not contain")
+	assert.Equal(t, b.Len(), 0)
+}
+
+func TestInstallSeccompProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seccomp", "default.json")
+	assert.NilError(t, installSeccompProfile(path))
+	b, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	var profile specs.LinuxSeccomp
+	assert.NilError(t, json.Unmarshal(b, &profile))
+	assert.Equal(t, profile.DefaultAction, specs.ActErrno)
+	assert.Assert(t, len(profile.Syscalls) > 0)
+
+	// an existing profile is not overwritten
+	assert.NilError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	assert.NilError(t, installSeccompProfile(path))
+	b, err = os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "{}")
+}
@@ -17,13 +17,57 @@
 package apparmor
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/containerd/containerd/v2/contrib/apparmor"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
 )
 
-func Load() error {
-	log.L.Infof("Loading profile %q", defaults.AppArmorProfileName)
-	return apparmor.LoadDefaultProfile(defaults.AppArmorProfileName)
+func Load(options types.ApparmorLoadOptions) error {
+	if len(options.Files) == 0 {
+		if !options.Persist {
+			log.L.Infof("Loading profile %q", defaults.AppArmorProfileName)
+			return apparmor.LoadDefaultProfile(defaults.AppArmorProfileName)
+		}
+		b, err := apparmor.DumpDefaultProfile(defaults.AppArmorProfileName)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(apparmorutil.ProfilesDir, defaults.AppArmorProfileName)
+		log.L.Infof("Writing profile %q to %q", defaults.AppArmorProfileName, path)
+		if err := os.WriteFile(path, []byte(b), 0644); err != nil {
+			return err
+		}
+		log.L.Infof("Loading profile %q", defaults.AppArmorProfileName)
+		return apparmorutil.LoadFile(path)
+	}
+
+	for _, f := range options.Files {
+		path := f
+		if options.Persist {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(apparmorutil.ProfilesDir, filepath.Base(f))
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%q already exists, refusing to overwrite it", path)
+			}
+			log.L.Infof("Writing profile file %q to %q", f, path)
+			if err := os.WriteFile(path, b, 0644); err != nil {
+				return err
+			}
+		}
+		log.L.Infof("Loading profile file %q", path)
+		if err := apparmorutil.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
 }
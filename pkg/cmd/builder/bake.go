@@ -0,0 +1,156 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/bake"
+)
+
+// Bake builds the targets of the bake files, one after another, by running `nerdctl build`.
+func Bake(ctx context.Context, options types.BuilderBakeOptions) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	files, err := bake.ReadFiles(wd, options.Files)
+	if err != nil {
+		return err
+	}
+	cfg, err := bake.Parse(files)
+	if err != nil {
+		return err
+	}
+	targets, err := cfg.Resolve(options.Targets, options.Set)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if options.NoCache {
+			t.NoCache = &options.NoCache
+		}
+		if options.Pull {
+			t.Pull = &options.Pull
+		}
+	}
+	if options.Print {
+		enc := json.NewEncoder(options.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(bake.NewDefinition(targets))
+	}
+	for _, t := range targets {
+		args, err := bakeArgs(t)
+		if err != nil {
+			return fmt.Errorf("target %q: %w", t.Name, err)
+		}
+		if options.Progress != "" {
+			args = append([]string{"--progress=" + options.Progress}, args...)
+		}
+		if options.Builder != "" {
+			args = append([]string{"--builder=" + options.Builder}, args...)
+		}
+		fmt.Fprintf(options.Stderr, "Building target %q\n", t.Name)
+		cmd := exec.CommandContext(ctx, options.NerdctlCmd, append(append(slices.Clone(options.NerdctlArgs), "build"), args...)...)
+		if t.DockerfileInline != nil {
+			cmd.Stdin = strings.NewReader(*t.DockerfileInline)
+		}
+		cmd.Stdout = options.Stdout
+		cmd.Stderr = options.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to build target %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// bakeArgs returns the arguments of `nerdctl build` for a resolved target.
+func bakeArgs(t *bake.Target) ([]string, error) {
+	buildContext := *t.Context
+	_, _, isRemote := parseRemoteContext(buildContext)
+	var args []string
+	switch {
+	case t.DockerfileInline != nil:
+		args = append(args, "--file=-")
+	case isRemote || filepath.IsAbs(*t.Dockerfile):
+		// The Dockerfile of a remote context is a path in the context
+		args = append(args, "--file="+*t.Dockerfile)
+	default:
+		args = append(args, "--file="+filepath.Join(buildContext, *t.Dockerfile))
+	}
+	for _, tag := range t.Tags {
+		args = append(args, "--tag="+tag)
+	}
+	if t.Target != nil {
+		args = append(args, "--target="+*t.Target)
+	}
+	for _, k := range slices.Sorted(maps.Keys(t.Args)) {
+		args = append(args, "--build-arg="+k+"="+t.Args[k])
+	}
+	for _, k := range slices.Sorted(maps.Keys(t.Labels)) {
+		args = append(args, "--label="+k+"="+t.Labels[k])
+	}
+	if len(t.Platforms) > 0 {
+		args = append(args, "--platform="+strings.Join(t.Platforms, ","))
+	}
+	for _, v := range t.CacheFrom {
+		args = append(args, "--cache-from="+v)
+	}
+	for _, v := range t.CacheTo {
+		args = append(args, "--cache-to="+v)
+	}
+	for _, v := range t.Secrets {
+		args = append(args, "--secret="+v)
+	}
+	for _, v := range t.SSH {
+		args = append(args, "--ssh="+v)
+	}
+	switch len(t.Outputs) {
+	case 0:
+	case 1:
+		args = append(args, "--output="+t.Outputs[0])
+	default:
+		return nil, fmt.Errorf("multiple outputs are not supported: %w", errdefs.ErrNotImplemented)
+	}
+	for _, v := range t.Attest {
+		args = append(args, "--attest="+v)
+	}
+	for _, k := range slices.Sorted(maps.Keys(t.Contexts)) {
+		args = append(args, "--build-context="+k+"="+t.Contexts[k])
+	}
+	if t.Network != nil {
+		args = append(args, "--network="+*t.Network)
+	}
+	if t.NoCache != nil && *t.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if t.Pull != nil && *t.Pull {
+		args = append(args, "--pull")
+	}
+	return append(args, buildContext), nil
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/bake"
+)
+
+func TestBakeArgs(t *testing.T) {
+	str := func(s string) *string { return &s }
+	yes := true
+	tests := []struct {
+		name   string
+		target *bake.Target
+		args   []string
+		err    string
+	}{
+		{
+			name:   "minimal",
+			target: &bake.Target{Context: str("."), Dockerfile: str("Dockerfile")},
+			args:   []string{"--file=Dockerfile", "."},
+		},
+		{
+			name: "full",
+			target: &bake.Target{
+				Context:    str("app"),
+				Dockerfile: str("Dockerfile.app"),
+				Tags:       []string{"app:v1", "app:latest"},
+				Target:     str("release"),
+				Args:       map[string]string{"B": "2", "A": "1"},
+				Labels:     map[string]string{"org.example": "x"},
+				Platforms:  []string{"linux/amd64", "linux/arm64"},
+				CacheFrom:  []string{"type=local,src=/tmp/cache"},
+				CacheTo:    []string{"type=local,dest=/tmp/cache"},
+				Secrets:    []string{"id=token,env=TOKEN"},
+				SSH:        []string{"default"},
+				Outputs:    []string{"type=docker"},
+				Attest:     []string{"type=sbom"},
+				Contexts:   map[string]string{"base": "docker-image://alpine"},
+				Network:    str("host"),
+				NoCache:    &yes,
+				Pull:       &yes,
+			},
+			args: []string{
+				"--file=app/Dockerfile.app", "--tag=app:v1", "--tag=app:latest", "--target=release",
+				"--build-arg=A=1", "--build-arg=B=2", "--label=org.example=x", "--platform=linux/amd64,linux/arm64",
+				"--cache-from=type=local,src=/tmp/cache", "--cache-to=type=local,dest=/tmp/cache",
+				"--secret=id=token,env=TOKEN", "--ssh=default", "--output=type=docker", "--attest=type=sbom",
+				"--build-context=base=docker-image://alpine", "--network=host", "--no-cache", "--pull", "app",
+			},
+		},
+		{
+			name:   "remote context",
+			target: &bake.Target{Context: str("https://github.com/org/repo.git#main"), Dockerfile: str("build/Dockerfile")},
+			args:   []string{"--file=build/Dockerfile", "https://github.com/org/repo.git#main"},
+		},
+		{
+			name:   "inline Dockerfile",
+			target: &bake.Target{Context: str("."), DockerfileInline: str("FROM alpine\n")},
+			args:   []string{"--file=-", "."},
+		},
+		{
+			name:   "multiple outputs",
+			target: &bake.Target{Context: str("."), Dockerfile: str("Dockerfile"), Outputs: []string{"type=docker", "type=local,dest=out"}},
+			err:    "multiple outputs are not supported",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := bakeArgs(tc.target)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, args, tc.args)
+		})
+	}
+}
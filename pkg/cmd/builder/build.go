@@ -18,17 +18,21 @@ package builder
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/images"
@@ -40,11 +44,13 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 type PlatformParser interface {
@@ -62,7 +68,37 @@ func (p platformParser) DefaultSpec() platforms.Platform {
 	return platforms.DefaultSpec()
 }
 
-func Build(ctx context.Context, client *containerd.Client, options types.BuilderBuildOptions) error {
+// ProgressRawJSON is the --progress type that streams the BuildKit solve status as JSON lines on stderr.
+const ProgressRawJSON = "rawjson"
+
+// progressTypes are the --progress types supported by buildctl.
+var progressTypes = []string{"auto", "plain", "tty", ProgressRawJSON}
+
+func Build(ctx context.Context, client *containerd.Client, options types.BuilderBuildOptions) (retErr error) {
+	ctx, span := tracingutil.StartSpan(ctx, "nerdctl.image.build", attribute.StringSlice("image.tags", options.Tag))
+	defer func() { tracingutil.EndSpan(span, retErr) }()
+
+	if options.Progress != "" && !slices.Contains(progressTypes, options.Progress) {
+		return fmt.Errorf("unsupported --progress type %q, expected one of %v: %w", options.Progress, progressTypes, errdefs.ErrInvalidArgument)
+	}
+	switch {
+	case options.Invoke == "":
+	case options.Invoke != InvokeOnError:
+		return fmt.Errorf("unsupported --invoke value %q, expected %q: %w", options.Invoke, InvokeOnError, errdefs.ErrInvalidArgument)
+	case options.Builder != BuilderContainerd:
+		// Invoking a shell in a BuildKit build needs the gateway API, see `nerdctl builder debug` for debugging with buildg
+		return fmt.Errorf("--invoke is only supported with --builder=%s (Hint: use `nerdctl builder debug` for BuildKit builds): %w", BuilderContainerd, errdefs.ErrNotImplemented)
+	case options.File == "-":
+		return fmt.Errorf("--invoke needs the standard input, and can't be used with a Dockerfile from stdin: %w", errdefs.ErrInvalidArgument)
+	}
+	switch options.Call {
+	case "", CallBuild:
+	default:
+		return call(ctx, client, options)
+	}
+	if options.Builder == BuilderContainerd {
+		return buildWithContainerd(ctx, client, options)
+	}
 	buildctlBinary, buildctlArgs, needsLoading, metaFile, tags, cleanup, err := generateBuildctlArgs(ctx, client, options)
 	if err != nil {
 		return err
@@ -73,7 +109,8 @@ func Build(ctx context.Context, client *containerd.Client, options types.Builder
 
 	log.L.Debugf("running %s %v", buildctlBinary, buildctlArgs)
 	buildctlCmd := exec.Command(buildctlBinary, buildctlArgs...)
-	buildctlCmd.Env = os.Environ()
+	// Propagate the trace context, so that the spans of buildctl (and BuildKit) are recorded under the build span
+	buildctlCmd.Env = append(os.Environ(), tracingutil.Environ(ctx)...)
 
 	var buildctlStdout io.Reader
 	if needsLoading {
@@ -97,7 +134,10 @@ func Build(ctx context.Context, client *containerd.Client, options types.Builder
 		if err != nil {
 			return err
 		}
-		if err = loadImage(ctx, buildctlStdout, options.GOptions.Namespace, options.GOptions.Address, options.GOptions.Snapshotter, options.Stdout, platMC, options.Quiet); err != nil {
+		loadCtx, loadSpan := tracingutil.StartSpan(ctx, "nerdctl.image.build.load")
+		err = loadImage(loadCtx, buildctlStdout, options.GOptions.Namespace, options.GOptions.Address, options.GOptions.Snapshotter, options.Stdout, platMC, hasAttestations(options.Attest), options.Quiet)
+		tracingutil.EndSpan(loadSpan, err)
+		if err != nil {
 			return err
 		}
 	}
@@ -116,7 +156,8 @@ func Build(ctx context.Context, client *containerd.Client, options types.Builder
 		}
 	}
 
-	if len(tags) > 1 {
+	// With only --push, the image is not stored locally, and all the tags were pushed by BuildKit
+	if len(tags) > 1 && (!options.Push || options.Load) {
 		log.L.Debug("Found more than 1 tag")
 		imageService := client.ImageService()
 		image, err := imageService.Get(ctx, tags[0])
@@ -144,13 +185,40 @@ func Build(ctx context.Context, client *containerd.Client, options types.Builder
 	return nil
 }
 
+// hasAttestations returns whether any of the --attest values requests an attestation
+// (i.e., is not "disabled=true").
+func hasAttestations(attest []string) bool {
+	for _, s := range attest {
+		disabled := false
+		for _, field := range strings.Split(s, ",") {
+			if v, ok := strings.CutPrefix(field, "disabled="); ok {
+				disabled, _ = strconv.ParseBool(v)
+			}
+		}
+		if !disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// attestationMatchComparer also matches the "unknown/unknown" platform of attestation manifests,
+// so that they are imported along with the image manifests they describe.
+type attestationMatchComparer struct {
+	platforms.MatchComparer
+}
+
+func (m attestationMatchComparer) Match(p ocispec.Platform) bool {
+	return (p.OS == "unknown" && p.Architecture == "unknown") || m.MatchComparer.Match(p)
+}
+
 // TODO: This struct and `loadImage` are duplicated with the code in `cmd/load.go`, remove it after `load.go` has been refactor
 type readCounter struct {
 	io.Reader
 	N int
 }
 
-func loadImage(ctx context.Context, in io.Reader, namespace, address, snapshotter string, output io.Writer, platMC platforms.MatchComparer, quiet bool) error {
+func loadImage(ctx context.Context, in io.Reader, namespace, address, snapshotter string, output io.Writer, platMC platforms.MatchComparer, withAttestations, quiet bool) error {
 	// In addition to passing WithImagePlatform() to client.Import(), we also need to pass WithDefaultPlatform() to NewClient().
 	// Otherwise unpacking may fail.
 	client, ctx, cancel, err := clientutil.NewClient(ctx, namespace, address, containerd.WithDefaultPlatform(platMC))
@@ -161,8 +229,12 @@ func loadImage(ctx context.Context, in io.Reader, namespace, address, snapshotte
 		cancel()
 		client.Close()
 	}()
+	importMC := platMC
+	if withAttestations {
+		importMC = attestationMatchComparer{platMC}
+	}
 	r := &readCounter{Reader: in}
-	imgs, err := client.Import(ctx, r, containerd.WithDigestRef(archive.DigestTranslator(snapshotter)), containerd.WithSkipDigestRef(func(name string) bool { return name != "" }), containerd.WithImportPlatform(platMC))
+	imgs, err := client.Import(ctx, r, containerd.WithDigestRef(archive.DigestTranslator(snapshotter)), containerd.WithSkipDigestRef(func(name string) bool { return name != "" }), containerd.WithImportPlatform(importMC))
 	if err != nil {
 		if r.N == 0 {
 			// Avoid confusing "unrecognized image format"
@@ -202,8 +274,32 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 		return "", nil, false, "", nil, nil, err
 	}
 
+	if (options.Push || options.Load) && options.Output != "" {
+		return "", nil, false, "", nil, nil, fmt.Errorf("--push and --load can't be combined with --output: %w", errdefs.ErrInvalidArgument)
+	}
+	if tags = strutil.DedupeStrSlice(options.Tag); len(tags) > 0 {
+		for idx, tag := range tags {
+			parsedReference, err := referenceutil.Parse(tag)
+			if err != nil {
+				return "", nil, false, "", nil, nil, err
+			}
+			tags[idx] = parsedReference.String()
+		}
+	} else if options.Push {
+		return "", nil, false, "", nil, nil, fmt.Errorf("--push needs an image name (-t, --tag): %w", errdefs.ErrInvalidArgument)
+	}
+
 	output := options.Output
-	if output == "" {
+	// pushOutput is an additional exporter for --push --load, when the image is loaded from the output of buildctl
+	var pushOutput string
+	switch {
+	case callRequests[options.Call] != "":
+		// the subrequests of the frontend return a result without exporting anything
+		output = ""
+	case output == "" && options.Push && !options.Load:
+		// push directly from BuildKit, without storing the image locally
+		output = "type=image,push=true"
+	case output == "":
 		info, err := client.Server(ctx)
 		if err != nil {
 			return "", nil, false, "", nil, nil, err
@@ -214,60 +310,97 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 		}
 		if sharable {
 			output = "type=image,unpack=true" // ensure the target stage is unlazied (needed for any snapshotters)
+			if options.Push {
+				output += ",push=true"
+			}
 		} else {
 			output = "type=docker"
-			if len(options.Platform) > 1 {
+			if len(options.Platform) > 1 || hasAttestations(options.Attest) {
 				// For avoiding `error: failed to solve: docker exporter does not currently support exporting manifest lists`
+				// The attestation manifests are also only stored in an OCI index.
 				// TODO: consider using type=oci for single-options.Platform build too
 				output = "type=oci"
 			}
 			needsLoading = true
+			if options.Push {
+				// Multiple exporters need BuildKit >= 0.13
+				pushOutput = csvJoin("type=image", "push=true", "name="+strings.Join(tags, ","))
+			}
 		}
-	} else {
+	default:
 		if !strings.Contains(output, "type=") {
 			// should accept --output <DIR> as an alias of --output
 			// type=local,dest=<DIR>
 			output = fmt.Sprintf("type=local,dest=%s", output)
 		}
+		if strings.Contains(output, "type=docker") && len(options.Platform) > 1 {
+			return "", nil, false, "", nil, nil, fmt.Errorf("a multi-platform image can't be exported with %q, as the docker format does not support manifest lists "+
+				"(Hint: use type=oci, or omit --output to load the image into the image store): %w", output, errdefs.ErrInvalidArgument)
+		}
 		if strings.Contains(output, "type=docker") || strings.Contains(output, "type=oci") {
 			if !strings.Contains(output, "dest=") {
 				needsLoading = true
 			}
 		}
 	}
-	if tags = strutil.DedupeStrSlice(options.Tag); len(tags) > 0 {
-		ref := tags[0]
-		parsedReference, err := referenceutil.Parse(ref)
-		if err != nil {
-			return "", nil, false, "", nil, nil, err
+	if options.Push && options.GOptions.InsecureRegistry {
+		if pushOutput != "" {
+			pushOutput += ",registry.insecure=true"
+		} else {
+			output += ",registry.insecure=true"
 		}
-		output += ",name=" + parsedReference.String()
-
+	}
+	switch {
+	case output == "":
+	case strings.Contains(output, "push=true") && len(tags) > 1:
+		// all the tags are pushed, not only the first one
+		output += "," + csvJoin("name="+strings.Join(tags, ","))
+	case len(tags) > 0:
 		// pick the first tag and add it to output
-		for idx, tag := range tags {
-			parsedReference, err = referenceutil.Parse(tag)
-			if err != nil {
-				return "", nil, false, "", nil, nil, err
-			}
-			tags[idx] = parsedReference.String()
-		}
-	} else if len(tags) == 0 {
+		output += ",name=" + tags[0]
+	default:
 		output = output + ",dangling-name-prefix=<none>"
 	}
 
 	buildctlArgs = buildkitutil.BuildctlBaseArgs(options.BuildKitHost)
 
+	remoteContext, isGitContext, isRemoteContext := parseRemoteContext(options.BuildContext)
 	buildctlArgs = append(buildctlArgs, []string{
 		"build",
 		"--progress=" + options.Progress,
 		"--frontend=dockerfile.v0",
-		"--local=context=" + options.BuildContext,
-		"--output=" + output,
 	}...)
+	if isRemoteContext {
+		buildctlArgs = append(buildctlArgs, "--opt=context="+remoteContext)
+	} else {
+		buildctlArgs = append(buildctlArgs, "--local=context="+options.BuildContext)
+	}
+	if output != "" {
+		buildctlArgs = append(buildctlArgs, "--output="+output)
+	}
+	if req := callRequests[options.Call]; req != "" {
+		buildctlArgs = append(buildctlArgs, "--opt=requestid="+req)
+	}
+	if pushOutput != "" {
+		buildctlArgs = append(buildctlArgs, "--output="+pushOutput)
+	}
+
+	var gitArgs []string
+	if isGitContext {
+		gitArgs = append(gitArgs, gitAuthArgs(remoteContext, options.Secret, options.SSH)...)
+	}
 
 	dir := options.BuildContext
 	file := buildkitutil.DefaultDockerfileName
-	if options.File != "" {
+	if isRemoteContext {
+		// The Dockerfile is read from the remote context, -f is a path in the context
+		if options.File == "-" {
+			return "", nil, false, "", nil, nil, fmt.Errorf("reading the Dockerfile from stdin is not supported with a remote build context: %w", errdefs.ErrNotImplemented)
+		}
+		if options.File != "" {
+			file = options.File
+		}
+	} else if options.File != "" {
 		if options.File == "-" {
 			// Super Warning: this is a special trick to update the dir variable, Don't move this line!!!!!!
 			var err error
@@ -286,9 +419,11 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 			dir = "."
 		}
 	}
-	dir, file, err = buildkitutil.BuildKitFile(dir, file)
-	if err != nil {
-		return "", nil, false, "", nil, nil, err
+	if !isRemoteContext {
+		dir, file, err = buildkitutil.BuildKitFile(dir, file)
+		if err != nil {
+			return "", nil, false, "", nil, nil, err
+		}
 	}
 
 	buildCtx, err := parseContextNames(options.ExtendedBuildContext)
@@ -297,10 +432,15 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 	}
 
 	for k, v := range buildCtx {
-		isURL := strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://")
-		isDockerImage := strings.HasPrefix(v, "docker-image://") || strings.HasPrefix(v, "target:")
+		if remote, isGit, ok := parseRemoteContext(v); ok {
+			buildctlArgs = append(buildctlArgs, fmt.Sprintf("--opt=context:%s=%s", k, remote))
+			if isGit {
+				gitArgs = append(gitArgs, gitAuthArgs(remote, options.Secret, options.SSH)...)
+			}
+			continue
+		}
 
-		if isURL || isDockerImage {
+		if isDockerImage := strings.HasPrefix(v, "docker-image://") || strings.HasPrefix(v, "target:"); isDockerImage {
 			buildctlArgs = append(buildctlArgs, fmt.Sprintf("--opt=context:%s=%s", k, v))
 			continue
 		}
@@ -323,7 +463,11 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 		buildctlArgs = append(buildctlArgs, fmt.Sprintf("--opt=context:%s=local:%s", k, k))
 	}
 
-	buildctlArgs = append(buildctlArgs, "--local=dockerfile="+dir)
+	buildctlArgs = append(buildctlArgs, strutil.DedupeStrSlice(gitArgs)...)
+
+	if !isRemoteContext {
+		buildctlArgs = append(buildctlArgs, "--local=dockerfile="+dir)
+	}
 	buildctlArgs = append(buildctlArgs, "--opt=filename="+file)
 
 	if options.Target != "" {
@@ -394,6 +538,10 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 	}
 
 	for _, s := range strutil.DedupeStrSlice(options.Secret) {
+		s, err = parseSecretOption(s)
+		if err != nil {
+			return "", nil, false, "", nil, cleanup, err
+		}
 		buildctlArgs = append(buildctlArgs, "--secret="+s)
 	}
 
@@ -425,15 +573,17 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 	}
 
 	for _, s := range strutil.DedupeStrSlice(options.CacheFrom) {
-		if !strings.Contains(s, "type=") {
-			s = "type=registry,ref=" + s
+		s, err = parseCacheOption(s, false)
+		if err != nil {
+			return "", nil, false, "", nil, cleanup, err
 		}
 		buildctlArgs = append(buildctlArgs, "--import-cache="+s)
 	}
 
 	for _, s := range strutil.DedupeStrSlice(options.CacheTo) {
-		if !strings.Contains(s, "type=") {
-			s = "type=registry,ref=" + s
+		s, err = parseCacheOption(s, true)
+		if err != nil {
+			return "", nil, false, "", nil, cleanup, err
 		}
 		buildctlArgs = append(buildctlArgs, "--export-cache="+s)
 	}
@@ -460,7 +610,9 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 			buildctlArgs = append(buildctlArgs, "--opt=force-network-mode="+options.NetworkMode, "--allow=network.host", "--allow=security.insecure")
 		case "", "default":
 		default:
-			log.L.Debugf("ignoring network build arg %s", options.NetworkMode)
+			// BuildKit runs RUN instructions either in its own CNI network, or without a network, or in the host network.
+			return "", nil, false, "", nil, cleanup, fmt.Errorf("network %q is not supported by BuildKit, only default, none and host are supported "+
+				"(Hint: specify --builder=%s to run RUN instructions in a nerdctl network): %w", options.NetworkMode, BuilderContainerd, errdefs.ErrNotImplemented)
 		}
 	}
 
@@ -472,9 +624,176 @@ func generateBuildctlArgs(ctx context.Context, client *containerd.Client, option
 		buildctlArgs = append(buildctlArgs, "--opt=add-hosts="+strings.Join(extraHosts, ","))
 	}
 
+	buildctlArgs = append(buildctlArgs, registryAuthTLSContextArgs(options.GOptions.Registries)...)
+
 	return buildctlBinary, buildctlArgs, needsLoading, metaFile, tags, cleanup, nil
 }
 
+// registryAuthTLSContextArgs returns the buildctl flags presenting the client certificates of the [registries] tables
+// of nerdctl.toml when buildctl authenticates with the registries.
+// BuildKit pulls and pushes the images with the keypairs of the [registry] tables of buildkitd.toml though.
+func registryAuthTLSContextArgs(registries map[string]config.RegistryConfig) []string {
+	var args []string
+	for _, host := range slices.Sorted(maps.Keys(registries)) {
+		reg := registries[host]
+		if reg.ClientCert == "" {
+			continue
+		}
+		key := reg.ClientKey
+		if key == "" {
+			key = reg.ClientCert
+		}
+		args = append(args, fmt.Sprintf("--registry-auth-tlscontext=host=%s,cert=%s,key=%s", host, reg.ClientCert, key))
+	}
+	return args
+}
+
+// parseCacheOption validates a --cache-from (export=false) or --cache-to (export=true) value
+// and converts it to the buildctl --import-cache/--export-cache syntax.
+// A value without attributes is a registry reference, as in `docker build --cache-from user/app:cache`.
+//
+// Credentials of the gha cache are read by buildctl from $ACTIONS_RUNTIME_TOKEN and $ACTIONS_CACHE_URL,
+// credentials of the s3 cache are read by buildkitd from its own environment.
+func parseCacheOption(value string, export bool) (string, error) {
+	fields, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cache option %q: %w", value, errdefs.ErrInvalidArgument)
+	}
+	attrs := make(map[string]string, len(fields))
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			if len(fields) == 1 {
+				return "type=registry,ref=" + value, nil
+			}
+			return "", fmt.Errorf("invalid field %q in cache option %q: %w", field, value, errdefs.ErrInvalidArgument)
+		}
+		attrs[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	if _, ok := attrs["type"]; !ok {
+		if _, ok := attrs["ref"]; !ok {
+			return "", fmt.Errorf("cache option %q needs a type (e.g. type=registry,ref=user/app:cache): %w", value, errdefs.ErrInvalidArgument)
+		}
+		attrs["type"] = "registry"
+		value = "type=registry," + value
+	}
+	if mode, ok := attrs["mode"]; ok {
+		if !export {
+			return "", fmt.Errorf("cache option %q: mode is only supported by --cache-to: %w", value, errdefs.ErrInvalidArgument)
+		}
+		if mode != "min" && mode != "max" {
+			return "", fmt.Errorf("cache option %q: mode must be either min or max: %w", value, errdefs.ErrInvalidArgument)
+		}
+	}
+	switch attrs["type"] {
+	case "registry":
+		if attrs["ref"] == "" {
+			return "", fmt.Errorf("cache option %q: registry cache needs ref: %w", value, errdefs.ErrInvalidArgument)
+		}
+	case "inline":
+		if !export {
+			return "", fmt.Errorf("cache option %q: inline cache is imported with type=registry,ref=<image>: %w", value, errdefs.ErrInvalidArgument)
+		}
+	case "gha":
+		if _, ok := attrs["token"]; !ok && os.Getenv("ACTIONS_RUNTIME_TOKEN") == "" {
+			return "", fmt.Errorf("cache option %q: gha cache needs token, or $ACTIONS_RUNTIME_TOKEN to be set (as in GitHub Actions runners): %w", value, errdefs.ErrInvalidArgument)
+		}
+	case "s3":
+		if attrs["bucket"] == "" {
+			return "", fmt.Errorf("cache option %q: s3 cache needs bucket: %w", value, errdefs.ErrInvalidArgument)
+		}
+		if _, ok := attrs["region"]; !ok {
+			if region := os.Getenv("AWS_REGION"); region != "" {
+				value += ",region=" + region
+			}
+		}
+	}
+	return value, nil
+}
+
+// parseSecretOption validates a --secret value and converts it to the buildctl --secret syntax.
+// The secret is read from a file (id=mysecret,src=/local/secret) or from an environment variable
+// of the nerdctl process (id=mytoken,env=MY_TOKEN, or type=env,id=MY_TOKEN), so that credentials
+// don't have to be written to disk. A secret with only an id is read from the environment variable
+// of the same name when it is set, and from the file of the same name otherwise, as in Docker.
+func parseSecretOption(value string) (string, error) {
+	fields, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse secret %q: %w", value, errdefs.ErrInvalidArgument)
+	}
+	var typ, id, src, env string
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return "", fmt.Errorf("invalid field %q in secret %q: %w", field, value, errdefs.ErrInvalidArgument)
+		}
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "type":
+			if v != "file" && v != "env" {
+				return "", fmt.Errorf("secret %q: unsupported type %q, expected file or env: %w", value, v, errdefs.ErrInvalidArgument)
+			}
+			typ = v
+		case "id":
+			id = v
+		case "src", "source":
+			src = v
+		case "env":
+			env = v
+		default:
+			return "", fmt.Errorf("invalid field %q in secret %q: %w", field, value, errdefs.ErrInvalidArgument)
+		}
+	}
+	if src != "" && env != "" {
+		return "", fmt.Errorf("secret %q: src and env are mutually exclusive: %w", value, errdefs.ErrInvalidArgument)
+	}
+	switch typ {
+	case "env":
+		if env == "" {
+			env, src = src, ""
+		}
+		if env == "" {
+			env = id
+		}
+	case "file":
+		if env != "" {
+			return "", fmt.Errorf("secret %q: env is not supported with type=file: %w", value, errdefs.ErrInvalidArgument)
+		}
+		if src == "" {
+			src = id
+		}
+	default:
+		if src == "" && env == "" {
+			if _, ok := os.LookupEnv(id); ok {
+				env = id
+			} else {
+				src = id
+			}
+		}
+	}
+	if id == "" {
+		id = env
+	}
+	if id == "" {
+		return "", fmt.Errorf("secret %q needs an id: %w", value, errdefs.ErrInvalidArgument)
+	}
+	if env != "" {
+		if _, ok := os.LookupEnv(env); !ok {
+			return "", fmt.Errorf("secret %q: environment variable %q is not set: %w", value, env, errdefs.ErrInvalidArgument)
+		}
+		return csvJoin("id="+id, "env="+env), nil
+	}
+	return csvJoin("id="+id, "src="+src), nil
+}
+
+// csvJoin joins the fields of a buildctl option, quoting the fields that need it.
+func csvJoin(fields ...string) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
 func getDigestFromMetaFile(path string) (string, error) {
 	data, err := filesystem.ReadFile(path)
 	if err != nil {
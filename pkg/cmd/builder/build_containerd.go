@@ -0,0 +1,1200 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/mattn/go-isatty"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/dockerfileutil"
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/commit"
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// BuilderContainerd is the name of the built-in builder that does not need buildkitd.
+const BuilderContainerd = "containerd"
+
+// InvokeOnError is the value of --invoke that starts a shell in the container of a failed RUN instruction.
+const InvokeOnError = "on-error"
+
+// containerdBuilderComment is set as the comment of the image history entries created by the containerd builder.
+const containerdBuilderComment = "nerdctl.containerd-builder"
+
+// containerdBuilder is a minimal Dockerfile builder for environments where running buildkitd is not possible.
+//
+// RUN is executed in a container created with `nerdctl run`, and committed with imgutil/commit.
+// COPY and ADD write a layer directly from the build context, and the other instructions only
+// modify the image config, so that no container is needed for them.
+//
+// Multi-stage builds, heredocs and the build cache are not supported.
+type containerdBuilder struct {
+	client     *containerd.Client
+	options    types.BuilderBuildOptions
+	platMC     platforms.MatchComparer
+	contextDir string
+	tempDir    string
+	id         string
+	// out receives the output of the steps, it is a buffer when options.Quiet is set
+	out io.Writer
+
+	buildArgs  map[string]string // values of --build-arg
+	globalArgs map[string]string // ARG declared before FROM
+	args       map[string]string // ARG in the scope of the current instruction
+	shell      []string
+	started    bool // FROM was processed
+	cmdSet     bool // CMD was set by the Dockerfile
+
+	image    images.Image // current image. Name is empty for `FROM scratch`
+	manifest ocispec.Manifest
+	config   ocispec.Image
+	// dirty is true when config has changes that are not written to image yet
+	dirty        bool
+	step         int
+	intermediate []string
+}
+
+func buildWithContainerd(ctx context.Context, client *containerd.Client, options types.BuilderBuildOptions) error {
+	if err := checkContainerdBuilderOptions(options); err != nil {
+		return err
+	}
+	if err := checkContainerdBuilderNetwork(options); err != nil {
+		return err
+	}
+	// host-gateway is resolved here, so that the mappings are validated before running any instruction
+	extraHosts, err := containerutil.ParseExtraHosts(options.ExtraHosts, options.GOptions.HostGatewayIP, ":")
+	if err != nil {
+		return err
+	}
+	options.ExtraHosts = extraHosts
+	instructions, err := readDockerfile(options)
+	if err != nil {
+		return err
+	}
+	contextDir, err := filepath.Abs(options.BuildContext)
+	if err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp("", "nerdctl-build-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Don't gc the layers of the intermediate images until the build is done
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID(), leases.WithExpiration(1*time.Hour))
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+
+	b := &containerdBuilder{
+		client:     client,
+		options:    options,
+		platMC:     platforms.Default(),
+		contextDir: contextDir,
+		tempDir:    tempDir,
+		id:         idgen.TruncateID(idgen.GenerateID()),
+		out:        options.Stderr,
+		buildArgs:  parseContainerdBuilderArgs(options.BuildArgs),
+		globalArgs: map[string]string{},
+	}
+	var quietOut bytes.Buffer
+	if options.Quiet {
+		b.out = &quietOut
+	}
+	defer b.removeIntermediateImages(ctx)
+
+	for i, inst := range instructions {
+		fmt.Fprintf(b.out, "STEP %d/%d: %s\n", i+1, len(instructions), inst.Original)
+		if err := b.dispatch(ctx, inst); err != nil {
+			if options.Quiet {
+				_, _ = io.Copy(options.Stderr, &quietOut)
+			}
+			return fmt.Errorf("line %d: %s: %w", inst.Line, inst.Cmd, err)
+		}
+	}
+	return b.finish(ctx)
+}
+
+// checkContainerdBuilderOptions returns an error for the options that need buildkit.
+func checkContainerdBuilderOptions(options types.BuilderBuildOptions) error {
+	unsupported := []struct {
+		flag string
+		set  bool
+	}{
+		{"--output", options.Output != ""},
+		{"--secret", len(options.Secret) > 0},
+		{"--push", options.Push},
+		{"--ssh", len(options.SSH) > 0},
+		{"--cache-from", len(options.CacheFrom) > 0},
+		{"--cache-to", len(options.CacheTo) > 0},
+		{"--attest", len(options.Attest) > 0},
+		{"--allow", len(options.Allow) > 0},
+		{"--build-context", len(options.ExtendedBuildContext) > 0},
+		{"--progress=" + ProgressRawJSON, options.Progress == ProgressRawJSON},
+	}
+	for _, u := range unsupported {
+		if u.set {
+			return fmt.Errorf("%s is not supported by the %s builder (%w)", u.flag, BuilderContainerd, errdefs.ErrNotImplemented)
+		}
+	}
+	if _, _, ok := parseRemoteContext(options.BuildContext); ok {
+		return fmt.Errorf("remote build contexts are not supported by the %s builder (%w)", BuilderContainerd, errdefs.ErrNotImplemented)
+	}
+	if len(options.Platform) > 1 || (len(options.Platform) == 1 && !isMatchingRuntimePlatform(options.Platform[0], platformParser{})) {
+		return fmt.Errorf("the %s builder can only build images for the platform %s (%w)", BuilderContainerd, platforms.DefaultString(), errdefs.ErrNotImplemented)
+	}
+	return nil
+}
+
+// checkContainerdBuilderNetwork checks that the network of RUN instructions exists before starting the build.
+// RUN instructions are executed with `nerdctl run --net`, so any nerdctl network can be used.
+func checkContainerdBuilderNetwork(options types.BuilderBuildOptions) error {
+	switch options.NetworkMode {
+	case "", "default", "none", "host":
+		return nil
+	}
+	if strings.HasPrefix(options.NetworkMode, "container:") {
+		return nil
+	}
+	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
+	if err != nil {
+		return err
+	}
+	if _, err := cniEnv.NetworkByNameOrID(options.NetworkMode); err != nil {
+		return fmt.Errorf("failed to find the network for RUN instructions: %w", err)
+	}
+	return nil
+}
+
+func readDockerfile(options types.BuilderBuildOptions) ([]dockerfileInstruction, error) {
+	if options.File == "-" {
+		return parseDockerfile(options.Stdin)
+	}
+	dir, file := options.BuildContext, buildkitutil.DefaultDockerfileName
+	if options.File != "" {
+		dir, file = filepath.Split(options.File)
+		if dir == "" {
+			dir = "."
+		}
+	}
+	dir, file, err := buildkitutil.BuildKitFile(dir, file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(dir, file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDockerfile(f)
+}
+
+// parseContainerdBuilderArgs parses the values of --build-arg.
+// `KEY` without a value is taken from the environment, and ignored when not set.
+func parseContainerdBuilderArgs(buildArgs []string) map[string]string {
+	res := make(map[string]string)
+	for _, ba := range strutil.DedupeStrSlice(buildArgs) {
+		k, v, ok := strings.Cut(ba, "=")
+		if k == "" {
+			continue
+		}
+		if !ok {
+			if v, ok = os.LookupEnv(k); !ok {
+				log.L.Debugf("ignoring unset build arg %q", ba)
+				continue
+			}
+		}
+		res[k] = v
+	}
+	return res
+}
+
+func (b *containerdBuilder) dispatch(ctx context.Context, inst dockerfileInstruction) error {
+	switch inst.Cmd {
+	case "FROM":
+		return b.from(ctx, inst)
+	case "ARG":
+		return b.arg(inst)
+	}
+	if !b.started {
+		return errors.New("no FROM instruction before the instruction")
+	}
+	switch inst.Cmd {
+	case "RUN":
+		return b.run(ctx, inst)
+	case "COPY", "ADD":
+		return b.copy(ctx, inst)
+	}
+
+	if err := checkNoFlags(inst); err != nil {
+		return err
+	}
+	env := b.expansionEnv()
+	switch inst.Cmd {
+	case "ENV":
+		kvs, err := dockerfileutil.ParseKeyValues(inst.Args, env, false)
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			b.config.Config.Env = setEnv(b.config.Config.Env, kv)
+		}
+	case "LABEL":
+		kvs, err := dockerfileutil.ParseKeyValues(inst.Args, env, false)
+		if err != nil {
+			return err
+		}
+		if b.config.Config.Labels == nil {
+			b.config.Config.Labels = make(map[string]string)
+		}
+		maps.Copy(b.config.Config.Labels, strutil.ConvertKVStringsToMap(kvs))
+	case "EXPOSE":
+		ports, err := dockerfileutil.LexWords(inst.Args, env, true)
+		if err != nil {
+			return err
+		}
+		if b.config.Config.ExposedPorts == nil {
+			b.config.Config.ExposedPorts = make(map[string]struct{})
+		}
+		for _, port := range ports {
+			if !strings.Contains(port, "/") {
+				port += "/tcp"
+			}
+			b.config.Config.ExposedPorts[port] = struct{}{}
+		}
+	case "VOLUME":
+		volumes, ok := parseDockerfileJSON(inst.Args)
+		if !ok {
+			var err error
+			if volumes, err = dockerfileutil.LexWords(inst.Args, env, true); err != nil {
+				return err
+			}
+		}
+		if b.config.Config.Volumes == nil {
+			b.config.Config.Volumes = make(map[string]struct{})
+		}
+		for _, v := range volumes {
+			b.config.Config.Volumes[v] = struct{}{}
+		}
+	case "WORKDIR":
+		dir, err := dockerfileutil.LexWords(inst.Args, env, false)
+		if err != nil {
+			return err
+		}
+		if path.IsAbs(dir[0]) {
+			b.config.Config.WorkingDir = path.Clean(dir[0])
+		} else {
+			b.config.Config.WorkingDir = path.Join(b.workdir(), dir[0])
+		}
+	case "USER", "STOPSIGNAL", "MAINTAINER":
+		v, err := dockerfileutil.LexWords(inst.Args, env, false)
+		if err != nil {
+			return err
+		}
+		switch inst.Cmd {
+		case "USER":
+			b.config.Config.User = v[0]
+		case "STOPSIGNAL":
+			b.config.Config.StopSignal = v[0]
+		case "MAINTAINER":
+			b.config.Author = v[0]
+		}
+	case "CMD":
+		b.config.Config.Cmd = b.command(inst.Args)
+		b.cmdSet = true
+	case "ENTRYPOINT":
+		b.config.Config.Entrypoint = b.command(inst.Args)
+		if !b.cmdSet {
+			// CMD inherited from the base image is reset by ENTRYPOINT
+			b.config.Config.Cmd = nil
+		}
+	case "SHELL":
+		shell, ok := parseDockerfileJSON(inst.Args)
+		if !ok || len(shell) == 0 {
+			return errors.New("SHELL requires the arguments to be in JSON form")
+		}
+		b.shell = shell
+		return nil
+	default:
+		return fmt.Errorf("the instruction is not supported by the %s builder (%w)", BuilderContainerd, errdefs.ErrNotImplemented)
+	}
+	b.addHistory(inst, true)
+	return nil
+}
+
+func checkNoFlags(inst dockerfileInstruction) error {
+	if len(inst.Flags) > 0 {
+		return fmt.Errorf("flag %s is not supported by the %s builder (%w)", inst.Flags[0], BuilderContainerd, errdefs.ErrNotImplemented)
+	}
+	return nil
+}
+
+// expansionEnv returns the variables used for expanding the arguments of the instructions:
+// the ARG values, overridden by the ENV values.
+func (b *containerdBuilder) expansionEnv() map[string]string {
+	env := maps.Clone(b.args)
+	if env == nil {
+		env = make(map[string]string)
+	}
+	maps.Copy(env, strutil.ConvertKVStringsToMap(b.config.Config.Env))
+	return env
+}
+
+func (b *containerdBuilder) workdir() string {
+	if b.config.Config.WorkingDir == "" {
+		return "/"
+	}
+	return b.config.Config.WorkingDir
+}
+
+// command returns the exec form of the arguments of CMD and ENTRYPOINT.
+func (b *containerdBuilder) command(args string) []string {
+	if cmd, ok := parseDockerfileJSON(args); ok {
+		return cmd
+	}
+	return append(slices.Clone(b.shell), args)
+}
+
+func (b *containerdBuilder) addHistory(inst dockerfileInstruction, emptyLayer bool) {
+	created := time.Now().UTC()
+	b.config.History = append(b.config.History, ocispec.History{
+		Created:    &created,
+		CreatedBy:  inst.Original,
+		Comment:    containerdBuilderComment,
+		EmptyLayer: emptyLayer,
+	})
+	b.dirty = true
+}
+
+func setEnv(env []string, kv string) []string {
+	k, _, _ := strings.Cut(kv, "=")
+	for i, e := range env {
+		if ek, _, _ := strings.Cut(e, "="); ek == k {
+			env[i] = kv
+			return env
+		}
+	}
+	return append(env, kv)
+}
+
+func (b *containerdBuilder) arg(inst dockerfileInstruction) error {
+	if err := checkNoFlags(inst); err != nil {
+		return err
+	}
+	scope := b.globalArgs
+	if b.started {
+		scope = b.args
+	}
+	kvs, err := dockerfileutil.ParseKeyValues(inst.Args, b.expansionEnv(), true)
+	if err != nil {
+		return err
+	}
+	for _, kv := range kvs {
+		k, v, hasDefault := strings.Cut(kv, "=")
+		if bv, ok := b.buildArgs[k]; ok {
+			scope[k] = bv
+		} else if hasDefault {
+			scope[k] = v
+		} else if gv, ok := b.globalArgs[k]; ok && b.started {
+			// `ARG NAME` after FROM brings the value of the global ARG into the scope
+			scope[k] = gv
+		}
+	}
+	return nil
+}
+
+func (b *containerdBuilder) from(ctx context.Context, inst dockerfileInstruction) error {
+	if b.started {
+		return fmt.Errorf("multi-stage builds are not supported by the %s builder (%w)", BuilderContainerd, errdefs.ErrNotImplemented)
+	}
+	if err := checkNoFlags(inst); err != nil {
+		return err
+	}
+	words, err := dockerfileutil.LexWords(inst.Args, b.globalArgs, true)
+	if err != nil {
+		return err
+	}
+	stage := ""
+	switch {
+	case len(words) == 3 && strings.EqualFold(words[1], "AS"):
+		stage = words[2]
+	case len(words) != 1:
+		return errors.New("FROM requires either one or three arguments")
+	}
+	if b.options.Target != "" && b.options.Target != stage {
+		return fmt.Errorf("target stage %q could not be found", b.options.Target)
+	}
+	b.started = true
+	b.args = make(map[string]string)
+	b.shell = []string{"/bin/sh", "-c"}
+
+	if words[0] == "scratch" {
+		plat := platforms.DefaultSpec()
+		b.config = ocispec.Image{
+			Platform: ocispec.Platform{
+				Architecture: plat.Architecture,
+				OS:           plat.OS,
+				Variant:      plat.Variant,
+			},
+			RootFS: ocispec.RootFS{Type: "layers"},
+		}
+		return nil
+	}
+
+	parsedReference, err := referenceutil.Parse(words[0])
+	if err != nil {
+		return err
+	}
+	ref := parsedReference.String()
+	pull := b.options.Pull != nil && *b.options.Pull
+	if !pull {
+		if _, err := b.client.ImageService().Get(ctx, ref); err != nil {
+			if !errdefs.IsNotFound(err) {
+				return err
+			}
+			pull = true
+		}
+	}
+	if pull {
+		pullArgs := []string{"pull"}
+		if b.options.Quiet {
+			pullArgs = append(pullArgs, "--quiet")
+		}
+		if err := b.runNerdctl(ctx, append(pullArgs, ref)...); err != nil {
+			return err
+		}
+	}
+	img, err := b.client.ImageService().Get(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return b.setImage(ctx, img)
+}
+
+// setImage makes img the current image.
+func (b *containerdBuilder) setImage(ctx context.Context, img images.Image) error {
+	cimg := containerd.NewImageWithPlatform(b.client, img, b.platMC)
+	manifest, _, err := imgutil.ReadManifest(ctx, cimg)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return fmt.Errorf("image %s has no manifest for the platform %s", img.Name, platforms.DefaultString())
+	}
+	config, _, err := imgutil.ReadImageConfig(ctx, cimg)
+	if err != nil {
+		return err
+	}
+	b.image, b.manifest, b.config = img, *manifest, config
+	b.dirty = false
+	return nil
+}
+
+// nextIntermediateName returns the name of a new intermediate image, which is removed after the build.
+func (b *containerdBuilder) nextIntermediateName() (string, error) {
+	b.step++
+	parsedReference, err := referenceutil.Parse(fmt.Sprintf("nerdctl-build-%s:step%d", b.id, b.step))
+	if err != nil {
+		return "", err
+	}
+	name := parsedReference.String()
+	b.intermediate = append(b.intermediate, name)
+	return name, nil
+}
+
+// writeImage writes the current config as the image name, with layer appended to the layers of the current image,
+// and makes it the current image. The name of the image is its manifest digest when name is empty.
+func (b *containerdBuilder) writeImage(ctx context.Context, name string, layer *ocispec.Descriptor) error {
+	layers := slices.Clone(b.manifest.Layers)
+	if layer != nil {
+		layers = append(layers, *layer)
+	}
+	created := time.Now().UTC()
+	b.config.Created = &created
+	manifestDesc, _, err := image.WriteConfigAndManifest(ctx, b.client.ContentStore(), b.options.GOptions.Snapshotter, b.config, layers)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = manifestDesc.Digest.String()
+	}
+	img := images.Image{
+		Name:      name,
+		Target:    manifestDesc,
+		CreatedAt: created,
+	}
+	if err := b.createImage(ctx, img); err != nil {
+		return err
+	}
+	if len(layers) > 0 {
+		if err := containerd.NewImage(b.client, img).Unpack(ctx, b.options.GOptions.Snapshotter); err != nil {
+			return err
+		}
+	}
+	b.image = img
+	b.manifest.Layers = layers
+	b.dirty = false
+	return nil
+}
+
+func (b *containerdBuilder) createImage(ctx context.Context, img images.Image) error {
+	if _, err := b.client.ImageService().Update(ctx, img); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := b.client.ImageService().Create(ctx, img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *containerdBuilder) runNerdctl(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, b.options.NerdctlCmd, append(slices.Clone(b.options.NerdctlArgs), args...)...)
+	cmd.Stdout = b.out
+	cmd.Stderr = b.out
+	log.G(ctx).Debugf("running %v", cmd.Args)
+	return cmd.Run()
+}
+
+func (b *containerdBuilder) run(ctx context.Context, inst dockerfileInstruction) error {
+	if err := checkNoFlags(inst); err != nil {
+		return err
+	}
+	args, ok := parseDockerfileJSON(inst.Args)
+	if !ok {
+		args = append(slices.Clone(b.shell), inst.Args)
+	}
+	if len(args) == 0 {
+		return errors.New("RUN requires a command")
+	}
+	if b.dirty || b.image.Name == "" {
+		name, err := b.nextIntermediateName()
+		if err != nil {
+			return err
+		}
+		if err := b.writeImage(ctx, name, nil); err != nil {
+			return err
+		}
+	}
+
+	cidFile := filepath.Join(b.tempDir, fmt.Sprintf("step%d.cid", b.step))
+	// the entrypoint of the image is replaced with the command
+	runArgs := []string{"run", "--cidfile=" + cidFile, "--pull=never", "--entrypoint=" + args[0]}
+	runArgs = append(runArgs, b.stepRunArgs()...)
+	runArgs = append(runArgs, b.image.Name)
+	runArgs = append(runArgs, args[1:]...)
+
+	runErr := b.runNerdctl(ctx, runArgs...)
+	cid, err := os.ReadFile(cidFile)
+	if err != nil {
+		if runErr != nil {
+			return runErr
+		}
+		return err
+	}
+	containerID := strings.TrimSpace(string(cid))
+	// the container of a failed step is kept for debugging, unless --force-rm is set
+	defer func() {
+		if !b.options.ForceRm && (!b.options.Rm || runErr != nil) {
+			return
+		}
+		if err := b.runNerdctl(ctx, "rm", "-f", containerID); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to remove the intermediate container %s", containerID)
+		}
+	}()
+	if runErr != nil {
+		runErr = fmt.Errorf("the command %q returned an error: %w", args, runErr)
+		if b.options.Invoke == InvokeOnError {
+			if err := b.invokeShell(ctx, containerID, runErr); err != nil {
+				log.G(ctx).WithError(err).Warn("failed to start a shell in the container of the failed step")
+			}
+		}
+		return runErr
+	}
+
+	container, err := b.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	name, err := b.nextIntermediateName()
+	if err != nil {
+		return err
+	}
+	opts := &commit.Opts{
+		Ref:         name,
+		Message:     containerdBuilderComment,
+		Compression: types.Gzip,
+		Format:      types.ImageFormatDocker,
+	}
+	if _, err := commit.Commit(ctx, b.client, container, opts, b.options.GOptions); err != nil {
+		return err
+	}
+	img, err := b.client.ImageService().Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	return b.setImage(ctx, img)
+}
+
+// stepRunArgs returns the `nerdctl run` flags that give the container of a RUN instruction its environment.
+func (b *containerdBuilder) stepRunArgs() []string {
+	var runArgs []string
+	switch b.options.NetworkMode {
+	case "", "default":
+	default:
+		runArgs = append(runArgs, "--net="+b.options.NetworkMode)
+	}
+	for _, h := range b.options.ExtraHosts {
+		runArgs = append(runArgs, "--add-host="+h)
+	}
+	// ARG values are set as environment variables of RUN, unless overridden by ENV
+	env := strutil.ConvertKVStringsToMap(b.config.Config.Env)
+	for _, k := range slices.Sorted(maps.Keys(b.args)) {
+		if _, ok := env[k]; !ok {
+			runArgs = append(runArgs, fmt.Sprintf("--env=%s=%s", k, b.args[k]))
+		}
+	}
+	return runArgs
+}
+
+// invokeShell commits the container of the failed RUN instruction, and runs an interactive shell
+// in it with the environment of the step, so that the failure can be inspected.
+// The build is aborted when the shell exits.
+func (b *containerdBuilder) invokeShell(ctx context.Context, containerID string, runErr error) error {
+	container, err := b.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	name, err := b.nextIntermediateName()
+	if err != nil {
+		return err
+	}
+	opts := &commit.Opts{
+		Ref:         name,
+		Message:     containerdBuilderComment,
+		Compression: types.Gzip,
+		Format:      types.ImageFormatDocker,
+	}
+	if _, err := commit.Commit(ctx, b.client, container, opts, b.options.GOptions); err != nil {
+		return err
+	}
+	shell := b.shell[0]
+	fmt.Fprintf(b.options.Stderr, "%v\nRunning %s in the container of the failed step, the build is aborted when the shell exits\n", runErr, shell)
+	runArgs := []string{"run", "--rm", "-i", "--pull=never", "--entrypoint=" + shell}
+	if f, ok := b.options.Stdin.(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+		runArgs = append(runArgs, "-t")
+	}
+	runArgs = append(runArgs, b.stepRunArgs()...)
+	runArgs = append(runArgs, name)
+	cmd := exec.CommandContext(ctx, b.options.NerdctlCmd, append(slices.Clone(b.options.NerdctlArgs), runArgs...)...)
+	cmd.Stdin = b.options.Stdin
+	cmd.Stdout = b.options.Stdout
+	cmd.Stderr = b.options.Stderr
+	log.G(ctx).Debugf("running %v", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyOptions are the options of COPY and ADD.
+type copyOptions struct {
+	uid, gid int
+	mode     *fs.FileMode
+	// extract is true for ADD, which extracts local tar archives
+	extract bool
+}
+
+func (b *containerdBuilder) copy(ctx context.Context, inst dockerfileInstruction) error {
+	opts := copyOptions{extract: inst.Cmd == "ADD"}
+	for _, f := range inst.Flags {
+		k, v, _ := strings.Cut(strings.TrimPrefix(f, "--"), "=")
+		switch k {
+		case "chown":
+			u, g, hasGroup := strings.Cut(v, ":")
+			uid, err := strconv.Atoi(u)
+			if err != nil {
+				return fmt.Errorf("only numeric values of --chown are supported by the %s builder, got %q (%w)", BuilderContainerd, v, errdefs.ErrNotImplemented)
+			}
+			gid := uid
+			if hasGroup {
+				if gid, err = strconv.Atoi(g); err != nil {
+					return fmt.Errorf("only numeric values of --chown are supported by the %s builder, got %q (%w)", BuilderContainerd, v, errdefs.ErrNotImplemented)
+				}
+			}
+			opts.uid, opts.gid = uid, gid
+		case "chmod":
+			m, err := strconv.ParseUint(v, 8, 32)
+			if err != nil {
+				return fmt.Errorf("invalid --chmod value %q: %w", v, err)
+			}
+			mode := fs.FileMode(m)
+			opts.mode = &mode
+		default:
+			return fmt.Errorf("flag %s is not supported by the %s builder (%w)", f, BuilderContainerd, errdefs.ErrNotImplemented)
+		}
+	}
+
+	words, ok := parseDockerfileJSON(inst.Args)
+	if !ok {
+		var err error
+		if words, err = dockerfileutil.LexWords(inst.Args, b.expansionEnv(), true); err != nil {
+			return err
+		}
+	}
+	if len(words) < 2 {
+		return fmt.Errorf("%s requires at least two arguments", inst.Cmd)
+	}
+	dst := words[len(words)-1]
+	dstIsDir := strings.HasSuffix(dst, "/")
+	if !path.IsAbs(dst) {
+		dst = path.Join(b.workdir(), dst)
+	}
+	var sources []string
+	for _, src := range words[:len(words)-1] {
+		if strings.Contains(src, "://") {
+			return fmt.Errorf("remote source %q is not supported by the %s builder (%w)", src, BuilderContainerd, errdefs.ErrNotImplemented)
+		}
+		p := filepath.Join(b.contextDir, filepath.FromSlash(src))
+		if rel, err := filepath.Rel(b.contextDir, p); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("forbidden path outside the build context: %s", src)
+		}
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%s: no such file or directory in the build context", src)
+		}
+		for _, m := range matches {
+			resolved, err := resolveCopySource(b.contextDir, m)
+			if err != nil {
+				return fmt.Errorf("%s: %w", src, err)
+			}
+			sources = append(sources, resolved)
+		}
+	}
+	if len(sources) > 1 {
+		dstIsDir = true
+	}
+
+	var (
+		layerDesc ocispec.Descriptor
+		diffID    digest.Digest
+	)
+	err := b.withLowerRootfs(ctx, func(lower string) error {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(writeCopyLayer(pw, lower, sources, dst, dstIsDir, opts))
+		}()
+		var err error
+		layerDesc, diffID, err = image.WriteGzipLayer(ctx, b.client.ContentStore(), "build-", pr)
+		pr.CloseWithError(err)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	b.config.RootFS.DiffIDs = append(b.config.RootFS.DiffIDs, diffID)
+	b.addHistory(inst, false)
+	name, err := b.nextIntermediateName()
+	if err != nil {
+		return err
+	}
+	return b.writeImage(ctx, name, &layerDesc)
+}
+
+// withLowerRootfs mounts a read-only view of the rootfs of the current image, and calls f with the mount point.
+// f is called with an empty root when the current image has no layers.
+func (b *containerdBuilder) withLowerRootfs(ctx context.Context, f func(root string) error) error {
+	if len(b.config.RootFS.DiffIDs) == 0 {
+		return f("")
+	}
+	snapshotter := b.options.GOptions.Snapshotter
+	if err := containerd.NewImageWithPlatform(b.client, b.image, b.platMC).Unpack(ctx, snapshotter); err != nil {
+		return fmt.Errorf("error unpacking image: %w", err)
+	}
+	chainID := identity.ChainID(b.config.RootFS.DiffIDs).String()
+
+	root, err := os.MkdirTemp(b.tempDir, "lower-")
+	if err != nil {
+		return err
+	}
+	// Remove (not RemoveAll) so that a failed unmount never deletes the image contents
+	defer os.Remove(root)
+
+	sn := b.client.SnapshotService(snapshotter)
+	key := fmt.Sprintf("nerdctl-build-%s-%s", b.id, filepath.Base(root))
+	mounts, err := sn.View(ctx, key, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create view snapshot: %w", err)
+	}
+	defer func() {
+		if err := sn.Remove(ctx, key); err != nil && !errdefs.IsNotFound(err) {
+			log.G(ctx).WithError(err).Warnf("failed to remove view snapshot %q", key)
+		}
+	}()
+
+	if err := mount.All(mounts, root); err != nil {
+		return fmt.Errorf("failed to mount image snapshot: %w", err)
+	}
+	defer func() {
+		if err := mount.UnmountAll(root, 0); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to unmount snapshot")
+		}
+	}()
+	return f(root)
+}
+
+// resolveCopySource resolves the symlinks in the parent directories of the source p in contextDir,
+// and returns an error if the resolved path is outside contextDir.
+// p itself is not followed, as the symlinks are copied as links.
+func resolveCopySource(contextDir, p string) (string, error) {
+	root, err := filepath.EvalSymlinks(contextDir)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Clean(p) == filepath.Clean(contextDir) {
+		return root, nil
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	resolved := filepath.Join(parent, filepath.Base(p))
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("forbidden path outside the build context: %s", p)
+	}
+	return resolved, nil
+}
+
+// writeCopyLayer writes the layer tar stream of COPY and ADD to w.
+// The contents of the source directories, and the source files are copied to dst.
+// Files are copied into dst when dstIsDir is true, otherwise they are copied as dst.
+// Symlinks are copied as links, and never followed.
+// lower is the rootfs of the current image, the directories that already exist in it are not written,
+// so that their mode and owner are kept. lower may be empty for an image without layers.
+func writeCopyLayer(w io.Writer, lower string, sources []string, dst string, dstIsDir bool, opts copyOptions) error {
+	lw := &layerWriter{tw: tar.NewWriter(w), lower: lower, written: make(map[string]bool), opts: opts}
+	for _, src := range sources {
+		fi, err := os.Lstat(src)
+		if err != nil {
+			return err
+		}
+		switch {
+		case fi.IsDir():
+			if err := lw.mkdirAll(path.Dir(dst)); err != nil {
+				return err
+			}
+			if !lw.isLowerDir(dst) {
+				if err := lw.writeEntry(src, dst, fi); err != nil {
+					return err
+				}
+			}
+			err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+				if err != nil || p == src {
+					return err
+				}
+				rel, err := filepath.Rel(src, p)
+				if err != nil {
+					return err
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				return lw.writeEntry(p, path.Join(dst, filepath.ToSlash(rel)), info)
+			})
+			if err != nil {
+				return err
+			}
+		case opts.extract && fi.Mode().IsRegular() && isArchive(src):
+			if err := lw.mkdirAll(dst); err != nil {
+				return err
+			}
+			if err := lw.extractArchive(src, dst); err != nil {
+				return err
+			}
+		default:
+			name := dst
+			if dstIsDir {
+				name = path.Join(dst, filepath.Base(src))
+			}
+			if err := lw.mkdirAll(path.Dir(name)); err != nil {
+				return err
+			}
+			if err := lw.writeEntry(src, name, fi); err != nil {
+				return err
+			}
+		}
+	}
+	return lw.tw.Close()
+}
+
+type layerWriter struct {
+	tw      *tar.Writer
+	lower   string
+	written map[string]bool
+	opts    copyOptions
+}
+
+// isLowerDir returns true when dir is a directory in the lower rootfs.
+// Symlinks are resolved in the scope of the lower rootfs.
+func (lw *layerWriter) isLowerDir(dir string) bool {
+	if lw.lower == "" {
+		return false
+	}
+	p, err := securejoin.SecureJoin(lw.lower, dir)
+	if err != nil {
+		return false
+	}
+	fi, err := os.Stat(p)
+	return err == nil && fi.IsDir()
+}
+
+// mkdirAll writes the entries of dir and its parents that do not exist in the lower rootfs, owned by root.
+func (lw *layerWriter) mkdirAll(dir string) error {
+	dir = path.Clean(dir)
+	if dir == "/" || lw.written[dir] {
+		return nil
+	}
+	if lw.isLowerDir(dir) {
+		lw.written[dir] = true
+		return nil
+	}
+	if err := lw.mkdirAll(path.Dir(dir)); err != nil {
+		return err
+	}
+	lw.written[dir] = true
+	return lw.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     strings.TrimPrefix(dir, "/") + "/",
+		Mode:     0o755,
+		ModTime:  time.Now(),
+		Format:   tar.FormatPAX,
+	})
+}
+
+// writeEntry writes the file p of the build context as name.
+func (lw *layerWriter) writeEntry(p, name string, fi fs.FileInfo) error {
+	name = path.Clean(name)
+	if name == "/" {
+		return nil
+	}
+	var link string
+	switch {
+	case fi.Mode().IsRegular(), fi.IsDir():
+	case fi.Mode()&fs.ModeSymlink != 0:
+		var err error
+		if link, err = os.Readlink(p); err != nil {
+			return err
+		}
+	default:
+		log.L.Debugf("skipping the special file %s", p)
+		return nil
+	}
+	hdr, err := tar.FileInfoHeader(fi, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = strings.TrimPrefix(name, "/")
+	if fi.IsDir() {
+		hdr.Name += "/"
+	}
+	hdr.Uid, hdr.Gid = lw.opts.uid, lw.opts.gid
+	hdr.Uname, hdr.Gname = "", ""
+	if lw.opts.mode != nil && link == "" {
+		hdr.Mode = int64(*lw.opts.mode & 0o7777)
+	}
+	hdr.Format = tar.FormatPAX
+	lw.written[name] = true
+	if err := lw.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(lw.tw, f)
+	return err
+}
+
+// extractArchive writes the entries of the (possibly compressed) tar archive src under dst.
+func (lw *layerWriter) extractArchive(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	decomp, err := compression.DecompressStream(f)
+	if err != nil {
+		return err
+	}
+	defer decomp.Close()
+	tr := tar.NewReader(decomp)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// the entries can't escape dst
+		name := path.Join(dst, path.Clean("/"+hdr.Name))
+		if name == "/" {
+			continue
+		}
+		hdr.Name = strings.TrimPrefix(name, "/")
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = strings.TrimPrefix(path.Join(dst, path.Clean("/"+hdr.Linkname)), "/")
+		}
+		lw.written[name] = true
+		if err := lw.tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(lw.tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// isArchive returns true if p is a (possibly compressed) tar archive.
+func isArchive(p string) bool {
+	f, err := os.Open(p)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	decomp, err := compression.DecompressStream(f)
+	if err != nil {
+		return false
+	}
+	defer decomp.Close()
+	_, err = tar.NewReader(decomp).Next()
+	return err == nil
+}
+
+func (b *containerdBuilder) finish(ctx context.Context) error {
+	if !b.started {
+		return errors.New("the Dockerfile has no FROM instruction")
+	}
+	if len(b.options.Label) > 0 {
+		if b.config.Config.Labels == nil {
+			b.config.Config.Labels = make(map[string]string)
+		}
+		maps.Copy(b.config.Config.Labels, strutil.ConvertKVStringsToMap(strutil.DedupeStrSlice(b.options.Label)))
+		b.dirty = true
+	}
+
+	var tags []string
+	for _, tag := range strutil.DedupeStrSlice(b.options.Tag) {
+		parsedReference, err := referenceutil.Parse(tag)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, parsedReference.String())
+	}
+	if b.dirty || b.image.Name == "" || len(tags) == 0 {
+		name := ""
+		if len(tags) > 0 {
+			name = tags[0]
+		}
+		if err := b.writeImage(ctx, name, nil); err != nil {
+			return err
+		}
+	}
+	for _, tag := range tags {
+		if err := b.createImage(ctx, images.Image{Name: tag, Target: b.image.Target, CreatedAt: time.Now()}); err != nil {
+			return fmt.Errorf("unable to tag image: %w", err)
+		}
+	}
+
+	id := b.image.Target.Digest.String()
+	if b.options.Quiet {
+		fmt.Fprintln(b.options.Stdout, id)
+	} else {
+		fmt.Fprintf(b.options.Stdout, "Successfully built %s\n", id)
+		for _, tag := range tags {
+			fmt.Fprintf(b.options.Stdout, "Successfully tagged %s\n", tag)
+		}
+	}
+	if b.options.IidFile != "" {
+		if err := filesystem.WriteFile(b.options.IidFile, []byte(id), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *containerdBuilder) removeIntermediateImages(ctx context.Context) {
+	for _, name := range b.intermediate {
+		if err := b.client.ImageService().Delete(ctx, name); err != nil && !errdefs.IsNotFound(err) {
+			log.G(ctx).WithError(err).Warnf("failed to remove the intermediate image %s", name)
+		}
+	}
+}
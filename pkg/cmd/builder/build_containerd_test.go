@@ -0,0 +1,164 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// readLayer returns the headers of the entries of the layer tar stream, mapped by name.
+func readLayer(t *testing.T, layer []byte) map[string]*tar.Header {
+	t.Helper()
+	res := make(map[string]*tar.Header)
+	tr := tar.NewReader(bytes.NewReader(layer))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return res
+		}
+		assert.NilError(t, err)
+		res[hdr.Name] = hdr
+	}
+}
+
+func TestWriteCopyLayer(t *testing.T) {
+	t.Parallel()
+
+	ctxDir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(ctxDir, "dir", "sub"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(ctxDir, "dir", "sub", "a.txt"), []byte("a"), 0o600))
+	assert.NilError(t, os.Symlink("sub/a.txt", filepath.Join(ctxDir, "dir", "link")))
+	assert.NilError(t, os.WriteFile(filepath.Join(ctxDir, "b.txt"), []byte("bb"), 0o644))
+
+	var archive bytes.Buffer
+	gw := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gw)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "../../escape.txt", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("e"))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gw.Close())
+	assert.NilError(t, os.WriteFile(filepath.Join(ctxDir, "archive.tar.gz"), archive.Bytes(), 0o644))
+
+	t.Run("directory contents and files into a directory", func(t *testing.T) {
+		t.Parallel()
+		var layer bytes.Buffer
+		mode := fs.FileMode(0o640)
+		err := writeCopyLayer(&layer, "", []string{filepath.Join(ctxDir, "dir"), filepath.Join(ctxDir, "b.txt")}, "/opt/app", true,
+			copyOptions{uid: 1000, gid: 1001, mode: &mode})
+		assert.NilError(t, err)
+		entries := readLayer(t, layer.Bytes())
+
+		assert.Equal(t, entries["opt/"].Uid, 0)
+		assert.Equal(t, entries["opt/app/"].Uid, 1000)
+		assert.Equal(t, entries["opt/app/sub/a.txt"].Size, int64(1))
+		assert.Equal(t, entries["opt/app/sub/a.txt"].Gid, 1001)
+		assert.Equal(t, entries["opt/app/sub/a.txt"].Mode, int64(0o640))
+		assert.Equal(t, entries["opt/app/link"].Typeflag, byte(tar.TypeSymlink))
+		assert.Equal(t, entries["opt/app/link"].Linkname, "sub/a.txt")
+		assert.Equal(t, entries["opt/app/b.txt"].Size, int64(2))
+		_, ok := entries["opt/app/dir/"]
+		assert.Assert(t, !ok, "the contents of the source directories are copied, not the directories themselves")
+	})
+
+	t.Run("directories of the lower rootfs are not written", func(t *testing.T) {
+		t.Parallel()
+		lower := t.TempDir()
+		assert.NilError(t, os.MkdirAll(filepath.Join(lower, "usr", "app"), 0o700))
+		assert.NilError(t, os.Symlink("usr", filepath.Join(lower, "opt")))
+		var layer bytes.Buffer
+		err := writeCopyLayer(&layer, lower, []string{filepath.Join(ctxDir, "dir"), filepath.Join(ctxDir, "b.txt")}, "/opt/app/new", true,
+			copyOptions{uid: 1000, gid: 1001})
+		assert.NilError(t, err)
+		entries := readLayer(t, layer.Bytes())
+
+		for _, name := range []string{"opt/", "opt/app/"} {
+			_, ok := entries[name]
+			assert.Assert(t, !ok, "%s exists in the lower rootfs", name)
+		}
+		assert.Equal(t, entries["opt/app/new/"].Uid, 1000)
+		assert.Equal(t, entries["opt/app/new/b.txt"].Size, int64(2))
+	})
+
+	t.Run("file as the destination", func(t *testing.T) {
+		t.Parallel()
+		var layer bytes.Buffer
+		assert.NilError(t, writeCopyLayer(&layer, "", []string{filepath.Join(ctxDir, "b.txt")}, "/c.txt", false, copyOptions{}))
+		entries := readLayer(t, layer.Bytes())
+		assert.Equal(t, len(entries), 1)
+		assert.Equal(t, entries["c.txt"].Mode, int64(0o644))
+	})
+
+	t.Run("archives are extracted by ADD only", func(t *testing.T) {
+		t.Parallel()
+		var copied, added bytes.Buffer
+		src := []string{filepath.Join(ctxDir, "archive.tar.gz")}
+		assert.NilError(t, writeCopyLayer(&copied, "", src, "/x/", true, copyOptions{}))
+		assert.NilError(t, writeCopyLayer(&added, "", src, "/x/", true, copyOptions{extract: true}))
+
+		_, ok := readLayer(t, copied.Bytes())["x/archive.tar.gz"]
+		assert.Assert(t, ok)
+		_, ok = readLayer(t, added.Bytes())["x/escape.txt"]
+		assert.Assert(t, ok, "the entries of the archive must not escape the destination")
+	})
+}
+
+func TestCopySymlinks(t *testing.T) {
+	t.Parallel()
+
+	outside := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600))
+	ctxDir := t.TempDir()
+	assert.NilError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(ctxDir, "link")))
+	assert.NilError(t, os.Symlink(outside, filepath.Join(ctxDir, "outside")))
+	assert.NilError(t, os.Mkdir(filepath.Join(ctxDir, "dir"), 0o755))
+	assert.NilError(t, os.Symlink("dir", filepath.Join(ctxDir, "inside")))
+
+	t.Run("symlinks are copied as links", func(t *testing.T) {
+		t.Parallel()
+		src, err := resolveCopySource(ctxDir, filepath.Join(ctxDir, "link"))
+		assert.NilError(t, err)
+		var layer bytes.Buffer
+		assert.NilError(t, writeCopyLayer(&layer, "", []string{src}, "/", true, copyOptions{}))
+		entries := readLayer(t, layer.Bytes())
+		assert.Equal(t, entries["link"].Typeflag, byte(tar.TypeSymlink))
+		assert.Equal(t, entries["link"].Linkname, filepath.Join(outside, "secret"))
+		assert.Equal(t, entries["link"].Size, int64(0))
+	})
+
+	t.Run("symlinks in the parent directories are resolved in the build context", func(t *testing.T) {
+		t.Parallel()
+		_, err := resolveCopySource(ctxDir, filepath.Join(ctxDir, "outside", "secret"))
+		assert.ErrorContains(t, err, "forbidden path outside the build context")
+
+		src, err := resolveCopySource(ctxDir, filepath.Join(ctxDir, "inside", "x"))
+		assert.NilError(t, err)
+		root, err := filepath.EvalSymlinks(ctxDir)
+		assert.NilError(t, err)
+		assert.Equal(t, src, filepath.Join(root, "dir", "x"))
+	})
+}
@@ -18,6 +18,7 @@ package builder
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -26,6 +27,8 @@ import (
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"go.uber.org/mock/gomock"
 	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
 )
 
 type MockParse struct {
@@ -238,3 +241,223 @@ func TestParseBuildctlArgsForOCILayout(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCacheOption(t *testing.T) {
+	t.Setenv("ACTIONS_RUNTIME_TOKEN", "")
+	t.Setenv("AWS_REGION", "")
+
+	tests := []struct {
+		name    string
+		value   string
+		export  bool
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "image reference",
+			value: "user/app:cache",
+			want:  "type=registry,ref=user/app:cache",
+		},
+		{
+			name:   "registry without type",
+			value:  "ref=user/app:cache,mode=max",
+			export: true,
+			want:   "type=registry,ref=user/app:cache,mode=max",
+		},
+		{
+			name:  "local",
+			value: "type=local,src=path/to/dir",
+			want:  "type=local,src=path/to/dir",
+		},
+		{
+			name:    "registry without ref",
+			value:   "type=registry,mode=max",
+			export:  true,
+			wantErr: "registry cache needs ref",
+		},
+		{
+			name:    "mode on import",
+			value:   "type=registry,ref=user/app:cache,mode=max",
+			wantErr: "mode is only supported by --cache-to",
+		},
+		{
+			name:    "invalid mode",
+			value:   "type=registry,ref=user/app:cache,mode=all",
+			export:  true,
+			wantErr: "mode must be either min or max",
+		},
+		{
+			name:    "inline import",
+			value:   "type=inline",
+			wantErr: "inline cache is imported with type=registry",
+		},
+		{
+			name:    "gha without token",
+			value:   "type=gha,scope=main",
+			export:  true,
+			wantErr: "gha cache needs token",
+		},
+		{
+			name:   "gha with token from the environment",
+			value:  "type=gha,scope=main,mode=max",
+			export: true,
+			env:    map[string]string{"ACTIONS_RUNTIME_TOKEN": "token"},
+			want:   "type=gha,scope=main,mode=max",
+		},
+		{
+			name:    "s3 without bucket",
+			value:   "type=s3,region=us-east-1",
+			wantErr: "s3 cache needs bucket",
+		},
+		{
+			name:  "s3 with region from the environment",
+			value: "type=s3,bucket=cache,name=app",
+			env:   map[string]string{"AWS_REGION": "eu-west-1"},
+			want:  "type=s3,bucket=cache,name=app,region=eu-west-1",
+		},
+		{
+			name:    "missing type",
+			value:   "src=path/to/dir",
+			wantErr: "needs a type",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			got, err := parseCacheOption(tc.value, tc.export)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseSecretOption(t *testing.T) {
+	t.Setenv("MY_TOKEN", "secret")
+	t.Setenv("UNSET_TOKEN", "")
+	os.Unsetenv("UNSET_TOKEN")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "file",
+			value: "id=mysecret,src=/local/secret",
+			want:  "id=mysecret,src=/local/secret",
+		},
+		{
+			name:  "file with source",
+			value: "id=mysecret,source=/local/secret",
+			want:  "id=mysecret,src=/local/secret",
+		},
+		{
+			name:  "env",
+			value: "id=mytoken,env=MY_TOKEN",
+			want:  "id=mytoken,env=MY_TOKEN",
+		},
+		{
+			name:  "type=env with id",
+			value: "type=env,id=MY_TOKEN",
+			want:  "id=MY_TOKEN,env=MY_TOKEN",
+		},
+		{
+			name:  "type=env with src",
+			value: "type=env,id=mytoken,src=MY_TOKEN",
+			want:  "id=mytoken,env=MY_TOKEN",
+		},
+		{
+			name:  "env without id",
+			value: "env=MY_TOKEN",
+			want:  "id=MY_TOKEN,env=MY_TOKEN",
+		},
+		{
+			name:  "id of a set environment variable",
+			value: "id=MY_TOKEN",
+			want:  "id=MY_TOKEN,env=MY_TOKEN",
+		},
+		{
+			name:  "id of an unset environment variable",
+			value: "id=UNSET_TOKEN",
+			want:  "id=UNSET_TOKEN,src=UNSET_TOKEN",
+		},
+		{
+			name:  "path with a comma",
+			value: `id=mysecret,"src=/local/a,b"`,
+			want:  `id=mysecret,"src=/local/a,b"`,
+		},
+		{
+			name:    "unset environment variable",
+			value:   "id=mytoken,env=UNSET_TOKEN",
+			wantErr: `environment variable "UNSET_TOKEN" is not set`,
+		},
+		{
+			name:    "src and env",
+			value:   "id=mytoken,src=/local/secret,env=MY_TOKEN",
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "unsupported type",
+			value:   "type=vault,id=mytoken",
+			wantErr: "unsupported type",
+		},
+		{
+			name:    "missing id",
+			value:   "src=/local/secret",
+			wantErr: "needs an id",
+		},
+		{
+			name:    "unknown field",
+			value:   "id=mytoken,foo=bar",
+			wantErr: "invalid field",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseSecretOption(tc.value)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
+
+func TestHasAttestations(t *testing.T) {
+	tests := []struct {
+		attest []string
+		want   bool
+	}{
+		{nil, false},
+		{[]string{"type=sbom"}, true},
+		{[]string{"type=provenance,mode=min"}, true},
+		{[]string{"type=sbom,disabled=true"}, false},
+		{[]string{"type=sbom,disabled=false"}, true},
+		{[]string{"type=sbom,disabled=true", "type=provenance,mode=max"}, true},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, hasAttestations(tc.attest), tc.want, "attest=%v", tc.attest)
+	}
+}
+
+func TestRegistryAuthTLSContextArgs(t *testing.T) {
+	args := registryAuthTLSContextArgs(map[string]config.RegistryConfig{
+		"registry.example.com:5000": {ClientCert: "/etc/nerdctl/client.cert", ClientKey: "/etc/nerdctl/client.key"},
+		"other.example.com":         {ClientCert: "/etc/nerdctl/other.pem"},
+		"noop.example.com":          {},
+	})
+	assert.DeepEqual(t, args, []string{
+		"--registry-auth-tlscontext=host=other.example.com,cert=/etc/nerdctl/other.pem,key=/etc/nerdctl/other.pem",
+		"--registry-auth-tlscontext=host=registry.example.com:5000,cert=/etc/nerdctl/client.cert,key=/etc/nerdctl/client.key",
+	})
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
+)
+
+// The values of --call.
+const (
+	// CallBuild builds the image, this is the default
+	CallBuild = "build"
+	// CallCheck runs the lint checks of the Dockerfile frontend without building the image
+	CallCheck = "check"
+	// CallOutline prints the build args, secrets and SSH sockets used by the target
+	CallOutline = "outline"
+	// CallTargets prints the stages of the Dockerfile that can be built with --target
+	CallTargets = "targets"
+)
+
+// CallTypes are the values accepted by --call.
+var CallTypes = []string{CallBuild, CallCheck, CallOutline, CallTargets}
+
+// callRequests are the subrequests of the Dockerfile frontend of BuildKit for the values of --call.
+var callRequests = map[string]string{
+	CallCheck:   "frontend.lint",
+	CallOutline: "frontend.outline",
+	CallTargets: "frontend.targets",
+}
+
+// call runs the --call request other than "build" with the Dockerfile frontend of BuildKit, without building anything.
+// buildctl prints the result of the subrequest to the standard output.
+func call(ctx context.Context, client *containerd.Client, options types.BuilderBuildOptions) error {
+	if !slices.Contains(CallTypes, options.Call) {
+		return fmt.Errorf("unsupported --call value %q, expected one of %v: %w", options.Call, CallTypes, errdefs.ErrInvalidArgument)
+	}
+	if options.Builder == BuilderContainerd {
+		return fmt.Errorf("--call=%s needs BuildKit, and can't be used with --builder=%s: %w", options.Call, BuilderContainerd, errdefs.ErrNotImplemented)
+	}
+	// nothing is exported
+	options.Tag = nil
+	options.IidFile = ""
+	options.Push = false
+	options.Load = false
+	options.Output = ""
+
+	buildctlBinary, buildctlArgs, _, _, _, cleanup, err := generateBuildctlArgs(ctx, client, options)
+	if err != nil {
+		return err
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	log.L.Debugf("running %s %v", buildctlBinary, buildctlArgs)
+	var result bytes.Buffer
+	buildctlCmd := exec.Command(buildctlBinary, buildctlArgs...)
+	buildctlCmd.Env = append(os.Environ(), tracingutil.Environ(ctx)...)
+	buildctlCmd.Stdout = io.MultiWriter(options.Stdout, &result)
+	if !options.Quiet {
+		buildctlCmd.Stderr = options.Stderr
+	}
+	if err := buildctlCmd.Run(); err != nil {
+		return err
+	}
+	if options.Call != CallCheck {
+		return nil
+	}
+	return checkResult(options.Stdout, result.String())
+}
+
+// checkResult fails when the result of the lint subrequest has warnings, as buildctl does not.
+// The result has a "WARNING: <rule> - <url>" line for each warning.
+func checkResult(w io.Writer, result string) error {
+	var warnings int
+	for _, line := range strings.Split(result, "\n") {
+		if strings.HasPrefix(line, "WARNING: ") {
+			warnings++
+		}
+	}
+	switch warnings {
+	case 0:
+		_, err := fmt.Fprintln(w, "Check complete, no warnings found.")
+		return err
+	case 1:
+		return fmt.Errorf("check complete, 1 warning has been found")
+	default:
+		return fmt.Errorf("check complete, %d warnings have been found", warnings)
+	}
+}
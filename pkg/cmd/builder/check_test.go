@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckResult(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	assert.NilError(t, checkResult(&b, ""))
+	assert.Equal(t, b.String(), "Check complete, no warnings found.\n")
+
+	const warning = `
+WARNING: MaintainerDeprecated - https://docs.docker.com/go/dockerfile/rule/maintainer-deprecated/
+Maintainer instruction is deprecated in favor of using label
+Dockerfile:2
+--------------------
+   1 |     FROM alpine
+   2 | >>> MAINTAINER foo
+   3 |     RUN <<EOT
+--------------------
+`
+	b.Reset()
+	assert.ErrorContains(t, checkResult(&b, warning), "1 warning has been found")
+	assert.ErrorContains(t, checkResult(&b, warning+warning), "2 warnings have been found")
+	assert.Equal(t, b.String(), "")
+}
@@ -0,0 +1,155 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/containerd/log"
+)
+
+// dockerfileInstruction is an instruction of a Dockerfile, as parsed by the containerd builder.
+type dockerfileInstruction struct {
+	// Line is the line number where the instruction starts
+	Line int
+	// Cmd is the upper-cased instruction, e.g., "RUN"
+	Cmd string
+	// Flags are the flags of the instruction, e.g., "--chown=1000:1000"
+	Flags []string
+	// Args is the rest of the instruction, with the line continuations joined
+	Args string
+	// Original is the instruction as shown in the build output and in the image history
+	Original string
+}
+
+var dockerfileDirectiveRegexp = regexp.MustCompile(`^#\s*([a-zA-Z][a-zA-Z0-9]*)\s*=\s*(.+?)\s*$`)
+
+// parseDockerfile parses a Dockerfile into instructions.
+// Comments and line continuations are handled, but heredocs are not supported.
+func parseDockerfile(r io.Reader) ([]dockerfileInstruction, error) {
+	var (
+		res          []dockerfileInstruction
+		cur          strings.Builder
+		start        int
+		lineNo       int
+		inDirectives = true
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		trimmed := strings.TrimSpace(line)
+		if inDirectives {
+			if m := dockerfileDirectiveRegexp.FindStringSubmatch(trimmed); m != nil {
+				switch strings.ToLower(m[1]) {
+				case "syntax":
+					log.L.Warnf("Ignoring the syntax directive %q, the containerd builder does not support frontends", m[2])
+				case "escape":
+					if m[2] != `\` {
+						return nil, fmt.Errorf("line %d: unsupported escape directive %q", lineNo, m[2])
+					}
+				}
+				continue
+			}
+			inDirectives = false
+		}
+		// comments and empty lines are skipped, also inside a line continuation
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if cur.Len() == 0 {
+			start = lineNo
+			line = trimmed
+		}
+		if strings.HasSuffix(trimmed, `\`) {
+			line = strings.TrimRightFunc(line, unicode.IsSpace)
+			cur.WriteString(strings.TrimSuffix(line, `\`))
+			continue
+		}
+		cur.WriteString(line)
+		inst, err := parseDockerfileInstruction(start, cur.String())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, inst)
+		cur.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cur.Len() > 0 {
+		inst, err := parseDockerfileInstruction(start, cur.String())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, inst)
+	}
+	if len(res) == 0 {
+		return nil, errors.New("the Dockerfile has no instructions")
+	}
+	return res, nil
+}
+
+func parseDockerfileInstruction(lineNo int, s string) (dockerfileInstruction, error) {
+	cmd, rest := s, ""
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		cmd, rest = s[:i], s[i:]
+	}
+	inst := dockerfileInstruction{
+		Line:     lineNo,
+		Cmd:      strings.ToUpper(cmd),
+		Original: s,
+	}
+	rest = strings.TrimSpace(rest)
+	for strings.HasPrefix(rest, "--") {
+		flag := rest
+		if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+			flag, rest = rest[:i], strings.TrimSpace(rest[i:])
+		} else {
+			rest = ""
+		}
+		inst.Flags = append(inst.Flags, flag)
+	}
+	inst.Args = rest
+	if inst.Args == "" {
+		return inst, fmt.Errorf("line %d: %s requires at least one argument", lineNo, inst.Cmd)
+	}
+	return inst, nil
+}
+
+// parseDockerfileJSON parses the exec form (`["executable", "param1"]`) of an instruction.
+// ok is false when s is not in the exec form.
+func parseDockerfileJSON(s string) (res []string, ok bool) {
+	if !strings.HasPrefix(s, "[") {
+		return nil, false
+	}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return nil, false
+	}
+	return res, true
+}
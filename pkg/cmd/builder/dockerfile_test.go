@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	t.Parallel()
+
+	const dockerfile = `# syntax=docker/dockerfile:1
+# a comment
+FROM alpine AS base
+
+RUN apk add --no-cache \
+    # comments are skipped inside continuations
+    curl \
+    jq
+copy --chown=1000:1000 ./app /app
+CMD ["sh"]
+`
+	instructions, err := parseDockerfile(strings.NewReader(dockerfile))
+	assert.NilError(t, err)
+	assert.Equal(t, len(instructions), 4)
+
+	assert.Equal(t, instructions[0].Line, 3)
+	assert.Equal(t, instructions[0].Cmd, "FROM")
+	assert.Equal(t, instructions[0].Args, "alpine AS base")
+
+	assert.Equal(t, instructions[1].Line, 5)
+	assert.Equal(t, instructions[1].Cmd, "RUN")
+	assert.Equal(t, instructions[1].Args, "apk add --no-cache     curl     jq")
+	assert.Equal(t, len(instructions[1].Flags), 0)
+
+	assert.Equal(t, instructions[2].Cmd, "COPY")
+	assert.DeepEqual(t, instructions[2].Flags, []string{"--chown=1000:1000"})
+	assert.Equal(t, instructions[2].Args, "./app /app")
+
+	cmd, ok := parseDockerfileJSON(instructions[3].Args)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, cmd, []string{"sh"})
+	_, ok = parseDockerfileJSON("echo [not json]")
+	assert.Assert(t, !ok)
+
+	_, err = parseDockerfile(strings.NewReader("# only a comment\n"))
+	assert.ErrorContains(t, err, "no instructions")
+	_, err = parseDockerfile(strings.NewReader("FROM alpine\nRUN\n"))
+	assert.ErrorContains(t, err, "line 2: RUN requires at least one argument")
+	_, err = parseDockerfile(strings.NewReader("# escape=`\nFROM alpine\n"))
+	assert.ErrorContains(t, err, "unsupported escape directive")
+}
@@ -0,0 +1,232 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/builderstore"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+)
+
+// BuilderDefault is the name of the builder using the auto-detected buildkitd.
+const BuilderDefault = "default"
+
+func isReservedBuilderName(name string) bool {
+	return name == BuilderDefault || name == BuilderContainerd
+}
+
+// Resolve returns the builder named by `--builder`.
+// An empty name stands for the builder selected with `nerdctl builder use`, or the default builder if none is selected.
+// The default builder has an empty BuildKitHost, meaning buildkitd has to be auto-detected.
+// The containerd builder is the built-in builder, which does not use buildkitd at all.
+func Resolve(dataRoot, name string) (*builderstore.Builder, error) {
+	st, err := builderstore.New(dataRoot)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		if name, err = st.Current(); err != nil {
+			return nil, err
+		}
+	}
+	switch name {
+	case "", BuilderDefault:
+		return &builderstore.Builder{Name: BuilderDefault}, nil
+	case BuilderContainerd:
+		return &builderstore.Builder{Name: BuilderContainerd}, nil
+	}
+	return st.Get(name)
+}
+
+// Create creates a named builder for a buildkitd endpoint.
+func Create(options types.BuilderCreateOptions) error {
+	if isReservedBuilderName(options.Name) {
+		return fmt.Errorf("builder name %q is reserved: %w", options.Name, errdefs.ErrInvalidArgument)
+	}
+	buildkitHost := options.BuildKitHost
+	if buildkitHost == "" {
+		var err error
+		if buildkitHost, err = buildkitutil.GetBuildkitHost(options.GOptions.Namespace); err != nil {
+			return err
+		}
+	}
+	var platforms []string
+	for _, p := range options.Platforms {
+		normalized, err := platformutil.NormalizeString(p)
+		if err != nil {
+			return err
+		}
+		platforms = append(platforms, normalized)
+	}
+	st, err := builderstore.New(options.GOptions.DataRoot)
+	if err != nil {
+		return err
+	}
+	if err := st.Create(&builderstore.Builder{
+		Name:         options.Name,
+		BuildKitHost: buildkitHost,
+		Platforms:    platforms,
+	}); err != nil {
+		return err
+	}
+	if options.Use {
+		if err := st.Use(options.Name); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(options.Stdout, options.Name)
+	return err
+}
+
+// Use selects the builder to be used when `--builder` is not specified.
+func Use(options types.BuilderUseOptions) error {
+	st, err := builderstore.New(options.GOptions.DataRoot)
+	if err != nil {
+		return err
+	}
+	switch options.Name {
+	case BuilderDefault:
+		return st.Use("")
+	case BuilderContainerd:
+	default:
+		if _, err := st.Get(options.Name); err != nil {
+			return err
+		}
+	}
+	return st.Use(options.Name)
+}
+
+// Remove removes named builders. The buildkitd instances themselves are left untouched.
+func Remove(names []string, options types.BuilderRemoveOptions) error {
+	st, err := builderstore.New(options.GOptions.DataRoot)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		if isReservedBuilderName(name) {
+			errs = append(errs, fmt.Errorf("builder %q is built-in and cannot be removed: %w", name, errdefs.ErrInvalidArgument))
+			continue
+		}
+		if err := st.Remove(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintln(options.Stdout, name)
+	}
+	return errors.Join(errs...)
+}
+
+type builderListItem struct {
+	Name         string
+	BuildKitHost string
+	Platforms    []string
+	Status       string
+	Current      bool
+}
+
+// List lists the builders, including the built-in default and containerd ones.
+func List(options types.BuilderListOptions) error {
+	st, err := builderstore.New(options.GOptions.DataRoot)
+	if err != nil {
+		return err
+	}
+	current, err := st.Current()
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		current = BuilderDefault
+	}
+	builders, err := st.List()
+	if err != nil {
+		return err
+	}
+
+	defaultItem := builderListItem{Name: BuilderDefault, Status: "inactive"}
+	if buildkitHost, err := buildkitutil.DetectBuildkitHost(options.GOptions.Namespace); err == nil {
+		defaultItem.BuildKitHost = buildkitHost
+		defaultItem.Status = "running"
+	}
+	items := []builderListItem{
+		defaultItem,
+		{Name: BuilderContainerd, BuildKitHost: options.GOptions.Address, Status: "built-in"},
+	}
+	for _, b := range builders {
+		status := "running"
+		if err := buildkitutil.PingBKDaemon(b.BuildKitHost); err != nil {
+			status = "inactive"
+		}
+		items = append(items, builderListItem{
+			Name:         b.Name,
+			BuildKitHost: b.BuildKitHost,
+			Platforms:    b.Platforms,
+			Status:       status,
+		})
+	}
+	for i := range items {
+		items[i].Current = items[i].Name == current
+	}
+
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "NAME\tENDPOINT\tSTATUS\tPLATFORMS")
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		if tmpl, err = formatter.ParseTemplate(options.Format); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, item); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		name := item.Name
+		if item.Current {
+			name += " *"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, item.BuildKitHost, item.Status, strings.Join(item.Platforms, ",")); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.(*tabwriter.Writer); ok {
+		return f.Flush()
+	}
+	return nil
+}
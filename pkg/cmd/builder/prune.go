@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
@@ -30,19 +34,157 @@ import (
 )
 
 // Prune will prune all build cache.
+// With options.DryRun, it only returns the cache records that would be pruned.
 func Prune(ctx context.Context, options types.BuilderPruneOptions) ([]buildkitutil.UsageInfo, error) {
-	buildctlBinary, err := buildkitutil.BuildctlBinary()
+	if options.DryRun {
+		return pruneCandidates(ctx, options)
+	}
+	buildctlArgs, err := pruneArgs(options)
+	if err != nil {
+		return nil, err
+	}
+	return runBuildctlUsage(ctx, options.BuildKitHost, buildctlArgs, options.Stderr)
+}
+
+// DiskUsage returns the build cache records known to BuildKit.
+func DiskUsage(ctx context.Context, options types.BuilderDiskUsageOptions) ([]buildkitutil.UsageInfo, error) {
+	filters, keepDuration, err := convertFilters(options.Filters)
 	if err != nil {
 		return nil, err
 	}
-	buildctlArgs := buildkitutil.BuildctlBaseArgs(options.BuildKitHost)
-	buildctlArgs = append(buildctlArgs, "prune", "--format={{json .}}")
+	if keepDuration != "" {
+		return nil, fmt.Errorf("filter \"until\" is only supported by `builder prune`: %w", errdefs.ErrInvalidArgument)
+	}
+	buildctlArgs := []string{"du", "--format={{json .}}"}
+	for _, f := range filters {
+		buildctlArgs = append(buildctlArgs, "--filter="+f)
+	}
+	return runBuildctlUsage(ctx, options.BuildKitHost, buildctlArgs, options.Stderr)
+}
+
+// pruneCandidates lists the cache records that `buildctl prune` would remove.
+// Like BuildKit, the records in use are kept, and so are the shared, internal, and frontend records unless options.All is set.
+func pruneCandidates(ctx context.Context, options types.BuilderPruneOptions) ([]buildkitutil.UsageInfo, error) {
+	filters, keepDuration, err := convertFilters(options.Filters)
+	if err != nil {
+		return nil, err
+	}
+	buildctlArgs := []string{"du", "--format={{json .}}"}
+	for _, f := range filters {
+		buildctlArgs = append(buildctlArgs, "--filter="+f)
+	}
+	records, err := runBuildctlUsage(ctx, options.BuildKitHost, buildctlArgs, options.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	var keepSince time.Time
+	if keepDuration != "" {
+		d, err := time.ParseDuration(keepDuration)
+		if err != nil {
+			return nil, err
+		}
+		keepSince = time.Now().Add(-d)
+	}
+	candidates := make([]buildkitutil.UsageInfo, 0)
+	for _, r := range records {
+		if r.InUse {
+			continue
+		}
+		if !options.All && (r.Shared || r.RecordType == "internal" || r.RecordType == "frontend") {
+			continue
+		}
+		if !keepSince.IsZero() {
+			lastUsed := r.CreatedAt
+			if r.LastUsedAt != nil {
+				lastUsed = *r.LastUsedAt
+			}
+			if lastUsed.After(keepSince) {
+				continue
+			}
+		}
+		candidates = append(candidates, r)
+	}
+	return candidates, nil
+}
+
+// pruneArgs returns the buildctl arguments (without the base arguments) for pruning the build cache.
+func pruneArgs(options types.BuilderPruneOptions) ([]string, error) {
+	buildctlArgs := []string{"prune", "--format={{json .}}"}
 	if options.All {
 		buildctlArgs = append(buildctlArgs, "--all")
 	}
+	filters, keepDuration, err := convertFilters(options.Filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range filters {
+		buildctlArgs = append(buildctlArgs, "--filter="+f)
+	}
+	if keepDuration != "" {
+		buildctlArgs = append(buildctlArgs, "--keep-duration="+keepDuration)
+	}
+	// buildctl takes the storage limits in MB
+	for _, limit := range []struct {
+		flag  string
+		bytes int64
+	}{
+		{"keep-storage", options.KeepStorage},
+		{"max-used-space", options.MaxUsedSpace},
+		{"min-free-space", options.MinFreeSpace},
+	} {
+		if limit.bytes < 0 {
+			return nil, fmt.Errorf("invalid value for --%s: %d: %w", limit.flag, limit.bytes, errdefs.ErrInvalidArgument)
+		}
+		if limit.bytes > 0 {
+			buildctlArgs = append(buildctlArgs, fmt.Sprintf("--%s=%s", limit.flag, strconv.FormatFloat(float64(limit.bytes)/1e6, 'f', -1, 64)))
+		}
+	}
+	return buildctlArgs, nil
+}
+
+// convertFilters converts docker-style build cache filters into BuildKit filters.
+// The "until" filter has no BuildKit counterpart and is returned separately as the duration
+// to be passed with `buildctl prune --keep-duration`.
+func convertFilters(filters []string) (buildkitFilters []string, keepDuration string, err error) {
+	for _, f := range filters {
+		// BuildKit filter expressions are passed through as-is
+		if strings.Contains(f, "==") || strings.Contains(f, "!=") || strings.Contains(f, "~=") {
+			buildkitFilters = append(buildkitFilters, f)
+			continue
+		}
+		key, value, hasValue := strings.Cut(f, "=")
+		switch key {
+		case "until":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid value for filter %q: %w", f, errdefs.ErrInvalidArgument)
+			}
+			keepDuration = d.String()
+		case "id", "parent", "type", "description", "inuse", "shared", "private":
+			if key == "parent" {
+				key = "parents"
+			}
+			if hasValue {
+				buildkitFilters = append(buildkitFilters, key+"=="+value)
+			} else {
+				buildkitFilters = append(buildkitFilters, key)
+			}
+		default:
+			return nil, "", fmt.Errorf("unsupported filter %q: %w", f, errdefs.ErrInvalidArgument)
+		}
+	}
+	return buildkitFilters, keepDuration, nil
+}
+
+func runBuildctlUsage(ctx context.Context, buildkitHost string, args []string, stderr io.Writer) ([]buildkitutil.UsageInfo, error) {
+	buildctlBinary, err := buildkitutil.BuildctlBinary()
+	if err != nil {
+		return nil, err
+	}
+	buildctlArgs := append(buildkitutil.BuildctlBaseArgs(buildkitHost), args...)
 	buildctlCmd := exec.Command(buildctlBinary, buildctlArgs...)
 	log.G(ctx).Debugf("running %v", buildctlCmd.Args)
-	buildctlCmd.Stderr = options.Stderr
+	buildctlCmd.Stderr = stderr
 	stdout, err := buildctlCmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("faild to get stdout piper for %v: %w", buildctlCmd.Args, err)
@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestPruneArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		options types.BuilderPruneOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "default",
+			options: types.BuilderPruneOptions{},
+			want:    []string{"prune", "--format={{json .}}"},
+		},
+		{
+			name: "all with filters",
+			options: types.BuilderPruneOptions{
+				All:     true,
+				Filters: []string{"until=24h", "type=regular", "inuse", "parent=abc", "description~=foo"},
+			},
+			want: []string{"prune", "--format={{json .}}", "--all",
+				"--filter=type==regular", "--filter=inuse", "--filter=parents==abc", "--filter=description~=foo",
+				"--keep-duration=24h0m0s"},
+		},
+		{
+			name: "storage limits",
+			options: types.BuilderPruneOptions{
+				KeepStorage:  10 * 1000 * 1000 * 1000,
+				MaxUsedSpace: 1536 * 1000,
+				MinFreeSpace: 500,
+			},
+			want: []string{"prune", "--format={{json .}}",
+				"--keep-storage=10000", "--max-used-space=1.536", "--min-free-space=0.0005"},
+		},
+		{
+			name:    "invalid until",
+			options: types.BuilderPruneOptions{Filters: []string{"until=yesterday"}},
+			wantErr: "invalid value for filter",
+		},
+		{
+			name:    "unsupported filter",
+			options: types.BuilderPruneOptions{Filters: []string{"label=foo"}},
+			wantErr: "unsupported filter",
+		},
+		{
+			name:    "negative storage",
+			options: types.BuilderPruneOptions{KeepStorage: -1},
+			wantErr: "invalid value for --keep-storage",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pruneArgs(tt.options)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// gitURLSuffix matches http(s) git URLs such as https://github.com/org/repo.git#branch:subdir
+var gitURLSuffix = regexp.MustCompile(`\.git(?:#.+)?$`)
+
+// parseRemoteContext detects a remote build context, i.e., a git repository
+// ("<url>#<ref>:<subdir>", see https://docs.docker.com/build/concepts/context/#git-repositories)
+// or the HTTP(S) URL of a tarball or a Dockerfile.
+// The returned context is passed to the BuildKit Dockerfile frontend as is.
+func parseRemoteContext(s string) (remote string, isGit bool, ok bool) {
+	switch {
+	case strings.HasPrefix(s, "git://"), strings.HasPrefix(s, "git@"), strings.HasPrefix(s, "ssh://"):
+		return s, true, true
+	case strings.HasPrefix(s, "github.com/"):
+		return "https://" + s, true, true
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return s, gitURLSuffix.MatchString(s), true
+	}
+	return "", false, false
+}
+
+// isSSHGitContext returns whether the git context is fetched over SSH.
+func isSSHGitContext(s string) bool {
+	return strings.HasPrefix(s, "git@") || strings.HasPrefix(s, "ssh://")
+}
+
+// gitHost returns the host of a git context, e.g. "github.com".
+func gitHost(s string) string {
+	s, _, _ = strings.Cut(s, "#")
+	if rest, ok := strings.CutPrefix(s, "git@"); ok {
+		host, _, _ := strings.Cut(rest, ":")
+		return host
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// gitAuthArgs returns the buildctl arguments that forward the credentials for fetching a git context:
+// $GIT_AUTH_TOKEN and $GIT_AUTH_HEADER as the secrets BuildKit looks up for the host,
+// and the SSH agent for SSH URLs, unless the user already specified them.
+func gitAuthArgs(gitContext string, secrets, ssh []string) []string {
+	var args []string
+	if host := gitHost(gitContext); host != "" {
+		for _, env := range []string{"GIT_AUTH_TOKEN", "GIT_AUTH_HEADER"} {
+			if os.Getenv(env) == "" {
+				continue
+			}
+			id := env + "." + host
+			if hasSecretID(secrets, id) {
+				continue
+			}
+			args = append(args, fmt.Sprintf("--secret=id=%s,env=%s", id, env))
+		}
+	}
+	if isSSHGitContext(gitContext) && len(ssh) == 0 && os.Getenv("SSH_AUTH_SOCK") != "" {
+		args = append(args, "--ssh=default")
+	}
+	return args
+}
+
+func hasSecretID(secrets []string, id string) bool {
+	for _, s := range secrets {
+		for _, field := range strings.Split(s, ",") {
+			if field == "id="+id {
+				return true
+			}
+		}
+	}
+	return false
+}
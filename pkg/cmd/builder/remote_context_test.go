@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRemoteContext(t *testing.T) {
+	tests := []struct {
+		in     string
+		remote string
+		isGit  bool
+		ok     bool
+	}{
+		{in: ".", ok: false},
+		{in: "/path/to/ctx", ok: false},
+		{in: "https://github.com/org/repo.git#main:subdir", remote: "https://github.com/org/repo.git#main:subdir", isGit: true, ok: true},
+		{in: "https://github.com/org/repo.git", remote: "https://github.com/org/repo.git", isGit: true, ok: true},
+		{in: "git@github.com:org/repo.git#v1.0", remote: "git@github.com:org/repo.git#v1.0", isGit: true, ok: true},
+		{in: "ssh://git@example.com/org/repo", remote: "ssh://git@example.com/org/repo", isGit: true, ok: true},
+		{in: "git://example.com/repo", remote: "git://example.com/repo", isGit: true, ok: true},
+		{in: "github.com/org/repo#main", remote: "https://github.com/org/repo#main", isGit: true, ok: true},
+		{in: "https://example.com/context.tar.gz", remote: "https://example.com/context.tar.gz", isGit: false, ok: true},
+	}
+	for _, tc := range tests {
+		remote, isGit, ok := parseRemoteContext(tc.in)
+		assert.Equal(t, ok, tc.ok, tc.in)
+		assert.Equal(t, isGit, tc.isGit, tc.in)
+		assert.Equal(t, remote, tc.remote, tc.in)
+	}
+}
+
+func TestGitHost(t *testing.T) {
+	assert.Equal(t, gitHost("https://github.com/org/repo.git#main:subdir"), "github.com")
+	assert.Equal(t, gitHost("git@gitlab.example.com:org/repo.git"), "gitlab.example.com")
+	assert.Equal(t, gitHost("ssh://git@example.com:2222/org/repo"), "example.com")
+}
+
+func TestGitAuthArgs(t *testing.T) {
+	t.Setenv("GIT_AUTH_TOKEN", "token")
+	t.Setenv("GIT_AUTH_HEADER", "")
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/agent.sock")
+
+	assert.DeepEqual(t, gitAuthArgs("https://github.com/org/repo.git", nil, nil),
+		[]string{"--secret=id=GIT_AUTH_TOKEN.github.com,env=GIT_AUTH_TOKEN"})
+	assert.DeepEqual(t, gitAuthArgs("git@github.com:org/repo.git", nil, nil),
+		[]string{"--secret=id=GIT_AUTH_TOKEN.github.com,env=GIT_AUTH_TOKEN", "--ssh=default"})
+	// user-specified secrets and SSH sockets take precedence
+	assert.Equal(t, len(gitAuthArgs("git@github.com:org/repo.git",
+		[]string{"id=GIT_AUTH_TOKEN.github.com,src=/run/secrets/token"}, []string{"default=/tmp/other.sock"})), 0)
+}
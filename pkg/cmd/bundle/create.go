@@ -0,0 +1,128 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+// Create packages the compose project, the images it references and the configurations of
+// the networks it uses into a single archive.
+func Create(ctx context.Context, client *containerd.Client, c *composer.Composer, options types.BundleCreateOptions) error {
+	if options.Output == "" {
+		return fmt.Errorf("output path must be specified")
+	}
+
+	imgs, err := c.BundleImages(ctx, options.Services)
+	if err != nil {
+		return err
+	}
+	if len(imgs) == 0 {
+		return fmt.Errorf("no images found in the compose project")
+	}
+
+	var composeYAML bytes.Buffer
+	if err := c.Config(ctx, &composeYAML, composer.ConfigOptions{}); err != nil {
+		return err
+	}
+
+	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
+	if err != nil {
+		return err
+	}
+	netMap, err := cniEnv.NetworkMap()
+	if err != nil {
+		return err
+	}
+
+	imagesFile, err := os.CreateTemp("", "nerdctl-bundle-images-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(imagesFile.Name())
+	defer imagesFile.Close()
+	saveOptions := types.ImageSaveOptions{
+		Stdout:       imagesFile,
+		GOptions:     options.GOptions,
+		Platform:     options.Platform,
+		AllPlatforms: options.AllPlatforms,
+	}
+	if err := image.Save(ctx, client, imgs, saveOptions); err != nil {
+		return err
+	}
+
+	out, err := os.Create(options.Output)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(out)
+	m := manifest{
+		Version: manifestVersion,
+		Images:  imgs,
+	}
+	err = func() error {
+		if err := writeBytes(tw, composeFileName, composeYAML.Bytes()); err != nil {
+			return err
+		}
+		if err := writeFile(tw, imagesFileName, imagesFile); err != nil {
+			return err
+		}
+		for _, name := range c.NetworkNames() {
+			net, ok := netMap[name]
+			if !ok {
+				// The network will be created by `nerdctl compose up` on the destination host
+				log.G(ctx).Debugf("Network %s does not exist, not bundling its configuration", name)
+				continue
+			}
+			if err := writeBytes(tw, networksDir+name+".conflist", net.Bytes); err != nil {
+				return err
+			}
+			m.Networks = append(m.Networks, name)
+		}
+		if err := writeManifest(tw, m); err != nil {
+			return err
+		}
+		return tw.Close()
+	}()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(options.Output)
+		return err
+	}
+
+	for _, img := range m.Images {
+		fmt.Fprintf(options.Stdout, "Bundled image: %s\n", img)
+	}
+	for _, net := range m.Networks {
+		fmt.Fprintf(options.Stdout, "Bundled network: %s\n", net)
+	}
+	return nil
+}
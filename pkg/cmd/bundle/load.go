@@ -0,0 +1,155 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/load"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+// Load loads the images and the network configurations of a bundle created by Create,
+// and extracts its compose file to options.Directory.
+func Load(ctx context.Context, client *containerd.Client, options types.BundleLoadOptions) error {
+	if err := clientutil.CheckLocalState(options.GOptions.Address); err != nil {
+		return err
+	}
+	composePath := filepath.Join(options.Directory, composeFileName)
+	if _, err := os.Stat(composePath); err == nil {
+		return fmt.Errorf("%s already exists", composePath)
+	}
+
+	f, err := os.Open(options.Input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tempDir, err := os.MkdirTemp("", "nerdctl-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	var (
+		m            *manifest
+		composeYAML  []byte
+		imagesPath   string
+		networkConfs [][]byte
+	)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read bundle %s: %w", options.Input, err)
+		}
+		switch {
+		case hdr.Name == manifestFileName:
+			m = &manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", manifestFileName, err)
+			}
+		case hdr.Name == composeFileName:
+			if composeYAML, err = io.ReadAll(tr); err != nil {
+				return err
+			}
+		case hdr.Name == imagesFileName:
+			imagesPath = filepath.Join(tempDir, imagesFileName)
+			if err := copyToFile(imagesPath, tr); err != nil {
+				return err
+			}
+		case strings.HasPrefix(hdr.Name, networksDir) && strings.HasSuffix(hdr.Name, ".conflist"):
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			networkConfs = append(networkConfs, b)
+		default:
+			log.G(ctx).Warnf("Ignoring unknown bundle entry %q", hdr.Name)
+		}
+	}
+	if m == nil || composeYAML == nil || imagesPath == "" {
+		return fmt.Errorf("%s is not a valid bundle", options.Input)
+	}
+	if m.Version != manifestVersion {
+		return fmt.Errorf("unsupported bundle version %d", m.Version)
+	}
+
+	loadOptions := types.ImageLoadOptions{
+		Stdout:       options.Stdout,
+		GOptions:     options.GOptions,
+		Input:        imagesPath,
+		AllPlatforms: true,
+		Quiet:        options.Quiet,
+	}
+	if _, err := load.FromArchive(ctx, client, loadOptions); err != nil {
+		return err
+	}
+
+	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
+	if err != nil {
+		return err
+	}
+	for _, b := range networkConfs {
+		net, err := cniEnv.ImportNetwork(b)
+		if errdefs.IsAlreadyExists(err) {
+			log.G(ctx).WithError(err).Info("Not importing the network configuration from the bundle")
+			continue
+		} else if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Stdout, "Loaded network: %s\n", net.Name)
+	}
+
+	if err := os.MkdirAll(options.Directory, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(composePath, composeYAML, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(options.Stdout, "Wrote compose file: %s\n", composePath)
+	return nil
+}
+
+func copyToFile(path string, r io.Reader) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
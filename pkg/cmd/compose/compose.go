@@ -157,6 +157,10 @@ func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, op
 		return err
 	}
 
+	options.ResolveDigest = func(ctx context.Context, imageName string) (string, error) {
+		return imgutil.ResolveDigest(ctx, imageName, globalOptions.InsecureRegistry, globalOptions.HostsDir)
+	}
+
 	return composer.New(options, client, (*config.Config)(&globalOptions))
 }
 
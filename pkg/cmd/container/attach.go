@@ -36,6 +36,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/signalutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 )
 
 // Attach attaches stdin, stdout, and stderr to a running container.
@@ -126,7 +127,16 @@ func Attach(ctx context.Context, client *containerd.Client, req string, options
 	} else {
 		opt = cio.WithStreams(options.Stdin, options.Stdout, options.Stderr)
 	}
-	task, err = container.Task(ctx, cio.NewAttach(opt))
+	attach := cio.NewAttach(opt)
+	if sshutil.IsAddress(options.GOptions.Address) {
+		// the FIFOs are on the SSH host, as well as the shim
+		host, err := sshutil.ParseAddress(options.GOptions.Address)
+		if err != nil {
+			return err
+		}
+		attach = host.NewAttach(opt)
+	}
+	task, err = container.Task(ctx, attach)
 	if err != nil {
 		return fmt.Errorf("failed to attach to the container: %w", err)
 	}
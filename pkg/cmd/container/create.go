@@ -27,22 +27,26 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
 	dockercliopts "github.com/docker/cli/opts"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"go.opentelemetry.io/otel/attribute"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/annotations"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/namespace"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
@@ -59,17 +63,34 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/maputil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/networkstore"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 // Create will create a container.
-func Create(ctx context.Context, client *containerd.Client, args []string, netManager containerutil.NetworkOptionsManager, options types.ContainerCreateOptions) (containerd.Container, func(), error) {
+func Create(ctx context.Context, client *containerd.Client, args []string, netManager containerutil.NetworkOptionsManager, options types.ContainerCreateOptions) (_ containerd.Container, _ func(), retErr error) {
+	// The spans of the image pull and of the containerd client are recorded under this span
+	ctx, span := tracingutil.StartSpan(ctx, "nerdctl.container.create", attribute.String("container.name", options.Name))
+	defer func() { tracingutil.EndSpan(span, retErr) }()
+
+	if sshutil.IsAddress(options.GOptions.Address) {
+		// The OCI hooks, the logging binary, and the volumes would refer to nerdctl and the files on this host.
+		return nil, nil, fmt.Errorf("creating containers over SSH is not supported, use a context with `nerdctl context create --ssh` to run nerdctl on the remote host instead: %w", errdefs.ErrNotImplemented)
+	}
+
+	if err := namespace.CheckQuota(ctx, client, options.GOptions); err != nil {
+		return nil, nil, err
+	}
+
 	// Acquire an exclusive lock on the volume store until we are done to avoid being raced by any other
 	// volume operations (or any other operation involving volume manipulation)
 	volStore, err := volume.Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
@@ -89,9 +110,22 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		newArg = append(newArg, args[2:]...)
 		args = newArg
 	}
+	options.SecurityOpt = normalizeSecurityOpts(options.SecurityOpt)
+	if options.Bypass4netnsChanged {
+		options.Annotations, err = withBypass4netnsAnnotation(options.Bypass4netns, options.Annotations)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	options.Annotations, err = withRootlessPortDriverAnnotations(ctx, options.RootlessPortDriver, netManager.NetworkOptions().NetworkSlice, options.Annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var internalLabels internalLabels
 	internalLabels.platform = options.Platform
 	internalLabels.namespace = options.GOptions.Namespace
+	internalLabels.securityOpt = options.SecurityOpt
 
 	var (
 		id    = idgen.GenerateID()
@@ -226,7 +260,11 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	} else {
 		if !options.Rootfs {
 			// UserNS not set and its a normal image
-			cOpts = append(cOpts, containerd.WithNewSnapshot(id, ensuredImage.Image))
+			snapshotOpts, err := imgutil.RootfsSnapshotOpts(ctx, ensuredImage)
+			if err != nil {
+				return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+			}
+			cOpts = append(cOpts, containerd.WithNewSnapshot(id, ensuredImage.Image, snapshotOpts...))
 		}
 	}
 
@@ -250,7 +288,7 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 
 	var mountOpts []oci.SpecOpts
-	mountOpts, internalLabels.anonVolumes, internalLabels.mountPoints, err = generateMountOpts(ctx, client, ensuredImage, volStore, options)
+	mountOpts, internalLabels.anonVolumes, internalLabels.mountPoints, err = generateMountOpts(ctx, client, ensuredImage, volStore, id, internalLabels.stateDir, options)
 	if err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
 	}
@@ -302,9 +340,13 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	// NOTE: OCI hooks are currently not supported on Windows so we skip setting them altogether.
 	// The OCI hooks we define (whose logic can be found in pkg/ocihook) primarily
 	// perform network setup and teardown when using CNI networking.
-	// On Windows, we are forced to set up and tear down the networking from within nerdctl.
-	if runtime.GOOS != "windows" {
-		hookOpt, err := withNerdctlOCIHook(options.NerdctlCmd, options.NerdctlArgs)
+	// On Windows and FreeBSD, we are forced to set up and tear down the networking from within nerdctl.
+	if runtime.GOOS != "windows" && runtime.GOOS != "freebsd" {
+		netType, err := nettype.Detect(netLabelOpts.NetworkSlice)
+		if err != nil {
+			return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
+		}
+		hookOpt, err := withNerdctlOCIHook(options.NerdctlCmd, options.NerdctlArgs, netType)
 		if err != nil {
 			return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
 		}
@@ -330,7 +372,19 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 	opts = append(opts, umaskOpts...)
 
-	rtCOpts, err := generateRuntimeCOpts(options.GOptions.CgroupManager, options.Runtime)
+	// chowning the mounts needs the user of the container
+	opts = append(opts, withChownMounts(internalLabels.mountPoints))
+	opts = append(opts, withRelabelMounts(internalLabels.mountPoints))
+	opts = append(opts, withIDMappedMounts(internalLabels.mountPoints))
+
+	// secrets are written after the user namespace is set, so that their owner can be mapped
+	secretOpts, err := generateSecretOpts(ctx, internalLabels.stateDir, options.GOptions.Namespace, id, options.Secret)
+	if err != nil {
+		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
+	}
+	opts = append(opts, secretOpts...)
+
+	rtCOpts, err := generateRuntimeCOpts(options.GOptions.CgroupManager, options.Runtime, options.GOptions.Runtimes)
 	if err != nil {
 		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), err
 	}
@@ -399,7 +453,9 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateRemoveOrphanedDirsFunc(ctx, id, dataStore, internalLabels), fmt.Errorf("Error writing to network-config.json: %v", err)
 	}
 
+	// the annotations of the runtime are overridden by --annotation
 	opts = append(opts, propagateInternalContainerdLabelsToOCIAnnotations(),
+		oci.WithAnnotations(generateRuntimeAnnotations(options.Runtime, options.GOptions.Runtimes)),
 		oci.WithAnnotations(strutil.ConvertKVStringsToMap(options.Annotations)))
 
 	var s specs.Spec
@@ -407,10 +463,13 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 
 	cOpts = append(cOpts, spec)
 
+	span.SetAttributes(attribute.String("container.id", id))
 	c, containerErr := client.NewContainer(ctx, id, cOpts...)
 	var netSetupErr error
 	if containerErr == nil {
-		netSetupErr = netManager.SetupNetworking(ctx, id)
+		netCtx, netSpan := tracingutil.StartSpan(ctx, "nerdctl.network.prepare")
+		netSetupErr = netManager.SetupNetworking(netCtx, id)
+		tracingutil.EndSpan(netSpan, netSetupErr)
 		if netSetupErr != nil {
 			log.G(ctx).WithError(netSetupErr).Warnf("networking setup error has occurred")
 		}
@@ -424,6 +483,7 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 		return nil, generateGcFunc(ctx, c, options.GOptions.Namespace, id, options.Name, dataStore, containerErr, containerNameStore, netManager, internalLabels), returnedError
 	}
 
+	publishVolumeEvents(ctx, client, internalLabels.mountPoints, id)
 	return c, nil, nil
 }
 
@@ -563,8 +623,10 @@ func GenerateLogURI(dataStore string) (*url.URL, error) {
 	return cio.LogURIGenerator("binary", selfExe, args)
 }
 
-func withNerdctlOCIHook(cmd string, args []string) (oci.SpecOpts, error) {
-	if rootlessutil.IsRootless() {
+func withNerdctlOCIHook(cmd string, args []string, netType nettype.Type) (oci.SpecOpts, error) {
+	// pasta has to be launched in the host netns so as to forward the ports of the host,
+	// and it does not need CAP_NET_ADMIN in the detached netns.
+	if rootlessutil.IsRootless() && netType != nettype.Pasta {
 		detachedNetNS, err := rootlessutil.DetachedNetNS()
 		if err != nil {
 			return nil, fmt.Errorf("failed to check whether RootlessKit is running with --detach-netns: %w", err)
@@ -589,7 +651,9 @@ func withNerdctlOCIHook(cmd string, args []string) (oci.SpecOpts, error) {
 
 	args = append([]string{cmd}, append(args, "internal", "oci-hook")...)
 	// sbin is appended for iptables https://github.com/containerd/nerdctl/discussions/1536
-	env := append(os.Environ(), "PATH="+os.Getenv("PATH")+":/usr/sbin:/sbin")
+	// TRACEPARENT is dropped, as the trace context of this command would be stale when the hooks run on restarting the container
+	env := slices.DeleteFunc(os.Environ(), func(e string) bool { return strings.HasPrefix(e, "TRACEPARENT=") })
+	env = append(env, "PATH="+os.Getenv("PATH")+":/usr/sbin:/sbin")
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
 		if s.Hooks == nil {
 			s.Hooks = &specs.Hooks{}
@@ -692,6 +756,8 @@ type internalLabels struct {
 	name       string
 	hostname   string
 	domainname string
+	// network aliases from cmd options
+	networkAliases []string
 	// automatically generated
 	stateDir string
 	// network
@@ -724,6 +790,9 @@ type internalLabels struct {
 	// label for device mapping set by the --device flag
 	deviceMapping []dockercompat.DeviceMapping
 
+	// label for the --security-opt flags
+	securityOpt []string
+
 	user string
 
 	healthcheck string
@@ -744,6 +813,13 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 	}
 	m[labels.ExtraHosts] = string(extraHostsJSON)
 	m[labels.StateDir] = internalLabels.stateDir
+	if len(internalLabels.networkAliases) > 0 {
+		networkAliasesJSON, err := json.Marshal(internalLabels.networkAliases)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.NetworkAliases] = string(networkAliasesJSON)
+	}
 	networksJSON, err := json.Marshal(internalLabels.networks)
 	if err != nil {
 		return nil, err
@@ -827,6 +903,10 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		hostConfigLabel.Devices = append(hostConfigLabel.Devices, internalLabels.deviceMapping...)
 	}
 
+	if len(internalLabels.securityOpt) > 0 {
+		hostConfigLabel.SecurityOpt = internalLabels.securityOpt
+	}
+
 	hostConfigJSON, err := json.Marshal(hostConfigLabel)
 	if err != nil {
 		return nil, err
@@ -912,6 +992,7 @@ func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil
 func (il *internalLabels) loadNetOpts(opts types.NetworkOptions) {
 	il.hostname = opts.Hostname
 	il.domainname = opts.Domainname
+	il.networkAliases = opts.NetworkAliases
 	il.ipAddress = opts.IPAddress
 	il.ip6Address = opts.IP6Address
 	il.networks = opts.NetworkSlice
@@ -930,7 +1011,7 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			Name:        mp.Name,
 			Source:      mp.Mount.Source,
 			Destination: mp.Mount.Destination,
-			Driver:      "",
+			Driver:      mp.Driver,
 			Mode:        mp.Mode,
 		}
 		result[i].RW, result[i].Propagation = dockercompat.ParseMountProperties(strings.Split(mp.Mode, ","))
@@ -940,8 +1021,7 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			result[i].Name = mp.AnonymousVolume
 		}
 
-		// volume only support local driver
-		if mp.Type == "volume" {
+		if mp.Type == "volume" && mp.Driver == "" {
 			result[i].Driver = "local"
 		}
 	}
@@ -1043,8 +1123,29 @@ func generateLogConfig(dataStore string, id string, logDriver string, logOpt []s
 	return logConfig, nil
 }
 
+// normalizeSecurityOpts converts the deprecated `<KEY>:<VALUE>` form of the security-opts (e.g., `no-new-privileges:true`)
+// used by Docker Compose files to `<KEY>=<VALUE>`, and drops the duplicates.
+func normalizeSecurityOpts(securityOpts []string) []string {
+	res := make([]string, 0, len(securityOpts))
+	for _, opt := range securityOpts {
+		if !strings.Contains(opt, "=") {
+			if k, v, ok := strings.Cut(opt, ":"); ok {
+				opt = k + "=" + v
+			}
+		}
+		res = append(res, opt)
+	}
+	return strutil.DedupeStrSlice(res)
+}
+
 func generateRemoveStateDirFunc(ctx context.Context, id string, internalLabels internalLabels) func() {
 	return func() {
+		if rmErr := secretutil.Remove(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q secrets", id)
+		}
+		if rmErr := mountutil.RemoveVolumeSubpaths(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q volume subpaths", id)
+		}
 		if rmErr := os.RemoveAll(internalLabels.stateDir); rmErr != nil {
 			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q state dir %q", id, internalLabels.stateDir)
 		}
@@ -1053,6 +1154,12 @@ func generateRemoveStateDirFunc(ctx context.Context, id string, internalLabels i
 
 func generateRemoveOrphanedDirsFunc(ctx context.Context, id, dataStore string, internalLabels internalLabels) func() {
 	return func() {
+		if rmErr := secretutil.Remove(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q secrets", id)
+		}
+		if rmErr := mountutil.RemoveVolumeSubpaths(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q volume subpaths", id)
+		}
 		if rmErr := os.RemoveAll(internalLabels.stateDir); rmErr != nil {
 			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q state dir %q", id, internalLabels.stateDir)
 		}
@@ -1093,6 +1200,12 @@ func generateGcFunc(ctx context.Context, container containerd.Container, ns, id,
 		if ipcErr := ipcutil.CleanUp(ipc); ipcErr != nil {
 			log.G(ctx).WithError(ipcErr).Warnf("failed to clean up ipc for container %q", id)
 		}
+		if rmErr := secretutil.Remove(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q secrets", id)
+		}
+		if rmErr := mountutil.RemoveVolumeSubpaths(internalLabels.stateDir); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q volume subpaths", id)
+		}
 		if rmErr := os.RemoveAll(internalLabels.stateDir); rmErr != nil {
 			log.G(ctx).WithError(rmErr).Warnf("failed to remove container %q state dir %q", id, internalLabels.stateDir)
 		}
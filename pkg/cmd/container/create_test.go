@@ -0,0 +1,40 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestCreateOverSSH(t *testing.T) {
+	options := types.ContainerCreateOptions{
+		GOptions: types.GlobalCommandOptions{Address: "ssh://user@host.example.com"},
+	}
+	// The address is checked before using the client
+	_, gc, err := Create(context.Background(), nil, []string{"alpine"}, nil, options)
+	assert.Assert(t, gc == nil)
+	assert.Assert(t, errors.Is(err, errdefs.ErrNotImplemented), err)
+	assert.ErrorContains(t, err, "nerdctl context create --ssh")
+}
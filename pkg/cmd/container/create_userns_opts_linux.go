@@ -78,9 +78,19 @@ func getUserNamespaceOpts(
 		return nil, nil, errors.New("snapshotter does not support remap-ids capability")
 	}
 
-	idMapping, err := loadAndValidateIDMapping(options.UserNS)
-	if err != nil {
+	var idMapping IdentityMapping
+	if size, isAuto, err := parseUsernsAuto(options.UserNS); err != nil {
 		return nil, nil, err
+	} else if isAuto {
+		idMapping, err = allocateAutoIdentityMapping(options.GOptions.DataRoot, options.GOptions.Address, options.GOptions.Namespace, id, size)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		idMapping, err = loadAndValidateIDMapping(options.UserNS)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	uidMaps, gidMaps := convertMappings(idMapping)
@@ -0,0 +1,108 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+)
+
+// DefaultDebugImage is the default image of `nerdctl debug`.
+const DefaultDebugImage = "busybox"
+
+// Debug runs a temporary container of options.Image that joins the PID, network, and IPC namespaces of the target container,
+// so that the target can be inspected with the tools of the image, even when its own image has no shell (e.g., distroless).
+// The target is not modified. Its root filesystem is visible at /proc/1/root in the debug container.
+func Debug(ctx context.Context, client *containerd.Client, req string, command []string, options types.ContainerDebugOptions) error {
+	var target containerd.Container
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			target = found.Container
+			return nil
+		},
+	}
+	n, err := walker.Walk(ctx, req)
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+	task, err := target.Task(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("container %s is not running: %w", req, err)
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Status != containerd.Running {
+		return fmt.Errorf("container %s is not running (status: %s)", req, status.Status)
+	}
+
+	id := target.ID()
+	args := append(options.NerdctlArgs, "run", "--rm",
+		"--pid=container:"+id,
+		"--network=container:"+id,
+		"--ipc=container:"+id,
+		// needed for accessing /proc/1/root of the target, and for tracing its processes
+		"--cap-add=SYS_PTRACE",
+	)
+	if options.Pull != "" {
+		args = append(args, "--pull="+options.Pull)
+	}
+	if options.Interactive {
+		args = append(args, "--interactive")
+	}
+	if options.TTY {
+		args = append(args, "--tty")
+	}
+	image := options.Image
+	if image == "" {
+		image = DefaultDebugImage
+	}
+	args = append(args, image)
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+	args = append(args, command...)
+
+	cmd := exec.CommandContext(ctx, options.NerdctlCmd, args...)
+	cmd.Stdin = options.Stdin
+	cmd.Stdout = options.Stdout
+	cmd.Stderr = options.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// the error of `nerdctl run` has already been printed
+			return errutil.NewExitCoderErr(exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
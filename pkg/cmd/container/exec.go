@@ -18,6 +18,7 @@ package container
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -35,7 +36,12 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/flagutil"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/maputil"
 	"github.com/containerd/nerdctl/v2/pkg/signalutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/taskutil"
 )
 
@@ -85,7 +91,16 @@ func execActionWithContainer(ctx context.Context, client *containerd.Client, con
 	if options.TTY {
 		cioOpts = append(cioOpts, cio.WithTerminal)
 	}
-	ioCreator = cio.NewCreator(cioOpts...)
+	if sshutil.IsAddress(options.GOptions.Address) {
+		// the FIFOs have to be on the SSH host, as well as the shim
+		host, err := sshutil.ParseAddress(options.GOptions.Address)
+		if err != nil {
+			return err
+		}
+		ioCreator = host.NewCreator(cioOpts...)
+	} else {
+		ioCreator = cio.NewCreator(cioOpts...)
+	}
 
 	execID := "exec-" + idgen.GenerateID()
 	process, err := task.Exec(ctx, execID, pspec, ioCreator)
@@ -198,5 +213,30 @@ func generateExecProcessSpec(ctx context.Context, client *containerd.Client, con
 		}
 	}
 
+	// the exec processes are always restricted by the no-new-privileges security-opt of the container,
+	// even if the spec of the container has been modified after its creation
+	nnp, err := noNewPrivileges(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+	if nnp {
+		pspec.NoNewPrivileges = true
+	}
+
 	return pspec, nil
 }
+
+// noNewPrivileges returns whether the container was created with the no-new-privileges security-opt.
+func noNewPrivileges(ctx context.Context, container containerd.Container) (bool, error) {
+	containerLabels, err := container.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	var hostConfigLabel dockercompat.HostConfigLabel
+	if hostConfigJSON, ok := containerLabels[labels.HostConfigLabel]; ok {
+		if err := json.Unmarshal([]byte(hostConfigJSON), &hostConfigLabel); err != nil {
+			return false, err
+		}
+	}
+	return maputil.MapBoolValueAsOpt(strutil.ConvertKVStringsToMap(hostConfigLabel.SecurityOpt), "no-new-privileges")
+}
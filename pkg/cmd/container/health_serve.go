@@ -0,0 +1,130 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// healthCheckServePollInterval is the interval to look for the containers with a healthcheck
+const healthCheckServePollInterval = time.Second
+
+// HealthCheckServe runs the healthchecks of the running containers at their intervals, until ctx is done.
+// The containers whose healthchecks are run by the transient systemd timers are skipped.
+func HealthCheckServe(ctx context.Context, client *containerd.Client, options types.ContainerHealthCheckServeOptions) error {
+	cfg := (*config.Config)(&options.GOptions)
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		next    = make(map[string]time.Time)
+		running = make(map[string]bool)
+	)
+	defer wg.Wait()
+
+	ticker := time.NewTicker(healthCheckServePollInterval)
+	defer ticker.Stop()
+	for {
+		containers, err := client.Containers(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.G(ctx).WithError(err).Warn("failed to list containers")
+		}
+
+		now := time.Now()
+		seen := make(map[string]struct{})
+		mu.Lock()
+		for _, c := range containers {
+			hc := scheduledHealthcheck(ctx, c, cfg)
+			if hc == nil {
+				continue
+			}
+			id := c.ID()
+			seen[id] = struct{}{}
+			t, ok := next[id]
+			if !ok {
+				// Like Docker, the first probe runs after the interval
+				next[id] = now.Add(hc.Interval)
+				continue
+			}
+			if now.Before(t) || running[id] {
+				continue
+			}
+			running[id] = true
+			wg.Add(1)
+			go func(c containerd.Container, interval time.Duration) {
+				defer wg.Done()
+				if err := HealthCheck(ctx, client, c); err != nil {
+					log.G(ctx).WithError(err).Debugf("healthcheck of container %s failed", c.ID())
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				running[c.ID()] = false
+				next[c.ID()] = time.Now().Add(interval)
+			}(c, hc.Interval)
+		}
+		for id := range next {
+			if _, ok := seen[id]; !ok && !running[id] {
+				delete(next, id)
+				delete(running, id)
+			}
+		}
+		mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduledHealthcheck returns the healthcheck of the container with the defaults applied,
+// or nil if the container is not running or its healthcheck is not to be scheduled by HealthCheckServe.
+func scheduledHealthcheck(ctx context.Context, c containerd.Container, cfg *config.Config) *healthcheck.Healthcheck {
+	l, err := c.Labels(ctx)
+	if err != nil {
+		return nil
+	}
+	hcJSON, ok := l[labels.HealthCheck]
+	if !ok || hcJSON == "" {
+		return nil
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil || len(hc.Test) == 0 || hc.Test[0] == healthcheck.CmdNone {
+		return nil
+	}
+	if healthcheck.IsTimerManaged(hc, cfg) {
+		return nil
+	}
+	if _, err := isContainerRunning(ctx, c); err != nil {
+		return nil
+	}
+	hc.ApplyDefaults()
+	return hc
+}
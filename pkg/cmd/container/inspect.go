@@ -25,7 +25,6 @@ import (
 	"github.com/containerd/containerd/v2/core/snapshots"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
-	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -36,7 +35,7 @@ import (
 
 // Inspect prints detailed information for each container in `containers`.
 func Inspect(ctx context.Context, client *containerd.Client, containers []string, options types.ContainerInspectOptions) ([]any, error) {
-	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	dataStore, err := portsDataStore(options.GOptions)
 	if err != nil {
 		return []any{}, err
 	}
@@ -42,6 +42,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 )
 
 // Kill kills a list of containers
@@ -128,6 +129,10 @@ func killContainer(ctx context.Context, container containerd.Container, signal s
 
 // cleanupNetwork removes cni network setup, specifically the forwards
 func cleanupNetwork(ctx context.Context, container containerd.Container, globalOpts types.GlobalCommandOptions) error {
+	if sshutil.IsAddress(globalOpts.Address) {
+		// the network of containerd on an SSH host is cleaned up by the postStop OCI hook on that host
+		return nil
+	}
 	return rootlessutil.WithDetachedNetNSIfAny(func() error {
 		// retrieve current active port mappings
 		dataStore, err := clientutil.DataStore(globalOpts.DataRoot, globalOpts.Address)
@@ -163,7 +168,7 @@ func cleanupNetwork(ctx context.Context, container containerd.Container, globalO
 		}
 
 		switch netType {
-		case nettype.Host, nettype.None, nettype.Container, nettype.Namespace:
+		case nettype.Host, nettype.None, nettype.Container, nettype.Namespace, nettype.Pasta:
 			// NOP
 		case nettype.CNI:
 			e, err := netutil.NewCNIEnv(globalOpts.CNIPath, globalOpts.CNINetConfPath, netutil.WithNamespace(globalOpts.Namespace), netutil.WithDefaultNetwork(globalOpts.BridgeIP))
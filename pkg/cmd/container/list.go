@@ -27,6 +27,7 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/containerd/v2/pkg/progress"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
@@ -39,6 +40,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 )
 
 // List prints containers according to `options`.
@@ -50,6 +52,57 @@ func List(ctx context.Context, client *containerd.Client, options types.Containe
 	return prepareContainers(ctx, client, containers, cMap, options)
 }
 
+// Watch calls render with the containers listed according to `options`, again whenever a container or task event
+// is emitted in the namespace, and at least every interval to refresh the CREATED and STATUS columns.
+// Watch returns when ctx is done.
+func Watch(ctx context.Context, client *containerd.Client, options types.ContainerListOptions, interval time.Duration, render func([]ListItem) error) error {
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return err
+	}
+	eventsCh, errCh := client.EventService().Subscribe(ctx,
+		fmt.Sprintf(`namespace==%s,topic~="^/tasks/"`, namespace),
+		fmt.Sprintf(`namespace==%s,topic~="^/containers/"`, namespace),
+	)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		containers, err := List(ctx, client, options)
+		if err != nil {
+			return err
+		}
+		if err := render(containers); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errCh:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+		case <-eventsCh:
+			// `nerdctl run` emits several events in a row (create, task create, task start, ...), so they are coalesced
+			debounce := time.After(watchDebounce)
+		drain:
+			for {
+				select {
+				case <-eventsCh:
+				case <-debounce:
+					break drain
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// watchDebounce is how long Watch waits for the subsequent events before listing the containers again.
+const watchDebounce = 100 * time.Millisecond
+
 // filterContainers returns containers matching the filters.
 //
 //   - Supported filters: https://github.com/containerd/nerdctl/blob/main/docs/command-reference.md#whale-blue_square-nerdctl-ps
@@ -137,6 +190,10 @@ func (x *ListItem) Label(s string) string {
 func prepareContainers(ctx context.Context, client *containerd.Client, containers []containerd.Container, statusPerContainer map[string]string, options types.ContainerListOptions) ([]ListItem, error) {
 	listItems := make([]ListItem, len(containers))
 	snapshottersCache := map[string]snapshots.Snapshotter{}
+	dataStore, err := portsDataStore(options.GOptions)
+	if err != nil {
+		return nil, err
+	}
 	for i, c := range containers {
 		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
 		if err != nil {
@@ -164,10 +221,6 @@ func prepareContainers(ctx context.Context, client *containerd.Client, container
 		} else {
 			return nil, fmt.Errorf("can't get container %s status", c.ID())
 		}
-		dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
-		if err != nil {
-			return nil, err
-		}
 		containerLabels, err := c.Labels(ctx)
 		if err != nil {
 			return nil, err
@@ -232,3 +285,12 @@ func getContainerSize(ctx context.Context, snapshotter snapshots.Snapshotter, sn
 
 	return fmt.Sprintf("%s (virtual %s)", progress.Bytes(containerSize).String(), progress.Bytes(imageSize).String()), nil
 }
+
+// portsDataStore returns the data store to load the port mappings from,
+// or an empty string for containerd on an SSH host, whose port mappings are not shown.
+func portsDataStore(gOptions types.GlobalCommandOptions) (string, error) {
+	if sshutil.IsAddress(gOptions.Address) {
+		return "", nil
+	}
+	return clientutil.DataStore(gOptions.DataRoot, gOptions.Address)
+}
@@ -19,27 +19,36 @@ package container
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/api/types/cri"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/composer/pipetagger"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/labels/k8slabels"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 )
 
-func Logs(ctx context.Context, client *containerd.Client, container string, options types.ContainerLogsOptions) error {
+// Logs prints the logs of the containers to options.Stdout and options.Stderr.
+// When more than one container is specified (or matched by options.Filters),
+// the logs are interleaved and each line is prefixed with the container name.
+func Logs(ctx context.Context, client *containerd.Client, containers []string, options types.ContainerLogsOptions) error {
 	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
 	if err != nil {
 		return err
@@ -50,9 +59,112 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 		log.G(ctx).Warn("Currently, `nerdctl logs` only supports containers created with `nerdctl run -d` or CRI")
 	}
 
-	stopChannel := make(chan os.Signal, 1)
+	targets, err := logsContainers(ctx, client, containers, options.Filters)
+	if err != nil {
+		return err
+	}
+
+	signalChannel := make(chan os.Signal, 1)
 	// catch OS signals:
-	signal.Notify(stopChannel, syscall.SIGTERM, syscall.SIGINT)
+	signal.Notify(signalChannel, syscall.SIGTERM, syscall.SIGINT)
+	defer close(signalChannel)
+	defer signal.Stop(signalChannel)
+
+	if len(targets) == 1 {
+		return containerLogs(ctx, targets[0], dataStore, options, options.Stdout, options.Stderr, signalChannel)
+	}
+
+	type logsTarget struct {
+		container   containerd.Container
+		name        string
+		stopChannel chan os.Signal
+	}
+	var logTagMaxLen int
+	logsTargets := make([]logsTarget, len(targets))
+	for i, c := range targets {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		name := l[labels.Name]
+		if name == "" {
+			name = c.ID()
+			if len(name) > 12 {
+				name = name[:12]
+			}
+		}
+		if len(name) > logTagMaxLen {
+			logTagMaxLen = len(name)
+		}
+		logsTargets[i] = logsTarget{container: c, name: name, stopChannel: make(chan os.Signal, 1)}
+	}
+
+	go func() {
+		sig, ok := <-signalChannel
+		if !ok {
+			return
+		}
+		for _, t := range logsTargets {
+			select {
+			case t.stopChannel <- sig:
+			default:
+			}
+		}
+	}()
+
+	logWidth := logTagMaxLen + 1
+	if options.NoLogPrefix {
+		logWidth = -1
+	}
+	stdout := &lockedWriter{w: options.Stdout}
+	stderr := &lockedWriter{w: options.Stderr}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, t := range logsTargets {
+		stdoutR, stdoutW := io.Pipe()
+		stderrR, stderrW := io.Pipe()
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			err := containerLogs(ctx, t.container, dataStore, options, stdoutW, stderrW, t.stopChannel)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to show the logs of container %s: %w", t.name, err))
+				mu.Unlock()
+			}
+			stdoutW.CloseWithError(err)
+			stderrW.CloseWithError(err)
+		}()
+		go func() {
+			defer wg.Done()
+			pipetagger.New(stdout, stdoutR, t.name, t.name, logWidth, options.NoColor).Run()
+			stdoutR.Close()
+		}()
+		go func() {
+			defer wg.Done()
+			pipetagger.New(stderr, stderrR, t.name, t.name, logWidth, options.NoColor).Run()
+			stderrR.Close()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// logsContainers returns the containers specified by name or ID and the containers matching the filters,
+// without duplicates.
+func logsContainers(ctx context.Context, client *containerd.Client, reqs []string, filters []string) ([]containerd.Container, error) {
+	var containers []containerd.Container
+	seen := make(map[string]struct{})
+	add := func(c containerd.Container) {
+		if _, ok := seen[c.ID()]; ok {
+			return
+		}
+		seen[c.ID()] = struct{}{}
+		containers = append(containers, c)
+	}
 
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
@@ -60,119 +172,234 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			l, err := found.Container.Labels(ctx)
-			if err != nil {
+			add(found.Container)
+			return nil
+		},
+	}
+	for _, req := range reqs {
+		n, err := walker.Walk(ctx, req)
+		if err != nil {
+			return nil, err
+		} else if n == 0 {
+			return nil, fmt.Errorf("no such container %s", req)
+		}
+	}
+
+	if len(filters) > 0 {
+		filtered, _, err := filterContainers(ctx, client, filters, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(filtered) == 0 && len(containers) == 0 {
+			return nil, fmt.Errorf("no container matches the filters %v", filters)
+		}
+		for _, c := range filtered {
+			add(c)
+		}
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no container specified: %w", errdefs.ErrInvalidArgument)
+	}
+	return containers, nil
+}
+
+// containerLogs prints the logs of a single container to stdout and stderr.
+func containerLogs(ctx context.Context, container containerd.Container, dataStore string, options types.ContainerLogsOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	l, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+
+	logPath, err := getLogPath(ctx, container)
+	if err != nil {
+		return err
+	}
+
+	follow := options.Follow
+	if follow {
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			if !errdefs.IsNotFound(err) {
 				return err
 			}
-
-			logPath, err := getLogPath(ctx, found.Container)
+			follow = false
+		} else {
+			status, err := task.Status(ctx)
 			if err != nil {
 				return err
 			}
-
-			follow := options.Follow
-			if follow {
-				task, err := found.Container.Task(ctx, nil)
+			if status.Status != containerd.Running {
+				follow = false
+			} else {
+				waitCh, err := task.Wait(ctx)
 				if err != nil {
-					if !errdefs.IsNotFound(err) {
-						return err
-					}
-					follow = false
-				} else {
-					status, err := task.Status(ctx)
-					if err != nil {
-						return err
+					return fmt.Errorf("failed to get wait channel for task %#v: %w", task, err)
+				}
+
+				// Setup goroutine to send stop event if container task finishes:
+				go func() {
+					followTask(ctx, container, waitCh, options.FollowRetries)
+					// Wait for logger to process remaining logs after container exit
+					if err = logging.WaitForLogger(dataStore, l[labels.Namespace], container.ID()); err != nil {
+						log.G(ctx).WithError(err).Error("failed to wait for logger shutdown")
 					}
-					if status.Status != containerd.Running {
-						follow = false
-					} else {
-						waitCh, err := task.Wait(ctx)
-						if err != nil {
-							return fmt.Errorf("failed to get wait channel for task %#v: %w", task, err)
-						}
-
-						// Setup goroutine to send stop event if container task finishes:
-						go func() {
-							<-waitCh
-							// Wait for logger to process remaining logs after container exit
-							if err = logging.WaitForLogger(dataStore, l[labels.Namespace], found.Container.ID()); err != nil {
-								log.G(ctx).WithError(err).Error("failed to wait for logger shutdown")
-							}
-							log.G(ctx).Debugf("container task has finished, sending kill signal to log viewer")
-							stopChannel <- os.Interrupt
-						}()
+					log.G(ctx).Debugf("container task has finished, sending kill signal to log viewer")
+					select {
+					case stopChannel <- os.Interrupt:
+					default:
 					}
-				}
+				}()
 			}
+		}
+	}
 
-			var detailPrefix string
-			if options.Details {
-				if logConfigJSON, ok := l["nerdctl/log-config"]; ok {
-					type logConfig struct {
-						Opts map[string]string `json:"opts"`
-					}
+	var detailPrefix string
+	if options.Details {
+		if logConfigJSON, ok := l["nerdctl/log-config"]; ok {
+			type logConfig struct {
+				Opts map[string]string `json:"opts"`
+			}
+
+			e, err := getContainerEnvs(ctx, container)
+			if err != nil {
+				return err
+			}
 
-					e, err := getContainerEnvs(ctx, found.Container)
-					if err != nil {
-						return err
+			var logCfg logConfig
+			var optPairs []string
+
+			if err := json.Unmarshal([]byte(logConfigJSON), &logCfg); err == nil {
+				envOpts, labelOpts := getLogOpts(logCfg.Opts)
+
+				for _, v := range envOpts {
+					if env, ok := e[v]; ok {
+						optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, env))
 					}
+				}
 
-					var logCfg logConfig
-					var optPairs []string
-
-					if err := json.Unmarshal([]byte(logConfigJSON), &logCfg); err == nil {
-						envOpts, labelOpts := getLogOpts(logCfg.Opts)
-
-						for _, v := range envOpts {
-							if env, ok := e[v]; ok {
-								optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, env))
-							}
-						}
-
-						for _, v := range labelOpts {
-							if label, ok := l[v]; ok {
-								optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, label))
-							}
-						}
-
-						if len(optPairs) > 0 {
-							sort.Strings(optPairs)
-							detailPrefix = strings.Join(optPairs, ",")
-						}
-					} else {
-						log.L.Warn("failed to parse `--details` option, detailed information might not be displayed")
+				for _, v := range labelOpts {
+					if label, ok := l[v]; ok {
+						optPairs = append(optPairs, fmt.Sprintf("%s=%s", v, label))
 					}
 				}
-			}
 
-			logViewOpts := logging.LogViewOptions{
-				ContainerID:       found.Container.ID(),
-				Namespace:         l[labels.Namespace],
-				DatastoreRootPath: dataStore,
-				LogPath:           logPath,
-				Follow:            follow,
-				Timestamps:        options.Timestamps,
-				Tail:              options.Tail,
-				Since:             options.Since,
-				Until:             options.Until,
-				Details:           options.Details,
-				DetailPrefix:      &detailPrefix,
-			}
-			logViewer, err := logging.InitContainerLogViewer(l, logViewOpts, stopChannel, options.GOptions.Experimental)
-			if err != nil {
-				return err
+				if len(optPairs) > 0 {
+					sort.Strings(optPairs)
+					detailPrefix = strings.Join(optPairs, ",")
+				}
+			} else {
+				log.L.Warn("failed to parse `--details` option, detailed information might not be displayed")
 			}
+		}
+	}
 
-			return logViewer.PrintLogsTo(options.Stdout, options.Stderr)
-		},
+	logViewOpts := logging.LogViewOptions{
+		ContainerID:       container.ID(),
+		Namespace:         l[labels.Namespace],
+		DatastoreRootPath: dataStore,
+		LogPath:           logPath,
+		Follow:            follow,
+		Timestamps:        options.Timestamps,
+		Tail:              options.Tail,
+		Since:             options.Since,
+		Until:             options.Until,
+		Details:           options.Details,
+		DetailPrefix:      &detailPrefix,
 	}
-	n, err := walker.Walk(ctx, container)
+	logViewer, err := logging.InitContainerLogViewer(l, logViewOpts, stopChannel, options.GOptions.Experimental)
 	if err != nil {
 		return err
-	} else if n == 0 {
-		return fmt.Errorf("no such container %s", container)
 	}
-	return nil
+
+	return logViewer.PrintLogsTo(stdout, stderr)
+}
+
+// followTask returns when the container task has exited, and is not going to be restarted by its restart policy.
+// Up to retries times (or indefinitely if negative), the new task of the restarted container, or the task
+// after a restart of containerd, is waited for instead of returning.
+func followTask(ctx context.Context, container containerd.Container, waitCh <-chan containerd.ExitStatus, retries int) {
+	for retries != 0 {
+		exitStatus := <-waitCh
+		if err := exitStatus.Error(); err != nil {
+			log.G(ctx).WithError(err).Debug("lost the container task, re-attaching")
+		} else {
+			log.G(ctx).Debugf("container task has exited with status %d, waiting for a restart", exitStatus.ExitCode())
+		}
+		var err error
+		waitCh, err = waitRestartedTask(ctx, container)
+		if err != nil {
+			log.G(ctx).WithError(err).Debug("stopped following the container task")
+			return
+		}
+		if retries > 0 {
+			retries--
+		}
+	}
+	<-waitCh
+}
+
+// waitRestartedTask polls the task of the container until it is running again,
+// and returns the wait channel of the running task.
+// It returns an error if the container is not going to be restarted.
+func waitRestartedTask(ctx context.Context, container containerd.Container) (<-chan containerd.ExitStatus, error) {
+	const pollInterval = time.Second
+	for {
+		waitCh, err := runningTaskWait(ctx, container)
+		if waitCh != nil || err != nil {
+			return waitCh, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// runningTaskWait returns the wait channel of the task if it is running,
+// nil if the task may still be (re)started, or an error if it is not going to be.
+func runningTaskWait(ctx context.Context, container containerd.Container) (<-chan containerd.ExitStatus, error) {
+	var status containerd.Status
+	task, err := container.Task(ctx, nil)
+	if err == nil {
+		status, err = task.Status(ctx)
+		if err == nil && status.Status == containerd.Running {
+			return task.Wait(ctx)
+		}
+	}
+	if err != nil && !errdefs.IsNotFound(err) {
+		// containerd may be restarting
+		log.G(ctx).WithError(err).Debug("failed to get the container task, retrying")
+		return nil, nil
+	}
+
+	l, err := container.Labels(ctx)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if l[restart.StatusLabel] != string(containerd.Running) {
+		return nil, errors.New("the container is not going to be restarted")
+	}
+	if task != nil && status.Status == containerd.Stopped && !restart.Reconcile(status, l) {
+		return nil, errors.New("the restart policy of the container does not restart it")
+	}
+	return nil, nil
+}
+
+// lockedWriter serializes the writes of the log viewers of multiple containers.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
 }
 
 func getLogPath(ctx context.Context, container containerd.Container) (string, error) {
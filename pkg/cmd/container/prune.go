@@ -21,15 +21,22 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/prune"
 )
 
 // Prune remove all stopped containers
 func Prune(ctx context.Context, client *containerd.Client, options types.ContainerPruneOptions) error {
+	filters, err := prune.ParseFilters(options.Filters, time.Now())
+	if err != nil {
+		return err
+	}
+
 	containers, err := client.Containers(ctx)
 	if err != nil {
 		return err
@@ -37,6 +44,20 @@ func Prune(ctx context.Context, client *containerd.Client, options types.Contain
 
 	var deleted []string
 	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to inspect container %s", c.ID())
+			continue
+		}
+		if !filters.MatchCreated(info.CreatedAt) || !filters.MatchLabels(info.Labels) {
+			continue
+		}
+		if options.DryRun {
+			if isStopped(ctx, c) {
+				deleted = append(deleted, c.ID())
+			}
+			continue
+		}
 		if err = RemoveContainer(ctx, c, options.GOptions, false, true, client); err == nil {
 			deleted = append(deleted, c.ID())
 			continue
@@ -48,9 +69,26 @@ func Prune(ctx context.Context, client *containerd.Client, options types.Contain
 	}
 
 	if len(deleted) > 0 {
-		fmt.Fprintln(options.Stdout, "Deleted Containers:")
+		if options.DryRun {
+			fmt.Fprintln(options.Stdout, "Would Delete Containers:")
+		} else {
+			fmt.Fprintln(options.Stdout, "Deleted Containers:")
+		}
 		fmt.Fprintln(options.Stdout, strings.Join(deleted, "\n"))
 	}
 
 	return nil
 }
+
+// isStopped returns whether the container would be removed by RemoveContainer without force.
+func isStopped(ctx context.Context, c containerd.Container) bool {
+	task, err := c.Task(ctx, nil)
+	if err != nil {
+		return true
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return true
+	}
+	return status.Status != containerd.Running && status.Status != containerd.Paused
+}
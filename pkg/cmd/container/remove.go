@@ -32,15 +32,20 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 )
 
@@ -139,6 +144,12 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 		// Release the lock
 		retErr = errors.Join(lf.Release(), retErr)
 		// Note: technically, this is racy...
+		if retErr == nil {
+			retErr = secretutil.Remove(containerLabels[labels.StateDir])
+		}
+		if retErr == nil {
+			retErr = mountutil.RemoveVolumeSubpaths(containerLabels[labels.StateDir])
+		}
 		if retErr == nil {
 			retErr = os.RemoveAll(containerLabels[labels.StateDir])
 		}
@@ -256,6 +267,24 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			log.G(ctx).WithError(err).Warnf("failed to remove hosts file for container %q", id)
 		}
 
+		// Publish the unmount events of the volumes - soft failure
+		if mountsJSON, ok := containerLabels[labels.Mounts]; ok {
+			var mounts []dockercompat.MountPoint
+			if err = json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to unmarshall mount information for container %q", id)
+			} else {
+				for _, m := range mounts {
+					if m.Type == "volume" {
+						eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeUnmountTopic, &eventutil.VolumeEvent{
+							Name:        m.Name,
+							Driver:      m.Driver,
+							ContainerID: id,
+						})
+					}
+				}
+			}
+		}
+
 		// Volume removal is not handled by the poststop hook lifecycle because it depends on removeAnonVolumes option
 		// Note that the anonymous volume list has been obtained earlier, without locking the volume store.
 		// Technically, a concurrent operation MAY have deleted these anonymous volumes already at this point, which
@@ -266,13 +295,23 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			if err = json.Unmarshal([]byte(anonVolumesJSON), &anonVolumes); err != nil {
 				log.G(ctx).WithError(err).Warnf("failed to unmarshall anonvolume information for container %q", id)
 			} else {
-				var errs []error
-				_, errs, err = volStore.Remove(func() ([]string, []error, error) {
+				anonVolumes = unusedVolumes(ctx, client, anonVolumes)
+				var (
+					removed []string
+					errs    []error
+				)
+				removed, errs, err = volStore.Remove(func() ([]string, []error, error) {
 					return anonVolumes, nil, nil
 				})
 				if err != nil || len(errs) > 0 {
 					log.G(ctx).WithError(err).Warnf("failed to remove anonymous volumes %v", anonVolumes)
 				}
+				for _, name := range removed {
+					eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeDestroyTopic, &eventutil.VolumeEvent{
+						Name:        name,
+						ContainerID: id,
+					})
+				}
 			}
 		}
 	}()
@@ -301,6 +340,10 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 		if !force {
 			return NewStatusError(id, status.Status)
 		}
+		// Prevent the restart policy from restarting the task being removed (e.g., by `nerdctl system supervisor`). Soft error.
+		if err = containerutil.UpdateExplicitlyStoppedLabel(ctx, c, true); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to update the labels of container %v", id)
+		}
 		// Kill the task. Soft error.
 		if err = task.Kill(ctx, syscall.SIGKILL); err != nil && !errdefs.IsNotFound(err) {
 			log.G(ctx).WithError(err).Warnf("failed to send SIGKILL to task %v", id)
@@ -332,3 +375,28 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 	_, err = task.Delete(ctx, containerd.WithProcessKill)
 	return err
 }
+
+// unusedVolumes filters out the anonymous volumes that are still referenced by other containers,
+// e.g., the ones shared with `--volumes-from`.
+// The container being removed must already be deleted at this point.
+func unusedVolumes(ctx context.Context, client *containerd.Client, anonVolumes []string) []string {
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to list containers, not removing anonymous volumes %v", anonVolumes)
+		return nil
+	}
+	used, err := volume.UsedVolumes(ctx, containers)
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to find the volumes in use, not removing anonymous volumes %v", anonVolumes)
+		return nil
+	}
+	var res []string
+	for _, name := range anonVolumes {
+		if used[name] > 0 {
+			log.G(ctx).Debugf("anonymous volume %q is still in use, not removing it", name)
+			continue
+		}
+		res = append(res, name)
+	}
+	return res
+}
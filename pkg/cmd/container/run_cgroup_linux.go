@@ -67,6 +67,10 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 		return []oci.SpecOpts{oci.WithCgroup("")}, nil
 	}
 
+	if rootlessutil.IsRootlessChild() && infoutil.CgroupsVersion() == "2" {
+		warnUndelegatedCgroupControllers(options)
+	}
+
 	var opts []oci.SpecOpts // nolint: prealloc
 	path, err := generateCgroupPath(id, options.GOptions.CgroupManager, options.CgroupParent)
 	if err != nil {
@@ -227,6 +231,31 @@ func generateCgroupOpts(id string, options types.ContainerCreateOptions, interna
 	return opts, nil
 }
 
+// warnUndelegatedCgroupControllers warns about the requested resource limits that cannot be applied
+// because systemd does not delegate the corresponding cgroup controllers to the user.
+func warnUndelegatedCgroupControllers(options types.ContainerCreateOptions) {
+	requested := map[string]bool{
+		"cpu":    options.CPUs > 0.0 || options.CPUShares != 0 || options.CPUQuota != -1 || options.CPUPeriod != 0,
+		"cpuset": options.CPUSetCPUs != "" || options.CPUSetMems != "",
+		"io": options.BlkioWeight != 0 || len(options.BlkioWeightDevice) > 0 ||
+			len(options.BlkioDeviceReadBps) > 0 || len(options.BlkioDeviceWriteBps) > 0 ||
+			len(options.BlkioDeviceReadIOps) > 0 || len(options.BlkioDeviceWriteIOps) > 0,
+		"memory": options.Memory != "" || options.MemoryReservation != "" || options.MemorySwap != "",
+		"pids":   options.PidsLimit > 0,
+	}
+	missing, err := rootlessutil.MissingCgroupControllers(rootlessutil.ParentEUID())
+	if err != nil {
+		log.L.WithError(err).Debug("failed to check the delegated cgroup controllers")
+		return
+	}
+	for _, c := range missing {
+		if requested[c] {
+			log.L.Warnf("the %q cgroup controller is not delegated to the user, the resource limits using it may not be applied "+
+				"(Hint: run `nerdctl system check-rootless`)", c)
+		}
+	}
+}
+
 func generateCgroupPath(id, cgroupManager, cgroupParent string) (string, error) {
 	var (
 		path         string
@@ -18,15 +18,23 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/moby/sys/userns"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-spec/specs-go/features"
+	"golang.org/x/sys/unix"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/contrib/nvidia"
 	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/containerd/v2/pkg/kernelversion"
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/log"
 
@@ -35,6 +43,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -66,17 +75,23 @@ func setPlatformOptions(ctx context.Context, client *containerd.Client, id, uts
 
 	capOpts, err := generateCapOpts(
 		strutil.DedupeStrSlice(options.CapAdd),
-		strutil.DedupeStrSlice(options.CapDrop))
+		strutil.DedupeStrSlice(options.CapDrop),
+		options.GOptions.DefaultCapabilities)
 	if err != nil {
 		return nil, err
 	}
 	opts = append(opts, capOpts...)
 	securityOptsMaps := strutil.ConvertKVStringsToMap(strutil.DedupeStrSlice(options.SecurityOpt))
-	secOpts, err := generateSecurityOpts(options.Privileged, securityOptsMaps)
+	secOpts, err := generateSecurityOpts(options.Privileged, securityOptsMaps, options.GOptions.SeccompProfile)
 	if err != nil {
 		return nil, err
 	}
 	opts = append(opts, secOpts...)
+	maskedPathsOpts, err := generateMaskedPathsOpts(strutil.DedupeStrSlice(options.SecurityOpt))
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, maskedPathsOpts...)
 
 	b4nnOpts, err := bypass4netnsutil.GenerateBypass4netnsOpts(securityOptsMaps, annotations, id)
 	if err != nil {
@@ -115,6 +130,12 @@ func setPlatformOptions(ctx context.Context, client *containerd.Client, id, uts
 	}
 	opts = append(opts, nsOpts...)
 
+	selinuxOpts, err := generateSELinuxOpts(ctx, client, options.Privileged, options.SecurityOpt, internalLabels)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, selinuxOpts...)
+
 	opts, err = setOOMScoreAdj(opts, options.OomScoreAdjChanged, options.OomScoreAdj)
 	if err != nil {
 		return nil, err
@@ -319,3 +340,180 @@ func parseGPUOpt(value string) (oci.SpecOpts, error) {
 
 	return nvidia.WithGPUs(gpuOpts...), nil
 }
+
+// withChownMounts returns a SpecOpts that recursively chowns the sources of the mounts with the chown option
+// to the user of the container, mapped to the host with the user namespace of the container.
+// The SpecOpts must be applied after the user of the container is set.
+func withChownMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(ctx context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		var uid, gid uint32
+		if s.Process != nil {
+			uid, gid = s.Process.User.UID, s.Process.User.GID
+		}
+		if s.Linux != nil {
+			var err error
+			if uid, err = hostID(s.Linux.UIDMappings, uid); err != nil {
+				return fmt.Errorf("uid %d: %w", uid, err)
+			}
+			if gid, err = hostID(s.Linux.GIDMappings, gid); err != nil {
+				return fmt.Errorf("gid %d: %w", gid, err)
+			}
+		}
+		for _, x := range mountPoints {
+			if !x.Chown {
+				continue
+			}
+			if x.Mount.Type != "bind" && x.Mount.Type != mountutil.DefaultMountType {
+				return fmt.Errorf("cannot chown the %s filesystem mounted on %q", x.Mount.Type, x.Mount.Destination)
+			}
+			if err := chownRecursive(x.Mount.Source, int(uid), int(gid)); err != nil {
+				return fmt.Errorf("failed to chown the mount %q: %w", x.Mount.Destination, err)
+			}
+		}
+		return nil
+	}
+}
+
+// withIDMappedMounts returns a SpecOpts that idmaps the bind mounts and the volumes with the idmap option
+// with the user namespace of the container, so that the files owned by the host IDs appear with the same IDs in the container.
+// The kernel, the runtime, and the filesystems of the sources are checked here, as the runtime would fail on start otherwise.
+// The SpecOpts must be applied after the user namespace, the mounts, and the runtime of the container are set.
+func withIDMappedMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(ctx context.Context, client oci.Client, c *containers.Container, s *oci.Spec) error {
+		var idmapped []*mountutil.Processed
+		for _, x := range mountPoints {
+			if x.IDMap {
+				idmapped = append(idmapped, x)
+			}
+		}
+		if len(idmapped) == 0 {
+			return nil
+		}
+		if s.Linux == nil || len(s.Linux.UIDMappings) == 0 || len(s.Linux.GIDMappings) == 0 {
+			return errors.New("the idmap option of mounts requires a user namespace (--userns-remap or --userns)")
+		}
+		// idmapped mounts (mount_setattr(2) with MOUNT_ATTR_IDMAP) are supported since Linux 5.12
+		if ok, err := kernelversion.GreaterEqualThan(kernelversion.KernelVersion{Kernel: 5, Major: 12}); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("the idmap option of mounts requires Linux 5.12 or later")
+		}
+		if err := checkRuntimeIDMapSupport(ctx, client, c); err != nil {
+			return err
+		}
+		usernsFD, err := mount.GetUsernsFD(formatIDMappings(s.Linux.UIDMappings), formatIDMappings(s.Linux.GIDMappings))
+		if err != nil {
+			return fmt.Errorf("failed to create the user namespace to check the idmapped mounts: %w", err)
+		}
+		defer usernsFD.Close()
+		for _, x := range idmapped {
+			for i := range s.Mounts {
+				m := &s.Mounts[i]
+				if m.Destination != x.Mount.Destination || m.Type != "bind" {
+					continue
+				}
+				// the source of a volume of a plugin is mounted on start
+				if x.Driver == "" {
+					if err := checkIDMapMount(m.Source, usernsFD); err != nil {
+						return fmt.Errorf("cannot idmap the mount %q: %w", m.Destination, err)
+					}
+				}
+				m.UIDMappings = append([]specs.LinuxIDMapping(nil), s.Linux.UIDMappings...)
+				m.GIDMappings = append([]specs.LinuxIDMapping(nil), s.Linux.GIDMappings...)
+			}
+		}
+		return nil
+	}
+}
+
+// runtimeInfoClient is implemented by *containerd.Client.
+type runtimeInfoClient interface {
+	RuntimeInfo(ctx context.Context, runtimePath string, runtimeOptions interface{}) (*containerd.RuntimeInfo, error)
+}
+
+// checkRuntimeIDMapSupport returns an error when the runtime of the container reports that it does not support idmapped mounts,
+// as runc older than 1.2 ignores the idmappings of the mounts.
+func checkRuntimeIDMapSupport(ctx context.Context, client oci.Client, c *containers.Container) error {
+	rc, ok := client.(runtimeInfoClient)
+	if !ok || c.Runtime.Name == "" {
+		return nil
+	}
+	var runtimeOptions interface{}
+	if c.Runtime.Options != nil {
+		runtimeOptions = c.Runtime.Options
+	}
+	info, err := rc.RuntimeInfo(ctx, c.Runtime.Name, runtimeOptions)
+	if err != nil {
+		// containerd older than 2.0 cannot introspect the runtimes
+		log.G(ctx).WithError(err).Debug("failed to get the features of the runtime, the support of idmapped mounts is not checked")
+		return nil
+	}
+	f, ok := info.Features.(*features.Features)
+	if !ok {
+		// the runtimes that are not compatible with runc do not report the features
+		return nil
+	}
+	if f.Linux == nil || f.Linux.MountExtensions == nil || f.Linux.MountExtensions.IDMap == nil ||
+		f.Linux.MountExtensions.IDMap.Enabled == nil || !*f.Linux.MountExtensions.IDMap.Enabled {
+		return fmt.Errorf("the runtime %q does not support idmapped mounts (requires runc >= 1.2 or crun >= 1.9)", c.Runtime.Name)
+	}
+	return nil
+}
+
+// checkIDMapMount idmaps a detached clone of the source with the user namespace,
+// to check that the filesystem of the source supports idmapped mounts.
+func checkIDMapMount(source string, usernsFD *os.File) error {
+	fd, err := unix.OpenTree(-int(unix.EBADF), source, unix.OPEN_TREE_CLONE|unix.OPEN_TREE_CLOEXEC)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	attr := unix.MountAttr{
+		Attr_set:  unix.MOUNT_ATTR_IDMAP,
+		Userns_fd: uint64(usernsFD.Fd()),
+	}
+	if err := unix.MountSetattr(fd, "", unix.AT_EMPTY_PATH, &attr); err != nil {
+		return fmt.Errorf("the filesystem of the source may not support idmapped mounts: %w", err)
+	}
+	return nil
+}
+
+// formatIDMappings formats the mappings in the format of mount.GetUsernsFD, i.e., "container-id:host-id:size[,...]".
+func formatIDMappings(mappings []specs.LinuxIDMapping) string {
+	s := make([]string, len(mappings))
+	for i, m := range mappings {
+		s[i] = fmt.Sprintf("%d:%d:%d", m.ContainerID, m.HostID, m.Size)
+	}
+	return strings.Join(s, ",")
+}
+
+// hostID maps the id in the user namespace to the host.
+func hostID(mappings []specs.LinuxIDMapping, id uint32) (uint32, error) {
+	if len(mappings) == 0 {
+		return id, nil
+	}
+	for _, m := range mappings {
+		if id >= m.ContainerID && id-m.ContainerID < m.Size {
+			return m.HostID + id - m.ContainerID, nil
+		}
+	}
+	return 0, errors.New("not mapped in the user namespace of the container")
+}
+
+// chownRecursive chowns the directory and its contents, unless the directory is already owned by uid:gid,
+// i.e., it has been chowned on a previous use.
+func chownRecursive(dir string, uid, gid int) error {
+	var st unix.Stat_t
+	if err := unix.Lstat(dir, &st); err != nil {
+		return err
+	}
+	if int(st.Uid) == uid && int(st.Gid) == gid {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, uid, gid)
+	})
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/kernelversion"
+
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+func TestWithIDMappedMounts(t *testing.T) {
+	if ok, _ := kernelversion.GreaterEqualThan(kernelversion.KernelVersion{Kernel: 5, Major: 12}); !ok {
+		t.Skip("requires Linux 5.12 or later")
+	}
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	idMappings := []specs.LinuxIDMapping{{ContainerID: 0, HostID: 100000, Size: 65536}}
+	s := &specs.Spec{
+		Linux: &specs.Linux{UIDMappings: idMappings, GIDMappings: idMappings},
+		Mounts: []specs.Mount{
+			{Type: "bind", Source: t.TempDir(), Destination: "/data", Options: []string{"rbind"}},
+			{Type: "bind", Source: t.TempDir(), Destination: "/other", Options: []string{"rbind"}},
+			{Type: "tmpfs", Source: "tmpfs", Destination: "/tmp"},
+		},
+	}
+	mountPoints := []*mountutil.Processed{
+		{Type: mountutil.Bind, Mount: s.Mounts[0], IDMap: true},
+		{Type: mountutil.Bind, Mount: s.Mounts[1]},
+		{Type: mountutil.Tmpfs, Mount: s.Mounts[2]},
+	}
+	err := withIDMappedMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s)
+	if err != nil && strings.Contains(err.Error(), "may not support idmapped mounts") {
+		t.Skipf("the filesystem of the temporary directory does not support idmapped mounts: %v", err)
+	}
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s.Mounts[0].UIDMappings, idMappings)
+	assert.DeepEqual(t, s.Mounts[0].GIDMappings, idMappings)
+	// the mounts are idmapped only with the idmap option
+	assert.Assert(t, s.Mounts[1].UIDMappings == nil)
+	assert.Assert(t, s.Mounts[2].UIDMappings == nil)
+
+	// the idmap option requires the user namespace
+	s = &specs.Spec{Linux: &specs.Linux{}, Mounts: []specs.Mount{{Type: "bind", Source: t.TempDir(), Destination: "/data"}}}
+	mountPoints = []*mountutil.Processed{{Type: mountutil.Bind, Mount: s.Mounts[0], IDMap: true}}
+	err = withIDMappedMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s)
+	assert.ErrorContains(t, err, "requires a user namespace")
+}
+
+func TestReadOnlyTmpfsMounts(t *testing.T) {
+	userTmp, err := mountutil.ProcessFlagTmpfs("/tmp/:size=64m")
+	assert.NilError(t, err)
+	res, err := readOnlyTmpfsMounts([]*mountutil.Processed{userTmp})
+	assert.NilError(t, err)
+	assert.Equal(t, len(res), 2)
+	assert.Equal(t, res[0].Mount.Destination, "/run")
+	assert.DeepEqual(t, res[0].Mount.Options, []string{"nosuid", "nodev", "exec", "mode=755"})
+	assert.Equal(t, res[1].Mount.Destination, "/var/tmp")
+	assert.DeepEqual(t, res[1].Mount.Options, []string{"nosuid", "nodev", "exec", "mode=1777"})
+}
@@ -24,7 +24,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +45,7 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
@@ -50,6 +53,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/volumeplugin"
 )
 
 // copy from https://github.com/containerd/containerd/blob/v1.6.0-rc.1/pkg/cri/opts/spec_linux.go#L129-L151
@@ -116,22 +120,57 @@ func parseMountFlags(volStore volumestore.VolumeStore, options types.ContainerCr
 		if err != nil {
 			return nil, err
 		}
+		if slices.Contains(mountutil.PrivilegedMountTypes, x.Type) && !options.Privileged {
+			return nil, fmt.Errorf("mount type %q requires --privileged, as the masked paths and the read-only paths do not apply to it", x.Type)
+		}
 		parsed = append(parsed, x)
 	}
 
+	if options.ReadOnly && options.ReadOnlyTmpfs && runtime.GOOS == "linux" {
+		readOnlyTmpfs, err := readOnlyTmpfsMounts(parsed)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, readOnlyTmpfs...)
+	}
+
 	return parsed, nil
 }
 
+// readOnlyTmpfsDirs are the writable directories of `--read-only --read-only-tmpfs` containers, like Podman.
+var readOnlyTmpfsDirs = []string{"/run:mode=755", "/tmp:mode=1777", "/var/tmp:mode=1777"}
+
+// readOnlyTmpfsMounts returns the tmpfs mounts of readOnlyTmpfsDirs, except the directories already mounted by the user.
+// Unlike the default options of --tmpfs, the tmpfs mounts allow executables for compatibility with most images.
+func readOnlyTmpfsMounts(parsed []*mountutil.Processed) ([]*mountutil.Processed, error) {
+	var res []*mountutil.Processed
+	for _, dir := range readOnlyTmpfsDirs {
+		dst, mode, _ := strings.Cut(dir, ":")
+		if slices.ContainsFunc(parsed, func(x *mountutil.Processed) bool {
+			return filepath.Clean(x.Mount.Destination) == dst
+		}) {
+			continue
+		}
+		x, err := mountutil.ProcessFlagTmpfs(dst + ":exec," + mode)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, x)
+	}
+	return res, nil
+}
+
 // generateMountOpts generates volume-related mount opts.
 // Other mounts such as procfs mount are not handled here.
 func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredImage *imgutil.EnsuredImage,
-	volStore volumestore.VolumeStore, options types.ContainerCreateOptions) ([]oci.SpecOpts, []string, []*mountutil.Processed, error) {
+	volStore volumestore.VolumeStore, id, stateDir string, options types.ContainerCreateOptions) ([]oci.SpecOpts, []string, []*mountutil.Processed, error) {
 	//nolint:prealloc
 	var (
 		opts        []oci.SpecOpts
 		anonVolumes []string
 		userMounts  []specs.Mount
 		mountPoints []*mountutil.Processed
+		subpaths    []mountutil.VolumeSubpath
 	)
 	mounted := make(map[string]struct{})
 	var imageVolumes map[string]struct{}
@@ -230,6 +269,29 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 		ociMounts := make([]specs.Mount, len(parsed))
 		for i, x := range parsed {
 			ociMounts[i] = x.Mount
+			if x.Subpath != "" || x.Driver != "" {
+				// the subpath, and the volume of a volume plugin, are bind-mounted on a private mount point
+				// when the container is started (see mountutil.VolumeSubpath)
+				if x.Driver != "" && runtime.GOOS != "linux" {
+					return nil, nil, nil, fmt.Errorf("volume %q: volume plugins are only supported on Linux", x.Name)
+				}
+				dir, err := mountutil.VolumeSubpathsDir(options.GOptions.Namespace, id)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				subpath := mountutil.VolumeSubpath{Subpath: x.Subpath, Target: filepath.Join(dir, strconv.Itoa(len(subpaths)))}
+				if x.Driver != "" {
+					subpath.Driver, subpath.Name, subpath.ID = x.Driver, x.Name, id
+					x.Mount.Source = subpath.Target
+				} else {
+					subpath.Root = x.Mount.Source
+					if x.Mount.Source, err = mountutil.ResolveVolumeSubpath(subpath.Root, x.Subpath); err != nil {
+						return nil, nil, nil, err
+					}
+				}
+				ociMounts[i].Source = subpath.Target
+				subpaths = append(subpaths, subpath)
+			}
 			mounted[filepath.Clean(x.Mount.Destination)] = struct{}{}
 
 			target, err := securejoin.SecureJoin(tempDir, x.Mount.Destination)
@@ -237,9 +299,16 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 				return nil, nil, nil, err
 			}
 
-			// Copying content in AnonymousVolume and namedVolume
-			if x.Type == "volume" {
-				if err := copyExistingContents(target, x.Mount.Source); err != nil {
+			// Copying content in AnonymousVolume and namedVolume, unless disabled with nocopy
+			// (not in the filesystems mounted for volumes created with the type option, e.g., NFS,
+			// nor in the subpath of a volume, which may be shared with other containers)
+			if x.Type == "volume" && !x.NoCopy && x.Subpath == "" && (x.Mount.Type == "bind" || x.Mount.Type == mountutil.DefaultMountType) {
+				if x.Driver != "" {
+					err = copyToPluginVolume(ctx, x, id, target)
+				} else {
+					err = copyExistingContents(target, x.Mount.Source)
+				}
+				if err != nil {
 					return nil, nil, nil, err
 				}
 			}
@@ -252,6 +321,12 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 
 		// add parsed user specified bind-mounts/volume/tmpfs to mountPoints
 		mountPoints = append(mountPoints, parsed...)
+
+		if len(subpaths) > 0 {
+			if err := mountutil.WriteVolumeSubpaths(stateDir, subpaths); err != nil {
+				return nil, nil, nil, err
+			}
+		}
 	}
 
 	// imageVolumes are defined in Dockerfile "VOLUME" instruction
@@ -268,7 +343,7 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 
 		log.G(ctx).Debugf("creating anonymous volume %q, for \"VOLUME %s\"",
 			anonVolName, imgVolRaw)
-		anonVol, err := volStore.CreateWithoutLock(anonVolName, []string{})
+		anonVol, err := volStore.CreateWithoutLock(anonVolName, []string{labels.AnonymousVolumes + "="})
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -356,6 +431,48 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 	return opts, anonVolumes, mountPoints, nil
 }
 
+// copyToPluginVolume copies the contents of target into the volume of a volume plugin,
+// which is only mounted by the plugin during the copy.
+func copyToPluginVolume(ctx context.Context, x *mountutil.Processed, id, target string) error {
+	p, err := volumeplugin.Lookup(ctx, x.Driver)
+	if err != nil {
+		return fmt.Errorf("failed to mount volume %q: %w", x.Name, err)
+	}
+	src, err := p.Mount(ctx, x.Name, id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := p.Unmount(ctx, x.Name, id); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to unmount volume %q", x.Name)
+		}
+	}()
+	return copyExistingContents(target, src)
+}
+
+// publishVolumeEvents publishes the create events of the anonymous volumes created for the container,
+// and the mount events of the volumes of the container.
+func publishVolumeEvents(ctx context.Context, client *containerd.Client, mountPoints []*mountutil.Processed, id string) {
+	for _, x := range mountPoints {
+		if x.Type != mountutil.Volume {
+			continue
+		}
+		name := x.Name
+		if x.AnonymousVolume != "" {
+			name = x.AnonymousVolume
+			eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeCreateTopic, &eventutil.VolumeEvent{
+				Name:        name,
+				ContainerID: id,
+			})
+		}
+		eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeMountTopic, &eventutil.VolumeEvent{
+			Name:        name,
+			Driver:      x.Driver,
+			ContainerID: id,
+		})
+	}
+}
+
 // copyExistingContents copies from the source to the destination and
 // ensures the ownership is appropriately set.
 func copyExistingContents(source, destination string) error {
@@ -0,0 +1,122 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/containerd/nerdctl/v2/pkg/annotations"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	// rootlessPortDriverRootlessKit uses the port driver of RootlessKit, whichever it is.
+	rootlessPortDriverRootlessKit = "rootlesskit"
+	// rootlessPortDriverBypass4netns exposes the ports with bypass4netns, as `--annotation nerdctl/bypass4netns=true`.
+	rootlessPortDriverBypass4netns = "bypass4netns"
+	// rootlessPortDriverPasta exposes the ports with the pasta process of `--network=pasta`.
+	rootlessPortDriverPasta = "pasta"
+)
+
+// withBypass4netnsAnnotation returns the annotations of the container with the annotation of `--bypass4netns`.
+func withBypass4netnsAnnotation(enabled bool, annots []string) ([]string, error) {
+	if !rootlessutil.IsRootless() {
+		if enabled {
+			return nil, errors.New("--bypass4netns is only supported in rootless mode")
+		}
+		return annots, nil
+	}
+	if b4nn, ok := strutil.ConvertKVStringsToMap(annots)[annotations.Bypass4netns]; ok {
+		if specified, err := strconv.ParseBool(b4nn); err != nil || specified != enabled {
+			return nil, fmt.Errorf("--bypass4netns=%t conflicts with annotation %s=%s", enabled, annotations.Bypass4netns, b4nn)
+		}
+		return annots, nil
+	}
+	return append(annots, annotations.Bypass4netns+"="+strconv.FormatBool(enabled)), nil
+}
+
+// withRootlessPortDriverAnnotations returns the annotations of the container with the port driver of `--rootless-port-driver`.
+//
+// RootlessKit runs a single port driver for all the containers, so the named port drivers
+// (builtin, slirp4netns, implicit) are only accepted when RootlessKit is running with that driver,
+// so that a container requiring e.g. the source IP propagation of slirp4netns never runs with another driver.
+// The OCI hook checks the driver again on start, as RootlessKit may be restarted with another driver.
+// The bypass4netns driver can be selected per container, as it does not depend on RootlessKit,
+// and the pasta driver is the pasta process of `--network=pasta`, which forwards the ports by itself.
+func withRootlessPortDriverAnnotations(ctx context.Context, driver string, networks []string, annots []string) ([]string, error) {
+	if driver == "" {
+		return annots, nil
+	}
+	if !rootlessutil.IsRootless() {
+		return nil, errors.New("--rootless-port-driver is only supported in rootless mode")
+	}
+	netType, err := nettype.Detect(networks)
+	if err != nil {
+		return nil, err
+	}
+	if (netType == nettype.Pasta) != (driver == rootlessPortDriverPasta) {
+		if netType == nettype.Pasta {
+			return nil, fmt.Errorf("--rootless-port-driver=%s cannot be used with --network=pasta, which forwards the ports with pasta", driver)
+		}
+		return nil, fmt.Errorf("--rootless-port-driver=%s requires --network=pasta", driver)
+	}
+	annotationsMap := strutil.ConvertKVStringsToMap(annots)
+	b4nn, b4nnSpecified := annotationsMap[annotations.Bypass4netns]
+	switch driver {
+	case rootlessPortDriverRootlessKit, rootlessPortDriverPasta:
+	case rootlessPortDriverBypass4netns:
+		if b4nnSpecified {
+			if enabled, err := strconv.ParseBool(b4nn); err != nil || !enabled {
+				return nil, fmt.Errorf("--rootless-port-driver=%s conflicts with annotation %s=%s", driver, annotations.Bypass4netns, b4nn)
+			}
+		} else {
+			annots = append(annots, annotations.Bypass4netns+"=true")
+		}
+	case "builtin", "slirp4netns", "implicit":
+		rlkClient, err := rootlessutil.NewRootlessKitClient()
+		if err != nil {
+			return nil, err
+		}
+		info, err := rlkClient.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the port driver of RootlessKit: %w", err)
+		}
+		if info.PortDriver == nil || info.PortDriver.Driver != driver {
+			actual := "none"
+			if info.PortDriver != nil {
+				actual = info.PortDriver.Driver
+			}
+			return nil, fmt.Errorf("--rootless-port-driver=%s is not available, as RootlessKit is running with port driver %q "+
+				"(Hint: RootlessKit runs a single port driver for all the containers, "+
+				"set CONTAINERD_ROOTLESS_ROOTLESSKIT_PORT_DRIVER for containerd-rootless.sh, or use --rootless-port-driver=%s)",
+				driver, actual, rootlessPortDriverBypass4netns)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rootless port driver %q", driver)
+	}
+	if driver != rootlessPortDriverBypass4netns && b4nnSpecified {
+		if enabled, _ := strconv.ParseBool(b4nn); enabled {
+			return nil, fmt.Errorf("--rootless-port-driver=%s conflicts with annotation %s=%s", driver, annotations.Bypass4netns, b4nn)
+		}
+	}
+	return append(annots, annotations.RootlessPortDriver+"="+driver), nil
+}
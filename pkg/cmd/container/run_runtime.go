@@ -17,21 +17,31 @@
 package container
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pelletier/go-toml/v2"
 
 	runcoptions "github.com/containerd/containerd/api/types/runc/options"
+	runtimeoptions "github.com/containerd/containerd/api/types/runtimeoptions/v1"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/containerd/v2/plugins"
 	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
 )
 
-func generateRuntimeCOpts(cgroupManager, runtimeStr string) ([]containerd.NewContainerOpts, error) {
+func generateRuntimeCOpts(cgroupManager, runtimeStr string, runtimes map[string]config.RuntimeConfig) ([]containerd.NewContainerOpts, error) {
+	if rc, ok := runtimes[runtimeStr]; ok {
+		return generateNamedRuntimeCOpts(cgroupManager, runtimeStr, rc)
+	}
 	runtime := plugins.RuntimeRuncV2
 	var (
 		runcOpts    runcoptions.Options
@@ -64,6 +74,67 @@ func generateRuntimeCOpts(cgroupManager, runtimeStr string) ([]containerd.NewCon
 	return []containerd.NewContainerOpts{o}, nil
 }
 
+// generateNamedRuntimeCOpts generates the runtime options of a [runtimes.<NAME>] table of nerdctl.toml .
+// The options are decoded as the runtime options of the CRI plugin of containerd:
+// runcoptions.Options for the runc runtime types, and runtimeoptions.Options (e.g. `ConfigPath`) for the others.
+func generateNamedRuntimeCOpts(cgroupManager, name string, rc config.RuntimeConfig) ([]containerd.NewContainerOpts, error) {
+	runtime := rc.Type
+	if runtime == "" {
+		runtime = plugins.RuntimeRuncV2
+	}
+	var runtimeOpts interface{}
+	if strings.HasPrefix(runtime, "io.containerd.runc.") {
+		runcOpts := &runcoptions.Options{SystemdCgroup: cgroupManager == "systemd"}
+		if err := decodeRuntimeOptions(rc.Options, runcOpts); err != nil {
+			return nil, fmt.Errorf("invalid options of runtime %q: %w", name, err)
+		}
+		if rc.Path != "" {
+			runcOpts.BinaryName = rc.Path
+		}
+		runtimeOpts = runcOpts
+	} else {
+		if cgroupManager == "systemd" {
+			log.L.Warnf("cannot set cgroup manager to %q for runtime %q", cgroupManager, name)
+		}
+		if len(rc.Options) > 0 {
+			opts := &runtimeoptions.Options{}
+			if err := decodeRuntimeOptions(rc.Options, opts); err != nil {
+				return nil, fmt.Errorf("invalid options of runtime %q: %w", name, err)
+			}
+			runtimeOpts = opts
+		}
+		if rc.Path != "" {
+			// containerd accepts the absolute path of a shim binary as the runtime name
+			if !filepath.IsAbs(rc.Path) {
+				return nil, fmt.Errorf("the path of runtime %q must be absolute, got %q", name, rc.Path)
+			}
+			runtime = rc.Path
+		}
+	}
+	return []containerd.NewContainerOpts{containerd.WithRuntime(runtime, runtimeOpts)}, nil
+}
+
+// decodeRuntimeOptions decodes the options of a runtime into the options type of the runtime,
+// matching the keys with the field names (e.g. `BinaryName`, `SystemdCgroup`, `ConfigPath`).
+func decodeRuntimeOptions(options map[string]any, v any) error {
+	if len(options) == 0 {
+		return nil
+	}
+	b, err := toml.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return toml.NewDecoder(bytes.NewReader(b)).DisallowUnknownFields().Decode(v)
+}
+
+// generateRuntimeAnnotations returns the annotations of a [runtimes.<NAME>] table of nerdctl.toml, if any.
+func generateRuntimeAnnotations(runtimeStr string, runtimes map[string]config.RuntimeConfig) map[string]string {
+	if rc, ok := runtimes[runtimeStr]; ok {
+		return rc.Annotations
+	}
+	return nil
+}
+
 // WithSysctls sets the provided sysctls onto the spec
 func WithSysctls(sysctls map[string]string) oci.SpecOpts {
 	return func(ctx context.Context, client oci.Client, c *containers.Container, s *specs.Spec) error {
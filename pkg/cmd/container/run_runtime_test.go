@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	runcoptions "github.com/containerd/containerd/api/types/runc/options"
+	runtimeoptions "github.com/containerd/containerd/api/types/runtimeoptions/v1"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+func TestGenerateRuntimeCOptsNamed(t *testing.T) {
+	runtimes := map[string]config.RuntimeConfig{
+		"crun": {
+			Path:    "/usr/local/bin/crun",
+			Options: map[string]any{"NoNewKeyring": true, "IoUid": int64(1000)},
+		},
+		"kata": {
+			Type:    "io.containerd.kata.v2",
+			Options: map[string]any{"ConfigPath": "/opt/kata/configuration-qemu.toml"},
+		},
+		"gvisor": {
+			Type: "io.containerd.runsc.v1",
+			Path: "/usr/local/bin/containerd-shim-runsc-v1",
+		},
+		"typo": {
+			Options: map[string]any{"BinaryNam": "crun"},
+		},
+		"relative": {
+			Type: "io.containerd.runsc.v1",
+			Path: "containerd-shim-runsc-v1",
+		},
+	}
+	apply := func(t *testing.T, cgroupManager, runtimeStr string) containers.Container {
+		t.Helper()
+		opts, err := generateRuntimeCOpts(cgroupManager, runtimeStr, runtimes)
+		assert.NilError(t, err)
+		var c containers.Container
+		for _, o := range opts {
+			assert.NilError(t, o(context.Background(), nil, &c))
+		}
+		return c
+	}
+
+	c := apply(t, "systemd", "crun")
+	assert.Equal(t, c.Runtime.Name, "io.containerd.runc.v2")
+	v, err := typeurl.UnmarshalAny(c.Runtime.Options)
+	assert.NilError(t, err)
+	runcOpts := v.(*runcoptions.Options)
+	assert.Equal(t, runcOpts.BinaryName, "/usr/local/bin/crun")
+	assert.Equal(t, runcOpts.SystemdCgroup, true)
+	assert.Equal(t, runcOpts.NoNewKeyring, true)
+	assert.Equal(t, runcOpts.IoUid, uint32(1000))
+
+	c = apply(t, "cgroupfs", "kata")
+	assert.Equal(t, c.Runtime.Name, "io.containerd.kata.v2")
+	v, err = typeurl.UnmarshalAny(c.Runtime.Options)
+	assert.NilError(t, err)
+	assert.Equal(t, v.(*runtimeoptions.Options).ConfigPath, "/opt/kata/configuration-qemu.toml")
+
+	c = apply(t, "cgroupfs", "gvisor")
+	assert.Equal(t, c.Runtime.Name, "/usr/local/bin/containerd-shim-runsc-v1")
+	assert.Assert(t, c.Runtime.Options == nil)
+
+	_, err = generateRuntimeCOpts("cgroupfs", "typo", runtimes)
+	assert.ErrorContains(t, err, "invalid options of runtime \"typo\"")
+	_, err = generateRuntimeCOpts("cgroupfs", "relative", runtimes)
+	assert.ErrorContains(t, err, "must be absolute")
+
+	// unnamed runtimes are not affected
+	c = apply(t, "cgroupfs", "io.containerd.runsc.v1")
+	assert.Equal(t, c.Runtime.Name, "io.containerd.runsc.v1")
+}
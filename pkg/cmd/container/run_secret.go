@@ -0,0 +1,217 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
+)
+
+// secretsDefaultDir is the directory of the secrets in the container, when the target is not absolute.
+const secretsDefaultDir = "/run/secrets"
+
+// parseSecret parses `--secret source=<NAME>,file=<PATH>|env=<VAR>|fd=<FD>|provider=<PROGRAM>[,target=<PATH>,uid=<UID>,gid=<GID>,mode=<MODE>]`,
+// and reads the content of the secret:
+//
+//   - file=<PATH>: the content of the file
+//   - env=<VAR>: the value of the environment variable of nerdctl
+//   - fd=<FD>: the content read from the file descriptor inherited by nerdctl, e.g., a pipe from `nerdctl compose`
+//   - provider=<PROGRAM>: the standard output of `<PROGRAM> <NAME>`, e.g., a wrapper of the CLI of a secret manager
+//
+// The path of the file and of the provider are made absolute, so that the secret can be read again on start.
+func parseSecret(ctx context.Context, s string) (*secretutil.Secret, error) {
+	fields, err := csv.NewReader(strings.NewReader(s)).Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse secret %q: %w", s, err)
+	}
+	res := &secretutil.Secret{UID: -1, GID: -1, HostUID: -1, HostGID: -1, Mode: 0o444}
+	var env, fd string
+	var providers int
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid secret option %q: expected <KEY>=<VALUE>", field)
+		}
+		switch strings.ToLower(k) {
+		case "source", "src":
+			res.Source = v
+		case "target", "dst":
+			res.Target = v
+		case "file":
+			res.File = v
+			providers++
+		case "env":
+			env = v
+			providers++
+		case "fd":
+			fd = v
+			providers++
+		case "provider":
+			res.Provider = v
+			providers++
+		case "uid":
+			if res.UID, err = strconv.Atoi(v); err != nil || res.UID < 0 {
+				return nil, fmt.Errorf("invalid secret uid %q", v)
+			}
+		case "gid":
+			if res.GID, err = strconv.Atoi(v); err != nil || res.GID < 0 {
+				return nil, fmt.Errorf("invalid secret gid %q", v)
+			}
+		case "mode":
+			mode, err := strconv.ParseUint(v, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid secret mode %q: %w", v, err)
+			}
+			res.Mode = os.FileMode(mode).Perm()
+		default:
+			return nil, fmt.Errorf("unknown secret option %q", k)
+		}
+	}
+	if err := identifiers.ValidateDockerCompat(res.Source); err != nil {
+		return nil, fmt.Errorf("invalid secret source: %w", err)
+	}
+	if res.Target == "" {
+		res.Target = res.Source
+	}
+	if !path.IsAbs(res.Target) {
+		res.Target = path.Join(secretsDefaultDir, res.Target)
+	}
+	res.Target = path.Clean(res.Target)
+
+	switch {
+	case providers != 1:
+		return nil, fmt.Errorf("secret %q: exactly one of file, env, fd, and provider must be specified", res.Source)
+	case res.File != "":
+		if res.File, err = filepath.Abs(res.File); err != nil {
+			return nil, err
+		}
+	case env != "":
+		v, ok := os.LookupEnv(env)
+		if !ok {
+			return nil, fmt.Errorf("secret %q: environment variable %q is not set", res.Source, env)
+		}
+		res.Content = []byte(v)
+		return res, nil
+	case fd != "":
+		n, err := strconv.Atoi(fd)
+		if err != nil || n < 3 {
+			return nil, fmt.Errorf("secret %q: invalid file descriptor %q", res.Source, fd)
+		}
+		f := os.NewFile(uintptr(n), "secret-"+res.Source)
+		defer f.Close()
+		if res.Content, err = io.ReadAll(f); err != nil {
+			return nil, fmt.Errorf("secret %q: %w", res.Source, err)
+		}
+		return res, nil
+	case res.Provider != "":
+		if res.Provider, err = exec.LookPath(res.Provider); err != nil {
+			return nil, fmt.Errorf("secret %q: %w", res.Source, err)
+		}
+		if res.Provider, err = filepath.Abs(res.Provider); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("secret %q: the value of file, env, fd, or provider must not be empty", res.Source)
+	}
+	if err := res.Read(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// generateSecretOpts reads the secrets of `--secret`, and returns the SpecOpts writing them
+// to the runtime dir and bind-mounting them read-only.
+// The secrets are neither recorded in the labels of the container, nor committed, as they are not in the rootfs.
+func generateSecretOpts(ctx context.Context, stateDir, namespace, id string, secretFlags []string) ([]oci.SpecOpts, error) {
+	if len(secretFlags) == 0 {
+		return nil, nil
+	}
+	if runtime.GOOS == "windows" {
+		return nil, errors.New("secrets are not supported on Windows")
+	}
+	targets := make(map[string]string, len(secretFlags))
+	var secrets []*secretutil.Secret
+	for _, s := range secretFlags {
+		x, err := parseSecret(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := targets[x.Target]; ok {
+			return nil, fmt.Errorf("secrets %q and %q have the same target %q", other, x.Source, x.Target)
+		}
+		targets[x.Target] = x.Source
+		secrets = append(secrets, x)
+	}
+	dir, err := secretutil.Dir(namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	return []oci.SpecOpts{withSecrets(stateDir, dir, secrets)}, nil
+}
+
+// withSecrets writes the secrets to dir, and bind-mounts them into the container.
+// The owner of the secrets is mapped to the host with the user namespace of the container, if any.
+// The SpecOpts must be applied after the user namespace of the container is set.
+func withSecrets(stateDir, dir string, secrets []*secretutil.Secret) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		var uidMap, gidMap []specs.LinuxIDMapping
+		if s.Linux != nil {
+			uidMap, gidMap = s.Linux.UIDMappings, s.Linux.GIDMappings
+		}
+		for i, x := range secrets {
+			if x.UID != -1 {
+				hostUID, err := toHost(uint32(x.UID), uidMap)
+				if err != nil {
+					return fmt.Errorf("secret %q: %w", x.Source, err)
+				}
+				x.HostUID = int(hostUID)
+			}
+			if x.GID != -1 {
+				hostGID, err := toHost(uint32(x.GID), gidMap)
+				if err != nil {
+					return fmt.Errorf("secret %q: %w", x.Source, err)
+				}
+				x.HostGID = int(hostGID)
+			}
+			s.Mounts = append(s.Mounts, specs.Mount{
+				Type:        "bind",
+				Source:      secretutil.Path(dir, i),
+				Destination: x.Target,
+				Options:     []string{"rbind", "ro", "nosuid", "nodev", "noexec"},
+			})
+		}
+		return secretutil.Write(stateDir, dir, secrets)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
+)
+
+func TestParseSecret(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "dbpass.txt")
+	assert.NilError(t, os.WriteFile(file, []byte("s3cr3t"), 0o600))
+	t.Setenv("NERDCTL_TEST_SECRET", "from-env")
+
+	x, err := parseSecret(ctx, "source=dbpass,file="+file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x, &secretutil.Secret{Source: "dbpass", Target: "/run/secrets/dbpass", UID: -1, GID: -1, HostUID: -1, HostGID: -1, Mode: 0o444, File: file, Content: []byte("s3cr3t")})
+
+	x, err = parseSecret(ctx, "src=token,env=NERDCTL_TEST_SECRET,target=app/token,uid=1000,gid=1001,mode=0400")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x, &secretutil.Secret{Source: "token", Target: "/run/secrets/app/token", UID: 1000, GID: 1001, HostUID: -1, HostGID: -1, Mode: 0o400, Content: []byte("from-env")})
+
+	r, w, err := os.Pipe()
+	assert.NilError(t, err)
+	_, err = w.Write([]byte("from-pipe"))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+	// parseSecret closes the file descriptor; closing r again only clears its finalizer
+	x, err = parseSecret(ctx, "source=token,fd="+strconv.Itoa(int(r.Fd())))
+	_ = r.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, string(x.Content), "from-pipe")
+
+	x, err = parseSecret(ctx, "source=token,env=NERDCTL_TEST_SECRET,target=/etc/token")
+	assert.NilError(t, err)
+	assert.Equal(t, x.Target, "/etc/token")
+
+	for _, s := range []string{
+		"file=" + file,
+		"source=dbpass",
+		"source=dbpass,file=" + file + ",env=NERDCTL_TEST_SECRET",
+		"source=dbpass,env=NERDCTL_TEST_SECRET_UNSET",
+		"source=dbpass,file=" + file + ",mode=999",
+		"source=dbpass,file=" + file + ",uid=-1",
+		"source=dbpass,file=" + file + ",foo=bar",
+		"source=dbpass,fd=0",
+		"source=../dbpass,file=" + file,
+	} {
+		_, err := parseSecret(ctx, s)
+		assert.Assert(t, err != nil, s)
+	}
+}
@@ -17,16 +17,24 @@
 package container
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/containerd/containerd/v2/contrib/apparmor"
 	"github.com/containerd/containerd/v2/contrib/seccomp"
+	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/cap"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/apparmorutil"
@@ -49,23 +57,86 @@ var privilegedWithoutDevicesOpts = []oci.SpecOpts{
 
 const (
 	systemPathsUnconfined = "unconfined"
+	// unmaskAll is the value of `--security-opt unmask` unmasking all the masked and readonly paths.
+	unmaskAll = "ALL"
 )
 
-func generateSecurityOpts(privileged bool, securityOptsMap map[string]string) ([]oci.SpecOpts, error) {
+// ensureAppArmorProfile checks that the profile is loaded, and loads it if it is the default profile.
+func ensureAppArmorProfile(profile string, canLoadNewAppArmor bool) error {
+	if profile == defaults.AppArmorProfileName && canLoadNewAppArmor {
+		return apparmor.LoadDefaultProfile(defaults.AppArmorProfileName)
+	}
+	loaded, err := apparmorutil.IsLoaded(profile)
+	if err != nil {
+		// the loaded profiles cannot be listed from a user namespace
+		log.L.WithError(err).Debugf("failed to check whether AppArmor profile %q is loaded", profile)
+		return nil
+	}
+	if !loaded {
+		hint := "load it with `nerdctl apparmor load <FILE>`"
+		if profile == defaults.AppArmorProfileName {
+			hint = apparmorutil.InstallHint
+		}
+		return fmt.Errorf("AppArmor profile %q is not loaded (Hint: %s, see `nerdctl apparmor ls` for the loaded profiles): %w", profile, hint, errdefs.ErrNotFound)
+	}
+	return nil
+}
+
+// builtinSeccompProfiles are the variants of the builtin seccomp profile, specified as `builtin:<VARIANT>`.
+// Each variant allows the syscalls on top of the builtin profile, without granting the corresponding capability.
+var builtinSeccompProfiles = map[string][]string{
+	// the syscalls allowed by the builtin profile for CAP_SYS_PTRACE
+	"allow-ptrace": {"kcmp", "pidfd_getfd", "process_madvise", "process_vm_readv", "process_vm_writev", "ptrace"},
+	// the syscalls allowed by the builtin profile for CAP_PERFMON
+	"allow-perf": {"perf_event_open"},
+}
+
+// withBuiltinSeccompProfile sets a variant of the builtin seccomp profile to the spec.
+// Like seccomp.WithDefaultProfile, it must follow the setting of process capabilities.
+func withBuiltinSeccompProfile(variant string) (oci.SpecOpts, error) {
+	syscalls, ok := builtinSeccompProfiles[variant]
+	if !ok {
+		return nil, fmt.Errorf("unknown builtin seccomp profile %q (known: %s)", variant,
+			strings.Join(slices.Sorted(maps.Keys(builtinSeccompProfiles)), ", "))
+	}
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		s.Linux.Seccomp = seccomp.DefaultProfile(s)
+		s.Linux.Seccomp.Syscalls = append(s.Linux.Seccomp.Syscalls, specs.LinuxSyscall{
+			Names:  syscalls,
+			Action: specs.ActAllow,
+			Args:   []specs.LinuxSeccompArg{},
+		})
+		return nil
+	}, nil
+}
+
+// generateSecurityOpts generates the spec opts of the security-opts.
+// defaultSeccompProfile is used if the seccomp security-opt is not specified, unless empty.
+func generateSecurityOpts(privileged bool, securityOptsMap map[string]string, defaultSeccompProfile string) ([]oci.SpecOpts, error) {
 	for k := range securityOptsMap {
 		switch k {
-		case "seccomp", "apparmor", "no-new-privileges", "systempaths", "privileged-without-host-devices", "writable-cgroups":
+		case "seccomp", "apparmor", "no-new-privileges", "systempaths", "privileged-without-host-devices", "writable-cgroups", "label", "mask", "unmask":
 		default:
 			log.L.Warnf("unknown security-opt: %q", k)
 		}
 	}
 	var opts []oci.SpecOpts
-	if seccompProfile, ok := securityOptsMap["seccomp"]; ok && seccompProfile != defaults.SeccompProfileName {
+	seccompProfile, ok := securityOptsMap["seccomp"]
+	if !ok && defaultSeccompProfile != "" {
+		seccompProfile, ok = defaultSeccompProfile, true
+	}
+	if ok && seccompProfile != defaults.SeccompProfileName {
 		if seccompProfile == "" {
 			return nil, errors.New("invalid security-opt \"seccomp\"")
 		}
 
-		if seccompProfile != "unconfined" {
+		if variant, isBuiltin := strings.CutPrefix(seccompProfile, defaults.SeccompProfileName+":"); isBuiltin {
+			opt, err := withBuiltinSeccompProfile(variant)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, opt)
+		} else if seccompProfile != "unconfined" {
 			opts = append(opts, seccomp.WithProfile(seccompProfile))
 		}
 	} else {
@@ -82,6 +153,9 @@ func generateSecurityOpts(privileged bool, securityOptsMap map[string]string) ([
 			if !canApplyExistingProfile {
 				log.L.Warnf("the host does not support AppArmor. Ignoring profile %q", aaProfile)
 			} else {
+				if err := ensureAppArmorProfile(aaProfile, canLoadNewAppArmor); err != nil {
+					return nil, err
+				}
 				opts = append(opts, apparmor.WithProfile(aaProfile))
 			}
 		}
@@ -93,6 +167,10 @@ func generateSecurityOpts(privileged bool, securityOptsMap map[string]string) ([
 		}
 		if apparmorutil.CanApplySpecificExistingProfile(defaults.AppArmorProfileName) {
 			opts = append(opts, apparmor.WithProfile(defaults.AppArmorProfileName))
+		} else if canApplyExistingProfile {
+			log.L.Warnf("AppArmor profile %q is not loaded, running the container without AppArmor "+
+				"(Hint: %s, or specify `--security-opt apparmor=unconfined` to suppress this warning)",
+				defaults.AppArmorProfileName, apparmorutil.InstallHint)
 		}
 	}
 
@@ -141,6 +219,56 @@ func generateSecurityOpts(privileged bool, securityOptsMap map[string]string) ([
 	return opts, nil
 }
 
+// generateMaskedPathsOpts generates the spec opts of `--security-opt mask=<PATH>[:<PATH>...]` and
+// `--security-opt unmask=<PATH>[:<PATH>...]|ALL`, that customize the masked paths and the readonly paths, like Podman.
+// The security-opts may be specified multiple times, so they are parsed from the slice rather than the map.
+// The spec opts must be applied after the other security-opts, as `mask` takes effect with `systempaths=unconfined` too.
+func generateMaskedPathsOpts(securityOpts []string) ([]oci.SpecOpts, error) {
+	var mask, unmask []string
+	for _, opt := range securityOpts {
+		k, v, _ := strings.Cut(opt, "=")
+		if k != "mask" && k != "unmask" {
+			continue
+		}
+		if v == "" {
+			return nil, fmt.Errorf("invalid security-opt %q", opt)
+		}
+		for _, p := range strings.Split(v, ":") {
+			if k == "unmask" && p == unmaskAll {
+				unmask = append(unmask, p)
+				continue
+			}
+			if !path.IsAbs(p) {
+				return nil, fmt.Errorf("invalid security-opt %q: %q is not an absolute path", opt, p)
+			}
+			if k == "mask" {
+				mask = append(mask, path.Clean(p))
+			} else {
+				unmask = append(unmask, path.Clean(p))
+			}
+		}
+	}
+	if len(mask) == 0 && len(unmask) == 0 {
+		return nil, nil
+	}
+	return []oci.SpecOpts{func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if s.Linux == nil {
+			s.Linux = &specs.Linux{}
+		}
+		isUnmasked := func(p string) bool {
+			return slices.Contains(unmask, unmaskAll) || slices.Contains(unmask, p)
+		}
+		s.Linux.MaskedPaths = slices.DeleteFunc(s.Linux.MaskedPaths, isUnmasked)
+		s.Linux.ReadonlyPaths = slices.DeleteFunc(s.Linux.ReadonlyPaths, isUnmasked)
+		for _, p := range mask {
+			if !slices.Contains(s.Linux.MaskedPaths, p) {
+				s.Linux.MaskedPaths = append(s.Linux.MaskedPaths, p)
+			}
+		}
+		return nil
+	}}, nil
+}
+
 func canonicalizeCapName(s string) string {
 	if s == "" {
 		return ""
@@ -173,32 +301,54 @@ func isKnownCapName(s string) bool {
 	return ok
 }
 
-func generateCapOpts(capAdd, capDrop []string) ([]oci.SpecOpts, error) {
-	if len(capAdd) == 0 && len(capDrop) == 0 {
+// capabilityPresets are the named sets of capabilities accepted by `--cap-add` and `--cap-drop`, and the default capabilities of nerdctl.toml.
+var capabilityPresets = map[string][]string{
+	// the capabilities to switch the user and to manage the ownership of files, for most images running as root
+	"minimal": {"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID"},
+	// the capabilities for basic networking, e.g., to listen on ports below 1024 and to ping
+	"net-basic": {"CAP_NET_BIND_SERVICE", "CAP_NET_RAW"},
+	// the default capabilities of containerd (pkg/oci), same as dockerd
+	"default": {
+		"CAP_AUDIT_WRITE", "CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL", "CAP_MKNOD",
+		"CAP_NET_BIND_SERVICE", "CAP_NET_RAW", "CAP_SETFCAP", "CAP_SETGID", "CAP_SETPCAP", "CAP_SETUID", "CAP_SYS_CHROOT",
+	},
+}
+
+// expandCapabilities expands the capability presets, and canonicalizes the capability names.
+func expandCapabilities(caps []string) []string {
+	var res []string
+	for _, c := range caps {
+		if preset, ok := capabilityPresets[strings.ToLower(c)]; ok {
+			res = append(res, preset...)
+			continue
+		}
+		res = append(res, canonicalizeCapName(c))
+	}
+	return strutil.DedupeStrSlice(res)
+}
+
+// generateCapOpts generates the spec opts of the capabilities.
+// defaultCaps replace the default capabilities of the containers, unless empty.
+func generateCapOpts(capAdd, capDrop, defaultCaps []string) ([]oci.SpecOpts, error) {
+	if len(capAdd) == 0 && len(capDrop) == 0 && len(defaultCaps) == 0 {
 		return nil, nil
 	}
 
 	var opts []oci.SpecOpts
 	if strutil.InStringSlice(capDrop, "ALL") {
 		opts = append(opts, oci.WithCapabilities(nil))
+	} else if len(defaultCaps) > 0 {
+		opts = append(opts, oci.WithCapabilities(expandCapabilities(defaultCaps)))
 	}
 
 	if strutil.InStringSlice(capAdd, "ALL") {
 		opts = append(opts, oci.WithAllCurrentCapabilities)
 	} else {
-		var capsAdd []string
-		for _, c := range capAdd {
-			capsAdd = append(capsAdd, canonicalizeCapName(c))
-		}
-		opts = append(opts, oci.WithAddedCapabilities(capsAdd))
+		opts = append(opts, oci.WithAddedCapabilities(expandCapabilities(capAdd)))
 	}
 
 	if !strutil.InStringSlice(capDrop, "ALL") {
-		var capsDrop []string
-		for _, c := range capDrop {
-			capsDrop = append(capsDrop, canonicalizeCapName(c))
-		}
-		opts = append(opts, oci.WithDroppedCapabilities(capsDrop))
+		opts = append(opts, oci.WithDroppedCapabilities(expandCapabilities(capDrop)))
 	}
 	return opts, nil
 }
@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/containers"
+)
+
+func TestWithBuiltinSeccompProfile(t *testing.T) {
+	opt, err := withBuiltinSeccompProfile("allow-perf")
+	assert.NilError(t, err)
+	s := &specs.Spec{Linux: &specs.Linux{}, Process: &specs.Process{Capabilities: &specs.LinuxCapabilities{}}}
+	assert.NilError(t, opt(context.Background(), nil, &containers.Container{}, s))
+	assert.Assert(t, s.Linux.Seccomp != nil)
+	var allowed bool
+	for _, syscall := range s.Linux.Seccomp.Syscalls {
+		if syscall.Action == specs.ActAllow && slices.Contains(syscall.Names, "perf_event_open") {
+			allowed = true
+		}
+	}
+	assert.Assert(t, allowed, "perf_event_open should be allowed")
+
+	_, err = withBuiltinSeccompProfile("foo")
+	assert.ErrorContains(t, err, "unknown builtin seccomp profile")
+}
+
+func TestGenerateSecurityOptsDefaultSeccompProfile(t *testing.T) {
+	_, err := generateSecurityOpts(false, map[string]string{}, "builtin:foo")
+	assert.ErrorContains(t, err, "unknown builtin seccomp profile")
+
+	// the security-opt takes precedence over the default profile
+	_, err = generateSecurityOpts(false, map[string]string{"seccomp": "unconfined", "apparmor": "unconfined"}, "builtin:foo")
+	assert.NilError(t, err)
+}
+
+func TestExpandCapabilities(t *testing.T) {
+	assert.DeepEqual(t, expandCapabilities([]string{"net-basic", "net_raw", "sys_admin"}),
+		[]string{"CAP_NET_BIND_SERVICE", "CAP_NET_RAW", "CAP_SYS_ADMIN"})
+	assert.DeepEqual(t, expandCapabilities([]string{"MINIMAL"}), capabilityPresets["minimal"])
+	assert.Assert(t, expandCapabilities(nil) == nil)
+}
+
+func TestGenerateMaskedPathsOpts(t *testing.T) {
+	apply := func(t *testing.T, securityOpts ...string) *specs.Spec {
+		t.Helper()
+		opts, err := generateMaskedPathsOpts(securityOpts)
+		assert.NilError(t, err)
+		s := &specs.Spec{Linux: &specs.Linux{
+			MaskedPaths:   []string{"/proc/acpi", "/sys/firmware"},
+			ReadonlyPaths: []string{"/proc/sys", "/proc/bus"},
+		}}
+		for _, o := range opts {
+			assert.NilError(t, o(context.Background(), nil, &containers.Container{}, s))
+		}
+		return s
+	}
+
+	s := apply(t, "mask=/proc/foo:/proc/acpi", "unmask=/sys/firmware", "mask=/proc/bar/")
+	assert.DeepEqual(t, s.Linux.MaskedPaths, []string{"/proc/acpi", "/proc/foo", "/proc/bar"})
+	assert.DeepEqual(t, s.Linux.ReadonlyPaths, []string{"/proc/sys", "/proc/bus"})
+
+	s = apply(t, "unmask=/proc/sys")
+	assert.DeepEqual(t, s.Linux.MaskedPaths, []string{"/proc/acpi", "/sys/firmware"})
+	assert.DeepEqual(t, s.Linux.ReadonlyPaths, []string{"/proc/bus"})
+
+	s = apply(t, "unmask=ALL", "mask=/proc/foo")
+	assert.DeepEqual(t, s.Linux.MaskedPaths, []string{"/proc/foo"})
+	assert.Equal(t, len(s.Linux.ReadonlyPaths), 0)
+
+	for _, opt := range []string{"mask=", "mask=proc/foo", "unmask=/proc/foo::/proc/bar"} {
+		_, err := generateMaskedPathsOpts([]string{opt})
+		assert.Assert(t, err != nil, opt)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/selinux/go-selinux"
+	"github.com/opencontainers/selinux/go-selinux/label"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+// selinuxLabelOpts returns the values of the `label` security-opts, e.g., "type:svirt_apache_t" for "label=type:svirt_apache_t".
+// Unlike the other security-opts, `label` may be specified multiple times.
+func selinuxLabelOpts(securityOpts []string) []string {
+	var res []string
+	for _, opt := range securityOpts {
+		if v, ok := strings.CutPrefix(opt, "label="); ok {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// sharedSELinuxLabelOpts returns the label opts duplicating the SELinux label of the container
+// whose IPC or PID namespace is joined, so that both the containers can access each other, like dockerd.
+func sharedSELinuxLabelOpts(ctx context.Context, client *containerd.Client, internalLabels *internalLabels) ([]string, error) {
+	sharedID := internalLabels.pidContainer
+	if internalLabels.ipc != "" {
+		ipc, err := ipcutil.DecodeIPCLabel(internalLabels.ipc)
+		if err != nil {
+			return nil, err
+		}
+		if ipc.Mode == ipcutil.Container && ipc.VictimContainerID != nil {
+			sharedID = *ipc.VictimContainerID
+		}
+	}
+	if sharedID == "" {
+		return nil, nil
+	}
+	c, err := client.LoadContainer(ctx, sharedID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Process == nil {
+		return nil, nil
+	}
+	return selinux.DupSecOpt(spec.Process.SelinuxLabel)
+}
+
+// generateSELinuxOpts generates the spec opts setting the SELinux process label and mount label of the container.
+// The labels are generated from the `label` security-opts (`disable`, `user:`, `role:`, `type:`, `level:`, and `filetype:`),
+// or shared with the container whose IPC or PID namespace is joined.
+// It must follow generateNamespaceOpts.
+func generateSELinuxOpts(ctx context.Context, client *containerd.Client, privileged bool, securityOpts []string, internalLabels *internalLabels) ([]oci.SpecOpts, error) {
+	labelOpts := selinuxLabelOpts(securityOpts)
+	if !selinux.GetEnabled() {
+		if len(labelOpts) > 0 {
+			log.L.Warnf("SELinux is not enabled on the host. Ignoring security-opt label=%s", strings.Join(labelOpts, ","))
+		}
+		return nil, nil
+	}
+	if privileged {
+		labelOpts = selinux.DisableSecOpt()
+	} else if len(labelOpts) == 0 {
+		var err error
+		labelOpts, err = sharedSELinuxLabelOpts(ctx, client, internalLabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the SELinux label of the shared container: %w", err)
+		}
+	}
+	processLabel, mountLabel, err := label.InitLabels(labelOpts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid security-opt \"label\": %w", err)
+	}
+	if processLabel == "" {
+		// label=disable
+		return nil, nil
+	}
+	return []oci.SpecOpts{oci.WithSelinuxLabel(processLabel), withMountLabel(mountLabel)}, nil
+}
+
+func withMountLabel(mountLabel string) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if s.Linux == nil {
+			s.Linux = &specs.Linux{}
+		}
+		s.Linux.MountLabel = mountLabel
+		return nil
+	}
+}
+
+// relabelDeniedPaths are the host directories that must not be relabeled, as relabeling them breaks the host.
+var relabelDeniedPaths = []string{"/", "/bin", "/boot", "/dev", "/etc", "/home", "/lib", "/lib64", "/proc", "/root", "/run", "/sbin", "/sys", "/usr", "/var"}
+
+// withRelabelMounts returns a SpecOpts that relabels the sources of the mounts with the `z` or `Z` option
+// with the SELinux mount label of the container.
+// The sources of the volumes of the local driver are always relabeled as shared, as they are managed by nerdctl.
+// The SpecOpts must be applied after the SELinux labels of the container are set.
+func withRelabelMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		if s.Process == nil || s.Process.SelinuxLabel == "" || s.Linux == nil || s.Linux.MountLabel == "" {
+			// SELinux is disabled on the host, or for the container
+			return nil
+		}
+		for _, x := range mountPoints {
+			isBind := x.Mount.Type == "bind" || x.Mount.Type == mountutil.DefaultMountType
+			relabel := x.Relabel
+			if relabel == "" && x.Type == mountutil.Volume && x.Driver == "" && isBind {
+				relabel = mountutil.RelabelShared
+			}
+			if relabel == "" || x.Mount.Source == "" {
+				continue
+			}
+			if !isBind {
+				return fmt.Errorf("cannot relabel the %s filesystem mounted on %q", x.Mount.Type, x.Mount.Destination)
+			}
+			if slices.Contains(relabelDeniedPaths, filepath.Clean(x.Mount.Source)) {
+				return fmt.Errorf("relabeling %q is not allowed (mounted on %q)", x.Mount.Source, x.Mount.Destination)
+			}
+			if err := label.Relabel(x.Mount.Source, s.Linux.MountLabel, relabel == mountutil.RelabelShared); err != nil {
+				return fmt.Errorf("failed to relabel the mount %q: %w", x.Mount.Destination, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/containers"
+
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+func TestSELinuxLabelOpts(t *testing.T) {
+	securityOpts := []string{"label=type:svirt_apache_t", "seccomp=unconfined", "label=level:s0:c1,c2"}
+	assert.DeepEqual(t, selinuxLabelOpts(securityOpts), []string{"type:svirt_apache_t", "level:s0:c1,c2"})
+	assert.Assert(t, selinuxLabelOpts([]string{"apparmor=unconfined"}) == nil)
+}
+
+func TestWithRelabelMountsDenied(t *testing.T) {
+	s := &specs.Spec{
+		Process: &specs.Process{SelinuxLabel: "system_u:system_r:container_t:s0:c1,c2"},
+		Linux:   &specs.Linux{MountLabel: "system_u:object_r:container_file_t:s0:c1,c2"},
+	}
+	mountPoints := []*mountutil.Processed{
+		{
+			Type:    mountutil.Bind,
+			Mount:   specs.Mount{Type: "bind", Source: "/usr/", Destination: "/mnt"},
+			Relabel: mountutil.RelabelShared,
+		},
+	}
+	err := withRelabelMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s)
+	assert.ErrorContains(t, err, "relabeling \"/usr/\" is not allowed")
+
+	// nothing is relabeled when SELinux is disabled for the container
+	s.Process.SelinuxLabel = ""
+	assert.NilError(t, withRelabelMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s))
+}
+
+func TestWithRelabelMountsNonBind(t *testing.T) {
+	s := &specs.Spec{
+		Process: &specs.Process{SelinuxLabel: "system_u:system_r:container_t:s0:c1,c2"},
+		Linux:   &specs.Linux{MountLabel: "system_u:object_r:container_file_t:s0:c1,c2"},
+	}
+	// the volumes mounting a filesystem are not relabeled by default
+	mountPoints := []*mountutil.Processed{
+		{
+			Type:  mountutil.Volume,
+			Mount: specs.Mount{Type: "nfs", Source: ":/export", Destination: "/mnt"},
+		},
+	}
+	assert.NilError(t, withRelabelMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s))
+
+	mountPoints[0].Relabel = mountutil.RelabelPrivate
+	err := withRelabelMounts(mountPoints)(context.Background(), nil, &containers.Container{}, s)
+	assert.ErrorContains(t, err, "cannot relabel the nfs filesystem")
+}
@@ -20,12 +20,14 @@ package container
 
 import (
 	"context"
+	"errors"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/containerd/v2/pkg/oci"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 )
 
 func WithoutRunMount() func(ctx context.Context, client oci.Client, c *containers.Container, s *oci.Spec) error {
@@ -42,3 +44,32 @@ func setPlatformOptions(
 ) ([]oci.SpecOpts, error) {
 	return []oci.SpecOpts{}, nil
 }
+
+func withChownMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		for _, x := range mountPoints {
+			if x.Chown {
+				return errors.New("the chown option of mounts is only supported on Linux")
+			}
+		}
+		return nil
+	}
+}
+
+// withRelabelMounts is a no-op, as SELinux is only supported on Linux.
+func withRelabelMounts(_ []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		return nil
+	}
+}
+
+func withIDMappedMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		for _, x := range mountPoints {
+			if x.IDMap {
+				return errors.New("the idmap option of mounts is only supported on Linux")
+			}
+		}
+		return nil
+	}
+}
@@ -18,8 +18,11 @@ package container
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/go-units"
@@ -30,6 +33,7 @@ import (
 	"github.com/containerd/containerd/v2/pkg/oci"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 )
 
 const (
@@ -83,6 +87,9 @@ func setPlatformOptions(
 		}
 		opts = append(opts, oci.WithWindowsHyperV)
 	case "host":
+		if _, ok := credentialSpec(options.SecurityOpt); ok {
+			return nil, errors.New("credentialspec is not supported for host process containers (--isolation=host)")
+		}
 		hpAnnotations := map[string]string{
 			hostProcessContainer: "true",
 		}
@@ -93,7 +100,8 @@ func setPlatformOptions(
 			hpAnnotations[hostProcessInheritUser] = "true"
 		}
 
-		opts = append(opts, oci.WithAnnotations(hpAnnotations))
+		// HostProcess containers run directly on the host and cannot be hypervisor-isolated
+		opts = append(opts, oci.WithAnnotations(hpAnnotations), WithWindowsProcessIsolated())
 	case "process":
 		// override the default isolation mode in the case where
 		// the containerd default_runtime is set to hyper-v
@@ -109,13 +117,18 @@ func setPlatformOptions(
 		oci.WithWindowNetworksAllowUnqualifiedDNSQuery(),
 		oci.WithWindowsIgnoreFlushesDuringBoot())
 
-	for _, dev := range options.Device {
-		idType, devID, ok := strings.Cut(dev, "://")
-		if !ok {
-			return nil, errors.New("devices must be in the format IDType://ID")
+	if spec, ok := credentialSpec(options.SecurityOpt); ok {
+		credSpecOpt, err := withWindowsCredentialSpec(spec)
+		if err != nil {
+			return nil, err
 		}
-		if idType == "" {
-			return nil, errors.New("devices must have a non-empty IDType")
+		opts = append(opts, credSpecOpt)
+	}
+
+	for _, dev := range options.Device {
+		idType, devID, err := parseWindowsDevice(dev)
+		if err != nil {
+			return nil, err
 		}
 		opts = append(opts, oci.WithWindowsDevice(idType, devID))
 	}
@@ -123,6 +136,74 @@ func setPlatformOptions(
 	return opts, nil
 }
 
+// parseWindowsDevice parses the --device flag on Windows.
+// Both the "IDType://ID" form and the Docker-compatible "class/GUID" form are accepted.
+func parseWindowsDevice(dev string) (string, string, error) {
+	idType, devID, ok := strings.Cut(dev, "://")
+	if !ok {
+		idType, devID, ok = strings.Cut(dev, "/")
+		if !ok {
+			return "", "", fmt.Errorf("invalid device %q: devices must be in the format IDType://ID or class/GUID", dev)
+		}
+		if idType != "class" {
+			return "", "", fmt.Errorf("invalid device assignment type %q in %q: should be \"class\"", idType, dev)
+		}
+	}
+	if idType == "" {
+		return "", "", fmt.Errorf("invalid device %q: devices must have a non-empty IDType", dev)
+	}
+	if devID == "" {
+		return "", "", fmt.Errorf("invalid device %q: devices must have a non-empty ID", dev)
+	}
+	return idType, devID, nil
+}
+
+// credentialSpec returns the value of `--security-opt credentialspec=<SPEC>`, if specified.
+func credentialSpec(securityOpts []string) (string, bool) {
+	var (
+		spec  string
+		found bool
+	)
+	for _, opt := range securityOpts {
+		if k, v, ok := strings.Cut(opt, "="); ok && k == "credentialspec" {
+			spec, found = v, true
+		}
+	}
+	return spec, found
+}
+
+// withWindowsCredentialSpec sets the gMSA credential spec of the container.
+// The spec is either "file://<PATH>" (a JSON file on the host) or "raw://<JSON>".
+func withWindowsCredentialSpec(spec string) (oci.SpecOpts, error) {
+	var content string
+	switch {
+	case strings.HasPrefix(spec, "file://"):
+		p := strings.TrimPrefix(spec, "file://")
+		if !filepath.IsAbs(p) {
+			return nil, fmt.Errorf("invalid credentialspec %q: the path must be absolute", spec)
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentialspec %q: %w", p, err)
+		}
+		content = string(b)
+	case strings.HasPrefix(spec, "raw://"):
+		content = strings.TrimPrefix(spec, "raw://")
+	default:
+		return nil, fmt.Errorf("invalid credentialspec %q: must be file://<PATH> or raw://<JSON>", spec)
+	}
+	if !json.Valid([]byte(content)) {
+		return nil, fmt.Errorf("invalid credentialspec %q: not a valid JSON document", spec)
+	}
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		if s.Windows == nil {
+			s.Windows = &specs.Windows{}
+		}
+		s.Windows.CredentialSpec = content
+		return nil
+	}, nil
+}
+
 func WithWindowsProcessIsolated() oci.SpecOpts {
 	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
 		if s.Windows == nil {
@@ -134,3 +215,32 @@ func WithWindowsProcessIsolated() oci.SpecOpts {
 		return nil
 	}
 }
+
+func withChownMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		for _, x := range mountPoints {
+			if x.Chown {
+				return errors.New("the chown option of mounts is only supported on Linux")
+			}
+		}
+		return nil
+	}
+}
+
+// withRelabelMounts is a no-op, as SELinux is only supported on Linux.
+func withRelabelMounts(_ []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		return nil
+	}
+}
+
+func withIDMappedMounts(mountPoints []*mountutil.Processed) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, _ *oci.Spec) error {
+		for _, x := range mountPoints {
+			if x.IDMap {
+				return errors.New("the idmap option of mounts is only supported on Linux")
+			}
+		}
+		return nil
+	}
+}
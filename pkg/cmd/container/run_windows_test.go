@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+)
+
+func TestParseWindowsDevice(t *testing.T) {
+	const guid = "5B45201D-F2F2-4F3B-85BB-30FF1F953599"
+	tests := []struct {
+		dev    string
+		idType string
+		id     string
+		err    string
+	}{
+		{dev: "class://" + guid, idType: "class", id: guid},
+		{dev: "class/" + guid, idType: "class", id: guid},
+		{dev: "vpci-location-path://PCIROOT(0)#PCI(0100)", idType: "vpci-location-path", id: "PCIROOT(0)#PCI(0100)"},
+		{dev: guid, err: "devices must be in the format"},
+		{dev: "vpci/" + guid, err: `invalid device assignment type "vpci"`},
+		{dev: "://" + guid, err: "non-empty IDType"},
+		{dev: "class/", err: "non-empty ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dev, func(t *testing.T) {
+			idType, id, err := parseWindowsDevice(tt.dev)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, idType, tt.idType)
+			assert.Equal(t, id, tt.id)
+		})
+	}
+}
+
+func TestWithWindowsCredentialSpec(t *testing.T) {
+	spec, ok := credentialSpec([]string{"no-new-privileges", `credentialspec=raw://{"CmsPlugins":["ActiveDirectory"]}`})
+	assert.Assert(t, ok)
+	opt, err := withWindowsCredentialSpec(spec)
+	assert.NilError(t, err)
+	var s oci.Spec
+	assert.NilError(t, opt(context.Background(), nil, &containers.Container{}, &s))
+	assert.Equal(t, s.Windows.CredentialSpec, `{"CmsPlugins":["ActiveDirectory"]}`)
+
+	_, err = withWindowsCredentialSpec("raw://not-json")
+	assert.ErrorContains(t, err, "not a valid JSON document")
+	_, err = withWindowsCredentialSpec("registry://foo")
+	assert.ErrorContains(t, err, "must be file://<PATH> or raw://<JSON>")
+
+	_, ok = credentialSpec([]string{"no-new-privileges"})
+	assert.Assert(t, !ok)
+}
@@ -21,12 +21,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"sync"
 	"text/tabwriter"
 	"text/template"
 	"time"
 
+	"golang.org/x/term"
+
 	eventstypes "github.com/containerd/containerd/api/events"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/events"
@@ -36,7 +40,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
-	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
@@ -81,6 +85,17 @@ func (s *stats) isKnownContainer(cid string) (int, bool) {
 	return -1, false
 }
 
+// statsTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var statsTableHeaders = map[string]string{
+	"ID":       "CONTAINER ID",
+	"CPUPerc":  "CPU %",
+	"MemUsage": "MEM USAGE / LIMIT",
+	"MemPerc":  "MEM %",
+	"NetIO":    "NET I/O",
+	"BlockIO":  "BLOCK I/O",
+	"PIDs":     "PIDS",
+}
+
 // Stats displays a live stream of container(s) resource usage statistics.
 func Stats(ctx context.Context, client *containerd.Client, containerIDs []string, options types.ContainerStatsOptions) error {
 	// NOTE: rootless container does not rely on cgroupv1.
@@ -92,16 +107,26 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 	showAll := len(containerIDs) == 0
 	closeChan := make(chan error)
 
+	sortKey := options.Sort
+	if err := statsutil.SortEntries(nil, sortKey, false); err != nil {
+		return err
+	}
+
 	var err error
-	w := options.Stdout
-	var tmpl *template.Template
+	var (
+		tmpl        *template.Template
+		tableHeader string
+	)
 	switch options.Format {
 	case "", "table":
-		w = tabwriter.NewWriter(options.Stdout, 10, 1, 3, ' ', 0)
 	case "raw":
 		return errors.New("unsupported format: \"raw\"")
 	default:
-		tmpl, err = formatter.ParseTemplate(options.Format)
+		if formatter.IsTableFormat(options.Format) {
+			tmpl, tableHeader, err = formatter.ParseTableTemplate(options.Format, statsTableHeaders)
+		} else {
+			tmpl, err = formatter.ParseTemplate(options.Format)
+		}
 		if err != nil {
 			return err
 		}
@@ -252,21 +277,30 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 
 	}
 
-	cleanScreen := func() {
-		if !options.NoStream {
-			fmt.Fprint(options.Stdout, "\033[2J")
-			fmt.Fprint(options.Stdout, "\033[H")
+	// keys are the sorting keys pressed in the interactive mode, see statsKeys
+	var (
+		keys <-chan byte
+		raw  bool
+	)
+	if !options.NoStream && tmpl == nil {
+		if f, ok := options.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			oldState, err := term.MakeRaw(int(f.Fd()))
+			if err != nil {
+				return err
+			}
+			defer term.Restore(int(f.Fd()), oldState)
+			raw = true
+			keys = readKeys(f)
 		}
 	}
+	reverse := false
 
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
 
-	// firstTick is for creating distant CPU readings.
-	// firstTick stats are not displayed.
-	firstTick := true
-	for range ticker.C {
-		cleanScreen()
+	// Each container already has a complete sample, as waitFirst is released after the second reading
+	// of the CPU usage, so the stats are printed right away.
+	for {
 		ccstats := []statsutil.StatsEntry{}
 		cStats.mu.Lock()
 		for _, c := range cStats.cs {
@@ -276,12 +310,20 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 			ccstats = append(ccstats, c.GetStatistics())
 		}
 		cStats.mu.Unlock()
+		if err := statsutil.SortEntries(ccstats, sortKey, reverse); err != nil {
+			return err
+		}
 
-		if !firstTick {
+		// the whole frame is rendered first, so that the screen does not flicker
+		var frame bytes.Buffer
+		w := io.Writer(&frame)
+		if tmpl == nil {
+			w = tabwriter.NewWriter(&frame, 10, 1, 3, ' ', 0)
 			// print header for every tick
-			if options.Format == "" || options.Format == "table" {
-				fmt.Fprintln(w, "CONTAINER ID\tNAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
-			}
+			fmt.Fprintln(w, "CONTAINER ID\tNAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
+		} else if tableHeader != "" {
+			w = tabwriter.NewWriter(&frame, 10, 1, 3, ' ', 0)
+			fmt.Fprintln(w, tableHeader)
 		}
 
 		for _, c := range ccstats {
@@ -289,39 +331,47 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 				continue
 			}
 			rc := statsutil.RenderEntry(&c, options.NoTrunc)
-			if !firstTick {
-				if tmpl != nil {
-					var b bytes.Buffer
-					if err := tmpl.Execute(&b, rc); err != nil {
-						break
-					}
-					if _, err = fmt.Fprintln(options.Stdout, b.String()); err != nil {
-						break
-					}
-				} else {
-					if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-						rc.ID,
-						rc.Name,
-						rc.CPUPerc,
-						rc.MemUsage,
-						rc.MemPerc,
-						rc.NetIO,
-						rc.BlockIO,
-						rc.PIDs,
-					); err != nil {
-						break
-					}
+			if tmpl != nil {
+				var b bytes.Buffer
+				if err := tmpl.Execute(&b, rc); err != nil {
+					break
+				}
+				if _, err = fmt.Fprintln(w, b.String()); err != nil {
+					break
+				}
+			} else {
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					rc.ID,
+					rc.Name,
+					rc.CPUPerc,
+					rc.MemUsage,
+					rc.MemPerc,
+					rc.NetIO,
+					rc.BlockIO,
+					rc.PIDs,
+				); err != nil {
+					break
 				}
 			}
 		}
 		if f, ok := w.(formatter.Flusher); ok {
 			f.Flush()
 		}
+		if raw {
+			fmt.Fprintf(&frame, "\n%s\n", statsKeysHelp(sortKey, reverse))
+		}
+		if options.NoStream {
+			if _, err := options.Stdout.Write(frame.Bytes()); err != nil {
+				return err
+			}
+		} else if err := consoleutil.Redraw(options.Stdout, frame.Bytes(), raw); err != nil {
+			return err
+		}
 
 		if len(cStats.cs) == 0 && !showAll {
 			break
 		}
-		if options.NoStream && !firstTick {
+		if options.NoStream {
 			break
 		}
 		select {
@@ -334,12 +384,67 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 		default:
 			// just skip
 		}
-		firstTick = false
+		select {
+		case <-ticker.C:
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			switch key {
+			case 'q', 0x03: // Ctrl-C does not raise SIGINT in the raw mode
+				return nil
+			case 'r':
+				reverse = !reverse
+			default:
+				if k, ok := statsKeys[key]; ok {
+					sortKey, reverse = k, false
+				}
+			}
+		}
 	}
 
 	return err
 }
 
+// statsKeys are the keys for sorting the stats in the interactive mode of `nerdctl stats`.
+var statsKeys = map[byte]string{
+	'c': statsutil.SortCPU,
+	'm': statsutil.SortMem,
+	'n': statsutil.SortNet,
+	'b': statsutil.SortBlock,
+	'p': statsutil.SortPIDs,
+	'o': statsutil.SortName,
+	'i': statsutil.SortID,
+}
+
+func statsKeysHelp(sortKey string, reverse bool) string {
+	sortedBy := "creation"
+	if sortKey != "" {
+		sortedBy = sortKey
+	}
+	if reverse {
+		sortedBy += " (reversed)"
+	}
+	return fmt.Sprintf("Sorted by %s. Sort by [c]pu, [m]em, [n]et, [b]lock, [p]ids, name ([o]), [i]d, [r]everse, [q]uit", sortedBy)
+}
+
+// readKeys returns the keys read from f, until f is closed.
+func readKeys(f *os.File) <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 1)
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+	return keys
+}
+
 func collect(ctx context.Context, globalOptions types.GlobalCommandOptions, s *statsutil.Stats, waitFirst *sync.WaitGroup, id string, noStream bool) {
 	log.G(ctx).Debugf("collecting stats for %s", s.ID)
 	var (
@@ -401,25 +506,20 @@ func collect(ctx context.Context, globalOptions types.GlobalCommandOptions, s *s
 				continue
 			}
 
-			netNS, err := containerinspector.InspectNetNS(ctx, int(task.Pid()))
-			if err != nil {
-				u <- err
-				continue
-			}
-
 			// when (firstSet == true), we only set container stats without rendering stat entry
-			statsEntry, err := setContainerStatsAndRenderStatsEntry(previousStats, firstSet, anydata, int(task.Pid()), netNS.Interfaces, systemInfo)
+			statsEntry, err := setContainerStatsAndRenderStatsEntry(previousStats, firstSet, anydata, int(task.Pid()), systemInfo)
 			if err != nil {
 				u <- err
 				continue
 			}
 
 			if firstSet {
+				// the first sample is only the baseline of the CPU usage, so it is not reported
 				firstSet = false
 			} else {
 				s.SetStatistics(statsEntry)
+				u <- nil
 			}
-			u <- nil
 			// sleep to create distant CPU readings
 			time.Sleep(500 * time.Millisecond)
 		}
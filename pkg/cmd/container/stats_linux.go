@@ -21,19 +21,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vishvananda/netlink"
-	"github.com/vishvananda/netns"
 
 	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
 	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
 
-	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/statsutil"
 )
 
@@ -47,7 +44,7 @@ const (
 )
 
 //nolint:nakedret
-func setContainerStatsAndRenderStatsEntry(previousStats *statsutil.ContainerStats, firstSet bool, anydata interface{}, pid int, interfaces []native.NetInterface, systemInfo statsutil.SystemInfo) (statsEntry statsutil.StatsEntry, err error) {
+func setContainerStatsAndRenderStatsEntry(previousStats *statsutil.ContainerStats, firstSet bool, anydata interface{}, pid int, systemInfo statsutil.SystemInfo) (statsEntry statsutil.StatsEntry, err error) {
 
 	var (
 		data  *v1.Metrics
@@ -65,46 +62,11 @@ func setContainerStatsAndRenderStatsEntry(previousStats *statsutil.ContainerStat
 	}
 
 	var nlinks []netlink.Link
-
 	if !firstSet {
-		var (
-			nlink    netlink.Link
-			nlHandle *netlink.Handle
-			ns       netns.NsHandle
-		)
-
-		ns, err = netns.GetFromPid(pid)
-		if err != nil {
-			err = fmt.Errorf("failed to retrieve the statistics in netns %s: %v", ns, err)
-			return
-		}
-		defer func() {
-			err = ns.Close()
-		}()
-
-		nlHandle, err = netlink.NewHandleAt(ns)
+		nlinks, err = statsutil.NetworkLinks(pid)
 		if err != nil {
-			err = fmt.Errorf("failed to retrieve the statistics in netns %s: %v", ns, err)
 			return
 		}
-		defer nlHandle.Close()
-
-		for _, v := range interfaces {
-			nlink, err = nlHandle.LinkByIndex(v.Index)
-			if err != nil {
-				err = fmt.Errorf("failed to retrieve the statistics for %s in netns %s: %v", v.Name, ns, err)
-				return
-			}
-			//exclude inactive interface
-			if nlink.Attrs().Flags&net.FlagUp != 0 {
-
-				//exclude loopback interface
-				if nlink.Attrs().Flags&net.FlagLoopback != 0 || strings.HasPrefix(nlink.Attrs().Name, "lo") {
-					continue
-				}
-				nlinks = append(nlinks, nlink)
-			}
-		}
 	}
 
 	if data != nil {
@@ -118,10 +80,13 @@ func setContainerStatsAndRenderStatsEntry(previousStats *statsutil.ContainerStat
 		}
 	} else if data2 != nil {
 		if !firstSet {
+			if err = statsutil.CompleteCgroup2Metrics(data2, pid); err != nil {
+				return
+			}
 			statsEntry, err = statsutil.SetCgroup2StatsFields(previousStats, data2, nlinks)
 		}
-		previousStats.Cgroup2CPU = data2.CPU.UsageUsec * 1000
-		previousStats.Cgroup2System = data2.CPU.SystemUsec * 1000
+		previousStats.Cgroup2CPU = data2.GetCPU().GetUsageUsec() * 1000
+		previousStats.Cgroup2System = data2.GetCPU().GetSystemUsec() * 1000
 		if err != nil {
 			return
 		}
@@ -0,0 +1,161 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+const (
+	// usernsAuto is the `--userns` mode allocating a range of host IDs dedicated to the container
+	usernsAuto = "auto"
+	// usernsAutoUser is the user of /etc/subuid and /etc/subgid owning the ranges allocated by `--userns=auto`, like Podman
+	usernsAutoUser = "containers"
+	// usernsAutoDefaultSize is the default number of IDs allocated by `--userns=auto`
+	usernsAutoDefaultSize = 65536
+	// usernsAutoFile is the file in the state dir of the container, recording the IDs allocated to the container.
+	// The allocation is released when the state dir is removed with the container.
+	usernsAutoFile = "userns-auto.json"
+)
+
+// parseUsernsAuto parses `auto[:size=<SIZE>]`, and returns the number of IDs to allocate.
+func parseUsernsAuto(userns string) (size int, isAuto bool, _ error) {
+	opts, isAuto := strings.CutPrefix(userns, usernsAuto)
+	if !isAuto || (opts != "" && !strings.HasPrefix(opts, ":")) {
+		return 0, false, nil
+	}
+	size = usernsAutoDefaultSize
+	opts = strings.TrimPrefix(opts, ":")
+	if opts == "" {
+		return size, true, nil
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		k, v, _ := strings.Cut(opt, "=")
+		switch k {
+		case "size":
+			var err error
+			size, err = strconv.Atoi(v)
+			if err != nil || size <= 0 {
+				return 0, true, fmt.Errorf("invalid userns option %q", opt)
+			}
+		default:
+			return 0, true, fmt.Errorf("unknown userns option %q", opt)
+		}
+	}
+	return size, true, nil
+}
+
+// allocateIDRange allocates a range of size IDs from the available ranges of host IDs, not overlapping the used ranges.
+func allocateIDRange(available, used []IDMap, size int) (IDMap, error) {
+	used = slices.Clone(used)
+	slices.SortFunc(used, func(a, b IDMap) int {
+		return a.HostID - b.HostID
+	})
+	for _, r := range available {
+		start := r.HostID
+		for _, u := range used {
+			if u.HostID >= start+size {
+				break
+			}
+			if u.HostID+u.Size > start {
+				start = u.HostID + u.Size
+			}
+		}
+		if start+size <= r.HostID+r.Size {
+			return IDMap{ContainerID: 0, HostID: start, Size: size}, nil
+		}
+	}
+	return IDMap{}, fmt.Errorf("no free range of %d IDs is left for user %q in /etc/subuid or /etc/subgid", size, usernsAutoUser)
+}
+
+// allocateAutoIdentityMapping allocates the ranges of host UIDs and GIDs for `--userns=auto` to the container,
+// from the ranges of usernsAutoUser in /etc/subuid and /etc/subgid, not overlapping the ranges allocated to the other containers
+// of any namespace.
+func allocateAutoIdentityMapping(dataRoot, address, namespace, id string, size int) (IdentityMapping, error) {
+	available, err := LoadIdentityMapping(usernsAutoUser)
+	if err != nil {
+		return IdentityMapping{}, fmt.Errorf("failed to load the ranges of IDs for `--userns=auto` (Hint: add the user %q to /etc/subuid and /etc/subgid): %w", usernsAutoUser, err)
+	}
+	dataStore, err := clientutil.DataStore(dataRoot, address)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	stateDir, err := containerutil.ContainerStateDirPath(namespace, dataStore, id)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	lock, err := store.New(filepath.Join(dataStore, "userns"), 0, 0)
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+
+	var res IdentityMapping
+	err = lock.WithLock(func() error {
+		files, err := filepath.Glob(filepath.Join(dataStore, "containers", "*", "*", usernsAutoFile))
+		if err != nil {
+			return err
+		}
+		var used IdentityMapping
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return err
+			}
+			var m IdentityMapping
+			if err := json.Unmarshal(b, &m); err != nil {
+				log.L.WithError(err).Warnf("failed to parse %q", f)
+				continue
+			}
+			used.UIDMaps = append(used.UIDMaps, m.UIDMaps...)
+			used.GIDMaps = append(used.GIDMaps, m.GIDMaps...)
+		}
+		uidMap, err := allocateIDRange(available.UIDMaps, used.UIDMaps, size)
+		if err != nil {
+			return err
+		}
+		gidMap, err := allocateIDRange(available.GIDMaps, used.GIDMaps, size)
+		if err != nil {
+			return err
+		}
+		res = IdentityMapping{UIDMaps: []IDMap{uidMap}, GIDMaps: []IDMap{gidMap}}
+		b, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(stateDir, usernsAutoFile), b, 0o600)
+	})
+	if err != nil {
+		return IdentityMapping{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseUsernsAuto(t *testing.T) {
+	testCases := []struct {
+		userns string
+		size   int
+		isAuto bool
+		err    string
+	}{
+		{userns: "", isAuto: false},
+		{userns: "remapuser", isAuto: false},
+		{userns: "automaton", isAuto: false},
+		{userns: "auto", size: usernsAutoDefaultSize, isAuto: true},
+		{userns: "auto:size=1024", size: 1024, isAuto: true},
+		{userns: "auto:size=0", isAuto: true, err: "invalid userns option"},
+		{userns: "auto:foo=1", isAuto: true, err: "unknown userns option"},
+	}
+	for _, tc := range testCases {
+		size, isAuto, err := parseUsernsAuto(tc.userns)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.userns)
+			continue
+		}
+		assert.NilError(t, err, tc.userns)
+		assert.Equal(t, tc.isAuto, isAuto, tc.userns)
+		assert.Equal(t, tc.size, size, tc.userns)
+	}
+}
+
+func TestAllocateIDRange(t *testing.T) {
+	available := []IDMap{
+		{ContainerID: 0, HostID: 100000, Size: 200000},
+		{ContainerID: 200000, HostID: 500000, Size: 100000},
+	}
+
+	x, err := allocateIDRange(available, nil, 65536)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x, IDMap{ContainerID: 0, HostID: 100000, Size: 65536})
+
+	used := []IDMap{
+		{HostID: 165536, Size: 65536},
+		{HostID: 100000, Size: 65536},
+	}
+	x, err = allocateIDRange(available, used, 65536)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x, IDMap{ContainerID: 0, HostID: 231072, Size: 65536})
+
+	// the rest of the first range is too small
+	used = append(used, IDMap{HostID: 240000, Size: 1})
+	x, err = allocateIDRange(available, used, 65536)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x, IDMap{ContainerID: 0, HostID: 500000, Size: 65536})
+
+	_, err = allocateIDRange(available, used, 200000)
+	assert.ErrorContains(t, err, "no free range")
+}
@@ -0,0 +1,255 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+// DefaultName is the name of the built-in context, which uses nerdctl.toml, the environment variables, and the flags as they are.
+const DefaultName = "default"
+
+const defaultDescription = "Settings of nerdctl.toml and the environment variables"
+
+// Resolve returns the context with the given name.
+// An empty name stands for the context selected with `nerdctl context use`, or the default context if none is selected.
+func Resolve(dir, name string) (*contextstore.Context, error) {
+	st, err := contextstore.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		if name, err = st.Current(); err != nil {
+			return nil, err
+		}
+	}
+	if name == "" || name == DefaultName {
+		return &contextstore.Context{Name: DefaultName, Description: defaultDescription}, nil
+	}
+	return st.Get(name)
+}
+
+// Create creates a named context.
+func Create(options types.ContextCreateOptions) error {
+	if options.Name == DefaultName {
+		return fmt.Errorf("context name %q is reserved: %w", options.Name, errdefs.ErrInvalidArgument)
+	}
+	if sshutil.IsAddress(options.Address) {
+		if options.SSHHost != "" {
+			return fmt.Errorf("the SSH address %q cannot be used with the SSH host %q: %w", options.Address, options.SSHHost, errdefs.ErrInvalidArgument)
+		}
+		if _, err := sshutil.ParseAddress(options.Address); err != nil {
+			return err
+		}
+	} else if strings.Contains(options.Address, "://") && !strings.HasPrefix(options.Address, "unix://") {
+		return fmt.Errorf("invalid address %q", options.Address)
+	}
+	if options.Namespace != "" {
+		if err := store.IsFilesystemSafe(options.Namespace); err != nil {
+			return err
+		}
+	}
+	if options.SSHHost != "" {
+		if _, _, err := sshutil.ParseHost(options.SSHHost); err != nil {
+			return err
+		}
+	}
+	st, err := contextstore.New(options.Dir)
+	if err != nil {
+		return err
+	}
+	if err := st.Create(&contextstore.Context{
+		Name:        options.Name,
+		Description: options.Description,
+		Address:     options.Address,
+		Namespace:   options.Namespace,
+		Snapshotter: options.Snapshotter,
+		SSHHost:     options.SSHHost,
+	}); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(options.Stdout, options.Name)
+	return err
+}
+
+// Use selects the context to be used when neither `--context` nor $NERDCTL_CONTEXT is specified.
+func Use(options types.ContextUseOptions) error {
+	st, err := contextstore.New(options.Dir)
+	if err != nil {
+		return err
+	}
+	name := options.Name
+	if name == DefaultName {
+		name = ""
+	} else if _, err := st.Get(name); err != nil {
+		return err
+	}
+	if err := st.Use(name); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(options.Stdout, options.Name)
+	return err
+}
+
+// Remove removes named contexts.
+func Remove(names []string, options types.ContextRemoveOptions) error {
+	st, err := contextstore.New(options.Dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		if name == DefaultName {
+			errs = append(errs, fmt.Errorf("context %q is built-in and cannot be removed: %w", name, errdefs.ErrInvalidArgument))
+			continue
+		}
+		if err := st.Remove(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintln(options.Stdout, name)
+	}
+	return errors.Join(errs...)
+}
+
+// Inspect prints the contexts in JSON, or with the given Go template.
+func Inspect(names []string, options types.ContextInspectOptions) error {
+	var (
+		result []interface{}
+		errs   []error
+	)
+	for _, name := range names {
+		c, err := Resolve(options.Dir, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, c)
+	}
+	if len(result) > 0 {
+		if err := formatter.FormatSlice(options.Format, options.Stdout, result); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type contextListItem struct {
+	Name        string
+	Description string
+	Address     string
+	Namespace   string
+	Snapshotter string
+	SSHHost     string
+	Current     bool
+}
+
+// contextTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var contextTableHeaders = map[string]string{
+	"SSHHost": "SSH HOST",
+}
+
+// List lists the contexts, including the built-in default one.
+func List(options types.ContextListOptions) error {
+	st, err := contextstore.New(options.Dir)
+	if err != nil {
+		return err
+	}
+	contexts, err := st.List()
+	if err != nil {
+		return err
+	}
+	current := options.Current
+	if current == "" {
+		current = DefaultName
+	}
+	items := []contextListItem{{Name: DefaultName, Description: defaultDescription}}
+	for _, c := range contexts {
+		items = append(items, contextListItem{
+			Name:        c.Name,
+			Description: c.Description,
+			Address:     c.Address,
+			Namespace:   c.Namespace,
+			Snapshotter: c.Snapshotter,
+			SSHHost:     c.SSHHost,
+		})
+	}
+	for i := range items {
+		items[i].Current = items[i].Name == current
+	}
+
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		if !options.Quiet {
+			fmt.Fprintln(w, "NAME\tDESCRIPTION\tADDRESS\tNAMESPACE\tSSH HOST")
+		}
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		if options.Quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		if w, tmpl, err = formatter.ParseListTemplate(w, options.Format, contextTableHeaders, false); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, item); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		if options.Quiet {
+			if _, err := fmt.Fprintln(w, item.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		name := item.Name
+		if item.Current {
+			name += " *"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, item.Description, item.Address, item.Namespace, item.SSHHost); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
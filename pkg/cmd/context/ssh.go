@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// RunRemote runs nerdctl with args on the SSH host of the context, with the settings of the context prepended to args.
+// A non-zero exit status of the remote command is returned as an errutil.ExitCoder.
+func RunRemote(ctx context.Context, c *contextstore.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	destination, port, err := sshutil.ParseHost(c.SSHHost)
+	if err != nil {
+		return err
+	}
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return fmt.Errorf("ssh is needed for context %q: %w", c.Name, err)
+	}
+	var sshArgs []string
+	if port != "" {
+		sshArgs = append(sshArgs, "-p", port)
+	}
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		sshArgs = append(sshArgs, "-t")
+	}
+	sshArgs = append(sshArgs, "--", destination, remoteCommandLine(c, args))
+	log.G(ctx).Debugf("running %q with args %v", sshPath, sshArgs)
+
+	cmd := exec.CommandContext(ctx, sshPath, sshArgs...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errutil.NewExitCoderErr(exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// remoteCommandLine returns the shell command line of nerdctl on the remote host.
+// The settings of the context come before args, so that the flags in args take precedence.
+func remoteCommandLine(c *contextstore.Context, args []string) string {
+	words := []string{"nerdctl"}
+	if c.Address != "" {
+		words = append(words, "--address="+c.Address)
+	}
+	if c.Namespace != "" {
+		words = append(words, "--namespace="+c.Namespace)
+	}
+	if c.Snapshotter != "" {
+		words = append(words, "--snapshotter="+c.Snapshotter)
+	}
+	words = append(words, args...)
+	for i, w := range words {
+		words[i] = strutil.ShellQuote(w)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,32 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+func TestRemoteCommandLine(t *testing.T) {
+	c := &contextstore.Context{Name: "remote", Namespace: "k8s.io", SSHHost: "example.com"}
+	got := remoteCommandLine(c, []string{"run", "--rm", "alpine", "sh", "-c", "echo 'hello world' > /dev/null"})
+	assert.Equal(t, got, `nerdctl --namespace=k8s.io run --rm alpine sh -c 'echo '"'"'hello world'"'"' > /dev/null'`)
+	assert.Equal(t, remoteCommandLine(&contextstore.Context{}, []string{"ps", ""}), "nerdctl ps ''")
+}
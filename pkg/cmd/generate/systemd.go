@@ -0,0 +1,325 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package generate implements `nerdctl generate`.
+package generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+const (
+	systemdScopeSystem = "system"
+	systemdScopeUser   = "user"
+)
+
+// systemdUnit is a systemd service unit of a container.
+type systemdUnit struct {
+	// Name is the name of the unit, e.g., "container-foo.service"
+	Name        string
+	ContainerID string
+	// Description is the human-friendly name of the container
+	Description string
+	// Restart is the restart policy of the container (containerd.io/restart.policy)
+	Restart string
+	// Requires are the units of the containers that the container depends on
+	Requires []string
+	// After are the units of the containers that the container is ordered after
+	After []string
+}
+
+// Systemd writes systemd service units for the containers, or the containers of the compose projects.
+//
+// The units start the containers with `nerdctl start --attach` so that systemd supervises them,
+// and clear the restart policies of the containers, which are translated into the Restart= of the units.
+func Systemd(ctx context.Context, client *containerd.Client, targets []string, options types.GenerateSystemdOptions) error {
+	scope := options.Scope
+	if scope == "" {
+		scope = systemdScopeSystem
+		if rootlessutil.IsRootless() {
+			scope = systemdScopeUser
+		}
+	}
+	if scope != systemdScopeSystem && scope != systemdScopeUser {
+		return fmt.Errorf("invalid scope %q (supported values: %q, %q)", scope, systemdScopeSystem, systemdScopeUser)
+	}
+
+	var units []systemdUnit
+	for _, target := range targets {
+		u, err := targetUnits(ctx, client, target)
+		if err != nil {
+			return err
+		}
+		units = append(units, u...)
+	}
+
+	for _, u := range units {
+		content, err := renderSystemdUnit(u, scope, options)
+		if err != nil {
+			return err
+		}
+		if !options.Files {
+			fmt.Fprintf(options.Stdout, "# %s\n%s\n", u.Name, content)
+			continue
+		}
+		if err := os.WriteFile(u.Name, []byte(content), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(options.Stdout, u.Name)
+	}
+	if options.Files && len(units) > 0 {
+		systemctl := "systemctl"
+		if scope == systemdScopeUser {
+			systemctl = "systemctl --user"
+		}
+		dir := "/etc/systemd/system"
+		if scope == systemdScopeUser {
+			dir = "~/.config/systemd/user"
+		}
+		names := make([]string, len(units))
+		for i, u := range units {
+			names[i] = u.Name
+		}
+		log.G(ctx).Infof("Install the units into %s, then run `%s daemon-reload` and `%s enable --now %s`", dir, systemctl, systemctl, strings.Join(names, " "))
+		if scope == systemdScopeUser {
+			log.G(ctx).Info("Run `sudo loginctl enable-linger $(whoami)` to keep the containers running after logout")
+		}
+	}
+	return nil
+}
+
+// targetUnits returns the units of a container, or the units of the containers of a compose project.
+func targetUnits(ctx context.Context, client *containerd.Client, target string) ([]systemdUnit, error) {
+	var containers []containerd.Container
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			containers = append(containers, found.Container)
+			return nil
+		},
+	}
+	n, err := walker.Walk(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		containers, err = client.Containers(ctx, fmt.Sprintf("labels.%q==%s", labels.ComposeProject, target))
+		if err != nil {
+			return nil, err
+		}
+		if len(containers) == 0 {
+			return nil, fmt.Errorf("no such container or compose project: %s", target)
+		}
+	}
+
+	// the names of the units of the services of the compose project
+	serviceUnits := make(map[string][]string)
+	infos := make(map[string]map[string]string)
+	for _, c := range containers {
+		l, err := c.Labels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		infos[c.ID()] = l
+		if svc := l[labels.ComposeService]; svc != "" {
+			serviceUnits[svc] = append(serviceUnits[svc], unitName(c.ID(), l))
+		}
+	}
+
+	var units []systemdUnit
+	for _, c := range containers {
+		l := infos[c.ID()]
+		u := systemdUnit{
+			Name:        unitName(c.ID(), l),
+			ContainerID: c.ID(),
+			Description: l[labels.Name],
+			Restart:     l[restart.PolicyLabel],
+		}
+		if u.Description == "" {
+			u.Description = c.ID()
+		}
+		if n == 0 {
+			deps, err := parseComposeDependsOn(l[labels.ComposeDependsOn])
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range deps {
+				depUnits, ok := serviceUnits[d.service]
+				if !ok {
+					log.G(ctx).Warnf("service %q depends on service %q, which has no containers", l[labels.ComposeService], d.service)
+					continue
+				}
+				u.After = append(u.After, depUnits...)
+				if d.required {
+					u.Requires = append(u.Requires, depUnits...)
+				}
+			}
+		}
+		units = append(units, u)
+	}
+	return sortUnits(units)
+}
+
+// sortUnits orders the units of the dependencies before the units that depend on them,
+// and otherwise keeps the order of the units.
+func sortUnits(units []systemdUnit) ([]systemdUnit, error) {
+	sorted := make([]systemdUnit, 0, len(units))
+	done := make(map[string]bool, len(units))
+	for len(sorted) < len(units) {
+		n := len(sorted)
+		for _, u := range units {
+			if done[u.Name] || slices.ContainsFunc(u.After, func(after string) bool { return !done[after] }) {
+				continue
+			}
+			sorted = append(sorted, u)
+			done[u.Name] = true
+		}
+		if len(sorted) == n {
+			return nil, errors.New("the dependencies of the containers have a cycle")
+		}
+	}
+	return sorted, nil
+}
+
+func unitName(id string, l map[string]string) string {
+	if name := l[labels.Name]; name != "" {
+		return "container-" + name + ".service"
+	}
+	return "container-" + id[:12] + ".service"
+}
+
+type composeDependency struct {
+	service  string
+	required bool
+}
+
+// parseComposeDependsOn parses the value of labels.ComposeDependsOn, e.g., "db:service_started:false,cache:service_healthy:true".
+// The last field of each dependency (whether the dependent has to be restarted) is ignored,
+// and the dependencies are required unless the condition is "service_completed_successfully".
+func parseComposeDependsOn(s string) ([]composeDependency, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var deps []composeDependency
+	for _, dep := range strings.Split(s, ",") {
+		fields := strings.Split(dep, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid label %s=%q", labels.ComposeDependsOn, s)
+		}
+		deps = append(deps, composeDependency{
+			service:  fields[0],
+			required: fields[1] != "service_completed_successfully",
+		})
+	}
+	return deps, nil
+}
+
+// systemdRestart translates a restart policy of a container into Restart= and StartLimitBurst= of systemd.
+// startLimitBurst is 0 when unlimited.
+func systemdRestart(policy string) (systemdRestart string, startLimitBurst int, err error) {
+	if policy == "" {
+		return "no", 0, nil
+	}
+	rp, err := restart.NewPolicy(policy)
+	if err != nil {
+		return "", 0, err
+	}
+	switch rp.Name() {
+	case "no":
+		return "no", 0, nil
+	case "always", "unless-stopped":
+		// `systemctl stop` never restarts the unit, so unless-stopped is same as always
+		return "always", 0, nil
+	case "on-failure":
+		return "on-failure", rp.MaximumRetryCount(), nil
+	default:
+		return "", 0, fmt.Errorf("unsupported restart policy %q", policy)
+	}
+}
+
+func renderSystemdUnit(u systemdUnit, scope string, options types.GenerateSystemdOptions) (string, error) {
+	if options.NerdctlCmd == "" {
+		return "", errors.New("the command name of nerdctl is not specified")
+	}
+	restartValue, startLimitBurst, err := systemdRestart(u.Restart)
+	if err != nil {
+		return "", err
+	}
+	args := make([]string, 0, len(options.NerdctlArgs)+1)
+	for _, arg := range append([]string{options.NerdctlCmd}, options.NerdctlArgs...) {
+		args = append(args, systemdQuote(arg))
+	}
+	nerdctl := strings.Join(args, " ")
+	id := systemdQuote(u.ContainerID)
+
+	var b strings.Builder
+	b.WriteString("# Generated by `nerdctl generate systemd`\n\n")
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=nerdctl container %s\n", u.Description)
+	after := []string{"containerd.service"}
+	if scope == systemdScopeSystem {
+		b.WriteString("Wants=network-online.target\n")
+		after = append([]string{"network-online.target"}, after...)
+	}
+	fmt.Fprintf(&b, "After=%s\n", strings.Join(append(after, u.After...), " "))
+	fmt.Fprintf(&b, "Requires=%s\n", strings.Join(append([]string{"containerd.service"}, u.Requires...), " "))
+	if startLimitBurst > 0 {
+		fmt.Fprintf(&b, "StartLimitBurst=%d\n", startLimitBurst)
+	}
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	// the restart policy of the container is cleared, so that only systemd restarts the container
+	fmt.Fprintf(&b, "ExecStartPre=-%s update --restart=no %s\n", nerdctl, id)
+	fmt.Fprintf(&b, "ExecStart=%s start --attach %s\n", nerdctl, id)
+	fmt.Fprintf(&b, "ExecStop=%s stop --time=%d %s\n", nerdctl, options.StopTimeout, id)
+	fmt.Fprintf(&b, "TimeoutStopSec=%s\n", strconv.FormatUint(uint64(options.StopTimeout)+60, 10))
+	fmt.Fprintf(&b, "Restart=%s\n", restartValue)
+	b.WriteString("\n[Install]\n")
+	if scope == systemdScopeUser {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+	return b.String(), nil
+}
+
+// systemdQuote quotes s as an argument of the command lines of the units (see systemd.service(5)).
+// The specifiers ("%") and the environment variables ("$") are escaped, so that they are not expanded by systemd.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\;") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
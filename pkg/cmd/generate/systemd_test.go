@@ -0,0 +1,139 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestSystemdRestart(t *testing.T) {
+	testCases := []struct {
+		policy          string
+		restart         string
+		startLimitBurst int
+	}{
+		{"", "no", 0},
+		{"no", "no", 0},
+		{"always", "always", 0},
+		{"unless-stopped", "always", 0},
+		{"on-failure", "on-failure", 0},
+		{"on-failure:3", "on-failure", 3},
+	}
+	for _, tc := range testCases {
+		restart, startLimitBurst, err := systemdRestart(tc.policy)
+		assert.NilError(t, err, tc.policy)
+		assert.Equal(t, restart, tc.restart, tc.policy)
+		assert.Equal(t, startLimitBurst, tc.startLimitBurst, tc.policy)
+	}
+	_, _, err := systemdRestart("sometimes")
+	assert.ErrorContains(t, err, "")
+}
+
+func TestParseComposeDependsOn(t *testing.T) {
+	deps, err := parseComposeDependsOn("db:service_healthy:false,init:service_completed_successfully:false")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, deps, []composeDependency{
+		{service: "db", required: true},
+		{service: "init", required: false},
+	}, cmp.AllowUnexported(composeDependency{}))
+
+	deps, err = parseComposeDependsOn("")
+	assert.NilError(t, err)
+	assert.Equal(t, len(deps), 0)
+
+	_, err = parseComposeDependsOn("db")
+	assert.ErrorContains(t, err, "invalid label")
+}
+
+func TestSortUnits(t *testing.T) {
+	units := []systemdUnit{
+		{Name: "web", After: []string{"app"}},
+		{Name: "app", After: []string{"db", "cache"}},
+		{Name: "cache"},
+		{Name: "db", After: []string{"init"}},
+		{Name: "init"},
+	}
+	sorted, err := sortUnits(units)
+	assert.NilError(t, err)
+	var names []string
+	for _, u := range sorted {
+		names = append(names, u.Name)
+	}
+	assert.DeepEqual(t, names, []string{"cache", "init", "db", "app", "web"})
+
+	_, err = sortUnits([]systemdUnit{{Name: "a", After: []string{"b"}}, {Name: "b", After: []string{"a"}}})
+	assert.ErrorContains(t, err, "cycle")
+}
+
+func TestSystemdQuote(t *testing.T) {
+	testCases := map[string]string{
+		"/usr/local/bin/nerdctl": "/usr/local/bin/nerdctl",
+		"--namespace=foo":        "--namespace=foo",
+		"":                       `""`,
+		"--data-root=/my dir":    `"--data-root=/my dir"`,
+		`a"b\c`:                  `"a\"b\\c"`,
+		"100%":                   "100%%",
+		"$HOME":                  "$$HOME",
+		";":                      `";"`,
+	}
+	for s, expected := range testCases {
+		assert.Equal(t, systemdQuote(s), expected, s)
+	}
+}
+
+func TestRenderSystemdUnit(t *testing.T) {
+	u := systemdUnit{
+		Name:        "container-web.service",
+		ContainerID: "0123456789abcdef",
+		Description: "web",
+		Restart:     "on-failure:5",
+		Requires:    []string{"container-db.service"},
+		After:       []string{"container-db.service"},
+	}
+	options := types.GenerateSystemdOptions{
+		NerdctlCmd:  "/usr/local/bin/nerdctl",
+		NerdctlArgs: []string{"--namespace=foo"},
+		StopTimeout: 10,
+	}
+
+	content, err := renderSystemdUnit(u, systemdScopeUser, options)
+	assert.NilError(t, err)
+	for _, s := range []string{
+		"After=containerd.service container-db.service\n",
+		"Requires=containerd.service container-db.service\n",
+		"StartLimitBurst=5\n",
+		"ExecStartPre=-/usr/local/bin/nerdctl --namespace=foo update --restart=no 0123456789abcdef\n",
+		"ExecStart=/usr/local/bin/nerdctl --namespace=foo start --attach 0123456789abcdef\n",
+		"ExecStop=/usr/local/bin/nerdctl --namespace=foo stop --time=10 0123456789abcdef\n",
+		"Restart=on-failure\n",
+		"WantedBy=default.target\n",
+	} {
+		assert.Assert(t, strings.Contains(content, s), "%q not found in %s", s, content)
+	}
+	assert.Assert(t, !strings.Contains(content, "network-online.target"))
+
+	content, err = renderSystemdUnit(u, systemdScopeSystem, options)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(content, "After=network-online.target containerd.service container-db.service\n"))
+	assert.Assert(t, strings.Contains(content, "WantedBy=multi-user.target\n"))
+}
@@ -0,0 +1,252 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+const (
+	blobMissing     = "missing"
+	blobCorrupted   = "corrupted"
+	snapshotMissing = "missing snapshot"
+)
+
+type blobProblem struct {
+	status string
+	desc   ocispec.Descriptor
+}
+
+// Check verifies the integrity of the local content of images: every blob of the selected platforms
+// must be present in the content store, and must match its digest and size.
+// The snapshots of partially unpacked images are checked too.
+// With options.Repair, the corrupted blobs are removed, and the missing blobs and snapshots are fetched and unpacked again.
+func Check(ctx context.Context, client *containerd.Client, rawRefs []string, options types.ImageCheckOptions) error {
+	platMC, err := platformutil.NewMatchComparer(options.AllPlatforms, options.Platform)
+	if err != nil {
+		return err
+	}
+
+	var imgs []images.Image
+	if options.All {
+		imgs, err = client.ImageService().List(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		walker := &imagewalker.ImageWalker{
+			Client: client,
+			OnFound: func(ctx context.Context, found imagewalker.Found) error {
+				imgs = append(imgs, found.Image)
+				return nil
+			},
+		}
+		if err := walker.WalkAll(ctx, rawRefs, true); err != nil {
+			return err
+		}
+	}
+
+	var failed int
+	for _, img := range imgs {
+		problems, err := checkImage(ctx, client, img, platMC, options.GOptions.Snapshotter)
+		if err != nil {
+			return fmt.Errorf("failed to check image %q: %w", img.Name, err)
+		}
+		if len(problems) == 0 {
+			fmt.Fprintf(options.Stdout, "%s: OK\n", img.Name)
+			continue
+		}
+		for _, p := range problems {
+			if p.status == snapshotMissing {
+				fmt.Fprintf(options.Stdout, "%s: %s %s\n", img.Name, p.status, p.desc.Digest)
+			} else {
+				fmt.Fprintf(options.Stdout, "%s: %s %s (%s)\n", img.Name, p.status, p.desc.Digest, p.desc.MediaType)
+			}
+		}
+		if !options.Repair {
+			failed++
+			continue
+		}
+		if err := repairImage(ctx, client, img, problems, platMC, options.GOptions); err != nil {
+			log.G(ctx).WithError(err).Errorf("failed to repair image %q", img.Name)
+			failed++
+			continue
+		}
+		fmt.Fprintf(options.Stdout, "%s: repaired\n", img.Name)
+	}
+	if failed > 0 {
+		if options.Repair {
+			return fmt.Errorf("failed to repair %d image(s)", failed)
+		}
+		return fmt.Errorf("found missing or corrupted content in %d image(s)", failed)
+	}
+	return nil
+}
+
+// checkImage returns the problems of the content of img, and of its snapshots for the current platform.
+func checkImage(ctx context.Context, client *containerd.Client, img images.Image, platMC platforms.MatchComparer, snapshotter string) ([]blobProblem, error) {
+	problems, err := checkContent(ctx, client.ContentStore(), img.Target, platMC)
+	if err != nil || len(problems) > 0 {
+		return problems, err
+	}
+	return checkSnapshots(ctx, client, img, snapshotter)
+}
+
+// checkSnapshots returns the missing snapshots of an image that is only partially unpacked,
+// e.g. after a crashed pull. Images that are not unpacked at all are not reported.
+func checkSnapshots(ctx context.Context, client *containerd.Client, img images.Image, snapshotter string) ([]blobProblem, error) {
+	chainIDs, err := imageChainIDs(ctx, client, img)
+	if err != nil {
+		// The image may not have the current platform
+		log.G(ctx).WithError(err).Debugf("skipping checking the snapshots of image %q", img.Name)
+		return nil, nil
+	}
+	sn := client.SnapshotService(snapshotter)
+	var (
+		problems []blobProblem
+		found    bool
+	)
+	for _, id := range chainIDs {
+		if _, err := sn.Stat(ctx, id); err != nil {
+			if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+			problems = append(problems, blobProblem{status: snapshotMissing, desc: ocispec.Descriptor{Digest: digest.Digest(id)}})
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return problems, nil
+}
+
+// repairImage removes the corrupted blobs of img, fetches the missing ones from the registry
+// (by digest, so that the content matches even if the tag was updated), and unpacks the image again.
+func repairImage(ctx context.Context, client *containerd.Client, img images.Image, problems []blobProblem, platMC platforms.MatchComparer, globalOptions types.GlobalCommandOptions) error {
+	cs := client.ContentStore()
+	for _, p := range problems {
+		if p.status == blobCorrupted {
+			log.G(ctx).Debugf("removing corrupted blob %s", p.desc.Digest)
+			if err := cs.Delete(ctx, p.desc.Digest); err != nil && !errdefs.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	parsedReference, err := referenceutil.Parse(img.Name)
+	if err != nil {
+		return err
+	}
+	ref := fmt.Sprintf("%s@%s", parsedReference.Name(), img.Target.Digest)
+	if _, err := cs.Info(ctx, img.Target.Digest); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		// The platforms of the image cannot be read without its root blob, so fetch it first
+		if err := ensureOne(ctx, client, ref, img.Target, platforms.DefaultSpec(), globalOptions); err != nil {
+			return err
+		}
+	}
+	if err := ensureAllContent(ctx, client, ref, img.Target, platMC, globalOptions); err != nil {
+		return err
+	}
+	if problems, err := checkContent(ctx, cs, img.Target, platMC); err != nil {
+		return err
+	} else if len(problems) > 0 {
+		return fmt.Errorf("%s %s is still %s after fetching", problems[0].desc.MediaType, problems[0].desc.Digest, problems[0].status)
+	}
+
+	// Only unpack images for which there was at least a snapshot
+	if snapshotProblems, err := checkSnapshots(ctx, client, img, globalOptions.Snapshotter); err != nil || len(snapshotProblems) == 0 {
+		return err
+	}
+	return containerd.NewImageWithPlatform(client, img, platforms.DefaultStrict()).Unpack(ctx, globalOptions.Snapshotter)
+}
+
+// checkContent walks the blobs referenced by target for the platforms matched by platMC, and returns the ones that are missing or corrupted.
+func checkContent(ctx context.Context, cs content.Store, target ocispec.Descriptor, platMC platforms.MatchComparer) ([]blobProblem, error) {
+	var problems []blobProblem
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		status, err := verifyBlob(ctx, cs, desc)
+		if err != nil {
+			return nil, err
+		}
+		if status != "" {
+			problems = append(problems, blobProblem{status: status, desc: desc})
+			return nil, images.ErrSkipDesc
+		}
+		return images.Children(ctx, cs, desc)
+	})
+	if err := images.Walk(ctx, images.FilterPlatforms(handler, platMC), target); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// verifyBlob returns blobMissing or blobCorrupted when the blob of desc is missing or does not match desc,
+// and an empty string when the blob is intact.
+func verifyBlob(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (string, error) {
+	info, err := cs.Info(ctx, desc.Digest)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return blobMissing, nil
+		}
+		return "", err
+	}
+	if info.Size != desc.Size {
+		return blobCorrupted, nil
+	}
+	ra, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return blobMissing, nil
+		}
+		return "", err
+	}
+	defer ra.Close()
+	verifier := desc.Digest.Verifier()
+	if _, err := io.Copy(verifier, content.NewReader(ra)); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return blobCorrupted, nil
+		}
+		return "", err
+	}
+	if !verifier.Verified() {
+		return blobCorrupted, nil
+	}
+	return "", nil
+}
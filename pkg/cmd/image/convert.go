@@ -83,14 +83,42 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 		return err
 	}
 
+	if options.Docker {
+		if options.Oci {
+			return errors.New("option --oci conflicts with Docker media types")
+		}
+		if options.Estargz || options.ZstdChunked || options.Overlaybd || options.Nydus || options.Erofs || options.Soci {
+			return errors.New("options --estargz, --zstdchunked, --overlaybd, --nydus, --erofs and --soci require OCI media types, and cannot be used with Docker media types")
+		}
+	}
+
 	estargz := options.Estargz
 	zstd := options.Zstd
 	zstdchunked := options.ZstdChunked
 	overlaybd := options.Overlaybd
 	nydus := options.Nydus
+	erofs := options.Erofs
 	soci := options.Soci
 	var finalize func(ctx context.Context, cs content.Store, ref string, desc *ocispec.Descriptor) (*images.Image, error)
-	if estargz || zstd || zstdchunked || overlaybd || nydus || soci {
+	var layerConvertFunc converter.ConvertFunc
+	var layerCache *converterutil.LayerCache
+	// wrapLayerConvertFunc limits the parallelism of the layer conversion, and makes it resumable
+	wrapLayerConvertFunc := func(convertType string, convertOptions any, fn converter.ConvertFunc) (converter.ConvertFunc, error) {
+		fn = converterutil.LayerConvertFuncWithParallelism(fn, options.Parallelism)
+		if finalize != nil {
+			// The finalizer of estargz-external-toc needs the state of every layer conversion
+			return fn, nil
+		}
+		layerCache, err = converterutil.NewLayerCache(convertType, convertOptions)
+		if err != nil {
+			return nil, err
+		}
+		return layerCache.LayerConvertFunc(fn), nil
+	}
+	if options.Parallelism < 0 {
+		return fmt.Errorf("invalid parallelism %d", options.Parallelism)
+	}
+	if estargz || zstd || zstdchunked || overlaybd || nydus || erofs || soci {
 		convertCount := 0
 		if estargz {
 			convertCount++
@@ -107,12 +135,15 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 		if nydus {
 			convertCount++
 		}
+		if erofs {
+			convertCount++
+		}
 		if soci {
 			convertCount++
 		}
 
 		if convertCount > 1 {
-			return errors.New("options --estargz, --zstdchunked, --overlaybd, --nydus and --soci lead to conflict, only one of them can be used")
+			return errors.New("options --estargz, --zstdchunked, --overlaybd, --nydus, --erofs and --soci lead to conflict, only one of them can be used")
 		}
 
 		var convertFunc converter.ConvertFunc
@@ -161,15 +192,25 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 					OCI:              true,
 				}),
 			}
+			nydusLayerConvertFunc, err := wrapLayerConvertFunc("nydus", options.NydusOptions, nydusconvert.LayerConvertFunc(*nydusOpts))
+			if err != nil {
+				return err
+			}
 			convertOpts = append(convertOpts, converter.WithIndexConvertFunc(
 				converter.IndexConvertFuncWithHook(
-					nydusconvert.LayerConvertFunc(*nydusOpts),
+					nydusLayerConvertFunc,
 					true,
 					platMC,
 					convertHooks,
 				)),
 			)
 			convertType = "nydus"
+		case erofs:
+			convertFunc, err = converterutil.ErofsLayerConvertFunc(options)
+			if err != nil {
+				return err
+			}
+			convertType = "erofs"
 		case soci:
 			// Convert image to SOCI format
 			convertedRef, err := snapshotterutil.ConvertSociIndexV2(ctx, client, srcRef, targetRef, options.GOptions, options.SociOptions)
@@ -182,12 +223,21 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 			return printConvertedImage(options.Stdout, options, res)
 		}
 
-		if convertType != "overlaybd" {
+		if convertType != "overlaybd" && convertType != "nydus" {
+			convertFunc, err = wrapLayerConvertFunc(convertType, convertTypeOptions(convertType, options), convertFunc)
+			if err != nil {
+				return err
+			}
+			layerConvertFunc = convertFunc
 			convertOpts = append(convertOpts, converter.WithLayerConvertFunc(convertFunc))
 		}
-		if !options.Oci {
-			if nydus || overlaybd {
+		if !options.Oci && !options.Docker {
+			if nydus || overlaybd || erofs {
 				log.G(ctx).Warnf("option --%s should be used in conjunction with --oci, forcibly enabling on oci mediatype for %s conversion", convertType, convertType)
+				// Unlike nydus and overlaybd, EROFS layers are produced by the default index converter
+				if erofs {
+					options.Oci = true
+				}
 			} else {
 				log.G(ctx).Warnf("option --%s should be used in conjunction with --oci", convertType)
 			}
@@ -198,18 +248,41 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 	}
 
 	if options.Uncompress {
-		convertOpts = append(convertOpts, converter.WithLayerConvertFunc(uncompress.LayerConvertFunc))
+		layerConvertFunc, err = wrapLayerConvertFunc("uncompress", nil, uncompress.LayerConvertFunc)
+		if err != nil {
+			return err
+		}
+		convertOpts = append(convertOpts, converter.WithLayerConvertFunc(layerConvertFunc))
 	}
 
 	if options.Oci {
 		convertOpts = append(convertOpts, converter.WithDockerToOCI(true))
 	}
 
+	if options.Docker {
+		convertOpts = append(convertOpts, converter.WithIndexConvertFunc(
+			converter.IndexConvertFuncWithHook(
+				layerConvertFunc,
+				false,
+				platMC,
+				converter.ConvertHooks{PostConvertHook: converterutil.OCIToDockerConvertHookFunc()},
+			)),
+		)
+	}
+
 	// converter.Convert() gains the lease by itself
 	newImg, err := converterutil.Convert(ctx, client, targetRef, srcRef, convertOpts...)
 	if err != nil {
+		if layerCache != nil {
+			log.G(ctx).Info("the converted layers are kept; run the same command again to resume the conversion")
+		}
 		return err
 	}
+	if layerCache != nil {
+		if err := layerCache.Clear(ctx, client.ContentStore()); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to clear the conversion cache")
+		}
+	}
 	res := converterutil.ConvertedImageInfo{
 		Image: newImg.Name + "@" + newImg.Target.Digest.String(),
 	}
@@ -233,6 +306,21 @@ func Convert(ctx context.Context, client *containerd.Client, srcRawRef, targetRa
 	return printConvertedImage(options.Stdout, options, res)
 }
 
+// convertTypeOptions returns the options that affect the layers converted to convertType.
+func convertTypeOptions(convertType string, options types.ImageConvertOptions) any {
+	switch convertType {
+	case "estargz":
+		return options.EstargzOptions
+	case "zstd":
+		return options.ZstdOptions
+	case "zstdchunked":
+		return options.ZstdChunkedOptions
+	case "erofs":
+		return options.ErofsOptions
+	}
+	return nil
+}
+
 func getESGZConverter(options types.ImageConvertOptions) (convertFunc converter.ConvertFunc, finalize func(ctx context.Context, cs content.Store, ref string, desc *ocispec.Descriptor) (*images.Image, error), _ error) {
 	if options.EstargzExternalToc && !options.GOptions.Experimental {
 		return nil, nil, fmt.Errorf("estargz-external-toc requires experimental mode to be enabled")
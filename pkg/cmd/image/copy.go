@@ -0,0 +1,244 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// Copy copies an image from a registry to another, streaming the blobs without storing them in the local content store.
+func Copy(ctx context.Context, rawSrc, rawDst string, options types.ImageCopyOptions) error {
+	src, err := referenceutil.Parse(rawSrc)
+	if err != nil {
+		return err
+	}
+	dst, err := referenceutil.Parse(rawDst)
+	if err != nil {
+		return err
+	}
+	if src.Protocol != "" || dst.Protocol != "" {
+		return errors.New("copy does not support IPFS references")
+	}
+
+	srcResolver, err := copyResolver(ctx, src.Domain, src.String(), options.GOptions)
+	if err != nil {
+		return err
+	}
+	name, desc, err := srcResolver.Resolve(ctx, src.String())
+	if err != nil {
+		return fmt.Errorf("failed to resolve %q: %w", src.String(), err)
+	}
+	fetcher, err := srcResolver.Fetcher(ctx, name)
+	if err != nil {
+		return err
+	}
+	provider := &remoteProvider{fetcher: fetcher}
+
+	platMC := platforms.All
+	if !options.AllPlatforms {
+		platMC = platforms.DefaultStrict()
+		if options.Platform != "" {
+			platMC, err = platformutil.NewMatchComparer(false, []string{options.Platform})
+			if err != nil {
+				return err
+			}
+		}
+		// Pushing the index would fail as the manifests of the other platforms are not copied,
+		// so the manifest of the platform becomes the root of the copied image.
+		desc, err = platformManifest(ctx, provider, desc, platMC)
+		if err != nil {
+			return err
+		}
+	}
+
+	dstResolver, err := copyResolver(ctx, dst.Domain, dst.String(), options.GOptions)
+	if err != nil {
+		return err
+	}
+	pusher, err := dstResolver.Pusher(ctx, dst.String())
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).Debugf("copying %s (%s) to %s", src.String(), desc.Digest, dst.String())
+	var wrapper func(images.Handler) images.Handler
+	if !options.Quiet {
+		wrapper = func(h images.Handler) images.Handler {
+			return images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+				if !images.IsManifestType(desc.MediaType) && !images.IsIndexType(desc.MediaType) {
+					fmt.Fprintf(options.Stdout, "Copying blob %s\n", desc.Digest)
+				}
+				return h.Handle(ctx, desc)
+			})
+		}
+	}
+	if err := remotes.PushContent(ctx, pusher, desc, provider, nil, platMC, wrapper); err != nil {
+		return fmt.Errorf("failed to copy %q to %q: %w", src.String(), dst.String(), err)
+	}
+	if options.Quiet {
+		fmt.Fprintln(options.Stdout, desc.Digest)
+	} else {
+		fmt.Fprintf(options.Stdout, "Copied %s to %s (%s)\n", src.String(), dst.String(), desc.Digest)
+	}
+	return nil
+}
+
+// copyResolver returns a resolver for the registry `domain`, using the credentials of `nerdctl login`.
+// The resolver falls back to plain HTTP for registries not supporting HTTPS when --insecure-registry is set.
+func copyResolver(ctx context.Context, domain, ref string, globalOptions types.GlobalCommandOptions) (remotes.Resolver, error) {
+	var dOpts []dockerconfigresolver.Opt
+	if globalOptions.InsecureRegistry {
+		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", domain)
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(globalOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(globalOptions.Registries))
+	dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!globalOptions.NoTokenCache))
+	resolver, err := dockerconfigresolver.New(ctx, domain, dOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if !globalOptions.InsecureRegistry {
+		return resolver, nil
+	}
+	if _, _, err = resolver.Resolve(ctx, ref); err == nil || (!errors.Is(err, http.ErrSchemeMismatch) && !errutil.IsErrConnectionRefused(err)) {
+		return resolver, nil
+	}
+	log.G(ctx).WithError(err).Warnf("server %q does not seem to support HTTPS, falling back to plain HTTP", domain)
+	dOpts = append(dOpts, dockerconfigresolver.WithPlainHTTP(true))
+	return dockerconfigresolver.New(ctx, domain, dOpts...)
+}
+
+// platformManifest returns the manifest of desc that best matches platMC.
+func platformManifest(ctx context.Context, provider content.Provider, desc ocispec.Descriptor, platMC platforms.MatchComparer) (ocispec.Descriptor, error) {
+	for images.IsIndexType(desc.MediaType) {
+		b, err := content.ReadBlob(ctx, provider, desc)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		var idx ocispec.Index
+		if err := json.Unmarshal(b, &idx); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		var candidates []ocispec.Descriptor
+		for _, m := range idx.Manifests {
+			if m.Platform == nil || platMC.Match(*m.Platform) {
+				candidates = append(candidates, m)
+			}
+		}
+		if len(candidates) == 0 {
+			return ocispec.Descriptor{}, fmt.Errorf("no manifest found for the platform: %w", errdefs.ErrNotFound)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Platform == nil {
+				return false
+			}
+			if candidates[j].Platform == nil {
+				return true
+			}
+			return platMC.Less(*candidates[i].Platform, *candidates[j].Platform)
+		})
+		desc = candidates[0]
+	}
+	return desc, nil
+}
+
+// remoteProvider is a content.Provider that streams the blobs from a registry.
+type remoteProvider struct {
+	fetcher remotes.Fetcher
+}
+
+func (p *remoteProvider) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	rc, err := p.fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteReaderAt{ctx: ctx, fetcher: p.fetcher, desc: desc, rc: rc}, nil
+}
+
+// remoteReaderAt implements content.ReaderAt for sequential reads on top of a fetched stream.
+// Reading at another offset seeks the stream if possible, or fetches the blob again.
+type remoteReaderAt struct {
+	ctx     context.Context
+	fetcher remotes.Fetcher
+	desc    ocispec.Descriptor
+	rc      io.ReadCloser
+	offset  int64
+}
+
+func (r *remoteReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off != r.offset {
+		if err := r.seek(off); err != nil {
+			return 0, err
+		}
+	}
+	n, err := io.ReadFull(r.rc, p)
+	r.offset += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (r *remoteReaderAt) seek(off int64) error {
+	if s, ok := r.rc.(io.Seeker); ok {
+		if _, err := s.Seek(off, io.SeekStart); err == nil {
+			r.offset = off
+			return nil
+		}
+	}
+	if off < r.offset {
+		r.rc.Close()
+		rc, err := r.fetcher.Fetch(r.ctx, r.desc)
+		if err != nil {
+			return err
+		}
+		r.rc, r.offset = rc, 0
+	}
+	n, err := io.CopyN(io.Discard, r.rc, off-r.offset)
+	r.offset += n
+	return err
+}
+
+func (r *remoteReaderAt) Size() int64 {
+	return r.desc.Size
+}
+
+func (r *remoteReaderAt) Close() error {
+	return r.rc.Close()
+}
@@ -0,0 +1,261 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+)
+
+// LayerDiskUsage is the disk usage of a layer of images.
+type LayerDiskUsage struct {
+	ID string
+	// Size of the snapshot of the layer, not including its parents
+	Size int64
+	// Number of images referencing the layer
+	Images int
+}
+
+type imageDiskUsage struct {
+	Repository string
+	Tag        string
+	ID         string
+	// Size is the sum of the sizes of the layers of the image
+	Size int64
+	// SharedSize is the size of the layers that are also used by other images
+	SharedSize int64
+	// UniqueSize is the size of the layers only used by this image, i.e., the space reclaimable by removing it
+	UniqueSize int64
+	Layers     []LayerDiskUsage
+}
+
+// TargetDiskUsage is the disk usage of the images sharing the same target.
+type TargetDiskUsage struct {
+	Target digest.Digest
+	// Names of the images, sorted
+	Names []string
+	// CreatedAt is the creation time of the oldest image
+	CreatedAt time.Time
+	// ChainIDs of the layers, from the bottom layer up
+	ChainIDs []string
+}
+
+// LayersDiskUsage returns the images grouped by target, and the disk usage of their layers by chain ID.
+// Images sharing the same target are the same image with different names, so their layers are only counted once.
+func LayersDiskUsage(ctx context.Context, client *containerd.Client, snapshotter string) ([]*TargetDiskUsage, map[string]*LayerDiskUsage, error) {
+	imageList, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sn := client.SnapshotService(snapshotter)
+	var (
+		targets    []*TargetDiskUsage
+		byTarget   = make(map[digest.Digest]*TargetDiskUsage)
+		layerUsage = make(map[string]*LayerDiskUsage)
+	)
+	for _, img := range imageList {
+		if t, ok := byTarget[img.Target.Digest]; ok {
+			t.Names = append(t.Names, img.Name)
+			if img.CreatedAt.Before(t.CreatedAt) {
+				t.CreatedAt = img.CreatedAt
+			}
+			continue
+		}
+		chainIDs, err := imageChainIDs(ctx, client, img)
+		if err != nil {
+			log.G(ctx).WithError(err).Debugf("failed to get the layers of image %q", img.Name)
+		}
+		t := &TargetDiskUsage{
+			Target:    img.Target.Digest,
+			Names:     []string{img.Name},
+			CreatedAt: img.CreatedAt,
+			ChainIDs:  chainIDs,
+		}
+		targets = append(targets, t)
+		byTarget[img.Target.Digest] = t
+		for _, id := range chainIDs {
+			if l, ok := layerUsage[id]; ok {
+				l.Images++
+				continue
+			}
+			usage, err := sn.Usage(ctx, id)
+			if err != nil && !errdefs.IsNotFound(err) {
+				return nil, nil, err
+			}
+			layerUsage[id] = &LayerDiskUsage{ID: id, Size: usage.Size, Images: 1}
+		}
+	}
+	for _, t := range targets {
+		sort.Strings(t.Names)
+	}
+	return targets, layerUsage, nil
+}
+
+// DiskUsage prints the unique and shared sizes of images and of their layers, based on the usage reported by the snapshotter.
+// The size of a layer is shared when it is referenced by more than one image.
+func DiskUsage(ctx context.Context, client *containerd.Client, options types.ImageDiskUsageOptions) error {
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	selected, err := selectDiskUsageImages(ctx, client, options.Images)
+	if err != nil {
+		return err
+	}
+	targets, layerUsage, err := LayersDiskUsage(ctx, client, options.GOptions.Snapshotter)
+	if err != nil {
+		return err
+	}
+
+	var w = options.Stdout
+	if tmpl == nil {
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tSIZE\tSHARED SIZE\tUNIQUE SIZE")
+	}
+	for _, target := range targets {
+		if selected != nil {
+			if _, ok := selected[target.Target]; !ok {
+				continue
+			}
+		}
+		for _, name := range target.Names {
+			du := imageDiskUsage{
+				ID: target.Target.String(),
+			}
+			if target.Target.String() != name {
+				du.Repository, du.Tag = imgutil.ParseRepoTag(name)
+			}
+			if du.Repository == "" {
+				du.Repository = "<none>"
+			}
+			if du.Tag == "" {
+				du.Tag = "<none>"
+			}
+			for _, id := range target.ChainIDs {
+				l := *layerUsage[id]
+				du.Size += l.Size
+				if l.Images > 1 {
+					du.SharedSize += l.Size
+				} else {
+					du.UniqueSize += l.Size
+				}
+				du.Layers = append(du.Layers, l)
+			}
+			if err := printImageDiskUsage(w, tmpl, du, options.Verbose); err != nil {
+				return err
+			}
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func printImageDiskUsage(w io.Writer, tmpl *template.Template, du imageDiskUsage, verbose bool) error {
+	if tmpl != nil {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, du); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, b.String())
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", du.Repository, du.Tag, strings.Split(du.ID, ":")[1][:12],
+		units.HumanSize(float64(du.Size)), units.HumanSize(float64(du.SharedSize)), units.HumanSize(float64(du.UniqueSize))); err != nil {
+		return err
+	}
+	if !verbose {
+		return nil
+	}
+	for _, l := range du.Layers {
+		shared, unique := l.Size, int64(0)
+		if l.Images == 1 {
+			shared, unique = 0, l.Size
+		}
+		if _, err := fmt.Fprintf(w, "  └─ %s\t\t\t%s\t%s\t%s\n", strings.Split(l.ID, ":")[1][:12],
+			units.HumanSize(float64(l.Size)), units.HumanSize(float64(shared)), units.HumanSize(float64(unique))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// imageChainIDs returns the chain IDs of the layers of the default platform of img, from the bottom layer up.
+func imageChainIDs(ctx context.Context, client *containerd.Client, img images.Image) ([]string, error) {
+	diffIDs, err := containerd.NewImageWithPlatform(client, img, platforms.DefaultStrict()).RootFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := identity.ChainIDs(diffIDs)
+	ids := make([]string, len(chainIDs))
+	for i, id := range chainIDs {
+		ids[i] = id.String()
+	}
+	return ids, nil
+}
+
+// selectDiskUsageImages returns the targets of the images matching reqs, or nil when reqs is empty.
+func selectDiskUsageImages(ctx context.Context, client *containerd.Client, reqs []string) (map[digest.Digest]struct{}, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	selected := make(map[digest.Digest]struct{})
+	walker := &imagewalker.ImageWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found imagewalker.Found) error {
+			selected[found.Image.Target.Digest] = struct{}{}
+			return nil
+		},
+	}
+	if err := walker.WalkAll(ctx, reqs, false); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/fetch"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 )
@@ -45,15 +46,19 @@ func EnsureAllContent(ctx context.Context, client *containerd.Client, srcName st
 	if err != nil {
 		return err
 	}
+	return ensureAllContent(ctx, client, srcName, img.Target, platMC, options)
+}
 
+// ensureAllContent fetches the missing content of target for the platforms matched by platMC from `rawRef`.
+func ensureAllContent(ctx context.Context, client *containerd.Client, rawRef string, target ocispec.Descriptor, platMC platforms.MatchComparer, options types.GlobalCommandOptions) error {
 	provider := containerdutil.NewProvider(client)
 	snapshotter := containerdutil.SnapshotService(client, options.Snapshotter)
 	// Read the image
-	imagesList, _ := read(ctx, provider, snapshotter, img.Target)
+	imagesList, _ := read(ctx, provider, snapshotter, target)
 	// Iterate through the list
 	for _, i := range imagesList {
 		if platMC.Match(i.platform) {
-			err = ensureOne(ctx, client, srcName, img.Target, i.platform, options)
+			err := ensureOne(ctx, client, rawRef, target, i.platform, options)
 			if err != nil {
 				return err
 			}
@@ -84,6 +89,8 @@ func ensureOne(ctx context.Context, client *containerd.Client, rawRef string, ta
 			dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 		}
 		dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(options.HostsDir))
+		dOpts = append(dOpts, dockerconfigresolver.WithRegistries(options.Registries))
+		dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!options.NoTokenCache))
 		resolver, err := dockerconfigresolver.New(ctx, parsedReference.Domain, dOpts...)
 		if err != nil {
 			return err
@@ -93,6 +100,10 @@ func ensureOne(ctx context.Context, client *containerd.Client, rawRef string, ta
 			RemoteOpts:     []containerd.RemoteOpt{},
 			Platforms:      pltf,
 			ProgressOutput: os.Stderr,
+			ProgressMode:   options.Progress,
+		}
+		if options.Progress == jobs.ProgressQuiet {
+			config.ProgressOutput = nil
 		}
 
 		err = fetch.Fetch(ctx, client, rawRef, config)
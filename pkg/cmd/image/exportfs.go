@@ -0,0 +1,107 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+)
+
+// exportFSTools maps the supported filesystem formats to the binaries used to build them.
+var exportFSTools = map[string]string{
+	"squashfs": "mksquashfs",
+	"erofs":    "mkfs.erofs",
+}
+
+// ExportFS flattens the rootfs of an image into a squashfs or erofs filesystem image.
+func ExportFS(ctx context.Context, client *containerd.Client, rawRef string, options types.ImageExportFSOptions) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("export-fs command is only supported on Linux")
+	}
+	tool, ok := exportFSTools[options.Format]
+	if !ok {
+		return fmt.Errorf("unsupported filesystem format %q (supported: squashfs, erofs)", options.Format)
+	}
+	toolPath, err := exec.LookPath(tool)
+	if err != nil {
+		return fmt.Errorf("%s is required for --format=%s: %w", tool, options.Format, err)
+	}
+	if options.Output == "" {
+		return errors.New("output path must be specified")
+	}
+
+	platMC := platforms.DefaultStrict()
+	if options.Platform != "" {
+		platMC, err = platformutil.NewMatchComparer(false, []string{options.Platform})
+		if err != nil {
+			return err
+		}
+	}
+
+	srcName, err := resolveImageName(ctx, client, rawRef)
+	if err != nil {
+		return err
+	}
+
+	// Hold a lease so that the view snapshot is not garbage collected while exporting
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID())
+	if err != nil {
+		return fmt.Errorf("failed to create lease: %w", err)
+	}
+	defer done(ctx)
+
+	imgRecord, err := client.ImageService().Get(ctx, srcName)
+	if err != nil {
+		return err
+	}
+	img := containerd.NewImageWithPlatform(client, imgRecord, platMC)
+
+	output, err := filepath.Abs(options.Output)
+	if err != nil {
+		return err
+	}
+	return withImageRootfs(ctx, client, img, options.GOptions.Snapshotter, func(root string) error {
+		var args []string
+		switch options.Format {
+		case "squashfs":
+			args = []string{root, output, "-noappend", "-no-progress"}
+		case "erofs":
+			args = []string{output, root}
+		}
+		cmd := exec.CommandContext(ctx, toolPath, args...)
+		log.G(ctx).Debugf("Running %v", cmd.Args)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			os.Remove(output)
+			return fmt.Errorf("failed to run %s: %w: %s", tool, err, string(out))
+		}
+		fmt.Fprintln(options.Stdout, output)
+		return nil
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/opencontainers/go-digest"
@@ -41,6 +42,7 @@ import (
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/dockerfileutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 )
 
@@ -71,58 +73,10 @@ func importRootfs(ctx context.Context, client *containerd.Client, snapshotter st
 	defer decomp.Close()
 
 	cs := client.ContentStore()
-
-	ref := randomRef("import-rootfs-")
-	w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+	layerDesc, diffID, err := WriteGzipLayer(ctx, cs, "import-rootfs-", decomp)
 	if err != nil {
 		return zero, err
 	}
-	defer w.Close()
-	if err := w.Truncate(0); err != nil {
-		return zero, err
-	}
-
-	digester := digest.Canonical.Digester()
-	tee := io.TeeReader(decomp, digester.Hash())
-	pr, pw := io.Pipe()
-	gz := gzip.NewWriter(pw)
-	doneCh := make(chan error, 1)
-	go func() {
-		_, err := io.Copy(gz, tee)
-		if err != nil {
-			doneCh <- err
-			_ = gz.Close()
-			_ = pw.CloseWithError(err)
-			return
-		}
-		if err := gz.Close(); err != nil {
-			doneCh <- err
-			_ = pw.CloseWithError(err)
-			return
-		}
-		doneCh <- pw.Close()
-	}()
-
-	n, err := io.Copy(w, pr)
-	if err != nil {
-		return zero, err
-	}
-	if err := <-doneCh; err != nil {
-		return zero, err
-	}
-
-	diffID := digester.Digest()
-	labels := map[string]string{
-		"containerd.io/uncompressed": diffID.String(),
-	}
-	if err := w.Commit(ctx, n, "", content.WithLabels(labels)); err != nil && !errdefs.IsAlreadyExists(err) {
-		return zero, err
-	}
-	layerDesc := ocispec.Descriptor{
-		MediaType: images.MediaTypeDockerSchema2LayerGzip,
-		Digest:    w.Digest(),
-		Size:      n,
-	}
 
 	ociplat := platforms.DefaultSpec()
 	if options.Platform != "" {
@@ -133,6 +87,11 @@ func importRootfs(ctx context.Context, client *containerd.Client, snapshotter st
 		ociplat = p
 	}
 
+	config, err := parseImportChanges(options.Change)
+	if err != nil {
+		return zero, err
+	}
+
 	created := time.Now().UTC()
 	imgConfig := ocispec.Image{
 		Platform: ocispec.Platform{
@@ -142,7 +101,7 @@ func importRootfs(ctx context.Context, client *containerd.Client, snapshotter st
 			Variant:      ociplat.Variant,
 		},
 		Created: &created,
-		Config:  ocispec.ImageConfig{},
+		Config:  config,
 		RootFS: ocispec.RootFS{
 			Type:    "layers",
 			DiffIDs: []digest.Digest{diffID},
@@ -153,7 +112,7 @@ func importRootfs(ctx context.Context, client *containerd.Client, snapshotter st
 		}},
 	}
 
-	manifestDesc, _, err := writeConfigAndManifest(ctx, cs, snapshotter, imgConfig, []ocispec.Descriptor{layerDesc})
+	manifestDesc, _, err := WriteConfigAndManifest(ctx, cs, snapshotter, imgConfig, []ocispec.Descriptor{layerDesc})
 	if err != nil {
 		return zero, err
 	}
@@ -192,13 +151,166 @@ func importRootfs(ctx context.Context, client *containerd.Client, snapshotter st
 	return img, nil
 }
 
+// parseImportChanges builds an image config from the Dockerfile instructions passed with `--change`.
+// The instructions supported are the ones supported by `docker import`.
+func parseImportChanges(changes []string) (ocispec.ImageConfig, error) {
+	var config ocispec.ImageConfig
+	for _, change := range changes {
+		fields := strings.Fields(change)
+		if len(fields) < 2 {
+			return config, fmt.Errorf("invalid change flag value %q", change)
+		}
+		directive := strings.ToUpper(fields[0])
+		value := strings.TrimSpace(change[len(fields[0]):])
+		switch directive {
+		case "CMD":
+			config.Cmd = parseImportCommand(value)
+		case "ENTRYPOINT":
+			config.Entrypoint = parseImportCommand(value)
+		case "ENV":
+			kvs, err := dockerfileutil.ParseKeyValues(value, importEnv(config.Env), false)
+			if err != nil {
+				return config, fmt.Errorf("invalid change flag value %q: %w", change, err)
+			}
+			config.Env = append(config.Env, kvs...)
+		case "LABEL":
+			kvs, err := dockerfileutil.ParseKeyValues(value, importEnv(config.Env), false)
+			if err != nil {
+				return config, fmt.Errorf("invalid change flag value %q: %w", change, err)
+			}
+			if config.Labels == nil {
+				config.Labels = make(map[string]string)
+			}
+			for _, kv := range kvs {
+				k, v, _ := strings.Cut(kv, "=")
+				config.Labels[k] = v
+			}
+		case "EXPOSE":
+			if config.ExposedPorts == nil {
+				config.ExposedPorts = make(map[string]struct{})
+			}
+			ports, err := dockerfileutil.LexWords(value, importEnv(config.Env), true)
+			if err != nil {
+				return config, fmt.Errorf("invalid change flag value %q: %w", change, err)
+			}
+			for _, port := range ports {
+				if !strings.Contains(port, "/") {
+					port += "/tcp"
+				}
+				config.ExposedPorts[port] = struct{}{}
+			}
+		case "VOLUME":
+			var volumes []string
+			if err := json.Unmarshal([]byte(value), &volumes); err != nil {
+				if volumes, err = dockerfileutil.LexWords(value, importEnv(config.Env), true); err != nil {
+					return config, fmt.Errorf("invalid change flag value %q: %w", change, err)
+				}
+			}
+			if config.Volumes == nil {
+				config.Volumes = make(map[string]struct{})
+			}
+			for _, v := range volumes {
+				config.Volumes[v] = struct{}{}
+			}
+		case "USER":
+			config.User = value
+		case "WORKDIR":
+			config.WorkingDir = value
+		case "STOPSIGNAL":
+			config.StopSignal = value
+		default:
+			return config, fmt.Errorf("unsupported change directive %q (supported: CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, STOPSIGNAL, USER, VOLUME, WORKDIR)", fields[0])
+		}
+	}
+	return config, nil
+}
+
+// importEnv returns the environment variables for expanding the variables in the arguments of the changes,
+// i.e., the variables set by the preceding ENV changes.
+func importEnv(env []string) map[string]string {
+	res := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		res[k] = v
+	}
+	return res
+}
+
+// parseImportCommand parses both the exec form (`["sh", "-c", "..."]`) and the shell form of CMD and ENTRYPOINT.
+func parseImportCommand(value string) []string {
+	var cmd []string
+	if err := json.Unmarshal([]byte(value), &cmd); err == nil {
+		return cmd
+	}
+	return []string{"/bin/sh", "-c", value}
+}
+
+// WriteGzipLayer compresses the uncompressed layer tar stream r with gzip and writes it to the content store.
+// Returns the descriptor of the compressed layer, and its diffID.
+func WriteGzipLayer(ctx context.Context, cs content.Store, refPrefix string, r io.Reader) (ocispec.Descriptor, digest.Digest, error) {
+	ref := randomRef(refPrefix)
+	w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer w.Close()
+	if err := w.Truncate(0); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	digester := digest.Canonical.Digester()
+	tee := io.TeeReader(r, digester.Hash())
+	pr, pw := io.Pipe()
+	gz := gzip.NewWriter(pw)
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(gz, tee)
+		if err != nil {
+			doneCh <- err
+			_ = gz.Close()
+			_ = pw.CloseWithError(err)
+			return
+		}
+		if err := gz.Close(); err != nil {
+			doneCh <- err
+			_ = pw.CloseWithError(err)
+			return
+		}
+		doneCh <- pw.Close()
+	}()
+
+	n, err := io.Copy(w, pr)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := <-doneCh; err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	diffID := digester.Digest()
+	labels := map[string]string{
+		"containerd.io/uncompressed": diffID.String(),
+	}
+	if err := w.Commit(ctx, n, "", content.WithLabels(labels)); err != nil && !errdefs.IsAlreadyExists(err) {
+		return ocispec.Descriptor{}, "", err
+	}
+	layerDesc := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2LayerGzip,
+		Digest:    w.Digest(),
+		Size:      n,
+	}
+	return layerDesc, diffID, nil
+}
+
 func randomRef(prefix string) string {
 	var b [6]byte
 	_, _ = rand.Read(b[:])
 	return prefix + base64.RawURLEncoding.EncodeToString(b[:])
 }
 
-func writeConfigAndManifest(ctx context.Context, cs content.Store, snapshotter string, config ocispec.Image, layers []ocispec.Descriptor) (ocispec.Descriptor, digest.Digest, error) {
+// WriteConfigAndManifest writes the image config and a Docker schema2 manifest referring to layers to the content store.
+// Returns the descriptor of the manifest, and the digest of the config.
+func WriteConfigAndManifest(ctx context.Context, cs content.Store, snapshotter string, config ocispec.Image, layers []ocispec.Descriptor) (ocispec.Descriptor, digest.Digest, error) {
 	configJSON, err := json.Marshal(config)
 	if err != nil {
 		return ocispec.Descriptor{}, "", err
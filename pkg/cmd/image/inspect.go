@@ -107,6 +107,7 @@ func Inspect(ctx context.Context, client *containerd.Client, identifiers []strin
 		var validatedImage *dockercompat.Image
 		var repoTags []string
 		var repoDigests []string
+		var lastTagTime time.Time
 
 		// Go through the candidates
 		for _, candidateImage := range candidateImageList {
@@ -170,6 +171,9 @@ func Inspect(ctx context.Context, client *containerd.Client, identifiers []strin
 				}
 				repoTags = append(repoTags, fmt.Sprintf("%s:%s", parsedReference.FamiliarName(), tag))
 				repoDigests = append(repoDigests, fmt.Sprintf("%s@%s", parsedReference.FamiliarName(), candidateImage.Target.Digest.String()))
+				if candidateImage.UpdatedAt.After(lastTagTime) {
+					lastTagTime = candidateImage.UpdatedAt
+				}
 			}
 		}
 
@@ -182,6 +186,9 @@ func Inspect(ctx context.Context, client *containerd.Client, identifiers []strin
 			// Then slap in the repoTags and repoDigests we found from the other candidates
 			validatedImage.RepoTags = append(validatedImage.RepoTags, repoTags...)
 			validatedImage.RepoDigests = append(validatedImage.RepoDigests, repoDigests...)
+			if lastTagTime.After(validatedImage.Metadata.LastTagTime) {
+				validatedImage.Metadata.LastTagTime = lastTagTime
+			}
 			// Store our image
 			// foundImages[validatedDigest] = validatedImage
 			entries = append(entries, validatedImage)
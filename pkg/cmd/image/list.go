@@ -126,6 +126,20 @@ type imagePrintable struct {
 	BlobSize     string // the size of the blobs in the content store (nerdctl extension)
 	// TODO: "SharedSize", "UniqueSize"
 	Platform string // nerdctl extension
+
+	labels map[string]string // labels of the image config
+}
+
+// Label returns the value of the image config label, for `--format '{{.Label "key"}}'`.
+func (p imagePrintable) Label(name string) string {
+	return p.labels[name]
+}
+
+// imageTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var imageTableHeaders = map[string]string{
+	"ID":           "IMAGE ID",
+	"CreatedSince": "CREATED",
+	"BlobSize":     "BLOB SIZE",
 }
 
 func printImages(ctx context.Context, client *containerd.Client, imageList []images.Image, options *types.ImageListOptions) error {
@@ -198,7 +212,7 @@ func printImages(ctx context.Context, client *containerd.Client, imageList []ima
 			return errors.New("format and quiet must not be specified together")
 		}
 		var err error
-		tmpl, err = formatter.ParseTemplate(options.Format)
+		w, tmpl, err = formatter.ParseListTemplate(w, options.Format, imageTableHeaders, options.Quiet)
 		if err != nil {
 			return err
 		}
@@ -241,6 +255,7 @@ type image struct {
 	size     int64
 	platform platforms.Platform
 	config   *ocispec.Descriptor
+	labels   map[string]string
 }
 
 func readManifest(ctx context.Context, provider content.Provider, snapshotter snapshots.Snapshotter, desc ocispec.Descriptor) (*image, error) {
@@ -292,6 +307,7 @@ func readManifest(ctx context.Context, provider content.Provider, snapshotter sn
 		size:     size,
 		platform: plt,
 		config:   &manifest.Config,
+		labels:   config.Config.Labels,
 	}, nil
 }
 
@@ -348,7 +364,7 @@ func (x *imagePrinter) printImage(ctx context.Context, img images.Image) error {
 	}
 
 	for platform, desc := range candidateImages {
-		if err := x.printImageSinglePlatform(*desc.config, img, desc.blobSize, desc.size, desc.platform); err != nil {
+		if err := x.printImageSinglePlatform(*desc.config, img, desc.blobSize, desc.size, desc.platform, desc.labels); err != nil {
 			log.G(ctx).WithError(err).Debugf("failed to get platform %q of image %q", platform, img.Name)
 		}
 	}
@@ -356,7 +372,7 @@ func (x *imagePrinter) printImage(ctx context.Context, img images.Image) error {
 	return nil
 }
 
-func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img images.Image, blobSize int64, size int64, plt platforms.Platform) error {
+func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img images.Image, blobSize int64, size int64, plt platforms.Platform, labels map[string]string) error {
 	var (
 		repository string
 		tag        string
@@ -377,6 +393,7 @@ func (x *imagePrinter) printImageSinglePlatform(desc ocispec.Descriptor, img ima
 		Size:         units.HumanSize(float64(size)),
 		BlobSize:     units.HumanSize(float64(blobSize)),
 		Platform:     platforms.FormatAll(plt),
+		labels:       labels,
 	}
 	if p.Repository == "" {
 		p.Repository = "<none>"
@@ -0,0 +1,163 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/docker/go-units"
+	"golang.org/x/sync/errgroup"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+)
+
+// Prefetch reads the files of a lazily-pulled image through a read-only view of its rootfs,
+// so that the remote snapshotter fetches their chunks before the containers access them.
+func Prefetch(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePrefetchOptions) error {
+	if runtime.GOOS != "linux" {
+		return errors.New("prefetch command is only supported on Linux")
+	}
+	if options.Parallelism < 0 {
+		return fmt.Errorf("invalid parallelism %d", options.Parallelism)
+	}
+	snapshotter := options.GOptions.Snapshotter
+	if !imgutil.IsRemoteSnapshotter(snapshotter) {
+		return fmt.Errorf("snapshotter %q does not support lazy pulling, nothing to prefetch (hint: pull the image with --snapshotter=stargz, nydus, soci or overlaybd)", snapshotter)
+	}
+
+	platMC := platforms.DefaultStrict()
+	var err error
+	if options.Platform != "" {
+		platMC, err = platformutil.NewMatchComparer(false, []string{options.Platform})
+		if err != nil {
+			return err
+		}
+	}
+
+	name, err := resolveImageName(ctx, client, rawRef)
+	if err != nil {
+		return err
+	}
+
+	// Hold a lease so that the view snapshot is not garbage collected while prefetching
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID())
+	if err != nil {
+		return fmt.Errorf("failed to create lease: %w", err)
+	}
+	defer done(ctx)
+
+	imgRecord, err := client.ImageService().Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	img := containerd.NewImageWithPlatform(client, imgRecord, platMC)
+	// Unpacking here would download every layer, defeating lazy pulling
+	unpacked, err := img.IsUnpacked(ctx, snapshotter)
+	if err != nil {
+		return err
+	}
+	if !unpacked {
+		return fmt.Errorf("image %q is not unpacked for snapshotter %q (hint: run `nerdctl --snapshotter=%s pull %s`)", name, snapshotter, snapshotter, rawRef)
+	}
+
+	return withImageRootfs(ctx, client, img, snapshotter, func(root string) error {
+		files, size, err := prefetchRootfs(ctx, root, options.Paths, options.Parallelism)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Stdout, "Prefetched %d files (%s) of %s\n", files, units.HumanSize(float64(size)), name)
+		return nil
+	})
+}
+
+// prefetchRootfs reads the regular files under paths of root, and returns the number of the files and their total size.
+func prefetchRootfs(ctx context.Context, root string, paths []string, parallelism int) (int64, int64, error) {
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	var files, size atomic.Int64
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(parallelism)
+	for _, p := range paths {
+		// Resolve the symlinks inside the rootfs, not on the host
+		resolved, err := securejoin.SecureJoin(root, p)
+		if err != nil {
+			return 0, 0, err
+		}
+		err = filepath.WalkDir(resolved, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && path == resolved {
+					return fmt.Errorf("path %q does not exist in the image", p)
+				}
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			eg.Go(func() error {
+				n, err := readFile(path)
+				if err != nil {
+					return err
+				}
+				log.G(ctx).Debugf("prefetched %s (%d bytes)", path, n)
+				files.Add(1)
+				size.Add(n)
+				return nil
+			})
+			return nil
+		})
+		if err != nil {
+			_ = eg.Wait()
+			return 0, 0, err
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return 0, 0, err
+	}
+	return files.Load(), size.Load(), nil
+}
+
+func readFile(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(io.Discard, f)
+}
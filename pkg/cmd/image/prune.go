@@ -68,6 +68,17 @@ func Prune(ctx context.Context, client *containerd.Client, options types.ImagePr
 		return err
 	}
 
+	if options.DryRun {
+		if len(imagesToBeRemoved) > 0 {
+			fmt.Fprintln(options.Stdout, "Would Delete Images:")
+			for _, image := range imagesToBeRemoved {
+				fmt.Fprintf(options.Stdout, "%s (%s)\n", image.Name, image.Target.Digest)
+			}
+			fmt.Fprintln(options.Stdout, "")
+		}
+		return nil
+	}
+
 	delOpts := []images.DeleteOpt{images.SynchronousDelete()}
 	removedImages := make(map[string][]digest.Digest)
 	for _, image := range imagesToBeRemoved {
@@ -19,10 +19,13 @@ package image
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
@@ -51,6 +54,12 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return nil, err
 	}
 
+	snapshotter, err := imgutil.PreflightSnapshotter(ctx, client, options.GOptions.Snapshotter, options.GOptions.SnapshotterFallback)
+	if err != nil {
+		return nil, err
+	}
+	options.GOptions.Snapshotter = snapshotter
+
 	if parsedReference.Protocol != "" {
 		if options.VerifyOptions.Provider != "none" {
 			return nil, errors.New("--verify flag is not supported on IPFS as of now")
@@ -76,14 +85,63 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return ensured, nil
 	}
 
-	ref, err := signutil.Verify(ctx, rawRef, options.GOptions.HostsDir, options.GOptions.Experimental, options.VerifyOptions)
+	// --verify=scan is done against the local content after pulling
+	ref := rawRef
+	if options.VerifyOptions.Provider != "scan" {
+		ref, err = signutil.Verify(ctx, rawRef, options.GOptions.HostsDir, options.GOptions.Experimental, options.VerifyOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch options.VerifyOptions.Provider {
+	case "cosign", "notation":
+		ensured, err = ensureVerifiedImage(ctx, client, rawRef, ref, options)
+	default:
+		ensured, err = imgutil.EnsureImage(ctx, client, ref, options)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if options.VerifyOptions.Provider == "scan" {
+		if err := verifyScan(ctx, client, ensured.Ref, options); err != nil {
+			return nil, err
+		}
+	}
+	return ensured, err
+}
 
-	ensured, err = imgutil.EnsureImage(ctx, client, ref, options)
+// ensureVerifiedImage ensures the image of which signature was verified as `verifiedRef` (`<rawRef>@<DIGEST>`).
+// The local image of rawRef is used only when its digest is the verified one,
+// so that a local image retagged or tampered after the verification is never used.
+func ensureVerifiedImage(ctx context.Context, client *containerd.Client, rawRef, verifiedRef string, options types.ImagePullOptions) (*imgutil.EnsuredImage, error) {
+	parsedReference, err := referenceutil.Parse(verifiedRef)
 	if err != nil {
 		return nil, err
 	}
-	return ensured, err
+	verified := parsedReference.Digest
+	if verified == "" {
+		return nil, fmt.Errorf("no digest was verified for image %q", rawRef)
+	}
+	if options.Mode != "always" && len(options.OCISpecPlatform) == 1 {
+		if local, err := imgutil.GetExistingImage(ctx, client, options.GOptions.Snapshotter, rawRef, options.OCISpecPlatform[0]); err == nil {
+			if local.Image.Target().Digest == verified {
+				return local, nil
+			}
+			if options.Mode == "never" {
+				return nil, fmt.Errorf("local image %q (%s) does not match the verified digest %s", rawRef, local.Image.Target().Digest, verified)
+			}
+			log.G(ctx).Warnf("local image %q (%s) does not match the verified digest %s, pulling the verified one", rawRef, local.Image.Target().Digest, verified)
+		} else if !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	ensured, err := imgutil.EnsureImage(ctx, client, verifiedRef, options)
+	if err != nil {
+		return nil, err
+	}
+	if actual := ensured.Image.Target().Digest; actual != verified {
+		return nil, fmt.Errorf("image %q (%s) does not match the verified digest %s", rawRef, actual, verified)
+	}
+	return ensured, nil
 }
@@ -27,6 +27,7 @@ import (
 
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/content"
@@ -45,6 +46,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
 	nerdconverter "github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/push"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/ipfs"
@@ -52,10 +54,14 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/signutil"
 	"github.com/containerd/nerdctl/v2/pkg/snapshotterutil"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 // Push pushes an image specified by `rawRef`.
-func Push(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePushOptions) error {
+func Push(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePushOptions) (retErr error) {
+	ctx, span := tracingutil.StartSpan(ctx, "nerdctl.image.push", attribute.String("image.ref", rawRef))
+	defer func() { tracingutil.EndSpan(span, retErr) }()
+
 	parsedReference, err := referenceutil.Parse(rawRef)
 	if err != nil {
 		return err
@@ -102,6 +108,21 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 			return err
 		}
 		fmt.Fprintln(options.Stdout, c)
+		if options.IpfsPinService != "" {
+			name := options.IpfsPinName
+			if name == "" {
+				name = parsedReference.String()
+			}
+			st, err := ipfs.Pin(ctx, c, ipfs.PinningServiceOptions{
+				Endpoint: options.IpfsPinService,
+				Token:    options.IpfsPinToken,
+				Name:     name,
+			})
+			if err != nil {
+				return err
+			}
+			log.G(ctx).WithField("requestid", st.RequestID).Infof("requested %q to pin %s (status: %s)", options.IpfsPinService, c, st.Status)
+		}
 		return nil
 	}
 
@@ -154,8 +175,23 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 	// resulting in the failure of the entire image push.
 	pushTracker := docker.NewInMemoryTracker()
 
+	var printer *jobs.Printer
+	if !options.Quiet {
+		progressOutput := options.Stdout
+		if options.GOptions.Progress == jobs.ProgressJSON {
+			// machine-readable records always go to stderr, so that stdout stays parseable
+			progressOutput = options.Stderr
+		}
+		printer = jobs.NewPrinter(progressOutput, options.GOptions.Progress, "push")
+	}
 	pushFunc := func(r remotes.Resolver) error {
-		return push.Push(ctx, client, r, pushTracker, options.Stdout, pushRef, ref, platMC, options.AllowNondistributableArtifacts, options.Quiet)
+		if err := push.Push(ctx, client, r, pushTracker, printer, pushRef, ref, platMC, options.AllowNondistributableArtifacts); err != nil {
+			return err
+		}
+		if options.SBOM != "" {
+			return attachSBOM(ctx, client, r, pushRef, ref, platMC, options)
+		}
+		return nil
 	}
 
 	var dOpts []dockerconfigresolver.Opt
@@ -164,6 +200,8 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 	}
 	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(options.GOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(options.GOptions.Registries))
+	dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!options.GOptions.NoTokenCache))
 
 	ho, err := dockerconfigresolver.NewHostOptions(ctx, refDomain, dOpts...)
 	if err != nil {
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/opencontainers/go-digest"
+
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/log"
@@ -30,6 +32,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 )
 
 // Remove removes a list of `images`.
@@ -62,12 +65,61 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 		}
 	}
 
+	// untagOrDelete removes the image record, like `docker rmi` does:
+	// when other image records still refer to the same target, only the tag is removed ("Untagged:"),
+	// otherwise the last reference is removed and the content is released ("Deleted:").
+	untagOrDelete := func(ctx context.Context, img images.Image) error {
+		refs, err := is.List(ctx, fmt.Sprintf("target.digest==%s", img.Target.Digest))
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if ref.Name != img.Name {
+				if err := is.Delete(ctx, img.Name); err != nil {
+					return err
+				}
+				fmt.Fprintf(options.Stdout, "Untagged: %s\n", img.Name)
+				return nil
+			}
+		}
+
+		// digests is used only for emulating human-readable output of `docker rmi`
+		var digests []digest.Digest
+		if configDesc, err := img.Config(ctx, cs, platforms.DefaultStrict()); err == nil {
+			digests = append(digests, configDesc.Digest)
+		}
+		rootfs, err := img.RootFS(ctx, cs, platforms.DefaultStrict())
+		if err != nil {
+			log.G(ctx).WithError(err).Warning("failed to enumerate rootfs")
+		}
+		digests = append(digests, rootfs...)
+
+		if err := is.Delete(ctx, img.Name, delOpts...); err != nil {
+			return err
+		}
+		repository, _ := imgutil.ParseRepoTag(img.Name)
+		if repository == "" {
+			repository = img.Name
+		}
+		if repository != img.Name {
+			fmt.Fprintf(options.Stdout, "Untagged: %s\n", img.Name)
+		}
+		fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", repository, img.Target.Digest)
+		for _, digest := range digests {
+			fmt.Fprintf(options.Stdout, "Deleted: %s\n", digest)
+		}
+		return nil
+	}
+
 	walker := &imagewalker.ImageWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found imagewalker.Found) error {
 			if found.NameMatchIndex == -1 {
 				// if found multiple images, return error unless in force-mode and
 				// there is only 1 unique image.
+				if found.MatchCount > 1 && found.UniqueImages == 1 && !options.Force {
+					return fmt.Errorf("conflict: unable to delete %s (must be forced) - image is referenced in multiple repositories", found.Req)
+				}
 				if found.MatchCount > 1 && !(options.Force && found.UniqueImages == 1) {
 					return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 				}
@@ -102,20 +154,7 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 			if cid, ok := usedImages[found.Image.Name]; ok && !options.Force {
 				return fmt.Errorf("conflict: unable to delete %s (must be forced) - image is being used by stopped container %s", found.Req, cid)
 			}
-			// digests is used only for emulating human-readable output of `docker rmi`
-			digests, err := found.Image.RootFS(ctx, cs, platforms.DefaultStrict())
-			if err != nil {
-				log.G(ctx).WithError(err).Warning("failed to enumerate rootfs")
-			}
-
-			if err := is.Delete(ctx, found.Image.Name, delOpts...); err != nil {
-				return err
-			}
-			fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", found.Image.Name, found.Image.Target.Digest)
-			for _, digest := range digests {
-				fmt.Fprintf(options.Stdout, "Deleted: %s\n", digest)
-			}
-			return nil
+			return untagOrDelete(ctx, found.Image)
 		},
 		OnFoundCriRm: func(ctx context.Context, found imagewalker.Found) (bool, error) {
 			if found.NameMatchIndex == -1 {
@@ -155,19 +194,9 @@ func Remove(ctx context.Context, client *containerd.Client, args []string, optio
 			if cid, ok := usedImages[found.Image.Name]; ok && !options.Force {
 				return false, fmt.Errorf("conflict: unable to delete %s (must be forced) - image is being used by stopped container %s", found.Req, cid)
 			}
-			// digests is used only for emulating human-readable output of `docker rmi`
-			digests, err := found.Image.RootFS(ctx, cs, platforms.DefaultStrict())
-			if err != nil {
-				log.G(ctx).WithError(err).Warning("failed to enumerate rootfs")
-			}
-
-			if err := is.Delete(ctx, found.Image.Name, delOpts...); err != nil {
+			if err := untagOrDelete(ctx, found.Image); err != nil {
 				return false, err
 			}
-			fmt.Fprintf(options.Stdout, "Untagged: %s@%s\n", found.Image.Name, found.Image.Target.Digest)
-			for _, digest := range digests {
-				fmt.Fprintf(options.Stdout, "Deleted: %s\n", digest)
-			}
 			return true, nil
 		},
 	}
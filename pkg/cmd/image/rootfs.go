@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/image-spec/identity"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+)
+
+// withImageRootfs unpacks img, mounts a read-only view of its rootfs, and calls f with the mount point.
+// The caller is expected to hold a lease.
+func withImageRootfs(ctx context.Context, client *containerd.Client, img containerd.Image, snapshotter string, f func(root string) error) error {
+	if err := img.Unpack(ctx, snapshotter); err != nil {
+		return fmt.Errorf("error unpacking image: %w", err)
+	}
+	diffIDs, err := img.RootFS(ctx)
+	if err != nil {
+		return err
+	}
+	chainID := identity.ChainID(diffIDs).String()
+
+	tempDir, err := os.MkdirTemp("", "nerdctl-rootfs-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary mount directory: %w", err)
+	}
+	// Remove (not RemoveAll) so that a failed unmount never deletes the image contents
+	defer os.Remove(tempDir)
+
+	sn := client.SnapshotService(snapshotter)
+	key := filepath.Base(tempDir)
+	mounts, err := sn.View(ctx, key, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create view snapshot: %w", err)
+	}
+	defer func() {
+		if err := sn.Remove(ctx, key); err != nil && !errdefs.IsNotFound(err) {
+			log.G(ctx).WithError(err).Warnf("Failed to remove view snapshot %q", key)
+		}
+	}()
+
+	if err := mount.All(mounts, tempDir); err != nil {
+		return fmt.Errorf("failed to mount image snapshot: %w", err)
+	}
+	defer func() {
+		if err := mount.UnmountAll(tempDir, 0); err != nil {
+			log.G(ctx).WithError(err).Warn("Failed to unmount snapshot")
+		}
+	}()
+	log.G(ctx).Debugf("Mounted image snapshot at %s", tempDir)
+
+	return f(tempDir)
+}
+
+// resolveImageName returns the name of the single image matching rawRef.
+func resolveImageName(ctx context.Context, client *containerd.Client, rawRef string) (string, error) {
+	var name string
+	walker := &imagewalker.ImageWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found imagewalker.Found) error {
+			if found.UniqueImages > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			if name == "" {
+				name = found.Image.Name
+			}
+			return nil
+		},
+	}
+	n, err := walker.Walk(ctx, rawRef)
+	if err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", fmt.Errorf("no such image: %s", rawRef)
+	}
+	return name, nil
+}
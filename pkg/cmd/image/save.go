@@ -19,12 +19,14 @@ package image
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/images/archive"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -69,5 +71,12 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 		return err
 	}
 
-	return client.Export(ctx, options.Stdout, exportOpts...)
+	out := options.Stdout
+	if printer := jobs.NewArchivePrinter(options.Stderr, options.GOptions.Progress, "save"); printer != nil {
+		var counter jobs.Counter
+		out = counter.CountingWriter(out)
+		stop := jobs.StartCounterProgress(printer, strings.Join(images, ","), jobs.StatusExporting, &counter, 0)
+		defer stop()
+	}
+	return client.Export(ctx, out, exportOpts...)
 }
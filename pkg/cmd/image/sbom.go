@@ -0,0 +1,179 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/sbom"
+	"github.com/containerd/nerdctl/v2/pkg/version"
+)
+
+// SBOM scans the packages installed in an image and writes an SBOM document.
+func SBOM(ctx context.Context, client *containerd.Client, rawRef string, options types.ImageSBOMOptions) error {
+	if _, err := sbom.MediaType(options.Format); err != nil {
+		return err
+	}
+	platMC := platforms.DefaultStrict()
+	if options.Platform != "" {
+		var err error
+		platMC, err = platformutil.NewMatchComparer(false, []string{options.Platform})
+		if err != nil {
+			return err
+		}
+	}
+
+	name, err := resolveImageName(ctx, client, rawRef)
+	if err != nil {
+		return err
+	}
+
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID())
+	if err != nil {
+		return fmt.Errorf("failed to create lease: %w", err)
+	}
+	defer done(ctx)
+
+	imgRecord, err := client.ImageService().Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	img := containerd.NewImageWithPlatform(client, imgRecord, platMC)
+	doc, _, err := generateSBOM(ctx, client, img, options.GOptions.Snapshotter, options.Format)
+	if err != nil {
+		return err
+	}
+	if options.Output == "" {
+		_, err = options.Stdout.Write(doc)
+		return err
+	}
+	return os.WriteFile(options.Output, doc, 0644)
+}
+
+// generateSBOM unpacks img and scans its rootfs. It returns the SBOM document and its media type.
+func generateSBOM(ctx context.Context, client *containerd.Client, img containerd.Image, snapshotter, format string) ([]byte, string, error) {
+	mediaType, err := sbom.MediaType(format)
+	if err != nil {
+		return nil, "", err
+	}
+	var buf bytes.Buffer
+	err = withImageRootfs(ctx, client, img, snapshotter, func(root string) error {
+		res, err := sbom.Scan(root)
+		if err != nil {
+			return fmt.Errorf("failed to scan image %q: %w", img.Name(), err)
+		}
+		subject := sbom.Subject{
+			Name:   img.Name(),
+			Digest: img.Target().Digest.String(),
+		}
+		return sbom.Write(&buf, format, subject, res, sbom.Options{ToolVersion: version.GetVersion()})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), mediaType, nil
+}
+
+// attachSBOM generates an SBOM of the local image `localRef` and pushes it to the repository of `remoteRef`
+// as an OCI artifact whose subject is the image.
+func attachSBOM(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, localRef, remoteRef string, platMC platforms.MatchComparer, options types.ImagePushOptions) error {
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID())
+	if err != nil {
+		return fmt.Errorf("failed to create lease: %w", err)
+	}
+	defer done(ctx)
+
+	imgRecord, err := client.ImageService().Get(ctx, localRef)
+	if err != nil {
+		return err
+	}
+	img := containerd.NewImageWithPlatform(client, imgRecord, platMC)
+	doc, mediaType, err := generateSBOM(ctx, client, img, options.GOptions.Snapshotter, options.SBOM)
+	if err != nil {
+		return err
+	}
+
+	cs := client.ContentStore()
+	write := func(mediaType string, data []byte) (ocispec.Descriptor, error) {
+		desc := ocispec.Descriptor{
+			MediaType: mediaType,
+			Digest:    digest.FromBytes(data),
+			Size:      int64(len(data)),
+		}
+		if err := content.WriteBlob(ctx, cs, desc.Digest.String(), bytes.NewReader(data), desc); err != nil && !errdefs.IsAlreadyExists(err) {
+			return ocispec.Descriptor{}, err
+		}
+		return desc, nil
+	}
+
+	layerDesc, err := write(mediaType, doc)
+	if err != nil {
+		return err
+	}
+	configDesc, err := write(ocispec.MediaTypeEmptyJSON, ocispec.DescriptorEmptyJSON.Data)
+	if err != nil {
+		return err
+	}
+	subject := imgRecord.Target
+	manifestJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: mediaType,
+		Config:       configDesc,
+		Layers:       []ocispec.Descriptor{layerDesc},
+		Subject: &ocispec.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	manifestDesc, err := write(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err != nil {
+		return err
+	}
+	manifestDesc.ArtifactType = mediaType
+
+	parsedReference, err := referenceutil.Parse(remoteRef)
+	if err != nil {
+		return err
+	}
+	artifactRef := fmt.Sprintf("%s@%s", parsedReference.Name(), manifestDesc.Digest)
+	log.G(ctx).Infof("pushing SBOM (%s) as %s", mediaType, artifactRef)
+	return client.Push(ctx, artifactRef, manifestDesc, containerd.WithResolver(resolver))
+}
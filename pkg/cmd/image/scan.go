@@ -0,0 +1,141 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"text/template"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/scanutil"
+)
+
+// Scan runs the configured vulnerability scanner against a local image.
+func Scan(ctx context.Context, client *containerd.Client, rawRef string, options types.ImageScanOptions) error {
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+	if options.Severity != "" {
+		if err := scanutil.ValidateSeverity(options.Severity); err != nil {
+			return err
+		}
+	}
+
+	name, err := resolveImageName(ctx, client, rawRef)
+	if err != nil {
+		return err
+	}
+	report, err := scanImage(ctx, client, name, options.Platform, options.GOptions)
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		for _, v := range report.Vulnerabilities {
+			if err := tmpl.Execute(options.Stdout, v); err != nil {
+				return err
+			}
+			fmt.Fprintln(options.Stdout)
+		}
+	} else {
+		w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "ID\tPACKAGE\tINSTALLED\tFIXED\tSEVERITY")
+		for _, v := range report.Vulnerabilities {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.ID, v.Package, v.InstalledVersion, v.FixedVersion, v.Severity)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Stdout, "\n%s: %s\n", name, report.Summary())
+	}
+
+	if options.Severity != "" {
+		if exceeding := report.Exceeding(options.Severity); len(exceeding) > 0 {
+			return fmt.Errorf("found %d vulnerabilities with severity %s or higher in %q", len(exceeding), options.Severity, name)
+		}
+	}
+	return nil
+}
+
+// scanImage exports the image `name` to a temporary archive and runs the configured scanner against it.
+func scanImage(ctx context.Context, client *containerd.Client, name string, platform []string, globalOptions types.GlobalCommandOptions) (*scanutil.Report, error) {
+	f, err := os.CreateTemp("", "nerdctl-scan-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	saveOptions := types.ImageSaveOptions{
+		Stdout:   f,
+		GOptions: globalOptions,
+		Platform: platform,
+	}
+	if err := Save(ctx, client, []string{name}, saveOptions); err != nil {
+		return nil, fmt.Errorf("failed to export image %q for scanning: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	log.G(ctx).Debugf("scanning image %q with %s", name, globalOptions.Scanner)
+	return scanutil.Scan(ctx, globalOptions.Scanner, f.Name())
+}
+
+// verifyScan fails if the image has vulnerabilities exceeding the configured severity threshold.
+func verifyScan(ctx context.Context, client *containerd.Client, ensured string, options types.ImagePullOptions) error {
+	threshold := options.GOptions.ScanSeverity
+	if threshold == "" {
+		threshold = "CRITICAL"
+	}
+	if err := scanutil.ValidateSeverity(threshold); err != nil {
+		return err
+	}
+	var platform []string
+	for _, p := range options.OCISpecPlatform {
+		platform = append(platform, platforms.Format(p))
+	}
+	report, err := scanImage(ctx, client, ensured, platform, options.GOptions)
+	if err != nil {
+		return err
+	}
+	if exceeding := report.Exceeding(threshold); len(exceeding) > 0 {
+		for _, v := range exceeding {
+			log.G(ctx).Errorf("%s: %s %s (%s)", v.Severity, v.ID, v.Package, v.InstalledVersion)
+		}
+		return fmt.Errorf("image %q failed the vulnerability scan: found %d vulnerabilities with severity %s or higher (%s)", ensured, len(exceeding), threshold, report.Summary())
+	}
+	log.G(ctx).Infof("image %q passed the vulnerability scan (%s)", ensured, report.Summary())
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// Squash collapses all the layers of an image into a single layer, and stores the result as a new image.
+func Squash(ctx context.Context, client *containerd.Client, options types.ImageSquashOptions) error {
+	platMC := platforms.DefaultStrict()
+	if options.Platform != "" {
+		var err error
+		platMC, err = platformutil.NewMatchComparer(false, []string{options.Platform})
+		if err != nil {
+			return err
+		}
+	}
+	parsedTarget, err := referenceutil.Parse(options.Target)
+	if err != nil {
+		return err
+	}
+
+	srcName, err := resolveImageName(ctx, client, options.Source)
+	if err != nil {
+		return err
+	}
+
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID(), leases.WithExpiration(1*time.Hour))
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+
+	imgRecord, err := client.ImageService().Get(ctx, srcName)
+	if err != nil {
+		return err
+	}
+	img := containerd.NewImageWithPlatform(client, imgRecord, platMC)
+	config, _, err := imgutil.ReadImageConfig(ctx, img)
+	if err != nil {
+		return err
+	}
+	oldChainID := identity.ChainID(config.RootFS.DiffIDs)
+
+	var (
+		layerDesc ocispec.Descriptor
+		diffID    digest.Digest
+	)
+	err = withImageRootfs(ctx, client, img, options.GOptions.Snapshotter, func(root string) error {
+		emptyDir, err := os.MkdirTemp("", "nerdctl-squash-empty-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(emptyDir)
+
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(archive.WriteDiff(ctx, pw, emptyDir, root))
+		}()
+		layerDesc, diffID, err = WriteGzipLayer(ctx, client.ContentStore(), "squash-", pr)
+		pr.CloseWithError(err)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to squash layers: %w", err)
+	}
+
+	created := time.Now().UTC()
+	for i := range config.History {
+		config.History[i].EmptyLayer = true
+	}
+	comment := options.Message
+	if comment == "" {
+		comment = fmt.Sprintf("merge %s to %s", oldChainID, diffID)
+	}
+	config.History = append(config.History, ocispec.History{
+		Created: &created,
+		Comment: comment,
+	})
+	config.Created = &created
+	config.RootFS = ocispec.RootFS{
+		Type:    "layers",
+		DiffIDs: []digest.Digest{diffID},
+	}
+
+	manifestDesc, _, err := WriteConfigAndManifest(ctx, client.ContentStore(), options.GOptions.Snapshotter, config, []ocispec.Descriptor{layerDesc})
+	if err != nil {
+		return err
+	}
+
+	newImg := images.Image{
+		Name:      parsedTarget.String(),
+		Target:    manifestDesc,
+		CreatedAt: created,
+	}
+	if _, err := client.ImageService().Update(ctx, newImg); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := client.ImageService().Create(ctx, newImg); err != nil {
+			return err
+		}
+	}
+	if err := containerd.NewImage(client, newImg).Unpack(ctx, options.GOptions.Snapshotter); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(options.Stdout, manifestDesc.Digest.String())
+	return nil
+}
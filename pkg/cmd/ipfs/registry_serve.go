@@ -45,6 +45,7 @@ func RegistryServe(options types.IPFSRegistryServeOptions) error {
 		IpfsPath:     ipfsPath,
 		ReadRetryNum: options.ReadRetryNum,
 		ReadTimeout:  options.ReadTimeout,
+		Gateways:     options.Gateways,
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,167 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+)
+
+const (
+	// DefaultOAuthScope requests a refresh token, which is stored as the identity token of the registry
+	DefaultOAuthScope = "openid offline_access"
+
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+// defaultDeviceFlowInterval is the polling interval when the authorization server does not specify one (RFC 8628, section 3.2).
+var defaultDeviceFlowInterval = 5 * time.Second
+
+// oidcConfiguration is the subset of the OpenID Provider Metadata used for the device flow.
+type oidcConfiguration struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// deviceAuthorization is the device authorization response (RFC 8628, section 3.2).
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceToken is the token response, or the error response (RFC 6749, section 5).
+type deviceToken struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// deviceFlow runs the OAuth2 device authorization grant against the authorization server of the issuer,
+// discovered with OpenID Connect Discovery.
+// The verification URL and the user code are printed to stdout, then the token endpoint is polled
+// until the user approves or denies the request, or the device code expires.
+func deviceFlow(ctx context.Context, client *http.Client, issuer, clientID, scope string, stdout io.Writer) (*deviceToken, error) {
+	if clientID == "" {
+		return nil, errors.New("the OAuth client ID is required for the device flow")
+	}
+	var conf oidcConfiguration
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &conf); err != nil {
+		return nil, fmt.Errorf("failed to discover the OAuth endpoints of %q: %w", issuer, err)
+	}
+	if conf.DeviceAuthorizationEndpoint == "" || conf.TokenEndpoint == "" {
+		return nil, fmt.Errorf("issuer %q does not support the device flow", issuer)
+	}
+
+	var da deviceAuthorization
+	if err := postFormJSON(ctx, client, conf.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {clientID},
+		"scope":     {scope},
+	}, &da); err != nil {
+		return nil, fmt.Errorf("failed to request the device authorization: %w", err)
+	}
+	if da.DeviceCode == "" || da.UserCode == "" || da.VerificationURI == "" {
+		return nil, errors.New("invalid device authorization response")
+	}
+	if da.VerificationURIComplete != "" {
+		fmt.Fprintf(stdout, "Open %s in a browser, and confirm the code %s\n", da.VerificationURIComplete, da.UserCode)
+	} else {
+		fmt.Fprintf(stdout, "Open %s in a browser, and enter the code %s\n", da.VerificationURI, da.UserCode)
+	}
+
+	if da.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(da.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+	interval := defaultDeviceFlowInterval
+	if da.Interval > 0 {
+		interval = time.Duration(da.Interval) * time.Second
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errors.New("the device code expired before the request was approved")
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		var tok deviceToken
+		if err := postFormJSON(ctx, client, conf.TokenEndpoint, url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {da.DeviceCode},
+			"client_id":   {clientID},
+		}, &tok); err != nil {
+			return nil, fmt.Errorf("failed to request the token: %w", err)
+		}
+		switch tok.Error {
+		case "":
+			if tok.AccessToken == "" && tok.RefreshToken == "" {
+				return nil, errors.New("invalid token response")
+			}
+			return &tok, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			if tok.ErrorDescription != "" {
+				return nil, fmt.Errorf("device authorization failed: %s: %s", tok.Error, tok.ErrorDescription)
+			}
+			return nil, fmt.Errorf("device authorization failed: %s", tok.Error)
+		}
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, u string, v any) error {
+	res, err := ctxhttp.Get(ctx, client, u)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// postFormJSON posts the form and decodes the JSON response.
+// The bodies of the 400 responses are decoded too, as they carry the OAuth errors such as "authorization_pending".
+func postFormJSON(ctx context.Context, client *http.Client, u string, form url.Values, v any) error {
+	res, err := ctxhttp.PostForm(ctx, client, u, form)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 && res.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
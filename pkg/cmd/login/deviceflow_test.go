@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// newDeviceFlowServer returns an authorization server that approves the device code after pending polls,
+// or denies it when pending is negative.
+func newDeviceFlowServer(t *testing.T, pending int) *httptest.Server {
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcConfiguration{
+			DeviceAuthorizationEndpoint: srv.URL + "/device",
+			TokenEndpoint:               srv.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.PostFormValue("client_id"), "nerdctl")
+		assert.Equal(t, r.PostFormValue("scope"), DefaultOAuthScope)
+		json.NewEncoder(w).Encode(deviceAuthorization{
+			DeviceCode:      "device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: srv.URL + "/activate",
+			ExpiresIn:       60,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.PostFormValue("grant_type"), deviceCodeGrantType)
+		assert.Equal(t, r.PostFormValue("device_code"), "device-code")
+		switch {
+		case pending < 0:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(deviceToken{Error: "access_denied"})
+		case pending > 0:
+			pending--
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(deviceToken{Error: "authorization_pending"})
+		default:
+			json.NewEncoder(w).Encode(deviceToken{AccessToken: "access-token", RefreshToken: "refresh-token"})
+		}
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDeviceFlow(t *testing.T) {
+	defaultDeviceFlowInterval = 10 * time.Millisecond
+
+	srv := newDeviceFlowServer(t, 2)
+	var stdout bytes.Buffer
+	tok, err := deviceFlow(context.Background(), srv.Client(), srv.URL, "nerdctl", DefaultOAuthScope, &stdout)
+	assert.NilError(t, err)
+	assert.Equal(t, tok.RefreshToken, "refresh-token")
+	assert.Assert(t, strings.Contains(stdout.String(), srv.URL+"/activate"), stdout.String())
+	assert.Assert(t, strings.Contains(stdout.String(), "ABCD-EFGH"), stdout.String())
+
+	srv = newDeviceFlowServer(t, -1)
+	_, err = deviceFlow(context.Background(), srv.Client(), srv.URL, "nerdctl", DefaultOAuthScope, &stdout)
+	assert.ErrorContains(t, err, "access_denied")
+
+	_, err = deviceFlow(context.Background(), srv.Client(), srv.URL, "", DefaultOAuthScope, &stdout)
+	assert.ErrorContains(t, err, "client ID is required")
+}
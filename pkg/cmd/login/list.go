@@ -0,0 +1,49 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+// List prints the registries with stored credentials, and the config file or the credential helper storing them.
+// The secrets are not printed.
+func List(stdout io.Writer) error {
+	credStore, err := dockerconfigresolver.NewCredentialsStore("")
+	if err != nil {
+		return err
+	}
+	list, err := credStore.List()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tUSERNAME\tTYPE\tSTORE")
+	for _, stored := range list {
+		typ := "password"
+		if stored.IdentityToken {
+			typ = "identity token"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stored.ServerAddress, stored.Username, typ, stored.Store)
+	}
+	return w.Flush()
+}
@@ -41,6 +41,14 @@ Configure a credential helper to remove this warning. See
 https://docs.docker.com/engine/reference/commandline/login/#credentials-store
 `
 
+const unencryptedIdentityTokenWarning = `WARNING: Your identity token will be stored unencrypted in %s.
+It can be used to obtain new access tokens until it is revoked. Configure a credential helper to remove this warning. See
+https://docs.docker.com/engine/reference/commandline/login/#credentials-store
+`
+
+// oauthAccessTokenUsername is the username of the OAuth2 access tokens used as passwords, when --username is not specified
+const oauthAccessTokenUsername = "oauth2accesstoken"
+
 func Login(ctx context.Context, options types.LoginCommandOptions, stdout io.Writer) error {
 	registryURL, err := dockerconfigresolver.Parse(options.ServerAddress)
 	if err != nil {
@@ -54,23 +62,51 @@ func Login(ctx context.Context, options types.LoginCommandOptions, stdout io.Wri
 
 	var responseIdentityToken string
 
-	credentials, err := credStore.Retrieve(registryURL, options.Username == "" && options.Password == "")
+	credentials, err := credStore.Retrieve(registryURL, options.Username == "" && options.Password == "" && options.IdentityToken == "" && !options.DeviceFlow)
 	credentials.IdentityToken = ""
 
-	if err == nil && credentials.Username != "" && credentials.Password != "" {
+	switch {
+	case options.IdentityToken != "":
+		credentials.Username, credentials.Password, credentials.IdentityToken = "", "", options.IdentityToken
 		responseIdentityToken, err = loginClientSide(ctx, options.GOptions, registryURL, credentials)
-	}
-
-	if err != nil || credentials.Username == "" || credentials.Password == "" {
-		err = promptUserForAuthentication(credentials, options.Username, options.Password, stdout)
 		if err != nil {
 			return err
 		}
-
+	case options.DeviceFlow:
+		tok, err := deviceFlow(ctx, http.DefaultClient, options.OAuthIssuer, options.OAuthClientID, options.OAuthScope, stdout)
+		if err != nil {
+			return err
+		}
+		credentials.Username, credentials.Password, credentials.IdentityToken = "", "", ""
+		if tok.RefreshToken != "" {
+			credentials.IdentityToken = tok.RefreshToken
+		} else {
+			// Without a refresh token, the access token is used as the password, as the registries of Google Cloud do
+			credentials.Username, credentials.Password = options.Username, tok.AccessToken
+			if credentials.Username == "" {
+				credentials.Username = oauthAccessTokenUsername
+			}
+		}
 		responseIdentityToken, err = loginClientSide(ctx, options.GOptions, registryURL, credentials)
 		if err != nil {
 			return err
 		}
+	default:
+		if err == nil && credentials.Username != "" && credentials.Password != "" {
+			responseIdentityToken, err = loginClientSide(ctx, options.GOptions, registryURL, credentials)
+		}
+
+		if err != nil || credentials.Username == "" || credentials.Password == "" {
+			err = promptUserForAuthentication(credentials, options.Username, options.Password, stdout)
+			if err != nil {
+				return err
+			}
+
+			responseIdentityToken, err = loginClientSide(ctx, options.GOptions, registryURL, credentials)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if responseIdentityToken != "" {
@@ -85,6 +121,11 @@ func Login(ctx context.Context, options types.LoginCommandOptions, stdout io.Wri
 		if err != nil {
 			return err
 		}
+	} else if storageFileLocation != "" && credentials.IdentityToken != "" && (options.IdentityToken != "" || options.DeviceFlow) {
+		_, err = fmt.Fprintln(stdout, fmt.Sprintf(unencryptedIdentityTokenWarning, storageFileLocation))
+		if err != nil {
+			return err
+		}
 	}
 
 	err = credStore.Store(registryURL, credentials)
@@ -116,6 +157,7 @@ func loginClientSide(ctx context.Context, globalOptions types.GlobalCommandOptio
 		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 	}
 	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(globalOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(globalOptions.Registries))
 
 	authCreds := func(acArg string) (string, string, error) {
 		if acArg == host {
@@ -124,6 +166,10 @@ func loginClientSide(ctx context.Context, globalOptions types.GlobalCommandOptio
 				// so, nobody is actually using RegistryToken?
 				log.G(ctx).Warnf("RegistryToken (for %q) is not supported yet (FIXME)", host)
 			}
+			if credentials.IdentityToken != "" {
+				// An empty username makes the authorizer exchange the identity token (a refresh token) for an access token
+				return "", credentials.IdentityToken, nil
+			}
 			return credentials.Username, credentials.Password, nil
 		}
 		return "", "", fmt.Errorf("expected acArg to be %q, got %q", host, acArg)
@@ -18,6 +18,8 @@ package logout
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
 )
@@ -36,6 +38,20 @@ func Logout(ctx context.Context, logoutServer string) (map[string]error, error)
 	return credentialsStore.Erase(reg)
 }
 
+// LogoutAll erases the credentials of all the registries, printing their server addresses to stdout.
+func LogoutAll(ctx context.Context, stdout io.Writer) (map[string]error, error) {
+	credentialsStore, err := dockerconfigresolver.NewCredentialsStore("")
+	if err != nil {
+		return nil, err
+	}
+
+	erased, errs, err := credentialsStore.EraseAll()
+	for _, serverAddress := range erased {
+		fmt.Fprintf(stdout, "Removing login credentials for %s\n", serverAddress)
+	}
+	return errs, err
+}
+
 func ShellCompletion() ([]string, error) {
 	credentialsStore, err := dockerconfigresolver.NewCredentialsStore("")
 	if err != nil {
@@ -47,6 +47,14 @@ func Inspect(ctx context.Context, client *containerd.Client, inspectedNamespaces
 			Name:   ns,
 			Labels: &labels,
 		}
+		if options.Usage {
+			if nsInspect.Usage, err = Usage(ctx, client, options.GOptions.Snapshotter); err != nil {
+				return err
+			}
+			if nsInspect.Usage.Quota, err = diskQuota(options.GOptions, ns); err != nil {
+				warns = append(warns, err)
+			}
+		}
 		result = append(result, nsInspect)
 	}
 	if err := formatter.FormatSlice(options.Format, options.Stdout, result); err != nil {
@@ -0,0 +1,126 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/go-units"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+)
+
+// Usage returns the disk usage of the namespace of ctx.
+func Usage(ctx context.Context, client *containerd.Client, snapshotter string) (*native.NamespaceUsage, error) {
+	usage := &native.NamespaceUsage{}
+
+	imgs, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage.Images.Count = len(imgs)
+	if err := client.ContentStore().Walk(ctx, func(info content.Info) error {
+		usage.Images.Size += info.Size
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sn := containerdutil.SnapshotService(client, snapshotter)
+	sizes := make(map[string]int64)
+	if err := sn.Walk(ctx, func(ctx context.Context, info snapshots.Info) error {
+		u, err := sn.Usage(ctx, info.Name)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		sizes[info.Name] = u.Size
+		usage.Snapshots.Count++
+		usage.Snapshots.Size += u.Size
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		usage.Containers.Count++
+		if info.Snapshotter == snapshotter {
+			usage.Containers.Size += sizes[info.SnapshotKey]
+		}
+	}
+
+	usage.Size = usage.Images.Size + usage.Snapshots.Size
+	return usage, nil
+}
+
+// CheckQuota compares the disk usage of the namespace with its quota configured in nerdctl.toml.
+// A warning is printed when the quota is exceeded, or an error is returned when the quota is enforced.
+func CheckQuota(ctx context.Context, client *containerd.Client, globalOptions types.GlobalCommandOptions) error {
+	quota, err := diskQuota(globalOptions, globalOptions.Namespace)
+	if err != nil || quota == 0 {
+		return err
+	}
+	usage, err := Usage(ctx, client, globalOptions.Snapshotter)
+	if err != nil {
+		return err
+	}
+	if usage.Size <= quota {
+		return nil
+	}
+	msg := fmt.Sprintf("namespace %q uses %s, exceeding its disk quota of %s", globalOptions.Namespace,
+		units.BytesSize(float64(usage.Size)), units.BytesSize(float64(quota)))
+	if globalOptions.Quotas[globalOptions.Namespace].Enforce {
+		return fmt.Errorf("%s: %w", msg, errdefs.ErrResourceExhausted)
+	}
+	log.G(ctx).Warn(msg)
+	return nil
+}
+
+// diskQuota returns the disk quota of the namespace in bytes, or 0 when it is not configured.
+func diskQuota(globalOptions types.GlobalCommandOptions, ns string) (int64, error) {
+	q, ok := globalOptions.Quotas[ns]
+	if !ok || q.Disk == "" {
+		return 0, nil
+	}
+	quota, err := units.RAMInBytes(q.Disk)
+	if err != nil {
+		return 0, fmt.Errorf("invalid disk quota %q of namespace %q: %w", q.Disk, ns, err)
+	}
+	return quota, nil
+}
@@ -23,10 +23,14 @@ import (
 	"github.com/containerd/errdefs"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 )
 
 func Create(options types.NetworkCreateOptions, stdout io.Writer) error {
+	if err := clientutil.CheckLocalState(options.GOptions.Address); err != nil {
+		return err
+	}
 	if len(options.Subnets) == 0 {
 		if options.Gateway != "" || options.IPRange != "" {
 			return fmt.Errorf("cannot set gateway or ip-range without subnet, specify --subnet manually")
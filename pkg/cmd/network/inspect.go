@@ -26,6 +26,7 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
@@ -35,6 +36,9 @@ import (
 )
 
 func Inspect(ctx context.Context, client *containerd.Client, options types.NetworkInspectOptions) error {
+	if err := clientutil.CheckLocalState(options.GOptions.Address); err != nil {
+		return err
+	}
 	if options.Mode != "native" && options.Mode != "dockercompat" {
 		return fmt.Errorf("unknown mode %q", options.Mode)
 	}
@@ -78,12 +82,18 @@ func Inspect(ctx context.Context, client *containerd.Client, options types.Netwo
 			containers = append(containers, nativeContainer)
 		}
 
+		iface, err := network.Interface()
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to inspect the interface of network %q", network.Name)
+		}
+
 		r := &native.Network{
 			CNI:           json.RawMessage(network.Bytes),
 			NerdctlID:     network.NerdctlID,
 			NerdctlLabels: network.NerdctlLabels,
 			File:          network.File,
 			Containers:    containers,
+			Interface:     iface,
 		}
 		switch options.Mode {
 		case "native":
@@ -27,6 +27,7 @@ import (
 	"text/template"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 )
@@ -36,10 +37,24 @@ type networkPrintable struct {
 	Name   string
 	Labels string
 	// TODO: "CreatedAt", "Driver", "IPv6", "Internal", "Scope"
-	file string
+	file   string
+	labels map[string]string
+}
+
+// Label returns the value of the label, for `--format '{{.Label "key"}}'`.
+func (p networkPrintable) Label(name string) string {
+	return p.labels[name]
+}
+
+// networkTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var networkTableHeaders = map[string]string{
+	"ID": "NETWORK ID",
 }
 
 func List(ctx context.Context, options types.NetworkListOptions) error {
+	if err := clientutil.CheckLocalState(options.GOptions.Address); err != nil {
+		return err
+	}
 	globalOptions := options.GOptions
 	quiet := options.Quiet
 	format := options.Format
@@ -60,7 +75,7 @@ func List(ctx context.Context, options types.NetworkListOptions) error {
 			return errors.New("format and quiet must not be specified together")
 		}
 		var err error
-		tmpl, err = formatter.ParseTemplate(format)
+		w, tmpl, err = formatter.ParseListTemplate(w, format, networkTableHeaders, quiet)
 		if err != nil {
 			return err
 		}
@@ -103,6 +118,7 @@ func List(ctx context.Context, options types.NetworkListOptions) error {
 		}
 		if n.NerdctlLabels != nil {
 			p.Labels = formatter.FormatLabels(*n.NerdctlLabels)
+			p.labels = *n.NerdctlLabels
 		}
 		pp[i] = p
 	}
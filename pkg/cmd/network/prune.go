@@ -19,16 +19,28 @@ package network
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/prune"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 func Prune(ctx context.Context, client *containerd.Client, options types.NetworkPruneOptions) error {
+	if err := clientutil.CheckLocalState(options.GOptions.Address); err != nil {
+		return err
+	}
+	filters, err := prune.ParseFilters(options.Filters, time.Now())
+	if err != nil {
+		return err
+	}
+
 	e, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
 	if err != nil {
 		return err
@@ -55,15 +67,24 @@ func Prune(ctx context.Context, client *containerd.Client, options types.Network
 		if _, ok := usedNetworks[net.Name]; ok {
 			continue
 		}
-		if err := e.RemoveNetwork(net); err != nil {
-			log.G(ctx).WithError(err).Errorf("failed to remove network %s", net.Name)
+		if !matchPruneFilters(net, filters) {
 			continue
 		}
+		if !options.DryRun {
+			if err := e.RemoveNetwork(net); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to remove network %s", net.Name)
+				continue
+			}
+		}
 		removedNetworks = append(removedNetworks, net.Name)
 	}
 
 	if len(removedNetworks) > 0 {
-		fmt.Fprintln(options.Stdout, "Deleted Networks:")
+		if options.DryRun {
+			fmt.Fprintln(options.Stdout, "Would Delete Networks:")
+		} else {
+			fmt.Fprintln(options.Stdout, "Deleted Networks:")
+		}
 		for _, name := range removedNetworks {
 			fmt.Fprintln(options.Stdout, name)
 		}
@@ -71,3 +92,19 @@ func Prune(ctx context.Context, client *containerd.Client, options types.Network
 	}
 	return nil
 }
+
+// matchPruneFilters returns whether the network matches the filters of `nerdctl network prune`.
+// The creation time of a network is the modification time of its config file.
+func matchPruneFilters(net *netutil.NetworkConfig, filters *prune.Filters) bool {
+	if !filters.Until.IsZero() {
+		st, err := os.Stat(net.File)
+		if err != nil || !filters.MatchCreated(st.ModTime()) {
+			return false
+		}
+	}
+	var labels map[string]string
+	if net.NerdctlLabels != nil {
+		labels = *net.NerdctlLabels
+	}
+	return filters.MatchLabels(labels)
+}
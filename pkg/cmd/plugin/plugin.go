@@ -0,0 +1,264 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package plugin implements the external CLI plugins: the executables named "nerdctl-<NAME>" on $PATH,
+// which are run as `nerdctl <NAME>`, like the plugins of kubectl and Docker CLI.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+// Prefix is the prefix of the file names of the plugins.
+const Prefix = "nerdctl-"
+
+// Plugin is a plugin executable found on $PATH.
+type Plugin struct {
+	Name string
+	Path string
+	// Shadowed is true when a plugin with the same name precedes this one on $PATH
+	Shadowed bool
+	// Builtin is true when a built-in command has the same name, so the plugin is never run
+	Builtin bool
+}
+
+// ValidName returns whether name can be the name of a plugin, e.g., "foo" for "nerdctl-foo".
+func ValidName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") || strings.HasPrefix(name, "_") {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\:`)
+}
+
+// Lookup returns the path of the plugin with the given name, or an error wrapping exec.ErrNotFound.
+func Lookup(name string) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("invalid plugin name %q: %w", name, exec.ErrNotFound)
+	}
+	return exec.LookPath(Prefix + name)
+}
+
+// Discover returns the plugins on $PATH, in the order of $PATH.
+// The plugins shadowed by the preceding ones are returned too, with Shadowed set.
+func Discover() []Plugin {
+	var (
+		plugins []Plugin
+		seen    = make(map[string]bool)
+	)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			// as exec.LookPath does not look up the current directory either
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok || e.IsDir() {
+				continue
+			}
+			p := filepath.Join(dir, e.Name())
+			if !isExecutable(p) {
+				continue
+			}
+			plugins = append(plugins, Plugin{Name: name, Path: p, Shadowed: seen[name]})
+			seen[name] = true
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the plugin name of the file name, e.g., "foo" for "nerdctl-foo" (or "nerdctl-foo.exe" on Windows).
+func pluginName(fileName string) (string, bool) {
+	name, ok := strings.CutPrefix(fileName, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !strings.EqualFold(ext, ".exe") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, ValidName(name)
+}
+
+func isExecutable(p string) bool {
+	st, err := os.Stat(p)
+	if err != nil || st.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return st.Mode().Perm()&0o111 != 0
+}
+
+// Environ returns the environment of the plugin: the environment of nerdctl, with the global options and the
+// connection settings in effect. The variables read by nerdctl itself (e.g. $CONTAINERD_NAMESPACE) are set as well,
+// so that the plugin can run $NERDCTL_BINARY with the same settings.
+func Environ(options types.PluginRunOptions) []string {
+	gOptions := options.GOptions
+	env := map[string]string{
+		"CONTAINERD_ADDRESS":      gOptions.Address,
+		"CONTAINERD_NAMESPACE":    gOptions.Namespace,
+		"CONTAINERD_SNAPSHOTTER":  gOptions.Snapshotter,
+		"CNI_PATH":                gOptions.CNIPath,
+		"NETCONFPATH":             gOptions.CNINetConfPath,
+		"NERDCTL_EXPERIMENTAL":    strconv.FormatBool(gOptions.Experimental),
+		"NERDCTL_HOST_GATEWAY_IP": gOptions.HostGatewayIP,
+		"NERDCTL_BRIDGE_IP":       gOptions.BridgeIP,
+		// not read by nerdctl
+		"NERDCTL_DATA_ROOT":      gOptions.DataRoot,
+		"NERDCTL_CGROUP_MANAGER": gOptions.CgroupManager,
+		"NERDCTL_HOSTS_DIR":      strings.Join(gOptions.HostsDir, string(os.PathListSeparator)),
+		"NERDCTL_DEBUG":          strconv.FormatBool(gOptions.Debug || gOptions.DebugFull),
+	}
+	if options.Context != "" {
+		env["NERDCTL_CONTEXT"] = options.Context
+	}
+	if options.NerdctlTOML != "" {
+		env["NERDCTL_TOML"] = options.NerdctlTOML
+	}
+	if self, err := os.Executable(); err == nil {
+		env["NERDCTL_BINARY"] = self
+	}
+	res := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		k, _, _ := strings.Cut(kv, "=")
+		_, ok := env[k]
+		return ok
+	})
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		res = append(res, k+"="+env[k])
+	}
+	return res
+}
+
+// Run runs the plugin, and waits for it to exit.
+// A non-zero exit status of the plugin is returned as an errutil.ExitCoder.
+func Run(ctx context.Context, options types.PluginRunOptions) error {
+	log.G(ctx).Debugf("running plugin %q with args %v", options.Path, options.Args)
+	cmd := exec.Command(options.Path, options.Args...)
+	cmd.Stdin = options.Stdin
+	cmd.Stdout = options.Stdout
+	cmd.Stderr = options.Stderr
+	cmd.Env = Environ(options)
+
+	// The plugin is in the same process group, so it receives Ctrl-C from the terminal by itself.
+	// nerdctl has to keep waiting for the plugin to exit, instead of exiting on the signal.
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errutil.NewExitCoderErr(exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run plugin %q: %w", options.Path, err)
+	}
+	return nil
+}
+
+// List lists the plugins on $PATH.
+func List(options types.PluginListOptions) error {
+	plugins := Discover()
+	for i := range plugins {
+		plugins[i].Builtin = slices.Contains(options.Builtins, plugins[i].Name)
+	}
+
+	w := options.Stdout
+	var (
+		tmpl *template.Template
+		err  error
+	)
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		if !options.Quiet {
+			fmt.Fprintln(w, "NAME\tPATH\tSTATUS")
+		}
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		if options.Quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		if w, tmpl, err = formatter.ParseListTemplate(w, options.Format, nil, false); err != nil {
+			return err
+		}
+	}
+	for _, p := range plugins {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, p); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		if options.Quiet {
+			if p.Shadowed || p.Builtin {
+				continue
+			}
+			if _, err := fmt.Fprintln(w, p.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Path, status(p)); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func status(p Plugin) string {
+	switch {
+	case p.Builtin:
+		return "overridden by the built-in command"
+	case p.Shadowed:
+		return "shadowed by a preceding plugin on $PATH"
+	default:
+		return "ok"
+	}
+}
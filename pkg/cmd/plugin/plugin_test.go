@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are .exe files on Windows")
+	}
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	for _, f := range []struct {
+		path string
+		perm os.FileMode
+	}{
+		{filepath.Join(dir1, "nerdctl-foo"), 0o755},
+		{filepath.Join(dir1, "nerdctl-not-executable"), 0o644},
+		{filepath.Join(dir1, "not-a-plugin"), 0o755},
+		{filepath.Join(dir2, "nerdctl-foo"), 0o755},
+		{filepath.Join(dir2, "nerdctl-bar"), 0o755},
+	} {
+		assert.NilError(t, os.WriteFile(f.path, []byte("#!/bin/sh\n"), f.perm))
+	}
+	assert.NilError(t, os.Mkdir(filepath.Join(dir1, "nerdctl-dir"), 0o755))
+	t.Setenv("PATH", dir1+string(os.PathListSeparator)+dir2)
+
+	assert.DeepEqual(t, Discover(), []Plugin{
+		{Name: "foo", Path: filepath.Join(dir1, "nerdctl-foo")},
+		{Name: "bar", Path: filepath.Join(dir2, "nerdctl-bar")},
+		{Name: "foo", Path: filepath.Join(dir2, "nerdctl-foo"), Shadowed: true},
+	})
+
+	p, err := Lookup("foo")
+	assert.NilError(t, err)
+	assert.Equal(t, p, filepath.Join(dir1, "nerdctl-foo"))
+	_, err = Lookup("not-executable")
+	assert.Assert(t, err != nil)
+	_, err = Lookup("../not-a-plugin")
+	assert.Assert(t, err != nil)
+}
+
+func TestEnviron(t *testing.T) {
+	t.Setenv("CONTAINERD_NAMESPACE", "overridden")
+	t.Setenv("NERDCTL_CONTEXT", "overridden")
+	env := Environ(types.PluginRunOptions{
+		GOptions: types.GlobalCommandOptions{
+			Address:   "/run/containerd/containerd.sock",
+			Namespace: "k8s.io",
+			HostsDir:  []string{"/etc/containerd/certs.d", "/etc/docker/certs.d"},
+			Debug:     true,
+		},
+		Context:     "remote",
+		NerdctlTOML: "/etc/nerdctl/nerdctl.toml",
+	})
+	for _, kv := range []string{
+		"CONTAINERD_ADDRESS=/run/containerd/containerd.sock",
+		"CONTAINERD_NAMESPACE=k8s.io",
+		"NERDCTL_CONTEXT=remote",
+		"NERDCTL_DEBUG=true",
+		"NERDCTL_EXPERIMENTAL=false",
+		"NERDCTL_TOML=/etc/nerdctl/nerdctl.toml",
+		"NERDCTL_HOSTS_DIR=/etc/containerd/certs.d" + string(os.PathListSeparator) + "/etc/docker/certs.d",
+	} {
+		assert.Assert(t, slices.Contains(env, kv), kv)
+	}
+	assert.Assert(t, !slices.Contains(env, "CONTAINERD_NAMESPACE=overridden"))
+	assert.Assert(t, !slices.Contains(env, "NERDCTL_CONTEXT=overridden"))
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package prune provides the filters shared by the prune commands.
+package prune
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	timetypes "github.com/docker/docker/api/types/time"
+
+	"github.com/containerd/errdefs"
+)
+
+// Filters are the parsed until and label filters of the prune commands.
+type Filters struct {
+	// Until is the time before which the objects were created, zero when the until filter is not set
+	Until time.Time
+	// Labels are the label and label! filters as written, e.g., "label=foo" or "label!=foo=bar"
+	Labels []string
+}
+
+// ParseFilters parses the filters of the prune commands, relative to now.
+//
+// Supported filters:
+//   - until=<timestamp>: Only remove the objects created before the timestamp, e.g., "24h" or "2006-01-02T15:04:05".
+//   - label=<key> or label=<key>=<value>: Only remove the objects with the label.
+//   - label!=<key> or label!=<key>=<value>: Only remove the objects without the label.
+func ParseFilters(filters []string, now time.Time) (*Filters, error) {
+	res := &Filters{}
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad format of filter %q (expected name=value): %w", filter, errdefs.ErrInvalidArgument)
+		}
+		switch key {
+		case "until":
+			if !res.Until.IsZero() {
+				return nil, fmt.Errorf("more than one until filter provided: %w", errdefs.ErrInvalidArgument)
+			}
+			ts, err := timetypes.GetTimestamp(value, now)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
+			}
+			sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
+			}
+			res.Until = time.Unix(sec, nsec)
+		case "label", "label!":
+			if value == "" {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
+			}
+			res.Labels = append(res.Labels, filter)
+		default:
+			return nil, fmt.Errorf("invalid filter %q: %w", key, errdefs.ErrInvalidArgument)
+		}
+	}
+	return res, nil
+}
+
+// MatchCreated returns whether an object created at the time matches the until filter.
+func (f *Filters) MatchCreated(created time.Time) bool {
+	return f.Until.IsZero() || created.Before(f.Until)
+}
+
+// MatchLabels returns whether the labels match all the label and label! filters.
+func (f *Filters) MatchLabels(labels map[string]string) bool {
+	for _, filter := range f.Labels {
+		key, value, _ := strings.Cut(filter, "=")
+		k, v, hasValue := strings.Cut(value, "=")
+		val, ok := labels[k]
+		matched := ok && (!hasValue || val == v)
+		if matched == (key == "label!") {
+			return false
+		}
+	}
+	return true
+}
+
+// Strings returns the filters in the form accepted by ParseFilters.
+func (f *Filters) Strings() []string {
+	res := append([]string{}, f.Labels...)
+	if !f.Until.IsZero() {
+		res = append(res, "until="+f.Until.Format(time.RFC3339Nano))
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package prune
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseFilters(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := ParseFilters([]string{"until=1h", "label=foo", "label!=bar=baz"}, now)
+	assert.NilError(t, err)
+	assert.Assert(t, f.Until.Equal(now.Add(-time.Hour)), f.Until)
+	assert.DeepEqual(t, f.Labels, []string{"label=foo", "label!=bar=baz"})
+	assert.DeepEqual(t, f.Strings(), []string{"label=foo", "label!=bar=baz", "until=" + now.Add(-time.Hour).Local().Format(time.RFC3339Nano)})
+
+	f, err = ParseFilters(nil, now)
+	assert.NilError(t, err)
+	assert.Equal(t, len(f.Strings()), 0)
+
+	for _, invalid := range [][]string{
+		{"until"},
+		{"until=yesterday"},
+		{"until=1h", "until=2h"},
+		{"label="},
+		{"dangling=true"},
+	} {
+		_, err := ParseFilters(invalid, now)
+		assert.ErrorContains(t, err, "", invalid)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := ParseFilters([]string{"until=1h", "label=foo", "label!=bar=baz"}, now)
+	assert.NilError(t, err)
+	assert.Assert(t, f.MatchCreated(now.Add(-2*time.Hour)))
+	assert.Assert(t, !f.MatchCreated(now))
+	assert.Assert(t, f.MatchLabels(map[string]string{"foo": "", "bar": "qux"}))
+	assert.Assert(t, !f.MatchLabels(map[string]string{"foo": "", "bar": "baz"}))
+	assert.Assert(t, !f.MatchLabels(map[string]string{"bar": "qux"}))
+	assert.Assert(t, !f.MatchLabels(nil))
+
+	f, err = ParseFilters(nil, now)
+	assert.NilError(t, err)
+	assert.Assert(t, f.MatchCreated(now))
+	assert.Assert(t, f.MatchLabels(nil))
+}
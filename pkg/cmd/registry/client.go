@@ -0,0 +1,166 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package registry implements `nerdctl search`, `nerdctl registry`, and the query of `nerdctl system ratelimit`.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+// pageSize is the number of the entries requested per page of the _catalog and tags/list endpoints
+const pageSize = 100
+
+// linkNextRegexp matches the Link header of the paginated responses, e.g., `</v2/_catalog?last=foo&n=100>; rel="next"`
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// client is a client of the HTTP API of a registry, authenticated with the credentials of `nerdctl login`.
+type client struct {
+	host docker.RegistryHost
+}
+
+func newClient(ctx context.Context, globalOptions types.GlobalCommandOptions, host string, plainHTTP bool) (*client, error) {
+	var dOpts []dockerconfigresolver.Opt
+	if globalOptions.InsecureRegistry {
+		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", host)
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	if plainHTTP {
+		dOpts = append(dOpts, dockerconfigresolver.WithPlainHTTP(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(globalOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(globalOptions.Registries))
+	dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!globalOptions.NoTokenCache))
+	ho, err := dockerconfigresolver.NewHostOptions(ctx, host, dOpts...)
+	if err != nil {
+		return nil, err
+	}
+	regHosts, err := dockerconfig.ConfigureHosts(ctx, *ho)(host)
+	if err != nil {
+		return nil, err
+	}
+	if len(regHosts) == 0 {
+		return nil, fmt.Errorf("got empty []docker.RegistryHost for %q", host)
+	}
+	// The mirrors precede the registry itself, which is the only one expected to serve the listings
+	return &client{host: regHosts[len(regHosts)-1]}, nil
+}
+
+// withClient calls fn with a client of the host, falling back to plain HTTP with --insecure-registry like `nerdctl push`.
+func withClient(ctx context.Context, globalOptions types.GlobalCommandOptions, host string, fn func(*client) error) error {
+	c, err := newClient(ctx, globalOptions, host, false)
+	if err != nil {
+		return err
+	}
+	err = fn(c)
+	if err == nil || !globalOptions.InsecureRegistry || (!errors.Is(err, http.ErrSchemeMismatch) && !errutil.IsErrConnectionRefused(err)) {
+		return err
+	}
+	log.G(ctx).WithError(err).Warnf("server %q does not seem to support HTTPS, falling back to plain HTTP", host)
+	if c, err = newClient(ctx, globalOptions, host, true); err != nil {
+		return err
+	}
+	return fn(c)
+}
+
+// getJSON gets the path of the registry (e.g., "/v2/_catalog?n=100") and decodes the JSON response into v.
+// The next page of the paginated responses is returned, or the empty string on the last page.
+func (c *client) getJSON(ctx context.Context, path string, v any) (string, error) {
+	res, err := c.do(ctx, http.MethodGet, path, "application/json")
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode the response of %s: %w", res.Request.URL, err)
+	}
+	if m := linkNextRegexp.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+		return m[1], nil
+	}
+	return "", nil
+}
+
+// do sends a request to the path of the registry, authenticating with the registry when challenged.
+// The response is returned when its status code is 2xx, and has to be closed.
+func (c *client) do(ctx context.Context, method, path, accept string) (*http.Response, error) {
+	u, err := url.Parse(c.host.Scheme + "://" + c.host.Host)
+	if err != nil {
+		return nil, err
+	}
+	if u, err = u.Parse(path); err != nil {
+		return nil, err
+	}
+	var responses []*http.Response
+	for range 3 {
+		req, err := http.NewRequest(method, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, vv := range c.host.Header.Clone() {
+			for _, v := range vv {
+				req.Header.Add(k, v)
+			}
+		}
+		req.Header.Set("Accept", accept)
+		if c.host.Authorizer != nil {
+			if err := c.host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		res, err := ctxhttp.Do(ctx, c.host.Client, req)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == http.StatusUnauthorized && c.host.Authorizer != nil {
+			res.Body.Close()
+			responses = append(responses, res)
+			if err := c.host.Authorizer.AddResponses(ctx, responses); err != nil {
+				if errdefs.IsNotImplemented(err) {
+					return nil, fmt.Errorf("unauthorized to access %s (Hint: try `nerdctl login %s`)", u, c.host.Host)
+				}
+				return nil, err
+			}
+			continue
+		}
+		switch {
+		case res.StatusCode == http.StatusNotFound:
+			res.Body.Close()
+			return nil, fmt.Errorf("%s: %w", u, errdefs.ErrNotFound)
+		case res.StatusCode/100 != 2:
+			res.Body.Close()
+			return nil, fmt.Errorf("unexpected status code %d from %s", res.StatusCode, u)
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("unauthorized to access %s (Hint: try `nerdctl login %s`)", u, c.host.Host)
+}
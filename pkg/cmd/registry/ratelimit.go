@@ -0,0 +1,52 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/images"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+// rateLimitPath is the manifest that Docker Hub reports the rate limit for.
+// The HEAD requests are not counted as pulls.
+const rateLimitPath = "/v2/ratelimitpreview/test/manifests/latest"
+
+// QueryRateLimit queries the pull rate limit of Docker Hub, with the credentials of `nerdctl login docker.io` if any.
+// It returns nil when Docker Hub does not report a rate limit.
+func QueryRateLimit(ctx context.Context, globalOptions types.GlobalCommandOptions) (*dockerconfigresolver.RateLimit, error) {
+	var rl *dockerconfigresolver.RateLimit
+	err := withClient(ctx, globalOptions, "docker.io", func(c *client) error {
+		accept := strings.Join([]string{images.MediaTypeDockerSchema2Manifest, images.MediaTypeDockerSchema2ManifestList,
+			ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex}, ", ")
+		res, err := c.do(ctx, http.MethodHead, rateLimitPath, accept)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		rl, err = dockerconfigresolver.ParseRateLimit(res.Header)
+		return err
+	})
+	return rl, err
+}
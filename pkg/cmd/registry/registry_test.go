@@ -0,0 +1,126 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// newRegistryServer returns a registry serving the repositories on a _catalog endpoint paginated by two,
+// without the search API.
+func newRegistryServer(t *testing.T, repos ...string) string {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/_catalog", func(w http.ResponseWriter, r *http.Request) {
+		start := 0
+		if last := r.URL.Query().Get("last"); last != "" {
+			for i, repo := range repos {
+				if repo == last {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+2, len(repos))
+		if end < len(repos) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/_catalog?last=%s&n=2>; rel="next"`, repos[end-1]))
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": repos[start:end]})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestRepos(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	host := newRegistryServer(t, "alpine", "busybox", "library/nginx", "nginx-debug", "ubuntu")
+	gOptions := types.GlobalCommandOptions{InsecureRegistry: true}
+
+	var stdout bytes.Buffer
+	err := Repos(context.Background(), host, types.RegistryReposOptions{Stdout: &stdout, GOptions: gOptions, Format: "{{.Name}}"})
+	assert.NilError(t, err)
+	assert.Equal(t, stdout.String(), "alpine\nbusybox\nlibrary/nginx\nnginx-debug\nubuntu\n")
+
+	stdout.Reset()
+	err = Repos(context.Background(), host, types.RegistryReposOptions{Stdout: &stdout, GOptions: gOptions, Format: "{{.Name}}", Limit: 3})
+	assert.NilError(t, err)
+	assert.Equal(t, stdout.String(), "alpine\nbusybox\nlibrary/nginx\n")
+
+	stdout.Reset()
+	err = Search(context.Background(), host+"/nginx", types.SearchOptions{Stdout: &stdout, GOptions: gOptions, Format: "{{.Name}}", Limit: DefaultSearchLimit})
+	assert.NilError(t, err)
+	assert.Equal(t, stdout.String(), host+"/library/nginx\n"+host+"/nginx-debug\n")
+}
+
+func TestSearchFilters(t *testing.T) {
+	results := []SearchResult{
+		{Name: "alpine", StarCount: 10, IsOfficial: true},
+		{Name: "foo/alpine", StarCount: 2, IsAutomated: true},
+		{Name: "bar/alpine"},
+	}
+	for _, tc := range []struct {
+		filters  []string
+		expected []string
+		err      string
+	}{
+		{expected: []string{"alpine", "foo/alpine", "bar/alpine"}},
+		{filters: []string{"stars=2"}, expected: []string{"alpine", "foo/alpine"}},
+		{filters: []string{"is-official=true"}, expected: []string{"alpine"}},
+		{filters: []string{"is-official=false", "is-automated=true"}, expected: []string{"foo/alpine"}},
+		{filters: []string{"stars=foo"}, err: "invalid filter"},
+		{filters: []string{"is-official"}, err: "bad format of filter"},
+		{filters: []string{"foo=bar"}, err: "invalid filter"},
+	} {
+		f, err := parseSearchFilters(tc.filters)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		var names []string
+		for _, r := range results {
+			if f.match(r) {
+				names = append(names, r.Name)
+			}
+		}
+		assert.DeepEqual(t, names, tc.expected)
+	}
+}
+
+func TestSplitSearchTerm(t *testing.T) {
+	for term, expected := range map[string][2]string{
+		"alpine":                      {indexHost, "alpine"},
+		"foo/alpine":                  {indexHost, "foo/alpine"},
+		"docker.io/alpine":            {indexHost, "alpine"},
+		"registry.example.com/alpine": {"registry.example.com", "alpine"},
+		"localhost:5000/foo/alpine":   {"localhost:5000", "foo/alpine"},
+		"localhost/alpine":            {"localhost", "alpine"},
+	} {
+		host, query := splitSearchTerm(term)
+		assert.DeepEqual(t, [2]string{host, query}, expected)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+type repository struct {
+	Name string
+}
+
+// Repos lists the repositories of the registry, from the _catalog endpoint.
+func Repos(ctx context.Context, host string, options types.RegistryReposOptions) error {
+	var names []string
+	if err := withClient(ctx, options.GOptions, host, func(c *client) error {
+		names = nil
+		path := fmt.Sprintf("/v2/_catalog?n=%d", pageLimit(options.Limit))
+		for path != "" {
+			var page struct {
+				Repositories []string `json:"repositories"`
+			}
+			next, err := c.getJSON(ctx, path, &page)
+			if err != nil {
+				return err
+			}
+			names = append(names, page.Repositories...)
+			if options.Limit > 0 && len(names) >= options.Limit {
+				names = names[:options.Limit]
+				break
+			}
+			path = next
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	items := make([]any, len(names))
+	for i, name := range names {
+		items[i] = repository{Name: name}
+	}
+	return printItems(options.Stdout, options.Format, "REPOSITORY", items, func(item any) string {
+		return item.(repository).Name
+	})
+}
+
+// pageLimit returns the page size of the paginated requests, not larger than the limit of the listed entries.
+func pageLimit(limit int) int {
+	if limit > 0 && limit < pageSize {
+		return limit
+	}
+	return pageSize
+}
+
+// printItems prints the items as a table (by default), or with the Go template of format.
+func printItems(w io.Writer, format, header string, items []any, row func(any) string) error {
+	switch format {
+	case "", "table":
+		tw := tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(tw, header)
+		for _, item := range items {
+			fmt.Fprintln(tw, row(item))
+		}
+		return tw.Flush()
+	case "raw", "wide":
+		return fmt.Errorf("unsupported format: %q", format)
+	default:
+		return formatter.FormatSlice(format, w, items)
+	}
+}
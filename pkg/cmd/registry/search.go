@@ -0,0 +1,189 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+// DefaultSearchLimit is the default maximum number of the results of `nerdctl search`, same as Docker
+const DefaultSearchLimit = 25
+
+// indexHost is the host serving the search API of Docker Hub
+const indexHost = "index.docker.io"
+
+// SearchResult is a result of `nerdctl search`, compatible with the results of the v1 search API of Docker Hub.
+type SearchResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	StarCount   int    `json:"star_count"`
+	IsOfficial  bool   `json:"is_official"`
+	IsAutomated bool   `json:"is_automated"`
+}
+
+type searchFilters struct {
+	stars       int
+	isOfficial  *bool
+	isAutomated *bool
+}
+
+func parseSearchFilters(filters []string) (*searchFilters, error) {
+	var f searchFilters
+	for _, filter := range filters {
+		k, v, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad format of filter (expected name=value): %q", filter)
+		}
+		switch k {
+		case "stars":
+			stars, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+			}
+			f.stars = stars
+		case "is-official", "is-automated":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+			}
+			if k == "is-official" {
+				f.isOfficial = &b
+			} else {
+				f.isAutomated = &b
+			}
+		default:
+			return nil, fmt.Errorf("invalid filter %q", k)
+		}
+	}
+	return &f, nil
+}
+
+func (f *searchFilters) match(r SearchResult) bool {
+	return r.StarCount >= f.stars &&
+		(f.isOfficial == nil || *f.isOfficial == r.IsOfficial) &&
+		(f.isAutomated == nil || *f.isAutomated == r.IsAutomated)
+}
+
+// splitSearchTerm splits the term into the registry host and the query, e.g., "registry.example.com/foo" into
+// "registry.example.com" and "foo". The host is Docker Hub unless the first component of the term looks like a host.
+func splitSearchTerm(term string) (string, string) {
+	host, query, ok := strings.Cut(term, "/")
+	switch {
+	case ok && (host == "docker.io" || host == indexHost):
+		return indexHost, query
+	case ok && (strings.ContainsAny(host, ".:") || host == "localhost"):
+		return host, query
+	}
+	return indexHost, term
+}
+
+// Search searches the repositories matching the term, with the v1 search API of Docker Hub and the registries
+// implementing it. Other registries are searched by the repository names of their _catalog endpoint.
+func Search(ctx context.Context, term string, options types.SearchOptions) error {
+	if options.Limit < 1 || options.Limit > 100 {
+		return fmt.Errorf("limit %d is outside the range of [1, 100]", options.Limit)
+	}
+	filters, err := parseSearchFilters(options.Filters)
+	if err != nil {
+		return err
+	}
+	host, query := splitSearchTerm(term)
+
+	var results []SearchResult
+	if err := withClient(ctx, options.GOptions, host, func(c *client) error {
+		results = nil
+		var res struct {
+			Results []SearchResult `json:"results"`
+		}
+		_, err := c.getJSON(ctx, fmt.Sprintf("/v1/search?q=%s&n=%d", url.QueryEscape(query), options.Limit), &res)
+		if err == nil {
+			results = res.Results
+			return nil
+		}
+		if host == indexHost || !errors.Is(err, errdefs.ErrNotFound) {
+			return err
+		}
+		log.G(ctx).WithError(err).Debugf("registry %q does not implement the search API, searching the catalog", host)
+		path := fmt.Sprintf("/v2/_catalog?n=%d", pageSize)
+		for path != "" && len(results) < options.Limit {
+			var page struct {
+				Repositories []string `json:"repositories"`
+			}
+			next, err := c.getJSON(ctx, path, &page)
+			if err != nil {
+				return err
+			}
+			for _, name := range page.Repositories {
+				if strings.Contains(name, query) {
+					results = append(results, SearchResult{Name: host + "/" + name})
+				}
+			}
+			path = next
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var filtered []SearchResult
+	for _, r := range results {
+		if filters.match(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	if len(filtered) > options.Limit {
+		filtered = filtered[:options.Limit]
+	}
+
+	switch options.Format {
+	case "", "table":
+		w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDESCRIPTION\tSTARS\tOFFICIAL")
+		for _, r := range filtered {
+			description := strings.ReplaceAll(r.Description, "\n", " ")
+			if !options.NoTrunc {
+				description = formatter.Ellipsis(description, 45)
+			}
+			official := ""
+			if r.IsOfficial {
+				official = "[OK]"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", r.Name, description, r.StarCount, official)
+		}
+		return w.Flush()
+	case "raw", "wide":
+		return fmt.Errorf("unsupported format: %q", options.Format)
+	default:
+		items := make([]any, len(filtered))
+		for i, r := range filtered {
+			items[i] = r
+		}
+		return formatter.FormatSlice(options.Format, options.Stdout, items)
+	}
+}
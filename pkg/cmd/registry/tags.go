@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+type tag struct {
+	Repository string
+	Tag        string
+}
+
+// Tags lists the tags of the repository, from the tags/list endpoint.
+func Tags(ctx context.Context, repo string, options types.RegistryTagsOptions) error {
+	ref, err := referenceutil.Parse(repo)
+	if err != nil {
+		return err
+	}
+	if ref.Protocol != "" {
+		return fmt.Errorf("unsupported repository %q", repo)
+	}
+
+	var tags []string
+	if err := withClient(ctx, options.GOptions, ref.Domain, func(c *client) error {
+		tags = nil
+		path := fmt.Sprintf("/v2/%s/tags/list?n=%d", ref.Path, pageLimit(options.Limit))
+		for path != "" {
+			var page struct {
+				Tags []string `json:"tags"`
+			}
+			next, err := c.getJSON(ctx, path, &page)
+			if err != nil {
+				return err
+			}
+			tags = append(tags, page.Tags...)
+			if options.Limit > 0 && len(tags) >= options.Limit {
+				tags = tags[:options.Limit]
+				break
+			}
+			path = next
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	items := make([]any, len(tags))
+	for i, t := range tags {
+		items[i] = tag{Repository: ref.Name(), Tag: t}
+	}
+	return printItems(options.Stdout, options.Format, "REPOSITORY\tTAG", items, func(item any) string {
+		t := item.(tag)
+		return t.Repository + "\t" + t.Tag
+	})
+}
@@ -0,0 +1,130 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	b4nnapi "github.com/rootless-containers/bypass4netns/pkg/api"
+	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+type bypass4netnsStatus struct {
+	ID  string
+	Pid int
+	// Ports are the published ports accelerated by bypass4netns
+	Ports []string
+	// IgnoreBind is true when the ports are not accelerated by bypass4netns
+	IgnoreBind bool
+	// IgnoreSubnets are the destinations of the connections not accelerated by bypass4netns
+	IgnoreSubnets []string
+}
+
+func newBypass4netnsStatus(st b4nnapi.BypassStatus) bypass4netnsStatus {
+	var ports []string
+	for _, p := range st.Spec.PortMapping {
+		for _, proto := range p.Protos {
+			ports = append(ports, fmt.Sprintf("%s:%d->%d/%s", p.ParentIP, p.ParentPort, p.ChildPort, proto))
+		}
+	}
+	return bypass4netnsStatus{
+		ID:            st.ID,
+		Pid:           st.Pid,
+		Ports:         ports,
+		IgnoreBind:    st.Spec.IgnoreBind,
+		IgnoreSubnets: st.Spec.IgnoreSubnets,
+	}
+}
+
+// Bypass4netnsStatus lists the containers accelerated by bypass4netnsd, with the connections being accelerated:
+// the published ports, and the outgoing connections except the ones to the ignored subnets.
+func Bypass4netnsStatus(ctx context.Context, options types.SystemBypass4netnsStatusOptions) error {
+	if !rootlessutil.IsRootless() {
+		return errors.New("bypass4netns is only supported in rootless mode")
+	}
+	socketPath, err := bypass4netnsutil.GetBypass4NetnsdDefaultSocketPath()
+	if err != nil {
+		return err
+	}
+	if running, err := bypass4netnsutil.IsBypass4netnsdRunning(); err != nil {
+		return err
+	} else if !running {
+		return fmt.Errorf("bypass4netnsd is not running on %s (Hint: run `containerd-rootless-setuptool.sh install-bypass4netnsd`, or `nerdctl run --bypass4netns`)", socketPath)
+	}
+	client, err := b4nndclient.New(socketPath)
+	if err != nil {
+		return err
+	}
+	list, err := client.BypassManager().ListBypass(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the containers accelerated by bypass4netnsd: %w", err)
+	}
+
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "CONTAINER ID\tPID\tPORTS\tIGNORED SUBNETS")
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+	for _, st := range list {
+		status := newBypass4netnsStatus(st)
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, status); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		id := status.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		ports := strings.Join(status.Ports, ", ")
+		if status.IgnoreBind {
+			ports = "(not accelerated)"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", id, status.Pid, ports, strings.Join(status.IgnoreSubnets, ",")); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/containerd/cgroups/v3"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// minSubIDCount is the number of the subordinate IDs recommended for rootless mode.
+const minSubIDCount = 65536
+
+type rootlessChecker struct {
+	w        io.Writer
+	failures int
+}
+
+func (c *rootlessChecker) ok(format string, a ...any) {
+	fmt.Fprintf(c.w, "[OK] "+format+"\n", a...)
+}
+
+func (c *rootlessChecker) warn(format string, a ...any) {
+	fmt.Fprintf(c.w, "[WARNING] "+format+"\n", a...)
+}
+
+func (c *rootlessChecker) fail(format string, a ...any) {
+	c.failures++
+	fmt.Fprintf(c.w, "[FAIL] "+format+"\n", a...)
+}
+
+// rootlessUser returns the user to be checked.
+// When executed with sudo, the user who invoked sudo is checked.
+func rootlessUser() (*user.User, error) {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && os.Geteuid() == 0 {
+		return user.Lookup(sudoUser)
+	}
+	return user.Current()
+}
+
+// CheckRootless checks the requirements of rootless mode: cgroup v2 with the controllers delegated
+// by systemd, newuidmap and newgidmap, and the subordinate IDs of the user.
+// It also checks whether ports below 1024 can be published, but only warns if not.
+func CheckRootless(options types.SystemCheckRootlessOptions) error {
+	if options.Fix && os.Geteuid() != 0 {
+		return errors.New("--fix requires root (Hint: run `sudo nerdctl system check-rootless --fix`)")
+	}
+	if options.PrivilegedPorts && !options.Fix {
+		return errors.New("--privileged-ports requires --fix")
+	}
+	u, err := rootlessUser()
+	if err != nil {
+		return err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return err
+	}
+	if uid == 0 {
+		return errors.New("rootless mode cannot be checked for root (Hint: run as the user who runs rootless containerd, or with sudo)")
+	}
+	c := &rootlessChecker{w: options.Stdout}
+	fmt.Fprintf(c.w, "Checking rootless mode for user %q (uid=%d)\n", u.Username, uid)
+
+	if err := checkCgroupDelegation(c, uid, options.Fix); err != nil {
+		return err
+	}
+	for _, bin := range []string{"newuidmap", "newgidmap"} {
+		if p, err := exec.LookPath(bin); err != nil {
+			c.fail("%s is not installed (Hint: install the \"uidmap\" package)", bin)
+		} else {
+			c.ok("%s is installed at %s", bin, p)
+		}
+	}
+	for _, f := range []string{"/etc/subuid", "/etc/subgid"} {
+		count, err := rootlessutil.SubIDCount(f, u.Username, uid)
+		switch {
+		case err != nil:
+			c.fail("failed to read %s: %v", f, err)
+		case count == 0:
+			c.fail("no subordinate IDs are allocated to %q in %s (Hint: add a line like \"%s:100000:%d\")", u.Username, f, u.Username, minSubIDCount)
+		case count < minSubIDCount:
+			c.warn("only %d subordinate IDs are allocated to %q in %s, at least %d are recommended", count, u.Username, f, minSubIDCount)
+		default:
+			c.ok("%d subordinate IDs are allocated to %q in %s", count, u.Username, f)
+		}
+	}
+	if err := checkPrivilegedPorts(c, u, options.PrivilegedPorts); err != nil {
+		return err
+	}
+
+	if c.failures > 0 {
+		return fmt.Errorf("%d check(s) failed", c.failures)
+	}
+	return nil
+}
+
+func checkCgroupDelegation(c *rootlessChecker, uid int, fix bool) error {
+	if cgroups.Mode() != cgroups.Unified {
+		c.fail("cgroup v2 is not enabled, resource limits cannot be applied to rootless containers (Hint: boot the system with `systemd.unified_cgroup_hierarchy=1`)")
+		return nil
+	}
+	c.ok("cgroup v2 is enabled")
+
+	missing, err := rootlessutil.MissingCgroupControllers(uid)
+	if err != nil {
+		c.warn("cannot check the cgroup controllers delegated to user@%d.service: %v (Hint: log in with systemd-logind, not with `su`)", uid, err)
+		return nil
+	}
+	if len(missing) == 0 {
+		c.ok("cgroup controllers are delegated to user@%d.service", uid)
+		return nil
+	}
+	if !fix {
+		c.fail("cgroup controllers are not delegated to user@%d.service: %s (Hint: run `sudo nerdctl system check-rootless --fix`, or create %s with the following content, then run `sudo systemctl daemon-reload`)\n%s",
+			uid, strings.Join(missing, ", "), rootlessutil.CgroupDelegationDropInPath, rootlessutil.CgroupDelegationDropIn())
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(rootlessutil.CgroupDelegationDropInPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(rootlessutil.CgroupDelegationDropInPath, []byte(rootlessutil.CgroupDelegationDropIn()), 0o644); err != nil {
+		return err
+	}
+	if out, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run `systemctl daemon-reload`: %w (out=%q)", err, string(out))
+	}
+	c.ok("wrote %s to delegate the cgroup controllers: %s (the user has to log out and log in again to apply it)", rootlessutil.CgroupDelegationDropInPath, strings.Join(missing, ", "))
+	return nil
+}
+
+func checkPrivilegedPorts(c *rootlessChecker, u *user.User, fix bool) error {
+	start, err := rootlessutil.UnprivilegedPortStart()
+	if err != nil {
+		c.warn("cannot check the sysctl net.ipv4.ip_unprivileged_port_start: %v", err)
+		return nil
+	}
+	if start < 1024 {
+		c.ok("ports below 1024 can be published (net.ipv4.ip_unprivileged_port_start=%d)", start)
+		return nil
+	}
+	rlk, err := rootlessutil.LookRootlessKit(u.HomeDir)
+	if err != nil {
+		c.fail("cannot find rootlesskit: %v", err)
+		return nil
+	}
+	hasCap, err := rootlessutil.HasFileCapability(rlk, unix.CAP_NET_BIND_SERVICE)
+	if err != nil {
+		c.warn("cannot check the file capabilities of %s: %v", rlk, err)
+		return nil
+	}
+	if hasCap {
+		c.ok("ports below 1024 can be published, as %s has CAP_NET_BIND_SERVICE", rlk)
+		return nil
+	}
+	if !fix {
+		c.warn("ports below %d cannot be published (Hint: run `sudo nerdctl system check-rootless --fix --privileged-ports` "+
+			"to grant CAP_NET_BIND_SERVICE to %s, or set the sysctl net.ipv4.ip_unprivileged_port_start=0)", start, rlk)
+		return nil
+	}
+	if out, err := exec.Command("setcap", "cap_net_bind_service=ep", rlk).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run `setcap cap_net_bind_service=ep %s`: %w (out=%q)", rlk, err, string(out))
+	}
+	c.ok("granted CAP_NET_BIND_SERVICE to %s (run `systemctl --user restart containerd` as the user to apply it)", rlk)
+	return nil
+}
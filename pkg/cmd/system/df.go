@@ -0,0 +1,438 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+)
+
+// diskUsageSummary is a row of `nerdctl system df`, compatible with `docker system df --format`.
+type diskUsageSummary struct {
+	Type        string
+	TotalCount  string
+	Active      string
+	Size        string
+	Reclaimable string
+}
+
+type imageUsage struct {
+	Repository string
+	Tag        string
+	ID         string
+	CreatedAt  time.Time
+	// Size is the sum of the sizes of the layers of the image
+	Size int64
+	// SharedSize is the size of the layers that are also used by other images
+	SharedSize int64
+	// UniqueSize is the size of the layers only used by this image
+	UniqueSize int64
+	// Containers is the number of containers using the image
+	Containers int
+}
+
+type containerUsage struct {
+	ID      string
+	Image   string
+	Command string
+	// LocalVolumes is the number of volumes mounted by the container
+	LocalVolumes int
+	// Size is the size of the writable layer of the container
+	Size      int64
+	CreatedAt time.Time
+	Status    string
+	Names     string
+	running   bool
+}
+
+type volumeUsage struct {
+	Name string
+	// Links is the number of containers referencing the volume
+	Links int64
+	Size  int64
+}
+
+// diskUsage is the output of `nerdctl system df -v`.
+type diskUsage struct {
+	Images     []imageUsage
+	Containers []containerUsage
+	Volumes    []volumeUsage
+	BuildCache []buildkitutil.UsageInfo
+
+	imagesSize        int64
+	imagesReclaimable int64
+}
+
+// DiskUsage prints the disk usage of the images, the containers, the volumes, and the build cache,
+// along with the space reclaimable by `nerdctl system prune`.
+func DiskUsage(ctx context.Context, client *containerd.Client, options types.SystemDiskUsageOptions) error {
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	du, err := collectDiskUsage(ctx, client, options)
+	if err != nil {
+		return err
+	}
+
+	if options.Verbose {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, du); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintln(options.Stdout, b.String())
+			return err
+		}
+		return printVerboseDiskUsage(options.Stdout, du)
+	}
+
+	var w = options.Stdout
+	if tmpl == nil {
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+	}
+	for _, s := range du.summary() {
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, s); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Type, s.TotalCount, s.Active, s.Size, s.Reclaimable); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func collectDiskUsage(ctx context.Context, client *containerd.Client, options types.SystemDiskUsageOptions) (*diskUsage, error) {
+	du := &diskUsage{}
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containersPerImage := make(map[string]int)
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		cu := containerUsage{
+			ID:        c.ID(),
+			Image:     info.Image,
+			CreatedAt: info.CreatedAt,
+			Status:    formatter.ContainerStatus(ctx, c),
+			Names:     containerutil.GetContainerName(info.Labels),
+		}
+		cu.running = strings.HasPrefix(cu.Status, "Up")
+		if spec, err := c.Spec(ctx); err == nil {
+			cu.Command = formatter.InspectContainerCommand(spec, true, true)
+		}
+		if info.SnapshotKey != "" {
+			rw, _, err := imgutil.ResourceUsage(ctx, containerdutil.SnapshotService(client, info.Snapshotter), info.SnapshotKey)
+			if err != nil {
+				log.G(ctx).WithError(err).Debugf("failed to get the size of container %q", c.ID())
+			}
+			cu.Size = rw.Size
+		}
+		if used, err := volume.UsedVolumes(ctx, []containerd.Container{c}); err == nil {
+			cu.LocalVolumes = len(used)
+		}
+		containersPerImage[info.Image]++
+		du.Containers = append(du.Containers, cu)
+	}
+
+	targets, layers, err := image.LayersDiskUsage(ctx, client, options.GOptions.Snapshotter)
+	if err != nil {
+		return nil, err
+	}
+	containersPerTarget := make(map[digest.Digest]int)
+	for _, t := range targets {
+		for _, name := range t.Names {
+			containersPerTarget[t.Target] += containersPerImage[name]
+		}
+	}
+	du.imagesSize, du.imagesReclaimable = imagesReclaimable(targets, layers, containersPerTarget)
+	for _, t := range targets {
+		var size, shared int64
+		for _, id := range t.ChainIDs {
+			l := layers[id]
+			size += l.Size
+			if l.Images > 1 {
+				shared += l.Size
+			}
+		}
+		for _, name := range t.Names {
+			iu := imageUsage{
+				ID:         t.Target.String(),
+				CreatedAt:  t.CreatedAt,
+				Size:       size,
+				SharedSize: shared,
+				UniqueSize: size - shared,
+				Containers: containersPerTarget[t.Target],
+			}
+			if t.Target.String() != name {
+				iu.Repository, iu.Tag = imgutil.ParseRepoTag(name)
+			}
+			if iu.Repository == "" {
+				iu.Repository = "<none>"
+			}
+			if iu.Tag == "" {
+				iu.Tag = "<none>"
+			}
+			du.Images = append(du.Images, iu)
+		}
+	}
+
+	vols, err := volume.Volumes(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	links, err := volume.UsedVolumes(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vols {
+		du.Volumes = append(du.Volumes, volumeUsage{
+			Name:  v.Name,
+			Links: links[v.Name],
+			Size:  max(v.Size, 0),
+		})
+	}
+
+	if options.BuildKitHost != "" {
+		du.BuildCache, err = builder.DiskUsage(ctx, types.BuilderDiskUsageOptions{
+			Stderr:       options.Stderr,
+			GOptions:     options.GOptions,
+			BuildKitHost: options.BuildKitHost,
+		})
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to get the build cache usage")
+		}
+	}
+	return du, nil
+}
+
+// imagesReclaimable returns the total size of the layers of the images, and the size of the layers
+// that are not used by the images of any container, i.e., the space reclaimed by `nerdctl image prune --all`.
+func imagesReclaimable(targets []*image.TargetDiskUsage, layers map[string]*image.LayerDiskUsage, containersPerTarget map[digest.Digest]int) (size, reclaimable int64) {
+	active := make(map[string]struct{})
+	for _, t := range targets {
+		if containersPerTarget[t.Target] == 0 {
+			continue
+		}
+		for _, id := range t.ChainIDs {
+			active[id] = struct{}{}
+		}
+	}
+	for id, l := range layers {
+		size += l.Size
+		if _, ok := active[id]; !ok {
+			reclaimable += l.Size
+		}
+	}
+	return size, reclaimable
+}
+
+func (du *diskUsage) summary() []diskUsageSummary {
+	var activeImages int
+	seen := make(map[string]struct{})
+	for _, img := range du.Images {
+		if _, ok := seen[img.ID]; ok {
+			continue
+		}
+		seen[img.ID] = struct{}{}
+		if img.Containers > 0 {
+			activeImages++
+		}
+	}
+
+	var activeContainers int
+	var containersSize, containersReclaimable int64
+	for _, c := range du.Containers {
+		containersSize += c.Size
+		if c.running {
+			activeContainers++
+		} else {
+			containersReclaimable += c.Size
+		}
+	}
+
+	var activeVolumes int
+	var volumesSize, volumesReclaimable int64
+	for _, v := range du.Volumes {
+		volumesSize += v.Size
+		if v.Links > 0 {
+			activeVolumes++
+		} else {
+			volumesReclaimable += v.Size
+		}
+	}
+
+	var activeBuildCache int
+	var buildCacheSize, buildCacheReclaimable int64
+	for _, r := range du.BuildCache {
+		buildCacheSize += r.Size
+		if r.InUse {
+			activeBuildCache++
+		} else if !r.Shared {
+			// shared records are also the layers of images, so they are not reclaimed by pruning the build cache
+			buildCacheReclaimable += r.Size
+		}
+	}
+
+	return []diskUsageSummary{
+		{
+			Type:        "Images",
+			TotalCount:  fmt.Sprint(len(seen)),
+			Active:      fmt.Sprint(activeImages),
+			Size:        units.HumanSize(float64(du.imagesSize)),
+			Reclaimable: reclaimableString(du.imagesReclaimable, du.imagesSize),
+		},
+		{
+			Type:        "Containers",
+			TotalCount:  fmt.Sprint(len(du.Containers)),
+			Active:      fmt.Sprint(activeContainers),
+			Size:        units.HumanSize(float64(containersSize)),
+			Reclaimable: reclaimableString(containersReclaimable, containersSize),
+		},
+		{
+			Type:        "Local Volumes",
+			TotalCount:  fmt.Sprint(len(du.Volumes)),
+			Active:      fmt.Sprint(activeVolumes),
+			Size:        units.HumanSize(float64(volumesSize)),
+			Reclaimable: reclaimableString(volumesReclaimable, volumesSize),
+		},
+		{
+			Type:        "Build Cache",
+			TotalCount:  fmt.Sprint(len(du.BuildCache)),
+			Active:      fmt.Sprint(activeBuildCache),
+			Size:        units.HumanSize(float64(buildCacheSize)),
+			Reclaimable: units.HumanSize(float64(buildCacheReclaimable)),
+		},
+	}
+}
+
+// reclaimableString formats the reclaimable size like Docker, e.g., "1.5GB (75%)".
+func reclaimableString(reclaimable, size int64) string {
+	s := units.HumanSize(float64(reclaimable))
+	if size > 0 {
+		s += fmt.Sprintf(" (%d%%)", reclaimable*100/size)
+	}
+	return s
+}
+
+func printVerboseDiskUsage(stdout io.Writer, du *diskUsage) error {
+	fmt.Fprint(stdout, "Images space usage:\n\n")
+	w := tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS")
+	for _, img := range du.Images {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", img.Repository, img.Tag, strings.Split(img.ID, ":")[1][:12],
+			formatter.TimeSinceInHuman(img.CreatedAt), units.HumanSize(float64(img.Size)),
+			units.HumanSize(float64(img.SharedSize)), units.HumanSize(float64(img.UniqueSize)), img.Containers)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprint(stdout, "\nContainers space usage:\n\n")
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER ID\tIMAGE\tCOMMAND\tLOCAL VOLUMES\tSIZE\tCREATED\tSTATUS\tNAMES")
+	for _, c := range du.Containers {
+		id := c.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", id, c.Image, c.Command, c.LocalVolumes,
+			units.HumanSize(float64(c.Size)), formatter.TimeSinceInHuman(c.CreatedAt), c.Status, c.Names)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprint(stdout, "\nLocal Volumes space usage:\n\n")
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "VOLUME NAME\tLINKS\tSIZE")
+	for _, v := range du.Volumes {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", v.Name, v.Links, units.HumanSize(float64(v.Size)))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var buildCacheSize int64
+	for _, r := range du.BuildCache {
+		buildCacheSize += r.Size
+	}
+	fmt.Fprintf(stdout, "\nBuild cache usage: %s\n\n", units.HumanSize(float64(buildCacheSize)))
+	w = tabwriter.NewWriter(stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CACHE ID\tCACHE TYPE\tSIZE\tCREATED\tLAST USED\tUSAGE\tSHARED")
+	for _, r := range du.BuildCache {
+		lastUsed := ""
+		if r.LastUsedAt != nil {
+			lastUsed = formatter.TimeSinceInHuman(*r.LastUsedAt)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%t\n", r.ID, r.RecordType, units.HumanSize(float64(r.Size)),
+			formatter.TimeSinceInHuman(r.CreatedAt), lastUsed, r.UsageCount, r.Shared)
+	}
+	return w.Flush()
+}
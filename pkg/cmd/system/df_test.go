@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func TestImagesReclaimable(t *testing.T) {
+	base := digest.FromString("base")
+	app := digest.FromString("app")
+	targets := []*image.TargetDiskUsage{
+		{Target: base, ChainIDs: []string{"l1"}},
+		{Target: app, ChainIDs: []string{"l1", "l2"}},
+	}
+	layers := map[string]*image.LayerDiskUsage{
+		"l1": {ID: "l1", Size: 100, Images: 2},
+		"l2": {ID: "l2", Size: 50, Images: 1},
+	}
+
+	size, reclaimable := imagesReclaimable(targets, layers, nil)
+	assert.Equal(t, size, int64(150))
+	assert.Equal(t, reclaimable, int64(150))
+
+	// the layers shared with the image of a container are not reclaimable
+	size, reclaimable = imagesReclaimable(targets, layers, map[digest.Digest]int{base: 1})
+	assert.Equal(t, size, int64(150))
+	assert.Equal(t, reclaimable, int64(50))
+
+	_, reclaimable = imagesReclaimable(targets, layers, map[digest.Digest]int{app: 2})
+	assert.Equal(t, reclaimable, int64(0))
+}
+
+func TestDiskUsageSummary(t *testing.T) {
+	du := &diskUsage{
+		Images: []imageUsage{
+			{ID: "sha256:a", Containers: 1},
+			{ID: "sha256:a", Containers: 1},
+			{ID: "sha256:b"},
+		},
+		Containers: []containerUsage{
+			{ID: "c1", Size: 30, running: true},
+			{ID: "c2", Size: 10},
+		},
+		Volumes: []volumeUsage{
+			{Name: "v1", Links: 1, Size: 100},
+			{Name: "v2", Size: 300},
+		},
+		BuildCache: []buildkitutil.UsageInfo{
+			{ID: "r1", Size: 1000, InUse: true},
+			{ID: "r2", Size: 2000, Shared: true},
+			{ID: "r3", Size: 3000},
+		},
+		imagesSize:        200,
+		imagesReclaimable: 50,
+	}
+	assert.DeepEqual(t, du.summary(), []diskUsageSummary{
+		{Type: "Images", TotalCount: "2", Active: "1", Size: "200B", Reclaimable: "50B (25%)"},
+		{Type: "Containers", TotalCount: "2", Active: "1", Size: "40B", Reclaimable: "10B (25%)"},
+		{Type: "Local Volumes", TotalCount: "2", Active: "1", Size: "400B", Reclaimable: "300B (75%)"},
+		{Type: "Build Cache", TotalCount: "3", Active: "1", Size: "6kB", Reclaimable: "3kB"},
+	})
+}
+
+func TestReclaimableString(t *testing.T) {
+	assert.Equal(t, reclaimableString(0, 0), "0B")
+	assert.Equal(t, reclaimableString(1500, 3000), "1.5kB (50%)")
+}
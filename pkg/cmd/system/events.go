@@ -22,57 +22,59 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 
-	_ "github.com/containerd/containerd/api/events" // Register grpc event types
+	timetypes "github.com/docker/docker/api/types/time"
+
+	eventtypes "github.com/containerd/containerd/api/events" // Register grpc event types
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/log"
 	"github.com/containerd/typeurl/v2"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 // EventOut contains information about an event.
+// The fields are compatible with the events of Docker (github.com/docker/docker/api/types/events.Message),
+// with the extra fields about the original containerd event.
 type EventOut struct {
-	Timestamp time.Time
-	ID        string
-	Namespace string
-	Topic     string
-	Status    Status
-	Event     string
-}
-
-type Status string
-
-const (
-	START   Status = "start"
-	UNKNOWN Status = "unknown"
-)
+	// Deprecated by Docker, but still printed for compatibility
+	Status string `json:"status,omitempty"`
+	ID     string `json:"id,omitempty"`
+	From   string `json:"from,omitempty"`
 
-var statuses = [...]Status{START, UNKNOWN}
+	Type     string
+	Action   string
+	Actor    EventActor
+	Scope    string `json:"scope"`
+	Time     int64  `json:"time"`
+	TimeNano int64  `json:"timeNano"`
 
-func isStatus(status string) bool {
-	status = strings.ToLower(status)
-
-	for _, supportedStatus := range statuses {
-		if string(supportedStatus) == status {
-			return true
-		}
-	}
+	// Namespace is the containerd namespace of the event
+	Namespace string
+	// Topic is the containerd topic of the event, e.g., "/tasks/start"
+	Topic string
+	// Event is the containerd event in JSON
+	Event string
 
-	return false
+	Timestamp time.Time `json:"-"`
 }
 
-func TopicToStatus(topic string) Status {
-	if strings.Contains(strings.ToLower(topic), string(START)) {
-		return START
-	}
+// statusUnknown is the status of the events that are not translated into the Docker-compatible events.
+const statusUnknown = "unknown"
 
-	return UNKNOWN
+// EventActor describes the object that emitted an event.
+type EventActor struct {
+	ID         string
+	Attributes map[string]string
 }
 
 // EventFilter for filtering events
@@ -84,11 +86,42 @@ func generateEventFilter(filter, filterValue string) (func(e *EventOut) bool, er
 	switch strings.ToUpper(filter) {
 	case "EVENT", "STATUS":
 		return func(e *EventOut) bool {
-			if !isStatus(string(e.Status)) {
+			// Like Docker, "health_status" matches "health_status: healthy" and so on
+			if strings.EqualFold(filterValue, "health_status") {
+				return strings.HasPrefix(e.Action, "health_status:")
+			}
+			return strings.EqualFold(e.Action, filterValue) || strings.EqualFold(e.Status, filterValue)
+		}, nil
+	case "TYPE":
+		return func(e *EventOut) bool {
+			return strings.EqualFold(e.Type, filterValue)
+		}, nil
+	case "CONTAINER":
+		return func(e *EventOut) bool {
+			if e.Type != eventutil.TypeContainer {
 				return false
 			}
-
-			return strings.EqualFold(string(e.Status), filterValue)
+			return strings.HasPrefix(e.Actor.ID, filterValue) || e.Actor.Attributes["name"] == filterValue
+		}, nil
+	case "IMAGE":
+		return func(e *EventOut) bool {
+			if e.Type == eventutil.TypeImage {
+				return e.Actor.ID == filterValue
+			}
+			return e.From == filterValue
+		}, nil
+	case "VOLUME":
+		return func(e *EventOut) bool {
+			return e.Type == eventutil.TypeVolume && e.Actor.ID == filterValue
+		}, nil
+	case "LABEL":
+		key, value, hasValue := strings.Cut(filterValue, "=")
+		return func(e *EventOut) bool {
+			v, ok := e.Actor.Attributes[key]
+			if !ok {
+				return false
+			}
+			return !hasValue || v == value
 		}, nil
 	}
 
@@ -136,18 +169,153 @@ func generateEventFilters(filters []string) (map[string][]EventFilter, error) {
 		if err != nil {
 			return nil, err
 		}
-		filterSlice := filterMap[key]
-		filterSlice = append(filterSlice, filterFunc)
-		filterMap[key] = filterSlice
+		key = strings.ToLower(key)
+		filterMap[key] = append(filterMap[key], filterFunc)
 	}
 
 	return filterMap, nil
 }
 
+// parseEventTime parses the value of --since and --until, e.g., "10m", "2006-01-02T15:04:05", or a UNIX timestamp.
+func parseEventTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// containerAttributes looks up the name, the image, and the user labels of the containers for the event attributes.
+// The attributes are cached, so that they are still available after the container is deleted.
+type containerAttributes struct {
+	client *containerd.Client
+	cache  map[string]map[string]string
+}
+
+func (c *containerAttributes) get(ctx context.Context, namespace, id string) map[string]string {
+	key := namespace + "/" + id
+	if attrs, ok := c.cache[key]; ok {
+		return attrs
+	}
+	ctx = namespaces.WithNamespace(ctx, namespace)
+	container, err := c.client.LoadContainer(ctx, id)
+	if err != nil {
+		return nil
+	}
+	info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return nil
+	}
+	attrs := make(map[string]string)
+	for k, v := range info.Labels {
+		if !strings.HasPrefix(k, labels.Prefix) && !strings.HasPrefix(k, "io.containerd.") {
+			attrs[k] = v
+		}
+	}
+	attrs["image"] = info.Image
+	if name := info.Labels[labels.Name]; name != "" {
+		attrs["name"] = name
+	}
+	c.cache[key] = attrs
+	return attrs
+}
+
+// forget evicts the cached attributes of a deleted container.
+func (c *containerAttributes) forget(namespace, id string) {
+	delete(c.cache, namespace+"/"+id)
+}
+
+func newEventOut(ctx context.Context, e *events.Envelope, containers *containerAttributes) (*EventOut, error) {
+	var (
+		v   any
+		out []byte
+		err error
+	)
+	if e.Event != nil {
+		v, err = typeurl.UnmarshalAny(e.Event)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal an event from Any: %w", err)
+		}
+		out, err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal Any into JSON: %w", err)
+		}
+	}
+	typ, id, action, attributes := eventutil.DockerEvent(e.Topic, v)
+	actorAttributes := make(map[string]string)
+	var from string
+	if typ == eventutil.TypeContainer {
+		for k, v := range containers.get(ctx, e.Namespace, id) {
+			actorAttributes[k] = v
+		}
+		from = actorAttributes["image"]
+	}
+	if ev, ok := v.(*eventtypes.ContainerDelete); ok {
+		containers.forget(e.Namespace, ev.ID)
+	}
+	for k, v := range attributes {
+		actorAttributes[k] = v
+	}
+	eOut := &EventOut{
+		ID:     id,
+		From:   from,
+		Type:   typ,
+		Action: action,
+		Actor: EventActor{
+			ID:         id,
+			Attributes: actorAttributes,
+		},
+		Scope:     "local",
+		Time:      e.Timestamp.Unix(),
+		TimeNano:  e.Timestamp.UnixNano(),
+		Namespace: e.Namespace,
+		Topic:     e.Topic,
+		Event:     string(out),
+		Timestamp: e.Timestamp,
+	}
+	switch typ {
+	case eventutil.TypeContainer:
+		// Only the container events have the legacy "status" field in Docker
+		eOut.Status = action
+	case eventutil.TypeImage, eventutil.TypeVolume:
+	default:
+		// The events that are not translated keep the status of the previous versions of nerdctl
+		eOut.Status = statusUnknown
+	}
+	return eOut, nil
+}
+
+// String returns the event in the default format of `docker events`.
+func (e *EventOut) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s", e.Timestamp.Format(time.RFC3339Nano), e.Type, e.Action)
+	if e.Actor.ID != "" {
+		fmt.Fprintf(&b, " %s", e.Actor.ID)
+	}
+	if len(e.Actor.Attributes) > 0 {
+		keys := make([]string, 0, len(e.Actor.Attributes))
+		for k := range e.Actor.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]string, len(keys))
+		for i, k := range keys {
+			attrs[i] = k + "=" + e.Actor.Attributes[k]
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(attrs, ", "))
+	}
+	return b.String()
+}
+
 // Events is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/ctr/commands/events/events.go
 func Events(ctx context.Context, client *containerd.Client, options types.SystemEventsOptions) error {
-	eventsClient := client.EventService()
-	eventsCh, errCh := eventsClient.Subscribe(ctx)
 	var tmpl *template.Template
 	switch options.Format {
 	case "":
@@ -165,63 +333,71 @@ func Events(ctx context.Context, client *containerd.Client, options types.System
 	if err != nil {
 		return err
 	}
+	now := time.Now()
+	since, err := parseEventTime(options.Since, now)
+	if err != nil {
+		return fmt.Errorf("invalid value for \"since\": %w", err)
+	}
+	until, err := parseEventTime(options.Until, now)
+	if err != nil {
+		return fmt.Errorf("invalid value for \"until\": %w", err)
+	}
+	if !since.IsZero() && since.Before(now) {
+		// containerd does not keep the past events
+		log.G(ctx).Warn("the events before the command was started are not available, \"since\" only skips the events that are older than the given time")
+	}
+
+	var untilCh <-chan time.Time
+	if !until.IsZero() {
+		if !until.After(now) {
+			return nil
+		}
+		timer := time.NewTimer(until.Sub(now))
+		defer timer.Stop()
+		untilCh = timer.C
+	}
+
+	eventsClient := client.EventService()
+	eventsCh, errCh := eventsClient.Subscribe(ctx)
+	containers := &containerAttributes{client: client, cache: make(map[string]map[string]string)}
 	for {
 		var e *events.Envelope
 		select {
 		case e = <-eventsCh:
 		case err := <-errCh:
 			return err
+		case <-untilCh:
+			return nil
+		}
+		if e == nil {
+			continue
+		}
+		if !since.IsZero() && e.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && e.Timestamp.After(until) {
+			return nil
 		}
-		if e != nil {
-			var out []byte
-			var id string
-			if e.Event != nil {
-				v, err := typeurl.UnmarshalAny(e.Event)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot unmarshal an event from Any")
-					continue
-				}
-				out, err = json.Marshal(v)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-					continue
-				}
+		eOut, err := newEventOut(ctx, e, containers)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("cannot convert the event")
+			continue
+		}
+		if !applyFilters(eOut, filterMap) {
+			continue
+		}
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, eOut); err != nil {
+				return err
 			}
-			var data map[string]interface{}
-			err := json.Unmarshal(out, &data)
-			if err != nil {
-				log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-			} else {
-				_, ok := data["container_id"]
-				if ok {
-					id = data["container_id"].(string)
-				}
+			if _, err := fmt.Fprintln(options.Stdout, b.String()); err != nil {
+				return err
 			}
-
-			eOut := EventOut{e.Timestamp, id, e.Namespace, e.Topic, TopicToStatus(e.Topic), string(out)}
-			match := applyFilters(&eOut, filterMap)
-			if match {
-				if tmpl != nil {
-					var b bytes.Buffer
-					if err := tmpl.Execute(&b, eOut); err != nil {
-						return err
-					}
-					if _, err := fmt.Fprintln(options.Stdout, b.String()+"\n"); err != nil {
-						return err
-					}
-				} else {
-					if _, err := fmt.Fprintln(
-						options.Stdout,
-						e.Timestamp,
-						e.Namespace,
-						e.Topic,
-						string(out),
-					); err != nil {
-						return err
-					}
-				}
+		} else {
+			if _, err := fmt.Fprintln(options.Stdout, eOut.String()); err != nil {
+				return err
 			}
-
 		}
 	}
 }
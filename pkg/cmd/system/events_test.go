@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	eventtypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/typeurl/v2"
+)
+
+func testEnvelope(t *testing.T, topic string, v any) *events.Envelope {
+	t.Helper()
+	a, err := typeurl.MarshalAny(v)
+	assert.NilError(t, err)
+	return &events.Envelope{Timestamp: time.Now(), Namespace: "default", Topic: topic, Event: a}
+}
+
+func TestNewEventOut(t *testing.T) {
+	ctx := context.Background()
+	containers := &containerAttributes{cache: map[string]map[string]string{
+		"default/foo": {"name": "foo", "image": "alpine"},
+	}}
+
+	e, err := newEventOut(ctx, testEnvelope(t, "/tasks/start", &eventtypes.TaskStart{ContainerID: "foo", Pid: 42}), containers)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Type, "container")
+	assert.Equal(t, e.Action, "start")
+	assert.Equal(t, e.Status, "start")
+	assert.Equal(t, e.From, "alpine")
+	assert.Equal(t, e.Actor.Attributes["name"], "foo")
+
+	// The exit of an exec process is not translated, but is still emitted
+	e, err = newEventOut(ctx, testEnvelope(t, "/tasks/exit", &eventtypes.TaskExit{ContainerID: "foo", ID: "exec-1"}), containers)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Type, "task")
+	assert.Equal(t, e.Action, "exit")
+	assert.Equal(t, e.Status, "unknown")
+
+	e, err = newEventOut(ctx, testEnvelope(t, "/snapshot/prepare", &eventtypes.SnapshotPrepare{Key: "k", Parent: "p"}), containers)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Type, "snapshot")
+	assert.Equal(t, e.Action, "prepare")
+
+	e, err = newEventOut(ctx, testEnvelope(t, "/containers/delete", &eventtypes.ContainerDelete{ID: "foo"}), containers)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Action, "destroy")
+	assert.Equal(t, e.Actor.Attributes["name"], "foo")
+	assert.Equal(t, len(containers.cache), 0)
+}
+
+func TestEventFilterUnknown(t *testing.T) {
+	filter, err := generateEventFilter("event", "unknown")
+	assert.NilError(t, err)
+	assert.Assert(t, filter(&EventOut{Type: "snapshot", Action: "prepare", Status: "unknown"}))
+	assert.Assert(t, !filter(&EventOut{Type: "container", Action: "start", Status: "start"}))
+}
@@ -0,0 +1,306 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// Labels used by the garbage collector of containerd.
+// See https://github.com/containerd/containerd/blob/main/docs/garbage-collection.md
+const (
+	gcRootLabel       = "containerd.io/gc.root"
+	gcExpireLabel     = "containerd.io/gc.expire"
+	gcContentRefLabel = "containerd.io/gc.ref.content"
+	gcSnapRefLabel    = "containerd.io/gc.ref.snapshot."
+)
+
+// gcResource is a content blob or a snapshot that can be collected.
+type gcResource struct {
+	// Type is either "content" or "snapshot"
+	Type string
+	// ID is the digest of the content, or "<snapshotter>/<key>" of the snapshot
+	ID   string
+	Size int64
+}
+
+// GC triggers the garbage collection of containerd, and reports the content and the snapshots
+// of the current namespace that were freed.
+// With options.DryRun, it only reports the content and the snapshots that are not referenced any more.
+func GC(ctx context.Context, client *containerd.Client, options types.SystemGCOptions) error {
+	snapshotters := map[string]struct{}{options.GOptions.Snapshotter: {}}
+	marked, err := markGCReferences(ctx, client, snapshotters)
+	if err != nil {
+		return err
+	}
+	before, err := listGCResources(ctx, client, snapshotters)
+	if err != nil {
+		return err
+	}
+
+	var (
+		candidates  []gcResource
+		reclaimable int64
+		sizes       = make(map[string]int64)
+	)
+	for _, r := range before {
+		if _, ok := marked[r.Type+":"+r.ID]; ok {
+			continue
+		}
+		if r.Type == "snapshot" {
+			snapshotter, key, _ := strings.Cut(r.ID, "/")
+			if usage, err := client.SnapshotService(snapshotter).Usage(ctx, key); err == nil {
+				r.Size = usage.Size
+			}
+		}
+		sizes[r.Type+":"+r.ID] = r.Size
+		reclaimable += r.Size
+		candidates = append(candidates, r)
+	}
+
+	if options.DryRun {
+		printGCResources(options.Stdout, "Would delete", candidates)
+		fmt.Fprintf(options.Stdout, "Total reclaimable space: %s\n", units.HumanSize(float64(reclaimable)))
+		return nil
+	}
+	if options.Threshold > 0 && reclaimable < options.Threshold {
+		fmt.Fprintf(options.Stdout, "Reclaimable space %s is below the threshold %s, skipping garbage collection\n",
+			units.HumanSize(float64(reclaimable)), units.HumanSize(float64(options.Threshold)))
+		return nil
+	}
+
+	// Deleting a lease synchronously makes containerd run the garbage collection and wait for its completion.
+	ls := client.LeasesService()
+	l, err := ls.Create(ctx, leases.WithRandomID(), leases.WithExpiration(time.Minute))
+	if err != nil {
+		return err
+	}
+	if err := ls.Delete(ctx, l, leases.SynchronousDelete); err != nil {
+		return fmt.Errorf("failed to trigger garbage collection: %w", err)
+	}
+
+	after, err := listGCResources(ctx, client, snapshotters)
+	if err != nil {
+		return err
+	}
+	remaining := make(map[string]struct{}, len(after))
+	for _, r := range after {
+		remaining[r.Type+":"+r.ID] = struct{}{}
+	}
+	var (
+		freed     []gcResource
+		reclaimed int64
+	)
+	for _, r := range before {
+		if _, ok := remaining[r.Type+":"+r.ID]; ok {
+			continue
+		}
+		if size, ok := sizes[r.Type+":"+r.ID]; ok {
+			r.Size = size
+		}
+		reclaimed += r.Size
+		freed = append(freed, r)
+	}
+	printGCResources(options.Stdout, "Deleted", freed)
+	fmt.Fprintf(options.Stdout, "Total reclaimed space: %s\n", units.HumanSize(float64(reclaimed)))
+	return nil
+}
+
+func printGCResources(w io.Writer, verb string, resources []gcResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].ID < resources[j].ID
+	})
+	for _, r := range resources {
+		fmt.Fprintf(w, "%s %s: %s (%s)\n", verb, r.Type, r.ID, units.HumanSize(float64(r.Size)))
+	}
+}
+
+// listGCResources lists all the content blobs, and the snapshots of the given snapshotters.
+func listGCResources(ctx context.Context, client *containerd.Client, snapshotters map[string]struct{}) ([]gcResource, error) {
+	var res []gcResource
+	if err := client.ContentStore().Walk(ctx, func(info content.Info) error {
+		res = append(res, gcResource{Type: "content", ID: info.Digest.String(), Size: info.Size})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for snapshotter := range snapshotters {
+		if err := client.SnapshotService(snapshotter).Walk(ctx, func(ctx context.Context, info snapshots.Info) error {
+			res = append(res, gcResource{Type: "snapshot", ID: snapshotter + "/" + info.Name})
+			return nil
+		}); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to walk snapshots of snapshotter %q", snapshotter)
+		}
+	}
+	return res, nil
+}
+
+// markGCReferences approximates the mark phase of the garbage collector of containerd:
+// it returns the set of the content blobs and the snapshots reachable from images, containers,
+// leases and gc.root labels, as "content:<digest>" and "snapshot:<snapshotter>/<key>" keys.
+//
+// Snapshotters referenced by the walked objects are added to snapshotters.
+func markGCReferences(ctx context.Context, client *containerd.Client, snapshotters map[string]struct{}) (map[string]struct{}, error) {
+	var (
+		marked       = make(map[string]struct{})
+		contentQueue []string
+		snapQueue    []string
+		// snapshotterQueue holds the snapshotters whose gc.root snapshots are not walked yet
+		snapshotterQueue = slices.Sorted(maps.Keys(snapshotters))
+	)
+	markContent := func(dgst string) {
+		if _, ok := marked["content:"+dgst]; !ok {
+			marked["content:"+dgst] = struct{}{}
+			contentQueue = append(contentQueue, dgst)
+		}
+	}
+	markSnapshot := func(snapshotter, key string) {
+		if snapshotter == "" || key == "" {
+			return
+		}
+		if _, ok := snapshotters[snapshotter]; !ok {
+			snapshotters[snapshotter] = struct{}{}
+			snapshotterQueue = append(snapshotterQueue, snapshotter)
+		}
+		id := snapshotter + "/" + key
+		if _, ok := marked["snapshot:"+id]; !ok {
+			marked["snapshot:"+id] = struct{}{}
+			snapQueue = append(snapQueue, id)
+		}
+	}
+	markLabels := func(labels map[string]string) {
+		for k, v := range labels {
+			switch {
+			case strings.HasPrefix(k, gcContentRefLabel):
+				markContent(v)
+			case strings.HasPrefix(k, gcSnapRefLabel):
+				snapshotter, _, _ := strings.Cut(strings.TrimPrefix(k, gcSnapRefLabel), "/")
+				markSnapshot(snapshotter, v)
+			}
+		}
+	}
+
+	imgs, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range imgs {
+		markContent(img.Target.Digest.String())
+		markLabels(img.Labels)
+	}
+
+	containers, err := client.ContainerService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range containers {
+		markSnapshot(c.Snapshotter, c.SnapshotKey)
+		markLabels(c.Labels)
+	}
+
+	ls := client.LeasesService()
+	leaseList, err := ls.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range leaseList {
+		if exp, ok := l.Labels[gcExpireLabel]; ok {
+			if t, err := time.Parse(time.RFC3339, exp); err == nil && t.Before(time.Now()) {
+				continue
+			}
+		}
+		resources, err := ls.ListResources(ctx, l)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resources {
+			switch {
+			case r.Type == "content":
+				markContent(r.ID)
+			case strings.HasPrefix(r.Type, "snapshots/"):
+				markSnapshot(strings.TrimPrefix(r.Type, "snapshots/"), r.ID)
+			}
+		}
+	}
+
+	cs := client.ContentStore()
+	if err := cs.Walk(ctx, func(info content.Info) error {
+		if _, ok := info.Labels[gcRootLabel]; ok {
+			markContent(info.Digest.String())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for len(snapshotterQueue) > 0 || len(contentQueue) > 0 || len(snapQueue) > 0 {
+		for len(snapshotterQueue) > 0 {
+			snapshotter := snapshotterQueue[0]
+			snapshotterQueue = snapshotterQueue[1:]
+			if err := client.SnapshotService(snapshotter).Walk(ctx, func(ctx context.Context, info snapshots.Info) error {
+				if _, ok := info.Labels[gcRootLabel]; ok {
+					markSnapshot(snapshotter, info.Name)
+				}
+				return nil
+			}); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to walk snapshots of snapshotter %q", snapshotter)
+			}
+		}
+		for len(contentQueue) > 0 {
+			dgst := contentQueue[0]
+			contentQueue = contentQueue[1:]
+			info, err := cs.Info(ctx, digest.Digest(dgst))
+			if err != nil {
+				continue
+			}
+			markLabels(info.Labels)
+		}
+		for len(snapQueue) > 0 {
+			id := snapQueue[0]
+			snapQueue = snapQueue[1:]
+			snapshotter, key, _ := strings.Cut(id, "/")
+			info, err := client.SnapshotService(snapshotter).Stat(ctx, key)
+			if err != nil {
+				continue
+			}
+			markSnapshot(snapshotter, info.Parent)
+			markLabels(info.Labels)
+		}
+	}
+	return marked, nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -74,6 +75,25 @@ func Info(ctx context.Context, client *containerd.Client, options types.SystemIn
 			return err
 		}
 		infoCompat.Plugins.Log = logging.Drivers()
+		infoCompat.DockerRootDir = options.GOptions.DataRoot
+		infoCompat.ExperimentalBuild = options.GOptions.Experimental
+		if options.GOptions.Logging.Driver != "" {
+			infoCompat.LoggingDriver = options.GOptions.Logging.Driver
+		}
+		infoCompat.RootlessNetwork, err = netutil.RootlessNetwork()
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to inspect the network namespace of RootlessKit")
+		}
+		if options.GOptions.SeccompProfile != "" {
+			for i, o := range infoCompat.SecurityOptions {
+				if strings.HasPrefix(o, "name=seccomp,") {
+					infoCompat.SecurityOptions[i] = "name=seccomp,profile=" + options.GOptions.SeccompProfile
+				}
+			}
+		}
+		if remap := options.GOptions.UsernsRemap; remap != "" && remap != "host" {
+			infoCompat.SecurityOptions = append(infoCompat.SecurityOptions, "name=userns")
+		}
 	default:
 		return fmt.Errorf("unknown mode %q", options.Mode)
 	}
@@ -108,6 +128,11 @@ func fulfillNativeInfo(di *native.DaemonInfo, globalOptions types.GlobalCommandO
 	info.Snapshotter = globalOptions.Snapshotter
 	info.CgroupManager = globalOptions.CgroupManager
 	info.Rootless = rootlessutil.IsRootless()
+	rootlessNetwork, err := netutil.RootlessNetwork()
+	if err != nil {
+		log.L.WithError(err).Warn("failed to inspect the network namespace of RootlessKit")
+	}
+	info.RootlessNetwork = rootlessNetwork
 	return info, nil
 }
 
@@ -116,6 +141,10 @@ func prettyPrintInfoNative(w io.Writer, info *native.Info) error {
 	fmt.Fprintf(w, "Snapshotter:        %s\n", info.Snapshotter)
 	fmt.Fprintf(w, "Cgroup Manager:     %s\n", info.CgroupManager)
 	fmt.Fprintf(w, "Rootless:           %v\n", info.Rootless)
+	if info.RootlessNetwork != nil {
+		fmt.Fprintln(w, "Rootless Network:")
+		printRootlessNetwork(w, " ", info.RootlessNetwork)
+	}
 	fmt.Fprintf(w, "containerd Version: %s (%s)\n", info.Daemon.Version.Version, info.Daemon.Version.Revision)
 	fmt.Fprintf(w, "containerd UUID:    %s\n", info.Daemon.Server.UUID)
 	var disabledPlugins, enabledPlugins []*introspection.Plugin
@@ -152,9 +181,17 @@ func prettyPrintInfoDockerCompat(stdout io.Writer, stderr io.Writer, info *docke
 	fmt.Fprintf(w, " Debug Mode:\t%v\n", debug)
 	fmt.Fprintln(w)
 	fmt.Fprintf(w, "Server:\n")
+	fmt.Fprintf(w, " Containers: %d\n", info.Containers)
+	fmt.Fprintf(w, "  Running: %d\n", info.ContainersRunning)
+	fmt.Fprintf(w, "  Paused: %d\n", info.ContainersPaused)
+	fmt.Fprintf(w, "  Stopped: %d\n", info.ContainersStopped)
+	fmt.Fprintf(w, " Images: %d\n", info.Images)
 	fmt.Fprintf(w, " Server Version: %s\n", info.ServerVersion)
 	// Storage Driver is not really Server concept for nerdctl, but mimics `docker info` output
 	fmt.Fprintf(w, " Storage Driver: %s\n", info.Driver)
+	for _, s := range info.DriverStatus {
+		fmt.Fprintf(w, "  %s: %s\n", s[0], s[1])
+	}
 	fmt.Fprintf(w, " Logging Driver: %s\n", info.LoggingDriver)
 	printF(w, " Cgroup Driver: ", info.CgroupDriver)
 	printF(w, " Cgroup Version: ", info.CgroupVersion)
@@ -173,6 +210,12 @@ func prettyPrintInfoDockerCompat(stdout io.Writer, stderr io.Writer, info *docke
 	fmt.Fprintf(w, " Total Memory:     %s\n", units.BytesSize(float64(info.MemTotal)))
 	fmt.Fprintf(w, " Name:             %s\n", info.Name)
 	fmt.Fprintf(w, " ID:               %s\n", info.ID)
+	printF(w, " Docker Root Dir:  ", info.DockerRootDir)
+	fmt.Fprintf(w, " Experimental:     %v\n", info.ExperimentalBuild)
+	if info.RootlessNetwork != nil {
+		fmt.Fprintf(w, " Rootless Network:\n")
+		printRootlessNetwork(w, "  ", info.RootlessNetwork)
+	}
 
 	fmt.Fprintln(w)
 	if len(info.Warnings) > 0 {
@@ -181,6 +224,21 @@ func prettyPrintInfoDockerCompat(stdout io.Writer, stderr io.Writer, info *docke
 	return nil
 }
 
+func printRootlessNetwork(w io.Writer, indent string, n *native.RootlessNetwork) {
+	netns := n.NetNS
+	if netns == "" {
+		netns = "(not detached)"
+	}
+	fmt.Fprintf(w, "%sNetNS: %s\n", indent, netns)
+	for _, iface := range n.Interfaces {
+		fmt.Fprintf(w, "%s%s: state %s, mtu %d", indent, iface.Name, iface.State, iface.MTU)
+		if len(iface.Addrs) > 0 {
+			fmt.Fprintf(w, ", %s", strings.Join(iface.Addrs, " "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
 func printF(w io.Writer, label string, dockerCompatInfo string) {
 	if dockerCompatInfo == "" {
 		return
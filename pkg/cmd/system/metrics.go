@@ -0,0 +1,239 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+)
+
+// metricFamily is a metric in the Prometheus text exposition format
+// (https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format).
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+type metricSample struct {
+	// labels are the pairs of the label names and values
+	labels []string
+	value  float64
+}
+
+func (m *metricFamily) add(value float64, labels ...string) {
+	m.samples = append(m.samples, metricSample{labels: labels, value: value})
+}
+
+// containerResourceUsage is the resource usage of a running container.
+type containerResourceUsage struct {
+	// hasResources is false when the resource usage is not available
+	hasResources bool
+	cpuSeconds   float64
+	memory       float64
+	memoryLimit  float64
+	networkRx    float64
+	networkTx    float64
+	blockRead    float64
+	blockWrite   float64
+	pidsCurrent  float64
+}
+
+// Metrics serves the Prometheus metrics of the containers, the images, and the volumes on options.Listen.
+// The metrics are collected from containerd on each scrape.
+func Metrics(ctx context.Context, client *containerd.Client, options types.SystemMetricsOptions) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		families, err := collectMetrics(r.Context(), client, options)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to collect the metrics")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := writeMetrics(w, families); err != nil {
+			log.G(ctx).WithError(err).Debug("failed to write the metrics")
+		}
+	})
+	srv := &http.Server{
+		Addr:              options.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.G(ctx).Infof("serving metrics on http://%s/metrics", options.Listen)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func collectMetrics(ctx context.Context, client *containerd.Client, options types.SystemMetricsOptions) ([]*metricFamily, error) {
+	var (
+		info = &metricFamily{name: "nerdctl_container_info", typ: "gauge",
+			help: "Information about the container, always 1"}
+		running = &metricFamily{name: "nerdctl_container_running", typ: "gauge",
+			help: "Whether the container is running (1) or not (0)"}
+		restarts = &metricFamily{name: "nerdctl_container_restarts_total", typ: "counter",
+			help: "Number of times the container was restarted by the restart policy"}
+		cpu = &metricFamily{name: "nerdctl_container_cpu_usage_seconds_total", typ: "counter",
+			help: "Cumulative CPU time consumed by the container in seconds"}
+		memory = &metricFamily{name: "nerdctl_container_memory_usage_bytes", typ: "gauge",
+			help: "Memory usage of the container in bytes, excluding the inactive page cache"}
+		memoryLimit = &metricFamily{name: "nerdctl_container_memory_limit_bytes", typ: "gauge",
+			help: "Memory limit of the container in bytes"}
+		networkRx = &metricFamily{name: "nerdctl_container_network_receive_bytes_total", typ: "counter",
+			help: "Cumulative bytes received by the container, on all the interfaces except the loopback interface"}
+		networkTx = &metricFamily{name: "nerdctl_container_network_transmit_bytes_total", typ: "counter",
+			help: "Cumulative bytes transmitted by the container, on all the interfaces except the loopback interface"}
+		blockRead = &metricFamily{name: "nerdctl_container_blkio_read_bytes_total", typ: "counter",
+			help: "Cumulative bytes read from the block devices by the container"}
+		blockWrite = &metricFamily{name: "nerdctl_container_blkio_write_bytes_total", typ: "counter",
+			help: "Cumulative bytes written to the block devices by the container"}
+		pids = &metricFamily{name: "nerdctl_container_pids", typ: "gauge",
+			help: "Number of processes in the container"}
+		containersByState = &metricFamily{name: "nerdctl_containers", typ: "gauge",
+			help: "Number of containers by state"}
+		images = &metricFamily{name: "nerdctl_images", typ: "gauge",
+			help: "Number of images"}
+		volumes = &metricFamily{name: "nerdctl_volumes", typ: "gauge",
+			help: "Number of volumes"}
+	)
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]int)
+	for _, c := range containers {
+		cinfo, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		labels := []string{"id", c.ID(), "name", containerutil.GetContainerName(cinfo.Labels)}
+		info.add(1, append(labels, "image", cinfo.Image, "runtime", cinfo.Runtime.Name)...)
+		restartCount, _ := strconv.Atoi(cinfo.Labels[restart.CountLabel])
+		restarts.add(float64(restartCount), labels...)
+
+		state := "created"
+		var usage containerResourceUsage
+		if task, err := c.Task(ctx, nil); err == nil {
+			if st, err := task.Status(ctx); err == nil {
+				state = string(st.Status)
+				if st.Status == containerd.Running {
+					usage, err = taskResourceUsage(ctx, task)
+					if err != nil {
+						log.G(ctx).WithError(err).Debugf("failed to get the resource usage of container %q", c.ID())
+					}
+				}
+			}
+		}
+		states[state]++
+		if state == string(containerd.Running) {
+			running.add(1, labels...)
+		} else {
+			running.add(0, labels...)
+		}
+		if !usage.hasResources {
+			continue
+		}
+		cpu.add(usage.cpuSeconds, labels...)
+		memory.add(usage.memory, labels...)
+		memoryLimit.add(usage.memoryLimit, labels...)
+		networkRx.add(usage.networkRx, labels...)
+		networkTx.add(usage.networkTx, labels...)
+		blockRead.add(usage.blockRead, labels...)
+		blockWrite.add(usage.blockWrite, labels...)
+		pids.add(usage.pidsCurrent, labels...)
+	}
+	for _, state := range sortedKeys(states) {
+		containersByState.add(float64(states[state]), "state", state)
+	}
+
+	imageList, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images.add(float64(len(imageList)))
+
+	vols, err := volume.Volumes(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	volumes.add(float64(len(vols)))
+
+	return []*metricFamily{
+		info, running, restarts, cpu, memory, memoryLimit, networkRx, networkTx, blockRead, blockWrite, pids,
+		containersByState, images, volumes,
+	}, nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeMetrics writes the metrics in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, families []*metricFamily) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", m.name, m.typ)
+		for _, s := range m.samples {
+			bw.WriteString(m.name)
+			if len(s.labels) > 0 {
+				pairs := make([]string, 0, len(s.labels)/2)
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", s.labels[i], labelValueEscaper.Replace(s.labels[i+1])))
+				}
+				fmt.Fprintf(bw, "{%s}", strings.Join(pairs, ","))
+			}
+			fmt.Fprintf(bw, " %s\n", strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
+
+// labelValueEscaper escapes the backslash, the double quote, and the line feed in the label values.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,81 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
+	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
+)
+
+// taskResourceUsage returns the resource usage of the task from the cgroup metrics reported by containerd,
+// and the network usage from the network namespace of the task.
+func taskResourceUsage(ctx context.Context, task containerd.Task) (containerResourceUsage, error) {
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return containerResourceUsage{}, err
+	}
+	anydata, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return containerResourceUsage{}, err
+	}
+	pid := int(task.Pid())
+	links, err := statsutil.NetworkLinks(pid)
+	if err != nil {
+		// e.g., the container exited after its status was checked
+		log.G(ctx).WithError(err).Debugf("failed to get the network usage of task %q", task.ID())
+	}
+
+	var (
+		entry      statsutil.StatsEntry
+		cpuSeconds float64
+	)
+	switch data := anydata.(type) {
+	case *v1.Metrics:
+		entry, err = statsutil.SetCgroupStatsFields(&statsutil.ContainerStats{}, data, links, statsutil.SystemInfo{})
+		cpuSeconds = float64(data.GetCPU().GetUsage().GetTotal()) / 1e9
+	case *v2.Metrics:
+		if err := statsutil.CompleteCgroup2Metrics(data, pid); err != nil {
+			log.G(ctx).WithError(err).Debugf("failed to read the cgroup of task %q", task.ID())
+		}
+		entry, err = statsutil.SetCgroup2StatsFields(&statsutil.ContainerStats{}, data, links)
+		cpuSeconds = float64(data.GetCPU().GetUsageUsec()) / 1e6
+	default:
+		err = errors.New("cannot convert metric data to cgroups.Metrics")
+	}
+	if err != nil {
+		return containerResourceUsage{}, err
+	}
+	return containerResourceUsage{
+		hasResources: true,
+		cpuSeconds:   cpuSeconds,
+		memory:       entry.Memory,
+		memoryLimit:  entry.MemoryLimit,
+		networkRx:    entry.NetworkRx,
+		networkTx:    entry.NetworkTx,
+		blockRead:    entry.BlockRead,
+		blockWrite:   entry.BlockWrite,
+		pidsCurrent:  float64(entry.PidsCurrent),
+	}, nil
+}
@@ -0,0 +1,48 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	restarts := &metricFamily{name: "nerdctl_container_restarts_total", typ: "counter", help: "Number of restarts"}
+	restarts.add(3, "id", "abc", "name", `web "1"`)
+	restarts.add(0, "id", "def", "name", "a\\b\nc")
+	images := &metricFamily{name: "nerdctl_images", typ: "gauge", help: "Number of images"}
+	images.add(12)
+	memory := &metricFamily{name: "nerdctl_container_memory_usage_bytes", typ: "gauge", help: "Memory usage"}
+	memory.add(1.5e9, "id", "abc")
+
+	var b bytes.Buffer
+	assert.NilError(t, writeMetrics(&b, []*metricFamily{restarts, images, memory}))
+	assert.Equal(t, b.String(), `# HELP nerdctl_container_restarts_total Number of restarts
+# TYPE nerdctl_container_restarts_total counter
+nerdctl_container_restarts_total{id="abc",name="web \"1\""} 3
+nerdctl_container_restarts_total{id="def",name="a\\b\nc"} 0
+# HELP nerdctl_images Number of images
+# TYPE nerdctl_images gauge
+nerdctl_images 12
+# HELP nerdctl_container_memory_usage_bytes Memory usage
+# TYPE nerdctl_container_memory_usage_bytes gauge
+nerdctl_container_memory_usage_bytes{id="abc"} 1.5e+09
+`)
+}
@@ -19,23 +19,36 @@ package system
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/prune"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 )
 
 // Prune will remove all unused containers, networks,
 // images (dangling only or both dangling and unreferenced), and optionally, volumes.
 func Prune(ctx context.Context, client *containerd.Client, options types.SystemPruneOptions) error {
+	filters, err := parsePruneFilters(options.Filters, time.Now())
+	if err != nil {
+		return err
+	}
+
 	if err := container.Prune(ctx, client, types.ContainerPruneOptions{
 		GOptions: options.GOptions,
 		Stdout:   options.Stdout,
+		Filters:  filters.Strings(),
+		DryRun:   options.DryRun,
 	}); err != nil {
 		return err
 	}
@@ -43,6 +56,8 @@ func Prune(ctx context.Context, client *containerd.Client, options types.SystemP
 		GOptions:             options.GOptions,
 		NetworkDriversToKeep: options.NetworkDriversToKeep,
 		Stdout:               options.Stdout,
+		Filters:              filters.Strings(),
+		DryRun:               options.DryRun,
 	}); err != nil {
 		return err
 	}
@@ -52,6 +67,9 @@ func Prune(ctx context.Context, client *containerd.Client, options types.SystemP
 			All:      false,
 			Force:    true,
 			Stdout:   options.Stdout,
+			// volumes do not have the creation time, so "until" is not applicable
+			Filters: filters.Labels,
+			DryRun:  options.DryRun,
 		}); err != nil {
 			return err
 		}
@@ -60,25 +78,44 @@ func Prune(ctx context.Context, client *containerd.Client, options types.SystemP
 		Stdout:   options.Stdout,
 		GOptions: options.GOptions,
 		All:      options.All,
+		Filters:  filters.Strings(),
+		DryRun:   options.DryRun,
 	}); err != nil {
-		return nil
+		return err
 	}
 
-	if options.BuildKitHost != "" {
+	if options.BuildCache && options.BuildKitHost != "" {
+		var buildCacheFilters []string
+		if !filters.Until.IsZero() {
+			// BuildKit only takes the duration to keep the cache records
+			buildCacheFilters = append(buildCacheFilters, "until="+max(time.Since(filters.Until), 0).Round(time.Second).String())
+		}
 		prunedObjects, err := builder.Prune(ctx, types.BuilderPruneOptions{
 			Stderr:       options.Stderr,
 			GOptions:     options.GOptions,
 			All:          options.All,
 			BuildKitHost: options.BuildKitHost,
+			Filters:      buildCacheFilters,
+			DryRun:       options.DryRun,
 		})
 		if err != nil {
 			return err
 		}
 
 		if len(prunedObjects) > 0 {
-			fmt.Fprintln(options.Stdout, "Deleted build cache objects:")
-			for _, item := range prunedObjects {
-				fmt.Fprintln(options.Stdout, item.ID)
+			if options.DryRun {
+				var total int64
+				fmt.Fprintln(options.Stdout, "Would Delete Build Cache Objects:")
+				for _, item := range prunedObjects {
+					fmt.Fprintf(options.Stdout, "%s\t%s\n", item.ID, units.HumanSize(float64(item.Size)))
+					total += item.Size
+				}
+				fmt.Fprintf(options.Stdout, "Total reclaimable build cache: %s\n", units.HumanSize(float64(total)))
+			} else {
+				fmt.Fprintln(options.Stdout, "Deleted build cache objects:")
+				for _, item := range prunedObjects {
+					fmt.Fprintln(options.Stdout, item.ID)
+				}
 			}
 		}
 	}
@@ -87,3 +124,18 @@ func Prune(ctx context.Context, client *containerd.Client, options types.SystemP
 
 	return nil
 }
+
+// parsePruneFilters parses the filters of `nerdctl system prune`.
+// The label! filters are not supported, as the images can't be filtered by them.
+func parsePruneFilters(filters []string, now time.Time) (*prune.Filters, error) {
+	res, err := prune.ParseFilters(filters, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range res.Labels {
+		if strings.HasPrefix(filter, "label!=") {
+			return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePruneFilters(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	f, err := parsePruneFilters([]string{"until=1h", "label=foo", "label=bar=baz"}, now)
+	assert.NilError(t, err)
+	assert.Assert(t, f.Until.Equal(now.Add(-time.Hour)), f.Until)
+	assert.DeepEqual(t, f.Labels, []string{"label=foo", "label=bar=baz"})
+
+	// the images can't be filtered by label!
+	_, err = parsePruneFilters([]string{"label!=foo"}, now)
+	assert.ErrorContains(t, err, "invalid filter")
+}
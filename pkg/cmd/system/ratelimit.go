@@ -0,0 +1,60 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/registry"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+// RateLimit prints the pull rate limit of Docker Hub, warning when the remaining pulls are low.
+func RateLimit(ctx context.Context, options types.SystemRateLimitOptions) error {
+	rl, err := registry.QueryRateLimit(ctx, options.GOptions)
+	if err != nil {
+		return err
+	}
+	if rl != nil && rl.Low() {
+		log.G(ctx).Warnf("only %d of %d pulls remain (Hint: log in with `nerdctl login` to raise the rate limit)", rl.Remaining, rl.Limit)
+	}
+
+	if options.Format != "" {
+		tmpl, err := formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(options.Stdout, rl); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(options.Stdout)
+		return err
+	}
+
+	if rl == nil {
+		_, err = fmt.Fprintln(options.Stdout, "Docker Hub does not report a pull rate limit")
+		return err
+	}
+	fmt.Fprintf(options.Stdout, "Limit:     %d per %s\n", rl.Limit, rl.Window)
+	fmt.Fprintf(options.Stdout, "Remaining: %d\n", rl.Remaining)
+	_, err = fmt.Fprintf(options.Stdout, "Source:    %s\n", rl.Source)
+	return err
+}
@@ -0,0 +1,237 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// DefaultSupervisorInterval is the default interval of reconciling all the containers,
+// same as the restart monitor plugin of containerd.
+const DefaultSupervisorInterval = 10 * time.Second
+
+// Supervisor enforces the restart policies of the containers, independently of the restart monitor plugin of containerd.
+//
+// The containers are restarted as soon as their tasks exit, and all the containers are also reconciled every
+// options.Interval, so that the containers that exited while the supervisor was not running are restarted too.
+// Unlike the restart monitor plugin, the containers stopped by `nerdctl stop` are never restarted, like Docker.
+func Supervisor(ctx context.Context, client *containerd.Client, options types.SystemSupervisorOptions) error {
+	if options.SystemdUnit {
+		unit, err := renderSupervisorUnit(options)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(options.Stdout, unit)
+		return err
+	}
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultSupervisorInterval
+	}
+	filter := `topic=="/tasks/exit"`
+	if !options.AllNamespaces {
+		filter = fmt.Sprintf("namespace==%s,%s", options.GOptions.Namespace, filter)
+	}
+	eventsCh, errCh := client.EventService().Subscribe(ctx, filter)
+
+	reconcileAll := func() {
+		nsList := []string{options.GOptions.Namespace}
+		if options.AllNamespaces {
+			var err error
+			if nsList, err = client.NamespaceService().List(ctx); err != nil {
+				log.G(ctx).WithError(err).Error("failed to list the namespaces")
+				return
+			}
+		}
+		for _, ns := range nsList {
+			ctx := namespaces.WithNamespace(ctx, ns)
+			containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q", restart.PolicyLabel))
+			if err != nil {
+				log.G(ctx).WithError(err).Error("failed to list the containers")
+				continue
+			}
+			for _, c := range containers {
+				if err := reconcileRestartPolicy(ctx, c); err != nil {
+					log.G(ctx).WithError(err).Errorf("failed to restart container %s", c.ID())
+				}
+			}
+		}
+	}
+	reconcileAll()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errCh:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+			reconcileAll()
+		case e := <-eventsCh:
+			v, err := typeurl.UnmarshalAny(e.Event)
+			if err != nil {
+				log.G(ctx).WithError(err).Warn("failed to decode the event")
+				continue
+			}
+			exit, ok := v.(*eventstypes.TaskExit)
+			// the exits of the processes of `nerdctl exec` are ignored
+			if !ok || exit.ID != exit.ContainerID {
+				continue
+			}
+			ctx := namespaces.WithNamespace(ctx, e.Namespace)
+			c, err := client.LoadContainer(ctx, exit.ContainerID)
+			if err != nil {
+				if !errdefs.IsNotFound(err) {
+					log.G(ctx).WithError(err).Errorf("failed to load container %s", exit.ContainerID)
+				}
+				continue
+			}
+			if err := reconcileRestartPolicy(ctx, c); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to restart container %s", c.ID())
+			}
+		}
+	}
+}
+
+// reconcileRestartPolicy restarts the container when its task has exited and its restart policy requires so.
+// The task is restarted the same way as the restart monitor plugin of containerd does, so that the OCI hooks
+// set up the network again, and the logs are written to the log URI of the container.
+func reconcileRestartPolicy(ctx context.Context, c containerd.Container) error {
+	labels, err := c.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	if labels[restart.PolicyLabel] == "" || containerd.ProcessStatus(labels[restart.StatusLabel]) != containerd.Running {
+		return nil
+	}
+	if explicitlyStopped, _ := strconv.ParseBool(labels[restart.ExplicitlyStoppedLabel]); explicitlyStopped {
+		return nil
+	}
+
+	var status containerd.Status
+	task, err := c.Task(ctx, nil)
+	if err == nil {
+		if status, err = task.Status(ctx); err != nil {
+			return err
+		}
+		if status.Status != containerd.Stopped {
+			return nil
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+	if !restart.Reconcile(status, labels) {
+		return nil
+	}
+
+	count, _ := strconv.Atoi(labels[restart.CountLabel])
+	log.G(ctx).Infof("restarting container %s (restart policy %q, exit status %d)", c.ID(), labels[restart.PolicyLabel], status.ExitStatus)
+	if err := c.Update(ctx, containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(map[string]string{
+		restart.CountLabel: strconv.Itoa(count + 1),
+	}))); err != nil {
+		return err
+	}
+	if task != nil {
+		if _, err := task.Delete(ctx); err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	ioCreator := cio.NullIO
+	if logURI := labels[restart.LogURILabel]; logURI != "" {
+		spec, err := c.Spec(ctx)
+		if err != nil {
+			return err
+		}
+		u, err := url.Parse(logURI)
+		if err != nil {
+			return fmt.Errorf("failed to parse the log URI %q: %w", logURI, err)
+		}
+		if spec.Process != nil && spec.Process.Terminal {
+			ioCreator = cio.TerminalLogURI(u)
+		} else {
+			ioCreator = cio.LogURI(u)
+		}
+	}
+	task, err = c.NewTask(ctx, ioCreator)
+	if err != nil {
+		return err
+	}
+	return task.Start(ctx)
+}
+
+func renderSupervisorUnit(options types.SystemSupervisorOptions) (string, error) {
+	if options.NerdctlCmd == "" {
+		return "", errors.New("the command name of nerdctl is not specified")
+	}
+	args := []string{options.NerdctlCmd}
+	for _, arg := range options.NerdctlArgs {
+		// the hidden --global-* flags are read from nerdctl.toml by the supervisor itself
+		if !strings.HasPrefix(arg, "--global-") {
+			args = append(args, arg)
+		}
+	}
+	args = append(args, "system", "supervisor")
+	if options.AllNamespaces {
+		args = append(args, "--all-namespaces")
+	}
+	if options.Interval > 0 && options.Interval != DefaultSupervisorInterval {
+		args = append(args, "--interval="+options.Interval.String())
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by `nerdctl system supervisor --systemd-unit`\n\n")
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=nerdctl restart policy supervisor\n")
+	b.WriteString("After=containerd.service\n")
+	b.WriteString("Requires=containerd.service\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	b.WriteString("Restart=always\n")
+	b.WriteString("\n[Install]\n")
+	if rootlessutil.IsRootless() {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestRenderSupervisorUnit(t *testing.T) {
+	unit, err := renderSupervisorUnit(types.SystemSupervisorOptions{
+		NerdctlCmd:    "/usr/local/bin/nerdctl",
+		NerdctlArgs:   []string{"--namespace=foo", "--global-runtimes=[kata]\ntype = 'io.containerd.kata.v2'"},
+		AllNamespaces: true,
+		Interval:      time.Minute,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(unit, "\nExecStart=/usr/local/bin/nerdctl --namespace=foo system supervisor --all-namespaces --interval=1m0s\n"), unit)
+	assert.Assert(t, strings.Contains(unit, "\nRequires=containerd.service\n"), unit)
+	assert.Assert(t, !strings.Contains(unit, "--global-runtimes"), unit)
+
+	unit, err = renderSupervisorUnit(types.SystemSupervisorOptions{
+		NerdctlCmd: "nerdctl",
+		Interval:   DefaultSupervisorInterval,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(unit, "\nExecStart=nerdctl system supervisor\n"), unit)
+
+	_, err = renderSupervisorUnit(types.SystemSupervisorOptions{})
+	assert.ErrorContains(t, err, "command name of nerdctl")
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+// Clone creates a local volume named dst with the labels, the size limit, and a copy of the contents of src.
+//
+// On filesystems supporting reflinks (e.g., btrfs, or xfs with reflink=1), the files are cloned
+// instead of being copied, as the copy uses copy_file_range(2) on Linux, and clonefile(2) on macOS.
+func Clone(ctx context.Context, src, dst string, options types.VolumeCloneOptions) (retErr error) {
+	volStore, err := Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	srcVol, err := volStore.Get(src, false)
+	if err != nil {
+		return err
+	}
+	if err := checkLocalData(srcVol); err != nil {
+		return err
+	}
+	if _, err := volStore.Get(dst, false); err == nil {
+		return fmt.Errorf("volume %q already exists: %w", dst, errdefs.ErrAlreadyExists)
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+
+	createOptions := types.VolumeCreateOptions{
+		GOptions: options.GOptions,
+		Stdout:   io.Discard,
+	}
+	if srcVol.Labels != nil {
+		for k, v := range *srcVol.Labels {
+			// the clone is a named volume
+			if k == labels.AnonymousVolumes {
+				continue
+			}
+			createOptions.Labels = append(createOptions.Labels, k+"="+v)
+		}
+	}
+	if srcVol.Options != nil {
+		if size := (*srcVol.Options)[mountutil.VolumeOptSize]; size != "" {
+			createOptions.Options = append(createOptions.Options, mountutil.VolumeOptSize+"="+size)
+		}
+	}
+	dstVol, err := Create(ctx, dst, createOptions)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		if _, _, err := volStore.Remove(func() ([]string, []error, error) {
+			return []string{dst}, nil, nil
+		}); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to remove volume %q", dst)
+		} else {
+			publishEvent(ctx, options.GOptions, eventutil.VolumeDestroyTopic, dstVol)
+		}
+	}()
+
+	if err := fs.CopyDir(dstVol.Mountpoint, srcVol.Mountpoint); err != nil {
+		return fmt.Errorf("failed to copy volume %q to %q: %w", src, dst, err)
+	}
+	fmt.Fprintln(options.Stdout, dst)
+	return nil
+}
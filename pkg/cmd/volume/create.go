@@ -17,17 +17,27 @@
 package volume
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/docker/docker/pkg/stringid"
 
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/nerdctl/v2/pkg/volumeplugin"
 )
 
-func Create(name string, options types.VolumeCreateOptions) (*native.Volume, error) {
+func Create(ctx context.Context, name string, options types.VolumeCreateOptions) (*native.Volume, error) {
 	if name == "" {
 		name = stringid.GenerateRandomID()
 		options.Labels = append(options.Labels, labels.AnonymousVolumes+"=")
@@ -37,10 +47,66 @@ func Create(name string, options types.VolumeCreateOptions) (*native.Volume, err
 		return nil, err
 	}
 	labels := strutil.DedupeStrSlice(options.Labels)
-	vol, err := volStore.Create(name, labels)
+	opts := strutil.ConvertKVStringsToMap(options.Options)
+	driver := options.Driver
+	if driver == LocalDriver {
+		driver = ""
+	}
+	created := false
+	if driver != "" {
+		if created, err = createPluginVolume(ctx, volStore, name, driver, opts); err != nil {
+			return nil, err
+		}
+	} else if err := mountutil.ValidateVolumeOptions(opts); err != nil {
+		return nil, err
+	}
+	vol, err := volStore.Create(name, driver, labels, opts)
 	if err != nil {
+		if created {
+			if rmErr := removeFromPlugin(ctx, name, driver); rmErr != nil {
+				log.G(ctx).WithError(rmErr).Warnf("failed to remove volume %q from volume plugin %q", name, driver)
+			}
+		}
 		return nil, err
 	}
+	publishEvent(ctx, options.GOptions, eventutil.VolumeCreateTopic, vol)
 	fmt.Fprintln(options.Stdout, name)
 	return vol, nil
 }
+
+// publishEvent publishes an event of the volume, for the commands not connected to containerd.
+// Creating a volume does not need containerd otherwise, so the event is skipped when containerd is not available.
+func publishEvent(ctx context.Context, globalOptions types.GlobalCommandOptions, topic string, vol *native.Volume) {
+	client, ctx, cancel, err := clientutil.NewClient(ctx, globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		log.G(ctx).WithError(err).Debugf("not publishing the %s event of volume %q", topic, vol.Name)
+		return
+	}
+	defer cancel()
+	defer client.Close()
+	eventutil.PublishVolumeEvent(ctx, client, topic, &eventutil.VolumeEvent{
+		Name:   vol.Name,
+		Driver: vol.Driver,
+	})
+}
+
+// createPluginVolume creates the volume with the volume plugin, unless the volume already exists.
+func createPluginVolume(ctx context.Context, volStore volumestore.VolumeStore, name, driver string, opts map[string]string) (created bool, err error) {
+	if err := identifiers.ValidateDockerCompat(name); err != nil {
+		return false, err
+	}
+	if vol, err := volStore.Get(name, false); err == nil {
+		if vol.Driver != driver {
+			return false, fmt.Errorf("volume %q already exists with driver %q: %w", name, DriverName(vol), errdefs.ErrAlreadyExists)
+		}
+		return false, nil
+	}
+	p, err := volumeplugin.Lookup(ctx, driver)
+	if err != nil {
+		return false, err
+	}
+	if err := p.Create(ctx, name, opts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,163 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+// Compression types of `nerdctl volume export`
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// Export writes the contents of a local volume as a tar archive, with the ownership and the xattrs of the files.
+func Export(ctx context.Context, name string, options types.VolumeExportOptions) (retErr error) {
+	volStore, err := Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	vol, err := volStore.Get(name, false)
+	if err != nil {
+		return err
+	}
+	if err := checkLocalData(vol); err != nil {
+		return err
+	}
+
+	w := options.Stdout
+	switch options.Compression {
+	case "", CompressionNone:
+	case CompressionGzip:
+		gw := gzip.NewWriter(w)
+		defer func() {
+			if err := gw.Close(); retErr == nil {
+				retErr = err
+			}
+		}()
+		w = gw
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := zw.Close(); retErr == nil {
+				retErr = err
+			}
+		}()
+		w = zw
+	default:
+		return fmt.Errorf("unknown compression %q, expected %q, %q, or %q: %w",
+			options.Compression, CompressionNone, CompressionGzip, CompressionZstd, errdefs.ErrInvalidArgument)
+	}
+
+	// the archive is the diff between an empty directory and the volume
+	emptyDir, err := os.MkdirTemp("", "nerdctl-volume-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(emptyDir)
+	if err := archive.WriteDiff(ctx, w, emptyDir, vol.Mountpoint); err != nil {
+		return fmt.Errorf("failed to export volume %q: %w", name, err)
+	}
+	return nil
+}
+
+// Import extracts a tar archive, optionally compressed with gzip or zstd, in a local volume.
+// The volume is created when it does not exist, and must be empty otherwise.
+func Import(ctx context.Context, name string, options types.VolumeImportOptions) (retErr error) {
+	volStore, err := Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	vol, err := volStore.Get(name, false)
+	if errdefs.IsNotFound(err) {
+		vol, err = Create(ctx, name, types.VolumeCreateOptions{
+			GOptions: options.GOptions,
+			Stdout:   io.Discard,
+		})
+		if err == nil {
+			created := vol
+			defer func() {
+				if retErr == nil {
+					return
+				}
+				if _, _, err := volStore.Remove(func() ([]string, []error, error) {
+					return []string{name}, nil, nil
+				}); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to remove volume %q", name)
+				} else {
+					publishEvent(ctx, options.GOptions, eventutil.VolumeDestroyTopic, created)
+				}
+			}()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkLocalData(vol); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(vol.Mountpoint)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("volume %q is not empty: %w", name, errdefs.ErrFailedPrecondition)
+	}
+
+	decomp, err := compression.DecompressStream(options.Stdin)
+	if err != nil {
+		return err
+	}
+	defer decomp.Close()
+	if _, err := archive.Apply(ctx, vol.Mountpoint, decomp); err != nil {
+		return fmt.Errorf("failed to import volume %q: %w", name, err)
+	}
+	fmt.Fprintln(options.Stdout, name)
+	return nil
+}
+
+// checkLocalData checks that the data of the volume is in its local directory.
+func checkLocalData(vol *native.Volume) error {
+	if vol.Driver != "" {
+		return fmt.Errorf("volume %q is managed by the volume plugin %q: %w", vol.Name, vol.Driver, errdefs.ErrNotImplemented)
+	}
+	if vol.Options != nil && (*vol.Options)[mountutil.VolumeOptType] != "" {
+		return fmt.Errorf("volume %q mounts a %s filesystem: %w", vol.Name, (*vol.Options)[mountutil.VolumeOptType], errdefs.ErrNotImplemented)
+	}
+	return nil
+}
@@ -20,17 +20,34 @@ import (
 	"context"
 	"errors"
 
+	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
+	"github.com/containerd/nerdctl/v2/pkg/volumeplugin"
 )
 
-func Inspect(ctx context.Context, volumes []string, options types.VolumeInspectOptions) error {
+// Inspect prints the volumes.
+// client is only used to count the containers referencing the volumes with options.Size, and may be nil.
+func Inspect(ctx context.Context, client *containerd.Client, volumes []string, options types.VolumeInspectOptions) error {
 	volStore, err := Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
 	if err != nil {
 		return err
 	}
+	var refCounts map[string]int64
+	if options.Size && client != nil {
+		containers, err := client.Containers(ctx)
+		if err == nil {
+			refCounts, err = UsedVolumes(ctx, containers)
+		}
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to count the containers referencing the volumes")
+		}
+	}
 	result := []interface{}{}
 
 	warns := []error{}
@@ -40,6 +57,30 @@ func Inspect(ctx context.Context, volumes []string, options types.VolumeInspectO
 			warns = append(warns, err)
 			continue
 		}
+		if vol.Driver != "" {
+			// the mountpoint of plugin volumes is only known by the plugin, and may be empty when not mounted
+			p, err := volumeplugin.Lookup(ctx, vol.Driver)
+			if err == nil {
+				vol.Mountpoint, err = p.Path(ctx, name)
+			}
+			if err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to get the mountpoint of volume %q", name)
+			}
+		}
+		if vol.Driver == "" && vol.Options != nil {
+			if _, ok := (*vol.Options)[mountutil.VolumeOptSize]; ok {
+				vol.Status = quotaStatus(vol)
+			}
+		}
+		if options.Size {
+			vol.UsageData = &native.VolumeUsageData{Size: vol.Size, RefCount: -1}
+			if vol.Driver != "" {
+				vol.UsageData.Size = -1
+			}
+			if refCounts != nil {
+				vol.UsageData.RefCount = refCounts[name]
+			}
+		}
 		result = append(result, vol)
 	}
 	err = formatter.FormatSlice(options.Format, options.Stdout, result)
@@ -55,3 +96,20 @@ func Inspect(ctx context.Context, volumes []string, options types.VolumeInspectO
 	}
 	return nil
 }
+
+// quotaStatus reports whether the size limit of the volume is enforced.
+func quotaStatus(vol *native.Volume) map[string]any {
+	q, err := volumestore.Quota(vol)
+	if err != nil {
+		return map[string]any{
+			"QuotaEnforced": false,
+			"QuotaError":    err.Error(),
+		}
+	}
+	return map[string]any{
+		"QuotaEnforced":   true,
+		"QuotaFilesystem": q.Filesystem,
+		"QuotaSize":       q.Size,
+		"QuotaUsed":       q.Used,
+	}
+}
@@ -18,6 +18,7 @@ package volume
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -26,7 +27,9 @@ import (
 	"text/tabwriter"
 	"text/template"
 
+	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/progress"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
@@ -42,9 +45,24 @@ type volumePrintable struct {
 	Scope      string
 	Size       string
 	// TODO: "Links"
+
+	labels map[string]string
+}
+
+// Label returns the value of the label with the given name, for `--format '{{.Label "foo"}}'`.
+func (p volumePrintable) Label(name string) string {
+	return p.labels[name]
+}
+
+// volumeTableHeaders are the headers of `--format 'table TEMPLATE'`.
+var volumeTableHeaders = map[string]string{
+	"Name": "VOLUME NAME",
 }
 
-func List(options types.VolumeListOptions) error {
+// List prints the volumes that match the given filters.
+//
+// The client is only needed for the dangling filter, and may be nil otherwise.
+func List(ctx context.Context, client *containerd.Client, options types.VolumeListOptions) error {
 	if options.Quiet && options.Size {
 		log.L.Warn("cannot use --size and --quiet together, ignoring --size")
 		options.Size = false
@@ -59,19 +77,49 @@ func List(options types.VolumeListOptions) error {
 		options.Size = true
 	}
 
-	vols, err := Volumes(
-		options.GOptions.Namespace,
-		options.GOptions.DataRoot,
-		options.GOptions.Address,
-		options.Size,
-		options.Filters,
-	)
+	filters, err := parseVolumeFilters(options.Filters)
+	if err != nil {
+		return err
+	}
+	volStore, err := Store(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address)
 	if err != nil {
 		return err
 	}
+	vols, err := volStore.List(options.Size)
+	if err != nil {
+		return err
+	}
+	var used map[string]int64
+	if filters.dangling != nil {
+		if client == nil {
+			return errors.New("the dangling filter requires a containerd client")
+		}
+		containers, err := client.Containers(ctx)
+		if err != nil {
+			return err
+		}
+		if used, err = UsedVolumes(ctx, containers); err != nil {
+			return err
+		}
+	}
+	for k, v := range vols {
+		if !filters.match(v, used) {
+			delete(vols, k)
+		}
+	}
 	return lsPrintOutput(vols, options)
 }
 
+// HasDanglingFilter returns true if the filters contain a dangling filter.
+func HasDanglingFilter(filters []string) bool {
+	for _, filter := range filters {
+		if strings.HasPrefix(filter, "dangling=") {
+			return true
+		}
+	}
+	return false
+}
+
 func hasSizeFilter(filters []string) bool {
 	for _, filter := range filters {
 		if strings.HasPrefix(filter, "size") {
@@ -111,7 +159,7 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 			return errors.New("format and quiet must not be specified together")
 		}
 		var err error
-		tmpl, err = formatter.ParseTemplate(options.Format)
+		w, tmpl, err = formatter.ParseListTemplate(w, options.Format, volumeTableHeaders, options.Quiet)
 		if err != nil {
 			return err
 		}
@@ -119,7 +167,7 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 
 	for _, v := range vols {
 		p := volumePrintable{
-			Driver:     "local",
+			Driver:     DriverName(&v),
 			Labels:     "",
 			Mountpoint: v.Mountpoint,
 			Name:       v.Name,
@@ -127,6 +175,7 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 		}
 		if v.Labels != nil {
 			p.Labels = formatter.FormatLabels(*v.Labels)
+			p.labels = *v.Labels
 		}
 		if options.Size {
 			p.Size = progress.Bytes(v.Size).String()
@@ -154,47 +203,53 @@ func lsPrintOutput(vols map[string]native.Volume, options types.VolumeListOption
 }
 
 // Volumes returns volumes that match the given filters.
-//
-// Supported filters:
-//   - label=<key>=<value>: Match volumes by label on both key and value.
-//     If value is left empty, match all volumes with key regardless of its value.
-//   - name=<value>: Match all volumes with a name containing the value string.
-//   - size=<value>: Match all volumes with a size meets the value.
-//     Size operand can be >=, <=, >, <, = and value must be an integer.
-//
-// Unsupported filters:
-//   - dangling=true: Filter volumes by dangling.
-//   - driver=local: Filter volumes by driver.
+// See parseVolumeFilters for the supported filters, except dangling, which requires the containers.
 func Volumes(ns string, dataRoot string, address string, volumeSize bool, filters []string) (map[string]native.Volume, error) {
-	volStore, err := Store(ns, dataRoot, address)
+	volFilters, err := parseVolumeFilters(filters)
 	if err != nil {
 		return nil, err
 	}
-	vols, err := volStore.List(volumeSize)
+	if volFilters.dangling != nil {
+		return nil, fmt.Errorf("the dangling filter is not supported here: %w", errdefs.ErrNotImplemented)
+	}
+	volStore, err := Store(ns, dataRoot, address)
 	if err != nil {
 		return nil, err
 	}
-
-	labelFilterFuncs, nameFilterFuncs, sizeFilterFuncs, isFilter, err := getVolumeFilterFuncs(filters)
+	vols, err := volStore.List(volumeSize)
 	if err != nil {
 		return nil, err
 	}
-	if !isFilter {
-		return vols, nil
-	}
 	for k, v := range vols {
-		if !volumeMatchesFilter(v, labelFilterFuncs, nameFilterFuncs, sizeFilterFuncs) {
+		if !volFilters.match(v, nil) {
 			delete(vols, k)
 		}
 	}
 	return vols, nil
 }
 
-func getVolumeFilterFuncs(filters []string) ([]func(*map[string]string) bool, []func(string) bool, []func(int64) bool, bool, error) {
-	isFilter := len(filters) > 0
-	labelFilterFuncs := make([]func(*map[string]string) bool, 0)
-	nameFilterFuncs := make([]func(string) bool, 0)
-	sizeFilterFuncs := make([]func(int64) bool, 0)
+// volumeFilters are the parsed filters of `nerdctl volume ls`.
+// Like Docker, a volume must match all the label and size filters, and any of the name and driver filters.
+type volumeFilters struct {
+	labels   []func(*map[string]string) bool
+	names    []func(string) bool
+	sizes    []func(int64) bool
+	drivers  []func(string) bool
+	dangling *bool
+}
+
+// parseVolumeFilters parses the filters of `nerdctl volume ls`.
+//
+// Supported filters:
+//   - label=<key>=<value>: Match volumes by label on both key and value.
+//     If value is left empty, match all volumes with key regardless of its value.
+//   - name=<value>: Match all volumes with a name matching the value regular expression.
+//   - driver=<value>: Match all volumes with the driver.
+//   - dangling=<bool>: Match all volumes that are not (true) or are (false) referenced by a container.
+//   - size=<value>: Match all volumes with a size meets the value.
+//     Size operand can be >=, <=, >, <, = and value must be an integer.
+func parseVolumeFilters(filters []string) (*volumeFilters, error) {
+	res := &volumeFilters{}
 	sizeOperators := []struct {
 		Operand string
 		Compare func(int64, int64) bool
@@ -216,72 +271,94 @@ func getVolumeFilterFuncs(filters []string) ([]func(*map[string]string) bool, []
 		}},
 	}
 	for _, filter := range filters {
-		if strings.HasPrefix(filter, "name") || strings.HasPrefix(filter, "label") {
-			filter, value, ok := strings.Cut(filter, "=")
-			if !ok {
-				continue
-			}
-			switch filter {
-			case "name":
-				re, err := regexp.Compile(value)
+		if rest, ok := strings.CutPrefix(filter, "size"); ok {
+			found := false
+			for _, sizeOperator := range sizeOperators {
+				value, ok := strings.CutPrefix(rest, sizeOperator.Operand)
+				if !ok {
+					continue
+				}
+				v, err := strconv.Atoi(value)
 				if err != nil {
-					return nil, nil, nil, false, err
+					return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
 				}
-				nameFilterFuncs = append(nameFilterFuncs, func(name string) bool {
-					return re.MatchString(name)
-				})
-			case "label":
-				k, v, hasValue := strings.Cut(value, "=")
-				labelFilterFuncs = append(labelFilterFuncs, func(labels *map[string]string) bool {
-					if labels == nil {
-						return false
-					}
-					val, ok := (*labels)[k]
-					if !ok || (hasValue && val != v) {
-						return false
-					}
-					return true
+				res.sizes = append(res.sizes, func(size int64) bool {
+					return sizeOperator.Compare(int64(v), size)
 				})
+				found = true
+				break
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
 			}
 			continue
 		}
-		if strings.HasPrefix(filter, "size") {
-			for _, sizeOperator := range sizeOperators {
-				if subs := strings.SplitN(filter, sizeOperator.Operand, 2); len(subs) == 2 {
-					v, err := strconv.Atoi(subs[1])
-					if err != nil {
-						return nil, nil, nil, false, err
-					}
-					sizeFilterFuncs = append(sizeFilterFuncs, func(size int64) bool {
-						return sizeOperator.Compare(int64(v), size)
-					})
-					break
+
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("bad format of filter %q (expected name=value): %w", filter, errdefs.ErrInvalidArgument)
+		}
+		switch key {
+		case "name":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, err
+			}
+			res.names = append(res.names, func(name string) bool {
+				return re.MatchString(name)
+			})
+		case "label":
+			k, v, hasValue := strings.Cut(value, "=")
+			res.labels = append(res.labels, func(labels *map[string]string) bool {
+				if labels == nil {
+					return false
+				}
+				val, ok := (*labels)[k]
+				if !ok || (hasValue && val != v) {
+					return false
 				}
+				return true
+			})
+		case "driver":
+			res.drivers = append(res.drivers, func(driver string) bool {
+				return driver == value
+			})
+		case "dangling":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
 			}
-			continue
+			if res.dangling != nil && *res.dangling != b {
+				return nil, fmt.Errorf("conflicting dangling filters: %w", errdefs.ErrInvalidArgument)
+			}
+			res.dangling = &b
+		default:
+			return nil, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
 		}
 	}
-	return labelFilterFuncs, nameFilterFuncs, sizeFilterFuncs, isFilter, nil
+	return res, nil
 }
 
-func volumeMatchesFilter(vol native.Volume, labelFilterFuncs []func(*map[string]string) bool, nameFilterFuncs []func(string) bool, sizeFilterFuncs []func(int64) bool) bool {
-	for _, labelFilterFunc := range labelFilterFuncs {
+// match returns true if the volume matches the filters.
+// used is the number of containers referencing each volume, only needed for the dangling filter.
+func (f *volumeFilters) match(vol native.Volume, used map[string]int64) bool {
+	for _, labelFilterFunc := range f.labels {
 		if !labelFilterFunc(vol.Labels) {
 			return false
 		}
 	}
 
-	if !anyMatch(vol.Name, nameFilterFuncs) {
-		return false
-	}
-
-	for _, sizeFilterFunc := range sizeFilterFuncs {
+	for _, sizeFilterFunc := range f.sizes {
 		if !sizeFilterFunc(vol.Size) {
 			return false
 		}
 	}
 
-	return true
+	if f.dangling != nil && (used[vol.Name] == 0) != *f.dangling {
+		return false
+	}
+
+	return anyMatch(vol.Name, f.names) && anyMatch(DriverName(&vol), f.drivers)
 }
 
 func anyMatch[T any](vol T, filters []func(T) bool) bool {
@@ -19,11 +19,15 @@ package volume
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
@@ -36,16 +40,23 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 		return err
 	}
 
+	labelFilterFuncs, all, err := pruneFilters(options.Filters, options.All)
+	if err != nil {
+		return err
+	}
+
 	var toRemove []string // nolint: prealloc
+	drivers := make(map[string]string)
 
 	err = volStore.Prune(func(volumes []*native.Volume) ([]string, error) {
-		// Get containers and see which volumes are used
+		// Get containers and see which volumes are used.
+		// Stopped containers are listed too, so that their volumes are still there when they are restarted.
 		containers, err := client.Containers(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		usedVolumesList, err := usedVolumes(ctx, containers)
+		usedVolumesList, err := UsedVolumes(ctx, containers)
 		if err != nil {
 			return nil, err
 		}
@@ -54,7 +65,10 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 			if _, ok := usedVolumesList[volume.Name]; ok {
 				continue
 			}
-			if !options.All {
+			if !matchesLabelFilters(volume.Labels, labelFilterFuncs) {
+				continue
+			}
+			if !all {
 				if volume.Labels == nil {
 					continue
 				}
@@ -64,9 +78,23 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 					continue
 				}
 			}
+			if options.DryRun {
+				toRemove = append(toRemove, volume.Name)
+				continue
+			}
+			if volume.Driver != "" {
+				if err := removeFromPlugin(ctx, volume.Name, volume.Driver); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to remove volume %q", volume.Name)
+					continue
+				}
+			}
 			toRemove = append(toRemove, volume.Name)
+			drivers[volume.Name] = volume.Driver
 		}
 
+		if options.DryRun {
+			return nil, nil
+		}
 		return toRemove, nil
 	})
 
@@ -74,6 +102,21 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 		return err
 	}
 
+	if options.DryRun {
+		if len(toRemove) > 0 {
+			fmt.Fprintln(options.Stdout, "Would Delete Volumes:")
+			fmt.Fprintln(options.Stdout, strings.Join(toRemove, "\n"))
+			fmt.Fprintln(options.Stdout, "")
+		}
+		return nil
+	}
+
+	for _, name := range toRemove {
+		eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeDestroyTopic, &eventutil.VolumeEvent{
+			Name:   name,
+			Driver: drivers[name],
+		})
+	}
 	if len(toRemove) > 0 {
 		fmt.Fprintln(options.Stdout, "Deleted Volumes:")
 		fmt.Fprintln(options.Stdout, strings.Join(toRemove, "\n"))
@@ -82,3 +125,50 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 
 	return nil
 }
+
+// pruneFilters parses the filters of `nerdctl volume prune`.
+//
+// Supported filters:
+//   - label=<key> or label=<key>=<value>: Only remove the volumes with the label.
+//   - label!=<key> or label!=<key>=<value>: Only remove the volumes without the label.
+//   - all=<bool>: Remove the named volumes too, like --all.
+func pruneFilters(filters []string, all bool) ([]func(*map[string]string) bool, bool, error) {
+	var labelFilterFuncs []func(*map[string]string) bool
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, false, fmt.Errorf("bad format of filter %q (expected name=value): %w", filter, errdefs.ErrInvalidArgument)
+		}
+		switch key {
+		case "label", "label!":
+			negate := key == "label!"
+			k, v, hasValue := strings.Cut(value, "=")
+			labelFilterFuncs = append(labelFilterFuncs, func(labels *map[string]string) bool {
+				var matched bool
+				if labels != nil {
+					val, ok := (*labels)[k]
+					matched = ok && (!hasValue || val == v)
+				}
+				return matched != negate
+			})
+		case "all":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid filter %q: %w", filter, errdefs.ErrInvalidArgument)
+			}
+			all = all || b
+		default:
+			return nil, false, fmt.Errorf("invalid filter %q: %w", key, errdefs.ErrInvalidArgument)
+		}
+	}
+	return labelFilterFuncs, all, nil
+}
+
+func matchesLabelFilters(labels *map[string]string, labelFilterFuncs []func(*map[string]string) bool) bool {
+	for _, labelFilterFunc := range labelFilterFuncs {
+		if !labelFilterFunc(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -27,9 +27,11 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/volumeplugin"
 )
 
 func Remove(ctx context.Context, client *containerd.Client, volumes []string, options types.VolumeRemoveOptions) error {
@@ -43,9 +45,17 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 		return err
 	}
 
+	// The drivers are read before locking the store, as Get locks it too
+	drivers := make(map[string]string)
+	for _, name := range volumes {
+		if vol, err := volStore.Get(name, false); err == nil && vol.Driver != "" {
+			drivers[name] = vol.Driver
+		}
+	}
+
 	// Note: to avoid racy behavior, this is called by volStore.Remove *inside a lock*
 	removableVolumes := func() (volumeNames []string, cannotRemove []error, err error) {
-		usedVolumesList, err := usedVolumes(ctx, containers)
+		usedVolumesList, err := UsedVolumes(ctx, containers)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -55,6 +65,12 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 				cannotRemove = append(cannotRemove, fmt.Errorf("volume %q is in use (%w)", name, errdefs.ErrFailedPrecondition))
 				continue
 			}
+			if driver, ok := drivers[name]; ok {
+				if err := removeFromPlugin(ctx, name, driver); err != nil {
+					cannotRemove = append(cannotRemove, err)
+					continue
+				}
+			}
 			volumeNames = append(volumeNames, name)
 		}
 
@@ -67,6 +83,10 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	}
 	// Otherwise, output on stdout whatever was successful
 	for _, name := range removedNames {
+		eventutil.PublishVolumeEvent(ctx, client, eventutil.VolumeDestroyTopic, &eventutil.VolumeEvent{
+			Name:   name,
+			Driver: drivers[name],
+		})
 		fmt.Fprintln(options.Stdout, name)
 	}
 	// Log the rest
@@ -79,8 +99,9 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	return nil
 }
 
-func usedVolumes(ctx context.Context, containers []containerd.Container) (map[string]struct{}, error) {
-	usedVolumesList := make(map[string]struct{})
+// UsedVolumes returns the number of containers referencing each volume.
+func UsedVolumes(ctx context.Context, containers []containerd.Container) (map[string]int64, error) {
+	usedVolumesList := make(map[string]int64)
 	for _, c := range containers {
 		l, err := c.Labels(ctx)
 		if err != nil {
@@ -104,9 +125,18 @@ func usedVolumes(ctx context.Context, containers []containerd.Container) (map[st
 		}
 		for _, m := range mounts {
 			if m.Type == mountutil.Volume {
-				usedVolumesList[m.Name] = struct{}{}
+				usedVolumesList[m.Name]++
 			}
 		}
 	}
 	return usedVolumesList, nil
 }
+
+// removeFromPlugin removes the volume from its volume plugin.
+func removeFromPlugin(ctx context.Context, name, driver string) error {
+	p, err := volumeplugin.Lookup(ctx, driver)
+	if err != nil {
+		return fmt.Errorf("volume %q: %w", name, err)
+	}
+	return p.Remove(ctx, name)
+}
@@ -18,9 +18,13 @@ package volume
 
 import (
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 )
 
+// LocalDriver is the name of the built-in volume driver, the volumes of the other drivers are managed by volume plugins.
+const LocalDriver = "local"
+
 // Store returns a volume store
 // that corresponds to a directory like `/var/lib/nerdctl/1935db59/volumes/default`
 func Store(ns string, dataRoot string, address string) (volumestore.VolumeStore, error) {
@@ -30,3 +34,11 @@ func Store(ns string, dataRoot string, address string) (volumestore.VolumeStore,
 	}
 	return volumestore.New(dataStore, ns)
 }
+
+// DriverName returns the name of the volume driver of vol.
+func DriverName(vol *native.Volume) string {
+	if vol.Driver == "" {
+		return LocalDriver
+	}
+	return vol.Driver
+}
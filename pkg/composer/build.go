@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/containerd/log"
 
@@ -34,24 +36,113 @@ type BuildOptions struct {
 	Progress string
 }
 
+// Build builds the images of the services concurrently, like `docker buildx bake`.
+// A service whose build uses the image of another service as an additional context
+// (`service:<name>`) is built after that service, which is built even if it was not specified.
 func (c *Composer) Build(ctx context.Context, bo BuildOptions, services []string) error {
-	return c.project.ForEachService(services, func(names string, svc *types.ServiceConfig) error {
+	builds := make(map[string]*serviceparser.Service)
+	var parseService func(name string, svc *types.ServiceConfig) error
+	parseService = func(name string, svc *types.ServiceConfig) error {
+		if _, ok := builds[name]; ok {
+			return nil
+		}
 		ps, err := serviceparser.Parse(c.project, *svc)
 		if err != nil {
 			return err
 		}
-		if ps.Build != nil {
-			return c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, bo)
+		if ps.Build == nil {
+			return nil
+		}
+		builds[name] = ps
+		for _, dep := range ps.Build.DependsOn {
+			depSvc, err := c.project.GetService(dep)
+			if err != nil {
+				return err
+			}
+			if err := parseService(dep, &depSvc); err != nil {
+				return err
+			}
 		}
 		return nil
-	}, types.IgnoreDependencies)
+	}
+	if err := c.project.ForEachService(services, parseService, types.IgnoreDependencies); err != nil {
+		return err
+	}
+	if err := checkBuildCycles(builds); err != nil {
+		return err
+	}
+
+	built := make(map[string]chan struct{}, len(builds))
+	for name := range builds {
+		built[name] = make(chan struct{})
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	for name, ps := range builds {
+		eg.Go(func() error {
+			for _, dep := range ps.Build.DependsOn {
+				if _, ok := built[dep]; !ok {
+					// the service has no build section, so its image is used as-is
+					continue
+				}
+				select {
+				case <-built[dep]:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if err := c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, bo); err != nil {
+				return err
+			}
+			close(built[name])
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// checkBuildCycles returns an error if the builds depend on each other's images in a cycle.
+func checkBuildCycles(builds map[string]*serviceparser.Service) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(builds))
+	var visit func(name string) error
+	visit = func(name string) error {
+		ps, ok := builds[name]
+		if !ok {
+			return nil
+		}
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("build of service %s depends on its own image via additional contexts", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range ps.Build.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for name := range builds {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *Composer) buildServiceImage(ctx context.Context, image string, b *serviceparser.Build, platform string, bo BuildOptions) error {
 	log.G(ctx).Infof("Building image %s", image)
 
 	var args []string // nolint: prealloc
-	if platform != "" {
+	if len(b.Platforms) > 0 {
+		args = append(args, "--platform="+strings.Join(b.Platforms, ","))
+	} else if platform != "" {
 		args = append(args, "--platform="+platform)
 	}
 	for _, a := range bo.Args {
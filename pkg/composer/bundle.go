@@ -0,0 +1,52 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// BundleImages makes sure that the images of the services are present locally,
+// building or pulling the missing ones, and returns their names.
+func (c *Composer) BundleImages(ctx context.Context, services []string) ([]string, error) {
+	parsedServices, err := c.Services(ctx, services...)
+	if err != nil {
+		return nil, err
+	}
+	var imgs []string
+	for _, ps := range parsedServices {
+		if err := c.ensureServiceImage(ctx, ps, true, false, BuildOptions{}, true, "missing"); err != nil {
+			return nil, err
+		}
+		imgs = append(imgs, ps.Image)
+	}
+	return strutil.DedupeStrSlice(imgs), nil
+}
+
+// NetworkNames returns the full names of the networks declared in the project,
+// including the external ones.
+func (c *Composer) NetworkNames() []string {
+	names := make([]string, 0, len(c.project.Networks))
+	for _, net := range c.project.Networks {
+		names = append(names, net.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"slices"
 
 	composecli "github.com/compose-spec/compose-go/v2/cli"
 	compose "github.com/compose-spec/compose-go/v2/types"
@@ -33,6 +35,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 // Options groups the command line options recommended for a Compose implementation (ProjectOptions) and extra options for nerdctl
@@ -50,6 +53,8 @@ type Options struct {
 	VolumeExists     func(string) (bool, error)
 	ImageExists      func(ctx context.Context, imageName string) (bool, error)
 	EnsureImage      func(ctx context.Context, imageName, pullMode, platform string, ps *serviceparser.Service, quiet bool) error
+	ResolveDigest    func(ctx context.Context, imageName string) (string, error)
+	NoInterpolate    bool // do not interpolate environment variables in the compose files
 	DebugPrintFull   bool // full debug print, may leak secret env var to logs
 	Experimental     bool // enable experimental features
 	IPFSAddress      string
@@ -63,6 +68,42 @@ func New(o Options, client *containerd.Client, cfg *config.Config) (*Composer, e
 		return nil, errors.New("got empty functions")
 	}
 
+	project, err := loadProject(o)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.DebugPrintFull {
+		projectJSON, _ := json.MarshalIndent(project, "", "    ")
+		log.L.Debug("printing project JSON")
+		log.L.Debugf("%s", projectJSON)
+	}
+
+	if unknown := reflectutil.UnknownNonEmptyFields(project,
+		"Name",
+		"WorkingDir",
+		"Environment",
+		"Services",
+		"Networks",
+		"Volumes",
+		"Secrets",
+		"Configs",
+		"ComposeFiles"); len(unknown) > 0 {
+		log.L.Warnf("Ignoring: %+v", unknown)
+	}
+
+	c := &Composer{
+		Options: o,
+		project: project,
+		client:  client,
+		config:  cfg,
+	}
+
+	return c, nil
+}
+
+// loadProject loads the compose project specified by o.
+func loadProject(o Options) (*compose.Project, error) {
 	if o.Project != "" {
 		if err := identifiers.ValidateDockerCompat(o.Project); err != nil {
 			return nil, fmt.Errorf("invalid project name: %w", err)
@@ -87,43 +128,28 @@ func New(o Options, client *containerd.Client, cfg *config.Config) (*Composer, e
 		composecli.WithName(o.Project),
 		composecli.WithProfiles(o.Profiles),
 	)
+	if o.NoInterpolate {
+		optionsFn = append(optionsFn,
+			composecli.WithInterpolation(false),
+		)
+	}
 
 	projectOptions, err := composecli.NewProjectOptions(o.ConfigPaths, optionsFn...)
 	if err != nil {
 		return nil, err
 	}
-	project, err := projectOptions.LoadProject(context.TODO())
+	return projectOptions.LoadProject(context.TODO())
+}
+
+// ProjectServiceNames returns the service names of the compose project specified by o, in dependency order.
+// Unlike New, it does not need a containerd client. Used for shell completion.
+func ProjectServiceNames(o Options) ([]string, error) {
+	project, err := loadProject(o)
 	if err != nil {
 		return nil, err
 	}
-
-	if o.DebugPrintFull {
-		projectJSON, _ := json.MarshalIndent(project, "", "    ")
-		log.L.Debug("printing project JSON")
-		log.L.Debugf("%s", projectJSON)
-	}
-
-	if unknown := reflectutil.UnknownNonEmptyFields(project,
-		"Name",
-		"WorkingDir",
-		"Environment",
-		"Services",
-		"Networks",
-		"Volumes",
-		"Secrets",
-		"Configs",
-		"ComposeFiles"); len(unknown) > 0 {
-		log.L.Warnf("Ignoring: %+v", unknown)
-	}
-
-	c := &Composer{
-		Options: o,
-		project: project,
-		client:  client,
-		config:  cfg,
-	}
-
-	return c, nil
+	c := &Composer{Options: o, project: project}
+	return c.ServiceNames()
 }
 
 type Composer struct {
@@ -134,7 +160,12 @@ type Composer struct {
 }
 
 func (c *Composer) createNerdctlCmd(ctx context.Context, args ...string) *exec.Cmd {
-	return exec.CommandContext(ctx, c.NerdctlCmd, append(c.NerdctlArgs, args...)...)
+	cmd := exec.CommandContext(ctx, c.NerdctlCmd, append(c.NerdctlArgs, args...)...)
+	if env := tracingutil.Environ(ctx); len(env) > 0 {
+		// Propagate the trace context, so that the spans of the nerdctl subcommands are recorded under the compose spans
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
 }
 
 func (c *Composer) runNerdctlCmd(ctx context.Context, args ...string) error {
@@ -176,3 +207,22 @@ func (c *Composer) ServiceNames(svcs ...string) ([]string, error) {
 	}
 	return names, nil
 }
+
+// serviceNamesNoDeps returns the service names in dependency order like ServiceNames,
+// but excludes the dependencies of `svcs` that are not listed when `noDeps` is true.
+func (c *Composer) serviceNamesNoDeps(noDeps bool, svcs ...string) ([]string, error) {
+	names, err := c.ServiceNames(svcs...)
+	if err != nil {
+		return nil, err
+	}
+	if !noDeps || len(svcs) == 0 {
+		return names, nil
+	}
+	var filtered []string
+	for _, name := range names {
+		if slices.Contains(svcs, name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
@@ -28,9 +28,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/distribution/reference"
 	"github.com/opencontainers/go-digest"
 	"go.yaml.in/yaml/v3"
 )
@@ -38,10 +41,28 @@ import (
 type ConfigOptions struct {
 	Services bool
 	Volumes  bool
+	Profiles bool
 	Hash     string
+	// Format is either "yaml" (default) or "json"
+	Format string
+	// ResolveImageDigests pins the image of each service to its digest in the registry
+	ResolveImageDigests bool
 }
 
 func (c *Composer) Config(ctx context.Context, w io.Writer, co ConfigOptions) error {
+	if co.Profiles {
+		// list the profiles declared by all the services, including those not enabled
+		profiles := make(map[string]struct{})
+		for _, service := range c.project.AllServices() {
+			for _, p := range service.Profiles {
+				profiles[p] = struct{}{}
+			}
+		}
+		for _, p := range slices.Sorted(maps.Keys(profiles)) {
+			fmt.Fprintln(w, p)
+		}
+		return nil
+	}
 	if co.Services {
 		for _, service := range c.project.Services {
 			fmt.Fprintln(w, service.Name)
@@ -68,11 +89,39 @@ func (c *Composer) Config(ctx context.Context, w io.Writer, co ConfigOptions) er
 			return err
 		})
 	}
-	projectYAML, err := yaml.Marshal(c.project)
+	project := c.project
+	if co.ResolveImageDigests {
+		var err error
+		project, err = project.WithImagesResolved(func(named reference.Named) (digest.Digest, error) {
+			dgst, err := c.ResolveDigest(ctx, named.String())
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve the digest of image %s: %w", named, err)
+			}
+			return digest.Parse(dgst)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	var (
+		out []byte
+		err error
+	)
+	switch co.Format {
+	case "", "yaml":
+		out, err = yaml.Marshal(project)
+	case "json":
+		out, err = project.MarshalJSON()
+	default:
+		return fmt.Errorf("unsupported format %q, must be either \"yaml\" or \"json\"", co.Format)
+	}
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s", projectYAML)
+	fmt.Fprintf(w, "%s", out)
+	if co.Format == "json" {
+		fmt.Fprintln(w)
+	}
 	return nil
 }
 
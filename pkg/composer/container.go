@@ -19,22 +19,30 @@ package composer
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
+// Containers returns the containers of `services`, or of all the services enabled by the active profiles
+// when `services` is empty. Containers of services disabled by the profiles are never returned.
 func (c *Composer) Containers(ctx context.Context, services ...string) ([]containerd.Container, error) {
+	if len(services) == 0 {
+		services = c.project.ServiceNames()
+		if len(services) == 0 {
+			return nil, nil
+		}
+	}
 	projectLabel := fmt.Sprintf("labels.%q==%s", labels.ComposeProject, c.project.Name)
 	filters := []string{}
 	for _, service := range services {
 		filters = append(filters, fmt.Sprintf("%s,labels.%q==%s", projectLabel, labels.ComposeService, service))
 	}
-	if len(services) == 0 {
-		filters = append(filters, projectLabel)
-	}
 	log.G(ctx).Debugf("filters: %v", filters)
 	containers, err := c.client.Containers(ctx, filters...)
 	if err != nil {
@@ -43,44 +51,65 @@ func (c *Composer) Containers(ctx context.Context, services ...string) ([]contai
 	return containers, nil
 }
 
-func (c *Composer) containerExists(ctx context.Context, name, service string) (bool, error) {
+func (c *Composer) containerID(ctx context.Context, name, service string) (string, error) {
 	// get list of containers for service
 	containers, err := c.Containers(ctx, service)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	for _, container := range containers {
 		containerLabels, err := container.Labels(ctx)
 		if err != nil {
-			return false, err
+			return "", err
 		}
 		if name == containerLabels[labels.Name] {
 			// container exists
-			return true, nil
+			return container.ID(), nil
 		}
 	}
 	// container doesn't exist
-	return false, nil
+	return "", nil
 }
 
-func (c *Composer) containerID(ctx context.Context, name, service string) (string, error) {
-	// get list of containers for service
+// serviceContainer returns the replica of the service with the given index (starting from 1),
+// i.e. the container named `<PROJECT>-<SERVICE>-<INDEX>`.
+// A service with a single container (e.g. with `container_name`) only has the index 1.
+func (c *Composer) serviceContainer(ctx context.Context, service string, index int) (containerd.Container, error) {
 	containers, err := c.Containers(ctx, service)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("fail to get containers for service %s: %w", service, err)
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running containers from service %s", service)
 	}
-
 	for _, container := range containers {
 		containerLabels, err := container.Labels(ctx)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		if name == containerLabels[labels.Name] {
-			// container exists
-			return container.ID(), nil
+		if c.replicaIndex(service, containerLabels[labels.Name]) == index {
+			return container, nil
 		}
 	}
-	// container doesn't exist
-	return "", nil
+	if len(containers) == 1 && index == 1 {
+		return containers[0], nil
+	}
+	return nil, fmt.Errorf("index (%d) out of range: no such instance among the %d instances from service %s",
+		index, len(containers), service)
+}
+
+// replicaIndex returns the index of the replica of the service from its container name,
+// or -1 if the container is not a numbered replica (e.g., a `compose run` container, or a `container_name`).
+func (c *Composer) replicaIndex(service, containerName string) int {
+	prefix := serviceparser.DefaultImageName(c.project.Name, service) + serviceparser.Separator
+	s, ok := strings.CutPrefix(containerName, prefix)
+	if !ok {
+		return -1
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 1 {
+		return -1
+	}
+	return index
 }
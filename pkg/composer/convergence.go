@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// serviceImageDigest returns the digest of the local image of the service,
+// or an empty string when the image is not present.
+func (c *Composer) serviceImageDigest(ctx context.Context, ps *serviceparser.Service) (string, error) {
+	parsedReference, err := referenceutil.Parse(ps.Image)
+	if err != nil {
+		return "", err
+	}
+	img, err := c.client.ImageService().Get(ctx, parsedReference.String())
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return img.Target.Digest.String(), nil
+}
+
+// convergenceLabels returns the labels used for deciding whether the container of the service
+// has to be recreated, i.e., the hash of the service configuration and the digest of the image.
+func (c *Composer) convergenceLabels(ctx context.Context, ps *serviceparser.Service) (map[string]string, error) {
+	configHash, err := ServiceHash(*ps.Unparsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed computing service hash for %s: %w", ps.Unparsed.Name, err)
+	}
+	imageDigest, err := c.serviceImageDigest(ctx, ps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the digest of image %s: %w", ps.Image, err)
+	}
+	return map[string]string{
+		labels.ComposeConfigHash: configHash,
+		labels.ComposeImage:      imageDigest,
+	}, nil
+}
+
+// convergenceLabelFlags returns the `nerdctl run` flags for the convergence labels.
+func convergenceLabelFlags(current map[string]string) []string {
+	flags := []string{fmt.Sprintf("-l=%s=%s", labels.ComposeConfigHash, current[labels.ComposeConfigHash])}
+	if imageDigest := current[labels.ComposeImage]; imageDigest != "" {
+		flags = append(flags, fmt.Sprintf("-l=%s=%s", labels.ComposeImage, imageDigest))
+	}
+	return flags
+}
+
+// containerDiverged returns whether the existing container was created from
+// another service configuration or another image than the current ones.
+// FYI: https://github.com/docker/compose/blob/v2.14.1/pkg/compose/convergence.go#L244
+func (c *Composer) containerDiverged(ctx context.Context, cid string, current map[string]string) (bool, error) {
+	con, err := c.client.LoadContainer(ctx, cid)
+	if err != nil {
+		return false, fmt.Errorf("failed to load container %s: %w", cid, err)
+	}
+	lbls, err := con.Labels(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read labels for %s: %w", cid, err)
+	}
+	if lbls[labels.ComposeConfigHash] != current[labels.ComposeConfigHash] {
+		return true, nil
+	}
+	// containers created by older versions of nerdctl do not have the image label
+	if stored := lbls[labels.ComposeImage]; stored != "" && current[labels.ComposeImage] != "" {
+		return stored != current[labels.ComposeImage], nil
+	}
+	return false, nil
+}
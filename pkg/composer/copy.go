@@ -126,11 +126,10 @@ func (c *Composer) listContainersTargetedForCopy(ctx context.Context, index int,
 	}
 
 	if index > 0 {
-		if index > len(containers) {
-			return nil, fmt.Errorf("index (%d) out of range: only %d running instances from service %s",
-				index, len(containers), serviceName)
+		container, err := c.serviceContainer(ctx, serviceName, index)
+		if err != nil {
+			return nil, err
 		}
-		container := containers[index-1]
 		return []containerd.Container{container}, nil
 	}
 
@@ -138,10 +137,20 @@ func (c *Composer) listContainersTargetedForCopy(ctx context.Context, index int,
 		return nil, fmt.Errorf("no container found for service %q", serviceName)
 	}
 	if direction == fromService {
-		return containers[:1], err
-
+		// copy from the first replica, like `--index 1`
+		for _, container := range containers {
+			containerLabels, err := container.Labels(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if c.replicaIndex(serviceName, containerLabels[labels.Name]) == 1 {
+				return []containerd.Container{container}, nil
+			}
+		}
+		return containers[:1], nil
 	}
-	return containers, err
+	// copy to all the replicas
+	return containers, nil
 }
 
 // https://github.com/docker/compose/blob/v2.21.0/pkg/compose/cp.go#L307
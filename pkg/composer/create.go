@@ -40,8 +40,7 @@ const (
 	// RecreateForce specifies always force-recreating service containers
 	RecreateForce = "force"
 	// RecreateDiverged specifies only recreating service containers which diverges from compose model.
-	// (Unimplemented, currently equal to `RecreateNever`) In docker-compose,
-	// service config is hashed and stored in a label.
+	// As in docker-compose, the service config is hashed and stored in a label, along with the image digest.
 	// FYI: https://github.com/docker/compose/blob/v2.14.1/pkg/compose/convergence.go#L244
 	RecreateDiverged = "diverged"
 )
@@ -158,19 +157,34 @@ func (c *Composer) createService(ctx context.Context, ps *serviceparser.Service,
 // 3. it'll be easier to refactor after related `compose` logic are moved to `pkg` from `cmd`.
 func (c *Composer) createServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container, recreate string) (string, error) {
 	// check if container already exists
-	exists, err := c.containerExists(ctx, container.Name, service.Unparsed.Name)
+	existingCid, err := c.containerID(ctx, container.Name, service.Unparsed.Name)
 	if err != nil {
 		return "", fmt.Errorf("error while checking for containers with name %q: %w", container.Name, err)
 	}
 
-	// delete container if it already exists and force-recreate is enabled
-	if exists {
-		if recreate != RecreateForce {
+	current, err := c.convergenceLabels(ctx, service)
+	if err != nil {
+		return "", err
+	}
+
+	// delete container if it already exists and has to be recreated
+	if existingCid != "" {
+		switch recreate {
+		case RecreateNever:
 			log.G(ctx).Infof("Container %s exists, skipping", container.Name)
 			return "", nil
+		case RecreateDiverged:
+			diverged, err := c.containerDiverged(ctx, existingCid, current)
+			if err != nil {
+				return "", err
+			}
+			if !diverged {
+				log.G(ctx).Infof("Container %s is up-to-date, skipping", container.Name)
+				return "", nil
+			}
 		}
 
-		log.G(ctx).Debugf("Container %q already exists and force-created is enabled, deleting", container.Name)
+		log.G(ctx).Debugf("Container %q already exists and has to be recreated, deleting", container.Name)
 		delCmd := c.createNerdctlCmd(ctx, "rm", "-f", container.Name)
 		if err = delCmd.Run(); err != nil {
 			return "", fmt.Errorf("could not delete container %q: %w", container.Name, err)
@@ -187,19 +201,22 @@ func (c *Composer) createServiceContainer(ctx context.Context, service *servicep
 	defer os.RemoveAll(tempDir)
 	cidFilename := filepath.Join(tempDir, "cid")
 
-	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	currentHash, err := ServiceHash(*service.Unparsed)
+	files, err := openContainerFiles(ctx, container)
 	if err != nil {
-		return "", fmt.Errorf("failed computing service hash for %s: %w", container.Name, err)
+		return "", fmt.Errorf("failed to pass the secrets and configs of container %s: %w", container.Name, err)
 	}
-	container.RunArgs = append([]string{
+	defer files.close()
+	container.RunArgs = append(files.Flags, container.RunArgs...)
+
+	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
+	container.RunArgs = append(append([]string{
 		"--cidfile=" + cidFilename,
 		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigHash, currentHash),
-	}, container.RunArgs...)
+	}, convergenceLabelFlags(current)...), container.RunArgs...)
 
 	cmd := c.createNerdctlCmd(ctx, append([]string{"create"}, container.RunArgs...)...)
+	files.setCmd(cmd)
 	if c.DebugPrintFull {
 		log.G(ctx).Debugf("Running %v", cmd.Args)
 	}
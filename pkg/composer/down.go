@@ -19,15 +19,26 @@ package composer
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
+const (
+	// RemoveImagesLocal removes only the images that don't have a custom tag, i.e., the images built for the services without `image`
+	RemoveImagesLocal = "local"
+	// RemoveImagesAll removes all the images used by the services
+	RemoveImagesAll = "all"
+)
+
 type DownOptions struct {
 	RemoveVolumes bool
 	RemoveOrphans bool
+	RemoveImages  string // RemoveImagesLocal, RemoveImagesAll, or empty for keeping the images
 }
 
 func (c *Composer) Down(ctx context.Context, downOptions DownOptions) error {
@@ -75,6 +86,7 @@ func (c *Composer) Down(ctx context.Context, downOptions DownOptions) error {
 		}
 	}
 
+	// the volumes are removed after all the containers using them
 	if downOptions.RemoveVolumes {
 		for shortName := range c.project.Volumes {
 			if err := c.downVolume(ctx, shortName); err != nil {
@@ -83,6 +95,37 @@ func (c *Composer) Down(ctx context.Context, downOptions DownOptions) error {
 		}
 	}
 
+	if downOptions.RemoveImages != "" {
+		if err := c.downImages(ctx, downOptions.RemoveImages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Composer) downImages(ctx context.Context, mode string) error {
+	images := make(map[string]struct{})
+	for _, svc := range c.project.Services {
+		image := svc.Image
+		if image == "" {
+			image = serviceparser.DefaultImageName(c.project.Name, svc.Name)
+		} else if mode == RemoveImagesLocal {
+			continue
+		}
+		images[image] = struct{}{}
+	}
+	for _, image := range slices.Sorted(maps.Keys(images)) {
+		imageExists, err := c.ImageExists(ctx, image)
+		if err != nil {
+			return err
+		} else if imageExists {
+			log.G(ctx).Infof("Removing image %s", image)
+			if err := c.runNerdctlCmd(ctx, "rmi", image); err != nil {
+				log.G(ctx).Warn(err)
+			}
+		}
+	}
 	return nil
 }
 
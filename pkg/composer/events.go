@@ -0,0 +1,192 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// EventsOptions has args for streaming events of the project containers.
+type EventsOptions struct {
+	JSON bool
+}
+
+// Event is a lifecycle event of a project container, printed by `compose events`.
+// The JSON representation is compatible with `docker compose events --json`.
+type Event struct {
+	Time       time.Time         `json:"time"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Service    string            `json:"service"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// eventContainer is the cached metadata of a project container,
+// so that events can still be attributed after the container is deleted.
+type eventContainer struct {
+	name    string
+	service string
+	image   string
+}
+
+// Events streams the lifecycle events of the containers of `services`
+// (or of all the enabled services when `services` is empty), until the context is canceled.
+func (c *Composer) Events(ctx context.Context, writer io.Writer, eo EventsOptions, services []string) error {
+	if len(services) == 0 {
+		services = c.project.ServiceNames()
+	}
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return err
+	}
+	eventsCh, errCh := c.client.EventService().Subscribe(ctx,
+		fmt.Sprintf(`namespace==%s,topic~="^/tasks/"`, namespace),
+		fmt.Sprintf(`namespace==%s,topic~="^/containers/"`, namespace),
+	)
+
+	// Seed the cache with the existing containers, as their creation events have already been emitted
+	known := make(map[string]eventContainer)
+	containers, err := c.Containers(ctx, services...)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		info, err := container.Info(ctx)
+		if err != nil {
+			return err
+		}
+		known[container.ID()] = eventContainer{
+			name:    info.Labels[labels.Name],
+			service: info.Labels[labels.ComposeService],
+			image:   info.Image,
+		}
+	}
+
+	lookup := func(id string) (eventContainer, bool) {
+		if ec, ok := known[id]; ok {
+			return ec, true
+		}
+		container, err := c.client.LoadContainer(ctx, id)
+		if err != nil {
+			if !errdefs.IsNotFound(err) {
+				log.G(ctx).WithError(err).Debugf("failed to load container %q", id)
+			}
+			return eventContainer{}, false
+		}
+		info, err := container.Info(ctx)
+		if err != nil {
+			return eventContainer{}, false
+		}
+		if info.Labels[labels.ComposeProject] != c.project.Name || !slices.Contains(services, info.Labels[labels.ComposeService]) {
+			return eventContainer{}, false
+		}
+		ec := eventContainer{
+			name:    info.Labels[labels.Name],
+			service: info.Labels[labels.ComposeService],
+			image:   info.Image,
+		}
+		known[id] = ec
+		return ec, true
+	}
+
+	for {
+		var e *events.Envelope
+		select {
+		case e = <-eventsCh:
+		case err := <-errCh:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if e == nil || e.Event == nil {
+			continue
+		}
+		v, err := typeurl.UnmarshalAny(e.Event)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("cannot unmarshal an event from Any")
+			continue
+		}
+		id, action, attributes := eventutil.ContainerEventAction(v)
+		if id == "" {
+			continue
+		}
+		ec, ok := lookup(id)
+		if !ok {
+			continue
+		}
+		if action == "destroy" {
+			delete(known, id)
+		}
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		attributes["name"] = ec.name
+		attributes["image"] = ec.image
+		ev := Event{
+			Time:       e.Timestamp,
+			Type:       "container",
+			Action:     action,
+			ID:         id,
+			Service:    ec.service,
+			Attributes: attributes,
+		}
+		if err := printEvent(writer, ev, eo.JSON); err != nil {
+			return err
+		}
+	}
+}
+
+func printEvent(writer io.Writer, ev Event, jsonFormat bool) error {
+	if jsonFormat {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(b))
+		return err
+	}
+	keys := make([]string, 0, len(ev.Attributes))
+	for k := range ev.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, fmt.Sprintf("%s=%s", k, ev.Attributes[k]))
+	}
+	_, err := fmt.Fprintf(writer, "%s %s %s %s (%s)\n",
+		ev.Time.Format("2006-01-02 15:04:05.000000"), ev.Type, ev.Action, ev.ID, strings.Join(attrs, ", "))
+	return err
+}
@@ -0,0 +1,188 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"golang.org/x/sync/errgroup"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// healthPollInterval is the interval between two checks of the health state of containers.
+const healthPollInterval = time.Second
+
+// waitForDependencies waits for the dependencies of `ps` that have the `service_healthy` condition
+// to become healthy. A zero timeout means no timeout.
+func (c *Composer) waitForDependencies(ctx context.Context, ps *serviceparser.Service, timeout time.Duration) error {
+	for depName, dep := range ps.Unparsed.DependsOn {
+		if dep.Condition != types.ServiceConditionHealthy {
+			continue
+		}
+		if _, err := c.project.GetService(depName); err != nil {
+			if !dep.Required {
+				log.G(ctx).Warnf("service %s: optional dependency %s is not available, skipping", ps.Unparsed.Name, depName)
+				continue
+			}
+			return fmt.Errorf("service %s: dependency %s: %w", ps.Unparsed.Name, depName, err)
+		}
+		log.G(ctx).Infof("Waiting for service %s to be healthy", depName)
+		if err := c.waitServiceHealthy(ctx, depName, timeout); err != nil {
+			return fmt.Errorf("service %s: dependency failed to start: %w", ps.Unparsed.Name, err)
+		}
+	}
+	return nil
+}
+
+// waitServicesReady waits for all the containers of the services to be running, and healthy if they have a healthcheck.
+func (c *Composer) waitServicesReady(ctx context.Context, services []string, timeout time.Duration) error {
+	var eg errgroup.Group
+	for _, svc := range services {
+		eg.Go(func() error {
+			if err := c.waitServiceReady(ctx, svc, false, timeout); err != nil {
+				return err
+			}
+			log.G(ctx).Infof("Service %s is ready", svc)
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// waitServiceHealthy waits for all the containers of the service to become healthy.
+func (c *Composer) waitServiceHealthy(ctx context.Context, service string, timeout time.Duration) error {
+	return c.waitServiceReady(ctx, service, true, timeout)
+}
+
+// waitServiceReady waits for all the containers of the service to be running, and healthy if they have a healthcheck.
+// If requireHealthcheck is true, the containers must have a healthcheck.
+func (c *Composer) waitServiceReady(ctx context.Context, service string, requireHealthcheck bool, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+	for {
+		ready, err := c.serviceReady(ctx, service, requireHealthcheck)
+		if err != nil || ready {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				if requireHealthcheck {
+					return fmt.Errorf("service %s did not become healthy within %s", service, timeout)
+				}
+				return fmt.Errorf("service %s did not become ready within %s", service, timeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceReady returns whether all the containers of the service are running, and healthy if they have a healthcheck.
+// An error is returned if a container is unhealthy or has exited, or if it has no healthcheck while requireHealthcheck is true.
+func (c *Composer) serviceReady(ctx context.Context, service string, requireHealthcheck bool) (bool, error) {
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return false, err
+	}
+	if len(containers) == 0 {
+		return false, fmt.Errorf("service %s has no container", service)
+	}
+	ready := true
+	for _, container := range containers {
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			return false, fmt.Errorf("container %s of service %s is not running: %w", container.ID(), service, err)
+		}
+		st, err := task.Status(ctx)
+		if err != nil {
+			return false, err
+		}
+		switch st.Status {
+		case containerd.Stopped:
+			return false, fmt.Errorf("container %s of service %s exited with code %d", container.ID(), service, st.ExitStatus)
+		case containerd.Running:
+		default:
+			ready = false
+			continue
+		}
+
+		health, err := ContainerHealth(ctx, container)
+		if err != nil {
+			return false, err
+		}
+		if health == nil {
+			if requireHealthcheck {
+				return false, fmt.Errorf("container %s of service %s has no healthcheck", container.ID(), service)
+			}
+			continue
+		}
+		switch health.Status {
+		case healthcheck.Healthy:
+		case healthcheck.Unhealthy:
+			msg := fmt.Sprintf("container %s of service %s is unhealthy", container.ID(), service)
+			if n := len(health.Log); n > 0 {
+				msg += ": " + strings.TrimSpace(health.Log[n-1].Output)
+			}
+			return false, errors.New(msg)
+		default:
+			ready = false
+		}
+	}
+	return ready, nil
+}
+
+// ContainerHealth returns the health of a container, from its labels and its health log.
+// A nil health is returned if the container has no healthcheck, or if the healthcheck is disabled.
+func ContainerHealth(ctx context.Context, container containerd.Container) (*healthcheck.Health, error) {
+	lbls, err := container.Labels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hcJSON, ok := lbls[labels.HealthCheck]
+	if !ok || hcJSON == "" {
+		return nil, nil
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return nil, err
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == healthcheck.CmdNone {
+		return nil, nil
+	}
+	state, ok := lbls[labels.HealthState]
+	if !ok || state == "" {
+		return &healthcheck.Health{Status: healthcheck.Starting}, nil
+	}
+	return healthcheck.ReadHealthStatusForInspect(lbls[labels.StateDir], state)
+}
@@ -39,9 +39,12 @@ type LogsOptions struct {
 	Follow               bool
 	Timestamps           bool
 	Tail                 string
+	Since                string
+	Until                string
 	NoColor              bool
 	NoLogPrefix          bool
 	LatestRun            bool
+	Index                int // index of the replica of the services, 0 for all the replicas
 }
 
 func (c *Composer) Logs(ctx context.Context, lo LogsOptions, services []string) error {
@@ -61,9 +64,20 @@ func (c *Composer) Logs(ctx context.Context, lo LogsOptions, services []string)
 	if err != nil {
 		return err
 	}
-	containers, err := c.Containers(ctx, serviceNames...)
-	if err != nil {
-		return err
+	var containers []containerd.Container
+	if lo.Index > 0 {
+		for _, svc := range serviceNames {
+			container, err := c.serviceContainer(ctx, svc, lo.Index)
+			if err != nil {
+				return err
+			}
+			containers = append(containers, container)
+		}
+	} else {
+		containers, err = c.Containers(ctx, serviceNames...)
+		if err != nil {
+			return err
+		}
 	}
 	return c.logs(ctx, containers, lo)
 }
@@ -72,6 +86,7 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 	var logTagMaxLen int
 	type containerState struct {
 		name      string
+		service   string
 		logTag    string
 		logCmd    *exec.Cmd
 		startedAt string
@@ -95,6 +110,7 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 
 		containerStates[container.ID()] = containerState{
 			name:      name,
+			service:   info.Labels[labels.ComposeService],
 			logTag:    logTag,
 			startedAt: string(ts),
 		}
@@ -118,9 +134,14 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 				args = append(args, lo.Tail)
 			}
 		}
-		if lo.LatestRun {
+		if lo.Since != "" {
+			args = append(args, fmt.Sprintf("--since=%s", lo.Since))
+		} else if lo.LatestRun {
 			args = append(args, fmt.Sprintf("--since=%s", state.startedAt))
 		}
+		if lo.Until != "" {
+			args = append(args, fmt.Sprintf("--until=%s", lo.Until))
+		}
 
 		args = append(args, id)
 		state.logCmd = c.createNerdctlCmd(ctx, args...)
@@ -132,12 +153,12 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 		if lo.NoLogPrefix {
 			logWidth = -1
 		}
-		stdoutTagger := pipetagger.New(os.Stdout, stdout, state.logTag, logWidth, lo.NoColor)
+		stdoutTagger := pipetagger.New(os.Stdout, stdout, state.logTag, state.service, logWidth, lo.NoColor)
 		stderr, err := state.logCmd.StderrPipe()
 		if err != nil {
 			return err
 		}
-		stderrTagger := pipetagger.New(os.Stderr, stderr, state.logTag, logWidth, lo.NoColor)
+		stderrTagger := pipetagger.New(os.Stderr, stderr, state.logTag, state.service, logWidth, lo.NoColor)
 		if c.DebugPrintFull {
 			log.G(ctx).Debugf("Running %v", state.logCmd.Args)
 		}
@@ -38,6 +38,10 @@ func (c *Composer) getOrphanContainers(ctx context.Context, parsedServices []*se
 	for _, svc := range parsedServices {
 		parsedSvcNames[svc.Unparsed.Name] = true
 	}
+	// containers of services disabled by the active profiles are not orphans
+	for _, name := range c.project.DisabledServiceNames() {
+		parsedSvcNames[name] = true
+	}
 
 	var orphanContainers []containerd.Container
 	for _, container := range containers {
@@ -26,9 +26,11 @@ import (
 	"github.com/fatih/color"
 )
 
-func ChooseColorAttrs(tag string) []color.Attribute {
+// ChooseColorAttrs returns the color attributes for the key.
+// The same key is always given the same color.
+func ChooseColorAttrs(key string) []color.Attribute {
 	hasher := fnv.New32()
-	hasher.Write([]byte(tag))
+	hasher.Write([]byte(key))
 	tagHash := int(hasher.Sum32())
 
 	fgCandidates := []color.Attribute{
@@ -70,11 +72,12 @@ func ChooseColorAttrs(tag string) []color.Attribute {
 }
 
 // New create a PipeTagger.
+// The color of the tag is chosen from colorKey (e.g., the service name, so that the replicas of a service share the same color).
 // Set width = -1 to disable tagging.
-func New(w io.Writer, r io.Reader, tag string, width int, noColor bool) *PipeTagger {
+func New(w io.Writer, r io.Reader, tag, colorKey string, width int, noColor bool) *PipeTagger {
 	var attrs []color.Attribute
 	if !noColor {
-		attrs = ChooseColorAttrs(tag)
+		attrs = ChooseColorAttrs(colorKey)
 	}
 	return &PipeTagger{
 		w:     w,
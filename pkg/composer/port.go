@@ -18,7 +18,6 @@ package composer
 
 import (
 	"context"
-	"fmt"
 	"io"
 
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
@@ -39,18 +38,10 @@ type PortOptions struct {
 // Port gets the corresponding public port of a given private port/protocol
 // on a service container.
 func (c *Composer) Port(ctx context.Context, writer io.Writer, po PortOptions) error {
-	containers, err := c.Containers(ctx, po.ServiceName)
+	container, err := c.serviceContainer(ctx, po.ServiceName, po.Index)
 	if err != nil {
-		return fmt.Errorf("fail to get containers for service %s: %w", po.ServiceName, err)
-	}
-	if len(containers) == 0 {
-		return fmt.Errorf("no running containers from service %s", po.ServiceName)
-	}
-	if po.Index > len(containers) {
-		return fmt.Errorf("index (%d) out of range: only %d running instances from service %s",
-			po.Index, len(containers), po.ServiceName)
+		return err
 	}
-	container := containers[po.Index-1]
 	containerLabels, err := container.Labels(ctx)
 	if err != nil {
 		return err
@@ -17,32 +17,100 @@
 package composer
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 )
 
 type PullOptions struct {
 	Quiet bool
+	// IncludeDeps pulls the images of the dependencies of the services too
+	IncludeDeps bool
+	// IgnoreBuildable skips the services that have a build section
+	IgnoreBuildable bool
 }
 
+// pullParallelism is the maximum number of images pulled concurrently.
+const pullParallelism = 4
+
+// Pull pulls the images of the services concurrently.
+// When more than one image is pulled, the progress of each pull is not printed,
+// only the start and the completion of the pull of each service.
 func (c *Composer) Pull(ctx context.Context, po PullOptions, services []string) error {
-	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+	var opts []types.DependencyOption
+	if !po.IncludeDeps {
+		opts = append(opts, types.IgnoreDependencies)
+	}
+	var (
+		parsedServices []*serviceparser.Service
+		images         = make(map[string]struct{})
+	)
+	err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
 		ps, err := serviceparser.Parse(c.project, *svc)
 		if err != nil {
 			return err
 		}
-		return c.pullServiceImage(ctx, ps.Image, ps.Unparsed.Platform, ps, po)
-	})
+		if po.IgnoreBuildable && ps.Build != nil {
+			log.G(ctx).Debugf("Skipping pulling image %s of service %s, as it is buildable", ps.Image, name)
+			return nil
+		}
+		key := ps.Image + "@" + ps.Unparsed.Platform
+		if _, ok := images[key]; ok {
+			return nil
+		}
+		images[key] = struct{}{}
+		parsedServices = append(parsedServices, ps)
+		return nil
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(parsedServices) == 1 {
+		ps := parsedServices[0]
+		return c.pullServiceImage(ctx, ps.Image, ps.Unparsed.Platform, ps, po, os.Stdout, os.Stderr)
+	}
+
+	var eg errgroup.Group
+	eg.SetLimit(pullParallelism)
+	for _, ps := range parsedServices {
+		eg.Go(func() error {
+			if c.config.Progress == jobs.ProgressJSON {
+				// the JSON records of concurrent pulls can be interleaved, as they are line-oriented and carry their refs
+				if err := c.pullServiceImage(ctx, ps.Image, ps.Unparsed.Platform, ps, po, io.Discard, os.Stderr); err != nil {
+					return fmt.Errorf("service %s: %w", ps.Unparsed.Name, err)
+				}
+				return nil
+			}
+			// the progress of concurrent pulls cannot be shown on the same terminal,
+			// so the output is only shown when the pull fails.
+			var out bytes.Buffer
+			quietPo := po
+			quietPo.Quiet = true
+			if err := c.pullServiceImage(ctx, ps.Image, ps.Unparsed.Platform, ps, quietPo, io.Discard, &out); err != nil {
+				fmt.Fprint(os.Stderr, out.String())
+				return fmt.Errorf("service %s: %w", ps.Unparsed.Name, err)
+			}
+			if !po.Quiet {
+				log.G(ctx).Infof("Pulled image %s for service %s", ps.Image, ps.Unparsed.Name)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
 }
 
-func (c *Composer) pullServiceImage(ctx context.Context, image string, platform string, ps *serviceparser.Service, po PullOptions) error {
+func (c *Composer) pullServiceImage(ctx context.Context, image string, platform string, ps *serviceparser.Service, po PullOptions, stdout, stderr io.Writer) error {
 	log.G(ctx).Infof("Pulling image %s", image)
 
 	var args []string // nolint: prealloc
@@ -82,8 +150,8 @@ func (c *Composer) pullServiceImage(ctx context.Context, image string, platform
 		log.G(ctx).Debugf("Running %v", cmd.Args)
 	}
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("error while pulling image %s: %w", image, err)
 	}
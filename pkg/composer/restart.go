@@ -21,31 +21,68 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/compose-spec/compose-go/v2/types"
-
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 // RestartOptions stores all option input from `nerdctl compose restart`
 type RestartOptions struct {
 	Timeout *uint
+	NoDeps  bool
 }
 
-// Restart restarts running/stopped containers in `services`. It calls
-// `nerdctl restart CONTAINER_ID` to do the actual job.
+// Restart restarts running/stopped containers in `services` and their dependencies.
+// The containers are stopped in reverse dependency order, then started in dependency order,
+// so that a service is never running while one of its dependencies is being restarted.
+// It calls `nerdctl stop CONTAINER_ID` and `nerdctl start CONTAINER_ID` to do the actual job.
 func (c *Composer) Restart(ctx context.Context, opt RestartOptions, services []string) error {
-	// in dependency order
-	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
-		containers, err := c.Containers(ctx, svc.Name)
+	serviceNames, err := c.serviceNamesNoDeps(opt.NoDeps, services...)
+	if err != nil {
+		return err
+	}
+	// reverse dependency order
+	for _, svc := range strutil.ReverseStrSlice(serviceNames) {
+		containers, err := c.Containers(ctx, svc)
+		if err != nil {
+			return err
+		}
+		if err := c.stopContainers(ctx, containers, StopOptions{Timeout: opt.Timeout}); err != nil {
+			return err
+		}
+	}
+	// dependency order
+	for _, svc := range serviceNames {
+		containers, err := c.Containers(ctx, svc)
 		if err != nil {
 			return err
 		}
+		if err := c.startContainers(ctx, containers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		return c.restartContainers(ctx, containers, opt)
-	})
+func (c *Composer) startContainers(ctx context.Context, containers []containerd.Container) error {
+	var stWG sync.WaitGroup
+	for _, container := range containers {
+		container := container
+		stWG.Add(1)
+		go func() {
+			defer stWG.Done()
+			info, _ := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+			log.G(ctx).Infof("Starting container %s", info.Labels[labels.Name])
+			if err := c.runNerdctlCmd(ctx, "start", container.ID()); err != nil {
+				log.G(ctx).Warn(err)
+			}
+		}()
+	}
+	stWG.Wait()
+
+	return nil
 }
 
 func (c *Composer) restartContainers(ctx context.Context, containers []containerd.Container, opt RestartOptions) error {
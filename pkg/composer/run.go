@@ -57,6 +57,7 @@ type RunOptions struct {
 	Label        []string
 	WorkDir      string
 	ServicePorts bool
+	UseAliases   bool
 	Publish      []string
 }
 
@@ -174,6 +175,20 @@ func (c *Composer) Run(ctx context.Context, ro RunOptions) error {
 		}
 	}
 
+	// `compose run` command does not attach the network aliases of the service unless --use-aliases is specified.
+	if !ro.UseAliases && targetSvc.Networks != nil {
+		networks := make(map[string]*types.ServiceNetworkConfig, len(targetSvc.Networks))
+		for name, value := range targetSvc.Networks {
+			if value != nil {
+				copied := *value
+				copied.Aliases = nil
+				value = &copied
+			}
+			networks[name] = value
+		}
+		targetSvc.Networks = networks
+	}
+
 	// `compose run` command overrides the command defined in the service configuration.
 	if len(ro.Args) != 0 {
 		targetSvc.Command = make([]string, len(ro.Args))
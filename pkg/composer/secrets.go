@@ -0,0 +1,101 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// containerFiles are the secrets and configs of a container that cannot be bind-mounted from their source.
+// The content of the secrets and configs is passed to `nerdctl create --secret` through pipes, so it is never
+// written to the disk. The secrets and configs copied from a file are read again from the file instead.
+type containerFiles struct {
+	// Flags are the `--secret` flags
+	Flags   []string
+	readers []*os.File
+	wg      sync.WaitGroup
+}
+
+// openContainerFiles returns the `--secret` flags for the secrets and configs of a container.
+// The pipes must be passed to the nerdctl command with setCmd, and closed with close after the command exits.
+func openContainerFiles(ctx context.Context, container serviceparser.Container) (*containerFiles, error) {
+	res := &containerFiles{}
+	for _, f := range container.Files {
+		opts := []string{"source=" + f.Source, "target=" + f.Target, fmt.Sprintf("mode=%o", f.Mode)}
+		if f.File != "" {
+			opts = append(opts, "file="+f.File)
+		} else {
+			r, w, err := os.Pipe()
+			if err != nil {
+				res.close()
+				return nil, err
+			}
+			// the file descriptors of cmd.ExtraFiles start from 3
+			opts = append(opts, "fd="+strconv.Itoa(3+len(res.readers)))
+			res.readers = append(res.readers, r)
+			log.G(ctx).Debugf("Passing the content of %q through a pipe", f.Target)
+			res.wg.Add(1)
+			go func(content []byte) {
+				defer res.wg.Done()
+				// the error is reported by nerdctl, which reads the pipe
+				_, _ = w.Write(content)
+				w.Close()
+			}(f.Content)
+		}
+		if f.UID != -1 {
+			opts = append(opts, "uid="+strconv.Itoa(f.UID))
+		}
+		if f.GID != -1 {
+			opts = append(opts, "gid="+strconv.Itoa(f.GID))
+		}
+		// the options are parsed as a CSV record, like `--mount`
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if err := w.Write(opts); err != nil {
+			res.close()
+			return nil, err
+		}
+		w.Flush()
+		res.Flags = append(res.Flags, "--secret="+strings.TrimSuffix(b.String(), "\n"))
+	}
+	return res, nil
+}
+
+// setCmd passes the pipes to cmd.
+func (f *containerFiles) setCmd(cmd *exec.Cmd) {
+	cmd.ExtraFiles = f.readers
+}
+
+// close closes the read ends of the pipes, so that the writers are not blocked when the command exits without reading them,
+// and waits for the writers.
+func (f *containerFiles) close() {
+	for _, r := range f.readers {
+		r.Close()
+	}
+	f.wg.Wait()
+}
@@ -34,7 +34,8 @@ import (
 
 func parseBuildConfig(c *types.BuildConfig, project *types.Project, imageName string) (*Build, error) {
 	if unknown := reflectutil.UnknownNonEmptyFields(c,
-		"Context", "Dockerfile", "Args", "CacheFrom", "Target", "Labels", "Secrets", "DockerfileInline", "AdditionalContexts",
+		"Context", "Dockerfile", "Args", "CacheFrom", "CacheTo", "Target", "Labels", "Secrets", "DockerfileInline", "AdditionalContexts",
+		"Platforms",
 	); len(unknown) > 0 {
 		log.L.Warnf("Ignoring: build: %+v", unknown)
 	}
@@ -76,10 +77,31 @@ func parseBuildConfig(c *types.BuildConfig, project *types.Project, imageName st
 		b.BuildArgs = append(b.BuildArgs, "--cache-from="+s)
 	}
 
+	for _, s := range c.CacheTo {
+		b.BuildArgs = append(b.BuildArgs, "--cache-to="+s)
+	}
+
 	for k, v := range c.AdditionalContexts {
+		if svcName, ok := strings.CutPrefix(v, types.ServicePrefix); ok {
+			// the image of another service, which has to be built first
+			svc, err := project.GetService(svcName)
+			if err != nil {
+				return nil, fmt.Errorf("build: additional context %s: %w", k, err)
+			}
+			image := svc.Image
+			if image == "" {
+				image = DefaultImageName(project.Name, svcName)
+			}
+			v = "docker-image://" + image
+			b.DependsOn = append(b.DependsOn, svcName)
+		}
 		b.BuildArgs = append(b.BuildArgs, "--build-context="+k+"="+v)
 	}
 
+	if len(c.Platforms) > 0 {
+		b.Platforms = c.Platforms
+	}
+
 	if c.Target != "" {
 		b.BuildArgs = append(b.BuildArgs, "--target="+c.Target)
 	}
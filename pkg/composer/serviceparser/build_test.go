@@ -116,3 +116,59 @@ secrets:
 		return strings.TrimSpace(baz.Build.DockerfileInline) == "FROM random"
 	}())
 }
+
+func TestParseBuildBake(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("test is not compatible with windows")
+	}
+
+	const dockerComposeYAML = `
+services:
+  base:
+    build: ./basectx
+  foo:
+    image: fooimg
+    build:
+      context: ./fooctx
+      cache_from:
+        - type=local,src=/tmp/cache
+      cache_to:
+        - type=local,dest=/tmp/cache
+      platforms:
+        - linux/amd64
+        - linux/arm64
+      additional_contexts:
+        base: service:base
+        assets: ./assets
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	assert.Assert(t, in(foo.Build.BuildArgs, "--cache-from=type=local,src=/tmp/cache"))
+	assert.Assert(t, in(foo.Build.BuildArgs, "--cache-to=type=local,dest=/tmp/cache"))
+	assert.Assert(t, in(foo.Build.BuildArgs, "--build-context=base=docker-image://"+DefaultImageName(project.Name, "base")))
+	assert.Assert(t, in(foo.Build.BuildArgs, "--build-context=assets="+project.RelativePath("assets")))
+	assert.DeepEqual(t, []string{"linux/amd64", "linux/arm64"}, foo.Build.Platforms)
+	assert.DeepEqual(t, []string{"base"}, foo.Build.DependsOn)
+
+	baseSvc, err := project.GetService("base")
+	assert.NilError(t, err)
+
+	base, err := Parse(project, baseSvc)
+	assert.NilError(t, err)
+
+	assert.Equal(t, 0, len(base.Build.Platforms))
+	assert.Equal(t, 0, len(base.Build.DependsOn))
+}
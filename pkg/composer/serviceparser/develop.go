@@ -0,0 +1,81 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package serviceparser
+
+import (
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+)
+
+// Develop is the parsed `develop` section of a service.
+type Develop struct {
+	// Watch contains the valid `develop.watch` rules, with absolute paths
+	Watch []types.Trigger
+}
+
+// parseDevelop parses the `develop` section of a service.
+// Invalid or unsupported watch rules are ignored with a warning,
+// so that they do not prevent the service from being used outside `compose watch`.
+func parseDevelop(project *types.Project, svc types.ServiceConfig) *Develop {
+	c := svc.Develop
+	if unknown := reflectutil.UnknownNonEmptyFields(c, "Watch"); len(unknown) > 0 {
+		log.L.Warnf("Ignoring: service %s: develop: %+v", svc.Name, unknown)
+	}
+
+	d := &Develop{}
+	for _, t := range c.Watch {
+		if unknown := reflectutil.UnknownNonEmptyFields(&t,
+			"Path", "Action", "Target", "Exec", "Include", "Ignore", "InitialSync",
+		); len(unknown) > 0 {
+			log.L.Warnf("Ignoring: service %s: develop.watch: %+v", svc.Name, unknown)
+		}
+		switch t.Action {
+		case types.WatchActionSync, types.WatchActionSyncRestart, types.WatchActionSyncExec:
+			if t.Target == "" {
+				log.L.Warnf("Ignoring: service %s: develop.watch: target is required for action %q", svc.Name, t.Action)
+				continue
+			}
+			if t.Action == types.WatchActionSyncExec && len(t.Exec.Command) == 0 {
+				log.L.Warnf("Ignoring: service %s: develop.watch: exec.command is required for action %q", svc.Name, t.Action)
+				continue
+			}
+		case types.WatchActionRebuild:
+			if svc.Build == nil {
+				log.L.Warnf("Ignoring: service %s: develop.watch: action %q requires a build section", svc.Name, t.Action)
+				continue
+			}
+		case types.WatchActionRestart:
+		default:
+			log.L.Warnf("Ignoring: service %s: develop.watch: unsupported action %q", svc.Name, t.Action)
+			continue
+		}
+		if t.Path == "" {
+			log.L.Warnf("Ignoring: service %s: develop.watch: path is required", svc.Name)
+			continue
+		}
+		if !filepath.IsAbs(t.Path) {
+			t.Path = filepath.Join(project.WorkingDir, t.Path)
+		}
+		d.Watch = append(d.Watch, t)
+	}
+	return d
+}
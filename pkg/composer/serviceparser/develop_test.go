@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package serviceparser
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestParseDevelop(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("test is not compatible with windows")
+	}
+
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: alpine:3.14
+    develop:
+      watch:
+        - path: ./src
+          action: sync
+          target: /app/src
+          ignore:
+            - node_modules/
+          initial_sync: true
+        - path: ./config
+          action: sync+exec
+          target: /etc/app
+          exec:
+            command: ["kill", "-HUP", "1"]
+        - path: ./package.json
+          action: rebuild
+        - path: ./Makefile
+          action: restart
+          target: /app/Makefile
+  bar:
+    image: alpine:3.14
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo.Develop: %+v", foo.Develop)
+	// "rebuild" without a build section is ignored
+	assert.Equal(t, 3, len(foo.Develop.Watch))
+
+	sync := foo.Develop.Watch[0]
+	assert.Equal(t, types.WatchActionSync, sync.Action)
+	assert.Equal(t, project.RelativePath("src"), sync.Path)
+	assert.Equal(t, "/app/src", sync.Target)
+	assert.DeepEqual(t, []string{"node_modules/"}, sync.Ignore)
+	assert.Equal(t, true, sync.InitialSync)
+
+	syncExec := foo.Develop.Watch[1]
+	assert.Equal(t, types.WatchActionSyncExec, syncExec.Action)
+	assert.DeepEqual(t, types.ShellCommand{"kill", "-HUP", "1"}, syncExec.Exec.Command)
+
+	restart := foo.Develop.Watch[2]
+	assert.Equal(t, types.WatchActionRestart, restart.Action)
+	assert.Equal(t, project.RelativePath("Makefile"), restart.Path)
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+
+	bar, err := Parse(project, barSvc)
+	assert.NilError(t, err)
+	assert.Assert(t, bar.Develop == nil)
+}
@@ -35,6 +35,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 // ComposeExtensionKey defines fields used to implement extension features.
@@ -69,6 +70,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"ContainerName",
 		"DependsOn",
 		"Deploy",
+		"Develop",
 		"Devices",
 		"Dockerfile", // handled by the loader (normalizer)
 		"DNS",
@@ -79,12 +81,14 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"Extends", // handled by the loader
 		"Extensions",
 		"ExtraHosts",
+		"HealthCheck",
 		"Hostname",
 		"Image",
 		"Init",
 		"Labels",
 		"Logging",
 		"MemLimit",
+		"MemReservation",
 		"Networks",
 		"NetworkMode",
 		"Pid",
@@ -129,7 +133,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: %+v", svc.Name, depName, unknown)
 		}
 		switch dep.Condition {
-		case "", types.ServiceConditionStarted:
+		case "", types.ServiceConditionStarted, types.ServiceConditionHealthy:
 			// NOP
 		default:
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: condition %s", svc.Name, depName, dep.Condition)
@@ -161,12 +165,14 @@ func warnUnknownFields(svc types.ServiceConfig) {
 			if unknown := reflectutil.UnknownNonEmptyFields(svc.Deploy.Resources.Limits,
 				"NanoCPUs",
 				"MemoryBytes",
+				"Pids",
 			); len(unknown) > 0 {
 				log.L.Warnf("Ignoring: service %s: deploy.resources.resources: %+v", svc.Name, unknown)
 			}
 		}
 		if svc.Deploy.Resources.Reservations != nil {
 			if unknown := reflectutil.UnknownNonEmptyFields(svc.Deploy.Resources.Reservations,
+				"MemoryBytes",
 				"Devices",
 			); len(unknown) > 0 {
 				log.L.Warnf("Ignoring: service %s: deploy.resources.resources.reservations: %+v", svc.Name, unknown)
@@ -192,12 +198,28 @@ type Container struct {
 	Name    string   // e.g., "compose-wordpress_wordpress_1"
 	RunArgs []string // {"--pull=never", ...}
 	Mkdir   []string // For Bind.CreateHostPath
+	Files   []File   // For secrets and configs that cannot be bind-mounted from their source file
+}
+
+// File is a secret or a config that cannot be bind-mounted from its source file.
+// It is passed to `nerdctl create --secret`, which writes it to the state directory of the container,
+// and bind-mounts it read-only into the container.
+type File struct {
+	Source  string // e.g., "foo"
+	Target  string // e.g., "/run/secrets/foo"
+	Content []byte
+	File    string      // the source file on the host, empty if not sourced from a file
+	UID     int         // -1 if not specified
+	GID     int         // -1 if not specified
+	Mode    os.FileMode // 0444 if not specified
 }
 
 type Build struct {
 	Force            bool     // force build even if already present
 	BuildArgs        []string // {"-t", "example.com/foo", "--target", "foo", "/path/to/ctx"}
 	DockerfileInline string   // store contents of dockerfile_inline field is specified
+	Platforms        []string // build.platforms, overrides the platform of the service
+	DependsOn        []string // services whose image is used as an additional context (`service:<name>`)
 	// TODO: call BuildKit API directly without executing `nerdctl build`
 }
 
@@ -206,6 +228,7 @@ type Service struct {
 	PullMode   string
 	Containers []Container // length = replicas
 	Build      *Build
+	Develop    *Develop // nil if the service has no develop section
 	Unparsed   *types.ServiceConfig
 }
 
@@ -259,7 +282,39 @@ func getMemLimit(svc types.ServiceConfig) (types.UnitBytes, error) {
 	return limit, nil
 }
 
-func getGPUs(svc types.ServiceConfig) (reqs []string, _ error) {
+func getMemReservation(svc types.ServiceConfig) types.UnitBytes {
+	var reservation types.UnitBytes
+	if svc.MemReservation > 0 {
+		log.L.Warn("mem_reservation is deprecated, use deploy.resources.reservations.memory")
+		reservation = svc.MemReservation
+	}
+	if svc.Deploy != nil && svc.Deploy.Resources.Reservations != nil {
+		if memoryBytes := svc.Deploy.Resources.Reservations.MemoryBytes; memoryBytes > 0 {
+			if svc.MemReservation > 0 && memoryBytes != svc.MemReservation {
+				log.L.Warnf("deploy.resources.reservations.memory and mem_reservation (deprecated) must not be set together, ignoring mem_reservation=%d", svc.MemReservation)
+			}
+			reservation = memoryBytes
+		}
+	}
+	return reservation
+}
+
+func getPidsLimit(svc types.ServiceConfig) int64 {
+	limit := svc.PidsLimit
+	if svc.Deploy != nil && svc.Deploy.Resources.Limits != nil {
+		if pids := svc.Deploy.Resources.Limits.Pids; pids > 0 {
+			if svc.PidsLimit > 0 && pids != svc.PidsLimit {
+				log.L.Warnf("deploy.resources.limits.pids and pids_limit must not be set together, ignoring pids_limit=%d", svc.PidsLimit)
+			}
+			limit = pids
+		}
+	}
+	return limit
+}
+
+// getGPUs returns `nerdctl run --gpus` flag strings, and the CDI device names (for `nerdctl run --device`)
+// of the devices with the "cdi" driver.
+func getGPUs(svc types.ServiceConfig) (reqs, cdiDevices []string, _ error) {
 	// "gpu" and "nvidia" are also allowed capabilities (but not used as nvidia driver capabilities)
 	// https://github.com/moby/moby/blob/v20.10.7/daemon/nvidia_linux.go#L37
 	capset := map[string]struct{}{"gpu": {}, "nvidia": {}}
@@ -271,7 +326,17 @@ func getGPUs(svc types.ServiceConfig) (reqs []string, _ error) {
 			if len(dev.Capabilities) == 0 {
 				// "capabilities" is required.
 				// https://github.com/compose-spec/compose-spec/blob/74b933db994109616580eab8f47bf2ba226e0faa/deploy.md#devices
-				return nil, fmt.Errorf("service %s: specifying \"capabilities\" is required for resource reservations", svc.Name)
+				return nil, nil, fmt.Errorf("service %s: specifying \"capabilities\" is required for resource reservations", svc.Name)
+			}
+
+			if dev.Driver == "cdi" {
+				// the device IDs are fully qualified CDI device names, e.g., "nvidia.com/gpu=all"
+				// https://github.com/cncf-tags/container-device-interface
+				if len(dev.IDs) == 0 {
+					return nil, nil, fmt.Errorf("service %s: \"device_ids\" is required for the \"cdi\" driver", svc.Name)
+				}
+				cdiDevices = append(cdiDevices, dev.IDs...)
+				continue
 			}
 
 			var requiresGPU bool
@@ -301,7 +366,7 @@ func getGPUs(svc types.ServiceConfig) (reqs []string, _ error) {
 			buf := new(bytes.Buffer)
 			w := csv.NewWriter(buf)
 			if err := w.Write(e); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			w.Flush()
 			o := buf.Bytes()
@@ -310,7 +375,7 @@ func getGPUs(svc types.ServiceConfig) (reqs []string, _ error) {
 			}
 		}
 	}
-	return reqs, nil
+	return reqs, cdiDevices, nil
 }
 
 var restartFailurePat = regexp.MustCompile(`^on-failure:\d+$`)
@@ -439,6 +504,10 @@ func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
 		}
 	}
 
+	if svc.Develop != nil {
+		parsed.Develop = parseDevelop(project, svc)
+	}
+
 	switch svc.PullPolicy {
 	case "", types.PullPolicyMissing, types.PullPolicyIfNotPresent:
 		// NOP
@@ -556,12 +625,19 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.RunArgs = append(c.RunArgs, fmt.Sprintf("-m=%d", memLimit))
 	}
 
-	if gpuReqs, err := getGPUs(svc); err != nil {
+	if memReservation := getMemReservation(svc); memReservation > 0 {
+		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--memory-reservation=%d", memReservation))
+	}
+
+	if gpuReqs, cdiDevices, err := getGPUs(svc); err != nil {
 		return nil, err
-	} else if len(gpuReqs) > 0 {
+	} else {
 		for _, gpus := range gpuReqs {
 			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--gpus=%s", gpus))
 		}
+		for _, dev := range cdiDevices {
+			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--device=%s", dev))
+		}
 	}
 
 	for k, v := range svc.Labels {
@@ -588,6 +664,7 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		return nil, err
 	}
 	netTypeContainer := false
+	var networkAliases []string
 	for _, net := range networks {
 		if strings.HasPrefix(net.fullName, "container:") {
 			netTypeContainer = true
@@ -600,8 +677,15 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 			if value != nil && value.MacAddress != "" {
 				c.RunArgs = append(c.RunArgs, "--mac-address="+value.MacAddress)
 			}
+			if value != nil {
+				networkAliases = append(networkAliases, value.Aliases...)
+			}
 		}
 	}
+	// nerdctl does not support per-network aliases, so the aliases apply to all the networks of the container
+	for _, alias := range strutil.DedupeStrSlice(networkAliases) {
+		c.RunArgs = append(c.RunArgs, "--network-alias="+alias)
+	}
 
 	if netTypeContainer && svc.Hostname != "" {
 		return nil, fmt.Errorf("conflicting options: hostname and container network mode")
@@ -618,8 +702,8 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.RunArgs = append(c.RunArgs, "--pid="+svc.Pid)
 	}
 
-	if svc.PidsLimit > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--pids-limit=%d", svc.PidsLimit))
+	if pidsLimit := getPidsLimit(svc); pidsLimit > 0 {
+		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--pids-limit=%d", pidsLimit))
 	}
 
 	if svc.Ulimits != nil {
@@ -694,6 +778,14 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--group-add=%s", v))
 	}
 
+	if svc.HealthCheck != nil {
+		flags, err := healthCheckToFlags(svc.HealthCheck)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: healthcheck: %w", svc.Name, err)
+		}
+		c.RunArgs = append(c.RunArgs, flags...)
+	}
+
 	for _, v := range svc.Volumes {
 		vStr, mkdir, err := serviceVolumeConfigToFlagV(v, project)
 		if err != nil {
@@ -712,20 +804,28 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 
 	for _, config := range svc.Configs {
 		fileRef := types.FileReferenceConfig(config)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, false)
+		vStr, file, err := fileReferenceConfigToFlagV(fileRef, project, false)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		if file != nil {
+			c.Files = append(c.Files, *file)
+		} else {
+			c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		}
 	}
 
 	for _, secret := range svc.Secrets {
 		fileRef := types.FileReferenceConfig(secret)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, true)
+		vStr, file, err := fileReferenceConfigToFlagV(fileRef, project, true)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		if file != nil {
+			c.Files = append(c.Files, *file)
+		} else {
+			c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		}
 	}
 
 	for _, tmpfs := range svc.Tmpfs {
@@ -795,8 +895,7 @@ func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Proj
 		}
 	}
 	if c.Volume != nil {
-		// c.Volume is expected to be a non-nil reference to an empty Volume struct
-		if unknown := reflectutil.UnknownNonEmptyFields(c.Volume); len(unknown) > 0 {
+		if unknown := reflectutil.UnknownNonEmptyFields(c.Volume, "NoCopy"); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: volume: Volume: %+v", unknown)
 		}
 	}
@@ -850,6 +949,9 @@ func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Proj
 		}
 		// c.Source is like "db_data", vol.Name is like "compose-wordpress_db_data"
 		src = vol.Name
+		if c.Volume != nil && c.Volume.NoCopy {
+			opts = append(opts, "nocopy")
+		}
 	case "bind":
 		src = project.RelativePath(c.Source)
 		var err error
@@ -878,7 +980,10 @@ func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Proj
 	return s, mkdir, nil
 }
 
-func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Project, secret bool) (string, error) {
+// fileReferenceConfigToFlagV returns the `-v` flag value for bind-mounting a file-sourced secret or config.
+// Secrets and configs sourced from an environment variable or an inline content, or with a uid, a gid or a mode,
+// are returned as a File to be written to a tmpfs-backed file by the composer instead.
+func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Project, secret bool) (string, *File, error) {
 	objType := "config"
 	if secret {
 		objType = "secret"
@@ -890,29 +995,23 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 	}
 
 	if err := identifiers.ValidateDockerCompat(c.Source); err != nil {
-		return "", fmt.Errorf("invalid source name for %s: %w", objType, err)
+		return "", nil, fmt.Errorf("invalid source name for %s: %w", objType, err)
 	}
 
 	var obj types.FileObjectConfig
 	if secret {
 		secret, ok := project.Secrets[c.Source]
 		if !ok {
-			return "", fmt.Errorf("secret %s is undefined", c.Source)
+			return "", nil, fmt.Errorf("secret %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(secret)
 	} else {
 		config, ok := project.Configs[c.Source]
 		if !ok {
-			return "", fmt.Errorf("config %s is undefined", c.Source)
+			return "", nil, fmt.Errorf("config %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(config)
 	}
-	src := project.RelativePath(obj.File)
-	var err error
-	src, err = filepath.Abs(src)
-	if err != nil {
-		return "", fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, src, err)
-	}
 
 	target := c.Target
 	if target == "" {
@@ -927,24 +1026,118 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 			if secret {
 				target = filepath.Join("/run/secrets", target)
 			} else {
-				return "", fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
+				return "", nil, fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
 			}
 		}
 	}
 
+	file := File{
+		Source: c.Source,
+		Target: target,
+		UID:    -1,
+		GID:    -1,
+		Mode:   0o444,
+	}
 	if c.UID != "" {
-		// Raise an error rather than ignoring the value, for avoiding any security issue
-		return "", fmt.Errorf("%s %s: unsupported field: UID", objType, c.Source)
+		uid, err := strconv.Atoi(c.UID)
+		if err != nil || uid < 0 {
+			return "", nil, fmt.Errorf("%s %s: invalid uid %q", objType, c.Source, c.UID)
+		}
+		file.UID = uid
 	}
 	if c.GID != "" {
-		return "", fmt.Errorf("%s %s: unsupported field: GID", objType, c.Source)
+		gid, err := strconv.Atoi(c.GID)
+		if err != nil || gid < 0 {
+			return "", nil, fmt.Errorf("%s %s: invalid gid %q", objType, c.Source, c.GID)
+		}
+		file.GID = gid
 	}
 	if c.Mode != nil {
-		return "", fmt.Errorf("%s %s: unsupported field: Mode", objType, c.Source)
+		file.Mode = os.FileMode(*c.Mode).Perm()
+	}
+
+	switch {
+	case obj.Environment != "":
+		v, ok := project.Environment[obj.Environment]
+		if !ok {
+			return "", nil, fmt.Errorf("%s %s: environment variable %q is not set", objType, c.Source, obj.Environment)
+		}
+		file.Content = []byte(v)
+		return "", &file, nil
+	case obj.Content != "":
+		file.Content = []byte(obj.Content)
+		return "", &file, nil
+	case obj.File == "":
+		return "", nil, fmt.Errorf("%s %s: one of file, environment or content is required", objType, c.Source)
+	}
+
+	src := project.RelativePath(obj.File)
+	var err error
+	src, err = filepath.Abs(src)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, src, err)
+	}
+
+	if c.UID != "" || c.GID != "" || c.Mode != nil {
+		// The ownership and the mode of the source file cannot be changed, so its content is copied instead
+		file.Content, err = os.ReadFile(src)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s %s: %w", objType, c.Source, err)
+		}
+		file.File = src
+		return "", &file, nil
 	}
 
 	s := fmt.Sprintf("%s:%s:ro", src, target)
-	return s, nil
+	return s, nil, nil
+}
+
+func healthCheckToFlags(hc *types.HealthCheckConfig) ([]string, error) {
+	if unknown := reflectutil.UnknownNonEmptyFields(hc,
+		"Test", "Timeout", "Interval", "Retries", "StartPeriod", "Disable",
+	); len(unknown) > 0 {
+		log.L.Warnf("Ignoring: healthcheck: %+v", unknown)
+	}
+	if hc.Disable || (len(hc.Test) > 0 && hc.Test[0] == "NONE") {
+		return []string{"--no-healthcheck"}, nil
+	}
+	var flags []string
+	if len(hc.Test) > 0 {
+		var cmd string
+		switch hc.Test[0] {
+		case "CMD-SHELL":
+			if len(hc.Test) != 2 {
+				return nil, fmt.Errorf("invalid test %v: CMD-SHELL requires exactly one argument", hc.Test)
+			}
+			cmd = hc.Test[1]
+		case "CMD":
+			if len(hc.Test) < 2 {
+				return nil, fmt.Errorf("invalid test %v: CMD requires at least one argument", hc.Test)
+			}
+			// `--health-cmd` is run with a shell, so the arguments are quoted
+			quoted := make([]string, len(hc.Test)-1)
+			for i, arg := range hc.Test[1:] {
+				quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+			}
+			cmd = strings.Join(quoted, " ")
+		default:
+			return nil, fmt.Errorf("invalid test %v: must start with NONE, CMD or CMD-SHELL", hc.Test)
+		}
+		flags = append(flags, "--health-cmd="+cmd)
+	}
+	if hc.Interval != nil {
+		flags = append(flags, fmt.Sprintf("--health-interval=%s", time.Duration(*hc.Interval)))
+	}
+	if hc.Timeout != nil {
+		flags = append(flags, fmt.Sprintf("--health-timeout=%s", time.Duration(*hc.Timeout)))
+	}
+	if hc.Retries != nil {
+		flags = append(flags, fmt.Sprintf("--health-retries=%d", *hc.Retries))
+	}
+	if hc.StartPeriod != nil {
+		flags = append(flags, fmt.Sprintf("--health-start-period=%s", time.Duration(*hc.StartPeriod)))
+	}
+	return flags, nil
 }
 
 // DefaultImageName returns the image name following compose naming logic.
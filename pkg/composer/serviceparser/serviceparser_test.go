@@ -18,9 +18,11 @@ package serviceparser
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/compose-spec/compose-go/v2/types"
@@ -214,6 +216,8 @@ services:
     cpus: 0.42
     # mem_limit is deprecated in favor of deploy.resources.limits.memory, but still valid
     mem_limit: 42m
+    # mem_reservation is deprecated in favor of deploy.resources.reservations.memory, but still valid
+    mem_reservation: 21m
 `
 	comp := testutil.NewComposeDir(t, dockerComposeYAML)
 	defer comp.CleanUp()
@@ -234,9 +238,58 @@ services:
 		assert.Assert(t, in(c.RunArgs, "--name="+c.Name))
 		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("--cpus=%f", 0.42)))
 		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Assert(t, in(c.RunArgs, "--memory-reservation=22020096"))
 	}
 }
 
+func TestParseDeployCDIDevices(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    deploy:
+      resources:
+        reservations:
+          devices:
+          - capabilities: ["gpu"]
+            driver: cdi
+            device_ids: ["nvidia.com/gpu=all"]
+  bar:
+    image: nginx:alpine
+    deploy:
+      resources:
+        reservations:
+          devices:
+          - capabilities: ["gpu"]
+            driver: cdi
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	c := foo.Containers[0]
+	assert.Assert(t, in(c.RunArgs, "--device=nvidia.com/gpu=all"))
+	for _, a := range c.RunArgs {
+		assert.Assert(t, !strings.HasPrefix(a, "--gpus"))
+	}
+
+	// "device_ids" is required for the "cdi" driver
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	_, err = Parse(project, barSvc)
+	assert.ErrorContains(t, err, "device_ids")
+}
+
 func TestParseDeploy(t *testing.T) {
 	t.Parallel()
 	const dockerComposeYAML = `
@@ -249,6 +302,9 @@ services:
         limits:
           cpus: "0.42"
           memory: "42m"
+          pids: 100
+        reservations:
+          memory: "21m"
   bar: # restart=always
     image: nginx:alpine
     deploy:
@@ -297,6 +353,8 @@ services:
 		assert.Assert(t, in(c.RunArgs, "--restart=no"))
 		assert.Assert(t, in(c.RunArgs, "--cpus=0.42"))
 		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Assert(t, in(c.RunArgs, "--memory-reservation=22020096"))
+		assert.Assert(t, in(c.RunArgs, "--pids-limit=100"))
 	}
 
 	barSvc, err := project.GetService("bar")
@@ -420,6 +478,13 @@ services:
       read_only: true
       bind:
         propagation: rshared
+    - type: volume
+      source: data
+      target: /tgt/data
+      volume:
+        nocopy: true
+volumes:
+  data:
 `
 	comp := testutil.NewComposeDir(t, dockerComposeYAML)
 	defer comp.CleanUp()
@@ -436,6 +501,7 @@ services:
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
 		assert.Assert(t, in(c.RunArgs, "-v=/src/dir1:/tgt/dir1:rshared,ro"))
+		assert.Assert(t, in(c.RunArgs, "-v="+project.Name+"_data:/tgt/data:nocopy"))
 	}
 }
 
@@ -519,6 +585,50 @@ services:
 
 }
 
+func TestParseNetworkAliases(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    networks:
+      net1:
+        aliases:
+          - web
+          - www
+      net2:
+        aliases:
+          - web
+networks:
+  net1:
+  net2:
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	for _, c := range foo.Containers {
+		assert.Assert(t, in(c.RunArgs, "--network-alias=web"))
+		assert.Assert(t, in(c.RunArgs, "--network-alias=www"))
+		count := 0
+		for _, arg := range c.RunArgs {
+			if arg == "--network-alias=web" {
+				count++
+			}
+		}
+		assert.Equal(t, count, 1)
+	}
+}
+
 func TestParseConfigs(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "windows" {
@@ -578,6 +688,116 @@ configs:
 	}
 }
 
+func TestParseConfigsWithFiles(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("test is not compatible with windows")
+	}
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    secrets:
+    - secret1
+    - source: secret2
+      uid: "1000"
+      gid: "1001"
+      mode: 0400
+    configs:
+    - config1
+secrets:
+  secret1:
+    environment: SECRET1
+  secret2:
+    file: ./secret2
+configs:
+  config1:
+    content: content-config1
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), map[string]string{"SECRET1": "content-secret1"})
+	assert.NilError(t, err)
+
+	err = filesystem.WriteFile(filepath.Join(project.WorkingDir, "secret2"), []byte("content-secret2"), 0444)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	for _, c := range foo.Containers {
+		assert.DeepEqual(t, c.Files, []File{
+			{Source: "config1", Target: "/config1", Content: []byte("content-config1"), UID: -1, GID: -1, Mode: 0o444},
+			{Source: "secret1", Target: "/run/secrets/secret1", Content: []byte("content-secret1"), UID: -1, GID: -1, Mode: 0o444},
+			{Source: "secret2", Target: "/run/secrets/secret2", Content: []byte("content-secret2"), File: filepath.Join(project.WorkingDir, "secret2"), UID: 1000, GID: 1001, Mode: 0o400},
+		})
+		for _, a := range c.RunArgs {
+			assert.Assert(t, !strings.HasPrefix(a, "-v="), "unexpected bind mount: %s", a)
+		}
+	}
+}
+
+func TestParseHealthCheck(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  shell:
+    image: nginx:alpine
+    healthcheck:
+      test: ["CMD-SHELL", "curl -f http://localhost || exit 1"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
+      start_period: 30s
+  exec:
+    image: nginx:alpine
+    healthcheck:
+      test: ["CMD", "echo", "it's ok"]
+  disabled:
+    image: nginx:alpine
+    healthcheck:
+      disable: true
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	shellSvc, err := project.GetService("shell")
+	assert.NilError(t, err)
+	shell, err := Parse(project, shellSvc)
+	assert.NilError(t, err)
+	for _, c := range shell.Containers {
+		assert.Assert(t, in(c.RunArgs, "--health-cmd=curl -f http://localhost || exit 1"))
+		assert.Assert(t, in(c.RunArgs, "--health-interval=10s"))
+		assert.Assert(t, in(c.RunArgs, "--health-timeout=5s"))
+		assert.Assert(t, in(c.RunArgs, "--health-retries=5"))
+		assert.Assert(t, in(c.RunArgs, "--health-start-period=30s"))
+	}
+
+	execSvc, err := project.GetService("exec")
+	assert.NilError(t, err)
+	exec, err := Parse(project, execSvc)
+	assert.NilError(t, err)
+	for _, c := range exec.Containers {
+		assert.Assert(t, in(c.RunArgs, `--health-cmd='echo' 'it'\''s ok'`))
+	}
+
+	disabledSvc, err := project.GetService("disabled")
+	assert.NilError(t, err)
+	disabled, err := Parse(project, disabledSvc)
+	assert.NilError(t, err)
+	for _, c := range disabled.Containers {
+		assert.Assert(t, in(c.RunArgs, "--no-healthcheck"))
+	}
+}
+
 func TestParseRestartPolicy(t *testing.T) {
 	t.Parallel()
 	const dockerComposeYAML = `
@@ -623,3 +843,77 @@ services:
 	c = getContainersFromService("unless_stopped")[0]
 	assert.Assert(t, in(c.RunArgs, "--restart=unless-stopped"))
 }
+
+func TestParseExtends(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("test is not compatible with windows")
+	}
+
+	const dockerComposeYAML = `
+services:
+  base:
+    image: alpine:3.14
+    command: sleep infinity
+    environment:
+      FOO: foo
+      BAR: bar
+    ports:
+      - 8080:80
+  foo:
+    extends: base
+    command: sleep 42
+    environment:
+      BAR: baz
+    ports:
+      - 8443:443
+  bar:
+    extends:
+      file: common/common.yaml
+      service: common
+    environment:
+      BAR: bar
+`
+	const commonYAML = `
+services:
+  common:
+    image: alpine:3.15
+    environment:
+      FOO: common
+    volumes:
+      - ./data:/data
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	assert.NilError(t, os.Mkdir(filepath.Join(comp.Dir(), "common"), 0o755))
+	comp.WriteFile("common/common.yaml", commonYAML)
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	// same-file: scalars are overridden, mappings are merged, and sequences are appended
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+	t.Logf("foo.RunArgs: %+v", foo.Containers[0].RunArgs)
+	assert.Equal(t, "alpine:3.14", foo.Image)
+	assert.Assert(t, in(foo.Containers[0].RunArgs, "-e=FOO=foo"))
+	assert.Assert(t, in(foo.Containers[0].RunArgs, "-e=BAR=baz"))
+	assert.Assert(t, !in(foo.Containers[0].RunArgs, "-e=BAR=bar"))
+	assert.Assert(t, in(foo.Containers[0].RunArgs, "-p=8080:80/tcp"))
+	assert.Assert(t, in(foo.Containers[0].RunArgs, "-p=8443:443/tcp"))
+	assert.Equal(t, "42", lastOf(foo.Containers[0].RunArgs))
+
+	// cross-file: relative paths are resolved against the directory of the extended file
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	bar, err := Parse(project, barSvc)
+	assert.NilError(t, err)
+	t.Logf("bar.RunArgs: %+v", bar.Containers[0].RunArgs)
+	assert.Equal(t, "alpine:3.15", bar.Image)
+	assert.Assert(t, in(bar.Containers[0].RunArgs, "-e=FOO=common"))
+	assert.Assert(t, in(bar.Containers[0].RunArgs, "-e=BAR=bar"))
+	assert.Assert(t, in(bar.Containers[0].RunArgs, "-v="+project.RelativePath("common/data")+":/data"))
+}
@@ -32,12 +32,13 @@ import (
 // StopOptions stores all option input from `nerdctl compose stop`
 type StopOptions struct {
 	Timeout *uint
+	NoDeps  bool
 }
 
-// Stop stops containers in `services` without removing them. It calls
+// Stop stops containers in `services` and their dependencies without removing them. It calls
 // `nerdctl stop CONTAINER_ID` to do the actual job.
 func (c *Composer) Stop(ctx context.Context, opt StopOptions, services []string) error {
-	serviceNames, err := c.ServiceNames(services...)
+	serviceNames, err := c.serviceNamesNoDeps(opt.NoDeps, services...)
 	if err != nil {
 		return err
 	}
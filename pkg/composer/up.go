@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 
@@ -43,6 +44,14 @@ type UpOptions struct {
 	NoRecreate           bool
 	Scale                map[string]int // map of service name to replicas
 	Pull                 string
+	DependencyTimeout    time.Duration // timeout for waiting for dependencies to become healthy, 0 for no timeout
+	Wait                 bool          // wait for services to be running (and healthy if they have a healthcheck), requires Detach
+	WaitTimeout          time.Duration // timeout for Wait, 0 for no timeout
+	NoDeps               bool          // do not start the dependencies of the specified services
+	AttachDependencies   bool          // attach to the logs of the dependencies of the specified services too
+
+	// attachServices is the list of the services to attach to, all the started services when empty
+	attachServices []string
 }
 
 func (opts UpOptions) recreateStrategy() string {
@@ -99,10 +108,17 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 		parsedServices = append(parsedServices, ps)
 		return nil
 	}
-	err := c.project.ForEachService(services, forEachFn)
+	var dependencyOpts []types.DependencyOption
+	if uo.NoDeps {
+		dependencyOpts = append(dependencyOpts, types.IgnoreDependencies)
+	}
+	err := c.project.ForEachService(services, forEachFn, dependencyOpts...)
 	if err != nil {
 		return err
 	}
+	if !uo.AttachDependencies {
+		uo.attachServices = services
+	}
 
 	// remove orphan containers before the service has be started
 	// FYI: https://github.com/docker/compose/blob/v2.3.4/pkg/compose/create.go#L91-L112
@@ -124,10 +140,14 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 }
 
 func validateFileObjectConfig(obj types.FileObjectConfig, shortName, objType string, project *types.Project) error {
-	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File"); len(unknown) > 0 {
+	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File", "Environment", "Content"); len(unknown) > 0 {
 		log.L.Warnf("Ignoring: %s %s: %+v", objType, shortName, unknown)
 	}
 
+	if obj.Environment != "" || obj.Content != "" {
+		// passed to `nerdctl create --secret` when the container is created
+		return nil
+	}
 	if obj.File == "" {
 		return fmt.Errorf("%s %q: lacks file path", objType, shortName)
 	}
@@ -19,6 +19,8 @@ package composer
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/containerd/log"
 
@@ -32,11 +34,23 @@ func (c *Composer) upNetwork(ctx context.Context, shortName string) error {
 		return fmt.Errorf("invalid network name %q", shortName)
 	}
 	if net.External {
-		// NOP
+		// external networks are not created, but they have to exist.
+		// net.Name is the `name` of the network if specified, otherwise shortName.
+		switch net.Name {
+		case "host", "none":
+			return nil
+		}
+		netExists, err := c.NetworkExists(net.Name)
+		if err != nil {
+			return err
+		}
+		if !netExists {
+			return fmt.Errorf("network %s declared as external, but could not be found", net.Name)
+		}
 		return nil
 	}
 
-	if unknown := reflectutil.UnknownNonEmptyFields(&net, "Name", "Ipam", "Driver", "DriverOpts"); len(unknown) > 0 {
+	if unknown := reflectutil.UnknownNonEmptyFields(&net, "Name", "Ipam", "Driver", "DriverOpts", "Internal", "Labels", "EnableIPv6"); len(unknown) > 0 {
 		log.G(ctx).Warnf("Ignoring: network %s: %+v", shortName, unknown)
 	}
 
@@ -57,10 +71,22 @@ func (c *Composer) upNetwork(ctx context.Context, shortName string) error {
 			createArgs = append(createArgs, fmt.Sprintf("--driver=%s", net.Driver))
 		}
 
-		if net.DriverOpts != nil {
-			for k, v := range net.DriverOpts {
-				createArgs = append(createArgs, fmt.Sprintf("--opt=%s=%s", k, v))
-			}
+		// driver options such as `com.docker.network.driver.mtu` (or `mtu`) and
+		// `com.docker.network.bridge.enable_ip_masquerade` (or `ip-masq`) are interpreted by netutil
+		for _, k := range slices.Sorted(maps.Keys(net.DriverOpts)) {
+			createArgs = append(createArgs, fmt.Sprintf("--opt=%s=%s", k, net.DriverOpts[k]))
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(net.Labels)) {
+			createArgs = append(createArgs, fmt.Sprintf("--label=%s=%s", k, net.Labels[k]))
+		}
+
+		if net.Internal {
+			createArgs = append(createArgs, "--internal")
+		}
+
+		if net.EnableIPv6 != nil && *net.EnableIPv6 {
+			createArgs = append(createArgs, "--ipv6")
 		}
 
 		if net.Ipam.Config != nil {
@@ -23,16 +23,20 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
+	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 func (c *Composer) upServices(ctx context.Context, parsedServices []*serviceparser.Service, uo UpOptions) error {
@@ -42,7 +46,11 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 
 	// TODO: parallelize loop for ensuring images (make sure not to mess up tty)
 	for _, ps := range parsedServices {
-		if err := c.ensureServiceImage(ctx, ps, !uo.NoBuild, uo.ForceBuild, BuildOptions{}, uo.QuietPull, uo.Pull); err != nil {
+		imageCtx, span := tracingutil.StartSpan(ctx, "nerdctl.compose.service.image",
+			attribute.String("compose.service", ps.Unparsed.Name), attribute.String("image.ref", ps.Image))
+		err := c.ensureServiceImage(imageCtx, ps, !uo.NoBuild, uo.ForceBuild, BuildOptions{}, uo.QuietPull, uo.Pull)
+		tracingutil.EndSpan(span, err)
+		if err != nil {
 			return err
 		}
 	}
@@ -56,12 +64,23 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	)
 	for _, ps := range parsedServices {
 		ps := ps
+		if err := c.removeExcessReplicas(ctx, ps); err != nil {
+			return err
+		}
+		if !uo.NoDeps {
+			if err := c.waitForDependencies(ctx, ps, uo.DependencyTimeout); err != nil {
+				return err
+			}
+		}
 		var runEG errgroup.Group
 		services = append(services, ps.Unparsed.Name)
 		for _, container := range ps.Containers {
 			container := container
 			runEG.Go(func() error {
-				id, err := c.upServiceContainer(ctx, ps, container, recreate)
+				upCtx, span := tracingutil.StartSpan(ctx, "nerdctl.compose.service.up",
+					attribute.String("compose.service", ps.Unparsed.Name), attribute.String("container.name", container.Name))
+				id, err := c.upServiceContainer(upCtx, ps, container, recreate)
+				tracingutil.EndSpan(span, err)
 				if err != nil {
 					return err
 				}
@@ -76,6 +95,10 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 		}
 	}
 
+	if uo.Wait {
+		return c.waitServicesReady(ctx, services, uo.WaitTimeout)
+	}
+
 	if uo.Detach {
 		return nil
 	}
@@ -93,7 +116,11 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 		NoLogPrefix:          uo.NoLogPrefix,
 		LatestRun:            recreate == RecreateNever,
 	}
-	if err := c.Logs(ctx, lo, services); err != nil {
+	attach := services
+	if len(uo.attachServices) > 0 {
+		attach = uo.attachServices
+	}
+	if err := c.Logs(ctx, lo, attach); err != nil {
 		return err
 	}
 
@@ -102,6 +129,31 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	return nil
 }
 
+// removeExcessReplicas removes the replicas of a service whose number exceeds its current number of replicas,
+// e.g., when running `compose up --scale svc=1` after `compose up --scale svc=3`.
+// Containers that are not numbered replicas (`compose run` containers, `container_name`) are kept.
+func (c *Composer) removeExcessReplicas(ctx context.Context, ps *serviceparser.Service) error {
+	containers, err := c.Containers(ctx, ps.Unparsed.Name)
+	if err != nil {
+		return err
+	}
+	var excess []containerd.Container
+	for _, container := range containers {
+		containerLabels, err := container.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		if c.replicaIndex(ps.Unparsed.Name, containerLabels[labels.Name]) > len(ps.Containers) {
+			excess = append(excess, container)
+		}
+	}
+	if len(excess) == 0 {
+		return nil
+	}
+	log.G(ctx).Infof("Scaling down service %s to %d replica(s)", ps.Unparsed.Name, len(ps.Containers))
+	return c.removeContainers(ctx, excess, RemoveOptions{Stop: true})
+}
+
 func (c *Composer) ensureServiceImage(ctx context.Context, ps *serviceparser.Service, allowBuild, forceBuild bool, bo BuildOptions, quiet bool, pullModeArg string) error {
 	if ps.Build != nil && allowBuild {
 		if ps.Build.Force || forceBuild {
@@ -153,23 +205,20 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 		return existingCid, nil
 	}
 
+	current, err := c.convergenceLabels(ctx, service)
+	if err != nil {
+		return "", err
+	}
+
 	// delete container if it already exists
 	if existingCid != "" {
-		// Default behavior for RecreateDiverged: compare stored hash with current service hash
+		// Default behavior for RecreateDiverged: compare stored hash and image with current ones
 		if recreate == RecreateDiverged {
-			currentHash, err := ServiceHash(*service.Unparsed)
+			diverged, err := c.containerDiverged(ctx, existingCid, current)
 			if err != nil {
-				return "", fmt.Errorf("failed computing service hash for %s: %w", container.Name, err)
+				return "", err
 			}
-			con, err := c.client.LoadContainer(ctx, existingCid)
-			if err != nil {
-				return "", fmt.Errorf("failed to load container %s: %w", existingCid, err)
-			}
-			lbls, err := con.Labels(ctx)
-			if err != nil {
-				return "", fmt.Errorf("failed to read labels for %s: %w", existingCid, err)
-			}
-			if lbls[labels.ComposeConfigHash] == currentHash {
+			if !diverged {
 				cmd := c.createNerdctlCmd(ctx, append([]string{"start"}, existingCid)...)
 				if err := c.executeUpCmd(ctx, cmd, container.Name, runFlagD, service.Unparsed.StdinOpen); err != nil {
 					return "", fmt.Errorf("error while starting existing container %s: %w", container.Name, err)
@@ -201,23 +250,29 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 	defer os.RemoveAll(tempDir)
 	cidFilename := filepath.Join(tempDir, "cid")
 
+	files, err := openContainerFiles(ctx, container)
+	if err != nil {
+		return "", fmt.Errorf("failed to pass the secrets and configs of container %s: %w", container.Name, err)
+	}
+	defer files.close()
+	container.RunArgs = append(files.Flags, container.RunArgs...)
+
 	if c.EnvFile != "" {
 		container.RunArgs = append([]string{"--env-file=" + c.EnvFile}, container.RunArgs...)
 	}
 
 	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	currentHash, err := ServiceHash(*service.Unparsed)
-	if err != nil {
-		return "", fmt.Errorf("failed computing service hash for %s: %w", container.Name, err)
-	}
-	container.RunArgs = append([]string{
+	container.RunArgs = append(append([]string{
 		"--cidfile=" + cidFilename,
 		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
 		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeConfigHash, currentHash),
-	}, container.RunArgs...)
+	}, convergenceLabelFlags(current)...), container.RunArgs...)
+	if dependsOn := composeDependsOnLabel(service); dependsOn != "" {
+		container.RunArgs = append([]string{fmt.Sprintf("-l=%s=%s", labels.ComposeDependsOn, dependsOn)}, container.RunArgs...)
+	}
 
 	cmd := c.createNerdctlCmd(ctx, append([]string{"run"}, container.RunArgs...)...)
+	files.setCmd(cmd)
 	if c.DebugPrintFull {
 		log.G(ctx).Debugf("Running %v", cmd.Args)
 	}
@@ -233,6 +288,17 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 	return strings.TrimSpace(string(cid)), nil
 }
 
+// composeDependsOnLabel returns the value of labels.ComposeDependsOn for the service,
+// in the same format as Docker Compose ("<SERVICE>:<CONDITION>:<RESTART>,...").
+func composeDependsOnLabel(service *serviceparser.Service) string {
+	var deps []string
+	for name, dep := range service.Unparsed.DependsOn {
+		deps = append(deps, fmt.Sprintf("%s:%s:%t", name, dep.Condition, dep.Restart))
+	}
+	sort.Strings(deps)
+	return strings.Join(deps, ",")
+}
+
 func (c *Composer) executeUpCmd(ctx context.Context, cmd *exec.Cmd, containerName string, runFlagD, stdinOpen bool) error {
 	log.G(ctx).Infof("Running %v", cmd.Args)
 	if c.DebugPrintFull {
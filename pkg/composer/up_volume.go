@@ -19,6 +19,8 @@ package composer
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/containerd/log"
 
@@ -32,11 +34,19 @@ func (c *Composer) upVolume(ctx context.Context, shortName string) error {
 		return fmt.Errorf("invalid volume name %q", shortName)
 	}
 	if vol.External {
-		// NOP
+		// external volumes are not created, but they have to exist.
+		// vol.Name is the `name` of the volume if specified, otherwise shortName.
+		volExists, err := c.VolumeExists(vol.Name)
+		if err != nil {
+			return err
+		}
+		if !volExists {
+			return fmt.Errorf("volume %s declared as external, but could not be found", vol.Name)
+		}
 		return nil
 	}
 
-	if unknown := reflectutil.UnknownNonEmptyFields(&vol, "Name"); len(unknown) > 0 {
+	if unknown := reflectutil.UnknownNonEmptyFields(&vol, "Name", "Driver", "DriverOpts", "Labels"); len(unknown) > 0 {
 		log.G(ctx).Warnf("Ignoring: volume %s: %+v", shortName, unknown)
 	}
 
@@ -54,8 +64,20 @@ func (c *Composer) upVolume(ctx context.Context, shortName string) error {
 		createArgs := []string{
 			fmt.Sprintf("--label=%s=%s", labels.ComposeProject, c.project.Name),
 			fmt.Sprintf("--label=%s=%s", labels.ComposeVolume, shortName),
-			fullName,
 		}
+		for _, k := range slices.Sorted(maps.Keys(vol.Labels)) {
+			createArgs = append(createArgs, fmt.Sprintf("--label=%s=%s", k, vol.Labels[k]))
+		}
+
+		if vol.Driver != "" {
+			createArgs = append(createArgs, fmt.Sprintf("--driver=%s", vol.Driver))
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(vol.DriverOpts)) {
+			createArgs = append(createArgs, fmt.Sprintf("--opt=%s=%s", k, vol.DriverOpts[k]))
+		}
+
+		createArgs = append(createArgs, fullName)
 		if err := c.runNerdctlCmd(ctx, append([]string{"volume", "create"}, createArgs...)...); err != nil {
 			return err
 		}
@@ -0,0 +1,346 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// watchDebounce is the delay to wait for more changes before applying them, so that a burst
+// of events (e.g. a `git checkout`) does not trigger many syncs or rebuilds.
+const watchDebounce = 500 * time.Millisecond
+
+// WatchOptions stores all option input from `nerdctl compose watch`
+type WatchOptions struct {
+	NoUp bool
+}
+
+type watchTrigger struct {
+	service string
+	types.Trigger
+}
+
+// Watch watches the paths of the `develop.watch` section of `services`, and syncs, restarts or rebuilds the services on changes.
+// The services are started first, unless NoUp is set.
+func (c *Composer) Watch(ctx context.Context, wo WatchOptions, services []string) error {
+	var triggers []watchTrigger
+	err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+		ps, err := serviceparser.Parse(c.project, *svc)
+		if err != nil {
+			return err
+		}
+		if ps.Develop == nil {
+			return nil
+		}
+		for _, t := range ps.Develop.Watch {
+			triggers = append(triggers, watchTrigger{service: svc.Name, Trigger: t})
+		}
+		return nil
+	}, types.IgnoreDependencies)
+	if err != nil {
+		return err
+	}
+	if len(triggers) == 0 {
+		return errors.New("none of the selected services is configured for watch, consider setting a 'develop' section")
+	}
+
+	if !wo.NoUp {
+		if err := c.Up(ctx, UpOptions{Detach: true}, services); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range triggers {
+		if t.InitialSync && t.Action != types.WatchActionRebuild && t.Action != types.WatchActionRestart {
+			c.applyWatchChanges(ctx, []watchTrigger{t}, map[string]struct{}{t.Path: {}})
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+	for _, t := range triggers {
+		if err := addWatchRecursive(watcher, t.Path, t.Ignore); err != nil {
+			return fmt.Errorf("service %s: failed to watch %q: %w", t.service, t.Path, err)
+		}
+	}
+
+	interruptChan := make(chan os.Signal, 1)
+	signal.Notify(interruptChan, os.Interrupt)
+	defer signal.Stop(interruptChan)
+
+	log.G(ctx).Info("Watching for file changes (press Ctrl-C to stop)")
+	var (
+		pending = make(map[string]struct{})
+		timer   = time.NewTimer(watchDebounce)
+	)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig := <-interruptChan:
+			log.G(ctx).Debugf("Received signal: %s", sig)
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.G(ctx).WithError(err).Warn("file watcher error")
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if st, err := os.Lstat(event.Name); err == nil && st.IsDir() {
+					if err := addWatchRecursive(watcher, event.Name, nil); err != nil {
+						log.G(ctx).WithError(err).Warnf("failed to watch %q", event.Name)
+					}
+				}
+			}
+			pending[event.Name] = struct{}{}
+			timer.Reset(watchDebounce)
+		case <-timer.C:
+			changed := pending
+			pending = make(map[string]struct{})
+			c.applyWatchChanges(ctx, triggers, changed)
+		}
+	}
+}
+
+// applyWatchChanges runs the actions of the triggers matching the changed paths.
+// A rebuild supersedes the syncs and restarts of the same service.
+func (c *Composer) applyWatchChanges(ctx context.Context, triggers []watchTrigger, changed map[string]struct{}) {
+	type serviceChanges struct {
+		rebuild bool
+		restart bool
+		syncs   map[string]string // host path -> container path
+		execs   []types.ServiceHook
+	}
+	var (
+		order   []string
+		changes = make(map[string]*serviceChanges)
+	)
+	for p := range changed {
+		for _, t := range triggers {
+			rel, ok := watchRelPath(t.Trigger, p)
+			if !ok {
+				continue
+			}
+			sc, ok := changes[t.service]
+			if !ok {
+				sc = &serviceChanges{syncs: make(map[string]string)}
+				changes[t.service] = sc
+				order = append(order, t.service)
+			}
+			switch t.Action {
+			case types.WatchActionRebuild:
+				sc.rebuild = true
+			case types.WatchActionRestart:
+				sc.restart = true
+			case types.WatchActionSync, types.WatchActionSyncRestart, types.WatchActionSyncExec:
+				sc.syncs[p] = path.Join(t.Target, filepath.ToSlash(rel))
+				switch t.Action {
+				case types.WatchActionSyncRestart:
+					sc.restart = true
+				case types.WatchActionSyncExec:
+					if !slices.ContainsFunc(sc.execs, func(h types.ServiceHook) bool { return slices.Equal(h.Command, t.Exec.Command) }) {
+						sc.execs = append(sc.execs, t.Exec)
+					}
+				}
+			}
+		}
+	}
+
+	for _, svc := range order {
+		sc := changes[svc]
+		if sc.rebuild {
+			log.G(ctx).Infof("Rebuilding service %q after changes", svc)
+			if err := c.rebuildService(ctx, svc); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to rebuild service %q", svc)
+			}
+			continue
+		}
+		containers, err := c.Containers(ctx, svc)
+		if err != nil {
+			log.G(ctx).WithError(err).Errorf("failed to list the containers of service %q", svc)
+			continue
+		}
+		for src, dst := range sc.syncs {
+			for _, container := range containers {
+				if err := c.syncWatchPath(ctx, container.ID(), src, dst); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to sync %q to service %q", src, svc)
+				}
+			}
+		}
+		if len(sc.syncs) > 0 {
+			log.G(ctx).Infof("Synced %d path(s) to service %q", len(sc.syncs), svc)
+		}
+		for _, hook := range sc.execs {
+			for _, container := range containers {
+				if err := c.execWatchHook(ctx, container.ID(), hook); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to run %v in service %q", hook.Command, svc)
+				}
+			}
+		}
+		if sc.restart {
+			if err := c.restartContainers(ctx, containers, RestartOptions{}); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to restart service %q", svc)
+			}
+		}
+	}
+}
+
+// execWatchHook runs the `exec` command of a sync+exec watch rule in the container.
+func (c *Composer) execWatchHook(ctx context.Context, containerID string, hook types.ServiceHook) error {
+	args := []string{"exec"}
+	if hook.User != "" {
+		args = append(args, "--user="+hook.User)
+	}
+	if hook.Privileged {
+		args = append(args, "--privileged")
+	}
+	if hook.WorkingDir != "" {
+		args = append(args, "--workdir="+hook.WorkingDir)
+	}
+	for _, k := range slices.Sorted(maps.Keys(hook.Environment)) {
+		if v := hook.Environment[k]; v != nil {
+			args = append(args, "--env="+k+"="+*v)
+		} else {
+			args = append(args, "--env="+k)
+		}
+	}
+	args = append(append(args, containerID), hook.Command...)
+	return c.runNerdctlCmd(ctx, args...)
+}
+
+// syncWatchPath copies the host path `src` to `dst` in the container, or removes `dst` when `src` does not exist anymore.
+func (c *Composer) syncWatchPath(ctx context.Context, containerID, src, dst string) error {
+	st, err := os.Lstat(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return c.runNerdctlCmd(ctx, "exec", containerID, "rm", "-rf", dst)
+	} else if err != nil {
+		return err
+	}
+	if st.IsDir() {
+		// Copy the content of the directory, not the directory itself
+		src += string(filepath.Separator) + "."
+	}
+	return c.runNerdctlCmd(ctx, "cp", src, containerID+":"+dst)
+}
+
+// rebuildService rebuilds the image of a service and recreates its containers, without touching its dependencies.
+func (c *Composer) rebuildService(ctx context.Context, service string) error {
+	var parsedServices []*serviceparser.Service
+	err := c.project.ForEachService([]string{service}, func(name string, svc *types.ServiceConfig) error {
+		ps, err := serviceparser.Parse(c.project, *svc)
+		if err != nil {
+			return err
+		}
+		parsedServices = append(parsedServices, ps)
+		return nil
+	}, types.IgnoreDependencies)
+	if err != nil {
+		return err
+	}
+	return c.upServices(ctx, parsedServices, UpOptions{Detach: true, ForceBuild: true, ForceRecreate: true})
+}
+
+// watchRelPath returns the path of p relative to the path of t, and whether p is watched by t
+// (i.e. p is under the path of t, matches the include patterns and does not match the ignore patterns).
+func watchRelPath(t types.Trigger, p string) (string, bool) {
+	rel, err := filepath.Rel(t.Path, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if rel == "." {
+		// The trigger path itself is a file
+		rel = ""
+	}
+	slashRel := filepath.ToSlash(rel)
+	if len(t.Include) > 0 && !matchWatchPatterns(t.Include, slashRel) {
+		return "", false
+	}
+	if matchWatchPatterns(t.Ignore, slashRel) {
+		return "", false
+	}
+	return rel, true
+}
+
+// matchWatchPatterns returns true if rel, or one of its parent directories, matches one of the patterns.
+func matchWatchPatterns(patterns []string, rel string) bool {
+	if rel == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		for p := rel; p != "."; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, path.Base(p)); ok && !strings.Contains(pattern, "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addWatchRecursive watches root and its subdirectories, except the ones matching the ignore patterns (e.g. `node_modules/`).
+func addWatchRecursive(watcher *fsnotify.Watcher, root string, ignore []string) error {
+	st, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !st.IsDir() {
+		return watcher.Add(root)
+	}
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel, err := filepath.Rel(root, p); err == nil && matchWatchPatterns(ignore, filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+			return watcher.Add(p)
+		}
+		return nil
+	})
+}
@@ -37,6 +37,7 @@ type Config struct {
 	CgroupManager    string   `toml:"cgroup_manager"`
 	InsecureRegistry bool     `toml:"insecure_registry"`
 	HostsDir         []string `toml:"hosts_dir"`
+	NoTokenCache     bool     `toml:"no_token_cache"`
 	Experimental     bool     `toml:"experimental"`
 	HostGatewayIP    string   `toml:"host_gateway_ip"`
 	BridgeIP         string   `toml:"bridge_ip, omitempty"`
@@ -47,6 +48,89 @@ type Config struct {
 	DNSOpts          []string `toml:"dns_opts,omitempty"`
 	DNSSearch        []string `toml:"dns_search,omitempty"`
 	DisableHCSystemd bool     `toml:"disable_hc_systemd"`
+	Scanner          string   `toml:"scanner,omitempty"`         // Scanner is the vulnerability scanner used by `nerdctl image scan` and `--verify=scan` (trivy|grype).
+	ScanSeverity     string   `toml:"scan_severity,omitempty"`   // ScanSeverity is the lowest severity that fails `--verify=scan`.
+	SeccompProfile   string   `toml:"seccomp_profile,omitempty"` // SeccompProfile is the default seccomp profile (a file path, `builtin`, or `builtin:<VARIANT>`).
+	OTelEndpoint     string   `toml:"otel_endpoint,omitempty"`   // OTelEndpoint is the OTLP/HTTP endpoint that the traces are exported to.
+	Progress         string   `toml:"progress,omitempty"`        // Progress is the mode of the progress output of pull, push, build, save, load, and compose (`auto`, `plain`, `json`, or `quiet`).
+	DefaultNetwork   string   `toml:"default_network,omitempty"` // DefaultNetwork is the network of `nerdctl run` and `nerdctl create` when `--network` is not specified.
+	// SnapshotterFallback falls back to the default snapshotter, when the remote snapshotter is not available.
+	SnapshotterFallback bool `toml:"snapshotter_fallback"`
+	// DefaultCapabilities replace the default capabilities of the containers (capability names or presets such as `minimal`).
+	DefaultCapabilities []string `toml:"default_capabilities,omitempty"`
+	// Logging is the default logging configuration of the containers created by `nerdctl run` and `nerdctl create`.
+	Logging LoggingConfig `toml:"logging,omitempty"`
+	// Verify is the default image verification of `nerdctl run` and `nerdctl create`.
+	Verify VerifyConfig `toml:"verify,omitempty"`
+	// Runtimes are the named runtime configurations selectable with `--runtime <NAME>`.
+	Runtimes map[string]RuntimeConfig `toml:"runtimes,omitempty"`
+	// Quotas are the disk quotas of the namespaces, keyed by the namespace names.
+	Quotas map[string]QuotaConfig `toml:"quotas,omitempty"`
+	// Registries are the TLS client and credential configurations of the registries, keyed by their hosts (e.g., `registry.example.com:5000`).
+	Registries map[string]RegistryConfig `toml:"registries,omitempty"`
+	// Namespaces are the per-namespace overrides of the settings above, keyed by the namespace names.
+	Namespaces map[string]NamespaceConfig `toml:"namespaces,omitempty"`
+}
+
+// LoggingConfig corresponds to the [logging] table of nerdctl.toml .
+type LoggingConfig struct {
+	// Driver is the default logging driver, instead of json-file.
+	Driver string `toml:"driver,omitempty"`
+	// Opts are the default logging options, applied to the containers using Driver.
+	Opts map[string]string `toml:"opts,omitempty"`
+}
+
+// VerifyConfig corresponds to the [verify] table of nerdctl.toml .
+type VerifyConfig struct {
+	// Provider is the default provider of `--verify` (none|cosign|notation|scan).
+	Provider string `toml:"provider,omitempty"`
+	// CosignKey is the default public key of `--verify=cosign`.
+	CosignKey string `toml:"cosign_key,omitempty"`
+}
+
+// RuntimeConfig corresponds to a [runtimes.<NAME>] table of nerdctl.toml .
+type RuntimeConfig struct {
+	// Type is the containerd runtime type, such as `io.containerd.kata.v2`. Defaults to `io.containerd.runc.v2`.
+	Type string `toml:"type,omitempty"`
+	// Path is the runc-compatible binary for the runc runtime types, or the absolute path of the shim binary for the others.
+	Path string `toml:"path,omitempty"`
+	// Options are the runtime options, in the same format as the runtime options of the CRI plugin of containerd.
+	Options map[string]any `toml:"options,omitempty"`
+	// Annotations are the OCI annotations of the containers created with the runtime.
+	Annotations map[string]string `toml:"annotations,omitempty"`
+}
+
+// QuotaConfig corresponds to a [quotas.<NAMESPACE>] table of nerdctl.toml .
+type QuotaConfig struct {
+	// Disk is the soft limit of the disk usage of the namespace, such as `20GiB`.
+	Disk string `toml:"disk,omitempty"`
+	// Enforce makes creating containers fail when the namespace exceeds Disk, instead of printing a warning.
+	Enforce bool `toml:"enforce,omitempty"`
+}
+
+// RegistryConfig corresponds to a [registries."<HOST>"] table of nerdctl.toml .
+type RegistryConfig struct {
+	// ClientCert is the PEM file of the TLS client certificate presented to the registry.
+	ClientCert string `toml:"client_cert,omitempty"`
+	// ClientKey is the PEM file of the private key of ClientCert. Defaults to ClientCert, which then has to contain the key.
+	ClientKey string `toml:"client_key,omitempty"`
+	// CredentialProvider is the built-in credential provider of the registry ("ecr", "gcr", or "acr"),
+	// used instead of the credentials stored by `nerdctl login`.
+	CredentialProvider string `toml:"credential_provider,omitempty"`
+}
+
+// NamespaceConfig corresponds to a [namespaces."<NAMESPACE>"] table of nerdctl.toml .
+// The non-empty fields override the top-level ones when the namespace is in use,
+// unless the corresponding CLI flags or env vars are specified.
+type NamespaceConfig struct {
+	// Snapshotter overrides the top-level snapshotter.
+	Snapshotter string `toml:"snapshotter,omitempty"`
+	// DefaultNetwork overrides the top-level default_network.
+	DefaultNetwork string `toml:"default_network,omitempty"`
+	// HostsDir overrides the top-level hosts_dir, e.g., to use different registry mirrors.
+	HostsDir []string `toml:"hosts_dir,omitempty"`
+	// Logging overrides the top-level [logging] table as a whole.
+	Logging LoggingConfig `toml:"logging,omitempty"`
 }
 
 // New creates a default Config object statically,
@@ -64,6 +148,7 @@ func New() *Config {
 		CgroupManager:    ncdefaults.CgroupManager(),
 		InsecureRegistry: false,
 		HostsDir:         ncdefaults.HostsDirs(),
+		NoTokenCache:     false,
 		Experimental:     true,
 		HostGatewayIP:    ncdefaults.HostGatewayIP(),
 		KubeHideDupe:     false,
@@ -73,5 +158,9 @@ func New() *Config {
 		DNSOpts:          []string{},
 		DNSSearch:        []string{},
 		DisableHCSystemd: false,
+		Scanner:          "",
+		ScanSeverity:     "CRITICAL",
+		SeccompProfile:   "",
+		Logging:          LoggingConfig{},
 	}
 }
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package consoleutil
+
+import (
+	"bytes"
+	"io"
+)
+
+// clearScreen clears the screen and moves the cursor to the top-left corner.
+const clearScreen = "\033[2J\033[H"
+
+// Redraw replaces the screen with frame, for full-screen live views such as `nerdctl ps --watch`.
+// When raw is true (i.e., the terminal is in raw mode), "\n" is written as "\r\n".
+func Redraw(w io.Writer, frame []byte, raw bool) error {
+	if raw {
+		frame = bytes.ReplaceAll(frame, []byte("\n"), []byte("\r\n"))
+	}
+	_, err := w.Write(append([]byte(clearScreen), frame...))
+	return err
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -44,6 +45,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook"
 	"github.com/containerd/nerdctl/v2/pkg/resolvconf"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
@@ -169,6 +171,8 @@ func NewNetworkingOptionsManager(globalOptions types.GlobalCommandOptions, netOp
 		// We'll handle Namespace networking identically to Host-mode networking, but
 		// put the container in the specified network namespace instead of the root.
 		manager = &hostNetworkManager{globalOptions, netOpts, client}
+	case nettype.Pasta:
+		manager = &pastaNetworkManager{hostNetworkManager{globalOptions, netOpts, client}}
 	default:
 		return nil, fmt.Errorf("unexpected container networking type: %q", netType)
 	}
@@ -372,9 +376,10 @@ func (m *containerNetworkManager) VerifyNetworkOptions(_ context.Context) error
 		"--hostname":   m.netOpts.Hostname,
 		"--domainname": m.netOpts.Domainname,
 		// NOTE: an empty slice still counts as a non-zero value so we check its length:
-		"-p/--publish": len(m.netOpts.PortMappings) != 0,
-		"--dns":        len(m.netOpts.DNSServers) != 0,
-		"--add-host":   len(m.netOpts.AddHost) != 0,
+		"-p/--publish":    len(m.netOpts.PortMappings) != 0,
+		"--dns":           len(m.netOpts.DNSServers) != 0,
+		"--add-host":      len(m.netOpts.AddHost) != 0,
+		"--network-alias": len(m.netOpts.NetworkAliases) != 0,
 	})
 
 	if len(nonZeroParams) != 0 {
@@ -779,6 +784,128 @@ func withRemoveSysfs(_ context.Context, _ oci.Client, c *containers.Container, s
 	return nil
 }
 
+// types.NetworkOptionsManager implementation for pasta networking settings.
+// The container gets its own network namespace, which is connected to the host
+// network by pasta(1) from the createRuntime hook in pkg/ocihook.
+// The /etc/hosts handling is identical to Host-mode networking.
+type pastaNetworkManager struct {
+	hostNetworkManager
+}
+
+// VerifyNetworkOptions Verifies that the internal network settings are correct.
+func (m *pastaNetworkManager) VerifyNetworkOptions(_ context.Context) error {
+	// TODO: check host OS, not client-side OS.
+	if runtime.GOOS != "linux" {
+		return errors.New("pasta networking is only supported on Linux")
+	}
+	if len(m.netOpts.NetworkSlice) > 1 {
+		return errors.New("pasta networking cannot be combined with other networks")
+	}
+	if _, err := exec.LookPath("pasta"); err != nil {
+		return fmt.Errorf("pasta networking requires pasta to be installed (Hint: install the \"passt\" package): %w", err)
+	}
+	for flagName, isSet := range map[string]bool{
+		"--ip":          m.netOpts.IPAddress != "",
+		"--ip6":         m.netOpts.IP6Address != "",
+		"--mac-address": m.netOpts.MACAddress != "",
+	} {
+		if isSet {
+			return fmt.Errorf("conflicting options: %s cannot be used with pasta networking (Hint: pass pasta options like \"--network=pasta:-a,<ADDR>\")", flagName)
+		}
+	}
+	if rootlessutil.IsRootless() {
+		detachedNetNS, err := rootlessutil.DetachedNetNS()
+		if err != nil {
+			return fmt.Errorf("failed to check whether RootlessKit is running with --detach-netns: %w", err)
+		}
+		if detachedNetNS == "" {
+			// Without --detach-netns, pasta would only be able to forward ports inside the
+			// network namespace of RootlessKit
+			return errors.New("pasta networking requires RootlessKit to be running with --detach-netns (Hint: set `CONTAINERD_ROOTLESS_ROOTLESSKIT_DETACH_NETNS=true`)")
+		}
+	}
+
+	return validateUtsSettings(m.netOpts)
+}
+
+// InternalNetworkingOptionLabels Returns the set of NetworkingOptions which should be set as labels on the container.
+func (m *pastaNetworkManager) InternalNetworkingOptionLabels(_ context.Context) (types.NetworkOptions, error) {
+	return m.netOpts, nil
+}
+
+// ContainerNetworkingOpts Returns a slice of `oci.SpecOpts` and `containerd.NewContainerOpts` which represent
+// the network specs which need to be applied to the container with the given ID.
+func (m *pastaNetworkManager) ContainerNetworkingOpts(_ context.Context, containerID string) ([]oci.SpecOpts, []containerd.NewContainerOpts, error) {
+	cOpts := []containerd.NewContainerOpts{}
+
+	dataStore, err := clientutil.DataStore(m.globalOptions.DataRoot, m.globalOptions.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateDir, err := ContainerStateDirPath(m.globalOptions.Namespace, dataStore, containerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolvConfPath := filepath.Join(stateDir, "resolv.conf")
+	dns, dnsSearch, dnsOptions, err := fetchDNSResolverConfig(m.netOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(m.netOpts.DNSServers) == 0 {
+		// The host nameservers may only be reachable via the loopback interface of the host,
+		// so let pasta forward the queries to them.
+		dns = []string{ocihook.PastaDNSForwardAddress}
+	}
+
+	_, err = resolvconf.Build(resolvConfPath, dns, dnsSearch, dnsOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the content of /etc/hosts is created in SetupNetworking
+	hs, err := hostsstore.New(dataStore, m.globalOptions.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	etcHostsPath, err := hs.AllocHostsFile(containerID, []byte(""))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	specs := []oci.SpecOpts{
+		withCustomResolvConf(resolvConfPath),
+		withCustomHosts(etcHostsPath),
+	}
+
+	if m.netOpts.UTSNamespace != UtsNamespaceHost {
+		// If no hostname is set, default to first 12 characters of the container ID.
+		hostname := m.netOpts.Hostname
+		if hostname == "" {
+			hostname = containerID
+			if len(hostname) > 12 {
+				hostname = hostname[0:12]
+			}
+		}
+		m.netOpts.Hostname = hostname
+
+		hostnameOpts, err := writeEtcHostnameForContainer(m.globalOptions, m.netOpts.Hostname, containerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hostnameOpts != nil {
+			specs = append(specs, hostnameOpts...)
+		}
+		if m.netOpts.Domainname != "" {
+			specs = append(specs, oci.WithDomainname(m.netOpts.Domainname))
+		}
+	}
+
+	return specs, cOpts, nil
+}
+
 // types.NetworkOptionsManager implementation for CNI networking settings.
 // This is a more specialized and OS-dependendant networking model so this
 // struct provides different implementations on different platforms.
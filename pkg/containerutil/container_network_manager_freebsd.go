@@ -0,0 +1,234 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook"
+)
+
+// parentJailAnnotation is the annotation that makes the OCI runtime (e.g., ocijail)
+// create the container jail as a child of the given jail, inheriting its vnet.
+const parentJailAnnotation = "org.freebsd.parentJail"
+
+type cniNetworkManagerPlatform struct {
+}
+
+// Verifies that the internal network settings are correct.
+func (m *cniNetworkManager) VerifyNetworkOptions(_ context.Context) error {
+	e, err := netutil.NewCNIEnv(m.globalOptions.CNIPath, m.globalOptions.CNINetConfPath, netutil.WithNamespace(m.globalOptions.Namespace), netutil.WithDefaultNetwork(m.globalOptions.BridgeIP))
+	if err != nil {
+		return err
+	}
+
+	// NOTE: only currently supported network type on FreeBSD is bridge:
+	validNetworkTypes := []string{"bridge"}
+	if _, err := verifyNetworkTypes(e, m.netOpts.NetworkSlice, validNetworkTypes); err != nil {
+		return err
+	}
+
+	nonZeroArgs := nonZeroMapValues(map[string]interface{}{
+		"--mac-address": m.netOpts.MACAddress,
+		// NOTE: zero-length slices count as a non-zero-value so we explicitly check length:
+		"--dns-opt/--dns-option": len(m.netOpts.DNSResolvConfOptions) != 0,
+		"--dns-servers":          len(m.netOpts.DNSServers) != 0,
+		"--dns-search":           len(m.netOpts.DNSSearchDomains) != 0,
+		"--add-host":             len(m.netOpts.AddHost) != 0,
+	})
+	if len(nonZeroArgs) != 0 {
+		return fmt.Errorf("the following networking arguments are not supported on FreeBSD: %+v", nonZeroArgs)
+	}
+
+	return validateUtsSettings(m.netOpts)
+}
+
+func (m *cniNetworkManager) getCNI() (cni.CNI, error) {
+	e, err := netutil.NewCNIEnv(m.globalOptions.CNIPath, m.globalOptions.CNINetConfPath, netutil.WithNamespace(m.globalOptions.Namespace), netutil.WithDefaultNetwork(m.globalOptions.BridgeIP))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate CNI env: %w", err)
+	}
+
+	cniOpts := []cni.Opt{
+		cni.WithPluginDir([]string{m.globalOptions.CNIPath}),
+		cni.WithPluginConfDir(m.globalOptions.CNINetConfPath),
+	}
+
+	if netMap, err := verifyNetworkTypes(e, m.netOpts.NetworkSlice, nil); err == nil {
+		for _, netConf := range netMap {
+			cniOpts = append(cniOpts, cni.WithConfListBytes(netConf.Bytes))
+		}
+	} else {
+		return nil, err
+	}
+
+	return cni.New(cniOpts...)
+}
+
+// Performs setup actions required for the container with the given ID.
+func (m *cniNetworkManager) SetupNetworking(ctx context.Context, containerID string) error {
+	cni, err := m.getCNI()
+	if err != nil {
+		return fmt.Errorf("failed to get container networking for setup: %w", err)
+	}
+
+	netJail, err := createNetJail(containerID)
+	if err != nil {
+		return err
+	}
+
+	// The FreeBSD ports of the CNI plugins take the name of a vnet jail as the network namespace.
+	if _, err = cni.Setup(ctx, containerID, netJail, m.getCNINamespaceOpts()...); err != nil {
+		if rmErr := removeNetJail(netJail); rmErr != nil {
+			log.G(ctx).WithError(rmErr).Warnf("failed to remove the network jail %q", netJail)
+		}
+		return err
+	}
+	return nil
+}
+
+// Performs any required cleanup actions for the given container.
+// Should only be called to revert any setup steps performed in setupNetworking.
+func (m *cniNetworkManager) CleanupNetworking(ctx context.Context, container containerd.Container) error {
+	containerID := container.ID()
+	cni, err := m.getCNI()
+	if err != nil {
+		return fmt.Errorf("failed to get container networking for cleanup: %w", err)
+	}
+
+	containerLabels, err := container.Labels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get container labels for networking cleanup: %w", err)
+	}
+
+	netJail, found := containerLabels[ocihook.NetworkNamespace]
+	if !found {
+		return fmt.Errorf("no %q label present on container with ID %s", ocihook.NetworkNamespace, containerID)
+	}
+
+	if err := cni.Remove(ctx, containerID, netJail, m.getCNINamespaceOpts()...); err != nil {
+		return err
+	}
+	return removeNetJail(netJail)
+}
+
+// Returns the set of NetworkingOptions which should be set as labels on the container.
+func (m *cniNetworkManager) InternalNetworkingOptionLabels(_ context.Context) (types.NetworkOptions, error) {
+	return m.netOpts, nil
+}
+
+// Returns a slice of `oci.SpecOpts` and `containerd.NewContainerOpts` which represent
+// the network specs which need to be applied to the container with the given ID.
+func (m *cniNetworkManager) ContainerNetworkingOpts(_ context.Context, containerID string) ([]oci.SpecOpts, []containerd.NewContainerOpts, error) {
+	netJail := netJailName(containerID)
+
+	opts := []oci.SpecOpts{
+		oci.WithAnnotations(map[string]string{
+			parentJailAnnotation: netJail,
+		}),
+	}
+
+	if m.netOpts.UTSNamespace != UtsNamespaceHost {
+		// If no hostname is set, default to first 12 characters of the container ID.
+		hostname := m.netOpts.Hostname
+		if hostname == "" {
+			hostname = containerID
+			if len(hostname) > 12 {
+				hostname = hostname[0:12]
+			}
+		}
+		m.netOpts.Hostname = hostname
+		opts = append(opts, oci.WithHostname(hostname))
+		if m.netOpts.Domainname != "" {
+			opts = append(opts, oci.WithDomainname(m.netOpts.Domainname))
+		}
+	}
+
+	cOpts := []containerd.NewContainerOpts{
+		containerd.WithAdditionalContainerLabels(
+			map[string]string{
+				ocihook.NetworkNamespace: netJail,
+			},
+		),
+	}
+
+	return opts, cOpts, nil
+}
+
+// Returns the []cni.NamespaceOpts to be used for CNI setup/teardown.
+func (m *cniNetworkManager) getCNINamespaceOpts() []cni.NamespaceOpts {
+	opts := []cni.NamespaceOpts{
+		cni.WithLabels(map[string]string{
+			// allow loose CNI argument verification
+			// FYI: https://github.com/containernetworking/cni/issues/560
+			"IgnoreUnknown": "1",
+		}),
+	}
+
+	if m.netOpts.IPAddress != "" {
+		opts = append(opts, cni.WithArgs("IP", m.netOpts.IPAddress))
+	}
+
+	if m.netOpts.PortMappings != nil {
+		opts = append(opts, cni.WithCapabilityPortMap(m.netOpts.PortMappings))
+	}
+
+	return opts
+}
+
+// netJailName returns the name of the vnet jail that holds the network stack of the container.
+func netJailName(containerID string) string {
+	if len(containerID) > 12 {
+		containerID = containerID[0:12]
+	}
+	return "nerdctl-" + containerID
+}
+
+// createNetJail creates a persistent vnet jail for the container.
+// The container jail is created as its child, so that the network stack outlives the restarts of the container.
+func createNetJail(containerID string) (string, error) {
+	netJail := netJailName(containerID)
+	out, err := exec.Command("jail", "-c", "name="+netJail, "vnet", "persist", "children.max=1").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to create the network jail %q: %w (out=%q)", netJail, err, strings.TrimSpace(string(out)))
+	}
+	return netJail, nil
+}
+
+// removeNetJail removes the vnet jail created by createNetJail.
+// The epair(4) interfaces moved into the jail are returned to the host and destroyed by the CNI plugins.
+func removeNetJail(netJail string) error {
+	if err := exec.Command("jls", "-j", netJail, "jid").Run(); err != nil {
+		// already removed
+		return nil
+	}
+	out, err := exec.Command("jail", "-r", netJail).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove the network jail %q: %w (out=%q)", netJail, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
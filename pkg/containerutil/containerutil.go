@@ -54,7 +54,9 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/labels/k8slabels"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
 	"github.com/containerd/nerdctl/v2/pkg/signalutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/taskutil"
 )
@@ -207,6 +209,9 @@ func GenerateSharingPIDOpts(ctx context.Context, targetCon containerd.Container)
 
 // Start starts `container` with `attach` flag. If `attach` is true, it will attach to the container's stdio.
 func Start(ctx context.Context, container containerd.Container, isAttach bool, isInteractive bool, client *containerd.Client, detachKeys string, checkpointDir string, cfg *config.Config, nerdctlCmd string, nerdctlArgs []string) (err error) {
+	if (isAttach || isInteractive) && sshutil.IsAddress(cfg.Address) {
+		return fmt.Errorf("attaching on start over SSH is not supported, use `nerdctl attach` after starting the container instead: %w", errdefs.ErrNotImplemented)
+	}
 	// defer the storage of start error in the dedicated label
 	defer func() {
 		if err != nil {
@@ -225,6 +230,12 @@ func Start(ctx context.Context, container containerd.Container, isAttach bool, i
 		return err
 	}
 
+	if stateDir := lab[labels.StateDir]; stateDir != "" && !sshutil.IsAddress(cfg.Address) {
+		if err := secretutil.Restore(ctx, stateDir); err != nil {
+			return err
+		}
+	}
+
 	if err := ReconfigPIDContainer(ctx, container, client, lab); err != nil {
 		return err
 	}
@@ -639,7 +650,7 @@ func DecodeContainerRmOptLabel(rmOptLabel string) (bool, error) {
 	return strconv.ParseBool(rmOptLabel)
 }
 
-// ParseExtraHosts takes an array of host-to-IP mapping strings, e.g. "localhost:127.0.0.1",
+// ParseExtraHosts takes an array of host-to-IP mapping strings, e.g. "localhost:127.0.0.1" or "localhost=127.0.0.1",
 // and a hostGatewayIP for resolving mappings to "host-gateway".
 //
 // Returns a map of host-to-IPs or errors if any mapping strings are not correctly formatted.
@@ -650,13 +661,15 @@ func ParseExtraHosts(extraHosts []string, hostGatewayIP, separator string) ([]st
 			return nil, err
 		}
 
-		parts := strings.SplitN(hostToIP, ":", 2)
-		if len(parts) != 2 {
+		// "host=ip" is accepted as well as "host:ip", as in Docker
+		host, ip, ok := strings.Cut(hostToIP, "=")
+		if !ok {
+			host, ip, ok = strings.Cut(hostToIP, ":")
+		}
+		if !ok {
 			return nil, fmt.Errorf("invalid host-to-IP map %s", hostToIP)
 		}
 
-		host, ip := parts[0], parts[1]
-
 		// If the IP address is a string called "host-gateway", replace this value with the IP address stored
 		// in the daemon level HostGatewayIP config variable.
 		if ip == dockeropts.HostGatewayName && hostGatewayIP == "" {
@@ -46,6 +46,12 @@ func TestParseExtraHosts(t *testing.T) {
 			separator:  "=",
 			expected:   []string{"localhost=127.0.0.1", "localhost=[::1]"},
 		},
+		{
+			name:       "EqualsInExtraHosts",
+			extraHosts: []string{"localhost=127.0.0.1", "localhost=::1"},
+			separator:  ":",
+			expected:   []string{"localhost:127.0.0.1", "localhost:::1"},
+		},
 		{
 			name:           "InvalidExtraHostFormat",
 			extraHosts:     []string{"localhost"},
@@ -64,6 +70,13 @@ func TestParseExtraHosts(t *testing.T) {
 			separator:   ":",
 			expected:    []string{"localhost:10.10.0.1"},
 		},
+		{
+			name:        "HostGatewayIPWithEqualsSeparator",
+			extraHosts:  []string{"gateway=host-gateway"},
+			hostGateway: "10.10.0.1",
+			separator:   "=",
+			expected:    []string{"gateway=10.10.0.1"},
+		},
 	}
 
 	for _, test := range tests {
@@ -35,6 +35,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
 	"github.com/containerd/nerdctl/v2/pkg/tarutil"
 )
 
@@ -45,6 +46,7 @@ var (
 	ErrFilesystem             = errors.New("filesystem error") // lstat hard errors, etc
 	ErrContainerVanished      = errors.New("the container you are trying to copy to/from has been deleted")
 	ErrRootlessCannotCp       = errors.New("cannot use cp with stopped containers in rootless mode") // rootless cp with a stopped container
+	ErrSSHCannotCp            = errors.New("cannot use cp with stopped containers over SSH")         // cp with a stopped container on an SSH host
 	ErrFailedMountingSnapshot = errors.New("failed mounting snapshot")                               // failure to mount a stopped container snapshot
 
 	// CP specific errors
@@ -84,6 +86,7 @@ func getRoot(ctx context.Context, container containerd.Container) (string, int,
 // - tar binary exists on the system
 // - nsenter binary exists on the system
 // - if rootless, the container is running (aka: /proc/pid/root)
+// - if the address is an SSH address, see copyFilesOverSSH
 func CopyFiles(ctx context.Context, client *containerd.Client, container containerd.Container, options types.ContainerCpOptions) (err error) {
 	// We do rely on the tar binary as a shortcut - could also be replaced by archive/tar, though that would mean
 	// we need to replace nsenter calls with re-exec
@@ -94,6 +97,10 @@ func CopyFiles(ctx context.Context, client *containerd.Client, container contain
 
 	log.G(ctx).Debugf("Detected tar binary %q (GNU=%v)", tarBinary, isGNUTar)
 
+	if sshutil.IsAddress(options.GOptions.Address) {
+		return copyFilesOverSSH(ctx, container, options, tarBinary, isGNUTar)
+	}
+
 	// This can happen if the container being passed has been deleted since in a racy way
 	conSpec, err := container.Spec(ctx)
 	if err != nil {
@@ -153,45 +160,8 @@ func CopyFiles(ctx context.Context, client *containerd.Client, container contain
 		destinationSpec, destErr = getPathSpecFromContainer(options.DestPath, conSpec, root)
 	}
 
-	if destErr != nil {
-		if errors.Is(destErr, errDoesNotExist) {
-			return ErrDestinationParentMustExist
-		} else if errors.Is(destErr, errIsNotADir) {
-			return ErrDestinationIsNotADir
-		}
-
-		return errors.Join(ErrFilesystem, destErr)
-	}
-
-	if sourceErr != nil {
-		if errors.Is(sourceErr, errDoesNotExist) {
-			return ErrSourceDoesNotExist
-		} else if errors.Is(sourceErr, errIsNotADir) {
-			return ErrSourceIsNotADir
-		}
-
-		return errors.Join(ErrFilesystem, sourceErr)
-	}
-
-	// Now, resolve cp shenanigans
-	// First, cannot copy a non-existent resource
-	if !sourceSpec.exists {
-		return ErrSourceDoesNotExist
-	}
-
-	// Second, cannot copy into a readonly destination
-	if destinationSpec.readOnly {
-		return ErrTargetIsReadOnly
-	}
-
-	// Cannot copy a dir into a file
-	if sourceSpec.isADir && destinationSpec.exists && !destinationSpec.isADir {
-		return ErrCannotCopyDirToFile
-	}
-
-	// A file cannot be copied inside a non-existent directory with a trailing slash, or slash+dot
-	if !sourceSpec.isADir && !destinationSpec.exists && (destinationSpec.endsWithSeparator || destinationSpec.endsWithSeparatorDot) {
-		return ErrDestinationDirMustExist
+	if err = checkPathSpecs(sourceSpec, sourceErr, destinationSpec, destErr); err != nil {
+		return err
 	}
 
 	// XXX FIXME: this seems wrong. What about ownership? We could be doing that inside a container
@@ -321,6 +291,53 @@ func CopyFiles(ctx context.Context, client *containerd.Client, container contain
 	return nil
 }
 
+// checkPathSpecs returns the cp error for the source and the destination resolved by getPathSpecFromHost or
+// getPathSpecFromContainer, or nil if the source can be copied to the destination
+func checkPathSpecs(sourceSpec *pathSpecifier, sourceErr error, destinationSpec *pathSpecifier, destErr error) error {
+	if destErr != nil {
+		if errors.Is(destErr, errDoesNotExist) {
+			return ErrDestinationParentMustExist
+		} else if errors.Is(destErr, errIsNotADir) {
+			return ErrDestinationIsNotADir
+		}
+
+		return errors.Join(ErrFilesystem, destErr)
+	}
+
+	if sourceErr != nil {
+		if errors.Is(sourceErr, errDoesNotExist) {
+			return ErrSourceDoesNotExist
+		} else if errors.Is(sourceErr, errIsNotADir) {
+			return ErrSourceIsNotADir
+		}
+
+		return errors.Join(ErrFilesystem, sourceErr)
+	}
+
+	// Now, resolve cp shenanigans
+	// First, cannot copy a non-existent resource
+	if !sourceSpec.exists {
+		return ErrSourceDoesNotExist
+	}
+
+	// Second, cannot copy into a readonly destination
+	if destinationSpec.readOnly {
+		return ErrTargetIsReadOnly
+	}
+
+	// Cannot copy a dir into a file
+	if sourceSpec.isADir && destinationSpec.exists && !destinationSpec.isADir {
+		return ErrCannotCopyDirToFile
+	}
+
+	// A file cannot be copied inside a non-existent directory with a trailing slash, or slash+dot
+	if !sourceSpec.isADir && !destinationSpec.exists && (destinationSpec.endsWithSeparator || destinationSpec.endsWithSeparatorDot) {
+		return ErrDestinationDirMustExist
+	}
+
+	return nil
+}
+
 func mountSnapshotForContainer(ctx context.Context, client *containerd.Client, conInfo containers.Container, snapshotter string) (string, func() error, error) {
 	snapKey := conInfo.SnapshotKey
 	resp, err := client.SnapshotService(snapshotter).Mounts(ctx, snapKey)
@@ -0,0 +1,378 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package containerutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/sshutil"
+)
+
+// copyFilesOverSSH implements `nerdctl cp` for the containers on an SSH host.
+// The root of the container is not accessible from here, so the archive is created or extracted by the tar binary
+// in the container, and streamed over SSH.
+// It depends on the following assumptions:
+// - the container is running
+// - tar and test binaries exist in the container (and mkdir, when copying a directory to a new path in the container)
+func copyFilesOverSSH(ctx context.Context, container containerd.Container, options types.ContainerCpOptions, tarBinary string, isGNUTar bool) (err error) {
+	host, err := sshutil.ParseAddress(options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+
+	conSpec, err := container.Spec(ctx)
+	if err != nil {
+		return errors.Join(ErrContainerVanished, err)
+	}
+	if conSpec.Process == nil {
+		return fmt.Errorf("container %q has no process spec", container.ID())
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ErrSSHCannotCp
+		}
+		return errors.Join(ErrContainerVanished, err)
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return errors.Join(ErrContainerVanished, err)
+	}
+	if status.Status != containerd.Running {
+		return ErrSSHCannotCp
+	}
+
+	runner := &containerRunner{
+		host:    host,
+		task:    task,
+		process: conSpec.Process,
+	}
+
+	var sourceSpec, destinationSpec *pathSpecifier
+	var sourceErr, destErr error
+	if options.Container2Host {
+		sourceSpec, sourceErr = runner.getPathSpec(ctx, options.SrcPath)
+		destinationSpec, destErr = getPathSpecFromHost(options.DestPath)
+	} else {
+		sourceSpec, sourceErr = getPathSpecFromHost(options.SrcPath)
+		destinationSpec, destErr = runner.getPathSpec(ctx, options.DestPath)
+	}
+
+	if err = checkPathSpecs(sourceSpec, sourceErr, destinationSpec, destErr); err != nil {
+		return err
+	}
+
+	// The archive has a single entry (tarCArg in tarCDir), extracted in tarXDir.
+	// For a file, the entry is renamed to name in tarXDir.
+	var tarCDir, tarCArg, tarXDir, name string
+	if sourceSpec.isADir {
+		tarXDir = destinationSpec.resolvedPath
+		if !destinationSpec.exists || sourceSpec.endsWithSeparatorDot {
+			// the content of the source directory is copied into this directory
+			tarCDir = sourceSpec.resolvedPath
+			tarCArg = "."
+		} else {
+			// the source directory is copied into this directory
+			tarCDir = filepath.Dir(sourceSpec.resolvedPath)
+			tarCArg = filepath.Base(sourceSpec.resolvedPath)
+		}
+	} else {
+		tarCDir = filepath.Dir(sourceSpec.resolvedPath)
+		tarCArg = filepath.Base(sourceSpec.resolvedPath)
+		if destinationSpec.endsWithSeparator || (destinationSpec.exists && destinationSpec.isADir) {
+			tarXDir = destinationSpec.resolvedPath
+			name = tarCArg
+		} else {
+			// Handle `nerdctl cp some-container:/path/to/file /path/to/file-with-another-name`
+			tarXDir = filepath.Dir(destinationSpec.resolvedPath)
+			name = filepath.Base(destinationSpec.resolvedPath)
+		}
+	}
+
+	if options.Container2Host {
+		return runner.copyFromContainer(ctx, tarCDir, tarCArg, tarXDir, name, sourceSpec.isADir && !destinationSpec.exists, options.FollowSymLink, tarBinary, isGNUTar)
+	}
+	return runner.copyToContainer(ctx, tarCDir, tarCArg, tarXDir, name, sourceSpec.isADir && !destinationSpec.exists, options.FollowSymLink, tarBinary)
+}
+
+// copyFromContainer streams the archive created by tar in the container to the local tar binary.
+func (r *containerRunner) copyFromContainer(ctx context.Context, tarCDir, tarCArg, tarXDir, name string, mkdir, followSymLink bool, tarBinary string, isGNUTar bool) error {
+	if mkdir {
+		if err := os.Mkdir(tarXDir, 0o755); err != nil {
+			return errors.Join(ErrFilesystem, err)
+		}
+	}
+
+	// A file is extracted in a temporary directory first, to be renamed
+	extractDir := tarXDir
+	if name != "" {
+		td, err := os.MkdirTemp(tarXDir, ".nerdctl-cp")
+		if err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return ErrTargetIsReadOnly
+			}
+			return errors.Join(ErrFilesystem, err)
+		}
+		defer os.RemoveAll(td)
+		extractDir = td
+	}
+
+	tarC := []string{"tar"}
+	if followSymLink {
+		tarC = append(tarC, "-h")
+	}
+	tarC = append(tarC, "-c", "-f", "-", "-C", tarCDir, tarCArg)
+
+	tarX := []string{tarBinary, "-x"}
+	if isGNUTar {
+		tarX = append(tarX, "--no-same-owner")
+	}
+	tarX = append(tarX, "-f", "-")
+
+	tarXCmd := exec.CommandContext(ctx, tarX[0], tarX[1:]...)
+	tarXCmd.Dir = extractDir
+	tarXCmd.Stdout = os.Stderr
+	var tarErr bytes.Buffer
+	tarXCmd.Stderr = &tarErr
+	stdin, err := tarXCmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).Debugf("executing %v in %q", tarXCmd.Args, tarXCmd.Dir)
+	if err := tarXCmd.Start(); err != nil {
+		return errors.Join(fmt.Errorf("failed to execute %v", tarXCmd.Args), err)
+	}
+
+	_, runErr := r.run(ctx, nil, stdin, tarC...)
+	stdin.Close()
+	waitErr := tarXCmd.Wait()
+	if runErr != nil {
+		return runErr
+	}
+	if waitErr != nil {
+		if strings.Contains(tarErr.String(), "Read-only file system") {
+			return ErrTargetIsReadOnly
+		}
+
+		// Other errors, just put them back on stderr
+		_, fpErr := fmt.Fprint(os.Stderr, tarErr.String())
+		if fpErr != nil {
+			return errors.Join(fpErr, waitErr)
+		}
+
+		return errors.Join(fmt.Errorf("failed to wait %v", tarXCmd.Args), waitErr)
+	}
+
+	if name != "" {
+		if err := os.Rename(filepath.Join(extractDir, tarCArg), filepath.Join(tarXDir, name)); err != nil {
+			return errors.Join(ErrFilesystem, err)
+		}
+	}
+	return nil
+}
+
+// copyToContainer streams the archive created by the local tar binary to tar in the container.
+func (r *containerRunner) copyToContainer(ctx context.Context, tarCDir, tarCArg, tarXDir, name string, mkdir, followSymLink bool, tarBinary string) error {
+	if name != "" {
+		// Prepare a single-file directory to create an archive of the source file
+		td, err := os.MkdirTemp("", "nerdctl-cp")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(td)
+		cp := []string{"cp", "-a"}
+		if followSymLink {
+			cp = append(cp, "-L")
+		}
+		cp = append(cp, filepath.Join(tarCDir, tarCArg), filepath.Join(td, name))
+		cpCmd := exec.CommandContext(ctx, cp[0], cp[1:]...)
+		log.G(ctx).Debugf("executing %v", cpCmd.Args)
+		if out, err := cpCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to execute %v: %w (out=%q)", cpCmd.Args, err, string(out))
+		}
+		tarCDir = td
+		tarCArg = name
+	}
+
+	if mkdir {
+		if _, err := r.run(ctx, nil, nil, "mkdir", tarXDir); err != nil {
+			return r.extractError(err)
+		}
+	}
+
+	tarC := []string{tarBinary}
+	if followSymLink {
+		tarC = append(tarC, "-h")
+	}
+	tarC = append(tarC, "-c", "-f", "-", tarCArg)
+
+	// the local tar is killed if the remote one fails before reading the whole archive
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tarCCmd := exec.CommandContext(ctx, tarC[0], tarC[1:]...)
+	tarCCmd.Dir = tarCDir
+	tarCCmd.Stderr = os.Stderr
+	stdout, err := tarCCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).Debugf("executing %v in %q", tarCCmd.Args, tarCCmd.Dir)
+	if err := tarCCmd.Start(); err != nil {
+		return errors.Join(fmt.Errorf("failed to execute %v", tarCCmd.Args), err)
+	}
+
+	if _, err := r.run(ctx, stdout, nil, "tar", "-x", "-f", "-", "-C", tarXDir); err != nil {
+		cancel()
+		tarCCmd.Wait()
+		return r.extractError(err)
+	}
+
+	if err := tarCCmd.Wait(); err != nil {
+		return fmt.Errorf("failed to wait %v: %w", tarCCmd.Args, err)
+	}
+	return nil
+}
+
+func (r *containerRunner) extractError(err error) error {
+	if strings.Contains(err.Error(), "Read-only file system") {
+		return ErrTargetIsReadOnly
+	}
+	return err
+}
+
+// containerRunner executes commands in a running container on an SSH host, with the process spec of the container.
+type containerRunner struct {
+	host    *sshutil.Host
+	task    containerd.Task
+	process *specs.Process
+}
+
+// getPathSpec builds a pathSpecifier from a container location, with test(1) in the container.
+// Unlike getPathSpecFromContainer, resolvedPath is the (absolute, cleaned) path in the container, and readOnly is
+// never set, as the mounts of the container are not accessible.
+func (r *containerRunner) getPathSpec(ctx context.Context, originalPath string) (*pathSpecifier, error) {
+	pathSpec := &pathSpecifier{
+		originalPath:         originalPath,
+		endsWithSeparator:    strings.HasSuffix(originalPath, "/"),
+		endsWithSeparatorDot: path.Base(originalPath) == ".",
+	}
+
+	// Paths in the container are relative to the root, see getPathSpecFromContainer
+	p := originalPath
+	if !path.IsAbs(p) {
+		p = "/" + p
+	}
+	p = path.Clean(p)
+	pathSpec.resolvedPath = p
+
+	var err error
+	if pathSpec.isADir, err = r.test(ctx, "-d", p); err != nil {
+		return nil, err
+	}
+	pathSpec.exists = pathSpec.isADir
+	if !pathSpec.exists {
+		if pathSpec.exists, err = r.test(ctx, "-e", p); err != nil {
+			return nil, err
+		}
+	}
+
+	if pathSpec.exists {
+		if !pathSpec.isADir && pathSpec.endsWithSeparator {
+			return nil, errIsNotADir
+		}
+		return pathSpec, nil
+	}
+
+	// Ensure the parent exists if the path itself does not
+	parent := path.Dir(p)
+	if ok, err := r.test(ctx, "-d", parent); err != nil || ok {
+		return pathSpec, err
+	}
+	if ok, err := r.test(ctx, "-e", parent); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, errIsNotADir
+	}
+	return nil, errDoesNotExist
+}
+
+// test returns whether test(1) in the container succeeds with args.
+func (r *containerRunner) test(ctx context.Context, args ...string) (bool, error) {
+	code, err := r.run(ctx, nil, nil, append([]string{"test"}, args...)...)
+	if code == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// run executes args in the container with stdin and stdout, and returns the exit code.
+// The error of a non-zero exit code contains the stderr of the process.
+func (r *containerRunner) run(ctx context.Context, stdin io.Reader, stdout io.Writer, args ...string) (uint32, error) {
+	pspec := *r.process
+	pspec.Terminal = false
+	pspec.Args = args
+
+	var stderr bytes.Buffer
+	ioCreator := r.host.NewCreator(cio.WithStreams(stdin, stdout, &stderr))
+
+	log.G(ctx).Debugf("executing %v in the container", args)
+	process, err := r.task.Exec(ctx, "cp-"+idgen.GenerateID(), &pspec, ioCreator)
+	if err != nil {
+		return 0, err
+	}
+	defer process.Delete(ctx)
+
+	statusC, err := process.Wait(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := process.Start(ctx); err != nil {
+		return 0, err
+	}
+	status := <-statusC
+	process.IO().Wait()
+
+	code, _, err := status.Result()
+	if err != nil {
+		return 0, err
+	}
+	if code != 0 {
+		return code, fmt.Errorf("failed to execute %v in the container: exit code %d (stderr=%q)", args, code, stderr.String())
+	}
+	return 0, nil
+}
@@ -0,0 +1,207 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package contextstore persists the named contexts created with `nerdctl context create`,
+// and the context selected with `nerdctl context use`.
+// Contexts are client-side settings, so they are stored next to nerdctl.toml rather than under the data root.
+package contextstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+const (
+	instancesDir = "instances"
+	currentKey   = "current"
+)
+
+// Context is a named set of endpoint settings.
+// Empty fields fall back to nerdctl.toml and the defaults.
+type Context struct {
+	Name        string `json:"Name"`
+	Description string `json:"Description,omitempty"`
+	// Address is the containerd address, e.g. "unix:///run/containerd/containerd.sock"
+	Address string `json:"Address,omitempty"`
+	// Namespace is the containerd namespace
+	Namespace string `json:"Namespace,omitempty"`
+	// Snapshotter is the containerd snapshotter
+	Snapshotter string `json:"Snapshotter,omitempty"`
+	// SSHHost is the remote host to run nerdctl on, in the form of "[ssh://][USER@]HOST[:PORT]"
+	SSHHost string `json:"SSHHost,omitempty"`
+}
+
+// Store manages the named contexts.
+type Store interface {
+	// Get returns the context with the given name, or an error wrapping errdefs.ErrNotFound
+	Get(name string) (*Context, error)
+	// List returns all the contexts, sorted by name
+	List() ([]*Context, error)
+	// Create saves a new context. It errors with errdefs.ErrAlreadyExists if the name is taken.
+	Create(c *Context) error
+	// Remove removes the context, and unselects it if it was the current one
+	Remove(name string) error
+	// Current returns the name of the context selected with Use, or "" if none is selected
+	Current() (string, error)
+	// Use selects the context with the given name. An empty name unselects the current context.
+	Use(name string) error
+}
+
+// Dir returns the directory of the context store for the given path of nerdctl.toml.
+func Dir(tomlPath string) string {
+	return filepath.Join(filepath.Dir(tomlPath), "contexts")
+}
+
+// New returns the context store in dir.
+func New(dir string) (Store, error) {
+	st, err := store.New(dir, 0o755, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create context store: %w", err)
+	}
+	return &contextStore{store: st}, nil
+}
+
+type contextStore struct {
+	store store.Store
+}
+
+func (s *contextStore) Get(name string) (*Context, error) {
+	var c *Context
+	err := s.store.WithLock(func() error {
+		var err error
+		c, err = s.get(name)
+		return err
+	})
+	return c, err
+}
+
+func (s *contextStore) List() ([]*Context, error) {
+	var contexts []*Context
+	err := s.store.WithLock(func() error {
+		names, err := s.store.List(instancesDir)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c, err := s.get(name)
+			if err != nil {
+				return err
+			}
+			contexts = append(contexts, c)
+		}
+		return nil
+	})
+	return contexts, err
+}
+
+func (s *contextStore) Create(c *Context) error {
+	if err := identifiers.ValidateDockerCompat(c.Name); err != nil {
+		return fmt.Errorf("invalid context name %q: %w", c.Name, err)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return s.store.WithLock(func() error {
+		exists, err := s.store.Exists(instancesDir, c.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("context %q already exists: %w", c.Name, errdefs.ErrAlreadyExists)
+		}
+		return s.store.Set(data, instancesDir, c.Name)
+	})
+}
+
+func (s *contextStore) Remove(name string) error {
+	return s.store.WithLock(func() error {
+		if err := s.store.Delete(instancesDir, name); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return fmt.Errorf("context %q not found: %w", name, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		current, err := s.current()
+		if err != nil {
+			return err
+		}
+		if current == name {
+			return s.store.Delete(currentKey)
+		}
+		return nil
+	})
+}
+
+func (s *contextStore) Current() (string, error) {
+	var current string
+	err := s.store.WithLock(func() error {
+		var err error
+		current, err = s.current()
+		return err
+	})
+	return current, err
+}
+
+func (s *contextStore) Use(name string) error {
+	return s.store.WithLock(func() error {
+		if name == "" {
+			if err := s.store.Delete(currentKey); err != nil && !errors.Is(err, store.ErrNotFound) {
+				return err
+			}
+			return nil
+		}
+		return s.store.Set([]byte(name), currentKey)
+	})
+}
+
+func (s *contextStore) get(name string) (*Context, error) {
+	data, err := s.store.Get(instancesDir, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("context %q not found: %w", name, errdefs.ErrNotFound)
+		}
+		return nil, err
+	}
+	var c Context
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal context %q: %w", name, err)
+	}
+	return &c, nil
+}
+
+func (s *contextStore) current() (string, error) {
+	data, err := s.store.Get(currentKey)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
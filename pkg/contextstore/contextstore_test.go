@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package contextstore
+
+import (
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+func TestContextStore(t *testing.T) {
+	st, err := New(t.TempDir())
+	assert.NilError(t, err)
+
+	contexts, err := st.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(contexts), 0)
+
+	current, err := st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "")
+
+	k8s := &Context{Name: "k8s", Namespace: "k8s.io"}
+	remote := &Context{Name: "remote", Description: "build host", SSHHost: "ssh://user@example.com:2222", Snapshotter: "stargz"}
+	assert.NilError(t, st.Create(remote))
+	assert.NilError(t, st.Create(k8s))
+	assert.ErrorIs(t, st.Create(k8s), errdefs.ErrAlreadyExists)
+	assert.ErrorContains(t, st.Create(&Context{Name: "in/valid"}), "invalid context name")
+
+	contexts, err = st.List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, contexts, []*Context{k8s, remote})
+
+	got, err := st.Get("remote")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, remote)
+	_, err = st.Get("missing")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+
+	assert.NilError(t, st.Use("remote"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "remote")
+
+	// removing another context keeps the selection
+	assert.NilError(t, st.Remove("k8s"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "remote")
+
+	// removing the current context unselects it
+	assert.NilError(t, st.Remove("remote"))
+	current, err = st.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "")
+	assert.ErrorIs(t, st.Remove("remote"), errdefs.ErrNotFound)
+
+	// unselecting without a selection is a no-op
+	assert.NilError(t, st.Use(""))
+}
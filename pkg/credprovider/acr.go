@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package credprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// azureResource is the audience of the AAD tokens accepted by the token exchange of ACR
+const azureResource = "https://management.azure.com/"
+
+var (
+	// acrScheme is the scheme of the token exchange endpoint; overridden in tests
+	acrScheme = "https"
+	// azureCredential returns the default credential chain of the Azure SDK; overridden in tests
+	azureCredential = func() (azcore.TokenCredential, error) {
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: azcore.ClientOptions{Transport: httpClient},
+		})
+	}
+)
+
+type acrProvider struct{}
+
+// credentials obtains an AAD access token from the default credential chain of the Azure SDK
+// (the environment, the workload identity, the managed identity, and the Azure CLI),
+// and exchanges it for a refresh token of the registry.
+func (p *acrProvider) credentials(ctx context.Context, host string) (*Credentials, error) {
+	cred, err := azureCredential()
+	if err != nil {
+		return nil, err
+	}
+	aad, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureResource + ".default"}})
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "access_token")
+	form.Set("service", host)
+	form.Set("access_token", aad.Token)
+	if tenant := os.Getenv("AZURE_TENANT_ID"); tenant != "" {
+		form.Set("tenant", tenant)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, acrScheme+"://"+host+"/oauth2/exchange", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var res struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doJSON(req, &res); err != nil {
+		return nil, err
+	}
+	if res.RefreshToken == "" {
+		return nil, fmt.Errorf("no refresh token returned from %s", req.URL.Redacted())
+	}
+	expiry := jwtExpiry(res.RefreshToken)
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Hour)
+	}
+	// The empty username makes the refresh token an identity token, exchanged with the refresh_token grant
+	return &Credentials{
+		Secret: res.RefreshToken,
+		Expiry: expiry,
+	}, nil
+}
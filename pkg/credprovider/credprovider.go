@@ -0,0 +1,163 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package credprovider implements the built-in credential providers of the cloud registries
+// (ECR, GAR/GCR, and ACR), selected with the `credential_provider` of the [registries."<HOST>"] tables of nerdctl.toml.
+//
+// The providers obtain short-lived registry credentials from the default credential chains of the clouds,
+// and refresh them when they expire, e.g., during long pushes, without installing docker credential helpers.
+package credprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ECR is the provider of Amazon Elastic Container Registry, using the default credential chain of the AWS SDK
+	ECR = "ecr"
+	// GCR is the provider of Google Artifact Registry and Google Container Registry, using the application default credentials
+	GCR = "gcr"
+	// GAR is an alias of GCR
+	GAR = "gar"
+	// ACR is the provider of Azure Container Registry, using the environment or the managed identity credentials
+	ACR = "acr"
+)
+
+// refreshMargin is subtracted from the lifetime of the credentials, so that they do not expire while in use
+const refreshMargin = 5 * time.Minute
+
+// Credentials are the credentials of a registry, valid until Expiry.
+// An empty Username denotes that Secret is an identity token (an OAuth2 refresh token).
+type Credentials struct {
+	Username string
+	Secret   string
+	Expiry   time.Time
+}
+
+type provider interface {
+	credentials(ctx context.Context, host string) (*Credentials, error)
+}
+
+var (
+	// httpClient is the client of the APIs of the clouds
+	httpClient = &http.Client{Timeout: 30 * time.Second}
+
+	mu    sync.Mutex
+	cache = make(map[string]*cacheEntry)
+)
+
+// cacheEntry is locked while the credentials of a host are obtained from the provider,
+// so that the concurrent requests for the same host share the credentials, without blocking the other hosts.
+type cacheEntry struct {
+	mu    sync.Mutex
+	creds *Credentials
+}
+
+func newProvider(name string) (provider, error) {
+	switch name {
+	case ECR:
+		return &ecrProvider{}, nil
+	case GCR, GAR:
+		return &gcrProvider{}, nil
+	case ACR:
+		return &acrProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential provider %q (supported: %s, %s, %s)", name, ECR, GCR, ACR)
+	}
+}
+
+// Validate returns an error if the name is not a known credential provider.
+func Validate(name string) error {
+	_, err := newProvider(name)
+	return err
+}
+
+// Get returns the credentials of the host (e.g., "123456789012.dkr.ecr.us-east-1.amazonaws.com") from the provider.
+// The credentials are cached in the process, until they are about to expire.
+func Get(ctx context.Context, name, host string) (*Credentials, error) {
+	p, err := newProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	key := name + "\x00" + host
+
+	mu.Lock()
+	entry, ok := cache[key]
+	if !ok {
+		entry = &cacheEntry{}
+		cache[key] = entry
+	}
+	mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if c := entry.creds; c != nil && time.Now().Before(c.Expiry.Add(-refreshMargin)) {
+		return c, nil
+	}
+	c, err := p.credentials(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("credential provider %q failed for %q: %w", name, host, err)
+	}
+	entry.creds = c
+	return c, nil
+}
+
+// doJSON sends the request and decodes the JSON response into v, returning an error with the response body on failures.
+func doJSON(req *http.Request, v any) error {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d from %s: %s", res.StatusCode, req.URL.Redacted(), strings.TrimSpace(string(b)))
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to decode the response of %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// jwtExpiry returns the "exp" claim of the JWT, or the zero time when the token is not a JWT.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
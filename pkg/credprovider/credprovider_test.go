@@ -0,0 +1,214 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package credprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"gotest.tools/v3/assert"
+)
+
+func TestECR(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	expiresAt := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("X-Amz-Target"), "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken")
+		assert.Assert(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+		assert.Assert(t, strings.Contains(r.Header.Get("Authorization"), "/us-west-2/ecr/aws4_request"))
+		b, _ := io.ReadAll(r.Body)
+		assert.Equal(t, string(b), `{"registryIds":["123456789012"]}`)
+		token := base64.StdEncoding.EncodeToString([]byte("AWS:password"))
+		fmt.Fprintf(w, `{"authorizationData":[{"authorizationToken":%q,"expiresAt":%d}]}`, token, expiresAt.Unix())
+	}))
+	defer srv.Close()
+	defer func(f func(string, string, string) string) { ecrEndpoint = f }(ecrEndpoint)
+	ecrEndpoint = func(service, region, domain string) string {
+		assert.Equal(t, service+"."+region+"."+domain, "ecr.us-west-2.amazonaws.com")
+		return srv.URL
+	}
+
+	c, err := (&ecrProvider{}).credentials(context.Background(), "123456789012.dkr.ecr.us-west-2.amazonaws.com")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *c, Credentials{Username: "AWS", Secret: "password", Expiry: expiresAt})
+
+	_, err = (&ecrProvider{}).credentials(context.Background(), "registry.example.com")
+	assert.ErrorContains(t, err, "not an ECR registry")
+}
+
+func TestGCRAuthorizedUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		assert.Equal(t, r.PostForm.Get("grant_type"), "refresh_token")
+		assert.Equal(t, r.PostForm.Get("refresh_token"), "refresh")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","expires_in":3600}`)
+	}))
+	defer srv.Close()
+	file := filepath.Join(t.TempDir(), "adc.json")
+	assert.NilError(t, os.WriteFile(file, []byte(fmt.Sprintf(
+		`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh","token_uri":%q}`, srv.URL)), 0o600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+
+	c, err := (&gcrProvider{}).credentials(context.Background(), "us-docker.pkg.dev")
+	assert.NilError(t, err)
+	assert.Equal(t, c.Username, "oauth2accesstoken")
+	assert.Equal(t, c.Secret, "access")
+	assert.Assert(t, time.Until(c.Expiry) > 59*time.Minute)
+}
+
+func TestGCRServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NilError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		assert.Equal(t, r.PostForm.Get("grant_type"), "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		assert.Equal(t, len(parts), 3)
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		assert.NilError(t, err)
+		sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NilError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig))
+		b, err := base64.RawURLEncoding.DecodeString(parts[1])
+		assert.NilError(t, err)
+		var claims map[string]any
+		assert.NilError(t, json.Unmarshal(b, &claims))
+		assert.Equal(t, claims["iss"], "sa@example.iam.gserviceaccount.com")
+		fmt.Fprint(w, `{"access_token":"access","expires_in":3600}`)
+	}))
+	defer srv.Close()
+	adc, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "sa@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL,
+	})
+	assert.NilError(t, err)
+	file := filepath.Join(t.TempDir(), "adc.json")
+	assert.NilError(t, os.WriteFile(file, adc, 0o600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+
+	c, err := (&gcrProvider{}).credentials(context.Background(), "gcr.io")
+	assert.NilError(t, err)
+	assert.Equal(t, c.Secret, "access")
+}
+
+// fakeTokenCredential is an azcore.TokenCredential returning a fixed token
+type fakeTokenCredential string
+
+func (c fakeTokenCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if len(opts.Scopes) != 1 || opts.Scopes[0] != azureResource+".default" {
+		return azcore.AccessToken{}, fmt.Errorf("unexpected scopes %v", opts.Scopes)
+	}
+	return azcore.AccessToken{Token: string(c), ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestACR(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	expiry := time.Now().Add(3 * time.Hour).Truncate(time.Second)
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiry.Unix())))
+	refreshToken := "header." + claims + ".signature"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/exchange" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.NilError(t, r.ParseForm())
+		assert.Equal(t, r.PostForm.Get("grant_type"), "access_token")
+		assert.Equal(t, r.PostForm.Get("service"), r.Host)
+		assert.Equal(t, r.PostForm.Get("tenant"), "tenant")
+		assert.Equal(t, r.PostForm.Get("access_token"), "aad")
+		fmt.Fprintf(w, `{"refresh_token":%q}`, refreshToken)
+	}))
+	defer srv.Close()
+	defer func(scheme string, f func() (azcore.TokenCredential, error)) {
+		acrScheme, azureCredential = scheme, f
+	}(acrScheme, azureCredential)
+	acrScheme = "http"
+	azureCredential = func() (azcore.TokenCredential, error) {
+		return fakeTokenCredential("aad"), nil
+	}
+
+	c, err := (&acrProvider{}).credentials(context.Background(), srv.Listener.Addr().String())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *c, Credentials{Secret: refreshToken, Expiry: expiry})
+}
+
+func TestGet(t *testing.T) {
+	_, err := Get(context.Background(), "unknown", "registry.example.com")
+	assert.ErrorContains(t, err, `unknown credential provider "unknown"`)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access%d","expires_in":3600}`, calls)
+	}))
+	defer srv.Close()
+	file := filepath.Join(t.TempDir(), "adc.json")
+	assert.NilError(t, os.WriteFile(file, []byte(fmt.Sprintf(`{"type":"authorized_user","refresh_token":"refresh","token_uri":%q}`, srv.URL)), 0o600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+
+	for range 2 {
+		c, err := Get(context.Background(), GAR, "europe-docker.pkg.dev")
+		assert.NilError(t, err)
+		assert.Equal(t, c.Secret, "access1")
+	}
+	assert.Equal(t, calls, 1)
+
+	// expired credentials are refreshed
+	mu.Lock()
+	cache[GAR+"\x00europe-docker.pkg.dev"].creds.Expiry = time.Now()
+	mu.Unlock()
+	c, err := Get(context.Background(), GAR, "europe-docker.pkg.dev")
+	assert.NilError(t, err)
+	assert.Equal(t, c.Secret, "access2")
+
+	// obtaining the credentials of a host does not block the other hosts
+	entry := cache[GAR+"\x00europe-docker.pkg.dev"]
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	c, err = Get(context.Background(), GAR, "us-docker.pkg.dev")
+	assert.NilError(t, err)
+	assert.Equal(t, c.Secret, "access3")
+}
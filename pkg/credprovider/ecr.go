@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package credprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ecrHostRegexp matches "<ACCOUNT>.dkr.ecr[-fips].<REGION>.amazonaws.com[.cn]"
+var ecrHostRegexp = regexp.MustCompile(`^([0-9]{12})\.dkr\.(ecr(?:-fips)?)\.([a-z0-9-]+)\.(amazonaws\.com(?:\.cn)?)$`)
+
+// ecrEndpoint returns the endpoint of the ECR API; overridden in tests
+var ecrEndpoint = func(service, region, domain string) string {
+	return fmt.Sprintf("https://api.%s.%s.%s/", service, region, domain)
+}
+
+type ecrProvider struct{}
+
+// credentials calls the GetAuthorizationToken API with the credentials of the default chain of the AWS SDK
+// (the environment variables, the shared config files, SSO, the web identity, ECS, and EC2 IMDS).
+func (p *ecrProvider) credentials(ctx context.Context, host string) (*Credentials, error) {
+	m := ecrHostRegexp.FindStringSubmatch(host)
+	if m == nil {
+		return nil, fmt.Errorf("not an ECR registry (expected <ACCOUNT>.dkr.ecr.<REGION>.amazonaws.com)")
+	}
+	account, service, region, domain := m[1], m[2], m[3], m[4]
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+	awsCreds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte(fmt.Sprintf(`{"registryIds":[%q]}`, account))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ecrEndpoint(service, region, domain), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken")
+	sum := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, awsCreds, req, hex.EncodeToString(sum[:]), "ecr", region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		AuthorizationData []struct {
+			AuthorizationToken string  `json:"authorizationToken"`
+			ExpiresAt          float64 `json:"expiresAt"`
+		} `json:"authorizationData"`
+	}
+	if err := doJSON(req, &res); err != nil {
+		return nil, err
+	}
+	if len(res.AuthorizationData) == 0 {
+		return nil, fmt.Errorf("no authorization data returned")
+	}
+	data := res.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(data.AuthorizationToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the authorization token: %w", err)
+	}
+	username, secret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed authorization token")
+	}
+	sec := int64(data.ExpiresAt)
+	return &Credentials{
+		Username: username,
+		Secret:   secret,
+		Expiry:   time.Unix(sec, int64((data.ExpiresAt-float64(sec))*1e9)),
+	}, nil
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package credprovider
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcrUsername = "oauth2accesstoken"
+	googleScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+type gcrProvider struct{}
+
+// credentials obtains an access token from the application default credentials, in the order of
+// $GOOGLE_APPLICATION_CREDENTIALS, the well-known file written by `gcloud auth application-default login`,
+// and the metadata server of GCE/GKE/Cloud Run.
+func (p *gcrProvider) credentials(ctx context.Context, host string) (*Credentials, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	creds, err := google.FindDefaultCredentials(ctx, googleScope)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := creds.TokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+	expiry := tok.Expiry
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Hour)
+	}
+	return &Credentials{
+		Username: gcrUsername,
+		Secret:   tok.AccessToken,
+		Expiry:   expiry,
+	}, nil
+}
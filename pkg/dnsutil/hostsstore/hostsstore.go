@@ -84,6 +84,7 @@ type Meta struct {
 	ExtraHosts map[string]string // host:ip
 	Name       string
 	Domainname string
+	Aliases    []string
 }
 
 type Store interface {
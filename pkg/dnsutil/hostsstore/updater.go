@@ -45,6 +45,8 @@ func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{})
 		baseHostnames = append(baseHostnames, meta.Name)
 	}
 
+	baseHostnames = append(baseHostnames, meta.Aliases...)
+
 	for _, baseHostname := range baseHostnames {
 		line = append(line, baseHostname)
 		if thatNetwork != netutil.DefaultNetworkName {
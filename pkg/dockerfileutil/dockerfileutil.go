@@ -0,0 +1,212 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package dockerfileutil provides the lexer of the arguments of Dockerfile instructions.
+package dockerfileutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// LexWords processes s like the shell lexer of Dockerfile instructions such as COPY, ENV and WORKDIR:
+// quotes and escapes are removed, and variables (`$VAR`, `${VAR}`, `${VAR:-default}`, `${VAR:+alternative}`)
+// are expanded with env, except inside single quotes.
+// When split is false, s is processed as a single word.
+func LexWords(s string, env map[string]string, split bool) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+	)
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case split && unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			inWord = true
+			j := i + 1
+			for j < len(runes) && runes[j] != '\'' {
+				j++
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unexpected end of statement while looking for matching single-quote in %q", s)
+			}
+			word.WriteString(string(runes[i+1 : j]))
+			i = j
+		case c == '"':
+			inWord = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				switch {
+				case runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$", runes[i+1]):
+					i++
+					word.WriteRune(runes[i])
+				case runes[i] == '$':
+					val, n, err := expandVar(runes[i:], env)
+					if err != nil {
+						return nil, err
+					}
+					word.WriteString(val)
+					i += n - 1
+				default:
+					word.WriteRune(runes[i])
+				}
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unexpected end of statement while looking for matching double-quote in %q", s)
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+			}
+			word.WriteRune(runes[i])
+		case c == '$':
+			inWord = true
+			val, n, err := expandVar(runes[i:], env)
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(val)
+			i += n - 1
+		default:
+			inWord = true
+			word.WriteRune(c)
+		}
+	}
+	if inWord || !split {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// expandVar expands the variable reference at the beginning of s (starting with `$`).
+// Returns the value, and the number of runes consumed.
+func expandVar(s []rune, env map[string]string) (string, int, error) {
+	isNameRune := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	if len(s) < 2 {
+		return "$", 1, nil
+	}
+	if s[1] != '{' {
+		n := 1
+		for n < len(s) && isNameRune(s[n]) {
+			n++
+		}
+		if n == 1 {
+			return "$", 1, nil
+		}
+		return env[string(s[1:n])], n, nil
+	}
+	depth := 0
+	end := -1
+	for i := 1; i < len(s) && end < 0; i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return "", 0, fmt.Errorf("missing '}' in %q", string(s))
+	}
+	inner := string(s[2:end])
+	nameEnd := strings.IndexFunc(inner, func(r rune) bool { return !isNameRune(r) })
+	if nameEnd < 0 {
+		return env[inner], end + 1, nil
+	}
+	name, modifier := inner[:nameEnd], inner[nameEnd:]
+	if name == "" {
+		return "", 0, fmt.Errorf("bad substitution %q", "${"+inner+"}")
+	}
+	val, set := env[name]
+	var (
+		word   string
+		useAlt bool
+	)
+	switch {
+	case strings.HasPrefix(modifier, ":-"):
+		word, useAlt = modifier[2:], val == ""
+	case strings.HasPrefix(modifier, ":+"):
+		word, useAlt = modifier[2:], val != ""
+	case strings.HasPrefix(modifier, "-"):
+		word, useAlt = modifier[1:], !set
+	case strings.HasPrefix(modifier, "+"):
+		word, useAlt = modifier[1:], set
+	default:
+		return "", 0, fmt.Errorf("unsupported modifier %q in substitution %q", modifier, "${"+inner+"}")
+	}
+	if !useAlt {
+		if strings.HasPrefix(modifier, ":+") || strings.HasPrefix(modifier, "+") {
+			return "", end + 1, nil
+		}
+		return val, end + 1, nil
+	}
+	words, err := LexWords(word, env, false)
+	if err != nil {
+		return "", 0, err
+	}
+	return words[0], end + 1, nil
+}
+
+// ParseKeyValues parses the `KEY=VALUE KEY2="VALUE 2"` and the legacy `KEY VALUE` forms of ENV, LABEL and ARG.
+// The results are in the `KEY=VALUE` form. When allowNoValue is true (for ARG), `KEY` is returned as is.
+func ParseKeyValues(s string, env map[string]string, allowNoValue bool) ([]string, error) {
+	first := s
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		first = s[:i]
+	}
+	if !strings.Contains(first, "=") && !allowNoValue {
+		// legacy form
+		rest := strings.TrimSpace(s[len(first):])
+		if rest == "" {
+			return nil, fmt.Errorf("missing value for %q", first)
+		}
+		k, err := LexWords(first, env, false)
+		if err != nil {
+			return nil, err
+		}
+		v, err := LexWords(rest, env, false)
+		if err != nil {
+			return nil, err
+		}
+		return []string{k[0] + "=" + v[0]}, nil
+	}
+	words, err := LexWords(s, env, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range words {
+		k, _, ok := strings.Cut(w, "=")
+		if k == "" || (!ok && !allowNoValue) {
+			return nil, fmt.Errorf("expected KEY=VALUE, got %q", w)
+		}
+	}
+	return words, nil
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerfileutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLexWords(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"FOO":   "foo",
+		"EMPTY": "",
+		"SPACE": "a b",
+	}
+	testCases := []struct {
+		in    string
+		split bool
+		want  []string
+	}{
+		{in: "a  b\tc", split: true, want: []string{"a", "b", "c"}},
+		{in: "a  b", split: false, want: []string{"a  b"}},
+		{in: `"a b" 'c d' e\ f`, split: true, want: []string{"a b", "c d", "e f"}},
+		{in: `$FOO ${FOO}bar '$FOO' "$FOO" \$FOO`, split: true, want: []string{"foo", "foobar", "$FOO", "foo", "$FOO"}},
+		{in: `"$SPACE"`, split: true, want: []string{"a b"}},
+		{in: `"say \"hi\""`, split: true, want: []string{`say "hi"`}},
+		{in: "${UNSET:-x} ${EMPTY:-y} ${EMPTY-z} ${FOO:+set} ${UNSET:+set}", split: false, want: []string{"x y  set "}},
+		{in: "${UNSET:-${FOO}}", split: false, want: []string{"foo"}},
+		{in: "$ $1 100$", split: true, want: []string{"$", "", "100$"}},
+		{in: "", split: false, want: []string{""}},
+	}
+	for _, tc := range testCases {
+		got, err := LexWords(tc.in, env, tc.split)
+		assert.NilError(t, err, tc.in)
+		assert.DeepEqual(t, got, tc.want)
+	}
+
+	for _, in := range []string{`"unterminated`, `'unterminated`, "${FOO", "${FOO%bar}"} {
+		_, err := LexWords(in, env, true)
+		assert.Assert(t, err != nil, in)
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"FOO": "foo"}
+	testCases := []struct {
+		in           string
+		allowNoValue bool
+		want         []string
+		wantErr      string
+	}{
+		{in: `A=1 B="two words" C=$FOO`, want: []string{"A=1", "B=two words", "C=foo"}},
+		{in: "A legacy form with $FOO", want: []string{"A=legacy form with foo"}},
+		{in: "A=", want: []string{"A="}},
+		{in: "A", wantErr: "missing value"},
+		{in: "A=1 B", wantErr: "expected KEY=VALUE"},
+		{in: "A B=1", allowNoValue: true, want: []string{"A", "B=1"}},
+	}
+	for _, tc := range testCases {
+		got, err := ParseKeyValues(tc.in, env, tc.allowNoValue)
+		if tc.wantErr != "" {
+			assert.ErrorContains(t, err, tc.wantErr, tc.in)
+			continue
+		}
+		assert.NilError(t, err, tc.in)
+		assert.DeepEqual(t, got, tc.want)
+	}
+}
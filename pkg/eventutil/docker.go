@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"fmt"
+	"strings"
+
+	eventtypes "github.com/containerd/containerd/api/events"
+)
+
+// Types of the Docker-compatible events
+const (
+	TypeContainer = "container"
+	TypeImage     = "image"
+	TypeVolume    = "volume"
+)
+
+// ContainerEventAction converts a containerd event into the container ID and the Docker-compatible action name.
+// An empty ID is returned for events that are not related to the lifecycle of a container.
+func ContainerEventAction(v any) (id, action string, attributes map[string]string) {
+	switch ev := v.(type) {
+	case *eventtypes.ContainerCreate:
+		return ev.ID, "create", nil
+	case *eventtypes.ContainerUpdate:
+		return ev.ID, "update", nil
+	case *eventtypes.ContainerDelete:
+		return ev.ID, "destroy", nil
+	case *eventtypes.TaskStart:
+		return ev.ContainerID, "start", nil
+	case *eventtypes.TaskExit:
+		// Ignore the exit of exec processes
+		if ev.ID != ev.ContainerID {
+			return "", "", nil
+		}
+		return ev.ContainerID, "die", map[string]string{"exitCode": fmt.Sprint(ev.ExitStatus)}
+	case *eventtypes.TaskOOM:
+		return ev.ContainerID, "oom", nil
+	case *eventtypes.TaskPaused:
+		return ev.ContainerID, "pause", nil
+	case *eventtypes.TaskResumed:
+		return ev.ContainerID, "unpause", nil
+	case *eventtypes.TaskExecAdded:
+		return ev.ContainerID, "exec_create", map[string]string{"execID": ev.ExecID}
+	case *eventtypes.TaskExecStarted:
+		return ev.ContainerID, "exec_start", map[string]string{"execID": ev.ExecID}
+	case *eventtypes.TaskCheckpointed:
+		return ev.ContainerID, "checkpoint", nil
+	case *HealthStatusEvent:
+		return ev.ContainerID, "health_status: " + ev.Status, nil
+	}
+	return "", "", nil
+}
+
+// DockerEvent converts a containerd event into the type, the actor ID, and the action of a Docker-compatible event.
+//
+// The events of containers, images, and volumes are converted into the Docker event types.
+// The other events (e.g., "/snapshot/prepare", or "/tasks/exit" of an exec process) are converted into the type
+// and the action named after the topic (e.g., "snapshot" and "prepare"), with an empty actor ID.
+func DockerEvent(topic string, v any) (typ, id, action string, attributes map[string]string) {
+	if id, action, attributes = ContainerEventAction(v); id != "" {
+		return TypeContainer, id, action, attributes
+	}
+	switch ev := v.(type) {
+	case *eventtypes.ImageCreate:
+		return TypeImage, ev.Name, "create", ev.Labels
+	case *eventtypes.ImageUpdate:
+		return TypeImage, ev.Name, "update", ev.Labels
+	case *eventtypes.ImageDelete:
+		return TypeImage, ev.Name, "delete", nil
+	case *VolumeEvent:
+		attributes = map[string]string{"driver": ev.Driver}
+		if ev.ContainerID != "" {
+			attributes["container"] = ev.ContainerID
+		}
+		return TypeVolume, ev.Name, strings.TrimPrefix(topic, "/volumes/"), attributes
+	}
+	typ, action, _ = strings.Cut(strings.TrimPrefix(topic, "/"), "/")
+	return strings.TrimSuffix(typ, "s"), "", action, nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+)
+
+// HealthStatusTopic is the topic of the health status events
+const HealthStatusTopic = "/containers/health_status"
+
+// HealthStatusEvent is published on HealthStatusTopic when the health status of a container changes.
+type HealthStatusEvent struct {
+	ContainerID string `json:"container_id"`
+	// Status is one of "starting", "healthy", or "unhealthy"
+	Status string `json:"status"`
+}
+
+func init() {
+	typeurl.Register(&HealthStatusEvent{}, "github.com/containerd/nerdctl/v2/pkg/eventutil", "HealthStatusEvent")
+}
+
+// PublishHealthStatusEvent publishes a health status event to containerd.
+// A failure is only logged, as the event is only informational.
+func PublishHealthStatusEvent(ctx context.Context, client *containerd.Client, event *HealthStatusEvent) {
+	if err := client.EventService().Publish(ctx, HealthStatusTopic, event); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to publish the health status event of container %q", event.ContainerID)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/typeurl/v2"
+)
+
+// Topics of the volume events
+const (
+	VolumeCreateTopic  = "/volumes/create"
+	VolumeMountTopic   = "/volumes/mount"
+	VolumeUnmountTopic = "/volumes/unmount"
+	VolumeDestroyTopic = "/volumes/destroy"
+)
+
+// VolumeEvent is published on the volume topics.
+// The volumes are mounted when a container using them is created, and unmounted when the container is removed.
+type VolumeEvent struct {
+	Name   string `json:"name"`
+	Driver string `json:"driver"`
+	// ContainerID is the container mounting or unmounting the volume, or the one creating or destroying
+	// the anonymous volume
+	ContainerID string `json:"container_id,omitempty"`
+}
+
+func init() {
+	typeurl.Register(&VolumeEvent{}, "github.com/containerd/nerdctl/v2/pkg/eventutil", "VolumeEvent")
+}
+
+// PublishVolumeEvent publishes a volume event to containerd.
+// A failure is only logged, as the event is only informational.
+func PublishVolumeEvent(ctx context.Context, client *containerd.Client, topic string, event *VolumeEvent) {
+	if event.Driver == "" {
+		event.Driver = "local"
+	}
+	if err := client.EventService().Publish(ctx, topic, event); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to publish the %s event of volume %q", topic, event.Name)
+	}
+}
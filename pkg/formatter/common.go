@@ -22,11 +22,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"text/tabwriter"
 	"text/template"
+	"text/template/parse"
+	"unicode"
 
 	"github.com/docker/cli/templates"
 )
 
+const tableFormatPrefix = "table "
+
 // Flusher is implemented by text/tabwriter.Writer
 type Flusher interface {
 	Flush() error
@@ -112,3 +118,101 @@ func ParseTemplate(format string) (*template.Template, error) {
 	}
 	return templates.Parse(format)
 }
+
+// IsTableFormat returns true for `--format="table TEMPLATE"`.
+func IsTableFormat(format string) bool {
+	return strings.HasPrefix(format, tableFormatPrefix)
+}
+
+// ParseTableTemplate parses `--format="table TEMPLATE"` in the same way as Docker, and returns the template of the rows
+// and the header row.
+//
+// `\t` and `\n` in TEMPLATE are unescaped. The header of each action is looked up in headers by the name of its field
+// (e.g., "ID" for `{{.ID}}`), or derived from the name (e.g., "CREATED AT" for `{{.CreatedAt}}`).
+func ParseTableTemplate(format string, headers map[string]string) (*template.Template, string, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(strings.TrimPrefix(format, tableFormatPrefix))
+	tmpl, err := ParseTemplate(format)
+	if err != nil {
+		return nil, "", err
+	}
+	var header strings.Builder
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			header.Write(n.Text)
+		case *parse.ActionNode:
+			header.WriteString(tableHeader(n.Pipe, headers))
+		case *parse.IfNode:
+			header.WriteString(tableHeader(n.Pipe, headers))
+		case *parse.RangeNode:
+			header.WriteString(tableHeader(n.Pipe, headers))
+		case *parse.WithNode:
+			header.WriteString(tableHeader(n.Pipe, headers))
+		}
+	}
+	return tmpl, strings.TrimRight(header.String(), "\n"), nil
+}
+
+// ParseListTemplate parses `--format` of the list commands: `json`, `table TEMPLATE`, or TEMPLATE.
+//
+// For `table TEMPLATE`, w is wrapped with a tabwriter and the header row is printed, unless quiet.
+// The caller has to flush the returned writer, if it implements Flusher.
+func ParseListTemplate(w io.Writer, format string, headers map[string]string, quiet bool) (io.Writer, *template.Template, error) {
+	if !IsTableFormat(format) {
+		tmpl, err := ParseTemplate(format)
+		return w, tmpl, err
+	}
+	tmpl, header, err := ParseTableTemplate(format, headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	tw := tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	if !quiet {
+		fmt.Fprintln(tw, header)
+	}
+	return tw, tmpl, nil
+}
+
+// tableHeader returns the header of the first field referred by pipe.
+func tableHeader(pipe *parse.PipeNode, headers map[string]string) string {
+	if pipe == nil {
+		return ""
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			var ident []string
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				ident = a.Ident
+			case *parse.ChainNode:
+				ident = a.Field
+			}
+			if len(ident) == 0 {
+				continue
+			}
+			name := ident[len(ident)-1]
+			if h, ok := headers[name]; ok {
+				return h
+			}
+			return headerFromFieldName(name)
+		}
+	}
+	return ""
+}
+
+// headerFromFieldName converts a field name to a header, e.g., "CreatedAt" to "CREATED AT", "CPUPerc" to "CPU PERC".
+func headerFromFieldName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])
+			if prevLower || nextLower {
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -17,6 +17,8 @@
 package formatter
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -192,3 +194,64 @@ func TestFormatPorts(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTableTemplate(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		ID        string
+		CreatedAt string
+		CPUPerc   string
+	}
+	x := item{ID: "abc", CreatedAt: "now", CPUPerc: "1%"}
+
+	tests := []struct {
+		format         string
+		expectedHeader string
+		expectedRow    string
+	}{
+		{
+			format:         `table {{.ID}}\t{{.CreatedAt}}`,
+			expectedHeader: "CONTAINER ID\tCREATED AT",
+			expectedRow:    "abc\tnow",
+		},
+		{
+			format:         "table {{.CPUPerc}}: {{json .ID}}",
+			expectedHeader: "CPU PERC: CONTAINER ID",
+			expectedRow:    `1%: "abc"`,
+		},
+		{
+			format:         `table {{if .ID}}{{.ID}}{{end}}`,
+			expectedHeader: "CONTAINER ID",
+			expectedRow:    "abc",
+		},
+	}
+	for _, tc := range tests {
+		tmpl, header, err := ParseTableTemplate(tc.format, map[string]string{"ID": "CONTAINER ID"})
+		assert.NilError(t, err)
+		assert.Equal(t, header, tc.expectedHeader)
+		var b strings.Builder
+		assert.NilError(t, tmpl.Execute(&b, x))
+		assert.Equal(t, b.String(), tc.expectedRow)
+	}
+}
+
+func TestParseListTemplate(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	w, tmpl, err := ParseListTemplate(&b, `table {{.Name}}\t{{.Driver}}`, map[string]string{"Name": "VOLUME NAME"}, false)
+	assert.NilError(t, err)
+	for _, v := range []map[string]string{{"Name": "foo", "Driver": "local"}, {"Name": "barbaz", "Driver": "local"}} {
+		assert.NilError(t, tmpl.Execute(w, v))
+		fmt.Fprintln(w)
+	}
+	assert.NilError(t, w.(Flusher).Flush())
+	assert.Equal(t, b.String(), "VOLUME NAME    DRIVER\nfoo            local\nbarbaz         local\n")
+
+	b.Reset()
+	w, tmpl, err = ParseListTemplate(&b, "json", nil, false)
+	assert.NilError(t, err)
+	assert.NilError(t, tmpl.Execute(w, map[string]string{"Name": "foo"}))
+	assert.Equal(t, b.String(), `{"Name":"foo"}`)
+}
@@ -29,11 +29,13 @@ import (
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/eventutil"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 )
 
-// ExecuteHealthCheck executes the health check command for a container
-func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container containerd.Container, hc *Healthcheck) error {
+// ExecuteHealthCheck executes the health check command for a container.
+// A health_status event is published when the health status of the container changes.
+func ExecuteHealthCheck(ctx context.Context, client *containerd.Client, task containerd.Task, container containerd.Container, hc *Healthcheck) error {
 	// Prepare process spec for health check command
 	processSpec, err := prepareProcessSpec(ctx, container, hc)
 	if err != nil {
@@ -46,7 +48,7 @@ func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container con
 	startTime := time.Now()
 	result, err := probeHealthCheck(ctx, task, hc, processSpec)
 	if err != nil {
-		_ = updateHealthStatus(ctx, container, hc, &HealthcheckResult{
+		_ = updateHealthStatus(ctx, client, container, hc, &HealthcheckResult{
 			Start:    startTime,
 			End:      time.Now(),
 			ExitCode: -1,
@@ -57,7 +59,7 @@ func ExecuteHealthCheck(ctx context.Context, task containerd.Task, container con
 
 	// Success case, update health status
 	result.Start = startTime
-	if err := updateHealthStatus(ctx, container, hc, result); err != nil {
+	if err := updateHealthStatus(ctx, client, container, hc, result); err != nil {
 		return fmt.Errorf("failed to update health status: %w", err)
 	}
 	return nil
@@ -118,12 +120,16 @@ func probeHealthCheck(ctx context.Context, task containerd.Task, hc *Healthcheck
 }
 
 // updateHealthStatus updates the health status based on the health check result
-func updateHealthStatus(ctx context.Context, container containerd.Container, hcConfig *Healthcheck, hcResult *HealthcheckResult) error {
+func updateHealthStatus(ctx context.Context, client *containerd.Client, container containerd.Container, hcConfig *Healthcheck, hcResult *HealthcheckResult) error {
 	// Get current health state from labels
 	currentHealth, err := readHealthStateFromLabels(ctx, container)
 	if err != nil {
 		return fmt.Errorf("failed to read health state from labels: %w", err)
 	}
+	var previousStatus HealthStatus
+	if currentHealth != nil {
+		previousStatus = currentHealth.Status
+	}
 	if currentHealth == nil {
 		// Determine if we should start in the start period workflow
 		hasStartPeriod := hcConfig.StartPeriod > 0
@@ -174,6 +180,13 @@ func updateHealthStatus(ctx context.Context, container containerd.Container, hcC
 		return fmt.Errorf("failed to write health state to labels: %w", err)
 	}
 
+	if currentHealth.Status != previousStatus {
+		eventutil.PublishHealthStatusEvent(ctx, client, &eventutil.HealthStatusEvent{
+			ContainerID: container.ID(),
+			Status:      currentHealth.Status,
+		})
+	}
+
 	// Store the latest health check result in the log file
 	if err := writeHealthLog(ctx, container, hcResult); err != nil {
 		return fmt.Errorf("failed to write health log: %w", err)
@@ -34,6 +34,11 @@ func StartTimer(ctx context.Context, container containerd.Container, cfg *config
 	return nil
 }
 
+// IsTimerManaged returns whether the healthcheck is run by the transient systemd timer created by CreateTimer.
+func IsTimerManaged(hc *Healthcheck, cfg *config.Config) bool {
+	return false
+}
+
 // RemoveTransientHealthCheckFiles stops and cleans up the transient timer and service.
 func RemoveTransientHealthCheckFiles(ctx context.Context, container containerd.Container) error {
 	return nil
@@ -258,6 +258,11 @@ func extractHealthcheck(ctx context.Context, container containerd.Container) *He
 	return hc
 }
 
+// IsTimerManaged returns whether the healthcheck is run by the transient systemd timer created by CreateTimer.
+func IsTimerManaged(hc *Healthcheck, cfg *config.Config) bool {
+	return !shouldSkipHealthCheckSystemd(hc, cfg)
+}
+
 // shouldSkipHealthCheckSystemd determines if healthcheck timers should be skipped.
 func shouldSkipHealthCheckSystemd(hc *Healthcheck, cfg *config.Config) bool {
 	// Don't proceed if systemd is unavailable or disabled
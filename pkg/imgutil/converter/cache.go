@@ -0,0 +1,180 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images/converter"
+	"github.com/containerd/containerd/v2/pkg/labels"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+)
+
+const (
+	// layerCacheLabelPrefix is the prefix of the content labels of the source blobs
+	// that record the descriptors of the converted blobs.
+	layerCacheLabelPrefix = "nerdctl/convert."
+	// layerCacheGCLabelPrefix is the prefix of the content labels of the source blobs
+	// that protect the converted blobs from the garbage collection.
+	layerCacheGCLabelPrefix = "containerd.io/gc.ref.content.nerdctl.convert."
+)
+
+// LayerConvertFuncWithParallelism limits the number of the layers converted by convertFunc at the same time.
+// The converter of containerd converts all the layers of the manifests in parallel.
+// parallelism <= 0 defaults to the number of CPUs.
+func LayerConvertFuncWithParallelism(convertFunc converter.ConvertFunc, parallelism int) converter.ConvertFunc {
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, parallelism)
+	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-sem }()
+		return convertFunc(ctx, cs, desc)
+	}
+}
+
+// LayerCache records the blobs converted by a layer convert func in the labels of their source blobs,
+// so that an interrupted conversion can be resumed without converting the layers again.
+//
+// The converted blobs are kept by the garbage collection labels as long as the source blobs exist,
+// until Clear is called after the conversion succeeds.
+type LayerCache struct {
+	key string
+
+	mu      sync.Mutex
+	sources map[digest.Digest]struct{}
+}
+
+// NewLayerCache returns a LayerCache keyed by the conversion type and its options.
+// Conversions with different types or options do not share the cached blobs.
+func NewLayerCache(convertType string, options any) (*LayerCache, error) {
+	b, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	return &LayerCache{
+		key:     convertType + "." + digest.FromBytes(b).Encoded()[:16],
+		sources: make(map[digest.Digest]struct{}),
+	}, nil
+}
+
+func (c *LayerCache) labelKey() string {
+	return layerCacheLabelPrefix + c.key
+}
+
+func (c *LayerCache) gcLabelKey() string {
+	return layerCacheGCLabelPrefix + c.key
+}
+
+// LayerConvertFunc wraps convertFunc to reuse the blobs converted by an interrupted conversion.
+func (c *LayerCache) LayerConvertFunc(convertFunc converter.ConvertFunc) converter.ConvertFunc {
+	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		if newDesc := c.lookup(ctx, cs, desc); newDesc != nil {
+			log.G(ctx).Debugf("reusing %s converted from %s", newDesc.Digest, desc.Digest)
+			return newDesc, nil
+		}
+		newDesc, err := convertFunc(ctx, cs, desc)
+		if err != nil || newDesc == nil || newDesc.Digest == desc.Digest {
+			return newDesc, err
+		}
+		if err := c.store(ctx, cs, desc, *newDesc); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to record %s converted from %s", newDesc.Digest, desc.Digest)
+		}
+		return newDesc, nil
+	}
+}
+
+func (c *LayerCache) lookup(ctx context.Context, cs content.Store, desc ocispec.Descriptor) *ocispec.Descriptor {
+	info, err := cs.Info(ctx, desc.Digest)
+	if err != nil {
+		return nil
+	}
+	v, ok := info.Labels[c.labelKey()]
+	if !ok {
+		return nil
+	}
+	var newDesc ocispec.Descriptor
+	if err := json.Unmarshal([]byte(v), &newDesc); err != nil {
+		log.G(ctx).WithError(err).Debugf("ignoring invalid label %q of %s", c.labelKey(), desc.Digest)
+		return nil
+	}
+	// The converted blob may have been removed by `ctr content rm`
+	newInfo, err := cs.Info(ctx, newDesc.Digest)
+	if err != nil || newInfo.Size != newDesc.Size {
+		return nil
+	}
+	c.record(desc.Digest)
+	return &newDesc
+}
+
+func (c *LayerCache) store(ctx context.Context, cs content.Store, desc, newDesc ocispec.Descriptor) error {
+	b, err := json.Marshal(newDesc)
+	if err != nil {
+		return err
+	}
+	if err := labels.Validate(c.labelKey(), string(b)); err != nil {
+		// e.g., too many annotations; the layer will be converted again on retry
+		return nil
+	}
+	info := content.Info{
+		Digest: desc.Digest,
+		Labels: map[string]string{
+			c.labelKey():   string(b),
+			c.gcLabelKey(): newDesc.Digest.String(),
+		},
+	}
+	if _, err := cs.Update(ctx, info, "labels."+c.labelKey(), "labels."+c.gcLabelKey()); err != nil {
+		return err
+	}
+	c.record(desc.Digest)
+	return nil
+}
+
+func (c *LayerCache) record(dgst digest.Digest) {
+	c.mu.Lock()
+	c.sources[dgst] = struct{}{}
+	c.mu.Unlock()
+}
+
+// Clear removes the labels recorded by the conversion, so that the converted blobs
+// are only kept by the converted image.
+func (c *LayerCache) Clear(ctx context.Context, cs content.Store) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for dgst := range c.sources {
+		info := content.Info{Digest: dgst}
+		if _, err := cs.Update(ctx, info, "labels."+c.labelKey(), "labels."+c.gcLabelKey()); err != nil && !errdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to clear the conversion cache of %s: %w", dgst, err)
+		}
+		delete(c.sources, dgst)
+	}
+	return nil
+}
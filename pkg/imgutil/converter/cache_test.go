@@ -0,0 +1,155 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+)
+
+type memoryLabelStore struct {
+	mu     sync.Mutex
+	labels map[digest.Digest]map[string]string
+}
+
+func (s *memoryLabelStore) Get(dgst digest.Digest) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.labels[dgst], nil
+}
+
+func (s *memoryLabelStore) Set(dgst digest.Digest, labels map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels[dgst] = labels
+	return nil
+}
+
+func (s *memoryLabelStore) Update(dgst digest.Digest, update map[string]string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	labels := s.labels[dgst]
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range update {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	s.labels[dgst] = labels
+	return labels, nil
+}
+
+func writeBlob(t *testing.T, cs content.Store, mediaType string, b []byte) ocispec.Descriptor {
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(b),
+		Size:      int64(len(b)),
+	}
+	assert.NilError(t, content.WriteBlob(context.Background(), cs, desc.Digest.String(), bytes.NewReader(b), desc))
+	return desc
+}
+
+func TestLayerCache(t *testing.T) {
+	ctx := context.Background()
+	cs, err := local.NewLabeledStore(t.TempDir(), &memoryLabelStore{labels: make(map[digest.Digest]map[string]string)})
+	assert.NilError(t, err)
+	src := writeBlob(t, cs, ocispec.MediaTypeImageLayerGzip, []byte("source"))
+
+	var calls int
+	convertFunc := func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		calls++
+		newDesc := writeBlob(t, cs, ocispec.MediaTypeImageLayerZstd, []byte("converted"))
+		return &newDesc, nil
+	}
+
+	cache, err := NewLayerCache("zstd", map[string]int{"level": 3})
+	assert.NilError(t, err)
+	newDesc, err := cache.LayerConvertFunc(convertFunc)(ctx, cs, src)
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 1)
+	info, err := cs.Info(ctx, src.Digest)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Labels[cache.gcLabelKey()], newDesc.Digest.String())
+
+	// Simulates retrying an interrupted conversion
+	cache, err = NewLayerCache("zstd", map[string]int{"level": 3})
+	assert.NilError(t, err)
+	cached, err := cache.LayerConvertFunc(convertFunc)(ctx, cs, src)
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 1)
+	assert.DeepEqual(t, cached, newDesc)
+
+	// Different options do not share the cache
+	other, err := NewLayerCache("zstd", map[string]int{"level": 19})
+	assert.NilError(t, err)
+	_, err = other.LayerConvertFunc(convertFunc)(ctx, cs, src)
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 2)
+
+	assert.NilError(t, cache.Clear(ctx, cs))
+	info, err = cs.Info(ctx, src.Digest)
+	assert.NilError(t, err)
+	_, ok := info.Labels[cache.labelKey()]
+	assert.Equal(t, ok, false)
+	_, ok = info.Labels[cache.gcLabelKey()]
+	assert.Equal(t, ok, false)
+	_, ok = info.Labels[other.gcLabelKey()]
+	assert.Equal(t, ok, true)
+}
+
+func TestLayerConvertFuncWithParallelism(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	convertFunc := func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil, nil
+	}
+	fn := LayerConvertFuncWithParallelism(convertFunc, 2)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fn(context.Background(), nil, ocispec.Descriptor{})
+			assert.Check(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Check(t, maxRunning.Load() <= 2)
+}
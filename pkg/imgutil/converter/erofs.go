@@ -0,0 +1,133 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/converter"
+	"github.com/containerd/containerd/v2/core/images/converter/uncompress"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/containerd/v2/pkg/labels"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// MediaTypeErofsLayer is the media type of the native EROFS layers.
+// The EROFS differ of containerd applies the layers of which media type ends with ".erofs" as is, without conversion.
+const MediaTypeErofsLayer = "application/vnd.oci.image.layer.v1.erofs"
+
+// IsErofsMediaType returns true for the media types of the native EROFS layers.
+func IsErofsMediaType(mt string) bool {
+	mediaType, _, hasExt := strings.Cut(mt, "+")
+	return !hasExt && images.IsLayerType(mediaType) && strings.HasSuffix(mediaType, ".erofs")
+}
+
+// ErofsLayerConvertFunc converts tar(.gz|.zst) layers into native EROFS layers with `mkfs.erofs --tar=f`,
+// in the same way as the EROFS differ of containerd applies the layers.
+func ErofsLayerConvertFunc(options types.ImageConvertOptions) (converter.ConvertFunc, error) {
+	if _, err := exec.LookPath("mkfs.erofs"); err != nil {
+		return nil, fmt.Errorf("mkfs.erofs (erofs-utils 1.7 or later) is required for --erofs: %w", err)
+	}
+	return func(ctx context.Context, cs content.Store, desc ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		if !images.IsLayerType(desc.MediaType) || IsErofsMediaType(desc.MediaType) {
+			// No conversion. No need to return an error here.
+			return nil, nil
+		}
+		readerAt, err := cs.ReaderAt(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		defer readerAt.Close()
+		var tarReader io.Reader = io.NewSectionReader(readerAt, 0, desc.Size)
+		if !uncompress.IsUncompressedType(desc.MediaType) {
+			decompStream, err := compression.DecompressStream(tarReader)
+			if err != nil {
+				return nil, err
+			}
+			defer decompStream.Close()
+			tarReader = decompStream
+		}
+
+		dir, err := os.MkdirTemp("", "nerdctl-erofs-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		blob := filepath.Join(dir, "layer.erofs")
+		// The UUID is derived from the source digest, for reproducible layers
+		u := uuid.NewSHA1(uuid.NameSpaceURL, []byte("erofs:blobs/"+desc.Digest))
+		args := append([]string{"--tar=f", "--aufs", "--quiet", "-Enoinline_data", "-U", u.String()}, options.ErofsMkfsOptions...)
+		args = append(args, blob)
+		cmd := exec.CommandContext(ctx, "mkfs.erofs", args...)
+		cmd.Stdin = tarReader
+		log.G(ctx).Debugf("running %v", cmd.Args)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to convert layer %s to EROFS with %v: %s: %w", desc.Digest, cmd.Args, strings.TrimSpace(string(out)), err)
+		}
+
+		f, err := os.Open(blob)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		ref := fmt.Sprintf("convert-erofs-from-%s", desc.Digest)
+		w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+		if err != nil {
+			return nil, err
+		}
+		defer w.Close()
+
+		// Reset the writing position
+		// Old writer possibly remains without aborted
+		// (e.g. conversion interrupted by a signal)
+		if err := w.Truncate(0); err != nil {
+			return nil, err
+		}
+		n, err := io.Copy(w, f)
+		if err != nil {
+			return nil, err
+		}
+		// The EROFS blob is applied as is, so it is the "uncompressed" content of the layer (i.e., the diff ID)
+		if err = w.Commit(ctx, 0, "", content.WithLabels(map[string]string{
+			labels.LabelUncompressed: w.Digest().String(),
+		})); err != nil && !errdefs.IsAlreadyExists(err) {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		newDesc := desc
+		newDesc.Digest = w.Digest()
+		newDesc.Size = n
+		newDesc.MediaType = MediaTypeErofsLayer
+		return &newDesc, nil
+	}, nil
+}
@@ -0,0 +1,158 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/converter"
+)
+
+// ConvertOCIMediaTypeToDocker converts an OCI media type string to the equivalent Docker one.
+// It is the reverse of converter.ConvertDockerMediaTypeToOCI.
+// An error is returned for OCI media types that do not have a Docker counterpart.
+func ConvertOCIMediaTypeToDocker(mt string) (string, error) {
+	switch mt {
+	case ocispec.MediaTypeImageIndex:
+		return images.MediaTypeDockerSchema2ManifestList, nil
+	case ocispec.MediaTypeImageManifest:
+		return images.MediaTypeDockerSchema2Manifest, nil
+	case ocispec.MediaTypeImageLayerGzip:
+		return images.MediaTypeDockerSchema2LayerGzip, nil
+	case ocispec.MediaTypeImageLayerNonDistributableGzip: //nolint:staticcheck // deprecated
+		return images.MediaTypeDockerSchema2LayerForeignGzip, nil
+	case ocispec.MediaTypeImageLayer:
+		return images.MediaTypeDockerSchema2Layer, nil
+	case ocispec.MediaTypeImageLayerNonDistributable: //nolint:staticcheck // deprecated
+		return images.MediaTypeDockerSchema2LayerForeign, nil
+	case ocispec.MediaTypeImageLayerZstd:
+		return images.MediaTypeDockerSchema2LayerZstd, nil
+	case ocispec.MediaTypeImageConfig:
+		return images.MediaTypeDockerSchema2Config, nil
+	case ocispec.MediaTypeImageLayerNonDistributableZstd: //nolint:staticcheck // deprecated
+		return "", fmt.Errorf("media type %q has no Docker equivalent", mt)
+	default:
+		if images.IsDockerType(mt) {
+			return mt, nil
+		}
+		if images.IsLayerType(mt) {
+			return "", fmt.Errorf("media type %q has no Docker equivalent", mt)
+		}
+		return mt, nil
+	}
+}
+
+// OCIToDockerConvertHookFunc returns a hook for converter.IndexConvertFuncWithHook that rewrites OCI media types
+// of the converted blobs into Docker media types.
+//
+// Docker media types do not support annotations, so annotations of manifests, indexes and their descriptors are dropped.
+func OCIToDockerConvertHookFunc() converter.ConvertHookFunc {
+	return func(ctx context.Context, cs content.Store, orgDesc ocispec.Descriptor, newDesc *ocispec.Descriptor) (*ocispec.Descriptor, error) {
+		desc := orgDesc
+		if newDesc != nil {
+			desc = *newDesc
+		}
+		if images.IsDockerType(desc.MediaType) && len(desc.Annotations) == 0 {
+			return newDesc, nil
+		}
+		mt, err := ConvertOCIMediaTypeToDocker(desc.MediaType)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case images.IsManifestType(desc.MediaType):
+			var manifest ocispec.Manifest
+			labels, err := readJSON(ctx, cs, &manifest, desc)
+			if err != nil {
+				return nil, err
+			}
+			manifest.MediaType = mt
+			manifest.Annotations = nil
+			manifest.Config.Annotations = nil
+			if manifest.Config.MediaType, err = ConvertOCIMediaTypeToDocker(manifest.Config.MediaType); err != nil {
+				return nil, err
+			}
+			for i := range manifest.Layers {
+				manifest.Layers[i].Annotations = nil
+				if manifest.Layers[i].MediaType, err = ConvertOCIMediaTypeToDocker(manifest.Layers[i].MediaType); err != nil {
+					return nil, err
+				}
+			}
+			return writeJSON(ctx, cs, &manifest, desc, mt, labels)
+		case images.IsIndexType(desc.MediaType):
+			var index ocispec.Index
+			labels, err := readJSON(ctx, cs, &index, desc)
+			if err != nil {
+				return nil, err
+			}
+			index.MediaType = mt
+			index.Annotations = nil
+			for i := range index.Manifests {
+				index.Manifests[i].Annotations = nil
+				if index.Manifests[i].MediaType, err = ConvertOCIMediaTypeToDocker(index.Manifests[i].MediaType); err != nil {
+					return nil, err
+				}
+			}
+			return writeJSON(ctx, cs, &index, desc, mt, labels)
+		}
+		converted := desc
+		converted.MediaType = mt
+		converted.Annotations = nil
+		return &converted, nil
+	}
+}
+
+func readJSON(ctx context.Context, cs content.Store, x any, desc ocispec.Descriptor) (map[string]string, error) {
+	info, err := cs.Info(ctx, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	b, err := content.ReadBlob(ctx, cs, desc)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, x); err != nil {
+		return nil, err
+	}
+	return info.Labels, nil
+}
+
+func writeJSON(ctx context.Context, cs content.Store, x any, oldDesc ocispec.Descriptor, mediaType string, labels map[string]string) (*ocispec.Descriptor, error) {
+	b, err := json.Marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	dgst := digest.SHA256.FromBytes(b)
+	ref := fmt.Sprintf("converter-write-json-%s", dgst.String())
+	if err := content.WriteBlob(ctx, cs, ref, bytes.NewReader(b), ocispec.Descriptor{Size: int64(len(b)), Digest: dgst}, content.WithLabels(labels)); err != nil {
+		return nil, err
+	}
+	newDesc := oldDesc
+	newDesc.MediaType = mediaType
+	newDesc.Annotations = nil
+	newDesc.Size = int64(len(b))
+	newDesc.Digest = dgst
+	return &newDesc, nil
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/errdefs"
+
+	ncconfig "github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+// WithRegistries specifies the [registries] tables of nerdctl.toml, for the TLS client certificates and the credential providers of the registries.
+func WithRegistries(registries map[string]ncconfig.RegistryConfig) Opt {
+	return func(o *opts) {
+		o.registries = registries
+	}
+}
+
+// clientCertificates loads the TLS client certificates of the registry, from the [registries] tables of nerdctl.toml
+// and from the Docker-style certificate files (e.g., "/etc/docker/certs.d/<HOST>/client.{cert,key}").
+//
+// Unlike containerd, the certificate files are loaded from every hosts directory, even when a directory has a hosts.toml,
+// so that mTLS keeps working when the hosts.toml of the registry is in another directory than its certificates.
+func clientCertificates(refHostname string, hostsDirs []string, registries map[string]ncconfig.RegistryConfig) ([]tls.Certificate, error) {
+	regURL, err := Parse(refHostname)
+	if err != nil {
+		return nil, err
+	}
+	// Docker inconsistencies handling: see NewHostOptions
+	if regURL.Hostname() == "index.docker.io" {
+		regURL.Host = "docker.io:" + StandardHTTPSPort
+	}
+
+	var pairs [][2]string
+	for _, host := range slices.Sorted(maps.Keys(registries)) {
+		reg := registries[host]
+		u, err := Parse(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry %q in the [registries] tables: %w", host, err)
+		}
+		if u.Host != regURL.Host || reg.ClientCert == "" {
+			continue
+		}
+		key := reg.ClientKey
+		if key == "" {
+			key = reg.ClientCert
+		}
+		pairs = append(pairs, [2]string{reg.ClientCert, key})
+	}
+
+	for _, hostsDir := range validateDirectories(hostsDirs) {
+		dir, err := config.HostDirFromRoot(hostsDir)(regURL.Host)
+		if errdefs.IsNotFound(err) && regURL.Port() == StandardHTTPSPort {
+			dir, err = config.HostDirFromRoot(hostsDir)(regURL.Hostname())
+		}
+		if errdefs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".cert") {
+				continue
+			}
+			cert := filepath.Join(dir, e.Name())
+			key := strings.TrimSuffix(cert, ".cert") + ".key"
+			if _, err := os.Stat(key); os.IsNotExist(err) {
+				key = cert
+			}
+			pairs = append(pairs, [2]string{cert, key})
+		}
+	}
+
+	var certs []tls.Certificate
+	for _, pair := range pairs {
+		cert, err := tls.LoadX509KeyPair(pair[0], pair[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate %q of %q: %w", pair[0], refHostname, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	ncconfig "github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+// writeClientCertificate writes a self-signed client certificate and its key to certFile and keyFile.
+func writeClientCertificate(t *testing.T, certFile, keyFile, cn string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(filepath.Dir(certFile), 0o755))
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func TestClientCertificates(t *testing.T) {
+	containerdDir := filepath.Join(t.TempDir(), "containerd", "certs.d")
+	dockerDir := filepath.Join(t.TempDir(), "docker", "certs.d")
+	configDir := t.TempDir()
+
+	// The hosts.toml of containerd does not prevent the certificates of docker from being loaded
+	assert.NilError(t, os.MkdirAll(filepath.Join(containerdDir, "registry.example.com"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(containerdDir, "registry.example.com", "hosts.toml"), []byte(`server = "https://registry.example.com"`), 0o644))
+	writeClientCertificate(t, filepath.Join(dockerDir, "registry.example.com", "client.cert"), filepath.Join(dockerDir, "registry.example.com", "client.key"), "docker")
+	writeClientCertificate(t, filepath.Join(dockerDir, "other.example.com", "client.cert"), filepath.Join(dockerDir, "other.example.com", "client.key"), "other")
+	writeClientCertificate(t, filepath.Join(configDir, "client.cert"), filepath.Join(configDir, "client.key"), "nerdctl")
+
+	registries := map[string]ncconfig.RegistryConfig{
+		"registry.example.com:443": {
+			ClientCert: filepath.Join(configDir, "client.cert"),
+			ClientKey:  filepath.Join(configDir, "client.key"),
+		},
+		"registry.example.com:5000": {
+			ClientCert: filepath.Join(configDir, "missing.cert"),
+		},
+	}
+
+	certs, err := clientCertificates("registry.example.com", []string{containerdDir, dockerDir}, registries)
+	assert.NilError(t, err)
+	var names []string
+	for _, cert := range certs {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		assert.NilError(t, err)
+		names = append(names, leaf.Subject.CommonName)
+	}
+	assert.DeepEqual(t, names, []string{"nerdctl", "docker"})
+
+	certs, err = clientCertificates("unknown.example.com", []string{containerdDir, dockerDir}, registries)
+	assert.NilError(t, err)
+	assert.Equal(t, len(certs), 0)
+
+	_, err = clientCertificates("registry.example.com:5000", []string{containerdDir, dockerDir}, registries)
+	assert.ErrorContains(t, err, "failed to load the client certificate")
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/containerd/log"
+
+	ncconfig "github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/credprovider"
+)
+
+// withCredentialProviders wraps the AuthCreds with the credential providers of the [registries] tables of nerdctl.toml.
+// The registries with a credential_provider do not fall back to the credentials stored by `nerdctl login`.
+//
+// The AuthCreds are called by the authorizer on every authentication challenge, so that the credentials
+// are refreshed by the provider when they expire during long pushes.
+// ctx is used for obtaining the credentials from the providers, so it must outlive the AuthCreds.
+func withCredentialProviders(ctx context.Context, authCreds AuthCreds, registries map[string]ncconfig.RegistryConfig) (AuthCreds, error) {
+	providers := make(map[string]string)
+	for _, host := range slices.Sorted(maps.Keys(registries)) {
+		name := registries[host].CredentialProvider
+		if name == "" {
+			continue
+		}
+		if err := credprovider.Validate(name); err != nil {
+			return nil, fmt.Errorf("invalid registry %q in the [registries] tables: %w", host, err)
+		}
+		u, err := Parse(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry %q in the [registries] tables: %w", host, err)
+		}
+		providers[u.Host] = name
+	}
+	if len(providers) == 0 {
+		return authCreds, nil
+	}
+
+	return func(host string) (string, string, error) {
+		u, err := Parse(host)
+		if err != nil {
+			return "", "", err
+		}
+		name, ok := providers[u.Host]
+		if !ok {
+			return authCreds(host)
+		}
+		hostname := u.Host
+		if u.Port() == StandardHTTPSPort {
+			hostname = u.Hostname()
+		}
+		log.L.Debugf("using the credential provider %q for %q", name, hostname)
+		c, err := credprovider.Get(ctx, name, hostname)
+		if err != nil {
+			return "", "", err
+		}
+		return c.Username, c.Secret, nil
+	}, nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	ncconfig "github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+func TestWithCredentialProviders(t *testing.T) {
+	stored := func(host string) (string, string, error) {
+		return "user", "password", nil
+	}
+
+	_, err := withCredentialProviders(context.Background(), stored, map[string]ncconfig.RegistryConfig{
+		"registry.example.com": {CredentialProvider: "unknown"},
+	})
+	assert.ErrorContains(t, err, `invalid registry "registry.example.com"`)
+
+	authCreds, err := withCredentialProviders(context.Background(), stored, map[string]ncconfig.RegistryConfig{
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com": {CredentialProvider: "ecr"},
+		"registry.example.com":                         {ClientCert: "/etc/nerdctl/client.cert"},
+	})
+	assert.NilError(t, err)
+	username, secret, err := authCreds("registry.example.com")
+	assert.NilError(t, err)
+	assert.Equal(t, username, "user")
+	assert.Equal(t, secret, "password")
+}
@@ -19,10 +19,12 @@ package dockerconfigresolver
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
 )
 
@@ -36,6 +38,11 @@ func NewCredentialsStore(path string) (*CredentialsStore, error) {
 	if err != nil {
 		return nil, errors.Join(ErrUnableToInstantiate, err)
 	}
+	// Like the docker cli, the credentials are stored with the credential helper of the platform
+	// (osxkeychain, wincred, pass, or secretservice) when it is installed, unless any credentials are configured yet.
+	if !dockerConfigFile.ContainsAuth() {
+		dockerConfigFile.CredentialsStore = credentials.DetectDefaultStore(dockerConfigFile.CredentialsStore)
+	}
 
 	return &CredentialsStore{
 		dockerConfigFile: dockerConfigFile,
@@ -73,9 +80,73 @@ func (cs *CredentialsStore) Erase(registryURL *RegistryURL) (map[string]error, e
 	return nil, nil
 }
 
+// StoredCredentials describes the credentials stored for a registry, without their secrets.
+type StoredCredentials struct {
+	ServerAddress string
+	Username      string
+	// IdentityToken is true when an identity token is stored, instead of a password
+	IdentityToken bool
+	// Store is the config file storing the credentials, or the credential helper binary (e.g., "docker-credential-pass")
+	Store string
+}
+
+// List returns the credentials stored for all the registries, sorted by server address.
+// On error, ErrUnableToRetrieve
+func (cs *CredentialsStore) List() ([]StoredCredentials, error) {
+	all, err := cs.dockerConfigFile.GetAllCredentials()
+	if err != nil {
+		return nil, errors.Join(ErrUnableToRetrieve, err)
+	}
+	list := []StoredCredentials{}
+	for serverAddress, credentials := range all {
+		// The entries of the config file that only record that a credential helper is used are skipped
+		if credentials.Username == "" && credentials.Password == "" && credentials.Auth == "" &&
+			credentials.IdentityToken == "" && credentials.RegistryToken == "" {
+			continue
+		}
+		store := cs.dockerConfigFile.Filename
+		if helper := cs.credentialHelper(serverAddress); helper != "" {
+			store = "docker-credential-" + helper
+		}
+		list = append(list, StoredCredentials{
+			ServerAddress: serverAddress,
+			Username:      strings.TrimSpace(credentials.Username),
+			IdentityToken: credentials.IdentityToken != "",
+			Store:         store,
+		})
+	}
+	slices.SortFunc(list, func(a, b StoredCredentials) int {
+		return strings.Compare(a.ServerAddress, b.ServerAddress)
+	})
+	return list, nil
+}
+
+// EraseAll removes the credentials stored for all the registries, returning the erased server addresses.
+// The errors are returned per server address, along with ErrUnableToErase.
+func (cs *CredentialsStore) EraseAll() ([]string, map[string]error, error) {
+	list, err := cs.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	var erased []string
+	errs := make(map[string]error)
+	for _, stored := range list {
+		if err := cs.dockerConfigFile.GetCredentialsStore(stored.ServerAddress).Erase(stored.ServerAddress); err != nil {
+			errs[stored.ServerAddress] = cs.credentialHelperError(stored.ServerAddress, err)
+			continue
+		}
+		erased = append(erased, stored.ServerAddress)
+	}
+	if len(errs) > 0 {
+		return erased, errs, ErrUnableToErase
+	}
+	return erased, nil, nil
+}
+
 // Store will save credentials for a given registry
 // On error, ErrUnableToStore
 func (cs *CredentialsStore) Store(registryURL *RegistryURL, credentials *Credentials) error {
+	identifier := cs.storeIdentifier(registryURL)
 	// We just overwrite the server property here with the host
 	// Whether it was one of the variants, or was not set at all (see for example Amazon ECR, https://github.com/containerd/nerdctl/issues/733
 	// - which is likely a bug in docker) it doesn't matter.
@@ -83,23 +154,58 @@ func (cs *CredentialsStore) Store(registryURL *RegistryURL, credentials *Credent
 	if registryURL.Namespace != nil {
 		credentials.ServerAddress = fmt.Sprintf("%s%s?%s", registryURL.Host, registryURL.Path, registryURL.RawQuery)
 	} else {
-		credentials.ServerAddress = registryURL.CanonicalIdentifier()
+		credentials.ServerAddress = identifier
 	}
 
 	// XXX future namespaced url likely require special handling here
-	if err := cs.dockerConfigFile.GetCredentialsStore(registryURL.CanonicalIdentifier()).Store(*(credentials)); err != nil {
-		return errors.Join(ErrUnableToStore, err)
+	if err := cs.dockerConfigFile.GetCredentialsStore(identifier).Store(*(credentials)); err != nil {
+		return errors.Join(ErrUnableToStore, cs.credentialHelperError(identifier, err))
 	}
 
 	return nil
 }
 
+// storeIdentifier returns the identifier that the credentials of the registry are stored with.
+// This is the canonical identifier, unless a variant has its own credential helper in `credHelpers`,
+// as their keys are usually the hostnames without a port (e.g., "gcr.io").
+func (cs *CredentialsStore) storeIdentifier(registryURL *RegistryURL) string {
+	for _, identifier := range registryURL.AllIdentifiers() {
+		if _, ok := cs.dockerConfigFile.CredentialHelpers[identifier]; ok {
+			return identifier
+		}
+	}
+	return registryURL.CanonicalIdentifier()
+}
+
+// credentialHelper returns the name of the credential helper used for the identifier, or the empty string
+// when the credentials are stored in the config file, like the docker cli does.
+func (cs *CredentialsStore) credentialHelper(identifier string) string {
+	if helper, ok := cs.dockerConfigFile.CredentialHelpers[identifier]; ok {
+		return helper
+	}
+	return cs.dockerConfigFile.CredentialsStore
+}
+
+// credentialHelperError wraps the error with the name of the credential helper binary, if any,
+// so that a missing or failing helper can be told apart from a broken config file.
+func (cs *CredentialsStore) credentialHelperError(identifier string, err error) error {
+	if helper := cs.credentialHelper(identifier); helper != "" {
+		return fmt.Errorf("credential helper \"docker-credential-%s\" failed for %q: %w", helper, identifier, err)
+	}
+	return err
+}
+
 // ShellCompletion will return candidate strings for nerdctl logout
 func (cs *CredentialsStore) ShellCompletion() []string {
 	candidates := []string{}
 	for key := range cs.dockerConfigFile.AuthConfigs {
 		candidates = append(candidates, key)
 	}
+	for key := range cs.dockerConfigFile.CredentialHelpers {
+		if _, ok := cs.dockerConfigFile.AuthConfigs[key]; !ok {
+			candidates = append(candidates, key)
+		}
+	}
 
 	return candidates
 }
@@ -140,11 +246,14 @@ func (cs *CredentialsStore) Retrieve(registryURL *RegistryURL, checkCredStore bo
 	// Get the legacy variants (w/o scheme or port), and iterate over until we find one with credentials
 	variants := registryURL.AllIdentifiers()
 
+	var errs []error
+	found := false
 	for _, identifier := range variants {
 		var credentials types.AuthConfig
-		// Note that Get does not raise an error on ENOENT
+		// Note that Get does not raise an error on ENOENT, nor do the credential helpers when they have no credentials
 		credentials, err = cs.dockerConfigFile.GetCredentialsStore(identifier).Get(identifier)
 		if err != nil {
+			errs = append(errs, cs.credentialHelperError(identifier, err))
 			continue
 		}
 		returnedCredentials = &credentials
@@ -155,16 +264,17 @@ func (cs *CredentialsStore) Retrieve(registryURL *RegistryURL, checkCredStore bo
 			returnedCredentials.Username != "" ||
 			returnedCredentials.Password != "" ||
 			returnedCredentials.RegistryToken != "" {
+			found = true
 			break
 		}
 	}
 
-	// (Last non nil) credential store error gets wrapped into ErrUnableToRetrieve
-	if err != nil {
-		err = errors.Join(ErrUnableToRetrieve, err)
+	// Credential store errors get wrapped into ErrUnableToRetrieve, unless a variant had credentials
+	if len(errs) > 0 && !found {
+		return returnedCredentials, errors.Join(append([]error{ErrUnableToRetrieve}, errs...)...)
 	}
 
-	return returnedCredentials, err
+	return returnedCredentials, nil
 }
 
 // isFileStore is an internal mock interface purely meant to help identify that the docker credential backend is a filesystem one
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -399,4 +400,113 @@ func TestWorkingCredentialsStore(t *testing.T) {
 	})
 }
 
+// writeCredentialHelper writes a fake `docker-credential-fake` to a directory prepended to $PATH.
+// The helper returns the credentials of `username`, and records the requests to `request.<ACTION>` in the directory.
+func writeCredentialHelper(t *testing.T) string {
+	t.Helper()
+	binDir := createTempDir(t, 0700)
+	script := fmt.Sprintf(`#!/bin/sh
+cat > %[1]s/request.$1
+case "$1" in
+get) echo '{"ServerURL":"registry.example","Username":"username","Secret":"secret"}' ;;
+store|erase) ;;
+*) exit 1 ;;
+esac
+`, binDir)
+	if err := os.WriteFile(filepath.Join(binDir, "docker-credential-fake"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return binDir
+}
+
+func TestCredentialHelpers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+
+	registryURL, err := Parse("registry.example")
+	assert.NilError(t, err)
+
+	t.Run("credHelpers are used for the hostname without a port", func(t *testing.T) {
+		binDir := writeCredentialHelper(t)
+		cs, err := NewCredentialsStore(writeContent(t, `{"credHelpers": {"registry.example": "fake"}}`))
+		assert.NilError(t, err)
+
+		af, err := cs.Retrieve(registryURL, true)
+		assert.NilError(t, err)
+		assert.Equal(t, af.Username, "username")
+		assert.Equal(t, af.Password, "secret")
+
+		assert.NilError(t, cs.Store(registryURL, &Credentials{Username: "username", Password: "secret"}))
+		request, err := os.ReadFile(filepath.Join(binDir, "request.store"))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(request), `"ServerURL":"registry.example"`), string(request))
+	})
+
+	t.Run("credsStore is used for all the registries", func(t *testing.T) {
+		binDir := writeCredentialHelper(t)
+		cs, err := NewCredentialsStore(writeContent(t, `{"credsStore": "fake"}`))
+		assert.NilError(t, err)
+
+		assert.NilError(t, cs.Store(registryURL, &Credentials{Username: "username", Password: "secret"}))
+		request, err := os.ReadFile(filepath.Join(binDir, "request.store"))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(request), `"ServerURL":"registry.example:443"`), string(request))
+		assert.Equal(t, cs.FileStorageLocation(registryURL), "")
+	})
+
+	t.Run("errors of the credential helpers are surfaced", func(t *testing.T) {
+		cs, err := NewCredentialsStore(writeContent(t, `{"credHelpers": {"registry.example": "doesnotexist"}}`))
+		assert.NilError(t, err)
+
+		_, err = cs.Retrieve(registryURL, true)
+		assert.ErrorIs(t, err, ErrUnableToRetrieve)
+		assert.ErrorContains(t, err, `credential helper "docker-credential-doesnotexist" failed for "registry.example"`)
+
+		err = cs.Store(registryURL, &Credentials{Username: "username", Password: "secret"})
+		assert.ErrorIs(t, err, ErrUnableToStore)
+		assert.ErrorContains(t, err, "docker-credential-doesnotexist")
+	})
+}
+
+func TestListAndEraseAll(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+
+	binDir := writeCredentialHelper(t)
+	dir := writeContent(t, fmt.Sprintf(`{
+	"auths": {
+		"https://index.docker.io/v1/": {"auth": "%s"},
+		"ghcr.io": {"identitytoken": "token"},
+		"stub.example": {}
+	},
+	"credHelpers": {"registry.example": "fake"}
+}`, base64.StdEncoding.EncodeToString([]byte("username:password"))))
+	cs, err := NewCredentialsStore(dir)
+	assert.NilError(t, err)
+
+	list, err := cs.List()
+	assert.NilError(t, err)
+	configFile := filepath.Join(dir, "config.json")
+	assert.DeepEqual(t, list, []StoredCredentials{
+		{ServerAddress: "ghcr.io", IdentityToken: true, Store: configFile},
+		{ServerAddress: "https://index.docker.io/v1/", Username: "username", Store: configFile},
+		{ServerAddress: "registry.example", Username: "username", Store: "docker-credential-fake"},
+	})
+
+	erased, errs, err := cs.EraseAll()
+	assert.NilError(t, err)
+	assert.Equal(t, len(errs), 0)
+	assert.DeepEqual(t, erased, []string{"ghcr.io", "https://index.docker.io/v1/", "registry.example"})
+	request, err := os.ReadFile(filepath.Join(binDir, "request.erase"))
+	assert.NilError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(request)), "registry.example")
+
+	cs, err = NewCredentialsStore(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(cs.dockerConfigFile.AuthConfigs), 1)
+}
+
 // TODO: add more tests that write credentials (specifically to hub locations) to verify they use the canonical id properly
@@ -20,12 +20,16 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"net/http"
 
 	"github.com/containerd/containerd/v2/core/remotes"
 	"github.com/containerd/containerd/v2/core/remotes/docker"
 	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/containerd/v2/pkg/tracing"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
+
+	ncconfig "github.com/containerd/nerdctl/v2/pkg/config"
 )
 
 var PushTracker = docker.NewInMemoryTracker()
@@ -35,6 +39,8 @@ type opts struct {
 	skipVerifyCerts bool
 	hostsDirs       []string
 	authCreds       AuthCreds
+	registries      map[string]ncconfig.RegistryConfig
+	tokenCache      bool
 }
 
 // Opt for New
@@ -105,17 +111,21 @@ func NewHostOptions(ctx context.Context, refHostname string, optFuncs ...Opt) (*
 	if o.authCreds != nil {
 		ho.Credentials = o.authCreds
 	} else {
-		authCreds, err := NewAuthCreds(refHostname)
+		authCreds, err := NewRegistryAuthCreds(ctx, refHostname, o.registries)
 		if err != nil {
 			return nil, err
 		}
 		ho.Credentials = authCreds
-
 	}
 
-	if o.skipVerifyCerts {
+	clientCerts, err := clientCertificates(refHostname, o.hostsDirs, o.registries)
+	if err != nil {
+		return nil, err
+	}
+	if o.skipVerifyCerts || len(clientCerts) > 0 {
 		ho.DefaultTLS = &tls.Config{
-			InsecureSkipVerify: true,
+			InsecureSkipVerify: o.skipVerifyCerts,
+			Certificates:       clientCerts,
 		}
 	}
 
@@ -132,6 +142,21 @@ func NewHostOptions(ctx context.Context, refHostname string, optFuncs ...Opt) (*
 		// https://github.com/containerd/containerd/issues/9208
 		ho.DefaultTLS = nil
 	}
+	var tokenCacheDir string
+	if o.tokenCache {
+		if tokenCacheDir, err = TokenCacheDir(); err != nil {
+			log.G(ctx).WithError(err).Debug("not caching the tokens of the registries")
+		}
+	}
+	// Observe the rate limits, and record the registry requests as spans when the traces are exported (no-op otherwise)
+	ho.UpdateClient = func(client *http.Client) error {
+		client.Transport = &rateLimitObserver{rt: client.Transport}
+		if tokenCacheDir != "" {
+			client.Transport = newTokenCache(tokenCacheDir, client.Transport)
+		}
+		tracing.UpdateHTTPClient(client, "registry.request")
+		return nil
+	}
 	return &ho, nil
 }
 
@@ -158,6 +183,16 @@ func New(ctx context.Context, refHostname string, optFuncs ...Opt) (remotes.Reso
 // AuthCreds is for docker.WithAuthCreds
 type AuthCreds func(string) (string, string, error)
 
+// NewRegistryAuthCreds returns AuthCreds that uses the credential providers of the [registries] tables of nerdctl.toml,
+// falling back to $DOCKER_CONFIG/config.json .
+func NewRegistryAuthCreds(ctx context.Context, refHostname string, registries map[string]ncconfig.RegistryConfig) (AuthCreds, error) {
+	authCreds, err := NewAuthCreds(refHostname)
+	if err != nil {
+		return nil, err
+	}
+	return withCredentialProviders(ctx, authCreds, registries)
+}
+
 // NewAuthCreds returns AuthCreds that uses $DOCKER_CONFIG/config.json .
 // AuthCreds can be nil.
 func NewAuthCreds(refHostname string) (AuthCreds, error) {
@@ -0,0 +1,134 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/log"
+)
+
+// rateLimitWarningRatio is the ratio of the remaining pulls to the limit, below which a warning is printed
+const rateLimitWarningRatio = 0.1
+
+// rateLimitWarning prints the warning of the rate limit once per command
+var rateLimitWarning sync.Once
+
+// RateLimit is the pull rate limit of Docker Hub, reported by the "ratelimit-*" headers of registry-1.docker.io.
+// See https://docs.docker.com/docker-hub/usage/pulls/ .
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Window is the duration that Limit applies to, e.g., 6 hours
+	Window time.Duration
+	// Source is the IP address for the anonymous pulls, or the account ID for the authenticated pulls
+	Source string
+}
+
+func (rl *RateLimit) String() string {
+	return fmt.Sprintf("%d of %d pulls remaining per %s (source: %s)", rl.Remaining, rl.Limit, rl.Window, rl.Source)
+}
+
+// Low returns true when the remaining pulls are close to be exhausted.
+func (rl *RateLimit) Low() bool {
+	return float64(rl.Remaining) <= float64(rl.Limit)*rateLimitWarningRatio
+}
+
+// ParseRateLimit parses the "ratelimit-limit" and "ratelimit-remaining" headers, e.g., "100;w=21600".
+// It returns nil when the headers are missing, e.g., for the accounts without a rate limit.
+func ParseRateLimit(header http.Header) (*RateLimit, error) {
+	limitHeader, remainingHeader := header.Get("RateLimit-Limit"), header.Get("RateLimit-Remaining")
+	if limitHeader == "" || remainingHeader == "" {
+		return nil, nil
+	}
+	limit, window, err := parseRateLimitValue(limitHeader)
+	if err != nil {
+		return nil, err
+	}
+	remaining, _, err := parseRateLimitValue(remainingHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Window:    window,
+		Source:    header.Get("Docker-RateLimit-Source"),
+	}, nil
+}
+
+func parseRateLimitValue(s string) (int, time.Duration, error) {
+	value, params, _ := strings.Cut(s, ";")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+	var window time.Duration
+	for _, param := range strings.Split(params, ";") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok && k == "w" {
+			seconds, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid rate limit window %q: %w", s, err)
+			}
+			window = time.Duration(seconds) * time.Second
+		}
+	}
+	return n, window, nil
+}
+
+// rateLimitObserver is a http.RoundTripper logging the rate limit reported by the responses of the registries,
+// and warning when the remaining pulls are low or exhausted.
+type rateLimitObserver struct {
+	rt http.RoundTripper
+}
+
+func (o *rateLimitObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := o.rt
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	ctx := req.Context()
+	if res.StatusCode == http.StatusTooManyRequests {
+		log.G(ctx).Warnf("%s: the pull rate limit is exceeded (Hint: log in with `nerdctl login` to raise the rate limit of Docker Hub, "+
+			"and see `nerdctl system ratelimit`)", req.URL.Host)
+		return res, nil
+	}
+	rl, err := ParseRateLimit(res.Header)
+	if err != nil {
+		log.G(ctx).WithError(err).Debugf("%s: failed to parse the rate limit", req.URL.Host)
+		return res, nil
+	}
+	if rl == nil {
+		return res, nil
+	}
+	log.G(ctx).Debugf("%s: %s", req.URL.Host, rl)
+	if rl.Low() {
+		rateLimitWarning.Do(func() {
+			log.G(ctx).Warnf("%s: %s; the pulls will fail when the rate limit is exhausted", req.URL.Host, rl)
+		})
+	}
+	return res, nil
+}
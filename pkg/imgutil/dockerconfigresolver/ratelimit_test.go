@@ -0,0 +1,49 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	header := http.Header{}
+	rl, err := ParseRateLimit(header)
+	assert.NilError(t, err)
+	assert.Assert(t, rl == nil)
+
+	header.Set("RateLimit-Limit", "100;w=21600")
+	header.Set("RateLimit-Remaining", "76;w=21600")
+	header.Set("Docker-RateLimit-Source", "203.0.113.1")
+	rl, err = ParseRateLimit(header)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *rl, RateLimit{Limit: 100, Remaining: 76, Window: 6 * time.Hour, Source: "203.0.113.1"})
+	assert.Assert(t, !rl.Low())
+
+	header.Set("RateLimit-Remaining", "10;w=21600")
+	rl, err = ParseRateLimit(header)
+	assert.NilError(t, err)
+	assert.Assert(t, rl.Low())
+
+	header.Set("RateLimit-Remaining", "foo")
+	_, err = ParseRateLimit(header)
+	assert.ErrorContains(t, err, "invalid rate limit")
+}
@@ -0,0 +1,207 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker/auth"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+const (
+	// defaultTokenExpiresIn is the lifetime of the tokens without "expires_in", as specified by the distribution token spec
+	defaultTokenExpiresIn = 60 * time.Second
+	// tokenExpiryMargin is subtracted from the lifetime of the cached tokens, so that they do not expire while in use
+	tokenExpiryMargin = 10 * time.Second
+)
+
+// tokenRealms are the URLs of the token endpoints, learned from the bearer challenges of the registries
+var tokenRealms sync.Map
+
+// WithTokenCache enables caching the bearer tokens of the registries in the runtime dir, across the commands
+func WithTokenCache(b bool) Opt {
+	return func(o *opts) {
+		o.tokenCache = b
+	}
+}
+
+// TokenCacheDir returns the directory where the bearer tokens are cached.
+// The directory is under /run (or $XDG_RUNTIME_DIR in rootless mode), which is usually a tmpfs,
+// so that the tokens are never written to the disk. The empty string is returned on non-Linux platforms.
+func TokenCacheDir() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", nil
+	}
+	runDir, err := rootlessutil.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runDir, "nerdctl", "tokens"), nil
+}
+
+// cachedToken is the file of a cached token response
+type cachedToken struct {
+	Expiry time.Time       `json:"expiry"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// tokenCache is a http.RoundTripper serving the responses of the token endpoints from the files of dir, until the tokens expire.
+//
+// The files are keyed by the hash of the whole request, including the scopes and the credentials,
+// so that a token is only reused for the same scopes, by the same user.
+type tokenCache struct {
+	dir string
+	rt  http.RoundTripper
+}
+
+func newTokenCache(dir string, rt http.RoundTripper) *tokenCache {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &tokenCache{dir: dir, rt: rt}
+}
+
+func (tc *tokenCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := tokenRealms.Load(realmOf(req)); !ok {
+		res, err := tc.rt.RoundTrip(req)
+		if err == nil && res.StatusCode == http.StatusUnauthorized {
+			for _, c := range auth.ParseAuthHeader(res.Header) {
+				if c.Scheme == auth.BearerAuth && c.Parameters["realm"] != "" {
+					tokenRealms.Store(c.Parameters["realm"], struct{}{})
+				}
+			}
+		}
+		return res, err
+	}
+
+	key, err := tokenCacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.Join(tc.dir, key)
+	if res := tc.load(p, req); res != nil {
+		log.G(req.Context()).Debugf("using the cached token of %s", realmOf(req))
+		return res, nil
+	}
+
+	res, err := tc.rt.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(b))
+	if err := tc.store(p, b); err != nil {
+		log.G(req.Context()).WithError(err).Debugf("failed to cache the token of %s", realmOf(req))
+	}
+	return res, nil
+}
+
+func (tc *tokenCache) load(p string, req *http.Request) *http.Response {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var cached cachedToken
+	if err := json.Unmarshal(b, &cached); err != nil || time.Now().After(cached.Expiry) {
+		_ = os.Remove(p)
+		return nil
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+func (tc *tokenCache) store(p string, body []byte) error {
+	var token struct {
+		ExpiresIn int       `json:"expires_in"`
+		IssuedAt  time.Time `json:"issued_at"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return err
+	}
+	expiresIn := defaultTokenExpiresIn
+	if token.ExpiresIn > 0 {
+		expiresIn = time.Duration(token.ExpiresIn) * time.Second
+	}
+	issuedAt := token.IssuedAt
+	if issuedAt.IsZero() || issuedAt.After(time.Now()) {
+		issuedAt = time.Now()
+	}
+	expiry := issuedAt.Add(expiresIn - tokenExpiryMargin)
+	if time.Now().After(expiry) {
+		return nil
+	}
+	b, err := json.Marshal(cachedToken{Expiry: expiry, Body: body})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tc.dir, 0o700); err != nil {
+		return err
+	}
+	return filesystem.WriteFileWithRename(p, b, 0o600)
+}
+
+// realmOf returns the URL of the request without the query, to be compared with the realms of the challenges
+func realmOf(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// tokenCacheKey hashes the method, the URL, the Authorization header, and the body of the request.
+// The body is restored, to be sent when the token is not cached.
+func tokenCacheKey(req *http.Request) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, req.Method+"\n"+req.URL.String()+"\n"+req.Header.Get("Authorization")+"\n")
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		h.Write(b)
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTokenCache(t *testing.T) {
+	var srv *httptest.Server
+	issued := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, srv.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issued++
+		expiresIn := 300
+		if r.URL.Query().Get("scope") == "repository:short:pull" {
+			expiresIn = 5
+		}
+		json.NewEncoder(w).Encode(map[string]any{"token": fmt.Sprintf("token-%d", issued), "expires_in": expiresIn})
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	getToken := func(scope, authorization string) string {
+		// A new client for each request, like the commands run in a row
+		client := &http.Client{Transport: newTokenCache(dir, srv.Client().Transport)}
+		res, err := client.Get(srv.URL + "/v2/")
+		assert.NilError(t, err)
+		res.Body.Close()
+		assert.Equal(t, res.StatusCode, http.StatusUnauthorized)
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/token?service=registry&scope="+scope, nil)
+		assert.NilError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		res, err = client.Do(req)
+		assert.NilError(t, err)
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		assert.NilError(t, err)
+		var token struct {
+			Token string `json:"token"`
+		}
+		assert.NilError(t, json.Unmarshal(b, &token))
+		return token.Token
+	}
+
+	assert.Equal(t, getToken("repository:foo:pull", ""), "token-1")
+	assert.Equal(t, getToken("repository:foo:pull", ""), "token-1")
+	// The tokens are not shared across the scopes, nor across the credentials
+	assert.Equal(t, getToken("repository:foo:pull,push", ""), "token-2")
+	assert.Equal(t, getToken("repository:foo:pull", "Basic dXNlcjpwYXNz"), "token-3")
+	assert.Equal(t, getToken("repository:foo:pull", "Basic dXNlcjpwYXNz"), "token-3")
+	// The tokens expiring within the margin are not cached
+	assert.Equal(t, getToken("repository:short:pull", ""), "token-4")
+	assert.Equal(t, getToken("repository:short:pull", ""), "token-5")
+	assert.Equal(t, issued, 5)
+
+	// The other requests are not cached
+	client := &http.Client{Transport: newTokenCache(dir, srv.Client().Transport)}
+	res, err := client.Get(srv.URL + "/v2/foo/manifests/latest")
+	assert.NilError(t, err)
+	res.Body.Close()
+	assert.Assert(t, strings.Contains(res.Header.Get("WWW-Authenticate"), "Bearer"))
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	converterutil "github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
+)
+
+// snapshotterNameErofs is the builtin EROFS snapshotter of containerd (v2.1 or later)
+const snapshotterNameErofs = "erofs"
+
+// checkErofsLayers returns an error when the manifest has native EROFS layers (e.g., the images converted with
+// `nerdctl image convert --erofs`), which only the erofs snapshotter can unpack.
+func checkErofsLayers(ref string, manifest *ocispec.Manifest, snapshotter string) error {
+	if manifest == nil || snapshotter == snapshotterNameErofs {
+		return nil
+	}
+	for _, l := range manifest.Layers {
+		if converterutil.IsErofsMediaType(l.MediaType) {
+			return fmt.Errorf("image %q has EROFS layers (%s), which require --snapshotter=%s, not %q", ref, l.MediaType, snapshotterNameErofs, snapshotter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckErofsLayers(t *testing.T) {
+	manifest := &ocispec.Manifest{Layers: []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageLayerGzip},
+		{MediaType: "application/vnd.oci.image.layer.v1.erofs"},
+	}}
+	assert.NilError(t, checkErofsLayers("example.com/foo:erofs", manifest, "erofs"))
+	assert.ErrorContains(t, checkErofsLayers("example.com/foo:erofs", manifest, "overlayfs"), "require --snapshotter=erofs")
+	assert.NilError(t, checkErofsLayers("example.com/foo", &ocispec.Manifest{Layers: manifest.Layers[:1]}, "overlayfs"))
+	assert.NilError(t, checkErofsLayers("example.com/foo", nil, "overlayfs"))
+}
@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/opencontainers/image-spec/identity"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/content"
@@ -43,9 +45,11 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/pull"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 // EnsuredImage contains the image existed in containerd and its metadata.
@@ -84,6 +88,13 @@ func GetExistingImage(ctx context.Context, client *containerd.Client, snapshotte
 				Remote:      getSnapshotterOpts(snapshotter).isRemote(),
 			}
 			if unpacked, err := image.IsUnpacked(ctx, snapshotter); err == nil && !unpacked {
+				manifest, _, err := ReadManifest(ctx, image)
+				if err != nil {
+					return err
+				}
+				if err := checkErofsLayers(found.Image.Name, manifest, snapshotter); err != nil {
+					return err
+				}
 				if err := image.Unpack(ctx, snapshotter); err != nil {
 					return err
 				}
@@ -139,6 +150,8 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 	}
 	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(options.GOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(options.GOptions.Registries))
+	dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!options.GOptions.NoTokenCache))
 	resolver, err := dockerconfigresolver.New(ctx, parsedReference.Domain, dOpts...)
 	if err != nil {
 		return nil, err
@@ -194,7 +207,13 @@ func ResolveDigest(ctx context.Context, rawRef string, insecure bool, hostsDirs
 }
 
 // PullImage pulls an image using the specified resolver.
-func PullImage(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, ref string, options types.ImagePullOptions) (*EnsuredImage, error) {
+func PullImage(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, ref string, options types.ImagePullOptions) (_ *EnsuredImage, retErr error) {
+	// The fetch and unpack spans of the containerd client are recorded under this span
+	ctx, span := tracingutil.StartSpan(ctx, "nerdctl.image.pull",
+		attribute.String("image.ref", ref),
+		attribute.String("snapshotter", options.GOptions.Snapshotter))
+	defer func() { tracingutil.EndSpan(span, retErr) }()
+
 	ctx, done, err := client.WithLease(ctx)
 	if err != nil {
 		return nil, err
@@ -207,11 +226,13 @@ func PullImage(ctx context.Context, client *containerd.Client, resolver remotes.
 		RemoteOpts: []containerd.RemoteOpt{},
 		Platforms:  options.OCISpecPlatform, // empty for all-platforms
 	}
-	if !options.Quiet {
+	if !options.Quiet && options.GOptions.Progress != jobs.ProgressQuiet {
 		config.ProgressOutput = options.Stderr
-		if options.ProgressOutputToStdout {
+		// machine-readable records always go to stderr, so that stdout stays parseable
+		if options.ProgressOutputToStdout && options.GOptions.Progress != jobs.ProgressJSON {
 			config.ProgressOutput = options.Stdout
 		}
+		config.ProgressMode = options.GOptions.Progress
 	}
 
 	// unpack(B) if given 1 platform unless specified by `unpack`
@@ -232,13 +253,34 @@ func PullImage(ctx context.Context, client *containerd.Client, resolver remotes.
 			containerd.WithPullUnpack,
 			containerd.WithUnpackOpts([]containerd.UnpackOpt{imgcryptUnpackOpt}))
 
+		rFlags := options.RFlags
+		if snOpt.isRemote() && rFlags.PullCredentials == nil {
+			rFlags.PullCredentials, err = pullCredentials(ctx, ref, options.GOptions.Registries)
+			if err != nil {
+				return nil, err
+			}
+		}
 		// different remote snapshotters will update pull.Config separately
-		snOpt.apply(config, ref, options.RFlags)
+		snOpt.apply(config, ref, rFlags)
 	} else {
 		log.G(ctx).Debugf("The image will not be unpacked. Platforms=%v.", options.OCISpecPlatform)
 	}
 
-	containerdImage, err = pull.Pull(ctx, client, ref, config)
+	// The lease covers all the attempts, so that the partially downloaded blobs are kept in the content store,
+	// and their download is resumed from the current offset (with a Range request) on retry.
+	for attempt := 0; ; attempt++ {
+		containerdImage, err = pull.Pull(ctx, client, ref, config)
+		if err == nil || attempt >= options.Retry || !isRetryablePullError(err) {
+			break
+		}
+		delay := pullRetryDelay(attempt)
+		log.G(ctx).WithError(err).Warnf("failed to pull %q, retrying in %s (%d/%d)", ref, delay, attempt+1, options.Retry)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
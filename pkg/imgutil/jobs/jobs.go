@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io"
 	"sync"
-	"text/tabwriter"
 	"time"
 
 	"github.com/opencontainers/go-digest"
@@ -38,10 +37,9 @@ import (
 // by checking status in the content store.
 //
 // From https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/content/fetch.go#L219-L336
-func ShowProgress(ctx context.Context, ongoing *Jobs, cs content.Store, out io.Writer) {
+func ShowProgress(ctx context.Context, ongoing *Jobs, cs content.Store, p *Printer) {
 	var (
 		ticker   = time.NewTicker(100 * time.Millisecond)
-		fw       = progress.NewWriter(out)
 		start    = time.Now()
 		statuses = map[string]StatusInfo{}
 		done     bool
@@ -52,10 +50,6 @@ outer:
 	for {
 		select {
 		case <-ticker.C:
-			fw.Flush()
-
-			tw := tabwriter.NewWriter(fw, 1, 8, 1, ' ', 0)
-
 			resolved := StatusResolved
 			if !ongoing.IsResolved() {
 				resolved = StatusResolving
@@ -141,11 +135,9 @@ outer:
 				ordered = append(ordered, statuses[key])
 			}
 
-			Display(tw, ordered, start)
-			tw.Flush()
+			p.Print(ordered, start, done)
 
 			if done {
-				fw.Flush()
 				return
 			}
 		case <-ctx.Done():
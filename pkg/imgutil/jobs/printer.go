@@ -0,0 +1,265 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containerd/containerd/v2/pkg/progress"
+	"github.com/containerd/errdefs"
+)
+
+// The modes of the global `--progress` flag.
+const (
+	// ProgressAuto redraws the progress bars in place, as `ctr` does. This is the default.
+	ProgressAuto = "auto"
+	// ProgressPlain prints a line per status change, without moving the cursor.
+	ProgressPlain = "plain"
+	// ProgressJSON prints a Record per line, on stderr.
+	ProgressJSON = "json"
+	// ProgressQuiet prints nothing.
+	ProgressQuiet = "quiet"
+)
+
+const (
+	// StatusComplete is the status of the last Record of an operation.
+	StatusComplete StatusInfoStatus = "complete"
+	// StatusExporting is the status of an archive being written by `nerdctl save`.
+	StatusExporting StatusInfoStatus = "exporting"
+	// StatusImporting is the status of an archive being read by `nerdctl load`.
+	StatusImporting StatusInfoStatus = "importing"
+)
+
+// ValidateProgressMode validates the value of the global `--progress` flag. An empty mode stands for ProgressAuto.
+func ValidateProgressMode(mode string) error {
+	switch mode {
+	case "", ProgressAuto, ProgressPlain, ProgressJSON, ProgressQuiet:
+		return nil
+	}
+	return fmt.Errorf("invalid progress mode %q (supported values: %q, %q, %q, %q): %w",
+		mode, ProgressAuto, ProgressPlain, ProgressJSON, ProgressQuiet, errdefs.ErrInvalidArgument)
+}
+
+// Record is a line of `--progress=json`.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Operation is "pull", "fetch", "push", "save", or "load"
+	Operation string `json:"operation"`
+	// Ref is the image name, or the ref key of a blob (e.g. "layer-sha256:..."). Empty for the StatusComplete record.
+	Ref    string           `json:"ref,omitempty"`
+	Status StatusInfoStatus `json:"status"`
+	// Current is the number of bytes processed
+	Current int64 `json:"current,omitempty"`
+	// Total is the number of bytes to process, when known
+	Total int64 `json:"total,omitempty"`
+	// Elapsed is the duration of the operation in seconds, only set in the StatusComplete record
+	Elapsed float64 `json:"elapsed,omitempty"`
+}
+
+// jsonInterval is the minimum interval between the records of a blob being transferred.
+const jsonInterval = time.Second
+
+// Printer prints the statuses of an operation in one of the progress modes.
+type Printer struct {
+	mode      string
+	operation string
+	out       io.Writer
+	fw        *progress.Writer
+	printed   map[string]printedStatus
+}
+
+type printedStatus struct {
+	status StatusInfoStatus
+	offset int64
+	at     time.Time
+}
+
+// NewPrinter returns a printer of the operation (e.g., "pull") in the mode, or nil for ProgressQuiet.
+func NewPrinter(out io.Writer, mode, operation string) *Printer {
+	if mode == ProgressQuiet {
+		return nil
+	}
+	if mode == "" {
+		mode = ProgressAuto
+	}
+	p := &Printer{
+		mode:      mode,
+		operation: operation,
+		out:       out,
+		printed:   map[string]printedStatus{},
+	}
+	if mode == ProgressAuto {
+		p.fw = progress.NewWriter(out)
+	}
+	return p
+}
+
+// NewArchivePrinter is similar to NewPrinter, for `nerdctl save` and `nerdctl load`.
+// It returns nil unless the mode is ProgressPlain or ProgressJSON, as they used to print no progress at all.
+func NewArchivePrinter(out io.Writer, mode, operation string) *Printer {
+	if out == nil || (mode != ProgressPlain && mode != ProgressJSON) {
+		return nil
+	}
+	return NewPrinter(out, mode, operation)
+}
+
+// Print prints the statuses. It is called periodically, and once more with done set when the operation is over.
+func (p *Printer) Print(statuses []StatusInfo, start time.Time, done bool) {
+	switch p.mode {
+	case ProgressAuto:
+		p.fw.Flush()
+		tw := tabwriter.NewWriter(p.fw, 1, 8, 1, ' ', 0)
+		Display(tw, statuses, start)
+		tw.Flush()
+		if done {
+			p.fw.Flush()
+		}
+	case ProgressPlain:
+		for _, s := range statuses {
+			if last, ok := p.printed[s.Ref]; ok && last.status == s.Status {
+				continue
+			}
+			p.printed[s.Ref] = printedStatus{status: s.Status}
+			if s.Total > 0 {
+				fmt.Fprintf(p.out, "%s: %s %s/%s\n", s.Ref, s.Status, progress.Bytes(s.Offset), progress.Bytes(s.Total))
+			} else {
+				fmt.Fprintf(p.out, "%s: %s\n", s.Ref, s.Status)
+			}
+		}
+		if done {
+			total := totalOffset(statuses)
+			fmt.Fprintf(p.out, "elapsed: %.1fs total: %s (%v)\n",
+				time.Since(start).Seconds(), progress.Bytes(total), progress.NewBytesPerSecond(total, time.Since(start)))
+		}
+	case ProgressJSON:
+		enc := json.NewEncoder(p.out)
+		now := time.Now()
+		for _, s := range statuses {
+			last, ok := p.printed[s.Ref]
+			if ok && last.status == s.Status && (last.offset == s.Offset || now.Sub(last.at) < jsonInterval) {
+				continue
+			}
+			p.printed[s.Ref] = printedStatus{status: s.Status, offset: s.Offset, at: now}
+			enc.Encode(Record{
+				Time:      now,
+				Operation: p.operation,
+				Ref:       s.Ref,
+				Status:    s.Status,
+				Current:   s.Offset,
+				Total:     s.Total,
+			})
+		}
+		if done {
+			enc.Encode(Record{
+				Time:      now,
+				Operation: p.operation,
+				Status:    StatusComplete,
+				Current:   totalOffset(statuses),
+				Elapsed:   time.Since(start).Seconds(),
+			})
+		}
+	}
+}
+
+func totalOffset(statuses []StatusInfo) int64 {
+	var total int64
+	for _, s := range statuses {
+		total += s.Offset
+	}
+	return total
+}
+
+// Counter counts the bytes written or read, for ShowCounterProgress.
+type Counter struct {
+	n atomic.Int64
+}
+
+// Add adds n bytes.
+func (c *Counter) Add(n int) {
+	c.n.Add(int64(n))
+}
+
+// Load returns the number of bytes counted.
+func (c *Counter) Load() int64 {
+	return c.n.Load()
+}
+
+// CountingWriter returns a writer that counts the bytes written to w.
+func (c *Counter) CountingWriter(w io.Writer) io.Writer {
+	return &countingWriter{w: w, c: c}
+}
+
+// CountingReader returns a reader that counts the bytes read from r.
+func (c *Counter) CountingReader(r io.Reader) io.Reader {
+	return &countingReader{r: r, c: c}
+}
+
+type countingWriter struct {
+	w io.Writer
+	c *Counter
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.c.Add(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	c *Counter
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.c.Add(n)
+	return n, err
+}
+
+// StartCounterProgress prints the progress of an operation that only knows its number of bytes processed so far,
+// such as exporting and importing archives, until the returned function is called.
+// total may be 0 when unknown.
+func StartCounterProgress(p *Printer, ref string, status StatusInfoStatus, c *Counter, total int64) (stop func()) {
+	var (
+		ticker = time.NewTicker(100 * time.Millisecond)
+		start  = time.Now()
+		doneCh = make(chan struct{})
+		exited = make(chan struct{})
+	)
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.Print([]StatusInfo{{Ref: ref, Status: status, Offset: c.Load(), Total: total}}, start, false)
+			case <-doneCh:
+				p.Print([]StatusInfo{{Ref: ref, Status: StatusDone, Offset: c.Load(), Total: total}}, start, true)
+				return
+			}
+		}
+	}()
+	return func() {
+		close(doneCh)
+		<-exited
+	}
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateProgressMode(t *testing.T) {
+	for _, mode := range []string{"", ProgressAuto, ProgressPlain, ProgressJSON, ProgressQuiet} {
+		assert.NilError(t, ValidateProgressMode(mode))
+	}
+	assert.ErrorContains(t, ValidateProgressMode("tty"), "invalid progress mode")
+}
+
+func TestNewPrinterQuiet(t *testing.T) {
+	assert.Assert(t, NewPrinter(&bytes.Buffer{}, ProgressQuiet, "pull") == nil)
+	assert.Assert(t, NewArchivePrinter(&bytes.Buffer{}, ProgressAuto, "save") == nil)
+	assert.Assert(t, NewArchivePrinter(nil, ProgressJSON, "save") == nil)
+	assert.Assert(t, NewArchivePrinter(&bytes.Buffer{}, ProgressJSON, "save") != nil)
+}
+
+func TestPrinterJSON(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf, ProgressJSON, "pull")
+	start := time.Now()
+	p.Print([]StatusInfo{
+		{Ref: "docker.io/library/alpine:latest", Status: StatusResolving},
+	}, start, false)
+	// unchanged statuses are not printed again
+	p.Print([]StatusInfo{
+		{Ref: "docker.io/library/alpine:latest", Status: StatusResolving},
+	}, start, false)
+	p.Print([]StatusInfo{
+		{Ref: "docker.io/library/alpine:latest", Status: StatusResolved},
+		{Ref: "layer-sha256:0123", Status: StatusDone, Offset: 42, Total: 42},
+	}, start, true)
+
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r Record
+		assert.NilError(t, json.Unmarshal([]byte(line), &r), line)
+		records = append(records, r)
+	}
+	assert.Equal(t, len(records), 4)
+	assert.Equal(t, records[0].Status, StatusResolving)
+	assert.Equal(t, records[1].Status, StatusResolved)
+	assert.Equal(t, records[2].Ref, "layer-sha256:0123")
+	assert.Equal(t, records[2].Current, int64(42))
+	assert.Equal(t, records[3].Status, StatusComplete)
+	assert.Equal(t, records[3].Operation, "pull")
+	assert.Equal(t, records[3].Current, int64(42))
+}
+
+func TestPrinterPlain(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(&buf, ProgressPlain, "push")
+	start := time.Now()
+	p.Print([]StatusInfo{{Ref: "layer-sha256:0123", Status: StatusWaiting}}, start, false)
+	p.Print([]StatusInfo{{Ref: "layer-sha256:0123", Status: StatusWaiting}}, start, false)
+	p.Print([]StatusInfo{{Ref: "layer-sha256:0123", Status: StatusDone, Offset: 1024, Total: 1024}}, start, true)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 3)
+	assert.Equal(t, lines[0], "layer-sha256:0123: waiting")
+	assert.Assert(t, strings.HasPrefix(lines[1], "layer-sha256:0123: done 1.0 KiB/1.0 KiB"), lines[1])
+	assert.Assert(t, strings.HasPrefix(lines[2], "elapsed: "), lines[2])
+}
+
+func TestStartCounterProgress(t *testing.T) {
+	var buf, archive bytes.Buffer
+	var counter Counter
+	stop := StartCounterProgress(NewPrinter(&buf, ProgressJSON, "save"), "alpine", StatusExporting, &counter, 0)
+	_, err := counter.CountingWriter(&archive).Write([]byte("hello"))
+	assert.NilError(t, err)
+	stop()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var last Record
+	assert.NilError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+	assert.Equal(t, last.Status, StatusComplete)
+	assert.Equal(t, last.Current, int64(5))
+}
@@ -28,10 +28,12 @@ import (
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/images/archive"
 	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/log"
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 )
 
@@ -44,6 +46,16 @@ func FromArchive(ctx context.Context, client *containerd.Client, options types.I
 		}
 		defer f.Close()
 		options.Stdin = f
+		if printer := jobs.NewArchivePrinter(options.Stderr, options.GOptions.Progress, "load"); printer != nil {
+			var total int64
+			if st, err := f.Stat(); err == nil {
+				total = st.Size()
+			}
+			var counter jobs.Counter
+			options.Stdin = counter.CountingReader(f)
+			stop := jobs.StartCounterProgress(printer, options.Input, jobs.StatusImporting, &counter, total)
+			defer stop()
+		}
 	} else {
 		// check if stdin is empty.
 		stdinStat, err := os.Stdin.Stat()
@@ -53,6 +65,12 @@ func FromArchive(ctx context.Context, client *containerd.Client, options types.I
 		if stdinStat.Size() == 0 && (stdinStat.Mode()&os.ModeNamedPipe) == 0 {
 			return nil, errors.New("stdin is empty and input flag is not specified")
 		}
+		if printer := jobs.NewArchivePrinter(options.Stderr, options.GOptions.Progress, "load"); printer != nil {
+			var counter jobs.Counter
+			options.Stdin = counter.CountingReader(options.Stdin)
+			stop := jobs.StartCounterProgress(printer, "stdin", jobs.StatusImporting, &counter, 0)
+			defer stop()
+		}
 	}
 	decompressor, err := compression.DecompressStream(options.Stdin)
 	if err != nil {
@@ -112,12 +130,22 @@ func (r *readCounter) Read(p []byte) (int, error) {
 func importImages(ctx context.Context, client *containerd.Client, in io.Reader, snapshotter string, platformMC platforms.MatchComparer) ([]images.Image, error) {
 	// In addition to passing WithImagePlatform() to client.Import(), we also need to pass WithDefaultPlatform() to NewClient().
 	// Otherwise unpacking may fail.
-	r := &readCounter{Reader: in}
+	vr := newVerifyingReader(in)
+	r := &readCounter{Reader: vr}
 	imgs, err := client.Import(ctx, r,
 		containerd.WithDigestRef(archive.DigestTranslator(snapshotter)),
 		containerd.WithSkipDigestRef(func(name string) bool { return name != "" }),
 		containerd.WithImportPlatform(platformMC),
 	)
+	if verr := vr.Wait(); verr != nil {
+		// Do not leave images referencing corrupted content behind
+		for _, img := range imgs {
+			if derr := client.ImageService().Delete(ctx, img.Name); derr != nil {
+				log.G(ctx).WithError(derr).Warnf("failed to remove image %q", img.Name)
+			}
+		}
+		return nil, verr
+	}
 	if err != nil {
 		if r.N == 0 {
 			// Avoid confusing "unrecognized image format"
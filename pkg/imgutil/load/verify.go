@@ -0,0 +1,123 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package load
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// verifyingReader passes an image archive through while verifying, in a background goroutine,
+// that the blobs named after their digest actually match that digest.
+type verifyingReader struct {
+	r    io.Reader
+	pw   *io.PipeWriter
+	done chan error
+	once sync.Once
+	err  error
+}
+
+func newVerifyingReader(r io.Reader) *verifyingReader {
+	pr, pw := io.Pipe()
+	v := &verifyingReader{
+		r:    r,
+		pw:   pw,
+		done: make(chan error, 1),
+	}
+	go func() {
+		err := verifyArchive(pr)
+		// Keep consuming so that Read never blocks after a failed verification
+		_, _ = io.Copy(io.Discard, pr)
+		v.done <- err
+	}()
+	return v
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	if n > 0 {
+		if _, werr := v.pw.Write(p[:n]); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Wait stops the verification and returns its result. The content read so far is verified.
+func (v *verifyingReader) Wait() error {
+	v.once.Do(func() {
+		v.pw.Close()
+		v.err = <-v.done
+	})
+	return v.err
+}
+
+// verifyArchive verifies the blobs of an OCI image layout (`blobs/<algorithm>/<encoded>`),
+// and the image configs of a Docker archive (`<encoded>.json`).
+// The layers of a Docker archive are named after their v1 ID, not their digest, and are verified against
+// the diffIDs of the image config when the image is unpacked.
+func verifyArchive(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			// Malformed archives are reported by the importer
+			return nil
+		}
+		//nolint:staticcheck // TypeRegA is deprecated but we may still receive an external tar with TypeRegA
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		expected, ok := archiveBlobDigest(name)
+		if !ok {
+			continue
+		}
+		verifier := expected.Verifier()
+		if _, err := io.Copy(verifier, tr); err != nil {
+			return nil
+		}
+		if !verifier.Verified() {
+			return fmt.Errorf("content of %q does not match its digest %s: the archive is corrupted", hdr.Name, expected)
+		}
+	}
+}
+
+// archiveBlobDigest returns the digest expected for the archive entry name, if the entry is named after its digest.
+func archiveBlobDigest(name string) (digest.Digest, bool) {
+	var dgst digest.Digest
+	if parts := strings.Split(name, "/"); len(parts) == 3 && parts[0] == "blobs" {
+		dgst = digest.NewDigestFromEncoded(digest.Algorithm(parts[1]), parts[2])
+	} else if encoded, ok := strings.CutSuffix(name, ".json"); ok && !strings.Contains(encoded, "/") {
+		dgst = digest.NewDigestFromEncoded(digest.SHA256, encoded)
+	} else {
+		return "", false
+	}
+	if !dgst.Algorithm().Available() || dgst.Validate() != nil {
+		return "", false
+	}
+	return dgst, true
+}
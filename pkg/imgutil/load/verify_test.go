@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package load
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+)
+
+func testArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(data)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestVerifyingReader(t *testing.T) {
+	blob := []byte("hello")
+	dgst := digest.FromBytes(blob)
+	other := digest.FromBytes([]byte("world"))
+
+	testCases := []struct {
+		name    string
+		files   map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "valid OCI layout",
+			files: map[string][]byte{
+				"oci-layout":                     []byte(`{"imageLayoutVersion":"1.0.0"}`),
+				"blobs/sha256/" + dgst.Encoded(): blob,
+			},
+		},
+		{
+			name: "corrupted OCI blob",
+			files: map[string][]byte{
+				"blobs/sha256/" + other.Encoded(): blob,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid Docker config",
+			files: map[string][]byte{
+				"manifest.json":          []byte("[]"),
+				dgst.Encoded() + ".json": blob,
+			},
+		},
+		{
+			name: "corrupted Docker config",
+			files: map[string][]byte{
+				other.Encoded() + ".json": blob,
+			},
+			wantErr: true,
+		},
+		{
+			name: "entries not named after a digest are ignored",
+			files: map[string][]byte{
+				"0123/layer.tar": blob,
+				"repositories":   blob,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := testArchive(t, tc.files)
+			r := newVerifyingReader(bytes.NewReader(archive))
+			out, err := io.ReadAll(r)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, out, archive)
+			if tc.wantErr {
+				assert.ErrorContains(t, r.Wait(), "does not match its digest")
+			} else {
+				assert.NilError(t, r.Wait())
+			}
+		})
+	}
+}
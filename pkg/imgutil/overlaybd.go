@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/accelerated-container-image/pkg/label"
+	"github.com/containerd/containerd/v2/core/snapshots"
+)
+
+// IsOverlaybdSnapshotter returns true for overlaybd-compatible snapshotters (e.g., "overlaybd", "overlaybd-v2"),
+// with the same fuzzy matching as the pull options of the remote snapshotters.
+func IsOverlaybdSnapshotter(snapshotter string) bool {
+	return strings.Contains(snapshotter, snapshotterNameOverlaybd)
+}
+
+// isOverlaybdManifest returns true when the layers of the manifest are overlaybd blobs,
+// e.g., the images converted with `nerdctl image convert --overlaybd`.
+func isOverlaybdManifest(manifest *ocispec.Manifest) bool {
+	if manifest == nil || len(manifest.Layers) == 0 {
+		return false
+	}
+	for _, l := range manifest.Layers {
+		if _, ok := l.Annotations[label.OverlayBDBlobDigest]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RootfsSnapshotOpts returns the options of the active snapshot of the container rootfs.
+//
+// For overlaybd-compatible snapshotters, the snapshot is labeled with the image reference,
+// which the snapshotter records for the trace-based prefetch and for fetching the remote blobs of the image.
+// An overlaybd image cannot be run with other snapshotters, as its layers are unpacked as the raw block device blobs.
+func RootfsSnapshotOpts(ctx context.Context, img *EnsuredImage) ([]snapshots.Opt, error) {
+	if IsOverlaybdSnapshotter(img.Snapshotter) {
+		return []snapshots.Opt{snapshots.WithLabels(map[string]string{
+			label.TargetImageRef: img.Ref,
+		})}, nil
+	}
+	manifest, _, err := ReadManifest(ctx, img.Image)
+	if err != nil {
+		return nil, err
+	}
+	if isOverlaybdManifest(manifest) {
+		return nil, fmt.Errorf("image %q consists of overlaybd layers, which require an overlaybd snapshotter (e.g., --snapshotter=overlaybd), not %q", img.Ref, img.Snapshotter)
+	}
+	return nil, checkErofsLayers(img.Ref, manifest, img.Snapshotter)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/accelerated-container-image/pkg/label"
+)
+
+func TestIsOverlaybdSnapshotter(t *testing.T) {
+	assert.Assert(t, IsOverlaybdSnapshotter("overlaybd"))
+	assert.Assert(t, IsOverlaybdSnapshotter("overlaybd-v2"))
+	assert.Assert(t, !IsOverlaybdSnapshotter("overlayfs"))
+	assert.Assert(t, !IsOverlaybdSnapshotter("stargz"))
+}
+
+func TestIsOverlaybdManifest(t *testing.T) {
+	obdLayer := ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageLayer,
+		Annotations: map[string]string{label.OverlayBDBlobDigest: "sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+	}
+	tarLayer := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip}
+
+	assert.Assert(t, !isOverlaybdManifest(nil))
+	assert.Assert(t, !isOverlaybdManifest(&ocispec.Manifest{}))
+	assert.Assert(t, isOverlaybdManifest(&ocispec.Manifest{Layers: []ocispec.Descriptor{obdLayer, obdLayer}}))
+	assert.Assert(t, !isOverlaybdManifest(&ocispec.Manifest{Layers: []ocispec.Descriptor{tarLayer}}))
+	assert.Assert(t, !isOverlaybdManifest(&ocispec.Manifest{Layers: []ocispec.Descriptor{obdLayer, tarLayer}}))
+}
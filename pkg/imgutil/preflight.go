@@ -0,0 +1,99 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/defaults"
+	"github.com/containerd/containerd/v2/plugins"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+)
+
+// preflightTimeout is the timeout of the request to the remote snapshotter daemon
+const preflightTimeout = 10 * time.Second
+
+// snapshotterDocs are the documents of setting up the remote snapshotters
+var snapshotterDocs = map[string]string{
+	snapshotterNameOverlaybd: "overlaybd.md",
+	snapshotterNameStargz:    "stargz.md",
+	snapshotterNameNydus:     "nydus.md",
+	snapshotterNameSoci:      "soci.md",
+	snapshotterNameCvmfs:     "cvmfs.md",
+	snapshotterNameErofs:     "erofs.md",
+}
+
+// PreflightSnapshotter checks that the remote snapshotter is registered in containerd and that its daemon is responding,
+// so that a missing snapshotter fails fast with a diagnostic, instead of with cryptic containerd errors in the middle of pulls.
+// The erofs snapshotter is checked as well, as it fails to initialize without the EROFS kernel module.
+//
+// When fallback is true, the default snapshotter is returned with a warning instead of the error.
+// The other snapshotters are returned as is.
+func PreflightSnapshotter(ctx context.Context, client *containerd.Client, snapshotter string, fallback bool) (string, error) {
+	if !getSnapshotterOpts(snapshotter).isRemote() && snapshotter != snapshotterNameErofs {
+		return snapshotter, nil
+	}
+	err := checkSnapshotter(ctx, client, snapshotter)
+	if err == nil {
+		return snapshotter, nil
+	}
+	if fallback && snapshotter != defaults.DefaultSnapshotter {
+		log.G(ctx).WithError(err).Warnf("falling back to the default snapshotter %q", defaults.DefaultSnapshotter)
+		return defaults.DefaultSnapshotter, nil
+	}
+	hint := "specify --snapshotter-fallback to fall back to the default snapshotter"
+	for sn, doc := range snapshotterDocs {
+		if strings.Contains(snapshotter, sn) {
+			hint = fmt.Sprintf("see https://github.com/containerd/nerdctl/blob/main/docs/%s for setting up the snapshotter, or %s", doc, hint)
+			break
+		}
+	}
+	return "", fmt.Errorf("%w (hint: %s)", err, hint)
+}
+
+func checkSnapshotter(ctx context.Context, client *containerd.Client, snapshotter string) error {
+	filter := fmt.Sprintf("type==%s,id==%s", plugins.SnapshotPlugin, strconv.Quote(snapshotter))
+	res, err := client.IntrospectionService().Plugins(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to check the snapshotter %q: %w", snapshotter, err)
+	}
+	if len(res.Plugins) == 0 {
+		if snapshotter == snapshotterNameErofs {
+			return fmt.Errorf("snapshotter %q is not available in containerd (containerd v2.1 or later is required)", snapshotter)
+		}
+		return fmt.Errorf("snapshotter %q is not configured in containerd (missing [proxy_plugins.%s] in the containerd config)", snapshotter, snapshotter)
+	}
+	if initErr := res.Plugins[0].InitErr; initErr != nil {
+		return fmt.Errorf("snapshotter %q failed to initialize in containerd: %s", snapshotter, initErr.Message)
+	}
+
+	// The proxy plugins are registered regardless of their daemons, so the daemon is checked with a request.
+	// A snapshot that does not exist is looked up, as the request to a running daemon fails with ErrNotFound.
+	ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+	_, err = client.SnapshotService(snapshotter).Stat(ctx, "nerdctl-preflight")
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("snapshotter %q is not responding (is its daemon running?): %w", snapshotter, err)
+	}
+	return nil
+}
@@ -38,6 +38,8 @@ type Config struct {
 	Resolver remotes.Resolver
 	// ProgressOutput to display progress
 	ProgressOutput io.Writer
+	// ProgressMode is the mode of the global `--progress` flag ("auto" if empty)
+	ProgressMode string
 	// RemoteOpts, e.g. containerd.WithPullUnpack.
 	//
 	// Regardless to RemoteOpts, the following opts are always set:
@@ -58,7 +60,7 @@ func Pull(ctx context.Context, client *containerd.Client, ref string, config *Co
 	go func() {
 		if config.ProgressOutput != nil {
 			// no progress bar, because it hides some debug logs
-			jobs.ShowProgress(pctx, ongoing, client.ContentStore(), config.ProgressOutput)
+			jobs.ShowProgress(pctx, ongoing, client.ContentStore(), jobs.NewPrinter(config.ProgressOutput, config.ProgressMode, "pull"))
 		}
 		close(progress)
 	}()
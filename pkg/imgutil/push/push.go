@@ -20,9 +20,7 @@ package push
 import (
 	"context"
 	"fmt"
-	"io"
 	"sync"
-	"text/tabwriter"
 	"time"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -32,7 +30,6 @@ import (
 	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/remotes"
 	"github.com/containerd/containerd/v2/core/remotes/docker"
-	"github.com/containerd/containerd/v2/pkg/progress"
 	"github.com/containerd/log"
 	"github.com/containerd/platforms"
 
@@ -40,8 +37,9 @@ import (
 )
 
 // Push pushes an image to a remote registry.
-func Push(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, pushTracker docker.StatusTracker, stdout io.Writer,
-	localRef, remoteRef string, platform platforms.MatchComparer, allowNonDist, quiet bool) error {
+// The progress is printed with printer, unless it is nil.
+func Push(ctx context.Context, client *containerd.Client, resolver remotes.Resolver, pushTracker docker.StatusTracker, printer *jobs.Printer,
+	localRef, remoteRef string, platform platforms.MatchComparer, allowNonDist bool) error {
 	img, err := client.ImageService().Get(ctx, localRef)
 	if err != nil {
 		return fmt.Errorf("unable to resolve image to manifest: %w", err)
@@ -78,11 +76,10 @@ func Push(ctx context.Context, client *containerd.Client, resolver remotes.Resol
 		)
 	})
 
-	if !quiet {
+	if printer != nil {
 		eg.Go(func() error {
 			var (
 				ticker = time.NewTicker(100 * time.Millisecond)
-				fw     = progress.NewWriter(stdout)
 				start  = time.Now()
 				done   bool
 			)
@@ -92,15 +89,9 @@ func Push(ctx context.Context, client *containerd.Client, resolver remotes.Resol
 			for {
 				select {
 				case <-ticker.C:
-					fw.Flush()
-
-					tw := tabwriter.NewWriter(fw, 1, 8, 1, ' ', 0)
-
-					jobs.Display(tw, ongoing.status(), start)
-					tw.Flush()
+					printer.Print(ongoing.status(), start, done)
 
 					if done {
-						fw.Flush()
 						return nil
 					}
 				case <-doneCh:
@@ -0,0 +1,65 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	remoteerrors "github.com/containerd/containerd/v2/core/remotes/errors"
+)
+
+const (
+	pullRetryInitialDelay = time.Second
+	pullRetryMaxDelay     = 30 * time.Second
+)
+
+// isRetryablePullError returns true for errors that are likely transient:
+// interrupted connections, timeouts, and 429 or 5xx responses from the registry.
+func isRetryablePullError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr remoteerrors.ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// Connection refused is not retried, as it may hint that the registry does not support HTTPS (see EnsureImage)
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// pullRetryDelay returns the delay before the attempt-th retry (starting from 0), doubling up to pullRetryMaxDelay.
+func pullRetryDelay(attempt int) time.Duration {
+	delay := pullRetryInitialDelay
+	for i := 0; i < attempt && delay < pullRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > pullRetryMaxDelay {
+		delay = pullRetryMaxDelay
+	}
+	return delay
+}
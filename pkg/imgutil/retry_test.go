@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package imgutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	remoteerrors "github.com/containerd/containerd/v2/core/remotes/errors"
+)
+
+func TestIsRetryablePullError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"canceled", fmt.Errorf("failed to copy: %w", context.Canceled), false},
+		{"unexpected EOF", fmt.Errorf("short read: %w", io.ErrUnexpectedEOF), true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), false},
+		{"503", remoteerrors.ErrUnexpectedStatus{StatusCode: http.StatusServiceUnavailable}, true},
+		{"429", fmt.Errorf("fetch: %w", remoteerrors.ErrUnexpectedStatus{StatusCode: http.StatusTooManyRequests}), true},
+		{"404", remoteerrors.ErrUnexpectedStatus{StatusCode: http.StatusNotFound}, false},
+		{"other", errors.New("unauthorized"), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, isRetryablePullError(tc.err), tc.expected)
+		})
+	}
+}
+
+func TestPullRetryDelay(t *testing.T) {
+	assert.Equal(t, pullRetryDelay(0), time.Second)
+	assert.Equal(t, pullRetryDelay(1), 2*time.Second)
+	assert.Equal(t, pullRetryDelay(3), 8*time.Second)
+	assert.Equal(t, pullRetryDelay(10), 30*time.Second)
+}
@@ -17,16 +17,23 @@
 package imgutil
 
 import (
+	"context"
 	"strings"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/images"
 	ctdsnapshotters "github.com/containerd/containerd/v2/pkg/snapshotters"
 	"github.com/containerd/log"
+	nyduslabel "github.com/containerd/nydus-snapshotter/pkg/label"
 	"github.com/containerd/stargz-snapshotter/fs/source"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/pull"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/snapshotterutil"
 )
 
@@ -45,7 +52,7 @@ const (
 var builtinRemoteSnapshotterOpts = map[string]snapshotterOpts{
 	snapshotterNameOverlaybd: &remoteSnapshotterOpts{snapshotter: "overlaybd"},
 	snapshotterNameStargz:    &remoteSnapshotterOpts{snapshotter: "stargz", extraLabels: stargzExtraLabels},
-	snapshotterNameNydus:     &remoteSnapshotterOpts{snapshotter: "nydus"},
+	snapshotterNameNydus:     &remoteSnapshotterOpts{snapshotter: "nydus", extraLabels: nydusExtraLabels},
 	snapshotterNameSoci:      &remoteSnapshotterOpts{snapshotter: "soci", extraLabels: sociExtraLabels},
 	snapshotterNameCvmfs:     &remoteSnapshotterOpts{snapshotter: "cvmfs-snapshotter"},
 }
@@ -71,6 +78,11 @@ func getSnapshotterOpts(snapshotter string) snapshotterOpts {
 	return &defaultSnapshotterOpts{snapshotter: snapshotter}
 }
 
+// IsRemoteSnapshotter returns true if the snapshotter is a remote (lazy-pulling) snapshotter handled by nerdctl.
+func IsRemoteSnapshotter(snapshotter string) bool {
+	return getSnapshotterOpts(snapshotter).isRemote()
+}
+
 // remoteSnapshotterOpts is used as a remote snapshotter implementation for
 // interface `snapshotterOpts.isRemote()` function
 type remoteSnapshotterOpts struct {
@@ -112,9 +124,82 @@ func (dsn *defaultSnapshotterOpts) isRemote() bool {
 }
 
 func stargzExtraLabels(f func(images.Handler) images.Handler, rFlags types.RemoteSnapshotterFlags) func(images.Handler) images.Handler {
+	warnPullCredentials(snapshotterNameStargz, rFlags)
 	return source.AppendExtraLabelsHandler(prefetchSize, f)
 }
 
 func sociExtraLabels(f func(images.Handler) images.Handler, rFlags types.RemoteSnapshotterFlags) func(images.Handler) images.Handler {
+	warnPullCredentials(snapshotterNameSoci, rFlags)
 	return snapshotterutil.SociAppendDefaultLabelsHandlerWrapper(rFlags.SociIndexDigest, f)
 }
+
+// warnPullCredentials warns that the registry credentials are not passed to the snapshotter,
+// as stargz and soci have no snapshot labels for the credentials, unlike nydus.
+func warnPullCredentials(snapshotter string, rFlags types.RemoteSnapshotterFlags) {
+	if rFlags.PullCredentials == nil {
+		return
+	}
+	if username, secret, err := rFlags.PullCredentials(); err != nil || (username == "" && secret == "") {
+		return
+	}
+	log.L.Warnf("the registry credentials are not passed to the %s snapshotter, "+
+		"the layers are lazily pulled only if the snapshotter is configured with the credentials (see docs/%s.md)", snapshotter, snapshotter)
+}
+
+// nydusExtraLabels passes the registry credentials to nydus-snapshotter via the pullusername and pullsecret labels,
+// so that nydusd can lazily pull from private registries without its own auth configuration.
+func nydusExtraLabels(f func(images.Handler) images.Handler, rFlags types.RemoteSnapshotterFlags) func(images.Handler) images.Handler {
+	if rFlags.PullCredentials == nil {
+		return f
+	}
+	return func(h images.Handler) images.Handler {
+		return images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+			children, err := f(h).Handle(ctx, desc)
+			if err != nil {
+				return nil, err
+			}
+			switch desc.MediaType {
+			case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+				username, secret, err := rFlags.PullCredentials()
+				if err != nil {
+					log.G(ctx).WithError(err).Warn("failed to get the registry credentials for nydus-snapshotter")
+					return children, nil
+				}
+				// nydus-snapshotter ignores the credentials unless both are set
+				if username == "" || secret == "" {
+					return children, nil
+				}
+				for i := range children {
+					c := &children[i]
+					if images.IsLayerType(c.MediaType) {
+						if c.Annotations == nil {
+							c.Annotations = make(map[string]string)
+						}
+						c.Annotations[nyduslabel.NydusImagePullUsername] = username
+						c.Annotations[nyduslabel.NydusImagePullSecret] = secret
+					}
+				}
+			}
+			return children, nil
+		})
+	}
+}
+
+// pullCredentials returns the credentials of the registry of ref, looked up in the same way as the resolver does.
+// Returns nil for references without a registry (e.g., ipfs://).
+func pullCredentials(ctx context.Context, ref string, registries map[string]config.RegistryConfig) (func() (string, string, error), error) {
+	parsedReference, err := referenceutil.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	if parsedReference.Domain == "" {
+		return nil, nil
+	}
+	authCreds, err := dockerconfigresolver.NewRegistryAuthCreds(ctx, parsedReference.Domain, registries)
+	if err != nil {
+		return nil, err
+	}
+	return func() (string, string, error) {
+		return authCreds(parsedReference.Domain)
+	}, nil
+}
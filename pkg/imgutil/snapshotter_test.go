@@ -27,6 +27,7 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	ctdsnapshotters "github.com/containerd/containerd/v2/pkg/snapshotters"
+	nyduslabel "github.com/containerd/nydus-snapshotter/pkg/label"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil/pull"
@@ -65,7 +66,7 @@ func TestGetSnapshotterOpts(t *testing.T) {
 		},
 		{
 			sns:   []string{"nydus", "nydus-v3"},
-			check: sameOpts(&remoteSnapshotterOpts{snapshotter: "nydus"}),
+			check: remoteSnOpts("nydus", true),
 		},
 	}
 	for _, tc := range testCases {
@@ -202,3 +203,46 @@ func checkSociSnapshotterAnnotataions(t *testing.T, a map[string]string) {
 	assert.Equal(t, ok, true)
 
 }
+
+func TestNydusPullCredentials(t *testing.T) {
+	tests := []struct {
+		name             string
+		username, secret string
+		want             bool
+	}{
+		{name: "both", username: "user", secret: "pass", want: true},
+		{name: "token only", username: "", secret: "identity-token", want: false},
+		{name: "none", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &pull.Config{}
+			rFlags := types.RemoteSnapshotterFlags{
+				PullCredentials: func() (string, string, error) {
+					return tt.username, tt.secret, nil
+				},
+			}
+			getSnapshotterOpts("nydus").apply(config, testRef, rFlags)
+			rc := &containerd.RemoteContext{}
+			for _, o := range config.RemoteOpts {
+				assert.NilError(t, o(nil, rc))
+			}
+
+			desc := ocispec.Descriptor{
+				MediaType: ocispec.MediaTypeImageManifest,
+			}
+			got, err := rc.HandlerWrapper(&dummyImageHandler{}).Handle(context.Background(), desc)
+			assert.NilError(t, err)
+			assert.Check(t, len(got) == 1)
+			checkRemoteSnapshotterAnnotataions(t, got[0].Annotations)
+			username, ok := got[0].Annotations[nyduslabel.NydusImagePullUsername]
+			assert.Equal(t, ok, tt.want)
+			secret, ok := got[0].Annotations[nyduslabel.NydusImagePullSecret]
+			assert.Equal(t, ok, tt.want)
+			if tt.want {
+				assert.Equal(t, username, tt.username)
+				assert.Equal(t, secret, tt.secret)
+			}
+		})
+	}
+}
@@ -22,11 +22,13 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/opencontainers/go-digest"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/introspection"
@@ -82,7 +84,14 @@ func Info(ctx context.Context, client *containerd.Client, snapshotter, cgroupMan
 	info.ID = daemonIntro.UUID
 	// Storage drivers and logging drivers are not really Server concept for nerdctl, but mimics `docker info` output
 	info.Driver = snapshotter
+	info.DriverStatus = [][2]string{{"driver-type", "io.containerd.snapshotter.v1"}}
 	info.Plugins.Storage = snapshotterPlugins
+	if snapshotter != "" && !slices.Contains(snapshotterPlugins, snapshotter) {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("WARNING: snapshotter %q is not available", snapshotter))
+	}
+	if err := fulfillContainersAndImages(ctx, client, &info); err != nil {
+		return nil, err
+	}
 	info.SystemTime = time.Now().Format(time.RFC3339Nano)
 	info.LoggingDriver = "json-file" // hard-coded
 	info.CgroupDriver = cgroupManager
@@ -100,6 +109,46 @@ func Info(ctx context.Context, client *containerd.Client, snapshotter, cgroupMan
 	return &info, nil
 }
 
+// fulfillContainersAndImages counts the containers and the images in the current namespace.
+// Like Docker, containers that are neither running nor paused are counted as stopped.
+func fulfillContainersAndImages(ctx context.Context, client *containerd.Client, info *dockercompat.Info) error {
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		info.Containers++
+		var status containerd.ProcessStatus
+		task, err := c.Task(ctx, nil)
+		if err == nil {
+			st, err := task.Status(ctx)
+			if err == nil {
+				status = st.Status
+			}
+		}
+		switch status {
+		case containerd.Running:
+			info.ContainersRunning++
+		case containerd.Paused, containerd.Pausing:
+			info.ContainersPaused++
+		default:
+			info.ContainersStopped++
+		}
+	}
+
+	images, err := client.ImageService().List(ctx)
+	if err != nil {
+		return err
+	}
+	// Images sharing the same target (e.g., multiple tags of an image) are counted once
+	targets := make(map[digest.Digest]struct{})
+	for _, img := range images {
+		targets[img.Target.Digest] = struct{}{}
+	}
+	info.Images = len(targets)
+	return nil
+}
+
 func GetSnapshotterNames(ctx context.Context, introService introspection.Service) ([]string, error) {
 	var names []string
 	plugins, err := introService.Plugins(ctx)
@@ -48,8 +48,8 @@ func fulfillSecurityOptions(info *dockercompat.Info) {
 		if rootlessutil.IsRootless() && !apparmorutil.CanApplySpecificExistingProfile(defaults.AppArmorProfileName) {
 			info.Warnings = append(info.Warnings, fmt.Sprintf(strings.TrimSpace(`
 WARNING: AppArmor profile %q is not loaded.
-         Use 'sudo nerdctl apparmor load' if you prefer to use AppArmor with rootless mode.
-         This warning is negligible if you do not intend to use AppArmor.`), defaults.AppArmorProfileName))
+         Hint: %s if you prefer to use AppArmor with rootless mode.
+         This warning is negligible if you do not intend to use AppArmor.`), defaults.AppArmorProfileName, apparmorutil.InstallHint))
 		}
 	}
 	info.SecurityOptions = append(info.SecurityOptions, "name=seccomp,profile="+defaults.SeccompProfileName)
@@ -66,8 +66,7 @@ type Image struct {
 	Architecture  string
 	Variant       string `json:",omitempty"`
 	Os            string
-
-	// TODO: OsVersion     string `json:",omitempty"`
+	OsVersion     string `json:",omitempty"`
 
 	Size        int64 // Size is the unpacked size of the image
 	VirtualSize int64 `json:"VirtualSize,omitempty"` // Deprecated
@@ -163,10 +162,10 @@ type HostConfig struct {
 	PidMode     string // PID namespace to use for the container
 	// Privileged      bool              // Is the container in privileged mode
 	// PublishAllPorts bool              // Should docker publish all exposed port for the container
-	ReadonlyRootfs bool // Is the container root filesystem in read-only
-	// SecurityOpt     []string          // List of string values to customize labels for MLS systems, such as SELinux.
-	Tmpfs   map[string]string `json:"Tmpfs,omitempty"` // List of tmpfs (mounts) used for the container
-	UTSMode string            // UTS namespace to use for the container
+	ReadonlyRootfs bool              // Is the container root filesystem in read-only
+	SecurityOpt    []string          // List of string values to customize labels for MLS systems, such as SELinux.
+	Tmpfs          map[string]string `json:"Tmpfs,omitempty"` // List of tmpfs (mounts) used for the container
+	UTSMode        string            // UTS namespace to use for the container
 	// UsernsMode      UsernsMode        // The user namespace to use for the container
 	ShmSize            int64             // Size of /dev/shm in bytes. The size must be greater than 0.
 	Sysctls            map[string]string // List of Namespaced sysctls used for the container
@@ -260,6 +259,7 @@ type HostConfigLabel struct {
 	BlkioWeight uint16
 	CidFile     string
 	Devices     []DeviceMapping
+	SecurityOpt []string
 }
 
 type DeviceMapping struct {
@@ -413,6 +413,7 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 
 	c.HostConfig.BlkioWeight = hostConfigLabel.BlkioWeight
 	c.HostConfig.ContainerIDFile = hostConfigLabel.CidFile
+	c.HostConfig.SecurityOpt = hostConfigLabel.SecurityOpt
 
 	groupAdd, err := groupAddFromNative(n.Spec.(*specs.Spec))
 	if err != nil {
@@ -607,11 +608,31 @@ func ImageFromNative(nativeImage *native.Image) (*Image, error) {
 		Architecture: imgOCI.Architecture,
 		Variant:      imgOCI.Platform.Variant,
 		Os:           imgOCI.OS,
+		OsVersion:    imgOCI.OSVersion,
 		Size:         nativeImage.Size,
 		VirtualSize:  nativeImage.Size,
 		RepoTags:     []string{fmt.Sprintf("%s:%s", repository, tag)},
 		RepoDigests:  []string{fmt.Sprintf("%s@%s", repository, nativeImage.Image.Target.Digest.String())},
 	}
+	image.Metadata.LastTagTime = nativeImage.Image.UpdatedAt
+
+	// Some images (e.g. those produced by old builders) do not carry the platform in the config,
+	// so fall back to the platform recorded in the index for the selected manifest.
+	if nativeImage.ManifestDesc != nil && nativeImage.ManifestDesc.Platform != nil {
+		p := nativeImage.ManifestDesc.Platform
+		if image.Architecture == "" {
+			image.Architecture = p.Architecture
+		}
+		if image.Os == "" {
+			image.Os = p.OS
+		}
+		if image.Variant == "" {
+			image.Variant = p.Variant
+		}
+		if image.OsVersion == "" {
+			image.OsVersion = p.OSVersion
+		}
+	}
 
 	if len(imgOCI.History) > 0 {
 		image.Comment = imgOCI.History[len(imgOCI.History)-1].Comment
@@ -622,6 +643,9 @@ func ImageFromNative(nativeImage *native.Image) (*Image, error) {
 	}
 
 	image.RootFS.Type = imgOCI.RootFS.Type
+	if image.RootFS.Type == "" {
+		image.RootFS.Type = "layers"
+	}
 	for _, d := range imgOCI.RootFS.DiffIDs {
 		image.RootFS.Layers = append(image.RootFS.Layers, d.String())
 	}
@@ -924,6 +948,7 @@ type Network struct {
 	Labels     map[string]string           `json:"Labels"`
 	Containers map[string]EndpointResource `json:"Containers"` // Containers contains endpoints belonging to the network
 	// Scope, Driver, etc. are omitted
+	Interface *native.NetworkInterface `json:"Interface,omitempty"` // nerdctl extension
 }
 
 type EndpointResource struct {
@@ -973,6 +998,8 @@ func NetworkFromNative(n *native.Network) (*Network, error) {
 		res.Labels = *n.NerdctlLabels
 	}
 
+	res.Interface = n.Interface
+
 	res.Containers = make(map[string]EndpointResource)
 	for _, container := range n.Containers {
 		res.Containers[container.ID] = EndpointResource{
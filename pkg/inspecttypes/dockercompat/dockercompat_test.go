@@ -627,6 +627,40 @@ func TestImageFromNative(t *testing.T) {
 		assert.Equal(t, out.Created, createdTime.Format(time.RFC3339Nano))
 	})
 
+	t.Run("parses platform, exposed ports and LastTagTime", func(t *testing.T) {
+		tagTime := time.Now().UTC()
+
+		img := native.Image{
+			Image: images.Image{
+				Name:      "myrepo/myimage:custom",
+				UpdatedAt: tagTime,
+			},
+			ManifestDesc: &ocispec.Descriptor{
+				Platform: &ocispec.Platform{
+					OS:           "linux",
+					Architecture: "arm",
+					Variant:      "v7",
+				},
+			},
+			ImageConfig: ocispec.Image{
+				Config: ocispec.ImageConfig{
+					ExposedPorts: map[string]struct{}{"80/tcp": {}},
+				},
+			},
+		}
+
+		out, err := ImageFromNative(&img)
+		assert.NilError(t, err)
+
+		assert.Equal(t, out.Os, "linux")
+		assert.Equal(t, out.Architecture, "arm")
+		assert.Equal(t, out.Variant, "v7")
+		assert.Equal(t, out.RootFS.Type, "layers")
+		assert.Equal(t, out.Metadata.LastTagTime, tagTime)
+		_, ok := out.Config.ExposedPorts["80/tcp"]
+		assert.Assert(t, ok)
+	})
+
 	t.Run("parses Healthcheck label", func(t *testing.T) {
 		testcases := []struct {
 			name     string
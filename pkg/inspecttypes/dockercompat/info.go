@@ -24,14 +24,22 @@
 
 package dockercompat
 
+import "github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+
 // Info mimics a `docker info` object.
 // From https://github.com/moby/moby/blob/v20.10.8/api/types/types.go#L146-L216
 type Info struct {
-	ID          string
-	Driver      string
-	Plugins     PluginsInfo
-	MemoryLimit bool
-	SwapLimit   bool
+	ID                string
+	Containers        int
+	ContainersRunning int
+	ContainersPaused  int
+	ContainersStopped int
+	Images            int
+	Driver            string
+	DriverStatus      [][2]string
+	Plugins           PluginsInfo
+	MemoryLimit       bool
+	SwapLimit         bool
 	// KernelMemory is omitted because it is deprecated in the Moby
 	CPUCfsPeriod      bool `json:"CpuCfsPeriod"`
 	CPUCfsQuota       bool `json:"CpuCfsQuota"`
@@ -57,8 +65,13 @@ type Info struct {
 	NCPU            int
 	MemTotal        int64
 	Name            string
-	ServerVersion   string
-	SecurityOptions []string
+	// DockerRootDir is the data root directory of nerdctl, not of containerd
+	DockerRootDir     string
+	ExperimentalBuild bool
+	ServerVersion     string
+	SecurityOptions   []string
+	// RootlessNetwork is set only for rootless mode
+	RootlessNetwork *native.RootlessNetwork `json:",omitempty"` // nerdctl extension
 
 	Warnings []string
 }
@@ -27,6 +27,8 @@ type Info struct {
 	CgroupManager string      `json:"CgroupManager,omitempty"`
 	Rootless      bool        `json:"Rootless,omitempty"`
 	Daemon        *DaemonInfo `json:"Daemon,omitempty"`
+	// RootlessNetwork is set only for rootless mode
+	RootlessNetwork *RootlessNetwork `json:"RootlessNetwork,omitempty"`
 }
 
 type DaemonInfo struct {
@@ -19,4 +19,25 @@ package native
 type Namespace struct {
 	Name   string             `json:"Name"`
 	Labels *map[string]string `json:"Labels,omitempty"`
+	Usage  *NamespaceUsage    `json:"Usage,omitempty"`
+}
+
+// NamespaceUsage is the disk usage of a namespace, printed by `nerdctl namespace inspect --usage`.
+type NamespaceUsage struct {
+	// Images is the number of the images, and the size of their blobs in the content store
+	Images ResourceUsage `json:"Images"`
+	// Snapshots is the number of the snapshots of the snapshotter, and their size
+	Snapshots ResourceUsage `json:"Snapshots"`
+	// Containers is the number of the containers, and the size of their writable layers
+	Containers ResourceUsage `json:"Containers"`
+	// Size is the total size of the blobs and the snapshots.
+	// The writable layers of the containers are snapshots, so they are not counted twice.
+	Size int64 `json:"Size"`
+	// Quota is the size configured in the [quotas.<NAMESPACE>] table of nerdctl.toml
+	Quota int64 `json:"Quota,omitempty"`
+}
+
+type ResourceUsage struct {
+	Count int   `json:"Count"`
+	Size  int64 `json:"Size"`
 }
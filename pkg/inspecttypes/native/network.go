@@ -25,4 +25,23 @@ type Network struct {
 	NerdctlLabels *map[string]string `json:"NerdctlLabels,omitempty"`
 	File          string             `json:"File,omitempty"`
 	Containers    []*Container       `json:"Containers"`
+	// Interface is the bridge interface of the network, in the network namespace of RootlessKit for rootless mode
+	Interface *NetworkInterface `json:"Interface,omitempty"`
+}
+
+// NetworkInterface is the state of a network interface
+type NetworkInterface struct {
+	Name         string   `json:"Name"`
+	Index        int      `json:"Index"`
+	State        string   `json:"State"` // e.g., "up", "down"
+	MTU          int      `json:"MTU"`
+	HardwareAddr string   `json:"HardwareAddr,omitempty"`
+	Addrs        []string `json:"Addrs,omitempty"`
+}
+
+// RootlessNetwork is the network namespace of RootlessKit, where the networks of rootless containers are created
+type RootlessNetwork struct {
+	// NetNS is the path of the detached network namespace, empty unless RootlessKit is running with --detach-netns
+	NetNS      string             `json:"NetNS,omitempty"`
+	Interfaces []NetworkInterface `json:"Interfaces"`
 }
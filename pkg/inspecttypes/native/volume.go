@@ -18,8 +18,22 @@ package native
 
 // Volume is also compatible with Docker
 type Volume struct {
-	Name       string             `json:"Name"`
+	Name string `json:"Name"`
+	// Driver is the volume plugin, empty for the local driver
+	Driver     string             `json:"Driver,omitempty"`
 	Mountpoint string             `json:"Mountpoint"`
 	Labels     *map[string]string `json:"Labels,omitempty"`
+	Options    *map[string]string `json:"Options,omitempty"`
 	Size       int64              `json:"Size,omitempty"`
+	UsageData  *VolumeUsageData   `json:"UsageData,omitempty"`
+	// Status is the low-level status of the volume, e.g., the enforcement of its size limit
+	Status map[string]any `json:"Status,omitempty"`
+}
+
+// VolumeUsageData is the usage of a volume, shown by `nerdctl volume inspect --size`
+type VolumeUsageData struct {
+	// Size is the disk usage of the volume in bytes, or -1 when it is not available (e.g. for plugin volumes)
+	Size int64 `json:"Size"`
+	// RefCount is the number of containers referencing the volume, or -1 when it is not available
+	RefCount int64 `json:"RefCount"`
 }
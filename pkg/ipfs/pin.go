@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PinningServiceOptions represents options to pin a CID to a remote pinning service
+// implementing the IPFS Pinning Service API (https://ipfs.github.io/pinning-services-api-spec/),
+// such as Pinata (https://api.pinata.cloud/psa) or web3.storage.
+type PinningServiceOptions struct {
+	// Endpoint is the base URL of the pinning service API.
+	Endpoint string
+	// Token is the bearer token used for authenticating to the pinning service.
+	Token string
+	// Name is an optional human-readable name of the pin.
+	Name string
+	// Origins is an optional list of multiaddrs of nodes that are known to provide the data.
+	Origins []string
+}
+
+// PinStatus is the status of a pin request, as defined by the IPFS Pinning Service API.
+type PinStatus struct {
+	RequestID string    `json:"requestid"`
+	Status    string    `json:"status"`
+	Created   time.Time `json:"created"`
+	Delegates []string  `json:"delegates"`
+}
+
+type pin struct {
+	CID     string   `json:"cid"`
+	Name    string   `json:"name,omitempty"`
+	Origins []string `json:"origins,omitempty"`
+}
+
+// Pin requests the remote pinning service to pin the specified CID.
+// The pinning service fetches the content asynchronously, so the returned status is usually "queued" or "pinning".
+func Pin(ctx context.Context, cid string, options PinningServiceOptions) (*PinStatus, error) {
+	if options.Endpoint == "" {
+		return nil, fmt.Errorf("pinning service endpoint must be specified")
+	}
+	body, err := json.Marshal(pin{CID: cid, Name: options.Name, Origins: options.Origins})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(options.Endpoint, "/")+"/pins", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if options.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+options.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to pin %q to %q: %s: %s", cid, options.Endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var st PinStatus
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, fmt.Errorf("failed to decode the response of the pinning service: %w", err)
+	}
+	if st.Status == "failed" {
+		return &st, fmt.Errorf("pinning service %q failed to pin %q (request %q)", options.Endpoint, cid, st.RequestID)
+	}
+	return &st, nil
+}
@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ipfs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPin(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/psa/pins" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var p pin
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{
+			"requestid": "req-" + p.Name,
+			"status":    "queued",
+			"pin":       map[string]string{"cid": p.CID},
+		})
+	}))
+	defer srv.Close()
+
+	st, err := Pin(context.Background(), "bafkreitest", PinningServiceOptions{
+		Endpoint: srv.URL + "/psa/",
+		Token:    "secret",
+		Name:     "foo",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, st.RequestID, "req-foo")
+	assert.Equal(t, st.Status, "queued")
+
+	_, err = Pin(context.Background(), "bafkreitest", PinningServiceOptions{
+		Endpoint: srv.URL + "/psa",
+		Token:    "wrong",
+	})
+	assert.ErrorContains(t, err, "401")
+}
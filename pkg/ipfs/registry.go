@@ -31,4 +31,8 @@ type RegistryOptions struct {
 
 	// IpfsPath is the IPFS_PATH value to be used for ipfs command.
 	IpfsPath string
+
+	// Gateways is a list of HTTP gateways (e.g. https://ipfs.io) used as fallback providers
+	// of the contents that cannot be fetched through the IPFS API.
+	Gateways []string
 }
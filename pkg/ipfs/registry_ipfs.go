@@ -41,21 +41,106 @@ import (
 )
 
 func NewRegistry(options RegistryOptions) (http.Handler, error) {
+	var providers []provider
 	// HTTP is only supported as of now. We can add https support here if needed (e.g. for connecting to it via proxy, etc)
 	iurl, err := ipfsclient.GetIPFSAPIAddress(lookupIPFSPath(options.IpfsPath), "http")
 	if err != nil {
-		return nil, err
+		if len(options.Gateways) == 0 {
+			return nil, err
+		}
+		log.L.WithError(err).Warn("IPFS API is not available, serving contents only from the gateways")
+	} else {
+		providers = append(providers, &apiProvider{ipfsclient.New(iurl)})
+	}
+	for _, g := range options.Gateways {
+		providers = append(providers, &gatewayProvider{strings.TrimSuffix(g, "/")})
 	}
-	return &server{options, ipfsclient.New(iurl)}, nil
+	return &server{options, providers}, nil
 }
 
 // server is a read-only registry which converts OCI Distribution Spec's pull-related API to IPFS
 // https://github.com/opencontainers/distribution-spec/blob/v1.0/spec.md#pull
 type server struct {
-	config     RegistryOptions
+	config    RegistryOptions
+	providers []provider
+}
+
+// provider provides contents of CIDs. Providers are queried in order, until one can stat the CID.
+type provider interface {
+	String() string
+	// Size returns the size of the file of the CID.
+	Size(ctx context.Context, c string) (int64, error)
+	// Get returns the specified range of the file of the CID.
+	Get(ctx context.Context, c string, off int64, size int) (io.ReadCloser, error)
+}
+
+// apiProvider provides contents through the IPFS (Kubo) HTTP API.
+type apiProvider struct {
 	ipfsclient *ipfsclient.Client
 }
 
+func (p *apiProvider) String() string {
+	return p.ipfsclient.Address
+}
+
+func (p *apiProvider) Size(ctx context.Context, c string) (int64, error) {
+	st, err := p.ipfsclient.StatCID(c)
+	if err != nil {
+		return 0, err
+	}
+	return int64(st.Size), nil
+}
+
+func (p *apiProvider) Get(ctx context.Context, c string, off int64, size int) (io.ReadCloser, error) {
+	ofst := int(off)
+	return p.ipfsclient.Get("/ipfs/"+c, &ofst, &size)
+}
+
+// gatewayProvider provides contents through an HTTP gateway (https://specs.ipfs.tech/http-gateways/path-gateway/).
+type gatewayProvider struct {
+	url string
+}
+
+func (p *gatewayProvider) String() string {
+	return p.url
+}
+
+func (p *gatewayProvider) Size(ctx context.Context, c string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, p.url+"/ipfs/"+c, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %q from gateway %q", resp.Status, p.url)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("gateway %q did not return the size of %q", p.url, c)
+	}
+	return resp.ContentLength, nil
+}
+
+func (p *gatewayProvider) Get(ctx context.Context, c string, off int64, size int) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"/ipfs/"+c, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(size)-1))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %q from gateway %q", resp.Status, p.url)
+	}
+	return resp.Body, nil
+}
+
 var manifestRegexp = regexp.MustCompile(`/v2/ipfs/([a-z0-9]+)/manifests/(.*)`)
 var blobsRegexp = regexp.MustCompile(`/v2/ipfs/([a-z0-9]+)/blobs/(.*)`)
 
@@ -167,24 +252,33 @@ func (s *server) getReadSeeker(ctx context.Context, c string) (io.ReadSeeker, er
 }
 
 func (s *server) getFile(ctx context.Context, c string) (*io.SectionReader, error) {
-	st, err := s.ipfsclient.StatCID(c)
-	if err != nil {
-		return nil, err
+	var errs []error
+	for _, p := range s.providers {
+		size, err := p.Size(ctx, c)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField("CID", c).Debugf("provider %q cannot provide the content", p)
+			errs = append(errs, err)
+			continue
+		}
+		ra := &retryReaderAt{
+			ctx: ctx,
+			readAtFunc: func(ctx context.Context, b []byte, off int64) (int, error) {
+				r, err := p.Get(ctx, c, off, len(b))
+				if err != nil {
+					return 0, err
+				}
+				defer r.Close()
+				return io.ReadFull(r, b)
+			},
+			timeout: s.config.ReadTimeout,
+			retry:   s.config.ReadRetryNum,
+		}
+		return io.NewSectionReader(ra, 0, size), nil
 	}
-	ra := &retryReaderAt{
-		ctx: ctx,
-		readAtFunc: func(ctx context.Context, p []byte, off int64) (int, error) {
-			ofst, size := int(off), len(p)
-			r, err := s.ipfsclient.Get("/ipfs/"+c, &ofst, &size)
-			if err != nil {
-				return 0, err
-			}
-			return io.ReadFull(r, p)
-		},
-		timeout: s.config.ReadTimeout,
-		retry:   s.config.ReadRetryNum,
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no provider is available for %q", c)
 	}
-	return io.NewSectionReader(ra, 0, int64(st.Size)), nil
+	return nil, errors.Join(errs...)
 }
 
 func (s *server) resolveCIDOfRootBlob(ctx context.Context, c string) (string, ocispec.Descriptor, error) {
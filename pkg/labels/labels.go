@@ -44,12 +44,22 @@ const (
 	// ComposeConfigHash stores the service configuration hash used for convergence decisions
 	ComposeConfigHash = "com.docker.compose.config-hash"
 
+	// ComposeImage stores the digest of the image the service container was created from
+	ComposeImage = "com.docker.compose.image"
+
+	// ComposeDependsOn stores the dependencies of the service, in the format of Docker Compose
+	// (e.g., "db:service_started:false,cache:service_healthy:true")
+	ComposeDependsOn = "com.docker.compose.depends_on"
+
 	// Hostname
 	Hostname = Prefix + "hostname"
 
 	// Domainname
 	Domainname = Prefix + "domainname"
 
+	// NetworkAliases is a JSON-marshalled string of []string, the additional names of the container in /etc/hosts
+	NetworkAliases = Prefix + "network-aliases"
+
 	// ExtraHosts are HostIPs to appended to /etc/hosts
 	ExtraHosts = Prefix + "extraHosts"
 
@@ -0,0 +1,357 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cwltypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	awslogsRegion             = "awslogs-region"
+	awslogsEndpoint           = "awslogs-endpoint"
+	awslogsGroup              = "awslogs-group"
+	awslogsStream             = "awslogs-stream"
+	awslogsCreateGroup        = "awslogs-create-group"
+	awslogsCreateStream       = "awslogs-create-stream"
+	awslogsForceFlushInterval = "awslogs-force-flush-interval-seconds"
+)
+
+var awslogsOpts = []string{
+	awslogsRegion,
+	awslogsEndpoint,
+	awslogsGroup,
+	awslogsStream,
+	awslogsCreateGroup,
+	awslogsCreateStream,
+	awslogsForceFlushInterval,
+	Tag,
+}
+
+// Limits of PutLogEvents, see https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html
+const (
+	awslogsPerEventBytes          = 26
+	awslogsMaximumBytesPerPut     = 1048576
+	awslogsMaximumLogEventsPerPut = 10000
+	// awslogsMaximumBytesPerEvent is the maximum size of the message of an event, larger messages are split.
+	awslogsMaximumBytesPerEvent = 262144 - awslogsPerEventBytes
+
+	awslogsDefaultForceFlushInterval = 5 * time.Second
+
+	// awslogsMaxPutAttempts is the number of attempts to put a batch whose requests are throttled or fail,
+	// in addition to the retries of the AWS SDK.
+	awslogsMaxPutAttempts = 5
+	// awslogsPutRetryInterval is the first interval between the attempts, doubled on each attempt.
+	awslogsPutRetryInterval = time.Second
+)
+
+func AWSLogsOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(awslogsOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for awslogs log driver", key)
+		}
+	}
+	if _, err := parseAWSLogsConfig(logOptMap); err != nil {
+		return err
+	}
+	return validateLogTag(logOptMap)
+}
+
+type awslogsConfig struct {
+	region             string
+	endpoint           string
+	group              string
+	stream             string
+	createGroup        bool
+	createStream       bool
+	forceFlushInterval time.Duration
+}
+
+func parseAWSLogsConfig(opts map[string]string) (*awslogsConfig, error) {
+	cfg := &awslogsConfig{
+		region:             opts[awslogsRegion],
+		endpoint:           opts[awslogsEndpoint],
+		group:              opts[awslogsGroup],
+		stream:             opts[awslogsStream],
+		createStream:       true,
+		forceFlushInterval: awslogsDefaultForceFlushInterval,
+	}
+	if cfg.group == "" {
+		return nil, fmt.Errorf("must specify a value for log opt %q", awslogsGroup)
+	}
+	var err error
+	if v := opts[awslogsCreateGroup]; v != "" {
+		if cfg.createGroup, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): %w", awslogsCreateGroup, v, err)
+		}
+	}
+	if v := opts[awslogsCreateStream]; v != "" {
+		if cfg.createStream, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): %w", awslogsCreateStream, v, err)
+		}
+	}
+	if v := opts[awslogsForceFlushInterval]; v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be a positive integer", awslogsForceFlushInterval, v)
+		}
+		cfg.forceFlushInterval = time.Duration(seconds) * time.Second
+	}
+	return cfg, nil
+}
+
+// AWSLogsLogger sends the logs to Amazon CloudWatch Logs.
+//
+// The credentials and the region (unless awslogs-region is set) are resolved with the default chain of the AWS SDK,
+// in the environment of the logging process (i.e., the containerd shim), like the environment variables,
+// the shared configuration files, and the ECS or EC2 instance roles.
+type AWSLogsLogger struct {
+	Opts          map[string]string
+	Address       string
+	client        awslogsClient
+	cfg           *awslogsConfig
+	events        chan cwltypes.InputLogEvent
+	done          chan struct{}
+	retryInterval time.Duration
+}
+
+// awslogsClient is the part of *cloudwatchlogs.Client used to send the logs.
+type awslogsClient interface {
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+func (a *AWSLogsLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (a *AWSLogsLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	cfg, err := parseAWSLogsConfig(a.Opts)
+	if err != nil {
+		return err
+	}
+	if cfg.stream == "" {
+		cfg.stream = config.ID
+		if tag, ok := a.Opts[Tag]; ok {
+			idn, err := loadIdentifier(ctx, a.Address, config)
+			if err != nil {
+				return err
+			}
+			if cfg.stream, err = executeLogTag(tag, idn); err != nil {
+				return err
+			}
+		}
+	}
+	client, err := newAWSLogsClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.createGroup {
+		_, err := client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(cfg.group)})
+		var exists *cwltypes.ResourceAlreadyExistsException
+		if err != nil && !errors.As(err, &exists) {
+			return fmt.Errorf("failed to create log group %q: %w", cfg.group, err)
+		}
+	}
+	if cfg.createStream {
+		_, err := client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+			LogGroupName:  aws.String(cfg.group),
+			LogStreamName: aws.String(cfg.stream),
+		})
+		var exists *cwltypes.ResourceAlreadyExistsException
+		if err != nil && !errors.As(err, &exists) {
+			return fmt.Errorf("failed to create log stream %q in log group %q: %w", cfg.stream, cfg.group, err)
+		}
+	}
+	a.client = client
+	a.cfg = cfg
+	a.retryInterval = awslogsPutRetryInterval
+	a.events = make(chan cwltypes.InputLogEvent, awslogsMaximumLogEventsPerPut)
+	a.done = make(chan struct{})
+	go a.send()
+	return nil
+}
+
+func newAWSLogsClient(ctx context.Context, cfg *awslogsConfig) (*cloudwatchlogs.Client, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if cfg.region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(cfg.region))
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the AWS configuration: %w", err)
+	}
+	if awsConfig.Region == "" {
+		// like Docker, fall back to the region of the EC2 instance
+		res, err := imds.NewFromConfig(awsConfig).GetRegion(ctx, &imds.GetRegionInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the AWS region, specify log opt %q: %w", awslogsRegion, err)
+		}
+		awsConfig.Region = res.Region
+	}
+	return cloudwatchlogs.NewFromConfig(awsConfig, func(o *cloudwatchlogs.Options) {
+		if cfg.endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.endpoint)
+		}
+	}), nil
+}
+
+func (a *AWSLogsLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string) {
+		defer wg.Done()
+		for log := range dataChan {
+			msg := strings.TrimSuffix(log, "\n")
+			if msg == "" {
+				// PutLogEvents rejects the whole batch with an empty message
+				continue
+			}
+			now := time.Now().UnixMilli()
+			for _, msg := range splitAWSLogsMessage(msg) {
+				a.events <- cwltypes.InputLogEvent{
+					Message:   aws.String(msg),
+					Timestamp: aws.Int64(now),
+				}
+			}
+		}
+	}
+	go fn(stdout)
+	go fn(stderr)
+	wg.Wait()
+	return nil
+}
+
+func (a *AWSLogsLogger) PostProcess() error {
+	close(a.events)
+	<-a.done
+	return nil
+}
+
+// send batches the events and puts them every forceFlushInterval, or when a batch is full.
+func (a *AWSLogsLogger) send() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.cfg.forceFlushInterval)
+	defer ticker.Stop()
+	batch := &awslogsBatch{}
+	for {
+		select {
+		case event, ok := <-a.events:
+			if !ok {
+				a.put(batch)
+				return
+			}
+			if !batch.add(event) {
+				a.put(batch)
+				batch.add(event)
+			}
+		case <-ticker.C:
+			a.put(batch)
+		}
+	}
+}
+
+func (a *AWSLogsLogger) put(batch *awslogsBatch) {
+	if len(batch.events) == 0 {
+		return
+	}
+	defer batch.reset()
+	// the events of a batch must be in chronological order
+	sort.SliceStable(batch.events, func(i, j int) bool {
+		return *batch.events[i].Timestamp < *batch.events[j].Timestamp
+	})
+	interval := a.retryInterval
+	for attempt := 1; ; attempt++ {
+		_, err := a.client.PutLogEvents(context.Background(), &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(a.cfg.group),
+			LogStreamName: aws.String(a.cfg.stream),
+			LogEvents:     batch.events,
+		})
+		if err == nil {
+			return
+		}
+		if attempt >= awslogsMaxPutAttempts || !awslogsRetryable(err) {
+			log.L.WithError(err).Errorf("failed to put %d log events to CloudWatch Logs", len(batch.events))
+			return
+		}
+		// the events are buffered in the channel meanwhile, and Process blocks when it is full
+		log.L.WithError(err).Warnf("failed to put %d log events to CloudWatch Logs, retrying in %s", len(batch.events), interval)
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+// awslogsRetryable returns true for the throttling, the server, and the connection errors,
+// which the AWS SDK has already retried a few times.
+func awslogsRetryable(err error) bool {
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// awslogsBatch is a batch of events within the limits of PutLogEvents.
+type awslogsBatch struct {
+	events []cwltypes.InputLogEvent
+	bytes  int
+}
+
+// add adds the event to the batch, and returns false if the batch is full.
+func (b *awslogsBatch) add(event cwltypes.InputLogEvent) bool {
+	size := len(*event.Message) + awslogsPerEventBytes
+	if len(b.events) >= awslogsMaximumLogEventsPerPut || b.bytes+size > awslogsMaximumBytesPerPut {
+		return false
+	}
+	b.events = append(b.events, event)
+	b.bytes += size
+	return true
+}
+
+func (b *awslogsBatch) reset() {
+	b.events = nil
+	b.bytes = 0
+}
+
+// splitAWSLogsMessage splits the message into chunks of at most awslogsMaximumBytesPerEvent bytes,
+// without splitting UTF-8 characters.
+func splitAWSLogsMessage(msg string) []string {
+	var res []string
+	for len(msg) > awslogsMaximumBytesPerEvent {
+		i := awslogsMaximumBytesPerEvent
+		for i > 0 && !utf8.RuneStart(msg[i]) {
+			i--
+		}
+		res = append(res, msg[:i])
+		msg = msg[i:]
+	}
+	return append(res, msg)
+}
@@ -0,0 +1,153 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cwltypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/smithy-go"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseAWSLogsConfig(t *testing.T) {
+	_, err := parseAWSLogsConfig(map[string]string{})
+	assert.ErrorContains(t, err, awslogsGroup)
+
+	cfg, err := parseAWSLogsConfig(map[string]string{awslogsGroup: "foo"})
+	assert.NilError(t, err)
+	assert.Equal(t, *cfg, awslogsConfig{
+		group:              "foo",
+		createStream:       true,
+		forceFlushInterval: awslogsDefaultForceFlushInterval,
+	})
+
+	cfg, err = parseAWSLogsConfig(map[string]string{
+		awslogsRegion:             "us-east-1",
+		awslogsGroup:              "foo",
+		awslogsStream:             "bar",
+		awslogsCreateGroup:        "true",
+		awslogsCreateStream:       "false",
+		awslogsForceFlushInterval: "10",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, *cfg, awslogsConfig{
+		region:             "us-east-1",
+		group:              "foo",
+		stream:             "bar",
+		createGroup:        true,
+		forceFlushInterval: 10 * time.Second,
+	})
+
+	_, err = parseAWSLogsConfig(map[string]string{awslogsGroup: "foo", awslogsCreateGroup: "yes"})
+	assert.ErrorContains(t, err, awslogsCreateGroup)
+	_, err = parseAWSLogsConfig(map[string]string{awslogsGroup: "foo", awslogsForceFlushInterval: "0"})
+	assert.ErrorContains(t, err, awslogsForceFlushInterval)
+}
+
+func TestSplitAWSLogsMessage(t *testing.T) {
+	assert.DeepEqual(t, splitAWSLogsMessage("foo"), []string{"foo"})
+
+	msg := strings.Repeat("a", awslogsMaximumBytesPerEvent-1) + "é" + "b"
+	chunks := splitAWSLogsMessage(msg)
+	assert.DeepEqual(t, chunks, []string{strings.Repeat("a", awslogsMaximumBytesPerEvent-1), "éb"})
+}
+
+func TestAWSLogsBatch(t *testing.T) {
+	event := func(size int) cwltypes.InputLogEvent {
+		return cwltypes.InputLogEvent{Message: aws.String(strings.Repeat("a", size)), Timestamp: aws.Int64(0)}
+	}
+	batch := &awslogsBatch{}
+	for range 4 {
+		assert.Assert(t, batch.add(event(awslogsMaximumBytesPerEvent)))
+	}
+	assert.Assert(t, !batch.add(event(awslogsMaximumBytesPerEvent)), "the batch must be limited by bytes")
+	batch.reset()
+
+	for range awslogsMaximumLogEventsPerPut {
+		assert.Assert(t, batch.add(event(1)))
+	}
+	assert.Assert(t, !batch.add(event(1)), "the batch must be limited by events")
+}
+
+// fakeAWSLogsClient fails the first len(errs) requests with errs.
+type fakeAWSLogsClient struct {
+	errs     []error
+	attempts int
+	events   []cwltypes.InputLogEvent
+}
+
+func (c *fakeAWSLogsClient) PutLogEvents(_ context.Context, params *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	c.attempts++
+	if c.attempts <= len(c.errs) {
+		return nil, c.errs[c.attempts-1]
+	}
+	c.events = append(c.events, params.LogEvents...)
+	return &cloudwatchlogs.PutLogEventsOutput{}, nil
+}
+
+func TestAWSLogsPutRetry(t *testing.T) {
+	newLogger := func(client awslogsClient) *AWSLogsLogger {
+		return &AWSLogsLogger{client: client, cfg: &awslogsConfig{group: "foo", stream: "bar"}, retryInterval: time.Millisecond}
+	}
+	newBatch := func() *awslogsBatch {
+		batch := &awslogsBatch{}
+		batch.add(cwltypes.InputLogEvent{Message: aws.String("foo"), Timestamp: aws.Int64(0)})
+		return batch
+	}
+
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	client := &fakeAWSLogsClient{errs: []error{throttled, throttled}}
+	newLogger(client).put(newBatch())
+	assert.Equal(t, client.attempts, 3)
+	assert.Equal(t, len(client.events), 1)
+
+	client = &fakeAWSLogsClient{errs: []error{&smithy.GenericAPIError{Code: "InvalidParameterException"}}}
+	newLogger(client).put(newBatch())
+	assert.Equal(t, client.attempts, 1, "the batch must not be retried for the errors that are not retryable")
+
+	client = &fakeAWSLogsClient{errs: make([]error, awslogsMaxPutAttempts+1)}
+	for i := range client.errs {
+		client.errs[i] = throttled
+	}
+	newLogger(client).put(newBatch())
+	assert.Equal(t, client.attempts, awslogsMaxPutAttempts)
+	assert.Equal(t, len(client.events), 0)
+}
+
+func TestAWSLogsProcessSkipsEmptyLines(t *testing.T) {
+	a := &AWSLogsLogger{events: make(chan cwltypes.InputLogEvent, 10)}
+	stdout := make(chan string, 3)
+	stderr := make(chan string)
+	stdout <- "foo\n"
+	stdout <- "\n"
+	stdout <- "bar\n"
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, a.Process(stdout, stderr))
+	close(a.events)
+	var messages []string
+	for event := range a.events {
+		messages = append(messages, *event.Message)
+	}
+	assert.DeepEqual(t, messages, []string{"foo", "bar"})
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// fluentdQueueSize is the number of records kept in memory while waiting to be posted.
+	fluentdQueueSize = 1024
+	// fluentdDiskBufferMaxSize is the maximum size of the disk buffer, after which records are dropped.
+	fluentdDiskBufferMaxSize = 64 * 1024 * 1024
+	// fluentdDiskBufferFlushInterval is the interval between attempts to post the disk buffer.
+	fluentdDiskBufferFlushInterval = time.Second
+	// fluentdFlushTimeout is how long to wait for the pending records to be posted on exit,
+	// before they are written to the disk buffer.
+	fluentdFlushTimeout = 10 * time.Second
+	// fluentdPostMaxRetries is the maximum number of attempts to post a record, after which it is kept in the disk buffer.
+	fluentdPostMaxRetries = 3
+	// fluentdWriteTimeout bounds the time to post a record to a collector that stopped reading.
+	fluentdWriteTimeout = 5 * time.Second
+)
+
+var errFluentdDiskBufferFull = errors.New("fluentd disk buffer is full")
+
+// fluentdRecord is a log record to be posted to fluentd.
+type fluentdRecord struct {
+	Time time.Time         `json:"time"`
+	Data map[string]string `json:"data"`
+}
+
+func fluentdDiskBufferPath(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, "fluentd-buffer.log")
+}
+
+// fluentdDiskBuffer stores the records that could not be posted to fluentd,
+// one JSON record per line, so that they can be posted once the collector is back.
+// The buffer survives restarts of the container.
+type fluentdDiskBuffer struct {
+	path string
+	mu   sync.Mutex
+	size int64
+}
+
+func newFluentdDiskBuffer(path string) (*fluentdDiskBuffer, error) {
+	b := &fluentdDiskBuffer{path: path}
+	st, err := os.Stat(path)
+	if err == nil {
+		b.size = st.Size()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *fluentdDiskBuffer) empty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size == 0
+}
+
+func (b *fluentdDiskBuffer) append(records ...*fluentdRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.size+int64(buf.Len()) > fluentdDiskBufferMaxSize {
+		return errFluentdDiskBufferFull
+	}
+	f, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := f.Write(buf.Bytes())
+	b.size += int64(n)
+	return err
+}
+
+// take removes all the records from the buffer and returns them.
+func (b *fluentdDiskBuffer) take() ([]*fluentdRecord, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.size == 0 {
+		return nil, nil
+	}
+	f, err := os.Open(b.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []*fluentdRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, fluentdDiskBufferMaxSize)
+	for scanner.Scan() {
+		var rec fluentdRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// skip a record truncated by a crash
+			continue
+		}
+		records = append(records, &rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(b.path); err != nil {
+		return nil, err
+	}
+	b.size = 0
+	return records, nil
+}
+
+// restore puts back records returned by take in front of the records appended since then.
+func (b *fluentdDiskBuffer) restore(records []*fluentdRecord) error {
+	newer, err := b.take()
+	if err != nil {
+		return err
+	}
+	return b.append(append(records, newer...)...)
+}
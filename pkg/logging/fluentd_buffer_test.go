@@ -0,0 +1,112 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFluentdDiskBuffer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fluentd-buffer.log")
+	b, err := newFluentdDiskBuffer(path)
+	assert.NilError(t, err)
+	assert.Assert(t, b.empty())
+
+	record := func(s string) *fluentdRecord {
+		return &fluentdRecord{Time: time.Unix(1700000000, 0).UTC(), Data: map[string]string{"log": s}}
+	}
+	logs := func(records []*fluentdRecord) []string {
+		var res []string
+		for _, rec := range records {
+			res = append(res, rec.Data["log"])
+		}
+		return res
+	}
+
+	assert.NilError(t, b.append(record("foo"), record("bar")))
+	assert.Assert(t, !b.empty())
+
+	// the buffer is kept across restarts
+	b, err = newFluentdDiskBuffer(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !b.empty())
+
+	records, err := b.take()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, logs(records), []string{"foo", "bar"})
+	assert.Equal(t, records[0].Time, time.Unix(1700000000, 0).UTC())
+	assert.Assert(t, b.empty())
+
+	// records that failed to be posted again go before the newer ones
+	assert.NilError(t, b.append(record("baz")))
+	assert.NilError(t, b.restore(records[1:]))
+	records, err = b.take()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, logs(records), []string{"bar", "baz"})
+
+	err = b.append(record(strings.Repeat("x", fluentdDiskBufferMaxSize)))
+	assert.ErrorIs(t, err, errFluentdDiskBufferFull)
+	assert.Assert(t, b.empty())
+}
+
+func TestFluentdKeepPending(t *testing.T) {
+	b, err := newFluentdDiskBuffer(filepath.Join(t.TempDir(), "fluentd-buffer.log"))
+	assert.NilError(t, err)
+	f := &FluentdLogger{
+		records:    make(chan *fluentdRecord, 2),
+		diskBuffer: b,
+		stop:       make(chan struct{}),
+	}
+	record := func(s string) *fluentdRecord {
+		return &fluentdRecord{Time: time.Now(), Data: map[string]string{"log": s}}
+	}
+
+	f.enqueue(record("bar"))
+	f.enqueue(record("baz"))
+	// the queue is full
+	f.enqueue(record("qux"))
+	// the disk buffer has older records than the queue is allowed to have
+	f.enqueue(record("quux"))
+	assert.Equal(t, len(f.records), 2)
+
+	// the records in flight and the queued records go before the disk buffer
+	f.inflight = []*fluentdRecord{record("foo")}
+	f.keepPendingLocked()
+	assert.Equal(t, len(f.records), 0)
+	assert.Assert(t, f.inflight == nil)
+
+	records, err := b.take()
+	assert.NilError(t, err)
+	var logs []string
+	for _, rec := range records {
+		logs = append(logs, rec.Data["log"])
+	}
+	assert.DeepEqual(t, logs, []string{"foo", "bar", "baz", "qux", "quux"})
+
+	// the stopped sender does not post (fluentClient is nil), and keeps the received record in the disk buffer
+	close(f.stop)
+	assert.Assert(t, !f.postRecords([]*fluentdRecord{record("foo")}))
+	assert.Assert(t, !f.flushDiskBuffer())
+	records, err = b.take()
+	assert.NilError(t, err)
+	assert.Equal(t, len(records), 1)
+}
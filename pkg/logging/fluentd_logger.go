@@ -39,6 +39,22 @@ type FluentdLogger struct {
 	Opts         map[string]string
 	fluentClient *fluent.Fluent
 	config       *logging.Config
+
+	// records are posted by a single goroutine, so that a collector that is down
+	// does not block the container output. The records that cannot be queued or posted
+	// are written to diskBuffer, and posted again later.
+	// The queued records are always older than the records in diskBuffer.
+	records    chan *fluentdRecord
+	diskBuffer *fluentdDiskBuffer
+	senderDone chan struct{}
+	// mu guards queuing the records, inflight, and stop
+	mu sync.Mutex
+	// inflight are the records being posted by the sender, which are older than the queued records
+	inflight []*fluentdRecord
+	// stop is closed when flushing timed out, after the pending records are written to diskBuffer.
+	stop chan struct{}
+	// warnOnce avoids logging a warning for every record while the collector is down.
+	warnOnce sync.Once
 }
 
 const (
@@ -100,7 +116,7 @@ func (f *FluentdLogger) Init(dataStore, ns, id string) error {
 	return nil
 }
 
-func (f *FluentdLogger) PreProcess(_ context.Context, _ string, config *logging.Config) error {
+func (f *FluentdLogger) PreProcess(_ context.Context, dataStore string, config *logging.Config) error {
 	if runtime.GOOS == "windows" {
 		// TODO: support fluentd on windows
 		return fmt.Errorf("logging to fluentd is not supported on windows")
@@ -109,27 +125,48 @@ func (f *FluentdLogger) PreProcess(_ context.Context, _ string, config *logging.
 	if err != nil {
 		return err
 	}
+	// The records are posted by the sender, and kept in the disk buffer when they cannot be posted,
+	// so the client posts them synchronously with a bounded number of attempts, instead of queuing them in memory.
+	// fluentd-async only allows starting the container while the collector is down.
+	async := fluentConfig.Async
+	fluentConfig.Async = false
+	fluentConfig.MaxRetry = min(fluentConfig.MaxRetry, fluentdPostMaxRetries)
+	fluentConfig.WriteTimeout = fluentdWriteTimeout
 	fluentClient, err := fluent.New(fluentConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create fluent client: %w", err)
+		if !async || fluentClient == nil {
+			return fmt.Errorf("failed to create fluent client: %w", err)
+		}
+		log.L.WithError(err).Warn("failed to connect to fluentd, the logs are posted once it is available")
 	}
 	f.fluentClient = fluentClient
 	f.config = config
+	f.diskBuffer, err = newFluentdDiskBuffer(fluentdDiskBufferPath(dataStore, config.Namespace, config.ID))
+	if err != nil {
+		return err
+	}
+	f.records = make(chan *fluentdRecord, fluentdQueueSize)
+	f.senderDone = make(chan struct{})
+	f.stop = make(chan struct{})
+	go f.send()
 	return nil
 }
+
 func (f *FluentdLogger) Process(stdout <-chan string, stderr <-chan string) error {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	fun := func(wg *sync.WaitGroup, dataChan <-chan string, id, namespace, source string) {
 		defer wg.Done()
-		metaData := map[string]string{
-			"container_id": id,
-			"namespace":    namespace,
-			"source":       source,
-		}
 		for log := range dataChan {
-			metaData["log"] = log
-			f.fluentClient.PostWithTime(f.Opts[Tag], time.Now(), metaData)
+			f.enqueue(&fluentdRecord{
+				Time: time.Now(),
+				Data: map[string]string{
+					"container_id": id,
+					"namespace":    namespace,
+					"source":       source,
+					"log":          log,
+				},
+			})
 		}
 	}
 	go fun(&wg, stdout, f.config.ID, f.config.Namespace, "stdout")
@@ -140,10 +177,163 @@ func (f *FluentdLogger) Process(stdout <-chan string, stderr <-chan string) erro
 }
 
 func (f *FluentdLogger) PostProcess() error {
-	defer f.fluentClient.Close()
+	close(f.records)
+	select {
+	case <-f.senderDone:
+		return f.fluentClient.Close()
+	case <-time.After(fluentdFlushTimeout):
+	}
+	log.L.Warnf("timed out posting logs to fluentd, keeping the remaining logs in %s", f.diskBuffer.path)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.stop)
+	f.keepPendingLocked()
+	// The sender may be blocked posting a record, so it is not waited for, and the client is not closed.
+	// The record being posted may be posted again, as it is kept in the disk buffer too.
 	return nil
 }
 
+// enqueue queues rec for the sender, or writes it to the disk buffer when the queue is full,
+// or when the disk buffer has older records.
+func (f *FluentdLogger) enqueue(rec *fluentdRecord) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.diskBuffer.empty() {
+		select {
+		case f.records <- rec:
+			return
+		default:
+		}
+	}
+	f.bufferRecords(rec)
+}
+
+// send posts the queued records, and periodically retries posting the disk buffer.
+func (f *FluentdLogger) send() {
+	defer close(f.senderDone)
+	ticker := time.NewTicker(fluentdDiskBufferFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case rec, ok := <-f.records:
+			if !ok {
+				f.flushDiskBuffer()
+				return
+			}
+			if !f.postRecords([]*fluentdRecord{rec}) {
+				return
+			}
+		case <-ticker.C:
+			// the queued records are older than the records in the disk buffer, so they are posted first
+			if len(f.records) == 0 && !f.flushDiskBuffer() {
+				return
+			}
+		}
+	}
+}
+
+func (f *FluentdLogger) stopped() bool {
+	select {
+	case <-f.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// flushDiskBuffer posts the records in the disk buffer, and returns false when the logger is stopped.
+func (f *FluentdLogger) flushDiskBuffer() bool {
+	f.mu.Lock()
+	if f.stopped() {
+		f.mu.Unlock()
+		return false
+	}
+	records, err := f.diskBuffer.take()
+	f.mu.Unlock()
+	if err != nil {
+		log.L.WithError(err).Errorf("failed to read the fluentd disk buffer %s", f.diskBuffer.path)
+		return true
+	}
+	return f.postRecords(records)
+}
+
+// postRecords posts the records in order. When a record cannot be posted, the remaining records
+// and the queued ones are written in front of the disk buffer.
+// postRecords returns false when the logger is stopped.
+func (f *FluentdLogger) postRecords(records []*fluentdRecord) bool {
+	f.mu.Lock()
+	if f.stopped() {
+		// the record received after PostProcess wrote the pending records to the disk buffer
+		f.inflight = records
+		f.keepPendingLocked()
+		f.mu.Unlock()
+		return false
+	}
+	f.inflight = records
+	f.mu.Unlock()
+	for len(records) > 0 {
+		err := f.post(records[0])
+		f.mu.Lock()
+		if f.stopped() {
+			// the records in flight were written to the disk buffer by PostProcess
+			f.mu.Unlock()
+			return false
+		}
+		if err != nil {
+			f.keepPendingLocked()
+			f.mu.Unlock()
+			return true
+		}
+		records = records[1:]
+		f.inflight = records
+		f.mu.Unlock()
+	}
+	return true
+}
+
+// keepPendingLocked writes the records in flight and the queued records in front of the disk buffer,
+// as they are older than the records in the disk buffer. f.mu must be held.
+func (f *FluentdLogger) keepPendingLocked() {
+	records := f.inflight
+	f.inflight = nil
+drain:
+	for {
+		select {
+		case rec, ok := <-f.records:
+			if !ok {
+				break drain
+			}
+			records = append(records, rec)
+		default:
+			break drain
+		}
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err := f.diskBuffer.restore(records); err != nil {
+		log.L.WithError(err).Errorf("dropping %d fluentd log records", len(records))
+	}
+}
+
+func (f *FluentdLogger) post(rec *fluentdRecord) error {
+	err := f.fluentClient.PostWithTime(f.Opts[Tag], rec.Time, rec.Data)
+	if err != nil {
+		f.warnOnce.Do(func() {
+			log.L.WithError(err).Warnf("failed to post logs to fluentd, buffering them in %s", f.diskBuffer.path)
+		})
+	}
+	return err
+}
+
+func (f *FluentdLogger) bufferRecords(records ...*fluentdRecord) {
+	if err := f.diskBuffer.append(records...); err != nil {
+		log.L.WithError(err).Errorf("dropping %d fluentd log records", len(records))
+	}
+}
+
 func parseAddress(address string) (*fluentdLocation, error) {
 	if address == "" {
 		return &fluentdLocation{
@@ -228,7 +418,7 @@ func parseFluentdConfig(config map[string]string) (fluent.Config, error) {
 			return result, fmt.Errorf("error occurs %w,invalid buffer limit (%s)", err, config[fluentdBufferLimit])
 		}
 	}
-	retryWait := int(defaultRetryWait)
+	retryWait := int(defaultRetryWait.Milliseconds())
 	if config[fluentdRetryWait] != "" {
 		temp, err := time.ParseDuration(config[fluentdRetryWait])
 		if err != nil {
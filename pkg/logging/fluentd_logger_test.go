@@ -94,7 +94,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
@@ -110,7 +110,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
@@ -127,7 +127,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  true,
 				AsyncReconnectInterval: 0,
@@ -143,7 +143,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 100,
@@ -159,7 +159,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            1000,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
@@ -191,7 +191,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               100,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
@@ -207,7 +207,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
@@ -223,7 +223,7 @@ func TestParseFluentdConfig(t *testing.T) {
 				FluentNetwork:          defaultProtocol,
 				FluentSocketPath:       "",
 				BufferLimit:            defaultBufferLimit,
-				RetryWait:              int(defaultRetryWait),
+				RetryWait:              int(defaultRetryWait.Milliseconds()),
 				MaxRetry:               defaultMaxRetries,
 				Async:                  false,
 				AsyncReconnectInterval: 0,
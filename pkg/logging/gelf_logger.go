@@ -0,0 +1,410 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	gelfAddress           = "gelf-address"
+	gelfCompressionType   = "gelf-compression-type"
+	gelfCompressionLevel  = "gelf-compression-level"
+	gelfTCPMaxReconnect   = "gelf-tcp-max-reconnect"
+	gelfTCPReconnectDelay = "gelf-tcp-reconnect-delay"
+)
+
+var gelfOpts = []string{
+	gelfAddress,
+	gelfCompressionType,
+	gelfCompressionLevel,
+	gelfTCPMaxReconnect,
+	gelfTCPReconnectDelay,
+	Tag,
+	Labels,
+	Env,
+}
+
+const (
+	gelfCompressionGzip = "gzip"
+	gelfCompressionZlib = "zlib"
+	gelfCompressionNone = "none"
+
+	// gelfChunkSize is the maximum size of an UDP datagram, including the chunk header.
+	gelfChunkSize       = 1420
+	gelfChunkHeaderSize = 12
+	gelfMaxChunks       = 128
+
+	gelfDefaultTCPMaxReconnect   = 3
+	gelfDefaultTCPReconnectDelay = time.Second
+)
+
+// gelfChunkMagic is the magic of the chunked GELF messages.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+type gelfConfig struct {
+	protocol          string
+	address           string
+	compressionType   string
+	compressionLevel  int
+	tcpMaxReconnect   int
+	tcpReconnectDelay time.Duration
+}
+
+func GelfLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(gelfOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for gelf log driver", key)
+		}
+	}
+	if _, err := parseGelfConfig(logOptMap); err != nil {
+		return err
+	}
+	return validateLogTag(logOptMap)
+}
+
+func parseGelfConfig(opts map[string]string) (*gelfConfig, error) {
+	if opts[gelfAddress] == "" {
+		return nil, fmt.Errorf("must specify a value for log opt %q", gelfAddress)
+	}
+	addr, err := url.Parse(opts[gelfAddress])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for log opt %q (%q): %w", gelfAddress, opts[gelfAddress], err)
+	}
+	if addr.Scheme != "udp" && addr.Scheme != "tcp" {
+		return nil, fmt.Errorf("invalid value for log opt %q (%q): the scheme must be udp or tcp", gelfAddress, opts[gelfAddress])
+	}
+	if _, _, err := net.SplitHostPort(addr.Host); err != nil {
+		return nil, fmt.Errorf("invalid value for log opt %q (%q): %w", gelfAddress, opts[gelfAddress], err)
+	}
+	cfg := &gelfConfig{
+		protocol:          addr.Scheme,
+		address:           addr.Host,
+		compressionType:   gelfCompressionGzip,
+		compressionLevel:  flate.DefaultCompression,
+		tcpMaxReconnect:   gelfDefaultTCPMaxReconnect,
+		tcpReconnectDelay: gelfDefaultTCPReconnectDelay,
+	}
+
+	if v, ok := opts[gelfCompressionType]; ok {
+		if cfg.protocol == "tcp" {
+			return nil, fmt.Errorf("log opt %q is not supported with tcp", gelfCompressionType)
+		}
+		switch v {
+		case gelfCompressionGzip, gelfCompressionZlib, gelfCompressionNone:
+			cfg.compressionType = v
+		default:
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be gzip, zlib, or none", gelfCompressionType, v)
+		}
+	}
+	if v, ok := opts[gelfCompressionLevel]; ok {
+		if cfg.protocol == "tcp" {
+			return nil, fmt.Errorf("log opt %q is not supported with tcp", gelfCompressionLevel)
+		}
+		level, err := strconv.Atoi(v)
+		if err != nil || level < flate.DefaultCompression || level > flate.BestCompression {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be an integer between -1 and 9", gelfCompressionLevel, v)
+		}
+		cfg.compressionLevel = level
+	}
+	if v, ok := opts[gelfTCPMaxReconnect]; ok {
+		if cfg.protocol == "udp" {
+			return nil, fmt.Errorf("log opt %q is not supported with udp", gelfTCPMaxReconnect)
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be a non-negative integer", gelfTCPMaxReconnect, v)
+		}
+		cfg.tcpMaxReconnect = n
+	}
+	if v, ok := opts[gelfTCPReconnectDelay]; ok {
+		if cfg.protocol == "udp" {
+			return nil, fmt.Errorf("log opt %q is not supported with udp", gelfTCPReconnectDelay)
+		}
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be a non-negative integer", gelfTCPReconnectDelay, v)
+		}
+		cfg.tcpReconnectDelay = time.Duration(seconds) * time.Second
+	}
+	return cfg, nil
+}
+
+// gelfMessage is a GELF 1.1 message, see https://go2docs.graylog.org/current/getting_in_log_data/gelf.html
+type gelfMessage struct {
+	Version      string  `json:"version"`
+	Host         string  `json:"host"`
+	ShortMessage string  `json:"short_message"`
+	Timestamp    float64 `json:"timestamp"`
+	Level        int     `json:"level"`
+	// Extra are the additional fields, prefixed with "_".
+	Extra map[string]string `json:"-"`
+}
+
+func (m *gelfMessage) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(m.Extra)+5)
+	for k, v := range m.Extra {
+		fields["_"+k] = v
+	}
+	fields["version"] = m.Version
+	fields["host"] = m.Host
+	fields["short_message"] = m.ShortMessage
+	fields["timestamp"] = m.Timestamp
+	fields["level"] = m.Level
+	return json.Marshal(fields)
+}
+
+// GELF levels are the syslog severities
+const (
+	gelfLevelError = 3
+	gelfLevelInfo  = 6
+)
+
+type GelfLogger struct {
+	Opts    map[string]string
+	Address string
+	cfg     *gelfConfig
+	host    string
+	extra   map[string]string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (g *GelfLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (g *GelfLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	cfg, err := parseGelfConfig(g.Opts)
+	if err != nil {
+		return err
+	}
+	info, err := loadContainerInfo(ctx, g.Address, config)
+	if err != nil {
+		return err
+	}
+	var spec specs.Spec
+	if info.Spec != nil {
+		if err := json.Unmarshal(info.Spec.GetValue(), &spec); err != nil {
+			return err
+		}
+	}
+	var env []string
+	var command string
+	if spec.Process != nil {
+		env = spec.Process.Env
+		command = strings.Join(spec.Process.Args, " ")
+	}
+
+	idn := newIdentifier(config, info)
+	tag := idn.ID
+	if cfgTag, ok := g.Opts[Tag]; ok {
+		if tag, err = executeLogTag(cfgTag, idn); err != nil {
+			return err
+		}
+	}
+
+	// like Docker, the labels and env are added first, so that they cannot override the container fields
+	extra := extraAttributes(g.Opts, info.Labels, env)
+	delete(extra, "id") // "_id" is reserved by GELF
+	for k, v := range map[string]string{
+		"container_id":   config.ID,
+		"container_name": idn.Name,
+		"image_name":     idn.ImageName,
+		"command":        command,
+		"tag":            tag,
+		"created":        info.CreatedAt.Format(time.RFC3339Nano),
+	} {
+		extra[k] = v
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial(cfg.protocol, cfg.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the gelf server %s://%s: %w", cfg.protocol, cfg.address, err)
+	}
+	g.cfg = cfg
+	g.host = host
+	g.extra = extra
+	g.conn = conn
+	return nil
+}
+
+func (g *GelfLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string, level int) {
+		defer wg.Done()
+		for line := range dataChan {
+			msg := &gelfMessage{
+				Version:      "1.1",
+				Host:         g.host,
+				ShortMessage: strings.TrimSuffix(line, "\n"),
+				Timestamp:    float64(time.Now().UnixMilli()) / 1000,
+				Level:        level,
+				Extra:        g.extra,
+			}
+			if err := g.write(msg); err != nil {
+				log.L.WithError(err).Error("failed to send the log message to the gelf server")
+			}
+		}
+	}
+	go fn(stdout, gelfLevelInfo)
+	go fn(stderr, gelfLevelError)
+	wg.Wait()
+	return nil
+}
+
+func (g *GelfLogger) PostProcess() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.conn != nil {
+		return g.conn.Close()
+	}
+	return nil
+}
+
+func (g *GelfLogger) write(msg *gelfMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cfg.protocol == "udp" {
+		return g.writeUDP(b)
+	}
+	return g.writeTCP(b)
+}
+
+func (g *GelfLogger) writeUDP(b []byte) error {
+	b, err := gelfCompress(b, g.cfg.compressionType, g.cfg.compressionLevel)
+	if err != nil {
+		return err
+	}
+	chunks, err := gelfChunks(b)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, err := g.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTCP writes the null-terminated message, and reconnects on failure.
+func (g *GelfLogger) writeTCP(b []byte) error {
+	b = append(b, 0)
+	var err error
+	for i := 0; ; i++ {
+		if g.conn != nil {
+			if _, err = g.conn.Write(b); err == nil {
+				return nil
+			}
+			g.conn.Close()
+			g.conn = nil
+		}
+		if i >= g.cfg.tcpMaxReconnect {
+			return err
+		}
+		time.Sleep(g.cfg.tcpReconnectDelay)
+		var conn net.Conn
+		if conn, err = net.Dial("tcp", g.cfg.address); err == nil {
+			g.conn = conn
+		}
+	}
+}
+
+func gelfCompress(b []byte, compressionType string, level int) ([]byte, error) {
+	var w io.WriteCloser
+	var buf bytes.Buffer
+	var err error
+	switch compressionType {
+	case gelfCompressionGzip:
+		w, err = gzip.NewWriterLevel(&buf, level)
+	case gelfCompressionZlib:
+		w, err = zlib.NewWriterLevel(&buf, level)
+	default:
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gelfChunks splits the message into UDP datagrams, using the GELF chunking when it is too large.
+func gelfChunks(b []byte) ([][]byte, error) {
+	if len(b) <= gelfChunkSize {
+		return [][]byte{b}, nil
+	}
+	const payloadSize = gelfChunkSize - gelfChunkHeaderSize
+	count := (len(b) + payloadSize - 1) / payloadSize
+	if count > gelfMaxChunks {
+		return nil, errors.New("the gelf message is too large")
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		payload := b[i*payloadSize : min((i+1)*payloadSize, len(b))]
+		chunk := make([]byte, 0, gelfChunkHeaderSize+len(payload))
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, payload...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
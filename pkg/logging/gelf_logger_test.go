@@ -0,0 +1,162 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseGelfConfig(t *testing.T) {
+	cfg, err := parseGelfConfig(map[string]string{gelfAddress: "udp://127.0.0.1:12201"})
+	assert.NilError(t, err)
+	assert.Equal(t, *cfg, gelfConfig{
+		protocol:          "udp",
+		address:           "127.0.0.1:12201",
+		compressionType:   gelfCompressionGzip,
+		compressionLevel:  -1,
+		tcpMaxReconnect:   gelfDefaultTCPMaxReconnect,
+		tcpReconnectDelay: gelfDefaultTCPReconnectDelay,
+	})
+
+	cfg, err = parseGelfConfig(map[string]string{
+		gelfAddress:           "tcp://graylog:12201",
+		gelfTCPMaxReconnect:   "5",
+		gelfTCPReconnectDelay: "2",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.tcpMaxReconnect, 5)
+	assert.Equal(t, cfg.tcpReconnectDelay, 2*time.Second)
+
+	for _, opts := range []map[string]string{
+		{},
+		{gelfAddress: "http://127.0.0.1:12201"},
+		{gelfAddress: "udp://127.0.0.1"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionType: "lz4"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionLevel: "10"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfTCPMaxReconnect: "1"},
+		{gelfAddress: "tcp://127.0.0.1:12201", gelfCompressionType: "gzip"},
+	} {
+		_, err := parseGelfConfig(opts)
+		assert.Assert(t, err != nil, "expected an error for %v", opts)
+	}
+}
+
+func TestGelfMessageMarshal(t *testing.T) {
+	msg := &gelfMessage{
+		Version:      "1.1",
+		Host:         "host",
+		ShortMessage: "foo",
+		Timestamp:    1700000000.123,
+		Level:        gelfLevelInfo,
+		Extra:        map[string]string{"container_name": "bar"},
+	}
+	b, err := json.Marshal(msg)
+	assert.NilError(t, err)
+	var fields map[string]any
+	assert.NilError(t, json.Unmarshal(b, &fields))
+	assert.DeepEqual(t, fields, map[string]any{
+		"version":         "1.1",
+		"host":            "host",
+		"short_message":   "foo",
+		"timestamp":       1700000000.123,
+		"level":           float64(gelfLevelInfo),
+		"_container_name": "bar",
+	})
+}
+
+func TestGelfCompress(t *testing.T) {
+	msg := []byte(`{"short_message":"foo"}`)
+
+	b, err := gelfCompress(msg, gelfCompressionGzip, -1)
+	assert.NilError(t, err)
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	assert.NilError(t, err)
+	decompressed, err := io.ReadAll(r)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decompressed, msg)
+
+	b, err = gelfCompress(msg, gelfCompressionZlib, 9)
+	assert.NilError(t, err)
+	zr, err := zlib.NewReader(bytes.NewReader(b))
+	assert.NilError(t, err)
+	decompressed, err = io.ReadAll(zr)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decompressed, msg)
+
+	b, err = gelfCompress(msg, gelfCompressionNone, -1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, b, msg)
+}
+
+func TestGelfChunks(t *testing.T) {
+	chunks, err := gelfChunks([]byte("foo"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, chunks, [][]byte{[]byte("foo")})
+
+	msg := bytes.Repeat([]byte("a"), 3000)
+	chunks, err = gelfChunks(msg)
+	assert.NilError(t, err)
+	assert.Equal(t, len(chunks), 3)
+	var payload []byte
+	for i, chunk := range chunks {
+		assert.Assert(t, len(chunk) <= gelfChunkSize)
+		assert.DeepEqual(t, chunk[:2], gelfChunkMagic)
+		assert.DeepEqual(t, chunk[2:10], chunks[0][2:10])
+		assert.Equal(t, chunk[10], byte(i))
+		assert.Equal(t, chunk[11], byte(3))
+		payload = append(payload, chunk[gelfChunkHeaderSize:]...)
+	}
+	assert.DeepEqual(t, payload, msg)
+
+	_, err = gelfChunks(make([]byte, gelfMaxChunks*gelfChunkSize))
+	assert.ErrorContains(t, err, "too large")
+}
+
+func TestGelfWriteTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer l.Close()
+	received := make(chan []byte)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b := make([]byte, 1024)
+		n, _ := conn.Read(b)
+		received <- b[:n]
+	}()
+
+	g := &GelfLogger{cfg: &gelfConfig{protocol: "tcp", address: l.Addr().String(), tcpMaxReconnect: 1}}
+	assert.NilError(t, g.write(&gelfMessage{Version: "1.1", ShortMessage: "foo"}))
+	b := <-received
+	assert.Equal(t, b[len(b)-1], byte(0), "the message must be null-terminated")
+	var fields map[string]any
+	assert.NilError(t, json.Unmarshal(b[:len(b)-1], &fields))
+	assert.Equal(t, fields["short_message"], "foo")
+	assert.NilError(t, g.PostProcess())
+}
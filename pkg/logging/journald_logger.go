@@ -17,20 +17,20 @@
 package logging
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
-	"text/template"
+	"sync/atomic"
 	"time"
 
 	"github.com/coreos/go-systemd/v22/journal"
-	"github.com/docker/cli/templates"
 	timetypes "github.com/docker/docker/api/types/time"
 
 	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
@@ -53,7 +53,7 @@ func JournalLogOptsValidate(logOptMap map[string]string) error {
 			log.L.Warnf("log-opt %s is ignored for journald log driver", key)
 		}
 	}
-	return nil
+	return validateLogTag(logOptMap)
 }
 
 type JournaldLogger struct {
@@ -62,12 +62,6 @@ type JournaldLogger struct {
 	Address string
 }
 
-type identifier struct {
-	ID        string
-	FullID    string
-	Namespace string
-}
-
 func (journaldLogger *JournaldLogger) Init(dataStore, ns, id string) error {
 	return nil
 }
@@ -76,32 +70,6 @@ func (journaldLogger *JournaldLogger) PreProcess(ctx context.Context, dataStore
 	if !journal.Enabled() {
 		return errors.New("the local systemd journal is not available for logging")
 	}
-	shortID := config.ID[:12]
-	var syslogIdentifier string
-	if _, ok := journaldLogger.Opts[Tag]; !ok {
-		syslogIdentifier = shortID
-	} else {
-		var tmpl *template.Template
-		var err error
-		tmpl, err = templates.Parse(journaldLogger.Opts[Tag])
-		if err != nil {
-			return err
-		}
-
-		if tmpl != nil {
-			idn := identifier{
-				ID:        shortID,
-				FullID:    config.ID,
-				Namespace: config.Namespace,
-			}
-			var b bytes.Buffer
-			if err := tmpl.Execute(&b, idn); err != nil {
-				return err
-			}
-			syslogIdentifier = b.String()
-		}
-	}
-
 	client, ctx, cancel, err := clientutil.NewClient(ctx, config.Namespace, journaldLogger.Address)
 	if err != nil {
 		return err
@@ -124,6 +92,21 @@ func (journaldLogger *JournaldLogger) PreProcess(ctx context.Context, dataStore
 		return err
 	}
 
+	shortID := containerID[:12]
+	syslogIdentifier := shortID
+	if tag, ok := journaldLogger.Opts[Tag]; ok {
+		syslogIdentifier, err = executeLogTag(tag, identifier{
+			ID:        shortID,
+			FullID:    containerID,
+			Namespace: config.Namespace,
+			Name:      containerutil.GetContainerName(containerLabels),
+			ImageName: containerInfo.Image,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// construct log metadata for the container
 	vars := map[string]string{
 		"SYSLOG_IDENTIFIER": syslogIdentifier,
@@ -133,17 +116,56 @@ func (journaldLogger *JournaldLogger) PreProcess(ctx context.Context, dataStore
 		"CONTAINER_NAME":    containerutil.GetContainerName(containerLabels),
 		"IMAGE_NAME":        containerInfo.Image,
 	}
+	// like Docker, add the requested labels and environment variables as extra fields
+	var env []string
+	if journaldLogger.Opts[Env] != "" {
+		spec, err := container.Spec(ctx)
+		if err != nil {
+			return err
+		}
+		if spec.Process != nil {
+			env = spec.Process.Env
+		}
+	}
+	extra := extraAttributes(journaldLogger.Opts, containerLabels, env)
+	for k, v := range extra {
+		if key := journalFieldName(k); key != "" {
+			if _, ok := vars[key]; !ok {
+				vars[key] = v
+			}
+		}
+	}
 	journaldLogger.vars = vars
 	return nil
 }
 
+// journalFieldName converts a label or environment variable name into a journal field name,
+// which may only contain uppercase letters, digits, and underscores, and must not start with an underscore.
+func journalFieldName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z':
+			r -= 'a' - 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		default:
+			r = '_'
+		}
+		if b.Len() == 0 && r == '_' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func (journaldLogger *JournaldLogger) Process(stdout <-chan string, stderr <-chan string) error {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	f := func(wg *sync.WaitGroup, dataChan <-chan string, pri journal.Priority, vars map[string]string) {
 		defer wg.Done()
 		for log := range dataChan {
-			journal.Send(log, pri, vars)
+			journal.Send(strings.TrimSuffix(log, "\n"), pri, vars)
 		}
 	}
 	// forward both stdout and stderr to the journal
@@ -158,34 +180,66 @@ func (journaldLogger *JournaldLogger) PostProcess() error {
 	return nil
 }
 
-// Exec's `journalctl` with the provided arguments and hooks it up
-// to the given stdout/stderr streams.
-func FetchLogs(stdout, stderr io.Writer, journalctlArgs []string, stopChannel chan os.Signal) error {
+// journalEntry is an entry of `journalctl --output=json`.
+type journalEntry struct {
+	// MESSAGE is a string, or an array of bytes when the message is not valid UTF-8.
+	Message           json.RawMessage `json:"MESSAGE"`
+	Priority          string          `json:"PRIORITY"`
+	RealtimeTimestamp string          `json:"__REALTIME_TIMESTAMP"`
+}
+
+// Exec's `journalctl` with the provided arguments, decodes its JSON output and
+// writes the messages to the given stdout/stderr streams depending on their priority.
+func FetchLogs(stdout, stderr io.Writer, journalctlArgs []string, timestamps bool, stopChannel chan os.Signal) error {
 	journalctl, err := exec.LookPath("journalctl")
 	if err != nil {
 		return fmt.Errorf("could not find `journalctl` executable in PATH: %w", err)
 	}
 
-	cmd := exec.Command(journalctl, journalctlArgs...)
-	cmd.Stdout = stdout
+	cmd := exec.Command(journalctl, append(journalctlArgs, "--output=json", "--no-pager")...)
 	cmd.Stderr = stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start journalctl command with args %#v: %w", journalctlArgs, err)
 	}
 
 	// Setup killing goroutine:
-	killed := false
+	var killed atomic.Bool
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		<-stopChannel
-		killed = true
-		log.L.Debugf("killing journalctl logs process with PID: %#v", cmd.Process.Pid)
-		cmd.Process.Kill()
+		select {
+		case <-stopChannel:
+			killed.Store(true)
+			log.L.Debugf("killing journalctl logs process with PID: %#v", cmd.Process.Pid)
+			cmd.Process.Kill()
+		case <-done:
+		}
 	}()
 
+	decoder := json.NewDecoder(out)
+	for {
+		var entry journalEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if !errors.Is(err, io.EOF) && !killed.Load() {
+				log.L.WithError(err).Warn("failed to decode journalctl output")
+			}
+			break
+		}
+		if err := writeJournalEntry(stdout, stderr, &entry, timestamps); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return err
+		}
+	}
+
 	err = cmd.Wait()
 	if exitError, ok := err.(*exec.ExitError); ok {
-		if !killed && exitError.ExitCode() != 0 {
+		if !killed.Load() && exitError.ExitCode() != 0 {
 			return fmt.Errorf("journalctl command exited with non-zero exit code (%d): %w", exitError.ExitCode(), exitError)
 		}
 	}
@@ -193,53 +247,91 @@ func FetchLogs(stdout, stderr io.Writer, journalctlArgs []string, stopChannel ch
 	return nil
 }
 
+func writeJournalEntry(stdout, stderr io.Writer, entry *journalEntry, timestamps bool) error {
+	var message string
+	if err := json.Unmarshal(entry.Message, &message); err != nil {
+		var raw []int
+		if err := json.Unmarshal(entry.Message, &raw); err != nil {
+			return fmt.Errorf("failed to decode journal message %q: %w", entry.Message, err)
+		}
+		b := make([]byte, len(raw))
+		for i, c := range raw {
+			b[i] = byte(c)
+		}
+		message = string(b)
+	}
+
+	var output []byte
+	if timestamps {
+		usec, err := strconv.ParseInt(entry.RealtimeTimestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid journal timestamp %q: %w", entry.RealtimeTimestamp, err)
+		}
+		output = append(output, time.UnixMicro(usec).Format(time.RFC3339Nano)...)
+		output = append(output, ' ')
+	}
+	output = append(output, strings.TrimSuffix(message, "\n")...)
+	output = append(output, '\n')
+
+	// stderr is sent with journal.PriErr, see JournaldLogger.Process
+	writeTo := stdout
+	if entry.Priority == strconv.Itoa(int(journal.PriErr)) {
+		writeTo = stderr
+	}
+	_, err := writeTo.Write(output)
+	return err
+}
+
 // Formats command line arguments for `journalctl` with the provided log viewing options and
 // exec's and redirects `journalctl`s outputs to the provided io.Writers.
 func viewLogsJournald(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
 	if !checkExecutableAvailableInPath("journalctl") {
 		return fmt.Errorf("`journalctl` executable could not be found in PATH, cannot use Journald to view logs")
 	}
-	shortID := lvopts.ContainerID[:12]
-	var journalctlArgs = []string{fmt.Sprintf("SYSLOG_IDENTIFIER=%s", shortID), "--output=cat"}
+	journalctlArgs, err := buildJournalctlArgs(lvopts, time.Now())
+	if err != nil {
+		return err
+	}
+	return FetchLogs(stdout, stderr, journalctlArgs, lvopts.Timestamps, stopChannel)
+}
+
+func buildJournalctlArgs(lvopts LogViewOptions, now time.Time) ([]string, error) {
+	// CONTAINER_ID_FULL is not affected by the tag log-opt, unlike SYSLOG_IDENTIFIER
+	var journalctlArgs = []string{fmt.Sprintf("CONTAINER_ID_FULL=%s", lvopts.ContainerID)}
 	if lvopts.Follow {
-		journalctlArgs = append(journalctlArgs, "-f")
+		journalctlArgs = append(journalctlArgs, "--follow")
+	}
+	if lvopts.Tail > 0 {
+		journalctlArgs = append(journalctlArgs, "--lines", strconv.FormatUint(uint64(lvopts.Tail), 10))
 	}
 	if lvopts.Since != "" {
-		// using GetTimestamp from moby to keep time format consistency
-		ts, err := timetypes.GetTimestamp(lvopts.Since, time.Now())
+		date, err := prepareJournalCtlDate(lvopts.Since, now)
 		if err != nil {
-			return fmt.Errorf("invalid value for \"since\": %w", err)
-		}
-		date, err := prepareJournalCtlDate(ts)
-		if err != nil {
-			return err
+			return nil, fmt.Errorf("invalid value for \"since\": %w", err)
 		}
 		journalctlArgs = append(journalctlArgs, "--since", date)
 	}
-	if lvopts.Timestamps {
-		log.L.Warnf("unsupported Timestamps option for journald driver")
-	}
 	if lvopts.Until != "" {
-		// using GetTimestamp from moby to keep time format consistency
-		ts, err := timetypes.GetTimestamp(lvopts.Until, time.Now())
-		if err != nil {
-			return fmt.Errorf("invalid value for \"until\": %w", err)
-		}
-		date, err := prepareJournalCtlDate(ts)
+		date, err := prepareJournalCtlDate(lvopts.Until, now)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("invalid value for \"until\": %w", err)
 		}
 		journalctlArgs = append(journalctlArgs, "--until", date)
 	}
-	return FetchLogs(stdout, stderr, journalctlArgs, stopChannel)
+	return journalctlArgs, nil
 }
 
-func prepareJournalCtlDate(t string) (string, error) {
-	i, err := strconv.ParseInt(t, 10, 64)
+// prepareJournalCtlDate converts a Docker timestamp (e.g. "10m", "2006-01-02T15:04:05", or a UNIX timestamp)
+// into a journalctl timestamp in seconds since the epoch, with microseconds precision.
+func prepareJournalCtlDate(value string, now time.Time) (string, error) {
+	// using GetTimestamp from moby to keep time format consistency
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return "", err
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
 	if err != nil {
 		return "", err
 	}
-	tm := time.Unix(i, 0)
-	s := tm.Format("2006-01-02 15:04:05")
-	return s, nil
+	return fmt.Sprintf("@%d.%06d", sec, nsec/int64(time.Microsecond)), nil
 }
@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildJournalctlArgs(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	args, err := buildJournalctlArgs(LogViewOptions{
+		ContainerID: "0123456789abcdef",
+		Follow:      true,
+		Tail:        5,
+		Since:       "10s",
+		Until:       "2023-11-14T22:13:20.5Z",
+	}, now)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{
+		"CONTAINER_ID_FULL=0123456789abcdef",
+		"--follow",
+		"--lines", "5",
+		"--since", "@1699999990.000000",
+		"--until", "@1700000000.500000",
+	})
+
+	_, err = buildJournalctlArgs(LogViewOptions{ContainerID: "0123456789abcdef", Since: "foo"}, now)
+	assert.ErrorContains(t, err, "invalid value for \"since\"")
+}
+
+func TestWriteJournalEntry(t *testing.T) {
+	testCases := []struct {
+		name       string
+		entry      journalEntry
+		timestamps bool
+		stdout     string
+		stderr     string
+	}{
+		{
+			name:   "stdout",
+			entry:  journalEntry{Message: []byte(`"foo"`), Priority: "6"},
+			stdout: "foo\n",
+		},
+		{
+			name:   "stderr",
+			entry:  journalEntry{Message: []byte(`"foo\n"`), Priority: "3"},
+			stderr: "foo\n",
+		},
+		{
+			name:   "binary",
+			entry:  journalEntry{Message: []byte(`[102,111,255]`), Priority: "6"},
+			stdout: "fo\xff\n",
+		},
+		{
+			name:       "timestamps",
+			entry:      journalEntry{Message: []byte(`"foo"`), Priority: "6", RealtimeTimestamp: "1700000000000001"},
+			timestamps: true,
+			stdout:     time.UnixMicro(1700000000000001).Format(time.RFC3339Nano) + " foo\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.NilError(t, writeJournalEntry(&stdout, &stderr, &tc.entry, tc.timestamps))
+			assert.Equal(t, stdout.String(), tc.stdout)
+			assert.Equal(t, stderr.String(), tc.stderr)
+		})
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	assert.Equal(t, journalFieldName("com.example.foo"), "COM_EXAMPLE_FOO")
+	assert.Equal(t, journalFieldName("_foo-bar1"), "FOO_BAR1")
+	assert.Equal(t, journalFieldName("__"), "")
+}
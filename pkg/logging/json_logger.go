@@ -17,6 +17,7 @@
 package logging
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -43,6 +44,7 @@ var JSONDriverLogOpts = []string{
 	LogPath,
 	MaxSize,
 	MaxFile,
+	Compress,
 	Env,
 	Labels,
 }
@@ -58,6 +60,11 @@ func JSONFileLogOptsValidate(logOptMap map[string]string) error {
 			log.L.Warnf("log-opt %s is ignored for json-file log driver", key)
 		}
 	}
+	if v, ok := logOptMap[Compress]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value for log-opt %s (%q): %w", Compress, v, err)
+		}
+	}
 	return nil
 }
 
@@ -118,6 +125,20 @@ func (jsonLogger *JSONLogger) PreProcess(ctx context.Context, dataStore string,
 	}
 	// MaxBackups does not include file to write logs to
 	l.MaxBackups = maxFile - 1
+	if compress, ok := jsonLogger.Opts[Compress]; ok {
+		var err error
+		l.Compress, err = strconv.ParseBool(compress)
+		if err != nil {
+			return err
+		}
+	}
+	// continue the numbering of the files rotated before the container was restarted,
+	// instead of overwriting them
+	fileOrder, err := jsonfile.LastRotatedOrder(jsonFilePath)
+	if err != nil {
+		return err
+	}
+	l.FileOrder = fileOrder
 	jsonLogger.logger = l
 	return nil
 }
@@ -147,9 +168,69 @@ func viewLogsJSONFile(lvopts LogViewOptions, stdout, stderr io.Writer, stopChann
 		}
 	}
 
+	if err := viewLogsJSONFileRotated(lvopts, logFilePath, stdout, stderr); err != nil {
+		return err
+	}
 	return viewLogsJSONFileDirect(lvopts, logFilePath, stdout, stderr, stopChannel)
 }
 
+// Loads JSON log entries from the files rotated from the provided JSON log file,
+// which precede the entries of the file itself.
+// With `LogViewOptions.Tail`, only the rotated files needed to complete the lines of the file itself are loaded.
+func viewLogsJSONFileRotated(lvopts LogViewOptions, jsonLogFilePath string, stdout, stderr io.Writer) error {
+	rotated, err := jsonfile.RotatedPaths(jsonLogFilePath)
+	if err != nil || len(rotated) == 0 {
+		return err
+	}
+	remaining := lvopts.Tail
+	if remaining > 0 {
+		current, err := os.ReadFile(jsonLogFilePath)
+		if err != nil {
+			return err
+		}
+		lines := uint(bytes.Count(current, []byte("\n")))
+		if lines >= remaining {
+			return nil
+		}
+		remaining -= lines
+	}
+
+	// load the rotated files from the newest, until there are enough lines
+	var contents [][]byte
+	for i := len(rotated) - 1; i >= 0; i-- {
+		b, err := jsonfile.ReadRotated(rotated[i])
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// removed by a concurrent rotation
+				break
+			}
+			return fmt.Errorf("failed to read rotated JSON logfile %q: %w", rotated[i], err)
+		}
+		contents = append(contents, b)
+		if remaining > 0 {
+			lines := uint(bytes.Count(b, []byte("\n")))
+			if lines >= remaining {
+				// skip the lines in excess in the oldest file
+				r := bytes.NewReader(b)
+				start, err := tail.FindTailLineStartIndex(r, remaining)
+				if err != nil {
+					return fmt.Errorf("failed to tail %d lines of rotated JSON logfile %q: %w", remaining, rotated[i], err)
+				}
+				contents[len(contents)-1] = b[start:]
+				break
+			}
+			remaining -= lines
+		}
+	}
+
+	for i := len(contents) - 1; i >= 0; i-- {
+		if _, err := jsonfile.Decode(stdout, stderr, bytes.NewReader(contents[i]), lvopts.Timestamps, lvopts.Since, lvopts.Until); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Loads JSON log entries directly from the provided JSON log file.
 // If `LogViewOptions.Follow` is provided, it will refresh and re-read the file until
 // it receives something through the stopChannel.
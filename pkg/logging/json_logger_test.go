@@ -18,6 +18,7 @@ package logging
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -25,6 +26,8 @@ import (
 	"runtime"
 	"testing"
 	"time"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/jsonfile"
 )
 
 func TestReadRotatedJSONLog(t *testing.T) {
@@ -176,3 +179,57 @@ func TestReadJSONLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestViewLogsJSONFileRotated(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "id-json.log")
+	writeLog := func(path string, compress bool, lines ...string) {
+		var buf bytes.Buffer
+		for _, line := range lines {
+			b, err := json.Marshal(map[string]string{"log": line + "\n", "stream": "stdout", "time": time.Now().Format(time.RFC3339Nano)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf.Write(append(b, '\n'))
+		}
+		content := buf.Bytes()
+		if compress {
+			var gz bytes.Buffer
+			w := gzip.NewWriter(&gz)
+			w.Write(content)
+			w.Close()
+			content = gz.Bytes()
+		}
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeLog(logPath+".1.gz", true, "line0", "line1")
+	writeLog(logPath+".2", false, "line2", "line3")
+	writeLog(logPath, false, "line4", "line5")
+
+	order, err := jsonfile.LastRotatedOrder(logPath)
+	if err != nil || order != 2 {
+		t.Fatalf("expected the last rotated order to be 2, got %d (%v)", order, err)
+	}
+
+	for _, tc := range []struct {
+		tail     uint
+		expected string
+	}{
+		{0, "line0\nline1\nline2\nline3\n"},
+		{1, ""},
+		{2, ""},
+		{3, "line3\n"},
+		{5, "line1\nline2\nline3\n"},
+		{10, "line0\nline1\nline2\nline3\n"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if err := viewLogsJSONFileRotated(LogViewOptions{Tail: tc.tail}, logPath, &stdout, &stderr); err != nil {
+			t.Fatal(err)
+		}
+		if stdout.String() != tc.expected {
+			t.Errorf("tail %d: expected %q, got %q", tc.tail, tc.expected, stdout.String())
+		}
+	}
+}
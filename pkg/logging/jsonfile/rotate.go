@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jsonfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompressSuffix is the suffix of the compressed rotated files.
+const CompressSuffix = ".gz"
+
+type rotatedFile struct {
+	order int
+	path  string
+}
+
+// rotatedFiles returns the rotated files of the log file at path, sorted by order.
+// The files are named "<name>.<order>", or "<name>.<order>.gz" when compressed,
+// and the order is incremented on each rotation.
+func rotatedFiles(path string) ([]rotatedFile, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefix := filepath.Base(path) + "."
+	byOrder := make(map[int]rotatedFile)
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		compressed := strings.HasSuffix(name, CompressSuffix)
+		order, err := strconv.Atoi(strings.TrimSuffix(name, CompressSuffix))
+		if err != nil || order <= 0 {
+			continue
+		}
+		// while a rotated file is being compressed, both files exist and the uncompressed one is complete
+		if _, ok := byOrder[order]; ok && compressed {
+			continue
+		}
+		byOrder[order] = rotatedFile{order: order, path: filepath.Join(filepath.Dir(path), entry.Name())}
+	}
+	res := make([]rotatedFile, 0, len(byOrder))
+	for _, f := range byOrder {
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].order < res[j].order })
+	return res, nil
+}
+
+// RotatedPaths returns the paths of the rotated files of the log file at path, from the oldest to the newest.
+func RotatedPaths(path string) ([]string, error) {
+	files, err := rotatedFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// LastRotatedOrder returns the order of the newest rotated file of the log file at path, or 0.
+func LastRotatedOrder(path string) (int, error) {
+	files, err := rotatedFiles(path)
+	if err != nil || len(files) == 0 {
+		return 0, err
+	}
+	return files[len(files)-1].order, nil
+}
+
+// ReadRotated reads the content of the rotated file, decompressing it if needed.
+func ReadRotated(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil || !strings.HasSuffix(path, CompressSuffix) {
+		return b, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,224 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/docker/go-units"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// The log-opts of the local cache kept for the drivers without a log viewer ("dual logging").
+// See https://docs.docker.com/engine/logging/dual-logging/
+const (
+	CacheDisabled = "cache-disabled"
+	CacheMaxSize  = "cache-max-size"
+	CacheMaxFile  = "cache-max-file"
+	CacheCompress = "cache-compress"
+)
+
+// The defaults correspond to Docker.
+const (
+	defaultCacheMaxSize  = "20m"
+	defaultCacheMaxFile  = "5"
+	defaultCacheCompress = "true"
+)
+
+var CacheLogOpts = []string{
+	CacheDisabled,
+	CacheMaxSize,
+	CacheMaxFile,
+	CacheCompress,
+}
+
+// cachingDrivers are the drivers sending the logs to a remote destination,
+// which nerdctl cannot read back from. The logging plugins are cached too.
+var cachingDrivers = []string{
+	"fluentd",
+	"syslog",
+	"awslogs",
+	"gelf",
+	"splunk",
+}
+
+// CachePath returns the path of the local log cache of the container.
+func CachePath(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, "container-cached.log")
+}
+
+// isCachingDriver returns whether the driver has no log viewer, hence needs the local cache.
+func isCachingDriver(driver string) bool {
+	if strutil.InStringSlice(cachingDrivers, driver) {
+		return true
+	}
+	_, builtin := drivers[driver]
+	_, viewer := logViewers[driver]
+	return !builtin && !viewer
+}
+
+// cacheEnabled returns whether the local cache is kept for the driver with the log-opts.
+func cacheEnabled(driver string, opts map[string]string) bool {
+	if !isCachingDriver(driver) {
+		return false
+	}
+	disabled, _ := strconv.ParseBool(opts[CacheDisabled])
+	return !disabled
+}
+
+// validateCacheLogOpts validates the cache log-opts, and returns the other log-opts.
+func validateCacheLogOpts(driver string, logOptMap map[string]string) (map[string]string, error) {
+	others := make(map[string]string, len(logOptMap))
+	for k, v := range logOptMap {
+		if !strutil.InStringSlice(CacheLogOpts, k) {
+			others[k] = v
+			continue
+		}
+		if !isCachingDriver(driver) {
+			log.L.Warnf("log-opt %s is ignored for %s log driver", k, driver)
+			continue
+		}
+		switch k {
+		case CacheDisabled, CacheCompress:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid value for log-opt %s (%q): %w", k, v, err)
+			}
+		case CacheMaxSize:
+			if size, err := units.FromHumanSize(v); err != nil || size <= 0 {
+				return nil, fmt.Errorf("invalid value for log-opt %s (%q): must be a positive size", k, v)
+			}
+		case CacheMaxFile:
+			if n, err := strconv.Atoi(v); err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid value for log-opt %s (%q): must be a positive integer", k, v)
+			}
+		}
+	}
+	return others, nil
+}
+
+// cachingDriver writes the logs to a size-capped json-file cache, in addition to the wrapped driver,
+// so that `nerdctl logs` works for the drivers without a log viewer.
+type cachingDriver struct {
+	Driver
+	cache *JSONLogger
+}
+
+func newCachingDriver(driver Driver, opts map[string]string) *cachingDriver {
+	cacheOpts := map[string]string{
+		MaxSize:  defaultCacheMaxSize,
+		MaxFile:  defaultCacheMaxFile,
+		Compress: defaultCacheCompress,
+	}
+	for k, cacheK := range map[string]string{MaxSize: CacheMaxSize, MaxFile: CacheMaxFile, Compress: CacheCompress} {
+		if v, ok := opts[cacheK]; ok {
+			cacheOpts[k] = v
+		}
+	}
+	return &cachingDriver{
+		Driver: driver,
+		cache:  &JSONLogger{Opts: cacheOpts},
+	}
+}
+
+func (d *cachingDriver) Init(dataStore, ns, id string) error {
+	if err := d.Driver.Init(dataStore, ns, id); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, ns, id)
+	return d.cache.Init(dataStore, ns, id)
+}
+
+func (d *cachingDriver) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	if err := d.Driver.PreProcess(ctx, dataStore, config); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, config.Namespace, config.ID)
+	return d.cache.PreProcess(ctx, dataStore, config)
+}
+
+func (d *cachingDriver) Process(stdout <-chan string, stderr <-chan string) error {
+	driverStdout, driverStderr := make(chan string, cap(stdout)), make(chan string, cap(stderr))
+	cacheStdout, cacheStderr := make(chan string, cap(stdout)), make(chan string, cap(stderr))
+	tee := func(in <-chan string, out1, out2 chan<- string) {
+		defer close(out1)
+		defer close(out2)
+		for s := range in {
+			out1 <- s
+			out2 <- s
+		}
+	}
+	go tee(stdout, driverStdout, cacheStdout)
+	go tee(stderr, driverStderr, cacheStderr)
+
+	var (
+		wg       sync.WaitGroup
+		cacheErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cacheErr = d.cache.Process(cacheStdout, cacheStderr)
+		if cacheErr != nil {
+			log.L.WithError(cacheErr).Error("failed to write the local log cache")
+			// keep draining, so that the driver is not blocked
+			drain(cacheStdout, cacheStderr)
+		}
+	}()
+	err := d.Driver.Process(driverStdout, driverStderr)
+	// keep draining, so that the cache is not blocked if the driver has returned early
+	drain(driverStdout, driverStderr)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	return cacheErr
+}
+
+func drain(chans ...<-chan string) {
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range ch {
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// viewLogsCache loads the log entries from the local cache of the drivers without a log viewer.
+func viewLogsCache(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	cachePath := CachePath(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	if _, err := os.Stat(cachePath); err != nil {
+		return fmt.Errorf("failed to stat the local log cache: %w", err)
+	}
+	if err := viewLogsJSONFileRotated(lvopts, cachePath, stdout, stderr); err != nil {
+		return err
+	}
+	return viewLogsJSONFileDirect(lvopts, cachePath, stdout, stderr, stopChannel)
+}
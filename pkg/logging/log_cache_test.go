@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+func TestValidateCacheLogOpts(t *testing.T) {
+	others, err := validateCacheLogOpts("fluentd", map[string]string{
+		CacheDisabled:     "false",
+		CacheMaxSize:      "1m",
+		CacheMaxFile:      "2",
+		"fluentd-address": "localhost:24224",
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, others, map[string]string{"fluentd-address": "localhost:24224"})
+
+	for _, opts := range []map[string]string{
+		{CacheDisabled: "foo"},
+		{CacheMaxSize: "-1"},
+		{CacheMaxFile: "0"},
+		{CacheCompress: "foo"},
+	} {
+		_, err := validateCacheLogOpts("syslog", opts)
+		assert.ErrorContains(t, err, "invalid value", "opts: %v", opts)
+	}
+
+	// ignored for the drivers with a log viewer
+	others, err = validateCacheLogOpts("json-file", map[string]string{CacheMaxFile: "0"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(others), 0)
+}
+
+func TestCacheEnabled(t *testing.T) {
+	assert.Assert(t, cacheEnabled("fluentd", nil))
+	assert.Assert(t, cacheEnabled("awslogs", map[string]string{CacheDisabled: "false"}))
+	assert.Assert(t, !cacheEnabled("syslog", map[string]string{CacheDisabled: "true"}))
+	assert.Assert(t, !cacheEnabled("json-file", nil))
+	assert.Assert(t, !cacheEnabled("journald", nil))
+}
+
+func TestCachingDriver(t *testing.T) {
+	dataStore := t.TempDir()
+	const ns, id = "default", "0123456789abcdef"
+
+	mock := &MockDriver{}
+	driver := newCachingDriver(mock, map[string]string{CacheCompress: "false"})
+	assert.NilError(t, driver.Init(dataStore, ns, id))
+	assert.NilError(t, driver.PreProcess(context.Background(), dataStore, &logging.Config{Namespace: ns, ID: id}))
+
+	stdout := make(chan string, 10)
+	stderr := make(chan string, 10)
+	stdout <- "foo\n"
+	stdout <- "bar\n"
+	stderr <- "baz\n"
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+
+	assert.DeepEqual(t, mock.receivedStdout, []string{"foo\n", "bar\n"})
+	assert.DeepEqual(t, mock.receivedStderr, []string{"baz\n"})
+
+	var viewStdout, viewStderr bytes.Buffer
+	lvopts := LogViewOptions{
+		ContainerID:       id,
+		Namespace:         ns,
+		DatastoreRootPath: dataStore,
+	}
+	assert.NilError(t, viewLogsCache(lvopts, &viewStdout, &viewStderr, nil))
+	assert.Equal(t, viewStdout.String(), "foo\nbar\n")
+	assert.Equal(t, viewStderr.String(), "baz\n")
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/docker/cli/templates"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+)
+
+// identifier is the data available to the template of the tag log-opt, e.g. `{{.Name}}/{{.ID}}`.
+type identifier struct {
+	ID        string
+	FullID    string
+	Namespace string
+	Name      string
+	ImageName string
+}
+
+// loadContainerInfo loads the container from containerd.
+func loadContainerInfo(ctx context.Context, address string, config *logging.Config) (containers.Container, error) {
+	client, ctx, cancel, err := clientutil.NewClient(ctx, config.Namespace, address)
+	if err != nil {
+		return containers.Container{}, err
+	}
+	defer func() {
+		cancel()
+		client.Close()
+	}()
+	container, err := client.LoadContainer(ctx, config.ID)
+	if err != nil {
+		return containers.Container{}, err
+	}
+	return container.Info(ctx)
+}
+
+func newIdentifier(config *logging.Config, info containers.Container) identifier {
+	return identifier{
+		ID:        config.ID[:12],
+		FullID:    config.ID,
+		Namespace: config.Namespace,
+		Name:      containerutil.GetContainerName(info.Labels),
+		ImageName: info.Image,
+	}
+}
+
+// loadIdentifier returns the identifier of the container, with the name and the image loaded from containerd.
+func loadIdentifier(ctx context.Context, address string, config *logging.Config) (identifier, error) {
+	info, err := loadContainerInfo(ctx, address, config)
+	if err != nil {
+		return identifier{}, err
+	}
+	return newIdentifier(config, info), nil
+}
+
+// executeLogTag executes the template of the tag log-opt.
+func executeLogTag(tag string, idn identifier) (string, error) {
+	tmpl, err := templates.Parse(tag)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, idn); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// validateLogTag checks the template of the tag log-opt, if any.
+func validateLogTag(logOptMap map[string]string) error {
+	if tag, ok := logOptMap[Tag]; ok {
+		_, err := templates.Parse(tag)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExecuteLogTag(t *testing.T) {
+	idn := identifier{
+		ID:        "0123456789ab",
+		FullID:    "0123456789abcdef",
+		Namespace: "default",
+		Name:      "foo",
+		ImageName: "docker.io/library/alpine:latest",
+	}
+	tag, err := executeLogTag("{{.Name}}/{{.ID}}", idn)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "foo/0123456789ab")
+
+	tag, err = executeLogTag("{{.Namespace}}.{{.FullID}}.{{.ImageName}}", idn)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "default.0123456789abcdef.docker.io/library/alpine:latest")
+
+	tag, err = executeLogTag("static", idn)
+	assert.NilError(t, err)
+	assert.Equal(t, tag, "static")
+
+	_, err = executeLogTag("{{.Foo}}", idn)
+	assert.Assert(t, err != nil)
+
+	assert.Assert(t, validateLogTag(map[string]string{Tag: "{{"}) != nil)
+	assert.NilError(t, validateLogTag(map[string]string{}))
+}
@@ -166,7 +166,11 @@ func (lv *ContainerLogViewer) PrintLogsTo(stdout, stderr io.Writer) error {
 	}
 	viewerFunc, err := getLogViewer(lv.loggingConfig.Driver)
 	if err != nil {
-		return err
+		if !cacheEnabled(lv.loggingConfig.Driver, lv.loggingConfig.Opts) {
+			return err
+		}
+		// the driver has no log viewer, read the local cache instead
+		viewerFunc = viewLogsCache
 	}
 
 	return viewerFunc(lv.logViewingOptions, stdout, stderr, lv.stopChannel)
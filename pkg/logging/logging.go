@@ -39,6 +39,8 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/secretutil"
 )
 
 const (
@@ -47,11 +49,35 @@ const (
 	LogPath    = "log-path"
 	MaxSize    = "max-size"
 	MaxFile    = "max-file"
+	Compress   = "compress"
 	Tag        = "tag"
 	Env        = "env"
 	Labels     = "labels"
 )
 
+// extraAttributes returns the container labels and environment variables
+// requested by the comma-separated lists of the labels and env log-opts.
+func extraAttributes(opts map[string]string, labels map[string]string, env []string) map[string]string {
+	extra := map[string]string{}
+	if opts[Labels] != "" {
+		for _, k := range strings.Split(opts[Labels], ",") {
+			if v, ok := labels[k]; ok {
+				extra[k] = v
+			}
+		}
+	}
+	if opts[Env] != "" {
+		for _, k := range strings.Split(opts[Env], ",") {
+			for _, e := range env {
+				if name, v, ok := strings.Cut(e, "="); ok && name == k {
+					extra[k] = v
+				}
+			}
+		}
+	}
+	return extra
+}
+
 type Driver interface {
 	Init(dataStore, ns, id string) error
 	PreProcess(ctx context.Context, dataStore string, config *logging.Config) error
@@ -66,6 +92,10 @@ var drivers = make(map[string]DriverFactory)
 var driversLogOptsValidateFunctions = make(map[string]LogOptsValidateFunc)
 
 func ValidateLogOpts(logDriver string, logOpts map[string]string) error {
+	logOpts, err := validateCacheLogOpts(logDriver, logOpts)
+	if err != nil {
+		return err
+	}
 	if value, ok := driversLogOptsValidateFunctions[logDriver]; ok && value != nil {
 		return value(logOpts)
 	}
@@ -87,11 +117,26 @@ func Drivers() []string {
 }
 
 func GetDriver(name string, opts map[string]string, address string) (Driver, error) {
-	driverFactory, ok := drivers[name]
-	if !ok {
-		return nil, fmt.Errorf("unknown logging driver %q: %w", name, errdefs.ErrNotFound)
+	var (
+		driver Driver
+		err    error
+	)
+	if driverFactory, ok := drivers[name]; ok {
+		driver, err = driverFactory(opts, address)
+	} else {
+		// fall back to the logging plugins speaking Docker's LogDriver protocol
+		driver, err = newPluginLogger(name, opts, address)
+		if errdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("unknown logging driver %q: %w", name, errdefs.ErrNotFound)
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
-	return driverFactory(opts, address)
+	if cacheEnabled(name, opts) {
+		return newCachingDriver(driver, opts), nil
+	}
+	return driver, nil
 }
 
 func init() {
@@ -108,8 +153,17 @@ func init() {
 		return &FluentdLogger{Opts: opts}, nil
 	}, FluentdLogOptsValidate)
 	RegisterDriver("syslog", func(opts map[string]string, address string) (Driver, error) {
-		return &SyslogLogger{Opts: opts}, nil
+		return &SyslogLogger{Opts: opts, Address: address}, nil
 	}, SyslogOptsValidate)
+	RegisterDriver("awslogs", func(opts map[string]string, address string) (Driver, error) {
+		return &AWSLogsLogger{Opts: opts, Address: address}, nil
+	}, AWSLogsOptsValidate)
+	RegisterDriver("gelf", func(opts map[string]string, address string) (Driver, error) {
+		return &GelfLogger{Opts: opts, Address: address}, nil
+	}, GelfLogOptsValidate)
+	RegisterDriver("splunk", func(opts map[string]string, address string) (Driver, error) {
+		return &SplunkLogger{Opts: opts, Address: address}, nil
+	}, SplunkLogOptsValidate)
 }
 
 // Main is the entrypoint for the containerd runtime v2 logging plugin mode.
@@ -293,6 +347,12 @@ func loggerFunc(dataStore string) (logging.LoggerFunc, error) {
 		if config.Namespace == "" || config.ID == "" {
 			return errors.New("got invalid config")
 		}
+		// the logging process is started before the task is created, so the secrets removed
+		// from the runtime dir (e.g., by a reboot) are written again here for the containers restarted by containerd.
+		stateDir := filepath.Join(dataStore, "containers", config.Namespace, config.ID)
+		if err := secretutil.Restore(ctx, stateDir); err != nil {
+			return err
+		}
 		logConfigFilePath := LogConfigFilePath(dataStore, config.Namespace, config.ID)
 		if _, err := os.Stat(logConfigFilePath); err == nil {
 			logConfig, err := LoadLogConfig(dataStore, config.Namespace, config.ID)
@@ -310,6 +370,18 @@ func loggerFunc(dataStore string) (logging.LoggerFunc, error) {
 			// stopped and the driver has finished processing all output,
 			// so that waiting log viewers can be signalled when the process is complete.
 			return filesystem.WithLock(loggerLock, func() error {
+				// the volume subpaths are resolved, and the volumes of volume plugins are mounted, on every start,
+				// and released when the container is stopped.
+				// They are mounted with the lock held, so that the logging process of the previous run of
+				// a restarted container does not unmount them.
+				if err := mountutil.MountVolumeSubpaths(ctx, stateDir); err != nil {
+					return err
+				}
+				defer func() {
+					if err := mountutil.UnmountVolumeSubpaths(ctx, stateDir); err != nil {
+						log.G(ctx).WithError(err).Warn("failed to unmount the volume subpaths")
+					}
+				}()
 				if err := ready(); err != nil {
 					return err
 				}
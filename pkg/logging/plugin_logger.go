@@ -0,0 +1,369 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// The directories searched for the logging plugins, as Docker does for the legacy (v1) plugins.
+// See https://docs.docker.com/engine/extend/plugin_api/#plugin-discovery
+var (
+	pluginSocketsDir = "/run/docker/plugins"
+	pluginSpecsDirs  = []string{"/etc/docker/plugins", "/usr/lib/docker/plugins"}
+)
+
+const (
+	pluginContentType     = "application/vnd.docker.plugins.v1.2+json"
+	pluginRequestTimeout  = 30 * time.Second
+	pluginLogDriverImplem = "LogDriver"
+)
+
+// pluginInfo is the container information passed to the plugin on StartLogging.
+// The fields correspond to Docker's logger.Info.
+type pluginInfo struct {
+	Config              map[string]string
+	ContainerID         string
+	ContainerName       string
+	ContainerEntrypoint string
+	ContainerArgs       []string
+	ContainerImageID    string
+	ContainerImageName  string
+	ContainerCreated    time.Time
+	ContainerEnv        []string
+	ContainerLabels     map[string]string
+	LogPath             string
+	DaemonName          string
+}
+
+type pluginStartLoggingRequest struct {
+	File string
+	Info pluginInfo
+}
+
+type pluginStopLoggingRequest struct {
+	File string
+}
+
+type pluginResponse struct {
+	Err string
+}
+
+type pluginActivateResponse struct {
+	Implements []string
+}
+
+// pluginClient calls the HTTP API of a logging plugin.
+type pluginClient struct {
+	name   string
+	base   string
+	client *http.Client
+}
+
+// lookupLogPlugin finds the logging plugin from its socket in pluginSocketsDir,
+// or its .spec or .json file in pluginSpecsDirs.
+func lookupLogPlugin(name string) (*pluginClient, error) {
+	if name == "" || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid logging plugin name %q: %w", name, errdefs.ErrInvalidArgument)
+	}
+	for _, p := range []string{
+		filepath.Join(pluginSocketsDir, name+".sock"),
+		filepath.Join(pluginSocketsDir, name, name+".sock"),
+	} {
+		if fi, err := os.Stat(p); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			return newPluginClient(name, "unix://"+p)
+		}
+	}
+	for _, dir := range pluginSpecsDirs {
+		for _, ext := range []string{".spec", ".json"} {
+			b, err := os.ReadFile(filepath.Join(dir, name+ext))
+			if err != nil {
+				continue
+			}
+			addr := strings.TrimSpace(string(b))
+			if ext == ".json" {
+				var spec struct {
+					Addr string
+				}
+				if err := json.Unmarshal(b, &spec); err != nil {
+					return nil, fmt.Errorf("failed to parse the spec of logging plugin %q: %w", name, err)
+				}
+				addr = spec.Addr
+			}
+			return newPluginClient(name, addr)
+		}
+	}
+	return nil, fmt.Errorf("logging plugin %q not found: %w", name, errdefs.ErrNotFound)
+}
+
+func newPluginClient(name, addr string) (*pluginClient, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address of logging plugin %q (%q): %w", name, addr, err)
+	}
+	transport := &http.Transport{}
+	base := addr
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		base = "http://plugin"
+	case "tcp", "http":
+		base = "http://" + u.Host
+	case "https":
+	default:
+		return nil, fmt.Errorf("unsupported address of logging plugin %q (%q)", name, addr)
+	}
+	return &pluginClient{
+		name:   name,
+		base:   base,
+		client: &http.Client{Transport: transport, Timeout: pluginRequestTimeout},
+	}, nil
+}
+
+func (c *pluginClient) call(method string, req, resp any) error {
+	var body bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&body).Encode(req); err != nil {
+			return err
+		}
+	}
+	r, err := c.client.Post(c.base+"/"+method, pluginContentType, &body)
+	if err != nil {
+		return fmt.Errorf("failed to call %s of logging plugin %q: %w", method, c.name, err)
+	}
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call %s of logging plugin %q: %s: %s", method, c.name, r.Status, strings.TrimSpace(string(b)))
+	}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return fmt.Errorf("failed to decode the response of %s of logging plugin %q: %w", method, c.name, err)
+	}
+	return nil
+}
+
+// activate checks that the plugin implements the LogDriver protocol.
+func (c *pluginClient) activate() error {
+	var resp pluginActivateResponse
+	if err := c.call("Plugin.Activate", nil, &resp); err != nil {
+		return err
+	}
+	for _, implem := range resp.Implements {
+		if implem == pluginLogDriverImplem {
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin %q does not implement %s (implements %v)", c.name, pluginLogDriverImplem, resp.Implements)
+}
+
+func (c *pluginClient) startLogging(file string, info pluginInfo) error {
+	var resp pluginResponse
+	if err := c.call("LogDriver.StartLogging", pluginStartLoggingRequest{File: file, Info: info}, &resp); err != nil {
+		return err
+	}
+	if resp.Err != "" {
+		return fmt.Errorf("logging plugin %q failed to start logging: %s", c.name, resp.Err)
+	}
+	return nil
+}
+
+func (c *pluginClient) stopLogging(file string) error {
+	var resp pluginResponse
+	if err := c.call("LogDriver.StopLogging", pluginStopLoggingRequest{File: file}, &resp); err != nil {
+		return err
+	}
+	if resp.Err != "" {
+		return fmt.Errorf("logging plugin %q failed to stop logging: %s", c.name, resp.Err)
+	}
+	return nil
+}
+
+// encodePluginLogEntry appends the length-prefixed protobuf LogEntry message
+// streamed to the plugins through the fifo.
+// See https://github.com/moby/moby/blob/v28.0.0/api/types/plugins/logdriver/entry.proto
+func encodePluginLogEntry(b []byte, source string, t time.Time, line string) []byte {
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	msg = protowire.AppendString(msg, source)
+	msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(t.UnixNano()))
+	msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+	msg = protowire.AppendString(msg, line)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// PluginLogger sends the logs to a logging plugin implementing Docker's LogDriver protocol.
+// See https://docs.docker.com/engine/extend/plugins_logging/
+type PluginLogger struct {
+	Name    string
+	Opts    map[string]string
+	Address string
+
+	plugin   *pluginClient
+	fifoPath string
+	fifo     io.WriteCloser
+}
+
+func newPluginLogger(name string, opts map[string]string, address string) (*PluginLogger, error) {
+	plugin, err := lookupLogPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	return &PluginLogger{
+		Name:    name,
+		Opts:    opts,
+		Address: address,
+		plugin:  plugin,
+	}, nil
+}
+
+func pluginFifoPath(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, "log-plugin.fifo")
+}
+
+func (p *PluginLogger) Init(dataStore, ns, id string) error {
+	return p.plugin.activate()
+}
+
+func (p *PluginLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	if err := p.plugin.activate(); err != nil {
+		return err
+	}
+	info, err := loadContainerInfo(ctx, p.Address, config)
+	if err != nil {
+		return err
+	}
+	var spec specs.Spec
+	if info.Spec != nil {
+		if err := json.Unmarshal(info.Spec.GetValue(), &spec); err != nil {
+			return err
+		}
+	}
+	pinfo := pluginInfo{
+		Config:             make(map[string]string),
+		ContainerID:        config.ID,
+		ContainerName:      "/" + containerutil.GetContainerName(info.Labels),
+		ContainerImageName: info.Image,
+		ContainerCreated:   info.CreatedAt,
+		ContainerLabels:    info.Labels,
+		DaemonName:         "nerdctl",
+	}
+	for k, v := range p.Opts {
+		if !strutil.InStringSlice(CacheLogOpts, k) {
+			pinfo.Config[k] = v
+		}
+	}
+	if spec.Process != nil {
+		pinfo.ContainerEnv = spec.Process.Env
+		if len(spec.Process.Args) > 0 {
+			pinfo.ContainerEntrypoint = spec.Process.Args[0]
+			pinfo.ContainerArgs = spec.Process.Args[1:]
+		}
+	}
+
+	p.fifoPath = pluginFifoPath(dataStore, config.Namespace, config.ID)
+	if err := os.Remove(p.fifoPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// the fifo is opened asynchronously, until the plugin opens it for reading on StartLogging
+	p.fifo, err = openPluginFifo(ctx, p.fifoPath)
+	if err != nil {
+		return fmt.Errorf("failed to create the fifo of logging plugin %q: %w", p.Name, err)
+	}
+	if err := p.plugin.startLogging(p.fifoPath, pinfo); err != nil {
+		p.fifo.Close()
+		return err
+	}
+	return nil
+}
+
+func (p *PluginLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	write := func(source, s string) error {
+		_, err := p.fifo.Write(encodePluginLogEntry(nil, source, time.Now().UTC(), strings.TrimSuffix(s, "\n")))
+		return err
+	}
+	for stdout != nil || stderr != nil {
+		var (
+			source string
+			s      string
+			ok     bool
+		)
+		select {
+		case s, ok = <-stdout:
+			if !ok {
+				stdout = nil
+				continue
+			}
+			source = "stdout"
+		case s, ok = <-stderr:
+			if !ok {
+				stderr = nil
+				continue
+			}
+			source = "stderr"
+		}
+		if err := write(source, s); err != nil {
+			return fmt.Errorf("failed to write to logging plugin %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *PluginLogger) PostProcess() error {
+	if p.fifo == nil {
+		return nil
+	}
+	if err := p.fifo.Close(); err != nil {
+		log.L.WithError(err).Warnf("failed to close the fifo of logging plugin %q", p.Name)
+	}
+	err := p.plugin.stopLogging(p.fifoPath)
+	if rmErr := os.Remove(p.fifoPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		log.L.WithError(rmErr).Warnf("failed to remove the fifo of logging plugin %q", p.Name)
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+//go:build !windows
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+type testPluginLogEntry struct {
+	Source   string
+	TimeNano int64
+	Line     string
+}
+
+func decodeTestPluginLogEntries(t *testing.T, b []byte) []testPluginLogEntry {
+	var entries []testPluginLogEntry
+	for len(b) > 0 {
+		size := binary.BigEndian.Uint32(b)
+		msg := b[4 : 4+size]
+		b = b[4+size:]
+		var entry testPluginLogEntry
+		for len(msg) > 0 {
+			num, typ, n := protowire.ConsumeTag(msg)
+			assert.Assert(t, n > 0)
+			msg = msg[n:]
+			switch {
+			case num == 1 && typ == protowire.BytesType:
+				v, n := protowire.ConsumeString(msg)
+				entry.Source, msg = v, msg[n:]
+			case num == 2 && typ == protowire.VarintType:
+				v, n := protowire.ConsumeVarint(msg)
+				entry.TimeNano, msg = int64(v), msg[n:]
+			case num == 3 && typ == protowire.BytesType:
+				v, n := protowire.ConsumeString(msg)
+				entry.Line, msg = v, msg[n:]
+			default:
+				t.Fatalf("unexpected field %d", num)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestEncodePluginLogEntry(t *testing.T) {
+	now := time.Unix(1700000000, 123)
+	b := encodePluginLogEntry(nil, "stdout", now, "foo")
+	b = encodePluginLogEntry(b, "stderr", now, "")
+	assert.DeepEqual(t, decodeTestPluginLogEntries(t, b), []testPluginLogEntry{
+		{Source: "stdout", TimeNano: now.UnixNano(), Line: "foo"},
+		{Source: "stderr", TimeNano: now.UnixNano()},
+	})
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestPluginLoggerProcess(t *testing.T) {
+	var buf bytes.Buffer
+	p := &PluginLogger{Name: "test", fifo: nopWriteCloser{&buf}}
+	stdout := make(chan string, 2)
+	stderr := make(chan string, 1)
+	stdout <- "foo\n"
+	stdout <- "bar"
+	close(stdout)
+	stderr <- "baz\n"
+	close(stderr)
+	assert.NilError(t, p.Process(stdout, stderr))
+
+	lines := map[string][]string{}
+	for _, entry := range decodeTestPluginLogEntries(t, buf.Bytes()) {
+		lines[entry.Source] = append(lines[entry.Source], entry.Line)
+	}
+	assert.DeepEqual(t, lines, map[string][]string{
+		"stdout": {"foo", "bar"},
+		"stderr": {"baz"},
+	})
+}
+
+func TestLookupLogPlugin(t *testing.T) {
+	// keep the socket path short
+	dir, err := os.MkdirTemp("", "nerdctl-plugins")
+	assert.NilError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	oldSocketsDir, oldSpecsDirs := pluginSocketsDir, pluginSpecsDirs
+	pluginSocketsDir, pluginSpecsDirs = filepath.Join(dir, "run"), []string{filepath.Join(dir, "etc")}
+	t.Cleanup(func() { pluginSocketsDir, pluginSpecsDirs = oldSocketsDir, oldSpecsDirs })
+	assert.NilError(t, os.MkdirAll(pluginSocketsDir, 0700))
+	assert.NilError(t, os.MkdirAll(pluginSpecsDirs[0], 0700))
+
+	var started, stopped pluginStartLoggingRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Plugin.Activate", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pluginActivateResponse{Implements: []string{"LogDriver"}})
+	})
+	mux.HandleFunc("/LogDriver.StartLogging", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&started)
+		json.NewEncoder(w).Encode(pluginResponse{})
+	})
+	mux.HandleFunc("/LogDriver.StopLogging", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&stopped)
+		json.NewEncoder(w).Encode(pluginResponse{Err: "already stopped"})
+	})
+
+	l, err := net.Listen("unix", filepath.Join(pluginSocketsDir, "sock-plugin.sock"))
+	assert.NilError(t, err)
+	unixServer := httptest.NewUnstartedServer(mux)
+	unixServer.Listener = l
+	unixServer.Start()
+	t.Cleanup(unixServer.Close)
+
+	tcpServer := httptest.NewServer(mux)
+	t.Cleanup(tcpServer.Close)
+	assert.NilError(t, os.WriteFile(filepath.Join(pluginSpecsDirs[0], "spec-plugin.spec"),
+		[]byte("tcp://"+tcpServer.Listener.Addr().String()+"\n"), 0600))
+	assert.NilError(t, os.WriteFile(filepath.Join(pluginSpecsDirs[0], "json-plugin.json"),
+		[]byte(`{"Name": "json-plugin", "Addr": "`+tcpServer.URL+`"}`), 0600))
+
+	for _, name := range []string{"sock-plugin", "spec-plugin", "json-plugin"} {
+		c, err := lookupLogPlugin(name)
+		assert.NilError(t, err, name)
+		assert.NilError(t, c.activate(), name)
+		assert.NilError(t, c.startLogging("/fifo", pluginInfo{ContainerID: "foo", Config: map[string]string{"a": "b"}}), name)
+		assert.Equal(t, started.File, "/fifo")
+		assert.Equal(t, started.Info.ContainerID, "foo")
+		assert.DeepEqual(t, started.Info.Config, map[string]string{"a": "b"})
+		assert.ErrorContains(t, c.stopLogging("/fifo"), "already stopped")
+		assert.Equal(t, stopped.File, "/fifo")
+	}
+
+	_, err = lookupLogPlugin("nonexistent")
+	assert.Assert(t, errdefs.IsNotFound(err))
+	_, err = GetDriver("nonexistent", nil, "")
+	assert.ErrorContains(t, err, "unknown logging driver")
+}
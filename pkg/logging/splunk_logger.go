@@ -0,0 +1,483 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	splunkURL                = "splunk-url"
+	splunkToken              = "splunk-token"
+	splunkSource             = "splunk-source"
+	splunkSourceType         = "splunk-sourcetype"
+	splunkIndex              = "splunk-index"
+	splunkCAPath             = "splunk-capath"
+	splunkCAName             = "splunk-caname"
+	splunkInsecureSkipVerify = "splunk-insecureskipverify"
+	splunkFormat             = "splunk-format"
+	splunkVerifyConnection   = "splunk-verify-connection"
+	splunkGzipCompression    = "splunk-gzip"
+	splunkGzipLevel          = "splunk-gzip-level"
+)
+
+var splunkOpts = []string{
+	splunkURL,
+	splunkToken,
+	splunkSource,
+	splunkSourceType,
+	splunkIndex,
+	splunkCAPath,
+	splunkCAName,
+	splunkInsecureSkipVerify,
+	splunkFormat,
+	splunkVerifyConnection,
+	splunkGzipCompression,
+	splunkGzipLevel,
+	Tag,
+	Labels,
+	Env,
+}
+
+const (
+	splunkFormatInline = "inline"
+	splunkFormatJSON   = "json"
+	splunkFormatRaw    = "raw"
+)
+
+// Like Docker, the batching is configured with environment variables of the logging process.
+const (
+	splunkEnvPostMessagesFrequency = "SPLUNK_LOGGING_DRIVER_POST_MESSAGES_FREQUENCY"
+	splunkEnvPostMessagesBatchSize = "SPLUNK_LOGGING_DRIVER_POST_MESSAGES_BATCH_SIZE"
+	splunkEnvBufferMaximum         = "SPLUNK_LOGGING_DRIVER_BUFFER_MAX"
+	splunkEnvChannelSize           = "SPLUNK_LOGGING_DRIVER_CHANNEL_SIZE"
+
+	splunkDefaultPostMessagesFrequency = 5 * time.Second
+	splunkDefaultPostMessagesBatchSize = 1000
+	splunkDefaultBufferMaximum         = 10 * 1000
+	splunkDefaultChannelSize           = 4 * 1000
+)
+
+func SplunkLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(splunkOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for splunk log driver", key)
+		}
+	}
+	if _, err := parseSplunkConfig(logOptMap); err != nil {
+		return err
+	}
+	return validateLogTag(logOptMap)
+}
+
+type splunkConfig struct {
+	url              string
+	token            string
+	source           string
+	sourceType       string
+	index            string
+	format           string
+	verifyConnection bool
+	gzip             bool
+	gzipLevel        int
+	tlsConfig        *tls.Config
+}
+
+func parseSplunkConfig(opts map[string]string) (*splunkConfig, error) {
+	cfg := &splunkConfig{
+		token:            opts[splunkToken],
+		source:           opts[splunkSource],
+		sourceType:       opts[splunkSourceType],
+		index:            opts[splunkIndex],
+		format:           splunkFormatInline,
+		verifyConnection: true,
+		gzipLevel:        gzip.DefaultCompression,
+		tlsConfig:        &tls.Config{},
+	}
+	if opts[splunkURL] == "" {
+		return nil, fmt.Errorf("must specify a value for log opt %q", splunkURL)
+	}
+	u, err := url.Parse(opts[splunkURL])
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for log opt %q (%q): %w", splunkURL, opts[splunkURL], err)
+	}
+	if !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid value for log opt %q (%q): expected format is scheme://dns_name_or_ip:port", splunkURL, opts[splunkURL])
+	}
+	u.Path = "/services/collector/event/1.0"
+	cfg.url = u.String()
+	if cfg.token == "" {
+		return nil, fmt.Errorf("must specify a value for log opt %q", splunkToken)
+	}
+
+	if v, ok := opts[splunkFormat]; ok {
+		switch v {
+		case splunkFormatInline, splunkFormatJSON, splunkFormatRaw:
+			cfg.format = v
+		default:
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be inline, json, or raw", splunkFormat, v)
+		}
+	}
+	parseBool := func(key string, v *bool) error {
+		if s, ok := opts[key]; ok {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return fmt.Errorf("invalid value for log opt %q (%q): %w", key, s, err)
+			}
+			*v = b
+		}
+		return nil
+	}
+	if err := parseBool(splunkVerifyConnection, &cfg.verifyConnection); err != nil {
+		return nil, err
+	}
+	if err := parseBool(splunkGzipCompression, &cfg.gzip); err != nil {
+		return nil, err
+	}
+	if err := parseBool(splunkInsecureSkipVerify, &cfg.tlsConfig.InsecureSkipVerify); err != nil {
+		return nil, err
+	}
+	if v, ok := opts[splunkGzipLevel]; ok {
+		level, err := strconv.Atoi(v)
+		if err != nil || level < gzip.DefaultCompression || level > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid value for log opt %q (%q): must be an integer between -1 and 9", splunkGzipLevel, v)
+		}
+		cfg.gzipLevel = level
+	}
+	if caPath := opts[splunkCAPath]; caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificates of log opt %q: %w", splunkCAPath, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %q (log opt %q)", caPath, splunkCAPath)
+		}
+		cfg.tlsConfig.RootCAs = pool
+	}
+	cfg.tlsConfig.ServerName = opts[splunkCAName]
+	return cfg, nil
+}
+
+// splunkMessage is an event of the HTTP Event Collector.
+type splunkMessage struct {
+	Event      any    `json:"event"`
+	Time       string `json:"time"`
+	Host       string `json:"host"`
+	Source     string `json:"source,omitempty"`
+	SourceType string `json:"sourcetype,omitempty"`
+	Index      string `json:"index,omitempty"`
+}
+
+// splunkMessageEvent is the event of the inline and json formats.
+type splunkMessageEvent struct {
+	Line   any               `json:"line"`
+	Source string            `json:"source"`
+	Tag    string            `json:"tag,omitempty"`
+	Attrs  map[string]string `json:"attrs,omitempty"`
+}
+
+type SplunkLogger struct {
+	Opts    map[string]string
+	Address string
+	cfg     *splunkConfig
+	client  *http.Client
+	host    string
+	tag     string
+	attrs   map[string]string
+	// rawPrefix is the prefix of the events in the raw format.
+	rawPrefix string
+
+	postFrequency time.Duration
+	batchSize     int
+	bufferMaximum int
+	messages      chan *splunkMessage
+	done          chan struct{}
+}
+
+func (s *SplunkLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (s *SplunkLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	cfg, err := parseSplunkConfig(s.Opts)
+	if err != nil {
+		return err
+	}
+	info, err := loadContainerInfo(ctx, s.Address, config)
+	if err != nil {
+		return err
+	}
+	var env []string
+	if s.Opts[Env] != "" && info.Spec != nil {
+		var spec struct {
+			Process *struct {
+				Env []string `json:"env"`
+			} `json:"process"`
+		}
+		if err := json.Unmarshal(info.Spec.GetValue(), &spec); err != nil {
+			return err
+		}
+		if spec.Process != nil {
+			env = spec.Process.Env
+		}
+	}
+	idn := newIdentifier(config, info)
+	tag := idn.ID
+	if cfgTag, ok := s.Opts[Tag]; ok {
+		if tag, err = executeLogTag(cfgTag, idn); err != nil {
+			return err
+		}
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	s.cfg = cfg
+	s.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: cfg.tlsConfig,
+		},
+	}
+	s.host = host
+	s.tag = tag
+	s.attrs = extraAttributes(s.Opts, info.Labels, env)
+	s.rawPrefix = splunkRawPrefix(tag, s.attrs)
+	s.postFrequency = splunkEnvDuration(splunkEnvPostMessagesFrequency, splunkDefaultPostMessagesFrequency)
+	s.batchSize = splunkEnvInt(splunkEnvPostMessagesBatchSize, splunkDefaultPostMessagesBatchSize)
+	s.bufferMaximum = splunkEnvInt(splunkEnvBufferMaximum, splunkDefaultBufferMaximum)
+
+	if cfg.verifyConnection {
+		if err := s.verifyConnection(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.messages = make(chan *splunkMessage, splunkEnvInt(splunkEnvChannelSize, splunkDefaultChannelSize))
+	s.done = make(chan struct{})
+	go s.send()
+	return nil
+}
+
+func splunkEnvInt(key string, defaultValue int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		log.L.Warnf("ignoring invalid value of %s: %q", key, v)
+	}
+	return defaultValue
+}
+
+func splunkEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		log.L.Warnf("ignoring invalid value of %s: %q", key, v)
+	}
+	return defaultValue
+}
+
+// splunkRawPrefix returns the prefix of the events in the raw format, i.e., the tag and the key=value attributes.
+func splunkRawPrefix(tag string, attrs map[string]string) string {
+	var prefix []string
+	if tag != "" {
+		prefix = append(prefix, tag)
+	}
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		prefix = append(prefix, k+"="+attrs[k])
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return strings.Join(prefix, " ") + " "
+}
+
+func (s *SplunkLogger) newMessage(line, source string, t time.Time) *splunkMessage {
+	msg := &splunkMessage{
+		Time:       fmt.Sprintf("%f", float64(t.UnixNano())/float64(time.Second)),
+		Host:       s.host,
+		Source:     s.cfg.source,
+		SourceType: s.cfg.sourceType,
+		Index:      s.cfg.index,
+	}
+	switch s.cfg.format {
+	case splunkFormatRaw:
+		msg.Event = s.rawPrefix + line
+	case splunkFormatJSON:
+		event := &splunkMessageEvent{Line: line, Source: source, Tag: s.tag, Attrs: s.attrs}
+		if json.Valid([]byte(line)) {
+			event.Line = json.RawMessage(line)
+		}
+		msg.Event = event
+	default:
+		msg.Event = &splunkMessageEvent{Line: line, Source: source, Tag: s.tag, Attrs: s.attrs}
+	}
+	return msg
+}
+
+func (s *SplunkLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string, source string) {
+		defer wg.Done()
+		for line := range dataChan {
+			s.messages <- s.newMessage(strings.TrimSuffix(line, "\n"), source, time.Now())
+		}
+	}
+	go fn(stdout, "stdout")
+	go fn(stderr, "stderr")
+	wg.Wait()
+	return nil
+}
+
+func (s *SplunkLogger) PostProcess() error {
+	close(s.messages)
+	<-s.done
+	return nil
+}
+
+// send posts the messages in batches of batchSize, at least every postFrequency.
+// The messages that failed to be posted are retried with the next batch,
+// and the oldest ones are dropped when there are more than bufferMaximum.
+func (s *SplunkLogger) send() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.postFrequency)
+	defer ticker.Stop()
+	var messages []*splunkMessage
+	for {
+		select {
+		case msg, ok := <-s.messages:
+			if !ok {
+				messages = s.postMessages(messages, true)
+				if len(messages) > 0 {
+					log.L.Errorf("dropping %d messages that could not be posted to splunk", len(messages))
+				}
+				return
+			}
+			messages = append(messages, msg)
+			if len(messages)%s.batchSize == 0 {
+				messages = s.postMessages(messages, false)
+			}
+		case <-ticker.C:
+			messages = s.postMessages(messages, true)
+		}
+	}
+}
+
+// postMessages posts the messages in batches, and returns the ones that failed to be posted.
+// Unless all is set, an incomplete last batch is kept for later.
+func (s *SplunkLogger) postMessages(messages []*splunkMessage, all bool) []*splunkMessage {
+	for len(messages) > 0 {
+		n := min(s.batchSize, len(messages))
+		if !all && n < s.batchSize {
+			break
+		}
+		if err := s.post(messages[:n]); err != nil {
+			log.L.WithError(err).Error("failed to post messages to splunk")
+			break
+		}
+		messages = messages[n:]
+	}
+	if len(messages) > s.bufferMaximum {
+		log.L.Errorf("dropping %d messages, the splunk buffer is full", len(messages)-s.bufferMaximum)
+		messages = messages[len(messages)-s.bufferMaximum:]
+	}
+	return messages
+}
+
+func (s *SplunkLogger) post(messages []*splunkMessage) error {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gzipWriter *gzip.Writer
+	if s.cfg.gzip {
+		var err error
+		if gzipWriter, err = gzip.NewWriterLevel(&buf, s.cfg.gzipLevel); err != nil {
+			return err
+		}
+		w = gzipWriter
+	}
+	enc := json.NewEncoder(w)
+	for _, msg := range messages {
+		if err := enc.Encode(msg); err != nil {
+			return err
+		}
+	}
+	if gzipWriter != nil {
+		if err := gzipWriter.Close(); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.cfg.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.cfg.token)
+	if s.cfg.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	return s.do(req)
+}
+
+func (s *SplunkLogger) verifyConnection(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, s.cfg.url, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.do(req); err != nil {
+		return fmt.Errorf("failed to verify the connection to splunk (set log opt %s=false to skip): %w", splunkVerifyConnection, err)
+	}
+	return nil
+}
+
+func (s *SplunkLogger) do(req *http.Request) error {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected status %s: %s", req.Method, s.cfg.url, res.Status, body)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSplunkConfig(t *testing.T) {
+	cfg, err := parseSplunkConfig(map[string]string{
+		splunkURL:        "https://splunk.example.com:8088",
+		splunkToken:      "token",
+		splunkIndex:      "main",
+		splunkFormat:     "json",
+		splunkGzipLevel:  "9",
+		splunkCAName:     "splunk",
+		splunkSourceType: "docker",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.url, "https://splunk.example.com:8088/services/collector/event/1.0")
+	assert.Equal(t, cfg.index, "main")
+	assert.Equal(t, cfg.sourceType, "docker")
+	assert.Equal(t, cfg.format, splunkFormatJSON)
+	assert.Equal(t, cfg.gzipLevel, 9)
+	assert.Equal(t, cfg.tlsConfig.ServerName, "splunk")
+	assert.Assert(t, cfg.verifyConnection)
+
+	for _, opts := range []map[string]string{
+		{splunkToken: "token"},
+		{splunkURL: "https://splunk.example.com:8088"},
+		{splunkURL: "https://splunk.example.com:8088/foo", splunkToken: "token"},
+		{splunkURL: "ftp://splunk.example.com", splunkToken: "token"},
+		{splunkURL: "https://splunk.example.com:8088", splunkToken: "token", splunkFormat: "xml"},
+		{splunkURL: "https://splunk.example.com:8088", splunkToken: "token", splunkGzipCompression: "yes"},
+		{splunkURL: "https://splunk.example.com:8088", splunkToken: "token", splunkCAPath: "/nonexistent"},
+	} {
+		_, err := parseSplunkConfig(opts)
+		assert.Assert(t, err != nil, "expected an error for %v", opts)
+	}
+}
+
+func TestSplunkNewMessage(t *testing.T) {
+	s := &SplunkLogger{
+		cfg:   &splunkConfig{format: splunkFormatInline, index: "main"},
+		host:  "host",
+		tag:   "tag",
+		attrs: map[string]string{"b": "2", "a": "1"},
+	}
+	s.rawPrefix = splunkRawPrefix(s.tag, s.attrs)
+	tm := time.Unix(1700000000, 500000000)
+
+	marshal := func(msg *splunkMessage) string {
+		b, err := json.Marshal(msg)
+		assert.NilError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, marshal(s.newMessage(`{"foo":1}`, "stdout", tm)),
+		`{"event":{"line":"{\"foo\":1}","source":"stdout","tag":"tag","attrs":{"a":"1","b":"2"}},"time":"1700000000.500000","host":"host","index":"main"}`)
+
+	s.cfg.format = splunkFormatJSON
+	assert.Equal(t, marshal(s.newMessage(`{"foo":1}`, "stdout", tm)),
+		`{"event":{"line":{"foo":1},"source":"stdout","tag":"tag","attrs":{"a":"1","b":"2"}},"time":"1700000000.500000","host":"host","index":"main"}`)
+	assert.Equal(t, marshal(s.newMessage(`foo`, "stderr", tm)),
+		`{"event":{"line":"foo","source":"stderr","tag":"tag","attrs":{"a":"1","b":"2"}},"time":"1700000000.500000","host":"host","index":"main"}`)
+
+	s.cfg.format = splunkFormatRaw
+	assert.Equal(t, marshal(s.newMessage(`foo`, "stdout", tm)),
+		`{"event":"tag a=1 b=2 foo","time":"1700000000.500000","host":"host","index":"main"}`)
+}
+
+func TestSplunkPostMessages(t *testing.T) {
+	var failing atomic.Bool
+	received := make(chan []splunkMessage, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodOptions {
+			return
+		}
+		if r.Header.Get("Authorization") != "Splunk token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		var messages []splunkMessage
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			var msg splunkMessage
+			if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			messages = append(messages, msg)
+		}
+		received <- messages
+	}))
+	defer server.Close()
+
+	cfg, err := parseSplunkConfig(map[string]string{
+		splunkURL:             server.URL,
+		splunkToken:           "token",
+		splunkFormat:          splunkFormatRaw,
+		splunkGzipCompression: "true",
+	})
+	assert.NilError(t, err)
+	s := &SplunkLogger{cfg: cfg, client: server.Client(), batchSize: 2, bufferMaximum: 3}
+	assert.NilError(t, s.verifyConnection(t.Context()))
+
+	var messages []*splunkMessage
+	for _, line := range []string{"a", "b", "c"} {
+		messages = append(messages, s.newMessage(line, "stdout", time.Now()))
+	}
+
+	// an incomplete batch is kept unless all is set
+	assert.Equal(t, len(s.postMessages(messages, false)), 1)
+	assert.Equal(t, len(<-received), 2)
+	assert.Equal(t, len(s.postMessages(messages, true)), 0)
+	assert.Equal(t, len(<-received), 2)
+	batch := <-received
+	assert.Equal(t, len(batch), 1)
+	assert.Equal(t, batch[0].Event, "c")
+
+	// the messages are kept on failure, up to bufferMaximum
+	failing.Store(true)
+	messages = append(messages, s.newMessage("d", "stdout", time.Now()))
+	remaining := s.postMessages(messages, true)
+	assert.Equal(t, len(remaining), 3)
+	assert.Equal(t, remaining[0].Event, "b")
+}
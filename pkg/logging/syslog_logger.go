@@ -111,12 +111,13 @@ func SyslogOptsValidate(logOptMap map[string]string) error {
 			return tlsErr
 		}
 	}
-	return nil
+	return validateLogTag(logOptMap)
 }
 
 type SyslogLogger struct {
-	Opts   map[string]string
-	logger *syslog.Writer
+	Opts    map[string]string
+	Address string
+	logger  *syslog.Writer
 }
 
 func (sy *SyslogLogger) Init(dataStore string, ns string, id string) error {
@@ -124,7 +125,17 @@ func (sy *SyslogLogger) Init(dataStore string, ns string, id string) error {
 }
 
 func (sy *SyslogLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
-	logger, err := parseSyslog(config.ID, sy.Opts)
+	tag := config.ID[:12]
+	if cfgTag, ok := sy.Opts[Tag]; ok {
+		idn, err := loadIdentifier(ctx, sy.Address, config)
+		if err != nil {
+			return err
+		}
+		if tag, err = executeLogTag(cfgTag, idn); err != nil {
+			return err
+		}
+	}
+	logger, err := parseSyslog(tag, sy.Opts)
 	if err != nil {
 		return err
 	}
@@ -152,11 +163,7 @@ func (sy *SyslogLogger) PostProcess() error {
 	return nil
 }
 
-func parseSyslog(containerID string, config map[string]string) (*syslog.Writer, error) {
-	tag := containerID[:12]
-	if cfgTag, ok := config[Tag]; ok {
-		tag = cfgTag
-	}
+func parseSyslog(tag string, config map[string]string) (*syslog.Writer, error) {
 	proto, address, err := parseSyslogAddress(config[syslogAddress])
 	if err != nil {
 		return nil, err
@@ -123,6 +123,8 @@ func buildResolverOptions(globalOptions types.GlobalCommandOptions, insecure boo
 		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
 	}
 	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(globalOptions.HostsDir))
+	dOpts = append(dOpts, dockerconfigresolver.WithRegistries(globalOptions.Registries))
+	dOpts = append(dOpts, dockerconfigresolver.WithTokenCache(!globalOptions.NoTokenCache))
 
 	return dOpts
 }
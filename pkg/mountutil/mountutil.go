@@ -21,8 +21,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/moby/sys/userns"
 	"github.com/opencontainers/runtime-spec/specs-go"
 
@@ -31,6 +33,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -50,6 +53,52 @@ type Processed struct {
 	AnonymousVolume string // anonymous volume name
 	Mode            string
 	Opts            []oci.SpecOpts
+	// Driver is the volume plugin of the volume, empty for the local driver.
+	// The source of the mount is empty, as the volume is only mounted by the plugin when the container is started.
+	Driver string
+	// Subpath is the path inside the volume to mount instead of the whole volume (`volume-subpath` of `--mount`)
+	Subpath string
+	// NoCopy disables copying the contents of the image directory into the volume when it is empty
+	// (`nocopy` of `-v`, `volume-nocopy` of `--mount`)
+	NoCopy bool
+	// Chown recursively chowns the source of the mount to the user of the container, on the first use of the mount
+	// (`U` of `-v`, `chown` of `--mount`)
+	Chown bool
+	// IDMap idmaps the mount with the user namespace of the container (`idmap` of `-v` and `--mount`)
+	IDMap bool
+	// Relabel is the SELinux relabeling of the source of the mount, RelabelShared or RelabelPrivate (`z` or `Z` of `-v`).
+	// Empty for no relabeling.
+	Relabel string
+}
+
+const (
+	// volumeOptNoCopy is the `-v` option disabling the copy of the contents of the image directory into the volume.
+	volumeOptNoCopy = "nocopy"
+	// volumeOptChown is the `-v` option chowning the source of the mount to the user of the container.
+	volumeOptChown = "U"
+	// volumeOptIDMap is the `-v` option idmapping the mount with the user namespace of the container.
+	volumeOptIDMap = "idmap"
+
+	// RelabelShared relabels the source of the mount with the shared SELinux label of all the containers.
+	RelabelShared = "z"
+	// RelabelPrivate relabels the source of the mount with the private SELinux label of the container.
+	RelabelPrivate = "Z"
+)
+
+// PrivilegedMountTypes are the mount types of `--mount` only allowed for privileged containers,
+// as the masked paths and the read-only paths of the spec only apply to the default mounts of /proc and /sys.
+var PrivilegedMountTypes = []string{"proc", "sysfs"}
+
+// cutVolumeOption removes the option from the comma-separated volume options.
+func cutVolumeOption(rawOpts, option string) (string, bool) {
+	opts := strings.Split(rawOpts, ",")
+	if !slices.Contains(opts, option) {
+		return rawOpts, false
+	}
+	opts = slices.DeleteFunc(opts, func(opt string) bool {
+		return opt == option
+	})
+	return strings.Join(opts, ","), true
 }
 
 type volumeSpec struct {
@@ -57,6 +106,9 @@ type volumeSpec struct {
 	Name            string
 	Source          string
 	AnonymousVolume string
+	Driver          string
+	// OptionsMount is the filesystem mounted for a local volume created with the type option
+	OptionsMount *specs.Mount
 }
 
 func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*Processed, error) {
@@ -111,6 +163,7 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 			Type:            volSpec.Type,
 			Name:            volSpec.Name,
 			AnonymousVolume: volSpec.AnonymousVolume,
+			Driver:          volSpec.Driver,
 		}
 
 		// Parse volume options
@@ -118,6 +171,26 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 			res.Mode = split[2]
 
 			rawOpts := res.Mode
+			rawOpts, res.NoCopy = cutVolumeOption(rawOpts, volumeOptNoCopy)
+			if res.NoCopy && res.Type != Volume {
+				return nil, fmt.Errorf("volume option %q is only supported for volumes", volumeOptNoCopy)
+			}
+			rawOpts, res.Chown = cutVolumeOption(rawOpts, volumeOptChown)
+			rawOpts, res.IDMap = cutVolumeOption(rawOpts, volumeOptIDMap)
+			if res.Chown && res.IDMap {
+				return nil, fmt.Errorf("volume options %q and %q cannot be used together", volumeOptChown, volumeOptIDMap)
+			}
+			var relabelShared, relabelPrivate bool
+			rawOpts, relabelShared = cutVolumeOption(rawOpts, RelabelShared)
+			rawOpts, relabelPrivate = cutVolumeOption(rawOpts, RelabelPrivate)
+			switch {
+			case relabelShared && relabelPrivate:
+				return nil, fmt.Errorf("volume options %q and %q cannot be used together", RelabelShared, RelabelPrivate)
+			case relabelShared:
+				res.Relabel = RelabelShared
+			case relabelPrivate:
+				res.Relabel = RelabelPrivate
+			}
 
 			options, res.Opts, err = getVolumeOptions(src, res.Type, rawOpts)
 			if err != nil {
@@ -151,7 +224,21 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 		Destination: cleanMount(dst),
 		Options:     options,
 	}
-	if userns.RunningInUserNS() {
+	if m := volSpec.OptionsMount; m != nil {
+		// the filesystem is mounted by the runtime on each start of the container, like with dockerd
+		res.Mount = specs.Mount{
+			Type:        m.Type,
+			Source:      m.Source,
+			Destination: cleanMount(dst),
+			Options:     slices.Clone(m.Options),
+		}
+		if slices.Contains(options, "ro") {
+			res.Mount.Options = append(res.Mount.Options, "ro")
+		}
+		return res, nil
+	}
+	// plugin volumes have no source yet, they are bind-mounted on a private mount point when the container is started
+	if userns.RunningInUserNS() && res.Driver == "" {
 		unpriv, err := UnprivilegedMountFlags(src)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get unprivileged mount flags for %q: %w", src, err)
@@ -189,7 +276,7 @@ func handleAnonymousVolumes(s string, volStore volumestore.VolumeStore) (volumeS
 	res.AnonymousVolume = idgen.GenerateID()
 
 	log.L.Debugf("creating anonymous volume %q, for %q", res.AnonymousVolume, s)
-	anonVol, err := volStore.CreateWithoutLock(res.AnonymousVolume, []string{})
+	anonVol, err := volStore.CreateWithoutLock(res.AnonymousVolume, []string{labels.AnonymousVolumes + "="})
 	if err != nil {
 		return res, fmt.Errorf("failed to create an anonymous volume %q: %w", res.AnonymousVolume, err)
 	}
@@ -208,9 +295,15 @@ func handleNamedVolumes(source string, volStore volumestore.VolumeStore) (volume
 	if err != nil {
 		return res, fmt.Errorf("failed to get volume %q: %w", res.Name, err)
 	}
-	// src is now an absolute path
+	// src is now an absolute path, or empty for plugin volumes
 	res.Type = Volume
 	res.Source = vol.Mountpoint
+	res.Driver = vol.Driver
+	if vol.Options != nil {
+		if res.OptionsMount, err = volumeOptionsMount(*vol.Options); err != nil {
+			return res, fmt.Errorf("invalid options of volume %q: %w", res.Name, err)
+		}
+	}
 
 	return res, nil
 }
@@ -265,3 +358,20 @@ func isNamedVolume(s string) bool {
 	// If the volume name is invalid, we assume it is a path
 	return err == nil
 }
+
+// ResolveVolumeSubpath returns the path of subpath inside the volume mounted at root.
+// Symlinks are resolved inside root, so that a volume shared with other containers cannot make the mount escape it.
+// The subpath must exist.
+func ResolveVolumeSubpath(root, subpath string) (string, error) {
+	if filepath.IsAbs(subpath) || !filepath.IsLocal(filepath.Clean(subpath)) {
+		return "", fmt.Errorf("invalid volume subpath %q: must be a relative path inside the volume", subpath)
+	}
+	p, err := securejoin.SecureJoin(root, subpath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(p); err != nil {
+		return "", fmt.Errorf("cannot access the volume subpath %q: %w", subpath, err)
+	}
+	return p, nil
+}
@@ -18,8 +18,13 @@ package mountutil
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/docker/go-units"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
@@ -64,10 +69,191 @@ func parseVolumeOptions(vType, src, optsRaw string) ([]string, []oci.SpecOpts, e
 	return opts, nil, nil
 }
 
+// ProcessFlagTmpfs parses the --tmpfs flag, e.g., `--tmpfs /tmp:size=64m,mode=1777`.
+// The options are the mount options of tmpfs(5) (`nosuid,noexec` by default).
 func ProcessFlagTmpfs(s string) (*Processed, error) {
-	return nil, errdefs.ErrNotImplemented
+	dst, optsRaw, _ := strings.Cut(s, ":")
+	if !filepath.IsAbs(dst) {
+		return nil, fmt.Errorf("invalid mount path for tmpfs: %q must be an absolute path", dst)
+	}
+	options := []string{"nosuid", "noexec"}
+	if optsRaw != "" {
+		extra, err := parseTmpfsOptions(strings.Split(optsRaw, ","))
+		if err != nil {
+			return nil, err
+		}
+		options = mergeTmpfsOptions(options, extra)
+	}
+	res := &Processed{
+		Mount: specs.Mount{
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Destination: dst,
+			Options:     options,
+		},
+		Type: Tmpfs,
+		Mode: strings.Join(options, ","),
+	}
+	return res, nil
+}
+
+// parseTmpfsOptions validates the tmpfs options, and normalizes the size to bytes,
+// as tmpfs(5) does not accept all the units of docker (e.g. "1.5g" or "64MB").
+func parseTmpfsOptions(raw []string) ([]string, error) {
+	var options []string
+	for _, opt := range raw {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			switch opt {
+			case "rw", "ro", "exec", "noexec", "suid", "nosuid", "noatime":
+				options = append(options, opt)
+			case "":
+				// NOP
+			default:
+				return nil, fmt.Errorf("unsupported tmpfs option %q", opt)
+			}
+			continue
+		}
+		var err error
+		switch key {
+		case "size":
+			var size int64
+			if size, err = units.RAMInBytes(value); err == nil {
+				opt = fmt.Sprintf("size=%d", size)
+			}
+		case "mode":
+			var mode uint64
+			if mode, err = strconv.ParseUint(value, 8, 32); err == nil && mode > 0o7777 {
+				err = fmt.Errorf("mode %o is out of range", mode)
+			}
+		case "uid", "gid", "inodes":
+			_, err = strconv.ParseUint(value, 10, 32)
+		default:
+			return nil, fmt.Errorf("unsupported tmpfs option %q", opt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tmpfs option %q: %w", opt, err)
+		}
+		options = append(options, opt)
+	}
+	return options, nil
+}
+
+// mergeTmpfsOptions appends the extra options to the default options,
+// dropping the default options overridden by the extra ones (e.g. "exec" overrides "noexec").
+func mergeTmpfsOptions(defaults, extra []string) []string {
+	overridden := make(map[string]bool)
+	for _, opt := range extra {
+		overridden[opt] = true
+		overridden["no"+opt] = true
+		overridden[strings.TrimPrefix(opt, "no")] = true
+	}
+	var options []string
+	for _, opt := range defaults {
+		if !overridden[opt] {
+			options = append(options, opt)
+		}
+	}
+	return append(options, extra...)
 }
 
+// ProcessFlagMount parses the --mount flag. Only the bind, volume, and tmpfs types are supported on FreeBSD:
+//
+//	--mount type=bind,source=/src,target=/app,readonly
+//	--mount type=volume,source=vol-1,target=/app
+//	--mount type=tmpfs,target=/app,tmpfs-size=64m,tmpfs-mode=1777
+//
+// If the type is not specified, it defaults to volume. The bind mounts are nullfs(5) mounts.
 func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
-	return nil, errdefs.ErrNotImplemented
+	var (
+		mountType    = Volume
+		src          string
+		dst          string
+		readonly     bool
+		tmpfsOptions []string
+		err          error
+	)
+	for _, field := range strings.Split(s, ",") {
+		key, value, hasValue := strings.Cut(field, "=")
+		key = strings.ToLower(key)
+		switch key {
+		case "type":
+			switch value {
+			case Bind, Volume, Tmpfs:
+				mountType = value
+			default:
+				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", value)
+			}
+		case "source", "src":
+			src = value
+		case "target", "dst", "destination":
+			dst = value
+		case "readonly", "ro":
+			readonly = true
+			if hasValue {
+				readonly, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+				}
+			}
+		case "rw":
+			readonly = false
+			if hasValue {
+				var rw bool
+				rw, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+				}
+				readonly = !rw
+			}
+		case "tmpfs-size":
+			tmpfsOptions = append(tmpfsOptions, "size="+value)
+		case "tmpfs-mode":
+			tmpfsOptions = append(tmpfsOptions, "mode="+value)
+		default:
+			if !hasValue {
+				return nil, fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+			}
+			return nil, fmt.Errorf("unexpected key '%s' in '%s': %w", key, field, errdefs.ErrNotImplemented)
+		}
+	}
+	if dst == "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: target must be specified", mountType)
+	}
+	if len(tmpfsOptions) > 0 && mountType != Tmpfs {
+		return nil, fmt.Errorf("invalid mount config for type %q: tmpfs options are only valid for tmpfs", mountType)
+	}
+
+	if mountType == Tmpfs {
+		if src != "" {
+			return nil, fmt.Errorf("invalid mount config for type %q: source must not be specified", mountType)
+		}
+		if readonly {
+			tmpfsOptions = append(tmpfsOptions, "ro")
+		}
+		return ProcessFlagTmpfs(dst + ":" + strings.Join(tmpfsOptions, ","))
+	}
+
+	if src == "" && mountType == Bind {
+		return nil, fmt.Errorf("invalid mount config for type %q: source must be specified", mountType)
+	}
+	if src != "" && isNamedVolume(src) != (mountType == Volume) {
+		return nil, fmt.Errorf("invalid mount config for type %q: source %q is not a %s", mountType, src, mountType)
+	}
+
+	// compose the fields to call ProcessFlagV
+	fields := []string{dst}
+	if src != "" {
+		fields = []string{src, dst}
+	}
+	// createDir=false for --mount option to disallow creating directories on host if not found
+	res, err := ProcessFlagV(strings.Join(fields, ":"), volStore, false)
+	if err != nil {
+		return nil, err
+	}
+	// not passed to ProcessFlagV, as "<DST>:ro" would be parsed as "<SRC>:<DST>"
+	if readonly {
+		res.Mount.Options = append([]string{"ro"}, res.Mount.Options...)
+	}
+	return res, nil
 }
@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProcessFlagTmpfs(t *testing.T) {
+	x, err := ProcessFlagTmpfs("/tmp:size=64m,mode=1777,exec")
+	assert.NilError(t, err)
+	assert.Equal(t, x.Type, Tmpfs)
+	assert.Equal(t, x.Mount.Type, "tmpfs")
+	assert.Equal(t, x.Mount.Destination, "/tmp")
+	assert.DeepEqual(t, x.Mount.Options, []string{"nosuid", "size=67108864", "mode=1777", "exec"})
+
+	_, err = ProcessFlagTmpfs("tmp")
+	assert.ErrorContains(t, err, "must be an absolute path")
+	_, err = ProcessFlagTmpfs("/tmp:mode=17777")
+	assert.ErrorContains(t, err, "out of range")
+	_, err = ProcessFlagTmpfs("/tmp:nr_inodes=10")
+	assert.ErrorContains(t, err, "unsupported tmpfs option")
+}
+
+func TestProcessFlagMount(t *testing.T) {
+	x, err := ProcessFlagMount("type=tmpfs,dst=/app,tmpfs-size=1m,readonly", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Type, Tmpfs)
+	assert.DeepEqual(t, x.Mount.Options, []string{"nosuid", "noexec", "size=1048576", "ro"})
+
+	x, err = ProcessFlagMount("src=TestVolume,dst=/app,ro", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, x.Type, Volume)
+	assert.Equal(t, x.Mount.Type, "nullfs")
+	assert.Equal(t, x.Mount.Destination, "/app")
+	assert.Equal(t, x.Mount.Options[0], "ro")
+
+	for _, s := range []string{
+		"type=bind,dst=/app",
+		"type=bind,src=TestVolume,dst=/app",
+		"type=volume,src=/mnt,dst=/app",
+		"type=volume,src=TestVolume,dst=/app,tmpfs-size=1m",
+		"type=tmpfs,src=/mnt,dst=/app",
+		"type=overlay,dst=/app",
+		"type=bind,src=/mnt,dst=/app,bind-propagation=shared",
+		"src=TestVolume",
+	} {
+		_, err := ProcessFlagMount(s, mockVolumeStore)
+		assert.Assert(t, err != nil, s)
+	}
+}
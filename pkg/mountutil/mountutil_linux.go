@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -53,8 +55,20 @@ const (
 	// where user doesn't specify mount propagation explicitly.
 	// See also: https://github.com/moby/moby/blob/v20.10.7/volume/mounts/linux_parser.go#L145
 	DefaultPropagationMode = "rprivate"
+
+	// Overlay is the mount type of the read-only overlays of host directories
+	Overlay = "overlay"
 )
 
+// specialMountOptions are the default options of the pseudo filesystems accepted as mount types by `--mount`.
+// The mounts of the types in PrivilegedMountTypes bypass the masked paths and the read-only paths of the spec.
+var specialMountOptions = map[string][]string{
+	"devpts": {"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620"},
+	"mqueue": {"nosuid", "noexec", "nodev"},
+	"proc":   {"nosuid", "noexec", "nodev"},
+	"sysfs":  {"nosuid", "noexec", "nodev"},
+}
+
 // UnprivilegedMountFlags is from https://github.com/moby/moby/blob/v20.10.5/daemon/oci_linux.go#L420-L450
 //
 // Get the set of mount flags that are set on the mount that contains the given
@@ -269,6 +283,9 @@ func ensureMountOptionalValue(mi mount.Info, vals ...string) error {
 func ProcessFlagTmpfs(s string) (*Processed, error) {
 	split := strings.SplitN(s, ":", 2)
 	dst := split[0]
+	if !filepath.IsAbs(dst) {
+		return nil, fmt.Errorf("invalid mount path for tmpfs: %q must be an absolute path", dst)
+	}
 	options := []string{"noexec", "nosuid", "nodev"}
 	if len(split) == 2 {
 		raw := append(options, strings.Split(split[1], ",")...)
@@ -277,6 +294,9 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := normalizeTmpfsOptions(options); err != nil {
+			return nil, err
+		}
 	}
 	res := &Processed{
 		Mount: specs.Mount{
@@ -291,6 +311,43 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return res, nil
 }
 
+// normalizeTmpfsOptions validates the tmpfs options taking a value (e.g. "size=64m", "mode=1777", "uid=1000"),
+// and normalizes the size, as the kernel does not accept all the units of docker (e.g. "1.5g" or "64MB").
+func normalizeTmpfsOptions(options []string) error {
+	for i, opt := range options {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch key {
+		case "size":
+			// the size can also be a percentage of the RAM
+			if percent, ok := strings.CutSuffix(value, "%"); ok {
+				_, err = strconv.ParseUint(percent, 10, 32)
+				break
+			}
+			var size int64
+			if size, err = units.RAMInBytes(value); err == nil {
+				options[i] = getTmpfsSize(size)
+			}
+		case "nr_blocks", "nr_inodes":
+			_, err = units.RAMInBytes(value)
+		case "mode":
+			var mode uint64
+			if mode, err = strconv.ParseUint(value, 8, 32); err == nil && mode > 0o7777 {
+				err = fmt.Errorf("mode %o is out of range", mode)
+			}
+		case "uid", "gid":
+			_, err = strconv.ParseUint(value, 10, 32)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tmpfs option %q: %w", opt, err)
+		}
+	}
+	return nil
+}
+
 func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
 	fields := strings.Split(s, ",")
 	var (
@@ -302,6 +359,12 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		rwOption         string
 		tmpfsSize        int64
 		tmpfsMode        os.FileMode
+		tmpfsOption      string
+		volumeSubpath    string
+		volumeNoCopy     bool
+		chown            bool
+		idmap            bool
+		overlayLowerdir  string
 		err              error
 	)
 
@@ -313,6 +376,7 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 	// --mount type=bind,source="$(pwd)"/target,target=/app2,readonly,bind-propagation=shared
 	// --mount type=tmpfs,destination=/app,tmpfs-mode=1770,tmpfs-size=1MB
 	// --mount type=volume,src=vol-1,dst=/app,readonly
+	// --mount type=overlay,lowerdir=/lower1:/lower2,dst=/app
 	// if type not specified, default will be set to volume
 	// --mount src=`pwd`/tmp,target=/app
 
@@ -328,6 +392,15 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			case "bind-nonrecursive":
 				bindNonRecursive = true
 				continue
+			case "volume-nocopy":
+				volumeNoCopy = true
+				continue
+			case "chown":
+				chown = true
+				continue
+			case "idmap":
+				idmap = true
+				continue
 			}
 		}
 
@@ -344,8 +417,13 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			case "bind":
 				mountType = Bind
 			case "volume":
+			case "overlay":
+				mountType = Overlay
 			default:
-				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", value)
+				if _, ok := specialMountOptions[value]; !ok {
+					return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs/overlay/%s", value, strings.Join(specialMountTypes(), "/"))
+				}
+				mountType = value
 			}
 		case "source", "src":
 			src = value
@@ -368,14 +446,35 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			if err != nil {
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
+		case "volume-subpath":
+			volumeSubpath = value
+		case "chown":
+			chown, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		case "idmap":
+			idmap, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		case "lowerdir":
+			overlayLowerdir = value
+		case "volume-nocopy":
+			volumeNoCopy, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
 		case "tmpfs-size":
+			tmpfsOption = key
 			tmpfsSize, err = units.RAMInBytes(value)
 			if err != nil {
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
 		case "tmpfs-mode":
+			tmpfsOption = key
 			ui64, err := strconv.ParseUint(value, 8, 32)
-			if err != nil {
+			if err != nil || ui64 > 0o7777 {
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
 			tmpfsMode = os.FileMode(ui64)
@@ -384,6 +483,39 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		}
 	}
 
+	if mountType != Volume && mountType != Bind && src != "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: source must not be specified", mountType)
+	}
+	if mountType != Tmpfs && tmpfsOption != "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: %s is only supported for tmpfs", mountType, tmpfsOption)
+	}
+	if dst == "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: target must be specified", mountType)
+	}
+	if volumeSubpath != "" && mountType != Volume {
+		return nil, fmt.Errorf("invalid mount config for type %q: volume-subpath is only supported for volumes", mountType)
+	}
+	if volumeNoCopy && mountType != Volume {
+		return nil, fmt.Errorf("invalid mount config for type %q: volume-nocopy is only supported for volumes", mountType)
+	}
+	if chown && mountType != Volume && mountType != Bind {
+		return nil, fmt.Errorf("invalid mount config for type %q: chown is only supported for volumes and binds", mountType)
+	}
+	if idmap && mountType != Volume && mountType != Bind {
+		return nil, fmt.Errorf("invalid mount config for type %q: idmap is only supported for volumes and binds", mountType)
+	}
+	if overlayLowerdir != "" && mountType != Overlay {
+		return nil, fmt.Errorf("invalid mount config for type %q: lowerdir is only supported for overlay", mountType)
+	}
+
+	switch mountType {
+	case Overlay:
+		return processOverlayMount(dst, overlayLowerdir, rwOption)
+	case Volume, Bind, Tmpfs:
+	default:
+		return processSpecialMount(mountType, dst, rwOption)
+	}
+
 	// compose new fileds and join into a string
 	// to call legacy ProcessFlagTmpfs or ProcessFlagV function
 	fields = []string{}
@@ -416,6 +548,15 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 				options = append(options, "rbind")
 			}
 		}
+		if volumeNoCopy {
+			options = append(options, volumeOptNoCopy)
+		}
+		if chown {
+			options = append(options, volumeOptChown)
+		}
+		if idmap {
+			options = append(options, volumeOptIDMap)
+		}
 	}
 
 	if len(options) > 0 {
@@ -431,11 +572,112 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		return ProcessFlagTmpfs(fieldsStr)
 	case Volume, Bind:
 		// createDir=false for --mount option to disallow creating directories on host if not found
-		return ProcessFlagV(fieldsStr, volStore, false)
+		res, err := ProcessFlagV(fieldsStr, volStore, false)
+		if err != nil || volumeSubpath == "" {
+			return res, err
+		}
+		return processVolumeSubpath(res, volumeSubpath)
 	}
 	return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", mountType)
 }
 
+// processOverlayMount returns a read-only overlay of the host directories in lowerdir, separated by colons,
+// the first one being the top layer.
+func processOverlayMount(dst, lowerdir, rwOption string) (*Processed, error) {
+	if lowerdir == "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: lowerdir must be specified", Overlay)
+	}
+	if rwOption == "rw" {
+		return nil, fmt.Errorf("invalid mount config for type %q: overlay mounts are read-only", Overlay)
+	}
+	if !filepath.IsAbs(dst) {
+		return nil, fmt.Errorf("invalid mount path for overlay: %q must be an absolute path", dst)
+	}
+	dirs := strings.Split(lowerdir, ":")
+	for i, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("invalid lowerdir %q: must be an absolute path", dir)
+		}
+		st, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lowerdir %q: %w", dir, err)
+		}
+		if !st.IsDir() {
+			return nil, fmt.Errorf("invalid lowerdir %q: not a directory", dir)
+		}
+		dirs[i] = filepath.Clean(dir)
+	}
+
+	res := &Processed{
+		Type: Overlay,
+		Mount: specs.Mount{
+			Type:        "overlay",
+			Source:      "overlay",
+			Destination: filepath.Clean(dst),
+			Options:     []string{"lowerdir=" + strings.Join(dirs, ":"), "ro"},
+		},
+	}
+	// overlayfs needs at least 2 lower layers without an upper layer
+	if len(dirs) == 1 {
+		res.Mount = specs.Mount{
+			Type:        DefaultMountType,
+			Source:      dirs[0],
+			Destination: filepath.Clean(dst),
+			Options:     []string{"rbind", "ro", DefaultPropagationMode},
+		}
+	}
+	res.Mode = strings.Join(res.Mount.Options, ",")
+	return res, nil
+}
+
+// processSpecialMount returns a mount of the pseudo filesystem fsType, with the default options of specialMountOptions.
+func processSpecialMount(fsType, dst, rwOption string) (*Processed, error) {
+	if !filepath.IsAbs(dst) {
+		return nil, fmt.Errorf("invalid mount path for %s: %q must be an absolute path", fsType, dst)
+	}
+	options := slices.Clone(specialMountOptions[fsType])
+	if rwOption != "" && rwOption != "rw" {
+		options = append(options, "ro")
+	}
+	return &Processed{
+		Type: fsType,
+		Mount: specs.Mount{
+			Type:        fsType,
+			Source:      fsType,
+			Destination: filepath.Clean(dst),
+			Options:     options,
+		},
+		Mode: strings.Join(options, ","),
+	}, nil
+}
+
+func specialMountTypes() []string {
+	types := slices.Collect(maps.Keys(specialMountOptions))
+	slices.Sort(types)
+	return types
+}
+
+// processVolumeSubpath makes the mount of a named volume only mount the subpath inside the volume.
+// The source of the mount remains the volume, as the subpath is resolved again when the container is started (see VolumeSubpath).
+// The subpath of plugin volumes is resolved once the volume is mounted by the plugin.
+func processVolumeSubpath(res *Processed, subpath string) (*Processed, error) {
+	if res.Type != Volume || res.AnonymousVolume != "" {
+		return nil, fmt.Errorf("volume-subpath is only supported for named volumes")
+	}
+	res.Subpath = subpath
+	if res.Driver != "" {
+		return res, nil
+	}
+	if res.Mount.Type != "bind" && res.Mount.Type != DefaultMountType {
+		// the filesystem of a volume created with the type option is mounted by the runtime
+		return nil, fmt.Errorf("volume-subpath is not supported for volume %q mounting a %s filesystem", res.Name, res.Mount.Type)
+	}
+	if _, err := ResolveVolumeSubpath(res.Mount.Source, subpath); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // copy from https://github.com/moby/moby/blob/085c6a98d54720e70b28354ccec6da9b1b9e7fcf/volume/mounts/linux_parser.go#L375
 func getTmpfsSize(size int64) string {
 	// calculate suffix here, making this linux specific, but that is
@@ -18,10 +18,15 @@ package mountutil
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 
@@ -200,12 +205,208 @@ func TestProcessTmpfs(t *testing.T) {
 	testCases := map[string][]string{
 		"/tmp":               {"noexec", "nosuid", "nodev"},
 		"/tmp:size=64m,exec": {"nosuid", "nodev", "size=64m", "exec"},
+		"/run:rw,noexec,nosuid,size=64m,mode=1777,uid=1000,gid=1000": {"nodev", "rw", "noexec", "nosuid", "size=64m", "mode=1777", "uid=1000", "gid=1000"},
+		"/tmp:size=1.5g": {"noexec", "nosuid", "nodev", "size=1536m"},
+		"/tmp:size=50%":  {"noexec", "nosuid", "nodev", "size=50%"},
 	}
 	for k, expected := range testCases {
 		x, err := ProcessFlagTmpfs(k)
 		assert.NilError(t, err)
 		assert.DeepEqual(t, expected, x.Mount.Options)
 	}
+
+	for _, invalid := range []string{
+		"tmp",
+		"/tmp:size=foo",
+		"/tmp:size=-1",
+		"/tmp:mode=888",
+		"/tmp:mode=17777",
+		"/tmp:uid=-1",
+		"/tmp:gid=foo",
+	} {
+		_, err := ProcessFlagTmpfs(invalid)
+		assert.ErrorContains(t, err, "", invalid)
+	}
+}
+
+func TestProcessFlagMountTmpfs(t *testing.T) {
+	testCases := map[string][]string{
+		"type=tmpfs,target=/tmp":                                {"noexec", "nosuid", "nodev", "mode=1777"},
+		"type=tmpfs,target=/tmp,tmpfs-size=64MB,tmpfs-mode=700": {"noexec", "nosuid", "nodev", "mode=700", "size=64m"},
+		"type=tmpfs,target=/tmp,readonly":                       {"noexec", "nosuid", "nodev", "ro", "mode=1777"},
+	}
+	for k, expected := range testCases {
+		x, err := ProcessFlagMount(k, nil)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, expected, x.Mount.Options)
+	}
+
+	for _, invalid := range []string{
+		"type=tmpfs",
+		"type=tmpfs,source=/foo,target=/tmp",
+		"type=tmpfs,target=/tmp,tmpfs-mode=17777",
+		"type=tmpfs,target=/tmp,tmpfs-size=foo",
+		"type=bind,source=/foo,target=/tmp,tmpfs-size=64m",
+		"type=tmpfs,target=/tmp,volume-subpath=foo",
+	} {
+		_, err := ProcessFlagMount(invalid, nil)
+		assert.ErrorContains(t, err, "", invalid)
+	}
+}
+
+func TestProcessFlagMountNoCopy(t *testing.T) {
+	testCases := map[string]bool{
+		"type=volume,src=TestVolume,dst=/mnt/foo":                      false,
+		"type=volume,src=TestVolume,dst=/mnt/foo,volume-nocopy":        true,
+		"type=volume,src=TestVolume,dst=/mnt/foo,volume-nocopy=true":   true,
+		"type=volume,src=TestVolume,dst=/mnt/foo,volume-nocopy=false":  false,
+		"type=volume,src=TestVolume,dst=/mnt/foo,volume-nocopy,ro":     true,
+		"type=volume,src=TestVolume,dst=/mnt/foo,ro,volume-nocopy=yes": false,
+	}
+	for k, expected := range testCases {
+		x, err := ProcessFlagMount(k, mockVolumeStore)
+		if strings.HasSuffix(k, "=yes") {
+			assert.ErrorContains(t, err, "invalid value for volume-nocopy", k)
+			continue
+		}
+		assert.NilError(t, err, k)
+		assert.Equal(t, expected, x.NoCopy, k)
+		assert.Assert(t, !slices.Contains(x.Mount.Options, "nocopy"), k)
+	}
+
+	_, err := ProcessFlagMount("type=bind,src=/mnt/foo,dst=/mnt/foo,volume-nocopy", mockVolumeStore)
+	assert.ErrorContains(t, err, "volume-nocopy is only supported for volumes")
+
+	x, err := ProcessFlagV("TestVolume:/mnt/foo:nocopy,ro", mockVolumeStore, false)
+	assert.NilError(t, err)
+	assert.Assert(t, x.NoCopy)
+	assert.DeepEqual(t, x.Mount.Options, []string{"ro", "rbind"})
+
+	_, err = ProcessFlagV("/mnt/foo:/mnt/foo:nocopy", mockVolumeStore, false)
+	assert.ErrorContains(t, err, "only supported for volumes")
+}
+
+func TestProcessFlagMountChown(t *testing.T) {
+	testCases := map[string]bool{
+		"type=volume,src=TestVolume,dst=/mnt/foo":             false,
+		"type=volume,src=TestVolume,dst=/mnt/foo,chown":       true,
+		"type=volume,src=TestVolume,dst=/mnt/foo,chown=true":  true,
+		"type=volume,src=TestVolume,dst=/mnt/foo,chown=false": false,
+	}
+	for k, expected := range testCases {
+		x, err := ProcessFlagMount(k, mockVolumeStore)
+		assert.NilError(t, err, k)
+		assert.Equal(t, expected, x.Chown, k)
+		assert.Assert(t, !slices.Contains(x.Mount.Options, "U"), k)
+	}
+
+	_, err := ProcessFlagMount("type=tmpfs,dst=/mnt/foo,chown", mockVolumeStore)
+	assert.ErrorContains(t, err, "chown is only supported for volumes and binds")
+
+	x, err := ProcessFlagV("TestVolume:/mnt/foo:ro,U", mockVolumeStore, false)
+	assert.NilError(t, err)
+	assert.Assert(t, x.Chown)
+	assert.DeepEqual(t, x.Mount.Options, []string{"ro", "rbind"})
+}
+
+func TestProcessFlagIDMap(t *testing.T) {
+	x, err := ProcessFlagV("TestVolume:/mnt/foo:ro,idmap", mockVolumeStore, false)
+	assert.NilError(t, err)
+	assert.Assert(t, x.IDMap)
+	assert.DeepEqual(t, x.Mount.Options, []string{"ro", "rbind"})
+
+	_, err = ProcessFlagV("TestVolume:/mnt/foo:U,idmap", mockVolumeStore, false)
+	assert.ErrorContains(t, err, "cannot be used together")
+
+	x, err = ProcessFlagMount("type=volume,src=TestVolume,dst=/mnt/foo,idmap", mockVolumeStore)
+	assert.NilError(t, err)
+	assert.Assert(t, x.IDMap)
+
+	_, err = ProcessFlagMount("type=tmpfs,dst=/mnt/foo,idmap", mockVolumeStore)
+	assert.ErrorContains(t, err, "idmap is only supported for volumes and binds")
+}
+
+func TestProcessFlagVRelabel(t *testing.T) {
+	testCases := map[string]string{
+		"TestVolume:/mnt/foo":      "",
+		"TestVolume:/mnt/foo:z":    RelabelShared,
+		"TestVolume:/mnt/foo:ro,Z": RelabelPrivate,
+		"/mnt/foo:/mnt/foo:z,ro":   RelabelShared,
+	}
+	for k, expected := range testCases {
+		x, err := ProcessFlagV(k, mockVolumeStore, false)
+		assert.NilError(t, err, k)
+		assert.Equal(t, expected, x.Relabel, k)
+		assert.Assert(t, !slices.Contains(x.Mount.Options, "z") && !slices.Contains(x.Mount.Options, "Z"), k)
+	}
+
+	_, err := ProcessFlagV("TestVolume:/mnt/foo:z,Z", mockVolumeStore, false)
+	assert.ErrorContains(t, err, "cannot be used together")
+}
+
+func TestProcessFlagMountOverlay(t *testing.T) {
+	lower1, lower2 := t.TempDir(), t.TempDir()
+	file := filepath.Join(lower1, "file")
+	assert.NilError(t, os.WriteFile(file, nil, 0o644))
+
+	x, err := ProcessFlagMount("type=overlay,lowerdir="+lower1+":"+lower2+",target=/mnt", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x.Mount, specs.Mount{
+		Type:        "overlay",
+		Source:      "overlay",
+		Destination: "/mnt",
+		Options:     []string{"lowerdir=" + lower1 + ":" + lower2, "ro"},
+	})
+	assert.Equal(t, x.Type, Overlay)
+
+	// a single lower layer is bind-mounted read-only
+	x, err = ProcessFlagMount("type=overlay,lowerdir="+lower1+",target=/mnt,readonly", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x.Mount, specs.Mount{
+		Type:        "none",
+		Source:      lower1,
+		Destination: "/mnt",
+		Options:     []string{"rbind", "ro", "rprivate"},
+	})
+
+	for _, invalid := range []string{
+		"type=overlay,target=/mnt",
+		"type=overlay,lowerdir=" + lower1 + ":" + lower2 + ",target=/mnt,rw",
+		"type=overlay,lowerdir=" + lower1 + ":relative,target=/mnt",
+		"type=overlay,lowerdir=" + lower1 + ":" + filepath.Join(lower2, "nonexistent") + ",target=/mnt",
+		"type=overlay,lowerdir=" + lower1 + ":" + file + ",target=/mnt",
+		"type=overlay,source=" + lower1 + ",target=/mnt",
+		"type=bind,source=" + lower1 + ",lowerdir=" + lower2 + ",target=/mnt",
+	} {
+		_, err := ProcessFlagMount(invalid, nil)
+		assert.ErrorContains(t, err, "", invalid)
+	}
+}
+
+func TestProcessFlagMountSpecial(t *testing.T) {
+	x, err := ProcessFlagMount("type=mqueue,target=/dev/mqueue", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x.Mount, specs.Mount{
+		Type:        "mqueue",
+		Source:      "mqueue",
+		Destination: "/dev/mqueue",
+		Options:     []string{"nosuid", "noexec", "nodev"},
+	})
+
+	x, err = ProcessFlagMount("type=sysfs,target=/sys,readonly", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, x.Mount.Options, []string{"nosuid", "noexec", "nodev", "ro"})
+
+	for _, invalid := range []string{
+		"type=ext4,target=/mnt",
+		"type=devtmpfs,target=/dev",
+		"type=proc,source=proc,target=/proc",
+		"type=proc,target=proc",
+		"type=mqueue,target=/dev/mqueue,tmpfs-size=1m",
+	} {
+		_, err := ProcessFlagMount(invalid, nil)
+		assert.ErrorContains(t, err, "", invalid)
+	}
 }
 
 func TestProcessFlagV(t *testing.T) {
@@ -348,3 +549,74 @@ func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveVolumeSubpath(t *testing.T) {
+	root := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "app1", "data"), 0o755))
+	assert.NilError(t, os.Symlink("/app1", filepath.Join(root, "abs")))
+	assert.NilError(t, os.Symlink("../../..", filepath.Join(root, "app1", "up")))
+	assert.NilError(t, os.Symlink("/etc", filepath.Join(root, "etc")))
+
+	for subpath, expected := range map[string]string{
+		"app1":      filepath.Join(root, "app1"),
+		"app1/data": filepath.Join(root, "app1", "data"),
+		// symlinks are resolved inside the volume
+		"abs/data":     filepath.Join(root, "app1", "data"),
+		"app1/up/app1": filepath.Join(root, "app1"),
+	} {
+		p, err := ResolveVolumeSubpath(root, subpath)
+		assert.NilError(t, err, subpath)
+		assert.Equal(t, expected, p, subpath)
+	}
+
+	for _, subpath := range []string{
+		"/app1",
+		"../app1",
+		"app1/../..",
+		"nonexistent",
+		// resolved to <root>/etc, which does not exist
+		"etc",
+	} {
+		_, err := ResolveVolumeSubpath(root, subpath)
+		assert.ErrorContains(t, err, "", subpath)
+	}
+}
+
+func TestMountVolumeSubpaths(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	root, stateDir := t.TempDir(), t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "app1"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(root, "app1", "foo"), []byte("foo"), 0o644))
+	target := filepath.Join(t.TempDir(), "subpaths", "0")
+	// the whole volume is mounted when the subpath is empty
+	wholeTarget := filepath.Join(filepath.Dir(target), "1")
+	assert.NilError(t, WriteVolumeSubpaths(stateDir, []VolumeSubpath{
+		{Root: root, Subpath: "app1", Target: target},
+		{Root: root, Target: wholeTarget},
+	}))
+
+	if err := MountVolumeSubpaths(context.Background(), stateDir); errors.Is(err, unix.EPERM) {
+		t.Skipf("cannot mount: %v", err)
+	} else {
+		assert.NilError(t, err)
+	}
+	b, err := os.ReadFile(filepath.Join(target, "foo"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "foo")
+	_, err = os.Stat(filepath.Join(wholeTarget, "app1", "foo"))
+	assert.NilError(t, err)
+
+	// the subpath replaced with a symlink escaping the volume is resolved inside the volume on the next start
+	assert.NilError(t, os.Rename(filepath.Join(root, "app1"), filepath.Join(root, "app2")))
+	assert.NilError(t, os.Symlink("/etc", filepath.Join(root, "app1")))
+	assert.ErrorContains(t, MountVolumeSubpaths(context.Background(), stateDir), "app1")
+
+	assert.NilError(t, UnmountVolumeSubpaths(context.Background(), stateDir))
+	assert.NilError(t, RemoveVolumeSubpaths(stateDir))
+	_, err = os.Stat(filepath.Dir(target))
+	assert.Assert(t, errors.Is(err, os.ErrNotExist))
+	_, err = os.Stat(filepath.Join(root, "app2", "foo"))
+	assert.NilError(t, err)
+}
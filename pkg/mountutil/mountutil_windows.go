@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/containerd/containerd/v2/pkg/oci"
@@ -81,8 +82,86 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return nil, errdefs.ErrNotImplemented
 }
 
+// ProcessFlagMount parses the --mount flag. Only the bind, volume, and npipe types are supported on Windows:
+//
+//	--mount type=bind,source=C:\data,target=C:\data,readonly
+//	--mount type=volume,source=vol-1,target=C:\data
+//	--mount type=npipe,source=\\.\pipe\docker_engine,target=\\.\pipe\docker_engine
+//
+// If the type is not specified, it defaults to volume.
 func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
-	return nil, errdefs.ErrNotImplemented
+	var (
+		mountType = Volume
+		src       string
+		dst       string
+		readonly  bool
+		err       error
+	)
+	for _, field := range strings.Split(s, ",") {
+		key, value, hasValue := strings.Cut(field, "=")
+		key = strings.ToLower(key)
+		switch key {
+		case "type":
+			switch value {
+			case Bind, Volume, Npipe:
+				mountType = value
+			default:
+				return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/npipe", value)
+			}
+		case "source", "src":
+			src = value
+		case "target", "dst", "destination":
+			dst = value
+		case "readonly", "ro":
+			readonly = true
+			if hasValue {
+				readonly, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+				}
+			}
+		case "rw":
+			readonly = false
+			if hasValue {
+				var rw bool
+				rw, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+				}
+				readonly = !rw
+			}
+		default:
+			if !hasValue {
+				return nil, fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+			}
+			return nil, fmt.Errorf("unexpected key '%s' in '%s': %w", key, field, errdefs.ErrNotImplemented)
+		}
+	}
+	if dst == "" {
+		return nil, fmt.Errorf("invalid mount config for type %q: target must be specified", mountType)
+	}
+	if src == "" && mountType != Volume {
+		return nil, fmt.Errorf("invalid mount config for type %q: source must be specified", mountType)
+	}
+	if sourceType := parseSourceType(src); src != "" && sourceType != mountType {
+		return nil, fmt.Errorf("invalid mount config for type %q: source %q is a %s", mountType, src, sourceType)
+	}
+
+	// compose the fields to call ProcessFlagV
+	fields := []string{dst}
+	if src != "" {
+		fields = []string{src, dst}
+	}
+	// createDir=false for --mount option to disallow creating directories on host if not found
+	res, err := ProcessFlagV(strings.Join(fields, ":"), volStore, false)
+	if err != nil {
+		return nil, err
+	}
+	// not passed to ProcessFlagV, as "<DST>:ro" would be parsed as "<SRC>:<DST>"
+	if readonly {
+		res.Mount.Options = append([]string{"ro"}, res.Mount.Options...)
+	}
+	return res, nil
 }
 
 func handleVolumeToMount(source string, dst string, volStore volumestore.VolumeStore, createDir bool) (volumeSpec, error) {
@@ -343,3 +343,69 @@ func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessFlagMount(t *testing.T) {
+	tests := []struct {
+		rawSpec string
+		wants   *Processed
+		err     string
+	}{
+		{
+			rawSpec: `type=npipe,source=\\.\pipe\containerd-containerd,target=\\.\pipe\containerd-containerd`,
+			wants: &Processed{
+				Type: "npipe",
+				Mount: specs.Mount{
+					Source:      `\\.\pipe\containerd-containerd`,
+					Destination: `\\.\pipe\containerd-containerd`,
+					Options:     []string{"rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=volume,src=TestVolume,dst=C:\TestVolume\Path,readonly`,
+			wants: &Processed{
+				Type: "volume",
+				Name: "TestVolume",
+				Mount: specs.Mount{
+					Destination: `C:\TestVolume\Path`,
+					Options:     []string{"ro", "rbind"},
+				}},
+		},
+		{
+			rawSpec: `type=npipe,source=C:\TestVolume\Path,target=C:\TestVolume\Path`,
+			err:     `invalid mount config for type "npipe": source "C:\\TestVolume\\Path" is a bind`,
+		},
+		{
+			rawSpec: `type=npipe,target=\\.\pipe\containerd-containerd`,
+			err:     `invalid mount config for type "npipe": source must be specified`,
+		},
+		{
+			rawSpec: `type=tmpfs,target=C:\TestVolume\Path`,
+			err:     "invalid mount type 'tmpfs' must be a volume/bind/npipe",
+		},
+		{
+			rawSpec: `type=bind,source=C:\TestVolume\Path,target=C:\TestVolume\Path,bind-propagation=shared`,
+			err:     "unexpected key 'bind-propagation'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawSpec, func(t *testing.T) {
+			processedVolSpec, err := ProcessFlagMount(tt.rawSpec, mockVolumeStore)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+
+			assert.Equal(t, processedVolSpec.Type, tt.wants.Type)
+			assert.Equal(t, processedVolSpec.Mount.Destination, tt.wants.Mount.Destination)
+			assert.DeepEqual(t, processedVolSpec.Mount.Options, tt.wants.Mount.Options)
+			if tt.wants.Name != "" {
+				assert.Equal(t, processedVolSpec.Name, tt.wants.Name)
+			}
+			if tt.wants.Mount.Source != "" {
+				assert.Equal(t, processedVolSpec.Mount.Source, tt.wants.Mount.Source)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// volumeSubpathsFileName is the file in the state dir of the container that records its volume subpaths
+const volumeSubpathsFileName = "volume-subpaths.json"
+
+// VolumeSubpath is the subpath of a volume mounted by a container (`volume-subpath` of `--mount`),
+// or a volume of a volume plugin.
+// The subpath is not the source of the mount in the spec of the container, as it may be replaced with a symlink
+// by another container sharing the volume. Instead, it is bind-mounted on Target, a private mount point on the
+// runtime dir of the host, every time the container is started.
+// Likewise, the volumes of a volume plugin are mounted by the plugin every time the container is started,
+// and unmounted when it is stopped, as the OCI hooks of the container are run after its mounts are set up.
+type VolumeSubpath struct {
+	// Root is the mount point of the volume on the host, empty for the volumes of a volume plugin
+	Root string
+	// Subpath is empty to mount the whole volume
+	Subpath string
+	// Target is the source of the mount in the spec of the container
+	Target string
+	// Driver is the volume plugin that mounts the volume Name for the container ID
+	Driver string `json:",omitempty"`
+	Name   string `json:",omitempty"`
+	ID     string `json:",omitempty"`
+}
+
+// VolumeSubpathsDir returns the directory of the mount points of the volume subpaths of the container on the runtime dir:
+// "/run/nerdctl/subpaths/<NAMESPACE>/<ID>", or under $XDG_RUNTIME_DIR in rootless mode.
+func VolumeSubpathsDir(namespace, id string) (string, error) {
+	runtimeDir, err := rootlessutil.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, "nerdctl", "subpaths", namespace, id), nil
+}
+
+// WriteVolumeSubpaths records the volume subpaths in the state dir of the container.
+func WriteVolumeSubpaths(stateDir string, subpaths []VolumeSubpath) error {
+	b, err := json.Marshal(subpaths)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir, volumeSubpathsFileName), b, 0o600)
+}
+
+// loadVolumeSubpaths returns nil for the containers without volume subpaths.
+func loadVolumeSubpaths(stateDir string) ([]VolumeSubpath, error) {
+	b, err := os.ReadFile(filepath.Join(stateDir, volumeSubpathsFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var subpaths []VolumeSubpath
+	if err := json.Unmarshal(b, &subpaths); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", volumeSubpathsFileName, err)
+	}
+	return subpaths, nil
+}
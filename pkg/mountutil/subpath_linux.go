@@ -0,0 +1,169 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cyphar/filepath-securejoin/pathrs-lite"
+	"golang.org/x/sys/unix"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/volumeplugin"
+)
+
+// MountVolumeSubpaths bind-mounts the volume subpaths of the container on their targets, replacing the previous mounts.
+// Each subpath is opened with O_PATH inside its volume, and the file descriptor is bind-mounted through /proc/self/fd,
+// so that the subpath is resolved when the container is started, and cannot escape the volume.
+// The volumes of a volume plugin are mounted by the plugin first.
+// MountVolumeSubpaths must be called before the task of the container is created.
+func MountVolumeSubpaths(ctx context.Context, stateDir string) error {
+	subpaths, err := loadVolumeSubpaths(stateDir)
+	if err != nil {
+		return err
+	}
+	for i, x := range subpaths {
+		if err := mountVolumeSubpath(ctx, x); err != nil {
+			unmountVolumeSubpaths(ctx, subpaths[:i])
+			if x.Driver != "" {
+				return fmt.Errorf("failed to mount the volume %q: %w", x.Name, err)
+			}
+			return fmt.Errorf("failed to mount the volume subpath %q: %w", x.Subpath, err)
+		}
+	}
+	return nil
+}
+
+func mountVolumeSubpath(ctx context.Context, x VolumeSubpath) (retErr error) {
+	if x.Driver != "" {
+		p, err := volumeplugin.Lookup(ctx, x.Driver)
+		if err != nil {
+			return err
+		}
+		if x.Root, err = p.Mount(ctx, x.Name, x.ID); err != nil {
+			return err
+		}
+		defer func() {
+			if retErr != nil {
+				if err := p.Unmount(ctx, x.Name, x.ID); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to unmount volume %q", x.Name)
+				}
+			}
+		}()
+	}
+	var (
+		f   *os.File
+		err error
+	)
+	if x.Subpath == "" {
+		f, err = os.OpenFile(x.Root, unix.O_PATH|unix.O_CLOEXEC, 0)
+	} else {
+		f, err = pathrs.OpenInRoot(x.Root, x.Subpath)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := unmountVolumeSubpath(x.Target); err != nil {
+		return err
+	}
+	// the type of the mount point must match the type of the subpath, which may have changed since the previous start
+	if err := os.Remove(x.Target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(x.Target), 0o700); err != nil {
+		return err
+	}
+	if st.IsDir() {
+		err = os.Mkdir(x.Target, 0o700)
+	} else {
+		err = os.WriteFile(x.Target, nil, 0o600)
+	}
+	if err != nil {
+		return err
+	}
+	if err := unix.Mount("/proc/self/fd/"+strconv.Itoa(int(f.Fd())), x.Target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return &os.PathError{Op: "mount", Path: x.Target, Err: err}
+	}
+	return nil
+}
+
+// UnmountVolumeSubpaths unmounts the volume subpaths of the container, e.g., when the container is stopped.
+// The volumes of a volume plugin are unmounted by the plugin too.
+func UnmountVolumeSubpaths(ctx context.Context, stateDir string) error {
+	subpaths, err := loadVolumeSubpaths(stateDir)
+	if err != nil {
+		return err
+	}
+	return unmountVolumeSubpaths(ctx, subpaths)
+}
+
+func unmountVolumeSubpaths(ctx context.Context, subpaths []VolumeSubpath) error {
+	var errs []error
+	for _, x := range subpaths {
+		errs = append(errs, unmountVolumeSubpath(x.Target))
+		if x.Driver != "" {
+			p, err := volumeplugin.Lookup(ctx, x.Driver)
+			if err == nil {
+				err = p.Unmount(ctx, x.Name, x.ID)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to unmount volume %q: %w", x.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveVolumeSubpaths unmounts the volume subpaths of the container, and removes their mount points.
+// The volumes of a volume plugin have already been unmounted by the plugin when the container was stopped.
+func RemoveVolumeSubpaths(stateDir string) error {
+	subpaths, err := loadVolumeSubpaths(stateDir)
+	if err != nil || len(subpaths) == 0 {
+		return err
+	}
+	for _, x := range subpaths {
+		if err := unmountVolumeSubpath(x.Target); err != nil {
+			return err
+		}
+		// not os.RemoveAll, so that the content of a volume is never removed
+		if err := os.Remove(x.Target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Dir(subpaths[0].Target)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func unmountVolumeSubpath(target string) error {
+	if err := unix.Unmount(target, unix.MNT_DETACH); err != nil && !errors.Is(err, unix.EINVAL) && !errors.Is(err, unix.ENOENT) {
+		return &os.PathError{Op: "unmount", Path: target, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import "context"
+
+// MountVolumeSubpaths is a no-op, as volume subpaths are only supported on Linux.
+func MountVolumeSubpaths(ctx context.Context, stateDir string) error {
+	return nil
+}
+
+// UnmountVolumeSubpaths is a no-op, as volume subpaths are only supported on Linux.
+func UnmountVolumeSubpaths(ctx context.Context, stateDir string) error {
+	return nil
+}
+
+// RemoveVolumeSubpaths is a no-op, as volume subpaths are only supported on Linux.
+func RemoveVolumeSubpaths(stateDir string) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/containerd/errdefs"
+)
+
+// The driver options of local volumes that mount a filesystem, like the local driver of dockerd, e.g.,
+// `--opt type=nfs --opt o=addr=10.0.0.5,rw --opt device=:/export/data`.
+const (
+	// VolumeOptType is the filesystem type, e.g., "nfs", "cifs", "tmpfs", or "none" for a bind mount
+	VolumeOptType = "type"
+	// VolumeOptDevice is the device or the remote path to mount, e.g., ":/export/data" or "//server/share"
+	VolumeOptDevice = "device"
+	// VolumeOptO is the comma-separated mount options, e.g., "addr=10.0.0.5,rw"
+	VolumeOptO = "o"
+	// VolumeOptSize is the limit of the disk usage of the volume, e.g., "10G", enforced with project quotas
+	VolumeOptSize = "size"
+)
+
+// ValidateVolumeOptions validates the driver options of a local volume.
+func ValidateVolumeOptions(opts map[string]string) error {
+	for k := range opts {
+		switch k {
+		case VolumeOptType, VolumeOptDevice, VolumeOptO, VolumeOptSize:
+		default:
+			return fmt.Errorf("invalid option key %q for the local volume driver, expected %q, %q, %q, or %q: %w",
+				k, VolumeOptType, VolumeOptDevice, VolumeOptO, VolumeOptSize, errdefs.ErrInvalidArgument)
+		}
+	}
+	if size, ok := opts[VolumeOptSize]; ok {
+		if opts[VolumeOptType] != "" {
+			return fmt.Errorf("option %q cannot be used with option %q: %w", VolumeOptSize, VolumeOptType, errdefs.ErrInvalidArgument)
+		}
+		if _, err := VolumeSize(size); err != nil {
+			return err
+		}
+	}
+	return checkVolumeOptions(opts)
+}
+
+// VolumeSize parses the size option of a local volume.
+func VolumeSize(size string) (uint64, error) {
+	b, err := units.RAMInBytes(size)
+	if err != nil || b <= 0 {
+		return 0, fmt.Errorf("invalid volume size %q: %w", size, errdefs.ErrInvalidArgument)
+	}
+	return uint64(b), nil
+}
+
+func checkVolumeOptions(opts map[string]string) error {
+	switch {
+	case opts[VolumeOptType] == "" && (opts[VolumeOptDevice] != "" || opts[VolumeOptO] != ""):
+		return fmt.Errorf("missing required option %q: %w", VolumeOptType, errdefs.ErrInvalidArgument)
+	case opts[VolumeOptType] != "" && opts[VolumeOptDevice] == "":
+		return fmt.Errorf("missing required option %q: %w", VolumeOptDevice, errdefs.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// volumeOptionsMount returns the filesystem to mount for the driver options of a local volume,
+// and nil when the volume is a plain directory.
+// The address of NFS and CIFS servers is resolved here, as the kernel only accepts IP addresses.
+func volumeOptionsMount(opts map[string]string) (*specs.Mount, error) {
+	if err := checkVolumeOptions(opts); err != nil {
+		return nil, err
+	}
+	fstype, device, o := opts[VolumeOptType], opts[VolumeOptDevice], opts[VolumeOptO]
+	if fstype == "" {
+		return nil, nil
+	}
+	var options []string
+	if o != "" {
+		options = strings.Split(o, ",")
+	}
+	if fstype == "nfs" || fstype == "nfs4" || fstype == "cifs" {
+		for i, opt := range options {
+			addr, ok := strings.CutPrefix(opt, "addr=")
+			if !ok || net.ParseIP(addr) != nil {
+				continue
+			}
+			ip, err := net.ResolveIPAddr("ip", addr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the address %q of the volume: %w", addr, err)
+			}
+			options[i] = "addr=" + ip.String()
+		}
+	}
+	return &specs.Mount{
+		Type:    fstype,
+		Source:  device,
+		Options: options,
+	}, nil
+}
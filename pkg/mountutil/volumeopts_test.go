@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mountutil
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestValidateVolumeOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "no options",
+		},
+		{
+			name: "nfs",
+			opts: map[string]string{"type": "nfs", "o": "addr=10.0.0.5,rw", "device": ":/export/data"},
+		},
+		{
+			name: "size",
+			opts: map[string]string{"size": "10G"},
+		},
+		{
+			name:    "invalid size",
+			opts:    map[string]string{"size": "foo"},
+			wantErr: true,
+		},
+		{
+			name:    "size with type",
+			opts:    map[string]string{"size": "10G", "type": "tmpfs", "device": "tmpfs"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			opts:    map[string]string{"foo": "bar"},
+			wantErr: true,
+		},
+		{
+			name:    "device without type",
+			opts:    map[string]string{"device": ":/export/data"},
+			wantErr: true,
+		},
+		{
+			name:    "type without device",
+			opts:    map[string]string{"type": "nfs", "o": "addr=10.0.0.5"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVolumeOptions(tc.opts)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestVolumeOptionsMount(t *testing.T) {
+	m, err := volumeOptionsMount(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, m == nil)
+
+	m, err = volumeOptionsMount(map[string]string{"type": "nfs", "o": "addr=10.0.0.5,rw,nfsvers=4", "device": ":/export/data"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *m, specs.Mount{
+		Type:    "nfs",
+		Source:  ":/export/data",
+		Options: []string{"addr=10.0.0.5", "rw", "nfsvers=4"},
+	})
+
+	m, err = volumeOptionsMount(map[string]string{"type": "tmpfs", "device": "tmpfs"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *m, specs.Mount{
+		Type:   "tmpfs",
+		Source: "tmpfs",
+	})
+}
@@ -23,12 +23,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/projectquota"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -37,8 +45,15 @@ const (
 	volumeDirBasename  = "volumes"
 	dataDirName        = "_data"
 	volumeJSONFileName = "volume.json"
+	sizeJSONFileName   = "size.json"
+	// sizeOption is the driver option limiting the size of a local volume, see mountutil.VolumeOptSize
+	sizeOption = "size"
 )
 
+// sizeCacheTTL is how long the computed size of a volume is reused, unless the top directory of the volume changed.
+// Walking large volumes is slow, and `nerdctl volume ls --size` walks all of them.
+const sizeCacheTTL = time.Minute
+
 // ErrVolumeStore will wrap all errors here
 var ErrVolumeStore = errors.New("volume-store error")
 
@@ -49,8 +64,11 @@ type VolumeStore interface {
 	Get(name string, size bool) (*native.Volume, error)
 	// Create will either return an existing volume, or create a new one
 	// NOTE that different labels will NOT create a new volume if there is one by that name already,
-	// but instead return the existing one with the (possibly different) labels
-	Create(name string, labels []string) (vol *native.Volume, err error)
+	// but instead return the existing one with the (possibly different) labels.
+	// opts are the driver options (e.g. `--opt` of `nerdctl volume create`), stored alongside the labels.
+	// driver is the volume plugin managing the volume, empty for the local driver: the store only records the
+	// volume, and does not create a data directory for it.
+	Create(name, driver string, labels []string, opts map[string]string) (vol *native.Volume, err error)
 	// List returns all existing volumes.
 	// Note that list is expensive as it reads all volumes individual info
 	List(size bool) (map[string]native.Volume, error)
@@ -158,10 +176,10 @@ func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *nat
 		return nil, err
 	}
 
-	return vs.rawCreate(name, labels)
+	return vs.rawCreate(name, "", labels, nil)
 }
 
-func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) Create(name, driver string, labels []string, opts map[string]string) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -173,7 +191,7 @@ func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume,
 	}
 
 	err = vs.Locker.WithLock(func() error {
-		vol, err = vs.rawCreate(name, labels)
+		vol, err = vs.rawCreate(name, driver, labels, opts)
 		return err
 	})
 
@@ -215,16 +233,27 @@ func (vs *volumeStore) List(size bool) (res map[string]native.Volume, err error)
 			return err
 		}
 
+		// walking the volumes for their size is slow, so, the volumes are read concurrently
+		var (
+			mu sync.Mutex
+			eg errgroup.Group
+		)
+		eg.SetLimit(runtime.NumCPU())
 		for _, name := range names {
-			vol, err := vs.rawGet(name, size)
-			if err != nil {
-				log.L.WithError(err).Errorf("something is wrong with %q", name)
-				continue
-			}
-			res[name] = *vol
+			eg.Go(func() error {
+				vol, err := vs.rawGet(name, size)
+				if err != nil {
+					log.L.WithError(err).Errorf("something is wrong with %q", name)
+					return nil
+				}
+				mu.Lock()
+				res[name] = *vol
+				mu.Unlock()
+				return nil
+			})
 		}
 
-		return nil
+		return eg.Wait()
 	})
 
 	return res, err
@@ -323,8 +352,14 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	}
 
 	vol = &native.Volume{
-		Name:   name,
-		Labels: labels(content),
+		Name:    name,
+		Driver:  volumeDriver(content),
+		Labels:  labels(content),
+		Options: options(content),
+	}
+	// the mountpoint of plugin volumes is managed by the plugin
+	if vol.Driver != "" {
+		return vol, nil
 	}
 
 	vol.Mountpoint, err = vs.manager.Location(name, dataDirName)
@@ -333,7 +368,7 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	}
 
 	if size {
-		vol.Size, err = vs.manager.GroupSize(name, dataDirName)
+		vol.Size, err = vs.size(name, vol.Mountpoint)
 		if err != nil {
 			return nil, errors.Join(fmt.Errorf("failed reading volume size for %q", name), err)
 		}
@@ -342,14 +377,51 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	return vol, nil
 }
 
-func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volume, err error) {
+type sizeCache struct {
+	Size      int64     `json:"size"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// size returns the disk usage of a local volume, from the cache when it is fresh enough.
+func (vs *volumeStore) size(name, mountpoint string) (int64, error) {
+	if content, err := vs.manager.Get(name, sizeJSONFileName); err == nil {
+		var cache sizeCache
+		if err := json.Unmarshal(content, &cache); err == nil && time.Since(cache.Timestamp) < sizeCacheTTL {
+			// files added or removed at the top of the volume invalidate the cache right away
+			if st, err := os.Stat(mountpoint); err == nil && st.ModTime().Before(cache.Timestamp) {
+				return cache.Size, nil
+			}
+		}
+	}
+
+	now := time.Now()
+	size, err := vs.manager.GroupSize(name, dataDirName)
+	if err != nil {
+		return 0, err
+	}
+	if content, err := json.Marshal(sizeCache{Size: size, Timestamp: now}); err == nil {
+		if err := vs.manager.Set(content, name, sizeJSONFileName); err != nil {
+			log.L.WithError(err).Debugf("failed to cache the size of volume %q", name)
+		}
+	}
+	return size, nil
+}
+
+func (vs *volumeStore) rawCreate(name, driver string, labels []string, opts map[string]string) (vol *native.Volume, err error) {
 	volOpts := struct {
-		Labels map[string]string `json:"labels"`
-	}{}
+		Driver  string            `json:"driver,omitempty"`
+		Labels  map[string]string `json:"labels"`
+		Options map[string]string `json:"options,omitempty"`
+	}{
+		Driver: driver,
+	}
 
 	if len(labels) > 0 {
 		volOpts.Labels = strutil.ConvertKVStringsToMap(labels)
 	}
+	if len(opts) > 0 {
+		volOpts.Options = opts
+	}
 
 	// Failure here must exit, no need to clean-up
 	labelsJSON, err := json.MarshalIndent(volOpts, "", "    ")
@@ -357,20 +429,43 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 		return nil, err
 	}
 
-	if doesExist, err := vs.manager.Exists(name, volumeJSONFileName); err != nil {
+	doesExist, err := vs.manager.Exists(name, volumeJSONFileName)
+	if err != nil {
 		return nil, err
 	} else if !doesExist {
 		if err = vs.manager.Set(labelsJSON, name, volumeJSONFileName); err != nil {
 			return nil, err
 		}
+		// the quota must be set before creating the data directory, which inherits the project of the volume
+		if size, ok := opts[sizeOption]; ok && driver == "" {
+			if err = vs.setQuota(name, size); err != nil {
+				if delErr := vs.manager.Delete(name); delErr != nil {
+					log.L.WithError(delErr).Errorf("failed to remove volume %q", name)
+				}
+				return nil, err
+			}
+		}
 	} else {
 		log.L.Warnf("volume %q already exists and will be returned as-is", name)
 		// FIXME: we do not check if the existing volume has the same labels as requested - should we?
+		if content, err := vs.manager.Get(name, volumeJSONFileName); err == nil {
+			driver = volumeDriver(content)
+			if o := options(content); o != nil {
+				opts = *o
+			}
+		}
 	}
 
 	// At this point, we either have an existing volume, or created a new one successfully
 	vol = &native.Volume{
-		Name: name,
+		Name:   name,
+		Driver: driver,
+	}
+	if len(opts) > 0 {
+		vol.Options = &opts
+	}
+	if driver != "" {
+		return vol, nil
 	}
 
 	if err = vs.manager.GroupEnsure(name, dataDirName); err != nil {
@@ -384,7 +479,40 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	return vol, nil
 }
 
+// setQuota limits the disk usage of a new volume with a project quota.
+func (vs *volumeStore) setQuota(name, size string) error {
+	b, err := units.RAMInBytes(size)
+	if err != nil || b <= 0 {
+		return fmt.Errorf("invalid volume size %q", size)
+	}
+	dir, err := vs.manager.Location(name)
+	if err != nil {
+		return err
+	}
+	// the projects must not be shared with the volumes of the other namespaces
+	if err := projectquota.SetQuota(filepath.Dir(filepath.Dir(dir)), dir, uint64(b)); err != nil {
+		return fmt.Errorf("failed to limit the size of volume %q: %w", name, err)
+	}
+	return nil
+}
+
+// Quota returns the project quota limiting the size of a local volume created with the size option.
+func Quota(vol *native.Volume) (*projectquota.Quota, error) {
+	// the quota is set on the directory of the volume, the parent of the data directory
+	return projectquota.GetQuota(filepath.Dir(vol.Mountpoint))
+}
+
 // Private helpers
+func volumeDriver(b []byte) string {
+	var vo struct {
+		Driver string `json:"driver,omitempty"`
+	}
+	if err := json.Unmarshal(b, &vo); err != nil {
+		return ""
+	}
+	return vo.Driver
+}
+
 func labels(b []byte) *map[string]string {
 	type volumeOpts struct {
 		Labels *map[string]string `json:"labels,omitempty"`
@@ -395,3 +523,14 @@ func labels(b []byte) *map[string]string {
 	}
 	return vo.Labels
 }
+
+func options(b []byte) *map[string]string {
+	type volumeOpts struct {
+		Options *map[string]string `json:"options,omitempty"`
+	}
+	var vo volumeOpts
+	if err := json.Unmarshal(b, &vo); err != nil {
+		return nil
+	}
+	return vo.Options
+}
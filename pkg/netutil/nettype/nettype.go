@@ -30,6 +30,7 @@ const (
 	CNI
 	Container
 	Namespace
+	Pasta
 )
 
 var netTypeToName = map[interface{}]string{
@@ -39,6 +40,7 @@ var netTypeToName = map[interface{}]string{
 	CNI:       "cni",
 	Container: "container",
 	Namespace: "ns",
+	Pasta:     "pasta",
 }
 
 func Detect(names []string) (Type, error) {
@@ -58,6 +60,8 @@ func Detect(names []string) (Type, error) {
 			tmp = Container
 		case "ns":
 			tmp = Namespace
+		case "pasta":
+			tmp = Pasta
 		default:
 			tmp = CNI
 		}
@@ -53,6 +53,18 @@ func TestDetect(t *testing.T) {
 			names:    []string{"foo", "bar", "bridge"},
 			expected: CNI,
 		},
+		{
+			names:    []string{"pasta"},
+			expected: Pasta,
+		},
+		{
+			names:    []string{"pasta:--ipv4-only,-a,10.0.2.100"},
+			expected: Pasta,
+		},
+		{
+			names: []string{"pasta", "bridge"},
+			err:   "mixed network types",
+		},
 		{
 			names: []string{"none", "host"},
 			err:   "mixed network types",
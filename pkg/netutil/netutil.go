@@ -331,6 +331,27 @@ func (e *CNIEnv) RemoveNetwork(net *NetworkConfig) error {
 	return fsRemove(e, net)
 }
 
+// ImportNetwork writes a network configuration list exported from another host (e.g., by `nerdctl bundle create`).
+// Returns errdefs.ErrAlreadyExists if a network with the same name already exists.
+func (e *CNIEnv) ImportNetwork(confListBytes []byte) (*NetworkConfig, error) {
+	l, err := libcni.ConfListFromBytes(confListBytes)
+	if err != nil {
+		return nil, err
+	}
+	netMap, err := e.NetworkMap()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := netMap[l.Name]; ok {
+		return nil, fmt.Errorf("network %q: %w", l.Name, errdefs.ErrAlreadyExists)
+	}
+	netConf := &NetworkConfig{NetworkConfigList: l}
+	if err := fsWrite(e, netConf); err != nil {
+		return nil, err
+	}
+	return netConf, nil
+}
+
 // GetDefaultNetworkConfig checks whether the default network exists
 // by first searching for if any network bears the `labels.NerdctlDefaultNetwork`
 // label, or falls back to checking whether any network bears the
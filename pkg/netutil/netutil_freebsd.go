@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+)
+
+// removeBridgeNetworkInterface destroys the bridge(4) interface created by the bridge plugin.
+func removeBridgeNetworkInterface(netIf string) error {
+	if _, err := net.InterfaceByName(netIf); err != nil {
+		// not created yet, or already destroyed
+		return nil
+	}
+	if out, err := exec.Command("ifconfig", netIf, "destroy").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove network interface %s: %v (out=%q)", netIf, err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// Interface returns the state of the bridge interface of the network, or nil if the network is not a bridge
+// network or the interface has not been created yet.
+// In rootless mode, the interface is looked up in the network namespace of RootlessKit.
+func (n *NetworkConfig) Interface() (*native.NetworkInterface, error) {
+	if len(n.Plugins) == 0 || n.Plugins[0].Network.Type != "bridge" {
+		return nil, nil
+	}
+	var bridge bridgeConfig
+	if err := json.Unmarshal(n.Plugins[0].Bytes, &bridge); err != nil {
+		return nil, err
+	}
+	var iface *native.NetworkInterface
+	err := rootlessutil.WithDetachedNetNSIfAny(func() error {
+		link, err := netlink.LinkByName(bridge.BrName)
+		if err != nil {
+			// The bridge is created on starting the first container of the network
+			if errors.As(err, &netlink.LinkNotFoundError{}) {
+				return nil
+			}
+			return err
+		}
+		i, err := interfaceFromLink(link)
+		if err != nil {
+			return err
+		}
+		iface = &i
+		return nil
+	})
+	return iface, err
+}
+
+// RootlessNetwork returns the network interfaces in the network namespace of RootlessKit,
+// or nil if not running in rootless mode.
+func RootlessNetwork() (*native.RootlessNetwork, error) {
+	if !rootlessutil.IsRootless() {
+		return nil, nil
+	}
+	netns, err := rootlessutil.DetachedNetNS()
+	if err != nil {
+		return nil, err
+	}
+	res := &native.RootlessNetwork{
+		NetNS: netns,
+	}
+	err = rootlessutil.WithDetachedNetNSIfAny(func() error {
+		links, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+		for _, link := range links {
+			i, err := interfaceFromLink(link)
+			if err != nil {
+				return err
+			}
+			res.Interfaces = append(res.Interfaces, i)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func interfaceFromLink(link netlink.Link) (native.NetworkInterface, error) {
+	attrs := link.Attrs()
+	iface := native.NetworkInterface{
+		Name:  attrs.Name,
+		Index: attrs.Index,
+		State: attrs.OperState.String(),
+		MTU:   attrs.MTU,
+	}
+	if len(attrs.HardwareAddr) > 0 {
+		iface.HardwareAddr = attrs.HardwareAddr.String()
+	}
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return iface, fmt.Errorf("failed to list the addresses of %s: %w", attrs.Name, err)
+	}
+	for _, addr := range addrs {
+		iface.Addrs = append(iface.Addrs, addr.IPNet.String())
+	}
+	return iface, nil
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+)
+
+// Interface returns the state of the bridge interface of the network.
+// Only supported on Linux.
+func (n *NetworkConfig) Interface() (*native.NetworkInterface, error) {
+	return nil, nil
+}
+
+// RootlessNetwork returns nil, as rootless mode is only supported on Linux.
+func RootlessNetwork() (*native.RootlessNetwork, error) {
+	return nil, nil
+}
@@ -25,17 +25,16 @@ import (
 	"net"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-viper/mapstructure/v2"
-	"github.com/vishvananda/netlink"
 
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
-	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/systemutil"
 )
@@ -140,6 +139,19 @@ func (e *CNIEnv) generateCNIPlugins(driver string, name string, ipam map[string]
 			bridge.Capabilities["ips"] = true
 		}
 
+		if runtime.GOOS == "freebsd" {
+			// The firewall and tuning plugins are not available on FreeBSD.
+			// The bridge plugin connects the jails with epair(4) interfaces, and the portmap plugin redirects the ports with pf(4).
+			if !icc {
+				return nil, fmt.Errorf("network option %q is not supported on FreeBSD", "icc")
+			}
+			plugins = []CNIPlugin{bridge}
+			if !internal {
+				plugins = append(plugins, newPortMapPlugin())
+			}
+			break
+		}
+
 		// Determine the appropriate firewall ingress policy based on icc setting
 		ingressPolicy := "same-bridge" // Default policy
 		firewallPath := filepath.Join(e.Path, "firewall")
@@ -171,6 +183,9 @@ func (e *CNIEnv) generateCNIPlugins(driver string, name string, ipam map[string]
 			}
 		}
 	case "macvlan", "ipvlan":
+		if runtime.GOOS == "freebsd" {
+			return nil, fmt.Errorf("unsupported cni driver %q on FreeBSD", driver)
+		}
 		mtu := 0
 		mode := ""
 		master := ""
@@ -366,15 +381,3 @@ func guessFirewallPluginVersion(stderr string) (*semver.Version, error) {
 	}
 	return nil, fmt.Errorf("stderr %q does not have any line that starts with %q", stderr, prefix)
 }
-
-func removeBridgeNetworkInterface(netIf string) error {
-	return rootlessutil.WithDetachedNetNSIfAny(func() error {
-		link, err := netlink.LinkByName(netIf)
-		if err == nil {
-			if err := netlink.LinkDel(link); err != nil {
-				return fmt.Errorf("failed to remove network interface %s: %v", netIf, err)
-			}
-		}
-		return nil
-	})
-}
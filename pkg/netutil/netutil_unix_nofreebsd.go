@@ -0,0 +1,39 @@
+//go:build unix && !freebsd
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+func removeBridgeNetworkInterface(netIf string) error {
+	return rootlessutil.WithDetachedNetNSIfAny(func() error {
+		link, err := netlink.LinkByName(netIf)
+		if err == nil {
+			if err := netlink.LinkDel(link); err != nil {
+				return fmt.Errorf("failed to remove network interface %s: %v", netIf, err)
+			}
+		}
+		return nil
+	})
+}
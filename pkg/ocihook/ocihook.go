@@ -34,10 +34,12 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
 	rlkclient "github.com/rootless-containers/rootlesskit/v2/pkg/api/client"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/containerd/go-cni"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/annotations"
 	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
@@ -49,6 +51,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
+	"github.com/containerd/nerdctl/v2/pkg/tracingutil"
 )
 
 const (
@@ -64,7 +67,7 @@ const (
 	NetworkNamespace = labels.Prefix + "network-namespace"
 )
 
-func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, bridgeIP string) error {
+func Run(ctx context.Context, stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, bridgeIP string) error {
 	if stdin == nil || event == "" || dataStore == "" || cniPath == "" || cniNetconfPath == "" {
 		return errors.New("got insufficient args")
 	}
@@ -73,6 +76,7 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	if err := json.NewDecoder(stdin).Decode(&state); err != nil {
 		return err
 	}
+	tracingutil.SetAttributes(ctx, attribute.String("container.id", state.ID), attribute.String("oci-hook.event", event))
 
 	containerStateDir := state.Annotations[labels.StateDir]
 	if containerStateDir == "" {
@@ -125,9 +129,9 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 
 	switch event {
 	case "createRuntime":
-		return onCreateRuntime(opts)
+		return onCreateRuntime(ctx, opts)
 	case "postStop":
-		return onPostStop(opts)
+		return onPostStop(ctx, opts)
 	default:
 		return fmt.Errorf("unexpected event %q", event)
 	}
@@ -145,6 +149,12 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 	}
 	o.extraHosts = extraHosts
 
+	if aliasesJSON := o.state.Annotations[labels.NetworkAliases]; aliasesJSON != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON), &o.networkAliases); err != nil {
+			return nil, err
+		}
+	}
+
 	hs, err := loadSpec(o.state.Bundle)
 	if err != nil {
 		return nil, err
@@ -177,6 +187,9 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 	switch netType {
 	case nettype.Host, nettype.None, nettype.Container, nettype.Namespace:
 		// NOP
+	case nettype.Pasta:
+		o.pasta = true
+		o.pastaOptions = pastaOptions(networks[0])
 	case nettype.CNI:
 		e, err := netutil.NewCNIEnv(cniPath, cniNetconfPath, netutil.WithNamespace(namespace), netutil.WithDefaultNetwork(bridgeIP))
 		if err != nil {
@@ -258,10 +271,13 @@ type handlerOpts struct {
 	ports             []cni.PortMapping
 	cni               cni.CNI
 	cniNames          []string
+	pasta             bool
+	pastaOptions      []string
 	fullID            string
 	rootlessKitClient rlkclient.Client
 	bypassClient      b4nndclient.Client
 	extraHosts        map[string]string // host:ip
+	networkAliases    []string
 	containerIP       string
 	containerMAC      string
 	containerIP6      string
@@ -460,7 +476,7 @@ func portReserverPidFilePath(opts *handlerOpts) string {
 	return filepath.Join("/run/nerdctl/", opts.state.Annotations[labels.Namespace], opts.state.ID, "port-reserver.pid")
 }
 
-func applyNetworkSettings(opts *handlerOpts) (err error) {
+func applyNetworkSettings(ctx context.Context, opts *handlerOpts) (err error) {
 	portMapOpts, err := getPortMapOpts(opts)
 	if err != nil {
 		return err
@@ -513,7 +529,6 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 	if err != nil {
 		return err
 	}
-	ctx := context.Background()
 	hs, err := hostsstore.New(opts.dataStore, opts.state.Annotations[labels.Namespace])
 	if err != nil {
 		return err
@@ -548,6 +563,7 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 		Domainname: opts.state.Annotations[labels.Domainname],
 		ExtraHosts: opts.extraHosts,
 		Name:       opts.state.Annotations[labels.Name],
+		Aliases:    opts.networkAliases,
 	}
 
 	// When containerd gets bounced, containers that were previously running and that are restarted will go again
@@ -600,6 +616,9 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 			}
 		}
 		if !b4nnBindEnabled && len(opts.ports) > 0 {
+			if err := checkRootlessPortDriver(ctx, opts); err != nil {
+				return err
+			}
 			if err := exposePortsRootless(ctx, opts.rootlessKitClient, opts.ports); err != nil {
 				return fmt.Errorf("failed to expose ports in rootless mode: %w", err)
 			}
@@ -608,7 +627,26 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 	return nil
 }
 
-func onCreateRuntime(opts *handlerOpts) error {
+// checkRootlessPortDriver returns an error when RootlessKit is not running with the port driver of `--rootless-port-driver`,
+// as RootlessKit may have been restarted with another port driver since the container was created.
+func checkRootlessPortDriver(ctx context.Context, opts *handlerOpts) error {
+	driver := opts.state.Annotations[annotations.RootlessPortDriver]
+	switch driver {
+	case "builtin", "slirp4netns", "implicit":
+	default:
+		return nil
+	}
+	info, err := opts.rootlessKitClient.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the port driver of RootlessKit: %w", err)
+	}
+	if info.PortDriver == nil || info.PortDriver.Driver != driver {
+		return fmt.Errorf("the container was created with --rootless-port-driver=%s, but RootlessKit is not running with that port driver", driver)
+	}
+	return nil
+}
+
+func onCreateRuntime(ctx context.Context, opts *handlerOpts) error {
 	loadAppArmor()
 
 	name := opts.state.Annotations[labels.Name]
@@ -622,7 +660,12 @@ func onCreateRuntime(opts *handlerOpts) error {
 
 	var netError error
 	if opts.cni != nil {
-		netError = applyNetworkSettings(opts)
+		netCtx, netSpan := tracingutil.StartSpan(ctx, "nerdctl.network.setup", attribute.StringSlice("network.names", opts.cniNames))
+		netError = applyNetworkSettings(netCtx, opts)
+		tracingutil.EndSpan(netSpan, netError)
+	}
+	if opts.pasta {
+		netError = startPasta(opts)
 	}
 
 	// Set StartedAt and CreateError
@@ -643,7 +686,7 @@ func onCreateRuntime(opts *handlerOpts) error {
 	return netError
 }
 
-func onPostStop(opts *handlerOpts) error {
+func onPostStop(ctx context.Context, opts *handlerOpts) error {
 	lf, err := state.New(opts.state.Annotations[labels.StateDir])
 	if err != nil {
 		return err
@@ -667,7 +710,6 @@ func onPostStop(opts *handlerOpts) error {
 		return nil
 	}
 
-	ctx := context.Background()
 	ns := opts.state.Annotations[labels.Namespace]
 	if opts.cni != nil {
 		var err error
@@ -743,6 +785,11 @@ func onPostStop(opts *handlerOpts) error {
 	if err := namst.Release(name, opts.state.ID); err != nil && !errors.Is(err, store.ErrNotFound) {
 		return fmt.Errorf("failed to release container name %s: %w", name, err)
 	}
+	if opts.pasta {
+		if err := stopPasta(opts); err != nil {
+			log.L.WithError(err).Errorf("failed to kill the pasta process")
+		}
+	}
 	// Kill port-reserver process if any
 	portReserverPidFile := portReserverPidFilePath(opts)
 	if err = killProcessByPidFile(portReserverPidFile); err != nil {
@@ -0,0 +1,134 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocihook
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// PastaDNSForwardAddress is the address in the container network namespace
+// where pasta forwards DNS queries to the first nameserver of the host.
+const PastaDNSForwardAddress = "169.254.1.1"
+
+// pastaOptions returns the pasta options specified as "pasta:<OPT>[,<OPT>...]".
+func pastaOptions(network string) []string {
+	_, opts, ok := strings.Cut(network, ":")
+	if !ok || opts == "" {
+		return nil
+	}
+	return strings.Split(opts, ",")
+}
+
+// pastaPidFilePath returns <state dir>/pasta.pid
+func pastaPidFilePath(opts *handlerOpts) string {
+	return filepath.Join(opts.state.Annotations[labels.StateDir], "pasta.pid")
+}
+
+func hasPastaOption(userOpts []string, short, long string) bool {
+	for _, o := range userOpts {
+		if o == short || o == long || strings.HasPrefix(o, long+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// pastaPortSpec converts a port mapping to the "[ADDR/]PORT:PORT" form accepted by
+// the -t and -u options of pasta.
+func pastaPortSpec(p cni.PortMapping) string {
+	spec := fmt.Sprintf("%d:%d", p.HostPort, p.ContainerPort)
+	// An unspecified address lets pasta bind the port for both IPv4 and IPv6.
+	if hostIP := net.ParseIP(p.HostIP); hostIP != nil && !hostIP.IsUnspecified() {
+		spec = hostIP.String() + "/" + spec
+	}
+	return spec
+}
+
+// pastaArgs returns the arguments for connecting the network namespace nsPath
+// to the host network with pasta(1).
+func pastaArgs(nsPath, pidFile string, ports []cni.PortMapping, userOpts []string) ([]string, error) {
+	args := []string{
+		"--config-net",
+		"--quiet",
+		"--netns", nsPath,
+		"--pid", pidFile,
+		"--dns-forward", PastaDNSForwardAddress,
+	}
+	var tcp, udp []string
+	for _, p := range ports {
+		switch p.Protocol {
+		case "tcp":
+			tcp = append(tcp, "-t", pastaPortSpec(p))
+		case "udp":
+			udp = append(udp, "-u", pastaPortSpec(p))
+		default:
+			return nil, fmt.Errorf("protocol %q is not supported by pasta networking", p.Protocol)
+		}
+	}
+	// pasta forwards all the ports bound on the host by default, unless told otherwise
+	if len(tcp) == 0 && !hasPastaOption(userOpts, "-t", "--tcp-ports") {
+		tcp = []string{"-t", "none"}
+	}
+	if len(udp) == 0 && !hasPastaOption(userOpts, "-u", "--udp-ports") {
+		udp = []string{"-u", "none"}
+	}
+	args = append(args, tcp...)
+	args = append(args, udp...)
+	if !hasPastaOption(userOpts, "-T", "--tcp-ns") {
+		args = append(args, "-T", "none")
+	}
+	if !hasPastaOption(userOpts, "-U", "--udp-ns") {
+		args = append(args, "-U", "none")
+	}
+	return append(args, userOpts...), nil
+}
+
+func startPasta(opts *handlerOpts) error {
+	nsPath, err := getNetNSPath(opts.state)
+	if err != nil {
+		return err
+	}
+	pidFile := pastaPidFilePath(opts)
+	// When containerd gets bounced, the pasta process of the previous run may still be alive.
+	if err := killProcessByPidFile(pidFile); err != nil {
+		log.L.WithError(err).Warn("failed to kill the stale pasta process")
+	}
+	args, err := pastaArgs(nsPath, pidFile, opts.ports, opts.pastaOptions)
+	if err != nil {
+		return err
+	}
+	// pasta daemonizes itself once the network namespace is configured.
+	cmd := exec.Command("pasta", args...)
+	log.L.Debugf("running %v", cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run %v: %w (out=%q)", cmd.Args, err, string(out))
+	}
+	return nil
+}
+
+func stopPasta(opts *handlerOpts) error {
+	return killProcessByPidFile(pastaPidFilePath(opts))
+}
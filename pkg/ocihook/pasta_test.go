@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ocihook
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/go-cni"
+)
+
+func TestPastaArgs(t *testing.T) {
+	base := []string{
+		"--config-net", "--quiet",
+		"--netns", "/proc/42/ns/net",
+		"--pid", "/state/pasta.pid",
+		"--dns-forward", PastaDNSForwardAddress,
+	}
+	testCases := []struct {
+		name     string
+		ports    []cni.PortMapping
+		userOpts []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "no ports",
+			expected: append(append([]string{}, base...), "-t", "none", "-u", "none", "-T", "none", "-U", "none"),
+		},
+		{
+			name: "ports",
+			ports: []cni.PortMapping{
+				{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostIP: "127.0.0.1", HostPort: 8443, ContainerPort: 443, Protocol: "tcp"},
+				{HostIP: "::1", HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+			},
+			expected: append(append([]string{}, base...),
+				"-t", "8080:80", "-t", "127.0.0.1/8443:443", "-u", "::1/5353:53", "-T", "none", "-U", "none"),
+		},
+		{
+			name:     "user options",
+			userOpts: []string{"--ipv4-only", "--tcp-ports=9000", "-T", "auto"},
+			expected: append(append([]string{}, base...), "-u", "none", "-U", "none", "--ipv4-only", "--tcp-ports=9000", "-T", "auto"),
+		},
+		{
+			name:  "sctp",
+			ports: []cni.PortMapping{{HostPort: 9, ContainerPort: 9, Protocol: "sctp"}},
+			err:   "not supported by pasta networking",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := pastaArgs("/proc/42/ns/net", "/state/pasta.pid", tc.ports, tc.userOpts)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, args)
+		})
+	}
+}
+
+func TestPastaOptions(t *testing.T) {
+	assert.DeepEqual(t, []string(nil), pastaOptions("pasta"))
+	assert.DeepEqual(t, []string(nil), pastaOptions("pasta:"))
+	assert.DeepEqual(t, []string{"--ipv4-only", "-a", "10.0.2.100"}, pastaOptions("pasta:--ipv4-only,-a,10.0.2.100"))
+}
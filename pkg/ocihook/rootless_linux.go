@@ -18,6 +18,7 @@ package ocihook
 
 import (
 	"context"
+	"fmt"
 
 	rlkclient "github.com/rootless-containers/rootlesskit/v2/pkg/api/client"
 
@@ -33,6 +34,10 @@ func exposePortsRootless(ctx context.Context, rlkClient rlkclient.Client, ports
 	}
 	for _, p := range ports {
 		if err := pm.ExposePort(ctx, p); err != nil {
+			if p.HostPort < 1024 {
+				return fmt.Errorf("failed to expose privileged port %d (Hint: run `sudo nerdctl system check-rootless --fix --privileged-ports`, "+
+					"or set the sysctl net.ipv4.ip_unprivileged_port_start=0): %w", p.HostPort, err)
+			}
 			return err
 		}
 	}
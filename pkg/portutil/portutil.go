@@ -151,15 +151,18 @@ func StoreNetworkConfig(dataStore, namespace, id string, netConf networkstore.Ne
 func LoadPortMappings(dataStore, namespace, id string, containerLabels map[string]string) ([]cni.PortMapping, error) {
 	var ports []cni.PortMapping
 
-	ns, err := networkstore.New(dataStore, namespace, id)
-	if err != nil {
-		return ports, err
-	}
-	if err = ns.Load(); err != nil {
-		return ports, err
-	}
-	if len(ns.NetConf.PortMappings) != 0 {
-		return ns.NetConf.PortMappings, nil
+	// an empty dataStore (e.g., for containerd on an SSH host) only has the legacy labels
+	if dataStore != "" {
+		ns, err := networkstore.New(dataStore, namespace, id)
+		if err != nil {
+			return ports, err
+		}
+		if err = ns.Load(); err != nil {
+			return ports, err
+		}
+		if len(ns.NetConf.PortMappings) != 0 {
+			return ns.NetConf.PortMappings, nil
+		}
 	}
 
 	portsJSON := containerLabels[labels.Ports]
@@ -0,0 +1,41 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package projectquota limits the disk usage of directories with the project quotas of XFS and ext4,
+// like the `size` storage option of the overlay2 driver of dockerd.
+//
+// The filesystem must be mounted with project quotas enabled (the `prjquota` mount option),
+// and Linux 5.14 or later is needed (for quotactl_fd(2)).
+package projectquota
+
+import (
+	"errors"
+)
+
+// ErrNotSupported is returned when the filesystem does not support project quotas.
+var ErrNotSupported = errors.New("project quotas are not supported")
+
+// Quota is the project quota of a directory.
+type Quota struct {
+	// ProjectID is the project of the directory
+	ProjectID uint32
+	// Size is the limit in bytes
+	Size uint64
+	// Used is the current usage in bytes
+	Used uint64
+	// Filesystem is the type of the filesystem enforcing the quota, "xfs" or "ext4"
+	Filesystem string
+}
@@ -0,0 +1,261 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package projectquota
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+)
+
+const (
+	// from linux/fs.h
+	fsXflagProjinherit = 0x00000200
+
+	// from linux/quota.h and linux/dqblk_xfs.h: the XFS quota interface, also implemented by ext4
+	qXGetQuota     = 0x5803 // XQM_CMD(3)
+	qXSetQLim      = 0x5804 // XQM_CMD(4)
+	prjQuota       = 2
+	fsDquotVersion = 1
+	fsProjQuota    = 2 // FS_PROJ_QUOTA
+	fsDqBSoft      = 1 << 2
+	fsDqBHard      = 1 << 3
+	basicBlockSize = 512
+
+	// firstProjectID is the first project allocated, to stay clear of the projects set up by the administrator
+	firstProjectID = 1 << 20
+)
+
+// fsxattr is struct fsxattr of linux/fs.h
+type fsxattr struct {
+	Xflags     uint32
+	Extsize    uint32
+	Nextents   uint32
+	Projid     uint32
+	Cowextsize uint32
+	Pad        [8]byte
+}
+
+// fsDiskQuota is struct fs_disk_quota of linux/dqblk_xfs.h
+type fsDiskQuota struct {
+	Version      int8
+	Flags        int8
+	Fieldmask    uint16
+	ID           uint32
+	BlkHardlimit uint64
+	BlkSoftlimit uint64
+	InoHardlimit uint64
+	InoSoftlimit uint64
+	Bcount       uint64
+	Icount       uint64
+	Itimer       int32
+	Btimer       int32
+	Iwarns       uint16
+	Bwarns       uint16
+	ItimerHi     int8
+	BtimerHi     int8
+	RtbtimerHi   int8
+	Padding2     int8
+	RtbHardlimit uint64
+	RtbSoftlimit uint64
+	Rtbcount     uint64
+	Rtbtimer     int32
+	Rtbwarns     uint16
+	Padding3     int16
+	Padding4     [8]byte
+}
+
+// fsxattrIoctls returns FS_IOC_FSGETXATTR and FS_IOC_FSSETXATTR, which are not defined by x/sys/unix.
+func fsxattrIoctls() (get, set uintptr) {
+	switch runtime.GOARCH {
+	case "ppc64", "ppc64le", "mips", "mipsle", "mips64", "mips64le":
+		// _IOC_READ is 2 and _IOC_WRITE is 4, shifted by 29
+		return 0x401c581f, 0x801c5820
+	}
+	// _IOR('X', 31, struct fsxattr) and _IOW('X', 32, struct fsxattr)
+	return 0x801c581f, 0x401c5820
+}
+
+// filesystemType returns the name of the filesystem of dir, when it supports project quotas.
+func filesystemType(dir string) (string, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return "", err
+	}
+	switch st.Type {
+	case unix.XFS_SUPER_MAGIC:
+		return "xfs", nil
+	case unix.EXT4_SUPER_MAGIC:
+		return "ext4", nil
+	}
+	return "", fmt.Errorf("%w on the filesystem of %q (type 0x%x), only xfs and ext4 are supported", ErrNotSupported, dir, st.Type)
+}
+
+func getProjectID(dir string) (uint32, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	get, _ := fsxattrIoctls()
+	var attr fsxattr
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), get, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return 0, fmt.Errorf("failed to get the project of %q: %w", dir, errno)
+	}
+	return attr.Projid, nil
+}
+
+// setProjectID sets the project of dir, inherited by the files created in dir.
+func setProjectID(dir string, id uint32) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	get, set := fsxattrIoctls()
+	var attr fsxattr
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), get, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return fmt.Errorf("failed to get the project of %q: %w", dir, errno)
+	}
+	attr.Projid = id
+	attr.Xflags |= fsXflagProjinherit
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), set, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return fmt.Errorf("failed to set the project of %q: %w", dir, errno)
+	}
+	return nil
+}
+
+// quotactl calls quotactl_fd(2) for the project quotas, on the filesystem of dir.
+func quotactl(dir string, cmd int, id uint32, q *fsDiskQuota) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	qcmd := cmd<<8 | prjQuota
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL_FD, f.Fd(), uintptr(qcmd), uintptr(id), uintptr(unsafe.Pointer(q)), 0, 0)
+	switch {
+	case errno == 0:
+		return nil
+	case errors.Is(errno, unix.ENOSYS):
+		return fmt.Errorf("%w: quotactl_fd(2) needs Linux 5.14 or later", ErrNotSupported)
+	case errors.Is(errno, unix.ESRCH), errors.Is(errno, unix.ENOTSUP):
+		return fmt.Errorf("%w: the filesystem of %q must be mounted with the prjquota option", ErrNotSupported, dir)
+	}
+	return errno
+}
+
+// nextProjectID returns a project not used by the directories in baseDir, down to depth levels.
+func nextProjectID(baseDir string, depth int) (uint32, error) {
+	next := uint32(firstProjectID)
+	var scan func(dir string, depth int) error
+	scan = func(dir string, depth int) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			p := filepath.Join(dir, e.Name())
+			id, err := getProjectID(p)
+			if err != nil {
+				return err
+			}
+			if id >= next {
+				next = id + 1
+			}
+			if depth > 1 {
+				if err := scan(p, depth-1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return next, scan(baseDir, depth)
+}
+
+// SetQuota limits the disk usage of dir, a directory under baseDir, to size bytes.
+// dir is assigned a project not used by the other directories under baseDir, at the same depth or above,
+// and should be empty, as only the files created after are accounted for.
+func SetQuota(baseDir, dir string, size uint64) error {
+	rel, err := filepath.Rel(baseDir, dir)
+	if err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("%q is not under %q", dir, baseDir)
+	}
+	if _, err := filesystemType(dir); err != nil {
+		return err
+	}
+	lock, err := filesystem.Lock(baseDir)
+	if err != nil {
+		return err
+	}
+	defer filesystem.Unlock(lock)
+	id, err := nextProjectID(baseDir, len(strings.Split(rel, string(filepath.Separator))))
+	if err != nil {
+		return err
+	}
+	blocks := (size + basicBlockSize - 1) / basicBlockSize
+	q := fsDiskQuota{
+		Version:      fsDquotVersion,
+		Flags:        fsProjQuota,
+		Fieldmask:    fsDqBHard | fsDqBSoft,
+		ID:           id,
+		BlkHardlimit: blocks,
+		BlkSoftlimit: blocks,
+	}
+	// set the limit first, so that dir is not left in a project without a limit
+	if err := quotactl(dir, qXSetQLim, id, &q); err != nil {
+		return fmt.Errorf("failed to set the quota of %q: %w", dir, err)
+	}
+	return setProjectID(dir, id)
+}
+
+// GetQuota returns the project quota of dir.
+func GetQuota(dir string) (*Quota, error) {
+	fs, err := filesystemType(dir)
+	if err != nil {
+		return nil, err
+	}
+	id, err := getProjectID(dir)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("%q has no project quota", dir)
+	}
+	var q fsDiskQuota
+	if err := quotactl(dir, qXGetQuota, id, &q); err != nil {
+		return nil, fmt.Errorf("failed to get the quota of %q: %w", dir, err)
+	}
+	return &Quota{
+		ProjectID:  id,
+		Size:       q.BlkHardlimit * basicBlockSize,
+		Used:       q.Bcount * basicBlockSize,
+		Filesystem: fs,
+	}, nil
+}
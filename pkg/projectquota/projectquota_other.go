@@ -0,0 +1,29 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package projectquota
+
+// SetQuota is not supported on non-Linux platforms.
+func SetQuota(baseDir, dir string, size uint64) error {
+	return ErrNotSupported
+}
+
+// GetQuota is not supported on non-Linux platforms.
+func GetQuota(dir string) (*Quota, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,166 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package rootlessutil
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// RequiredCgroupControllers are the cgroup v2 controllers needed for applying the resource limits of containers.
+var RequiredCgroupControllers = []string{"cpu", "cpuset", "io", "memory", "pids"}
+
+// CgroupDelegationDropInPath is the systemd drop-in for delegating RequiredCgroupControllers to user sessions.
+// See https://rootlesscontaine.rs/getting-started/common/cgroup2/
+const CgroupDelegationDropInPath = "/etc/systemd/system/user@.service.d/delegate.conf"
+
+// CgroupDelegationDropIn returns the content of CgroupDelegationDropInPath.
+func CgroupDelegationDropIn() string {
+	return "[Service]\nDelegate=" + strings.Join(RequiredCgroupControllers, " ") + "\n"
+}
+
+// DelegatedCgroupControllers returns the cgroup v2 controllers that systemd delegates to user@<uid>.service.
+func DelegatedCgroupControllers(uid int) ([]string, error) {
+	p := fmt.Sprintf("/sys/fs/cgroup/user.slice/user-%d.slice/user@%d.service/cgroup.controllers", uid, uid)
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(b)), nil
+}
+
+// MissingCgroupControllers returns RequiredCgroupControllers that are not delegated to user@<uid>.service.
+func MissingCgroupControllers(uid int) ([]string, error) {
+	delegated, err := DelegatedCgroupControllers(uid)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, c := range RequiredCgroupControllers {
+		found := false
+		for _, d := range delegated {
+			if c == d {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, c)
+		}
+	}
+	return missing, nil
+}
+
+// SubIDCount returns the number of the subordinate IDs allocated to the user in
+// /etc/subuid or /etc/subgid (specified as path).
+// The user may appear in the file either by name or by uid.
+func SubIDCount(path, name string, uid int) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+	var count int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// name:start:count
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ":")
+		if len(fields) != 3 || (fields[0] != name && fields[0] != strconv.Itoa(uid)) {
+			continue
+		}
+		n, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %q in %s: %w", scanner.Text(), path, err)
+		}
+		count += n
+	}
+	return count, scanner.Err()
+}
+
+// UnprivilegedPortStart returns the value of the sysctl net.ipv4.ip_unprivileged_port_start.
+// Ports below the value cannot be bound without CAP_NET_BIND_SERVICE.
+func UnprivilegedPortStart() (int, error) {
+	b, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// HasFileCapability returns whether the file capabilities of the executable specified as path
+// grant the capability (e.g., unix.CAP_NET_BIND_SERVICE) as effective and permitted.
+func HasFileCapability(path string, capability int) (bool, error) {
+	const (
+		// from linux/capability.h
+		xattrCapsSize        = 24
+		vfsCapFlagsEffective = 0x000001
+	)
+	b := make([]byte, xattrCapsSize)
+	n, err := unix.Getxattr(path, "security.capability", b)
+	if err != nil {
+		if errors.Is(err, unix.ENODATA) {
+			return false, nil
+		}
+		return false, err
+	}
+	// struct vfs_cap_data: magic_etc, then {permitted, inheritable} for each 32 capabilities
+	b = b[:n]
+	if len(b) < 12 {
+		return false, fmt.Errorf("unexpected size of the file capabilities of %s: %d", path, len(b))
+	}
+	magic := binary.LittleEndian.Uint32(b[0:4])
+	if magic&vfsCapFlagsEffective == 0 {
+		return false, nil
+	}
+	off := 4 + 8*(capability/32)
+	if off+4 > len(b) {
+		return false, nil
+	}
+	permitted := binary.LittleEndian.Uint32(b[off : off+4])
+	return permitted&(1<<(capability%32)) != 0, nil
+}
+
+// LookRootlessKit returns the path of the rootlesskit binary, with the symlinks resolved.
+// With sudo, $PATH does not contain the bin directories in the home of the user, so they are looked up too.
+func LookRootlessKit(homeDir string) (string, error) {
+	p, err := exec.LookPath("rootlesskit")
+	if err != nil {
+		for _, dir := range []string{filepath.Join(homeDir, "bin"), filepath.Join(homeDir, ".local", "bin")} {
+			if _, statErr := os.Stat(filepath.Join(dir, "rootlesskit")); statErr == nil {
+				p, err = filepath.Join(dir, "rootlesskit"), nil
+				break
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	// setcap and AppArmor do not follow symlinks
+	return filepath.EvalSymlinks(p)
+}
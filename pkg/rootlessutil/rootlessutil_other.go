@@ -48,6 +48,11 @@ func XDGRuntimeDir() (string, error) {
 	return "", fmt.Errorf("can only query XDG env vars on Linux")
 }
 
+// Always errors out on non-Linux platforms.
+func RuntimeDir() (string, error) {
+	return "", fmt.Errorf("can only query the runtime dir on Linux")
+}
+
 // Always returns -1 on non-Linux platforms.
 func ParentEUID() int {
 	return -1
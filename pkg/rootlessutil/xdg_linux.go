@@ -39,6 +39,15 @@ func XDGRuntimeDir() (string, error) {
 	return "", errors.New("environment variable XDG_RUNTIME_DIR is not set, see https://rootlesscontaine.rs/getting-started/common/login/")
 }
 
+// RuntimeDir returns the directory for the runtime files, which is usually a tmpfs:
+// "/run", or $XDG_RUNTIME_DIR in rootless mode.
+func RuntimeDir() (string, error) {
+	if !IsRootless() {
+		return "/run", nil
+	}
+	return XDGRuntimeDir()
+}
+
 func XDGConfigHome() (string, error) {
 	if xch := os.Getenv("XDG_CONFIG_HOME"); xch != "" {
 		return xch, nil
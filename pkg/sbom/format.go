@@ -0,0 +1,210 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+const (
+	// FormatSPDXJSON is the SPDX 2.3 JSON format
+	FormatSPDXJSON = "spdx-json"
+	// FormatCycloneDX is the CycloneDX 1.5 JSON format
+	FormatCycloneDX = "cyclonedx"
+
+	MediaTypeSPDXJSON  = "application/spdx+json"
+	MediaTypeCycloneDX = "application/vnd.cyclonedx+json"
+)
+
+// Subject describes the image an SBOM is generated for.
+type Subject struct {
+	Name   string
+	Digest string
+}
+
+// Options specifies the metadata of generated documents.
+type Options struct {
+	// ToolVersion is the version of nerdctl
+	ToolVersion string
+	// Created is the creation time of the document (defaults to now)
+	Created time.Time
+}
+
+// MediaType returns the media type of format.
+func MediaType(format string) (string, error) {
+	switch format {
+	case FormatSPDXJSON:
+		return MediaTypeSPDXJSON, nil
+	case FormatCycloneDX:
+		return MediaTypeCycloneDX, nil
+	default:
+		return "", fmt.Errorf("unsupported SBOM format %q (supported: %s, %s)", format, FormatSPDXJSON, FormatCycloneDX)
+	}
+}
+
+// Write writes res as an SBOM document of the given format.
+func Write(w io.Writer, format string, subject Subject, res *Result, opts Options) error {
+	if opts.Created.IsZero() {
+		opts.Created = time.Now()
+	}
+	var doc interface{}
+	switch format {
+	case FormatSPDXJSON:
+		doc = spdxDocument(subject, res, opts)
+	case FormatCycloneDX:
+		doc = cycloneDXDocument(subject, res, opts)
+	default:
+		_, err := MediaType(format)
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+func spdxID(s string) string {
+	return "SPDXRef-" + spdxIDInvalidChars.ReplaceAllString(s, "-")
+}
+
+func spdxDocument(subject Subject, res *Result, opts Options) map[string]interface{} {
+	const noAssertion = "NOASSERTION"
+	imageID := spdxID("Image")
+	packages := []map[string]interface{}{
+		{
+			"name":                  subject.Name,
+			"SPDXID":                imageID,
+			"versionInfo":           subject.Digest,
+			"downloadLocation":      noAssertion,
+			"licenseConcluded":      noAssertion,
+			"licenseDeclared":       noAssertion,
+			"copyrightText":         noAssertion,
+			"primaryPackagePurpose": "CONTAINER",
+		},
+	}
+	relationships := []map[string]interface{}{
+		{
+			"spdxElementId":      "SPDXRef-DOCUMENT",
+			"relationshipType":   "DESCRIBES",
+			"relatedSpdxElement": imageID,
+		},
+	}
+	for i, p := range res.Packages {
+		license := noAssertion
+		if p.License != "" {
+			license = p.License
+		}
+		id := spdxID(fmt.Sprintf("Package-%s-%s-%d", p.Type, p.Name, i))
+		packages = append(packages, map[string]interface{}{
+			"name":             p.Name,
+			"SPDXID":           id,
+			"versionInfo":      p.Version,
+			"downloadLocation": noAssertion,
+			"licenseConcluded": noAssertion,
+			"licenseDeclared":  license,
+			"copyrightText":    noAssertion,
+			"externalRefs": []map[string]string{
+				{
+					"referenceCategory": "PACKAGE-MANAGER",
+					"referenceType":     "purl",
+					"referenceLocator":  p.PURL(res.OS),
+				},
+			},
+		})
+		relationships = append(relationships, map[string]interface{}{
+			"spdxElementId":      imageID,
+			"relationshipType":   "CONTAINS",
+			"relatedSpdxElement": id,
+		})
+	}
+	return map[string]interface{}{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              subject.Name,
+		"documentNamespace": "https://github.com/containerd/nerdctl/spdx/" + spdxIDInvalidChars.ReplaceAllString(subject.Name, "-") + "-" + randomUUID(),
+		"creationInfo": map[string]interface{}{
+			"created":  opts.Created.UTC().Format(time.RFC3339),
+			"creators": []string{"Tool: nerdctl-" + opts.ToolVersion},
+		},
+		"packages":      packages,
+		"relationships": relationships,
+	}
+}
+
+func cycloneDXDocument(subject Subject, res *Result, opts Options) map[string]interface{} {
+	components := []map[string]interface{}{}
+	if res.OS.ID != "" {
+		components = append(components, map[string]interface{}{
+			"type":        "operating-system",
+			"name":        res.OS.ID,
+			"version":     res.OS.VersionID,
+			"description": res.OS.PrettyName,
+		})
+	}
+	for _, p := range res.Packages {
+		purl := p.PURL(res.OS)
+		c := map[string]interface{}{
+			"type":    "library",
+			"bom-ref": purl,
+			"name":    p.Name,
+			"version": p.Version,
+			"purl":    purl,
+		}
+		if p.License != "" {
+			c["licenses"] = []map[string]interface{}{
+				{"expression": p.License},
+			}
+		}
+		components = append(components, c)
+	}
+	return map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": "urn:uuid:" + randomUUID(),
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": opts.Created.UTC().Format(time.RFC3339),
+			"tools": map[string]interface{}{
+				"components": []map[string]interface{}{
+					{"type": "application", "name": "nerdctl", "version": opts.ToolVersion},
+				},
+			},
+			"component": map[string]interface{}{
+				"type":    "container",
+				"name":    subject.Name,
+				"version": subject.Digest,
+			},
+		},
+		"components": components,
+	}
+}
+
+// randomUUID returns a random (version 4) UUID.
+func randomUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(root, name)
+		assert.NilError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.NilError(t, os.WriteFile(p, []byte(data), 0644))
+	}
+}
+
+func TestScanAlpine(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"etc/os-release": "ID=alpine\nVERSION_ID=3.20.3\nPRETTY_NAME=\"Alpine Linux v3.20\"\n",
+		"lib/apk/db/installed": "P:musl\nV:1.2.5-r0\nA:x86_64\nL:MIT\no:musl\n\n" +
+			"P:busybox\nV:1.36.1-r29\nA:x86_64\nL:GPL-2.0-only\n\n",
+	})
+	res, err := Scan(root)
+	assert.NilError(t, err)
+	assert.Equal(t, res.OS.ID, "alpine")
+	assert.Equal(t, res.OS.VersionID, "3.20.3")
+	assert.Equal(t, len(res.Packages), 2)
+	assert.Equal(t, res.Packages[1].Name, "musl")
+	assert.Equal(t, res.Packages[1].Version, "1.2.5-r0")
+	assert.Equal(t, res.Packages[1].License, "MIT")
+	assert.Equal(t, res.Packages[1].PURL(res.OS), "pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64&distro=alpine-3.20.3")
+}
+
+func TestScanDebian(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"usr/lib/os-release": "ID=debian\nVERSION_ID=\"12\"\n",
+		"var/lib/dpkg/status": "Package: bash\nStatus: install ok installed\nArchitecture: amd64\nVersion: 5.2.15-2+b7\n\n" +
+			"Package: removed\nStatus: deinstall ok config-files\nVersion: 1.0\n\n",
+		"var/lib/dpkg/status.d/tzdata": "Package: tzdata\nVersion: 2024a-0+deb12u1\nArchitecture: all\n",
+	})
+	res, err := Scan(root)
+	assert.NilError(t, err)
+	assert.Equal(t, res.OS.ID, "debian")
+	assert.Equal(t, len(res.Packages), 2)
+	assert.Equal(t, res.Packages[0].Name, "bash")
+	assert.Equal(t, res.Packages[1].Name, "tzdata")
+}
+
+func TestWrite(t *testing.T) {
+	res := &Result{
+		OS:       OS{ID: "alpine", VersionID: "3.20.3"},
+		Packages: []Package{{Type: "apk", Name: "musl", Version: "1.2.5-r0", License: "MIT"}},
+	}
+	subject := Subject{Name: "docker.io/library/alpine:3.20", Digest: "sha256:deadbeef"}
+	opts := Options{ToolVersion: "v2.0.0", Created: time.Unix(0, 0)}
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, FormatSPDXJSON, subject, res, opts))
+	var spdx map[string]interface{}
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &spdx))
+	assert.Equal(t, spdx["spdxVersion"], "SPDX-2.3")
+	assert.Equal(t, len(spdx["packages"].([]interface{})), 2)
+	assert.Equal(t, len(spdx["relationships"].([]interface{})), 2)
+
+	buf.Reset()
+	assert.NilError(t, Write(&buf, FormatCycloneDX, subject, res, opts))
+	var cdx map[string]interface{}
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &cdx))
+	assert.Equal(t, cdx["bomFormat"], "CycloneDX")
+	assert.Equal(t, cdx["metadata"].(map[string]interface{})["timestamp"], "1970-01-01T00:00:00Z")
+	assert.Equal(t, len(cdx["components"].([]interface{})), 2)
+
+	assert.ErrorContains(t, Write(&buf, "syft", subject, res, opts), "unsupported SBOM format")
+}
@@ -0,0 +1,274 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sbom generates Software Bill of Materials (SBOM) documents from image root filesystems.
+package sbom
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+// Package is a package installed by an OS package manager.
+type Package struct {
+	// Type is the package type, as used in package URLs (apk, deb)
+	Type    string
+	Name    string
+	Version string
+	Arch    string
+	License string
+	// Source is the name of the source package, when different from Name
+	Source string
+}
+
+// OS is the operating system of a root filesystem, as described by os-release(5).
+type OS struct {
+	ID         string
+	VersionID  string
+	PrettyName string
+}
+
+// Result is the content found in a root filesystem.
+type Result struct {
+	OS       OS
+	Packages []Package
+}
+
+// Scan scans the root filesystem mounted at root for the packages installed by the apk and dpkg package managers.
+// Symbolic links are resolved within root.
+func Scan(root string) (*Result, error) {
+	res := &Result{}
+	for _, p := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		f, err := openInRoot(root, p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		res.OS = parseOSRelease(f)
+		f.Close()
+		break
+	}
+
+	if f, err := openInRoot(root, "/lib/apk/db/installed"); err == nil {
+		pkgs, err := parseApkInstalled(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Packages = append(res.Packages, pkgs...)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	dpkgFiles := []string{"/var/lib/dpkg/status"}
+	// Distroless images record the packages in a file per package
+	if dir, err := securejoin.SecureJoin(root, "/var/lib/dpkg/status.d"); err == nil {
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, e := range entries {
+				if !e.IsDir() && !strings.HasSuffix(e.Name(), ".md5sums") {
+					dpkgFiles = append(dpkgFiles, "/var/lib/dpkg/status.d/"+e.Name())
+				}
+			}
+		}
+	}
+	for _, p := range dpkgFiles {
+		f, err := openInRoot(root, p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		pkgs, err := parseDpkgStatus(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Packages = append(res.Packages, pkgs...)
+	}
+
+	sort.Slice(res.Packages, func(i, j int) bool {
+		if res.Packages[i].Type != res.Packages[j].Type {
+			return res.Packages[i].Type < res.Packages[j].Type
+		}
+		return res.Packages[i].Name < res.Packages[j].Name
+	})
+	return res, nil
+}
+
+func openInRoot(root, p string) (*os.File, error) {
+	resolved, err := securejoin.SecureJoin(root, p)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(resolved)
+}
+
+func parseOSRelease(r io.Reader) OS {
+	var o OS
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(k, "#") {
+			continue
+		}
+		v = strings.Trim(v, `"'`)
+		switch k {
+		case "ID":
+			o.ID = v
+		case "VERSION_ID":
+			o.VersionID = v
+		case "PRETTY_NAME":
+			o.PrettyName = v
+		}
+	}
+	return o
+}
+
+// parseApkInstalled parses the apk database (/lib/apk/db/installed).
+func parseApkInstalled(r io.Reader) ([]Package, error) {
+	var (
+		pkgs []Package
+		cur  Package
+	)
+	flush := func() {
+		if cur.Name != "" {
+			cur.Type = "apk"
+			if cur.Source == cur.Name {
+				cur.Source = ""
+			}
+			pkgs = append(pkgs, cur)
+		}
+		cur = Package{}
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		v := line[2:]
+		switch line[0] {
+		case 'P':
+			cur.Name = v
+		case 'V':
+			cur.Version = v
+		case 'A':
+			cur.Arch = v
+		case 'L':
+			cur.License = v
+		case 'o':
+			cur.Source = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return pkgs, nil
+}
+
+// parseDpkgStatus parses the dpkg database (/var/lib/dpkg/status), skipping the packages that are not installed.
+func parseDpkgStatus(r io.Reader) ([]Package, error) {
+	var (
+		pkgs   []Package
+		cur    Package
+		status string
+	)
+	flush := func() {
+		// Distroless status.d files have no Status field
+		if cur.Name != "" && (status == "" || strings.HasSuffix(status, " installed")) {
+			cur.Type = "deb"
+			pkgs = append(pkgs, cur)
+		}
+		cur = Package{}
+		status = ""
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			// continuation of a multi-line field
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch k {
+		case "Package":
+			cur.Name = v
+		case "Version":
+			cur.Version = v
+		case "Architecture":
+			cur.Arch = v
+		case "Status":
+			status = v
+		case "Source":
+			// "Source: name (version)"
+			cur.Source, _, _ = strings.Cut(v, " ")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return pkgs, nil
+}
+
+// PURL returns the package URL (https://github.com/package-url/purl-spec) of p.
+func (p Package) PURL(o OS) string {
+	namespace := o.ID
+	if namespace == "" {
+		namespace = map[string]string{"apk": "alpine", "deb": "debian"}[p.Type]
+	}
+	s := "pkg:" + p.Type + "/" + namespace + "/" + purlEscape(p.Name) + "@" + purlEscape(p.Version)
+	var qualifiers []string
+	if p.Arch != "" {
+		qualifiers = append(qualifiers, "arch="+purlEscape(p.Arch))
+	}
+	if o.ID != "" && o.VersionID != "" {
+		qualifiers = append(qualifiers, "distro="+purlEscape(o.ID+"-"+o.VersionID))
+	}
+	if p.Source != "" {
+		qualifiers = append(qualifiers, "upstream="+purlEscape(p.Source))
+	}
+	if len(qualifiers) > 0 {
+		s += "?" + strings.Join(qualifiers, "&")
+	}
+	return s
+}
+
+func purlEscape(s string) string {
+	return strings.NewReplacer("%", "%25", "@", "%40", "?", "%3F", "#", "%23", "&", "%26", "=", "%3D", "+", "%2B", " ", "%20").Replace(s)
+}
@@ -0,0 +1,170 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package scanutil runs external vulnerability scanners against image archives.
+package scanutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/containerd/log"
+)
+
+// Severities in ascending order.
+var severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Vulnerability is a vulnerability found by a scanner.
+type Vulnerability struct {
+	ID               string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         string
+}
+
+// Report is the result of a scan.
+type Report struct {
+	Scanner         string
+	Vulnerabilities []Vulnerability
+}
+
+type scanner struct {
+	// args returns the arguments to scan an image archive (`docker save` format).
+	args func(archive string) []string
+	// parse parses the JSON output of the scanner.
+	parse func(out []byte) ([]Vulnerability, error)
+	// hint is printed when the executable is not found.
+	hint string
+}
+
+var scanners = map[string]scanner{
+	"trivy": {
+		args: func(archive string) []string {
+			return []string{"image", "--quiet", "--format", "json", "--input", archive}
+		},
+		parse: parseTrivy,
+		hint:  "https://trivy.dev/latest/getting-started/installation/",
+	},
+	"grype": {
+		args: func(archive string) []string {
+			return []string{"--quiet", "--output", "json", "docker-archive:" + archive}
+		},
+		parse: parseGrype,
+		hint:  "https://github.com/anchore/grype#installation",
+	},
+}
+
+// Scanners returns the names of the supported scanners.
+func Scanners() []string {
+	var names []string
+	for name := range scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Scan runs the scanner `name` against the image archive `archive`.
+func Scan(ctx context.Context, name, archive string) (*Report, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no scanner is configured (Hint: set `scanner` in nerdctl.toml, supported: %s)", strings.Join(Scanners(), ", "))
+	}
+	s, ok := scanners[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scanner %q (supported: %s)", name, strings.Join(Scanners(), ", "))
+	}
+	executable, err := exec.LookPath(name)
+	if err != nil {
+		log.G(ctx).WithError(err).Errorf("%s executable not found in path $PATH", name)
+		log.G(ctx).Infof("you might consider installing %s from: %s", name, s.hint)
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, executable, s.args(archive)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	log.G(ctx).Debugf("running %v", cmd.Args)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w: %s", name, err, stderr.String())
+	}
+	vulns, err := s.parse(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the output of %s: %w", name, err)
+	}
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return severityRank(vulns[i].Severity) > severityRank(vulns[j].Severity)
+	})
+	return &Report{Scanner: name, Vulnerabilities: vulns}, nil
+}
+
+// ValidateSeverity returns an error if s is not a known severity.
+func ValidateSeverity(s string) error {
+	if severityRank(s) < 0 {
+		return fmt.Errorf("unknown severity %q (supported: %s)", s, strings.Join(severities, ", "))
+	}
+	return nil
+}
+
+// NormalizeSeverity maps the severity names used by the scanners to the ones of Trivy.
+func NormalizeSeverity(s string) string {
+	s = strings.ToUpper(s)
+	if s == "NEGLIGIBLE" {
+		return "LOW"
+	}
+	if severityRank(s) < 0 {
+		return "UNKNOWN"
+	}
+	return s
+}
+
+func severityRank(s string) int {
+	for i, sev := range severities {
+		if strings.EqualFold(s, sev) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Exceeding returns the vulnerabilities whose severity is equal to or higher than threshold.
+func (r *Report) Exceeding(threshold string) []Vulnerability {
+	min := severityRank(threshold)
+	var res []Vulnerability
+	for _, v := range r.Vulnerabilities {
+		if severityRank(v.Severity) >= min {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Summary returns the number of vulnerabilities per severity, e.g. "CRITICAL: 1, HIGH: 2".
+func (r *Report) Summary() string {
+	counts := map[string]int{}
+	for _, v := range r.Vulnerabilities {
+		counts[v.Severity]++
+	}
+	var parts []string
+	for i := len(severities) - 1; i >= 0; i-- {
+		parts = append(parts, fmt.Sprintf("%s: %d", severities[i], counts[severities[i]]))
+	}
+	return strings.Join(parts, ", ")
+}
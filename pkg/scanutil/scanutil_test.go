@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package scanutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	trivy := `{"Results":[{"Target":"alpine","Vulnerabilities":[
+		{"VulnerabilityID":"CVE-2024-0001","PkgName":"musl","InstalledVersion":"1.2.4-r0","FixedVersion":"1.2.4-r1","Severity":"HIGH"}]}]}`
+	vulns, err := parseTrivy([]byte(trivy))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vulns, []Vulnerability{{ID: "CVE-2024-0001", Package: "musl", InstalledVersion: "1.2.4-r0", FixedVersion: "1.2.4-r1", Severity: "HIGH"}})
+
+	grype := `{"matches":[{"vulnerability":{"id":"CVE-2024-0002","severity":"Negligible","fix":{"versions":["3.0.1"]}},
+		"artifact":{"name":"openssl","version":"3.0.0"}}]}`
+	vulns, err = parseGrype([]byte(grype))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vulns, []Vulnerability{{ID: "CVE-2024-0002", Package: "openssl", InstalledVersion: "3.0.0", FixedVersion: "3.0.1", Severity: "LOW"}})
+}
+
+func TestExceeding(t *testing.T) {
+	r := &Report{Vulnerabilities: []Vulnerability{
+		{ID: "a", Severity: "CRITICAL"},
+		{ID: "b", Severity: "HIGH"},
+		{ID: "c", Severity: "LOW"},
+	}}
+	assert.Equal(t, len(r.Exceeding("CRITICAL")), 1)
+	assert.Equal(t, len(r.Exceeding("high")), 2)
+	assert.Equal(t, len(r.Exceeding("UNKNOWN")), 3)
+	assert.Equal(t, r.Summary(), "CRITICAL: 1, HIGH: 1, MEDIUM: 0, LOW: 1, UNKNOWN: 0")
+	assert.ErrorContains(t, ValidateSeverity("SEVERE"), "unknown severity")
+}
@@ -0,0 +1,182 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package secretutil writes the secrets of `nerdctl run --secret` to the runtime dir on the host, which is usually a tmpfs,
+// and writes them again when the runtime dir was cleared, e.g., by a reboot.
+//
+// The content of the secrets is never written to the state dir of the container;
+// only the options for reading the content again are recorded there.
+package secretutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// stateFileName is the file in the state dir of the container that records the secrets without their content
+const stateFileName = "secrets.json"
+
+// Secret is a secret bind-mounted into a container.
+type Secret struct {
+	Source string
+	Target string
+	// UID and GID are the owner in the container; -1 if not specified
+	UID int
+	GID int
+	// HostUID and HostGID are the owner on the host, mapped with the user namespace of the container; -1 if not specified
+	HostUID int
+	HostGID int
+	Mode    os.FileMode
+	// File and Provider are used for reading the content again.
+	// The secrets given from an environment variable or a file descriptor cannot be read again.
+	File     string `json:",omitempty"`
+	Provider string `json:",omitempty"`
+	Content  []byte `json:"-"`
+}
+
+// Read reads the content of the secret from its File or its Provider.
+// The Provider is executed as `<PROVIDER> <SOURCE>`, and its standard output is the content.
+func (s *Secret) Read(ctx context.Context) error {
+	var err error
+	switch {
+	case s.File != "":
+		if s.Content, err = os.ReadFile(s.File); err != nil {
+			return fmt.Errorf("secret %q: %w", s.Source, err)
+		}
+	case s.Provider != "":
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, s.Provider, s.Source)
+		cmd.Stderr = &stderr
+		if s.Content, err = cmd.Output(); err != nil {
+			return fmt.Errorf("secret %q: provider %q failed: %w (stderr: %q)", s.Source, s.Provider, err, stderr.String())
+		}
+	default:
+		return fmt.Errorf("secret %q was given from an environment variable or a file descriptor, and cannot be read again", s.Source)
+	}
+	return nil
+}
+
+type state struct {
+	// Dir is the directory of the secret files, bind-mounted into the container
+	Dir     string
+	Secrets []*Secret
+}
+
+// Dir returns the directory of the secrets of the container on the runtime dir:
+// "/run/nerdctl/secrets/<NAMESPACE>/<ID>", or under $XDG_RUNTIME_DIR in rootless mode.
+func Dir(namespace, id string) (string, error) {
+	runtimeDir, err := rootlessutil.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(runtimeDir, "nerdctl", "secrets", namespace, id), nil
+}
+
+// Path returns the path of the i-th secret in dir.
+func Path(dir string, i int) string {
+	return filepath.Join(dir, strconv.Itoa(i))
+}
+
+// Write writes the secrets to dir, and records them without their content in the state dir of the container.
+func Write(stateDir, dir string, secrets []*Secret) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for i, x := range secrets {
+		if err := writeFile(Path(dir, i), x); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(state{Dir: dir, Secrets: secrets})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir, stateFileName), b, 0o600)
+}
+
+// Restore writes the secrets of the container again when they are missing from the runtime dir, e.g., after a reboot.
+// Restore must be called before the task of the container is created, as the secrets are the sources of bind mounts.
+func Restore(ctx context.Context, stateDir string) error {
+	st, err := load(stateDir)
+	if err != nil || st == nil {
+		return err
+	}
+	for i, x := range st.Secrets {
+		p := Path(st.Dir, i)
+		if _, err := os.Stat(p); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := x.Read(ctx); err != nil {
+			return fmt.Errorf("failed to restore the secrets removed from %q (Hint: recreate the container): %w", st.Dir, err)
+		}
+		if err := os.MkdirAll(st.Dir, 0o700); err != nil {
+			return err
+		}
+		if err := writeFile(p, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove removes the secrets of the container from the runtime dir.
+func Remove(stateDir string) error {
+	st, err := load(stateDir)
+	if err != nil || st == nil {
+		return err
+	}
+	return os.RemoveAll(st.Dir)
+}
+
+// load returns nil for the containers without secrets.
+func load(stateDir string) (*state, error) {
+	b, err := os.ReadFile(filepath.Join(stateDir, stateFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var st state
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", stateFileName, err)
+	}
+	return &st, nil
+}
+
+func writeFile(p string, x *Secret) error {
+	if err := os.WriteFile(p, x.Content, 0o600); err != nil {
+		return err
+	}
+	if x.HostUID != -1 || x.HostGID != -1 {
+		if err := os.Chown(p, x.HostUID, x.HostGID); err != nil {
+			return fmt.Errorf("failed to change the owner of secret %q: %w", x.Source, err)
+		}
+	}
+	// set the mode after writing the file, as it is not affected by the umask
+	return os.Chmod(p, x.Mode)
+}
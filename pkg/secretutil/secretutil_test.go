@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package secretutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	stateDir, dir := t.TempDir(), filepath.Join(t.TempDir(), "secrets")
+	file := filepath.Join(t.TempDir(), "dbpass.txt")
+	assert.NilError(t, os.WriteFile(file, []byte("s3cr3t"), 0o600))
+
+	secrets := []*Secret{
+		{Source: "dbpass", HostUID: -1, HostGID: -1, Mode: 0o400, File: file, Content: []byte("s3cr3t")},
+		{Source: "token", HostUID: -1, HostGID: -1, Mode: 0o444, Content: []byte("from-env")},
+	}
+	assert.NilError(t, Write(stateDir, dir, secrets))
+	b, err := os.ReadFile(filepath.Join(stateDir, stateFileName))
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(b), "s3cr3t"))
+
+	// nothing to restore
+	assert.NilError(t, Restore(ctx, stateDir))
+
+	// the secret from a file is read again
+	assert.NilError(t, os.Remove(Path(dir, 0)))
+	assert.NilError(t, Restore(ctx, stateDir))
+	b, err = os.ReadFile(Path(dir, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "s3cr3t")
+	st, err := os.Stat(Path(dir, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, st.Mode().Perm(), os.FileMode(0o400))
+
+	// the secret from an environment variable cannot be read again
+	assert.NilError(t, os.RemoveAll(dir))
+	assert.ErrorContains(t, Restore(ctx, stateDir), "cannot be read again")
+
+	assert.NilError(t, Remove(stateDir))
+	_, err = os.Stat(dir)
+	assert.Assert(t, os.IsNotExist(err))
+
+	// no secrets
+	assert.NilError(t, Restore(ctx, t.TempDir()))
+	assert.NilError(t, Remove(t.TempDir()))
+}
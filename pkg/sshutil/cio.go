@@ -0,0 +1,267 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sshutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// NewCreator returns the cio.Creator for the processes on the host, like cio.NewCreator.
+// The FIFOs are created in a temporary directory on the host, and the streams are copied from/to them over ssh(1),
+// with cat(1) on the host.
+func (h *Host) NewCreator(opts ...cio.Opt) cio.Creator {
+	streams := newStreams(opts)
+	return func(id string) (cio.IO, error) {
+		config := cio.Config{Terminal: streams.Terminal}
+		// the directory is only known after mktemp(1), so the placeholders are replaced later
+		if streams.Stdin != nil {
+			config.Stdin = "stdin"
+		}
+		if streams.Stdout != nil {
+			config.Stdout = "stdout"
+		}
+		if streams.Stderr != nil && !streams.Terminal {
+			config.Stderr = "stderr"
+		}
+		return h.copyIO(config, streams, true)
+	}
+}
+
+// NewAttach returns the cio.Attach for the processes on the host, like cio.NewAttach.
+// The existing FIFOs on the host are copied from/to over ssh(1), with cat(1) on the host.
+func (h *Host) NewAttach(opts ...cio.Opt) cio.Attach {
+	streams := newStreams(opts)
+	return func(fifos *cio.FIFOSet) (cio.IO, error) {
+		if fifos == nil {
+			return nil, errors.New("cannot attach, missing fifos")
+		}
+		config := fifos.Config
+		if streams.Stdin == nil {
+			config.Stdin = ""
+		}
+		if streams.Stdout == nil {
+			config.Stdout = ""
+		}
+		if streams.Stderr == nil || config.Terminal {
+			config.Stderr = ""
+		}
+		return h.copyIO(config, streams, false)
+	}
+}
+
+func newStreams(opts []cio.Opt) *cio.Streams {
+	streams := &cio.Streams{}
+	for _, opt := range opts {
+		opt(streams)
+	}
+	return streams
+}
+
+// copyScript returns the shell script copying the FIFOs of config from/to the stdio of the script.
+// With mkfifo, the FIFOs are created in a temporary directory, and the paths in config are the names in the directory.
+// The first line of stdout is the directory (empty without mkfifo), followed by the content of the stdout FIFO.
+func copyScript(config cio.Config, mkfifo bool) string {
+	var b strings.Builder
+	if mkfifo {
+		b.WriteString(`d=$(mktemp -d "${TMPDIR:-/tmp}/nerdctl-fifo.XXXXXX") || exit 1` + "\n")
+		b.WriteString(`trap 'rm -rf "$d"' EXIT` + "\n")
+		b.WriteString("cd \"$d\" || exit 1\n")
+		var names []string
+		for _, name := range []string{config.Stdin, config.Stdout, config.Stderr} {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			b.WriteString("mkfifo " + strings.Join(names, " ") + " || exit 1\n")
+		}
+		b.WriteString("pwd\n")
+	} else {
+		b.WriteString("echo\n")
+	}
+	var pids []string
+	if config.Stdin != "" {
+		// the stdin of the asynchronous commands is /dev/null, unless redirected explicitly
+		b.WriteString("exec 3<&0\n")
+		b.WriteString("cat <&3 >" + strutil.ShellQuote(config.Stdin) + " & i=$!\n")
+	}
+	if config.Stdout != "" {
+		b.WriteString("cat " + strutil.ShellQuote(config.Stdout) + " & o=$!\n")
+		pids = append(pids, "$o")
+	}
+	if config.Stderr != "" {
+		b.WriteString("cat " + strutil.ShellQuote(config.Stderr) + " >&2 & e=$!\n")
+		pids = append(pids, "$e")
+	}
+	if len(pids) == 0 && config.Stdin != "" {
+		pids = append(pids, "$i")
+	}
+	if len(pids) > 0 {
+		b.WriteString("wait " + strings.Join(pids, " ") + "\n")
+	}
+	return shellCommandLine(b.String())
+}
+
+// unblockScript returns the shell script to unblock cat(1) in copyScript blocked in opening the FIFOs in dir, and to
+// remove dir. It is used when copyScript has not exited, e.g., when the process failed to start.
+func unblockScript(dir string) string {
+	return shellCommandLine("cd " + strutil.ShellQuote(dir) + " || exit 0\n" +
+		"for f in *; do [ -p \"$f\" ] && { exec 4<>\"$f\"; exec 4>&-; }; done\n" +
+		"cd / && rm -rf " + strutil.ShellQuote(dir) + "\n")
+}
+
+// shellCommandLine returns the command line running the POSIX shell script, regardless of the login shell of the user.
+func shellCommandLine(script string) string {
+	return "sh -c " + strutil.ShellQuote(script)
+}
+
+func (h *Host) copyIO(config cio.Config, streams *cio.Streams, mkfifo bool) (cio.IO, error) {
+	cmd, err := h.Command(nil, copyScript(config, mkfifo))
+	if err != nil {
+		return nil, err
+	}
+	if streams.Stderr != nil && !config.Terminal {
+		cmd.Stderr = streams.Stderr
+	} else {
+		// for the messages of ssh itself
+		cmd.Stderr = os.Stderr
+	}
+	var stdin io.WriteCloser
+	if config.Stdin != "" {
+		if stdin, err = cmd.StdinPipe(); err != nil {
+			return nil, err
+		}
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	log.L.Debugf("running %v", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %v: %w", cmd.Args, err)
+	}
+	r := bufio.NewReader(stdout)
+	dir, err := r.ReadString('\n')
+	if err != nil {
+		killProcessGroup(cmd)
+		cmd.Wait()
+		return nil, fmt.Errorf("failed to set up the stdio of the process over SSH: %w", err)
+	}
+	rio := &remoteIO{
+		host:   h,
+		config: config,
+		cmd:    cmd,
+		dir:    strings.TrimSuffix(dir, "\n"),
+		exited: make(chan struct{}),
+	}
+	if rio.dir != "" {
+		for _, p := range []*string{&rio.config.Stdin, &rio.config.Stdout, &rio.config.Stderr} {
+			if *p != "" {
+				*p = path.Join(rio.dir, *p)
+			}
+		}
+	}
+	if stdin != nil {
+		// not waited for, as the reader (e.g., os.Stdin) may block forever
+		go func() {
+			io.Copy(stdin, streams.Stdin)
+			stdin.Close()
+		}()
+	}
+	rio.wg.Add(1)
+	go func() {
+		defer rio.wg.Done()
+		out := streams.Stdout
+		if out == nil {
+			out = io.Discard
+		}
+		if _, err := io.Copy(out, r); err != nil {
+			// keep reading, so that ssh does not block on writing
+			io.Copy(io.Discard, r)
+		}
+		if err := cmd.Wait(); err == nil {
+			rio.done = true
+		}
+		close(rio.exited)
+	}()
+	return rio, nil
+}
+
+// remoteIO is the cio.IO copying the FIFOs on the host over ssh(1).
+type remoteIO struct {
+	host   *Host
+	config cio.Config
+	cmd    *exec.Cmd
+	// dir is the temporary directory of the FIFOs, or "" for attaching to the existing FIFOs
+	dir string
+	wg  sync.WaitGroup
+	// done is true when the copying script exited successfully, with dir removed
+	done bool
+	// exited is closed when ssh exited
+	exited    chan struct{}
+	closeOnce sync.Once
+}
+
+func (r *remoteIO) Config() cio.Config {
+	return r.config
+}
+
+func (r *remoteIO) Cancel() {
+	select {
+	case <-r.exited:
+	default:
+		killProcessGroup(r.cmd)
+	}
+}
+
+func (r *remoteIO) Wait() {
+	r.wg.Wait()
+}
+
+func (r *remoteIO) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		r.Cancel()
+		r.Wait()
+		if r.dir == "" || r.done {
+			return
+		}
+		var cmd *exec.Cmd
+		cmd, err = r.host.Command(nil, unblockScript(r.dir))
+		if err != nil {
+			return
+		}
+		cmd.Stderr = os.Stderr
+		if err = cmd.Run(); err != nil {
+			err = fmt.Errorf("failed to remove the FIFOs in %q on the SSH host: %w", r.dir, err)
+		}
+	})
+	return err
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sshutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/pkg/cio"
+)
+
+// fakeSSH puts the ssh command running the command line locally on $PATH.
+func fakeSSH(t *testing.T) *Host {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--) shift 2; break ;;
+	-o | -p | -W) shift 2 ;;
+	*) shift ;;
+	esac
+done
+exec sh -c "$1"
+`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return &Host{Destination: "example.com", Socket: DefaultSocket}
+}
+
+func TestNewCreator(t *testing.T) {
+	h := fakeSSH(t)
+	var stdout, stderr bytes.Buffer
+	rio, err := h.NewCreator(cio.WithStreams(strings.NewReader("hello"), &stdout, &stderr))("test")
+	assert.NilError(t, err)
+	config := rio.Config()
+	dir := filepath.Dir(config.Stdin)
+	assert.Equal(t, filepath.Dir(config.Stdout), dir)
+	assert.Equal(t, filepath.Dir(config.Stderr), dir)
+
+	// act as the shim
+	in, err := os.OpenFile(config.Stdin, os.O_RDONLY, 0)
+	assert.NilError(t, err)
+	b, err := io.ReadAll(in)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "hello")
+	in.Close()
+	for _, f := range []struct{ path, content string }{{config.Stdout, "out"}, {config.Stderr, "err"}} {
+		w, err := os.OpenFile(f.path, os.O_WRONLY, 0)
+		assert.NilError(t, err)
+		_, err = w.WriteString(f.content)
+		assert.NilError(t, err)
+		w.Close()
+	}
+
+	rio.Wait()
+	assert.NilError(t, rio.Close())
+	assert.Equal(t, stdout.String(), "out")
+	assert.Equal(t, stderr.String(), "err")
+	_, err = os.Stat(dir)
+	assert.Assert(t, os.IsNotExist(err), "the FIFOs have to be removed")
+}
+
+func TestNewCreatorCancel(t *testing.T) {
+	h := fakeSSH(t)
+	var stdout bytes.Buffer
+	rio, err := h.NewCreator(cio.WithStreams(nil, &stdout, nil), cio.WithTerminal)("test")
+	assert.NilError(t, err)
+	config := rio.Config()
+	assert.Equal(t, config.Stdin, "")
+	assert.Equal(t, config.Stderr, "")
+
+	// the process never opens the FIFOs
+	rio.Cancel()
+	rio.Wait()
+	assert.NilError(t, rio.Close())
+	_, err = os.Stat(filepath.Dir(config.Stdout))
+	assert.Assert(t, os.IsNotExist(err), "the FIFOs have to be removed")
+}
+
+func TestNewAttach(t *testing.T) {
+	h := fakeSSH(t)
+	fifo := filepath.Join(t.TempDir(), "stdout")
+	assert.NilError(t, exec.Command("mkfifo", fifo).Run())
+	var stdout bytes.Buffer
+	rio, err := h.NewAttach(cio.WithStreams(nil, &stdout, nil))(cio.NewFIFOSet(cio.Config{Stdin: "unused", Stdout: fifo, Stderr: "unused"}, nil))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rio.Config(), cio.Config{Stdout: fifo})
+
+	assert.NilError(t, os.WriteFile(fifo, []byte("out"), 0))
+	rio.Wait()
+	assert.NilError(t, rio.Close())
+	assert.Equal(t, stdout.String(), "out")
+	_, err = os.Stat(fifo)
+	assert.NilError(t, err, "the existing FIFOs must not be removed")
+}
@@ -0,0 +1,213 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sshutil connects to containerd on a remote host over SSH, with the ssh(1) command.
+// No daemon other than sshd and containerd is needed on the remote host.
+package sshutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/log"
+)
+
+const (
+	// Scheme is the prefix of the SSH addresses, e.g., "ssh://user@example.com".
+	Scheme = "ssh://"
+	// DefaultSocket is the path of the containerd socket on the remote host, when the address has no path.
+	DefaultSocket = "/run/containerd/containerd.sock"
+)
+
+// Host is the remote host of containerd.
+type Host struct {
+	// Destination is "[USER@]HOST" for ssh(1)
+	Destination string
+	// Port is the port of ssh(1), or "" for the default
+	Port string
+	// Socket is the path of the containerd socket on the remote host
+	Socket string
+}
+
+// IsAddress returns whether address is an SSH address, e.g., "ssh://user@example.com".
+func IsAddress(address string) bool {
+	return strings.HasPrefix(address, Scheme)
+}
+
+// ParseHost parses "[ssh://][USER@]HOST[:PORT]" into the destination ("[USER@]HOST") and the port of ssh(1).
+func ParseHost(s string) (destination, port string, err error) {
+	u, err := parse(s)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Path != "" && u.Path != "/" {
+		return "", "", fmt.Errorf("invalid SSH host %q: paths and queries are not supported", s)
+	}
+	return destinationOf(u), u.Port(), nil
+}
+
+// ParseAddress parses the SSH address "ssh://[USER@]HOST[:PORT][/PATH/TO/containerd.sock]".
+func ParseAddress(address string) (*Host, error) {
+	if !IsAddress(address) {
+		return nil, fmt.Errorf("invalid SSH address %q: expected %q prefix", address, Scheme)
+	}
+	u, err := parse(address)
+	if err != nil {
+		return nil, err
+	}
+	h := &Host{
+		Destination: destinationOf(u),
+		Port:        u.Port(),
+		Socket:      u.Path,
+	}
+	if h.Socket == "" || h.Socket == "/" {
+		h.Socket = DefaultSocket
+	}
+	return h, nil
+}
+
+func parse(s string) (*url.URL, error) {
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = Scheme + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH host %q: %w", s, err)
+	}
+	if u.Scheme != "ssh" {
+		return nil, fmt.Errorf("invalid SSH host %q: unsupported scheme %q", s, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid SSH host %q: no host name", s)
+	}
+	if u.RawQuery != "" {
+		return nil, fmt.Errorf("invalid SSH host %q: queries are not supported", s)
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return nil, fmt.Errorf("invalid SSH host %q: passwords are not supported, use ssh keys or ssh-agent", s)
+		}
+	}
+	return u, nil
+}
+
+func destinationOf(u *url.URL) string {
+	if u.User != nil {
+		return u.User.Username() + "@" + u.Hostname()
+	}
+	return u.Hostname()
+}
+
+// Command returns the ssh(1) command running the shell command line on the host.
+// ssh runs in batch mode, as its stdin and stdout are used for the data, and in its own process group,
+// so that Ctrl-C on the terminal is only handled by nerdctl, e.g., to forward the signal to the container.
+// The host has to be in known_hosts, and the authentication has to be done without passwords.
+func (h *Host) Command(sshArgs []string, commandLine string) (*exec.Cmd, error) {
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return nil, fmt.Errorf("ssh is needed for the SSH address: %w", err)
+	}
+	args := []string{"-o", "BatchMode=yes"}
+	if h.Port != "" {
+		args = append(args, "-p", h.Port)
+	}
+	args = append(args, sshArgs...)
+	args = append(args, "--", h.Destination)
+	if commandLine != "" {
+		args = append(args, commandLine)
+	}
+	cmd := exec.Command(sshPath, args...)
+	setProcessGroup(cmd)
+	return cmd, nil
+}
+
+// Dial connects to the containerd socket on the host, with the stdio forwarding of ssh(1) (`ssh -W`).
+// It is used as the dialer of gRPC, so addr is ignored.
+func (h *Host) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	// not exec.CommandContext, as ctx is only for the dialing
+	cmd, err := h.Command([]string{"-W", h.Socket}, "")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	log.G(ctx).Debugf("running %v", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %v: %w", cmd.Args, err)
+	}
+	return &commandConn{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: stdout,
+		remote: &net.UnixAddr{Name: h.Destination + ":" + h.Socket, Net: "unix"},
+	}, nil
+}
+
+// commandConn is a net.Conn over the stdio of ssh(1).
+type commandConn struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	remote    net.Addr
+	closeOnce sync.Once
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		killProcessGroup(c.cmd)
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr {
+	return &net.UnixAddr{Name: "ssh", Net: "unix"}
+}
+
+func (c *commandConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// The deadlines are not supported, as in the connections over the stdio of the other commands.
+
+func (c *commandConn) SetDeadline(time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(time.Time) error { return nil }
@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The keys of SortEntries.
+const (
+	SortCPU   = "cpu"
+	SortMem   = "mem"
+	SortNet   = "net"
+	SortBlock = "block"
+	SortPIDs  = "pids"
+	SortName  = "name"
+	SortID    = "id"
+)
+
+// SortKeys are the keys supported by SortEntries.
+var SortKeys = []string{SortCPU, SortMem, SortNet, SortBlock, SortPIDs, SortName, SortID}
+
+// SortEntries sorts entries by key: the usages (cpu, mem, net, block, pids) in descending order,
+// and name and id in ascending order. reverse reverses the order.
+// An empty key keeps the order of entries.
+func SortEntries(entries []StatsEntry, key string, reverse bool) error {
+	var less func(a, b *StatsEntry) bool
+	switch key {
+	case "":
+		return nil
+	case SortCPU:
+		less = func(a, b *StatsEntry) bool { return a.CPUPercentage > b.CPUPercentage }
+	case SortMem:
+		less = func(a, b *StatsEntry) bool { return a.Memory > b.Memory }
+	case SortNet:
+		less = func(a, b *StatsEntry) bool { return a.NetworkRx+a.NetworkTx > b.NetworkRx+b.NetworkTx }
+	case SortBlock:
+		less = func(a, b *StatsEntry) bool { return a.BlockRead+a.BlockWrite > b.BlockRead+b.BlockWrite }
+	case SortPIDs:
+		less = func(a, b *StatsEntry) bool { return a.PidsCurrent > b.PidsCurrent }
+	case SortName:
+		less = func(a, b *StatsEntry) bool { return a.Name < b.Name }
+	case SortID:
+		less = func(a, b *StatsEntry) bool { return a.ID < b.ID }
+	default:
+		return fmt.Errorf("unknown sort key %q, expected one of %s", key, strings.Join(SortKeys, ", "))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if reverse {
+			return less(&entries[j], &entries[i])
+		}
+		return less(&entries[i], &entries[j])
+	})
+	return nil
+}
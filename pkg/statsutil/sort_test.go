@@ -0,0 +1,64 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSortEntries(t *testing.T) {
+	entries := func() []StatsEntry {
+		return []StatsEntry{
+			{ID: "b", Name: "web", CPUPercentage: 10, Memory: 300, NetworkRx: 1, PidsCurrent: 2},
+			{ID: "c", Name: "db", CPUPercentage: 50, Memory: 100, NetworkRx: 5, NetworkTx: 5, PidsCurrent: 20},
+			{ID: "a", Name: "cache", CPUPercentage: 30, Memory: 200, BlockRead: 7, PidsCurrent: 2},
+		}
+	}
+	ids := func(entries []StatsEntry) []string {
+		var res []string
+		for _, e := range entries {
+			res = append(res, e.ID)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		key      string
+		reverse  bool
+		expected []string
+	}{
+		{"", false, []string{"b", "c", "a"}},
+		{SortCPU, false, []string{"c", "a", "b"}},
+		{SortCPU, true, []string{"b", "a", "c"}},
+		{SortMem, false, []string{"b", "a", "c"}},
+		{SortNet, false, []string{"c", "b", "a"}},
+		{SortBlock, false, []string{"a", "b", "c"}},
+		// stable for the containers with the same number of PIDs
+		{SortPIDs, false, []string{"c", "b", "a"}},
+		{SortName, false, []string{"a", "c", "b"}},
+		{SortID, false, []string{"a", "b", "c"}},
+	}
+	for _, tc := range testCases {
+		e := entries()
+		assert.NilError(t, SortEntries(e, tc.key, tc.reverse))
+		assert.DeepEqual(t, ids(e), tc.expected)
+	}
+
+	assert.ErrorContains(t, SortEntries(entries(), "foo", false), "unknown sort key")
+}
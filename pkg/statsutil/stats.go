@@ -47,16 +47,19 @@ type StatsEntry struct {
 	IsInvalid        bool
 }
 
-// FormattedStatsEntry represents a formatted StatsEntry
+// FormattedStatsEntry represents a formatted StatsEntry.
+// The fields are compatible with `docker stats --format json`.
 type FormattedStatsEntry struct {
-	Name     string
-	ID       string
-	CPUPerc  string
-	MemUsage string
-	MemPerc  string
-	NetIO    string
-	BlockIO  string
-	PIDs     string
+	// Container is the container name, or the ID when the container has no name
+	Container string
+	Name      string
+	ID        string
+	CPUPerc   string
+	MemUsage  string
+	MemPerc   string
+	NetIO     string
+	BlockIO   string
+	PIDs      string
 }
 
 // Stats represents an entity to store containers statistics synchronously
@@ -133,15 +136,20 @@ func (cs *Stats) SetError(err error) {
 
 // Rendering a FormattedStatsEntry from StatsEntry
 func RenderEntry(in *StatsEntry, noTrunc bool) FormattedStatsEntry {
+	container := in.Name
+	if container == "" {
+		container = in.EntryID(noTrunc)
+	}
 	return FormattedStatsEntry{
-		Name:     in.EntryName(noTrunc),
-		ID:       in.EntryID(noTrunc),
-		CPUPerc:  in.CPUPerc(),
-		MemUsage: in.MemUsage(),
-		MemPerc:  in.MemPerc(),
-		NetIO:    in.NetIO(),
-		BlockIO:  in.BlockIO(),
-		PIDs:     in.PIDs(),
+		Container: container,
+		Name:      in.EntryName(noTrunc),
+		ID:        in.EntryID(noTrunc),
+		CPUPerc:   in.CPUPerc(),
+		MemUsage:  in.MemUsage(),
+		MemPerc:   in.MemPerc(),
+		NetIO:     in.NetIO(),
+		BlockIO:   in.BlockIO(),
+		PIDs:      in.PIDs(),
 	}
 }
 
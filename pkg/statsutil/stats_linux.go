@@ -18,12 +18,17 @@ package statsutil
 
 import (
 	"bufio"
+	"fmt"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
 
 	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
 	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
@@ -65,9 +70,9 @@ func SetCgroup2StatsFields(previousStats *ContainerStats, metrics *v2.Metrics, l
 	cpuPercent := calculateCgroup2CPUPercent(previousStats, metrics)
 	blkRead, blkWrite := calculateCgroup2IO(metrics)
 	mem := calculateCgroup2MemUsage(metrics)
-	memLimit := getCgroupMemLimit(float64(metrics.Memory.UsageLimit))
+	memLimit := getCgroupMemLimit(float64(metrics.GetMemory().GetUsageLimit()))
 	memPercent := calculateMemPercent(memLimit, mem)
-	pidsStatsCurrent := metrics.Pids.Current
+	pidsStatsCurrent := metrics.GetPids().GetCurrent()
 	netRx, netTx := calculateCgroupNetwork(links)
 
 	return StatsEntry{
@@ -138,9 +143,9 @@ func calculateCgroup2CPUPercent(previousStats *ContainerStats, metrics *v2.Metri
 	var (
 		cpuPercent = 0.0
 		// calculate the change for the cpu usage of the container in between readings
-		cpuDelta = float64(metrics.CPU.UsageUsec*1000) - float64(previousStats.Cgroup2CPU)
+		cpuDelta = float64(metrics.GetCPU().GetUsageUsec()*1000) - float64(previousStats.Cgroup2CPU)
 		// calculate the change for the entire system between readings
-		_ = float64(metrics.CPU.SystemUsec*1000) - float64(previousStats.Cgroup2System)
+		_ = float64(metrics.GetCPU().GetSystemUsec()*1000) - float64(previousStats.Cgroup2System)
 		// time duration
 		timeDelta = time.Since(previousStats.Time)
 	)
@@ -158,10 +163,11 @@ func calculateCgroupMemUsage(metrics *v1.Metrics) float64 {
 }
 
 func calculateCgroup2MemUsage(metrics *v2.Metrics) float64 {
-	if v := metrics.Memory.InactiveFile; v < metrics.Memory.Usage {
-		return float64(metrics.Memory.Usage - v)
+	mem := metrics.GetMemory()
+	if v := mem.GetInactiveFile(); v < mem.GetUsage() {
+		return float64(mem.GetUsage() - v)
 	}
-	return float64(metrics.Memory.Usage)
+	return float64(mem.GetUsage())
 }
 
 func calculateCgroupBlockIO(metrics *v1.Metrics) (uint64, uint64) {
@@ -183,7 +189,7 @@ func calculateCgroupBlockIO(metrics *v1.Metrics) (uint64, uint64) {
 func calculateCgroup2IO(metrics *v2.Metrics) (uint64, uint64) {
 	var ioRead, ioWrite uint64
 
-	for _, iOEntry := range metrics.Io.Usage {
+	for _, iOEntry := range metrics.GetIo().GetUsage() {
 		if iOEntry.Rios == 0 && iOEntry.Wios == 0 {
 			continue
 		}
@@ -212,3 +218,122 @@ func calculateCgroupNetwork(links []netlink.Link) (float64, float64) {
 	}
 	return rx, tx
 }
+
+// NetworkLinks returns the network interfaces in the network namespace of the process,
+// except the inactive and the loopback interfaces.
+func NetworkLinks(pid int) ([]netlink.Link, error) {
+	ns, err := netns.GetFromPid(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the statistics in netns of process %d: %w", pid, err)
+	}
+	defer ns.Close()
+
+	nlHandle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the statistics in netns %s: %w", ns, err)
+	}
+	defer nlHandle.Close()
+
+	links, err := nlHandle.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the statistics in netns %s: %w", ns, err)
+	}
+	var res []netlink.Link
+	for _, l := range links {
+		//exclude inactive interface
+		if l.Attrs().Flags&net.FlagUp == 0 {
+			continue
+		}
+		//exclude loopback interface
+		if l.Attrs().Flags&net.FlagLoopback != 0 || strings.HasPrefix(l.Attrs().Name, "lo") {
+			continue
+		}
+		res = append(res, l)
+	}
+	return res, nil
+}
+
+// CompleteCgroup2Metrics fills in the block I/O and the PIDs statistics missing from the metrics reported by containerd,
+// by reading io.stat and pids.current of the cgroup v2 of the process.
+// The statistics may be missing when the io and pids controllers are not enabled for the containerd shim.
+func CompleteCgroup2Metrics(metrics *v2.Metrics, pid int) error {
+	if len(metrics.GetIo().GetUsage()) > 0 && metrics.GetPids() != nil {
+		return nil
+	}
+	dir, err := cgroup2Dir(pid)
+	if err != nil {
+		return err
+	}
+	if len(metrics.GetIo().GetUsage()) == 0 {
+		if f, err := os.Open(filepath.Join(dir, "io.stat")); err == nil {
+			entries, err := parseCgroup2IOStat(f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			metrics.Io = &v2.IOStat{Usage: entries}
+		}
+	}
+	if metrics.GetPids() == nil {
+		if b, err := os.ReadFile(filepath.Join(dir, "pids.current")); err == nil {
+			current, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+			if err != nil {
+				return err
+			}
+			metrics.Pids = &v2.PidsStat{Current: current}
+		}
+	}
+	return nil
+}
+
+// cgroup2Dir returns the directory of the cgroup v2 of the process, from /proc/<pid>/cgroup.
+func cgroup2Dir(pid int) (string, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return filepath.Join("/sys/fs/cgroup", path), nil
+		}
+	}
+	return "", fmt.Errorf("cgroup v2 of process %d not found", pid)
+}
+
+// parseCgroup2IOStat parses io.stat of cgroup v2, e.g., "8:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0".
+func parseCgroup2IOStat(r io.Reader) ([]*v2.IOEntry, error) {
+	var entries []*v2.IOEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var entry v2.IOEntry
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &entry.Major, &entry.Minor); err != nil {
+			return nil, fmt.Errorf("invalid device %q in io.stat: %w", fields[0], err)
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q in io.stat: %w", field, err)
+			}
+			switch key {
+			case "rbytes":
+				entry.Rbytes = v
+			case "wbytes":
+				entry.Wbytes = v
+			case "rios":
+				entry.Rios = v
+			case "wios":
+				entry.Wios = v
+			}
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,52 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
+)
+
+func TestParseCgroup2IOStat(t *testing.T) {
+	ioStat := `8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0
+253:1 rbytes=100 wbytes=0 rios=3 wios=0 dbytes=0 dios=0
+`
+	entries, err := parseCgroup2IOStat(strings.NewReader(ioStat))
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Major, uint64(8))
+	assert.Equal(t, entries[1].Minor, uint64(1))
+
+	read, write := calculateCgroup2IO(&v2.Metrics{Io: &v2.IOStat{Usage: entries}})
+	assert.Equal(t, read, uint64(4196))
+	assert.Equal(t, write, uint64(8192))
+
+	_, err = parseCgroup2IOStat(strings.NewReader("invalid rbytes=1\n"))
+	assert.ErrorContains(t, err, "invalid device")
+}
+
+func TestSetCgroup2StatsFieldsWithoutControllers(t *testing.T) {
+	// the statistics of the controllers that are not enabled are missing from the metrics
+	entry, err := SetCgroup2StatsFields(&ContainerStats{}, &v2.Metrics{}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, entry.PidsCurrent, uint64(0))
+	assert.Equal(t, entry.BlockRead, float64(0))
+}
@@ -305,3 +305,9 @@ func TestParseCSVMap(t *testing.T) {
 		})
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, ShellQuote("/run/containerd/containerd.sock"), "/run/containerd/containerd.sock")
+	assert.Equal(t, ShellQuote("it's"), `'it'"'"'s'`)
+	assert.Equal(t, ShellQuote(""), "''")
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package tracingutil exports the OpenTelemetry traces of a nerdctl command via OTLP/HTTP.
+//
+// The spans created by the containerd client (e.g., pull, fetch, unpack, task creation) are
+// recorded under the root span of the command, as the global tracer provider is set by Init.
+package tracingutil
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/version"
+)
+
+const (
+	tracerName   = "github.com/containerd/nerdctl/v2"
+	serviceName  = "nerdctl"
+	flushTimeout = 5 * time.Second
+)
+
+var (
+	provider *sdktrace.TracerProvider
+	rootSpan trace.Span
+)
+
+// Enabled returns true when the traces are exported, either to endpoint or to the
+// endpoint specified by the OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) env var.
+func Enabled(endpoint string) bool {
+	if endpoint != "" {
+		return true
+	}
+	for _, k := range []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"} {
+		if os.Getenv(k) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Init sets up the global tracer provider and starts the root span named spanName.
+// Init is a no-op unless Enabled(endpoint) is true.
+// Shutdown must be called to end the root span and to flush the spans.
+func Init(ctx context.Context, endpoint, spanName string) (context.Context, error) {
+	if !Enabled(endpoint) || provider != nil {
+		return ctx, nil
+	}
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return ctx, err
+	}
+	// Schemaless, so that the resource does not conflict with the schema of resource.Default on upgrading the SDK
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.GetVersion()),
+	))
+	if err != nil {
+		return ctx, err
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	// Respect the trace context of the parent process (e.g., a CI job), if any
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{"traceparent": os.Getenv("TRACEPARENT")})
+	// The arguments are not recorded, as they may contain secrets (e.g., `--env`, `--secret`, `login --password`)
+	ctx, rootSpan = StartSpan(ctx, spanName)
+	return ctx, nil
+}
+
+// Shutdown ends the root span with err and flushes the spans.
+// Shutdown is a no-op when Init has not set up the tracer provider.
+func Shutdown(err error) {
+	if provider == nil {
+		return
+	}
+	EndSpan(rootSpan, err)
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		log.L.WithError(err).Warn("failed to export the traces")
+	}
+	provider = nil
+}
+
+// StartSpan starts a span named name as a child of the span in ctx.
+// When the traces are not exported, the returned span is a no-op.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SetAttributes sets attrs on the span in ctx.
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// EndSpan ends span, recording err unless it is nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Environ returns the TRACEPARENT env var that propagates the trace context of ctx to a child process,
+// such as buildctl. Environ returns nil when ctx has no span to propagate.
+func Environ(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if tp := carrier.Get("traceparent"); tp != "" {
+		return []string{"TRACEPARENT=" + tp}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package volumeplugin implements a client of the Docker volume plugin protocol (v1),
+// so that the volume drivers written for Docker can be used with nerdctl.
+//
+// See https://docs.docker.com/engine/extend/plugins_volume/
+package volumeplugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+)
+
+const (
+	// mimeType is the content type of the requests and the responses of the plugin API
+	mimeType = "application/vnd.docker.plugins.v1.2+json"
+	// requestTimeout is the timeout of a request to a plugin, mounting a remote volume can take a while
+	requestTimeout = 2 * time.Minute
+	// volumeDriver is the name of the volume driver interface in the response of Plugin.Activate
+	volumeDriver = "VolumeDriver"
+)
+
+var (
+	// SocketDirs are the directories where the plugins listen on `<name>.sock` or `<name>/<name>.sock`.
+	SocketDirs = []string{"/run/docker/plugins"}
+	// SpecDirs are the directories of the `<name>.spec` and `<name>.json` files, with the address of the plugins.
+	SpecDirs = []string{"/etc/docker/plugins", "/usr/lib/docker/plugins"}
+)
+
+// spec is the content of a `<name>.json` file.
+type spec struct {
+	Name      string
+	Addr      string
+	TLSConfig *struct {
+		InsecureSkipVerify bool
+		CAFile             string
+		CertFile           string
+		KeyFile            string
+	}
+}
+
+// Plugin is a volume plugin.
+type Plugin struct {
+	// Name is the name of the plugin, i.e., the volume driver name
+	Name string
+	// Addr is the URL of the plugin, e.g., "unix:///run/docker/plugins/foo.sock"
+	Addr string
+
+	client  *http.Client
+	baseURL string
+}
+
+// Lookup finds the plugin in SocketDirs and SpecDirs, and activates it.
+// ErrNotFound is returned when there is no plugin with that name.
+func Lookup(ctx context.Context, name string) (*Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid volume plugin name %q: %w", name, errdefs.ErrInvalidArgument)
+	}
+	p, err := find(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.activate(ctx); err != nil {
+		return nil, fmt.Errorf("failed to activate volume plugin %q: %w", name, err)
+	}
+	return p, nil
+}
+
+func find(name string) (*Plugin, error) {
+	for _, dir := range SocketDirs {
+		for _, sock := range []string{filepath.Join(dir, name+".sock"), filepath.Join(dir, name, name+".sock")} {
+			if fi, err := os.Stat(sock); err == nil && fi.Mode()&os.ModeSocket != 0 {
+				return newPlugin(name, "unix://"+sock, nil)
+			}
+		}
+	}
+	for _, dir := range SpecDirs {
+		if b, err := os.ReadFile(filepath.Join(dir, name+".spec")); err == nil {
+			return newPlugin(name, strings.TrimSpace(string(b)), nil)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		b, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var s spec
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", filepath.Join(dir, name+".json"), err)
+		}
+		return newPlugin(name, s.Addr, &s)
+	}
+	return nil, fmt.Errorf("volume plugin %q not found in %v or %v: %w", name, SocketDirs, SpecDirs, errdefs.ErrNotFound)
+}
+
+func newPlugin(name, addr string, s *spec) (*Plugin, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q of volume plugin %q: %w", addr, name, err)
+	}
+	transport := &http.Transport{}
+	p := &Plugin{
+		Name:   name,
+		Addr:   addr,
+		client: &http.Client{Transport: transport, Timeout: requestTimeout},
+	}
+	switch u.Scheme {
+	case "unix":
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", u.Path)
+		}
+		// the host is ignored for unix sockets
+		p.baseURL = "http://plugin"
+	case "tcp", "http":
+		p.baseURL = "http://" + u.Host
+	case "https":
+		p.baseURL = "https://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported address %q of volume plugin %q: %w", addr, name, errdefs.ErrNotImplemented)
+	}
+	if s != nil && s.TLSConfig != nil {
+		tlsConfig := &tls.Config{InsecureSkipVerify: s.TLSConfig.InsecureSkipVerify}
+		if s.TLSConfig.CAFile != "" {
+			ca, err := os.ReadFile(s.TLSConfig.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			tlsConfig.RootCAs.AppendCertsFromPEM(ca)
+		}
+		if s.TLSConfig.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(s.TLSConfig.CertFile, s.TLSConfig.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsConfig
+		p.baseURL = "https://" + u.Host
+	}
+	return p, nil
+}
+
+// call posts req to the method of the plugin, and decodes the response into res.
+// The "Err" field of the response is returned as an error.
+func (p *Plugin) call(ctx context.Context, method string, req, res any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Accept", mimeType)
+	r.Header.Set("Content-Type", mimeType)
+	resp, err := p.client.Do(r)
+	if err != nil {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.Name, method, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var e struct {
+		Err string
+	}
+	// plugins are expected to return the errors as JSON, but may also return plain text with an error status
+	if json.Unmarshal(b, &e) != nil && resp.StatusCode != http.StatusOK {
+		e.Err = strings.TrimSpace(string(b))
+	}
+	if e.Err != "" {
+		return fmt.Errorf("volume plugin %q: %s: %s", p.Name, method, e.Err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("volume plugin %q: %s: unexpected status %s", p.Name, method, resp.Status)
+	}
+	if res == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, res); err != nil {
+		return fmt.Errorf("volume plugin %q: %s: failed to decode the response: %w", p.Name, method, err)
+	}
+	return nil
+}
+
+func (p *Plugin) activate(ctx context.Context) error {
+	var res struct {
+		Implements []string
+	}
+	if err := p.call(ctx, "Plugin.Activate", struct{}{}, &res); err != nil {
+		return err
+	}
+	if !slices.Contains(res.Implements, volumeDriver) {
+		return fmt.Errorf("plugin %q does not implement %s, only %v: %w", p.Name, volumeDriver, res.Implements, errdefs.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Volume is a volume as returned by VolumeDriver.Get and VolumeDriver.List.
+type Volume struct {
+	Name       string
+	Mountpoint string                 `json:",omitempty"`
+	CreatedAt  string                 `json:",omitempty"`
+	Status     map[string]interface{} `json:",omitempty"`
+}
+
+// Create creates the volume with the driver options.
+func (p *Plugin) Create(ctx context.Context, name string, opts map[string]string) error {
+	return p.call(ctx, "VolumeDriver.Create", struct {
+		Name string
+		Opts map[string]string `json:",omitempty"`
+	}{name, opts}, nil)
+}
+
+// Remove removes the volume.
+func (p *Plugin) Remove(ctx context.Context, name string) error {
+	return p.call(ctx, "VolumeDriver.Remove", struct{ Name string }{name}, nil)
+}
+
+// Mount mounts the volume for the caller id (e.g., a container ID), and returns the path of the mount on the host.
+func (p *Plugin) Mount(ctx context.Context, name, id string) (string, error) {
+	var res struct {
+		Mountpoint string
+	}
+	if err := p.call(ctx, "VolumeDriver.Mount", struct{ Name, ID string }{name, id}, &res); err != nil {
+		return "", err
+	}
+	if res.Mountpoint == "" {
+		return "", fmt.Errorf("volume plugin %q: VolumeDriver.Mount: no mountpoint returned for %q", p.Name, name)
+	}
+	return res.Mountpoint, nil
+}
+
+// Unmount releases the mount of the volume by the caller id.
+func (p *Plugin) Unmount(ctx context.Context, name, id string) error {
+	return p.call(ctx, "VolumeDriver.Unmount", struct{ Name, ID string }{name, id}, nil)
+}
+
+// Path returns the mountpoint of the volume, which is empty when the volume is not mounted.
+func (p *Plugin) Path(ctx context.Context, name string) (string, error) {
+	var res struct {
+		Mountpoint string
+	}
+	if err := p.call(ctx, "VolumeDriver.Path", struct{ Name string }{name}, &res); err != nil {
+		return "", err
+	}
+	return res.Mountpoint, nil
+}
+
+// Get returns the volume.
+func (p *Plugin) Get(ctx context.Context, name string) (*Volume, error) {
+	var res struct {
+		Volume *Volume
+	}
+	if err := p.call(ctx, "VolumeDriver.Get", struct{ Name string }{name}, &res); err != nil {
+		return nil, err
+	}
+	if res.Volume == nil {
+		return nil, fmt.Errorf("volume %q not found in volume plugin %q: %w", name, p.Name, errdefs.ErrNotFound)
+	}
+	return res.Volume, nil
+}
+
+// List returns the volumes of the plugin.
+func (p *Plugin) List(ctx context.Context) ([]Volume, error) {
+	var res struct {
+		Volumes []Volume
+	}
+	if err := p.call(ctx, "VolumeDriver.List", struct{}{}, &res); err != nil {
+		return nil, err
+	}
+	return res.Volumes, nil
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumeplugin
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+// fakePlugin serves a volume plugin that keeps the volumes in memory.
+func fakePlugin(t *testing.T, sock string) map[string]string {
+	volumes := make(map[string]string)
+	mux := http.NewServeMux()
+	handle := func(method string, fn func(req map[string]any) any) {
+		mux.HandleFunc("/"+method, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Header.Get("Accept"), mimeType)
+			var req map[string]any
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&req))
+			w.Header().Set("Content-Type", mimeType)
+			assert.NilError(t, json.NewEncoder(w).Encode(fn(req)))
+		})
+	}
+	handle("Plugin.Activate", func(map[string]any) any {
+		return map[string]any{"Implements": []string{volumeDriver}}
+	})
+	handle("VolumeDriver.Create", func(req map[string]any) any {
+		name := req["Name"].(string)
+		if opts, ok := req["Opts"].(map[string]any); ok && opts["fail"] != nil {
+			return map[string]string{"Err": "failed to create " + name}
+		}
+		volumes[name] = ""
+		return map[string]string{}
+	})
+	handle("VolumeDriver.Mount", func(req map[string]any) any {
+		name := req["Name"].(string)
+		volumes[name] = "/mnt/" + name
+		return map[string]string{"Mountpoint": volumes[name]}
+	})
+	handle("VolumeDriver.Get", func(req map[string]any) any {
+		name := req["Name"].(string)
+		if _, ok := volumes[name]; !ok {
+			return map[string]string{"Err": "no such volume"}
+		}
+		return map[string]any{"Volume": map[string]string{"Name": name, "Mountpoint": volumes[name]}}
+	})
+	handle("VolumeDriver.Remove", func(req map[string]any) any {
+		delete(volumes, req["Name"].(string))
+		return map[string]string{}
+	})
+
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return volumes
+}
+
+func TestPlugin(t *testing.T) {
+	dir := t.TempDir()
+	SocketDirs, SpecDirs = []string{filepath.Join(dir, "run")}, []string{filepath.Join(dir, "etc")}
+	assert.NilError(t, os.MkdirAll(SocketDirs[0], 0o755))
+	assert.NilError(t, os.MkdirAll(SpecDirs[0], 0o755))
+	volumes := fakePlugin(t, filepath.Join(SocketDirs[0], "fake.sock"))
+	ctx := context.Background()
+
+	_, err := Lookup(ctx, "missing")
+	assert.Assert(t, errdefs.IsNotFound(err))
+	_, err = Lookup(ctx, "../fake")
+	assert.Assert(t, errdefs.IsInvalidArgument(err))
+
+	p, err := Lookup(ctx, "fake")
+	assert.NilError(t, err)
+	assert.Equal(t, p.Addr, "unix://"+filepath.Join(SocketDirs[0], "fake.sock"))
+
+	assert.NilError(t, p.Create(ctx, "vol", map[string]string{"size": "1G"}))
+	assert.ErrorContains(t, p.Create(ctx, "bad", map[string]string{"fail": "true"}), "failed to create bad")
+
+	mountpoint, err := p.Mount(ctx, "vol", "container")
+	assert.NilError(t, err)
+	assert.Equal(t, mountpoint, "/mnt/vol")
+
+	vol, err := p.Get(ctx, "vol")
+	assert.NilError(t, err)
+	assert.Equal(t, vol.Mountpoint, "/mnt/vol")
+
+	assert.NilError(t, p.Remove(ctx, "vol"))
+	assert.Equal(t, len(volumes), 0)
+	_, err = p.Get(ctx, "vol")
+	assert.ErrorContains(t, err, "no such volume")
+
+	// a spec file pointing to the same socket
+	assert.NilError(t, os.WriteFile(filepath.Join(SpecDirs[0], "spec.spec"), []byte("unix://"+filepath.Join(SocketDirs[0], "fake.sock")+"\n"), 0o644))
+	p, err = Lookup(ctx, "spec")
+	assert.NilError(t, err)
+	assert.Equal(t, p.Name, "spec")
+}